@@ -0,0 +1,635 @@
+// Package abi parses the ABI of Cairo 1 classes into a typed model:
+// functions, the constructor and l1 handlers, events, structs, enums,
+// interfaces and impls, with lookups by name and by selector, and the
+// resolution of type names such as
+// core::array::Array::<(core::felt252, my::Struct)> into type trees. It is the
+// parsing layer beneath the codec package, whose kind names the core types
+// resolve to.
+//
+//	parsed, err := abi.Parse([]byte(class.ABI))
+//	fn := parsed.FunctionBySelector(call.EntryPointSelector)
+//	for _, input := range fn.Inputs {
+//		t, err := parsed.Resolve(input.Type)
+//		...
+//	}
+package abi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/abi/codec"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var (
+	ErrInvalidABI      = errors.New("abi: invalid abi")
+	ErrUnknownType     = errors.New("abi: unknown type")
+	ErrInvalidTypeName = errors.New("abi: invalid type name")
+)
+
+// Kinds of the entries of an ABI.
+const (
+	EntryFunction    = "function"
+	EntryConstructor = "constructor"
+	EntryL1Handler   = "l1_handler"
+	EntryEvent       = "event"
+	EntryStruct      = "struct"
+	EntryEnum        = "enum"
+	EntryInterface   = "interface"
+	EntryImpl        = "impl"
+)
+
+// Kinds of the members of events.
+const (
+	MemberKey    = "key"
+	MemberData   = "data"
+	MemberNested = "nested"
+	MemberFlat   = "flat"
+)
+
+// ABI is the parsed ABI of a class.
+type ABI struct {
+	// Functions are the external and view functions, of the interfaces and
+	// at the top level, in the order of the ABI
+	Functions   []*Function
+	Constructor *Function
+	L1Handlers  []*Function
+	Events      []*Event
+	Structs     []*Struct
+	Enums       []*Enum
+	Interfaces  []*Interface
+	Impls       []*Impl
+
+	structs map[string]*Struct
+	enums   map[string]*Enum
+	events  map[string]*Event
+}
+
+// Param is an input or an output of a function.
+type Param struct {
+	// Name is the name of an input, empty for outputs
+	Name string `json:"name"`
+	// Type is the name of the type
+	Type string `json:"type"`
+}
+
+// Function is a function, the constructor or an l1 handler.
+type Function struct {
+	// Kind is EntryFunction, EntryConstructor or EntryL1Handler
+	Kind    string
+	Name    string
+	Inputs  []Param
+	Outputs []Param
+	// StateMutability is "view" or "external"
+	StateMutability string
+	// Interface is the name of the interface declaring the function, empty
+	// for top-level functions
+	Interface string
+	// Selector is the selector of the entrypoint
+	Selector *felt.Felt
+}
+
+// Member is a member of a struct or an event, or a variant of an enum.
+type Member struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Kind is MemberKey, MemberData, MemberNested or MemberFlat for events
+	Kind string `json:"kind,omitempty"`
+}
+
+// Struct is a struct type.
+type Struct struct {
+	Name    string
+	Members []Member
+}
+
+// Enum is an enum type.
+type Enum struct {
+	Name     string
+	Variants []Member
+}
+
+// Event is an event type, a struct of keys and data or an enum of events.
+type Event struct {
+	Name string
+	// Kind is EntryStruct or EntryEnum
+	Kind string
+	// Members are the keys and data of a struct event
+	Members []Member
+	// Variants are the events of an enum event
+	Variants []Member
+	// Selector is the first key of a struct event, the selector of the last
+	// segment of its name
+	Selector *felt.Felt
+}
+
+// Interface is an interface, a group of functions.
+type Interface struct {
+	Name      string
+	Functions []*Function
+}
+
+// Impl is the implementation of an interface by the contract.
+type Impl struct {
+	Name          string
+	InterfaceName string
+}
+
+// entry is an entry of the ABI JSON.
+type entry struct {
+	Type            string            `json:"type"`
+	Name            string            `json:"name"`
+	Inputs          []Param           `json:"inputs"`
+	Outputs         []Param           `json:"outputs"`
+	StateMutability string            `json:"state_mutability"`
+	Members         []Member          `json:"members"`
+	Variants        []Member          `json:"variants"`
+	Kind            string            `json:"kind"`
+	Items           []json.RawMessage `json:"items"`
+	InterfaceName   string            `json:"interface_name"`
+}
+
+// Parse parses an ABI, given as the JSON array of its entries or as a JSON
+// string holding it, as in the abi field of classes. Entries of unknown kinds
+// are ignored.
+//
+// Parameters:
+// - data: the JSON of the ABI
+// Returns:
+// - *ABI: the ABI
+// - error: an error wrapping ErrInvalidABI
+func Parse(data []byte) (*ABI, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var s string
+		if json.Unmarshal(data, &s) != nil || json.Unmarshal([]byte(s), &raw) != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidABI, err)
+		}
+	}
+	a := &ABI{structs: map[string]*Struct{}, enums: map[string]*Enum{}, events: map[string]*Event{}}
+	for _, r := range raw {
+		if err := a.add(r, ""); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// add adds an entry to the ABI.
+//
+// Parameters:
+// - r: the JSON of the entry
+// - iface: the name of the interface holding the entry, empty at the top level
+// Returns:
+// - error: an error wrapping ErrInvalidABI
+func (a *ABI) add(r json.RawMessage, iface string) error {
+	var e entry
+	if err := json.Unmarshal(r, &e); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidABI, err)
+	}
+	if e.Name == "" && e.Type != "" {
+		return fmt.Errorf("%w: %s entry without name", ErrInvalidABI, e.Type)
+	}
+	switch e.Type {
+	case EntryFunction, EntryConstructor, EntryL1Handler:
+		fn := &Function{
+			Kind:            e.Type,
+			Name:            e.Name,
+			Inputs:          e.Inputs,
+			Outputs:         e.Outputs,
+			StateMutability: e.StateMutability,
+			Interface:       iface,
+			Selector:        utils.GetSelectorFromNameCached(e.Name),
+		}
+		switch e.Type {
+		case EntryConstructor:
+			a.Constructor = fn
+		case EntryL1Handler:
+			a.L1Handlers = append(a.L1Handlers, fn)
+		default:
+			a.Functions = append(a.Functions, fn)
+			if iface != "" {
+				ifc := a.Interfaces[len(a.Interfaces)-1]
+				ifc.Functions = append(ifc.Functions, fn)
+			}
+		}
+	case EntryInterface:
+		if iface != "" {
+			return fmt.Errorf("%w: nested interface %s", ErrInvalidABI, e.Name)
+		}
+		a.Interfaces = append(a.Interfaces, &Interface{Name: e.Name})
+		for _, item := range e.Items {
+			if err := a.add(item, e.Name); err != nil {
+				return err
+			}
+		}
+	case EntryImpl:
+		a.Impls = append(a.Impls, &Impl{Name: e.Name, InterfaceName: e.InterfaceName})
+	case EntryStruct:
+		s := &Struct{Name: e.Name, Members: e.Members}
+		a.Structs = append(a.Structs, s)
+		a.structs[s.Name] = s
+	case EntryEnum:
+		en := &Enum{Name: e.Name, Variants: e.Variants}
+		a.Enums = append(a.Enums, en)
+		a.enums[en.Name] = en
+	case EntryEvent:
+		ev := &Event{Name: e.Name, Kind: e.Kind, Members: e.Members, Variants: e.Variants}
+		if ev.Kind == "" {
+			// events of Cairo 1 compilers before 2.0 have no kind, their
+			// members being data
+			ev.Kind = EntryStruct
+			for i := range ev.Members {
+				ev.Members[i].Kind = MemberData
+			}
+			for _, input := range e.Inputs {
+				ev.Members = append(ev.Members, Member{Name: input.Name, Type: input.Type, Kind: MemberData})
+			}
+		}
+		ev.Selector = utils.GetSelectorFromNameCached(lastSegment(ev.Name))
+		a.Events = append(a.Events, ev)
+		a.events[ev.Name] = ev
+	}
+	return nil
+}
+
+// Function returns the first function with a name.
+//
+// Parameters:
+// - name: the name of the function
+// Returns:
+// - *Function: the function, nil if there is none
+func (a *ABI) Function(name string) *Function {
+	for _, fn := range a.Functions {
+		if fn.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// FunctionBySelector returns the function, constructor or l1 handler of an
+// entrypoint selector.
+//
+// Parameters:
+// - selector: the entrypoint selector
+// Returns:
+// - *Function: the function, nil if there is none
+func (a *ABI) FunctionBySelector(selector *felt.Felt) *Function {
+	for _, fn := range a.Functions {
+		if fn.Selector.Equal(selector) {
+			return fn
+		}
+	}
+	for _, fn := range a.L1Handlers {
+		if fn.Selector.Equal(selector) {
+			return fn
+		}
+	}
+	if a.Constructor != nil && a.Constructor.Selector.Equal(selector) {
+		return a.Constructor
+	}
+	return nil
+}
+
+// Event returns an event by its full name.
+//
+// Parameters:
+// - name: the name of the event, e.g. "my::contract::Transfer"
+// Returns:
+// - *Event: the event, nil if there is none
+func (a *ABI) Event(name string) *Event {
+	return a.events[name]
+}
+
+// EventBySelector returns the struct event emitted with a selector as first
+// key.
+//
+// Parameters:
+// - selector: the first key of the event
+// Returns:
+// - *Event: the event, nil if there is none
+func (a *ABI) EventBySelector(selector *felt.Felt) *Event {
+	for _, ev := range a.Events {
+		if ev.Kind == EntryStruct && ev.Selector.Equal(selector) {
+			return ev
+		}
+	}
+	return nil
+}
+
+// Struct returns a struct by its full name.
+//
+// Parameters:
+// - name: the name of the struct
+// Returns:
+// - *Struct: the struct, nil if there is none
+func (a *ABI) Struct(name string) *Struct {
+	return a.structs[name]
+}
+
+// Enum returns an enum by its full name.
+//
+// Parameters:
+// - name: the name of the enum
+// Returns:
+// - *Enum: the enum, nil if there is none
+func (a *ABI) Enum(name string) *Enum {
+	return a.enums[name]
+}
+
+// Interface returns an interface by its full name.
+//
+// Parameters:
+// - name: the name of the interface
+// Returns:
+// - *Interface: the interface, nil if there is none
+func (a *ABI) Interface(name string) *Interface {
+	for _, ifc := range a.Interfaces {
+		if ifc.Name == name {
+			return ifc
+		}
+	}
+	return nil
+}
+
+// lastSegment returns the last segment of a path, as in a::b::C.
+//
+// Parameters:
+// - path: the path
+// Returns:
+// - string: the last segment
+func lastSegment(path string) string {
+	if i := strings.LastIndex(path, "::"); i >= 0 {
+		return path[i+2:]
+	}
+	return path
+}
+
+// Type is a resolved type name.
+type Type struct {
+	// Name is the name of the type, as in the ABI
+	Name string
+	// Base is the name without the generic arguments
+	Base string
+	// Args are the generic arguments, the elements of a tuple, or the
+	// element of an array, a span or a fixed-size array
+	Args []*Type
+	// Tuple is true for tuples
+	Tuple bool
+	// Array is true for Array<T> and Span<T>
+	Array bool
+	// Len is the length of a fixed-size array [T; N], 0 otherwise
+	Len int
+	// Kind is the codec kind of core scalar types, e.g. codec.KindU256, empty otherwise
+	Kind string
+	// Struct is the struct of the ABI the type names
+	Struct *Struct
+	// Enum is the enum of the ABI the type names
+	Enum *Enum
+}
+
+// coreKinds are the codec kinds of the core scalar types.
+var coreKinds = map[string]string{
+	"core::felt252":        codec.KindFelt,
+	"felt252":              codec.KindFelt,
+	"core::bool":           codec.KindBool,
+	"core::integer::u8":    codec.KindU8,
+	"core::integer::u16":   codec.KindU16,
+	"core::integer::u32":   codec.KindU32,
+	"core::integer::u64":   codec.KindU64,
+	"core::integer::u128":  codec.KindU128,
+	"core::integer::u256":  codec.KindU256,
+	"core::integer::usize": codec.KindUsize,
+	"core::integer::i8":    codec.KindI8,
+	"core::integer::i16":   codec.KindI16,
+	"core::integer::i32":   codec.KindI32,
+	"core::integer::i64":   codec.KindI64,
+	"core::integer::i128":  codec.KindI128,
+	"core::starknet::contract_address::ContractAddress": codec.KindContractAddress,
+	"core::starknet::class_hash::ClassHash":             codec.KindClassHash,
+	"core::byte_array::ByteArray":                       codec.KindByteArray,
+	"core::bytes_31::bytes31":                           codec.KindFelt,
+	"core::starknet::eth_address::EthAddress":           codec.KindFelt,
+	"core::starknet::storage_access::StorageAddress":    codec.KindFelt,
+}
+
+// Resolve resolves a type name into a type tree, linking the structs and
+// enums of the ABI. The enums of the ABI named after core types, as
+// core::bool, resolve to the core type.
+//
+// Parameters:
+// - name: the type name, e.g. "core::array::Span::<core::integer::u32>"
+// Returns:
+// - *Type: the type
+// - error: an error wrapping ErrInvalidTypeName or ErrUnknownType
+func (a *ABI) Resolve(name string) (*Type, error) {
+	p := &typeParser{s: name}
+	t, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("%w: %q: unexpected %q", ErrInvalidTypeName, name, p.s[p.pos:])
+	}
+	if err := a.link(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// link links a parsed type and its arguments to the core types and the ABI
+// types.
+//
+// Parameters:
+// - t: the type
+// Returns:
+// - error: an error wrapping ErrUnknownType
+func (a *ABI) link(t *Type) error {
+	for _, arg := range t.Args {
+		if err := a.link(arg); err != nil {
+			return err
+		}
+	}
+	if t.Tuple || t.Len > 0 {
+		return nil
+	}
+	if kind, ok := coreKinds[t.Name]; ok {
+		t.Kind = kind
+		return nil
+	}
+	switch t.Base {
+	case "core::array::Array", "core::array::Span":
+		if len(t.Args) != 1 {
+			return fmt.Errorf("%w: %q: one element type expected", ErrInvalidTypeName, t.Name)
+		}
+		t.Array = true
+		return nil
+	case "core::zeroable::NonZero":
+		// NonZero<T> is serialized as T
+		if len(t.Args) == 1 {
+			*t = *t.Args[0]
+			return nil
+		}
+	}
+	if s := a.structs[t.Name]; s != nil {
+		t.Struct = s
+		return nil
+	}
+	if e := a.enums[t.Name]; e != nil {
+		t.Enum = e
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownType, t.Name)
+}
+
+// typeParser parses type names: paths with generic arguments as in
+// a::B::<T, U>, tuples (T, U) and fixed-size arrays [T; N].
+type typeParser struct {
+	s   string
+	pos int
+}
+
+// parse parses a type at the position of the parser.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *Type: the parsed type, not linked
+// - error: an error wrapping ErrInvalidTypeName
+func (p *typeParser) parse() (*Type, error) {
+	p.skipSpaces()
+	start := p.pos
+	switch {
+	case p.consume("("):
+		t := &Type{Tuple: true}
+		args, err := p.list(")")
+		if err != nil {
+			return nil, err
+		}
+		t.Args = args
+		t.Name = p.s[start:p.pos]
+		t.Base = t.Name
+		return t, nil
+	case p.consume("["):
+		elem, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.consume(";") {
+			return nil, p.errorf("expected ;")
+		}
+		p.skipSpaces()
+		end := p.pos
+		for end < len(p.s) && p.s[end] >= '0' && p.s[end] <= '9' {
+			end++
+		}
+		n, err := strconv.Atoi(p.s[p.pos:end])
+		if err != nil || n <= 0 {
+			return nil, p.errorf("expected array length")
+		}
+		p.pos = end
+		p.skipSpaces()
+		if !p.consume("]") {
+			return nil, p.errorf("expected ]")
+		}
+		return &Type{Name: p.s[start:p.pos], Base: "[]", Args: []*Type{elem}, Len: n}, nil
+	}
+	for p.pos < len(p.s) && !strings.ContainsRune("<>(),;[] ", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return nil, p.errorf("expected a type")
+	}
+	base := p.s[start:p.pos]
+	t := &Type{Base: strings.TrimSuffix(base, "::")}
+	if strings.HasSuffix(base, "::") {
+		if !p.consume("<") {
+			return nil, p.errorf("expected <")
+		}
+		args, err := p.list(">")
+		if err != nil {
+			return nil, err
+		}
+		t.Args = args
+	}
+	t.Name = p.s[start:p.pos]
+	return t, nil
+}
+
+// list parses types separated by commas until a closing delimiter.
+//
+// Parameters:
+// - end: the closing delimiter
+// Returns:
+// - []*Type: the types
+// - error: an error wrapping ErrInvalidTypeName
+func (p *typeParser) list(end string) ([]*Type, error) {
+	var types []*Type
+	p.skipSpaces()
+	if p.consume(end) {
+		return types, nil
+	}
+	for {
+		t, err := p.parse()
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+		p.skipSpaces()
+		if p.consume(end) {
+			return types, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected , or %s", end)
+		}
+	}
+}
+
+// consume advances the parser past a token if it is next.
+//
+// Parameters:
+// - token: the token
+// Returns:
+// - bool: true if the token was consumed
+func (p *typeParser) consume(token string) bool {
+	if strings.HasPrefix(p.s[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+// skipSpaces advances the parser past spaces.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (p *typeParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// errorf returns a parse error at the position of the parser.
+//
+// Parameters:
+// - format: the format of the message
+// - args: the arguments of the format
+// Returns:
+// - error: an error wrapping ErrInvalidTypeName
+func (p *typeParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %q at %d: %s", ErrInvalidTypeName, p.s, p.pos, fmt.Sprintf(format, args...))
+}
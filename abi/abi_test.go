@@ -0,0 +1,323 @@
+package abi
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/abi/codec"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+const erc20ABI = `[
+  {"type": "impl", "name": "ERC20Impl", "interface_name": "my::IERC20"},
+  {"type": "struct", "name": "core::integer::u256", "members": [{"name": "low", "type": "core::integer::u128"}, {"name": "high", "type": "core::integer::u128"}]},
+  {"type": "enum", "name": "core::bool", "variants": [{"name": "False", "type": "()"}, {"name": "True", "type": "()"}]},
+  {"type": "struct", "name": "my::Allowance", "members": [{"name": "spender", "type": "core::starknet::contract_address::ContractAddress"}, {"name": "amount", "type": "core::integer::u256"}]},
+  {"type": "enum", "name": "core::option::Option::<my::Allowance>", "variants": [{"name": "Some", "type": "my::Allowance"}, {"name": "None", "type": "()"}]},
+  {"type": "interface", "name": "my::IERC20", "items": [
+    {"type": "function", "name": "balance_of", "inputs": [{"name": "account", "type": "core::starknet::contract_address::ContractAddress"}], "outputs": [{"type": "core::integer::u256"}], "state_mutability": "view"},
+    {"type": "function", "name": "approve_all", "inputs": [{"name": "allowances", "type": "core::array::Span::<my::Allowance>"}], "outputs": [{"type": "core::bool"}], "state_mutability": "external"}
+  ]},
+  {"type": "function", "name": "last_allowance", "inputs": [], "outputs": [{"type": "core::option::Option::<my::Allowance>"}], "state_mutability": "view"},
+  {"type": "constructor", "name": "constructor", "inputs": [{"name": "supply", "type": "core::integer::u256"}]},
+  {"type": "l1_handler", "name": "deposit", "inputs": [{"name": "from_address", "type": "core::felt252"}], "outputs": [], "state_mutability": "external"},
+  {"type": "event", "name": "my::Transfer", "kind": "struct", "members": [{"name": "from", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"}, {"name": "value", "type": "core::integer::u256", "kind": "data"}]},
+  {"type": "event", "name": "my::Event", "kind": "enum", "variants": [{"name": "Transfer", "type": "my::Transfer", "kind": "nested"}]}
+]`
+
+// TestParse tests the model of an ABI and its lookups by name and selector.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestParse(t *testing.T) {
+	parsed, err := Parse([]byte(erc20ABI))
+	require.NoError(t, err)
+
+	require.Len(t, parsed.Functions, 3)
+	require.Len(t, parsed.Interfaces, 1)
+	require.Len(t, parsed.Interface("my::IERC20").Functions, 2)
+	require.Equal(t, "my::IERC20", parsed.Impls[0].InterfaceName)
+
+	balanceOf := parsed.Function("balance_of")
+	require.Equal(t, "my::IERC20", balanceOf.Interface)
+	require.Equal(t, "view", balanceOf.StateMutability)
+	require.Equal(t, balanceOf, parsed.FunctionBySelector(utils.GetSelectorFromNameFelt("balance_of")))
+	require.Equal(t, "", parsed.Function("last_allowance").Interface)
+	require.Equal(t, EntryConstructor, parsed.Constructor.Kind)
+	require.Equal(t, "deposit", parsed.FunctionBySelector(utils.GetSelectorFromNameFelt("deposit")).Name)
+	require.Nil(t, parsed.FunctionBySelector(utils.GetSelectorFromNameFelt("transfer")))
+
+	transfer := parsed.EventBySelector(utils.GetSelectorFromNameFelt("Transfer"))
+	require.Equal(t, parsed.Event("my::Transfer"), transfer)
+	require.Equal(t, MemberKey, transfer.Members[0].Kind)
+	require.Equal(t, EntryEnum, parsed.Event("my::Event").Kind)
+	require.NotNil(t, parsed.Struct("my::Allowance"))
+	require.NotNil(t, parsed.Enum("core::option::Option::<my::Allowance>"))
+
+	// the abi field of classes holds the ABI as a JSON string
+	quoted, err := json.Marshal(erc20ABI)
+	require.NoError(t, err)
+	fromString, err := Parse(quoted)
+	require.NoError(t, err)
+	require.Len(t, fromString.Functions, 3)
+
+	_, err = Parse([]byte(`{"type": "function"}`))
+	require.True(t, errors.Is(err, ErrInvalidABI))
+}
+
+// TestResolve tests the resolution of nested type names into type trees.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestResolve(t *testing.T) {
+	parsed, err := Parse([]byte(erc20ABI))
+	require.NoError(t, err)
+
+	span, err := parsed.Resolve("core::array::Span::<my::Allowance>")
+	require.NoError(t, err)
+	require.True(t, span.Array)
+	require.Equal(t, "core::array::Span", span.Base)
+	require.Equal(t, parsed.Struct("my::Allowance"), span.Args[0].Struct)
+
+	// core types resolve to their codec kinds even if the ABI declares them
+	u256, err := parsed.Resolve("core::integer::u256")
+	require.NoError(t, err)
+	require.Equal(t, codec.KindU256, u256.Kind)
+	require.Nil(t, u256.Struct)
+
+	option, err := parsed.Resolve("core::option::Option::<my::Allowance>")
+	require.NoError(t, err)
+	require.NotNil(t, option.Enum)
+
+	tuple, err := parsed.Resolve("(core::felt252, core::array::Array::<(core::bool, core::integer::u8)>)")
+	require.NoError(t, err)
+	require.True(t, tuple.Tuple)
+	require.Len(t, tuple.Args, 2)
+	require.Equal(t, codec.KindFelt, tuple.Args[0].Kind)
+	require.True(t, tuple.Args[1].Args[0].Tuple)
+	require.Equal(t, codec.KindU8, tuple.Args[1].Args[0].Args[1].Kind)
+
+	unit, err := parsed.Resolve("()")
+	require.NoError(t, err)
+	require.True(t, unit.Tuple)
+	require.Empty(t, unit.Args)
+
+	fixed, err := parsed.Resolve("[core::integer::u32; 4]")
+	require.NoError(t, err)
+	require.Equal(t, 4, fixed.Len)
+	require.Equal(t, codec.KindU32, fixed.Args[0].Kind)
+
+	nonZero, err := parsed.Resolve("core::zeroable::NonZero::<core::felt252>")
+	require.NoError(t, err)
+	require.Equal(t, codec.KindFelt, nonZero.Kind)
+
+	_, err = parsed.Resolve("my::Unknown")
+	require.True(t, errors.Is(err, ErrUnknownType))
+	for _, name := range []string{"", "core::array::Array::<core::felt252", "(core::felt252", "[core::felt252; x]", "core::felt252>"} {
+		_, err = parsed.Resolve(name)
+		require.True(t, errors.Is(err, ErrInvalidTypeName), name)
+	}
+}
+
+// TestEncodeCall tests that the arguments of a call are checked against the
+// ABI and serialized as the types of the inputs.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestEncodeCall(t *testing.T) {
+	parsed, err := Parse([]byte(erc20ABI))
+	require.NoError(t, err)
+
+	account := new(felt.Felt).SetUint64(0xa)
+	call, err := EncodeCall(parsed, "balance_of", account)
+	require.NoError(t, err)
+	require.Equal(t, utils.GetSelectorFromNameFelt("balance_of"), call.EntryPointSelector)
+	require.Equal(t, []*felt.Felt{account}, call.Calldata)
+
+	type allowance struct {
+		Spender *felt.Felt
+		Amount  *big.Int
+	}
+	call, err = EncodeCall(parsed, "approve_all", []any{
+		allowance{Spender: account, Amount: big.NewInt(5)},
+		map[string]any{"spender": uint64(0xb), "amount": utils.Uint64ToUint256(7)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, feltsOf(2, 0xa, 5, 0, 0xb, 7, 0), call.Calldata)
+
+	_, err = EncodeCall(parsed, "balance_of")
+	require.True(t, errors.Is(err, ErrArgumentCount))
+	_, err = EncodeCall(parsed, "balance_of", true)
+	require.True(t, errors.Is(err, ErrTypeMismatch))
+	_, err = EncodeCall(parsed, "approve_all", []allowance{{Spender: account}})
+	require.True(t, errors.Is(err, codec.ErrUnsupportedType))
+	_, err = EncodeCall(parsed, "approve_all", []map[string]any{{"spender": account}})
+	require.True(t, errors.Is(err, ErrTypeMismatch))
+	_, err = EncodeCall(parsed, "transfer", account)
+	require.True(t, errors.Is(err, ErrUnknownFunction))
+}
+
+// TestDecodeResult tests that the results of calls are deserialized as the
+// types of the outputs, into typed and untyped destinations.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestDecodeResult(t *testing.T) {
+	parsed, err := Parse([]byte(erc20ABI))
+	require.NoError(t, err)
+
+	var balance *big.Int
+	require.NoError(t, DecodeResult(parsed, "balance_of", feltsOf(1, 1), &balance))
+	require.Equal(t, new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)), balance)
+
+	var ok bool
+	require.NoError(t, DecodeResult(parsed, "approve_all", feltsOf(1), &ok))
+	require.True(t, ok)
+
+	var last any
+	require.NoError(t, DecodeResult(parsed, "last_allowance", feltsOf(0, 0xb, 7, 0), &last))
+	require.Equal(t, Variant{Name: "Some", Value: map[string]any{
+		"spender": new(felt.Felt).SetUint64(0xb),
+		"amount":  big.NewInt(7),
+	}}, last)
+	require.NoError(t, DecodeResult(parsed, "last_allowance", feltsOf(1), &last))
+	require.Equal(t, Variant{Name: "None"}, last)
+
+	type allowance struct {
+		Spender *felt.Felt
+		Amount  utils.Uint256
+	}
+	var typed codec.Option[allowance]
+	require.NoError(t, DecodeResult(parsed, "last_allowance", feltsOf(0, 0xb, 7, 0), &typed))
+	require.True(t, typed.Valid)
+	require.Equal(t, "7", typed.Value.Amount.String())
+
+	require.True(t, errors.Is(DecodeResult(parsed, "balance_of", feltsOf(1, 1, 1), &balance), codec.ErrTrailingData))
+	require.True(t, errors.Is(DecodeResult(parsed, "balance_of", feltsOf(1), &balance), codec.ErrShortData))
+	require.True(t, errors.Is(DecodeResult(parsed, "balance_of", feltsOf(1, 1)), ErrArgumentCount))
+	var s string
+	require.True(t, errors.Is(DecodeResult(parsed, "balance_of", feltsOf(1, 1), &s), ErrTypeMismatch))
+	require.True(t, errors.Is(DecodeResult(parsed, "last_allowance", feltsOf(2), &last), codec.ErrInvalidEnum))
+}
+
+// TestDecodeEvent tests that DecodeEvent finds the event by its first key and
+// decodes the key members from the keys and the data members from the data.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDecodeEvent(t *testing.T) {
+	parsed, err := Parse([]byte(erc20ABI))
+	require.NoError(t, err)
+	selector := utils.GetSelectorFromNameFelt("Transfer")
+
+	ev, values, err := DecodeEvent(parsed, []*felt.Felt{selector, new(felt.Felt).SetUint64(0xa1)}, feltsOf(5, 0))
+	require.NoError(t, err)
+	require.Equal(t, "my::Transfer", ev.Name)
+	require.Equal(t, map[string]any{
+		"from":  new(felt.Felt).SetUint64(0xa1),
+		"value": big.NewInt(5),
+	}, values)
+
+	_, _, err = DecodeEvent(parsed, feltsOf(1), nil)
+	require.True(t, errors.Is(err, ErrUnknownEvent))
+	_, _, err = DecodeEvent(parsed, nil, nil)
+	require.True(t, errors.Is(err, ErrUnknownEvent))
+	_, _, err = DecodeEvent(parsed, []*felt.Felt{selector, new(felt.Felt).SetUint64(0xa1)}, feltsOf(5, 0, 1))
+	require.True(t, errors.Is(err, codec.ErrTrailingData))
+	_, _, err = DecodeEvent(parsed, []*felt.Felt{selector}, feltsOf(5, 0))
+	require.True(t, errors.Is(err, codec.ErrShortData))
+}
+
+// feltsOf returns felts of integers.
+//
+// Parameters:
+// - values: the integers
+// Returns:
+// - []*felt.Felt: the felts
+func feltsOf(values ...uint64) []*felt.Felt {
+	result := make([]*felt.Felt, len(values))
+	for i, v := range values {
+		result[i] = new(felt.Felt).SetUint64(v)
+	}
+	return result
+}
+
+// TestRegistry tests that the standard registry tells the ERC20 and ERC721
+// Transfer events apart by their layout, decodes the UDC and Upgraded events,
+// and tries the events registered last first.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestRegistry(t *testing.T) {
+	transfer := utils.GetSelectorFromNameFelt("Transfer")
+	from, to := new(felt.Felt).SetUint64(0xa1), new(felt.Felt).SetUint64(0xb2)
+
+	ev, values, err := DefaultRegistry.Decode([]*felt.Felt{transfer, from, to}, feltsOf(5, 0))
+	require.NoError(t, err)
+	require.Equal(t, "openzeppelin::token::erc20::erc20::ERC20Component::Transfer", ev.Name)
+	require.Equal(t, map[string]any{"from": from, "to": to, "value": big.NewInt(5)}, values)
+
+	ev, values, err = DefaultRegistry.Decode([]*felt.Felt{transfer, from, to, new(felt.Felt).SetUint64(9), new(felt.Felt)}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "openzeppelin::token::erc721::erc721::ERC721Component::Transfer", ev.Name)
+	require.Equal(t, big.NewInt(9), values["token_id"])
+
+	ev, values, err = DefaultRegistry.Decode([]*felt.Felt{transfer}, []*felt.Felt{from, to, new(felt.Felt).SetUint64(5), new(felt.Felt)})
+	require.NoError(t, err)
+	require.Equal(t, "openzeppelin::token::erc20::erc20::ERC20::Transfer", ev.Name)
+	require.Equal(t, to, values["to"])
+
+	classHash := new(felt.Felt).SetUint64(0xc1a55)
+	ev, values, err = DefaultRegistry.Decode(
+		[]*felt.Felt{utils.GetSelectorFromNameFelt("ContractDeployed")},
+		[]*felt.Felt{to, from, new(felt.Felt).SetUint64(1), classHash, new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(42), new(felt.Felt).SetUint64(7)},
+	)
+	require.NoError(t, err)
+	require.Equal(t, "openzeppelin::presets::universal_deployer::UniversalDeployer::ContractDeployed", ev.Name)
+	require.Equal(t, to, values["address"])
+	require.Equal(t, classHash, values["class_hash"])
+	require.Equal(t, true, values["not_from_zero"])
+
+	ev, values, err = DefaultRegistry.Decode([]*felt.Felt{utils.GetSelectorFromNameFelt("Upgraded")}, []*felt.Felt{classHash})
+	require.NoError(t, err)
+	require.Equal(t, "openzeppelin::upgrades::upgradeable::UpgradeableComponent::Upgraded", ev.Name)
+	require.Equal(t, classHash, values["class_hash"])
+
+	_, _, err = DefaultRegistry.Decode([]*felt.Felt{transfer, from}, feltsOf(5, 0, 1))
+	require.True(t, errors.Is(err, codec.ErrTrailingData) || errors.Is(err, codec.ErrShortData))
+	_, _, err = DefaultRegistry.Decode(feltsOf(1), nil)
+	require.True(t, errors.Is(err, ErrUnknownEvent))
+
+	registry := NewStandardRegistry()
+	parsed, err := Parse([]byte(erc20ABI))
+	require.NoError(t, err)
+	registry.Register(parsed)
+	// my::Transfer has one key, as no standard Transfer
+	ev, _, err = registry.Decode([]*felt.Felt{transfer, from}, feltsOf(5, 0))
+	require.NoError(t, err)
+	require.Equal(t, "my::Transfer", ev.Name)
+	_, _, err = DefaultRegistry.Decode([]*felt.Felt{transfer, from}, feltsOf(5, 0))
+	require.Error(t, err)
+}
@@ -0,0 +1,697 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/abi/codec"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+var (
+	ErrUnknownFunction = errors.New("abi: unknown function")
+	ErrArgumentCount   = errors.New("abi: wrong number of arguments")
+	ErrTypeMismatch    = errors.New("abi: value does not match the type")
+	ErrUnknownEvent    = errors.New("abi: unknown event")
+)
+
+// Variant is a value of an enum of the ABI, as a variant name and the value
+// of the variant, nil for unit variants. Enum values decoded into an any are
+// Variants.
+type Variant struct {
+	Name  string
+	Value any
+}
+
+var (
+	feltPtrType     = reflect.TypeOf(&felt.Felt{})
+	bigIntPtrType   = reflect.TypeOf(&big.Int{})
+	variantType     = reflect.TypeOf(Variant{})
+	marshalerType   = reflect.TypeOf((*codec.Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*codec.Unmarshaler)(nil)).Elem()
+)
+
+// EncodeCall returns the call of a function of the ABI, serializing the
+// arguments as the types of the inputs: core types as the codec does, arrays
+// and tuples from slices and arrays, structs from Go structs, field by field
+// in order, or from map[string]any keyed by member name, and enums from
+// Variants or codec enums. The caller sets the ContractAddress of the call.
+//
+//	call, err := abi.EncodeCall(parsed, "transfer", recipient, amount)
+//	call.ContractAddress = tokenAddress
+//
+// Parameters:
+// - a: the ABI
+// - name: the name of the function
+// - args: the arguments, one per input
+// Returns:
+// - rpc.FunctionCall: the call, without contract address
+// - error: ErrUnknownFunction, ErrArgumentCount, or an error wrapping ErrTypeMismatch or a codec error naming the input
+func EncodeCall(a *ABI, name string, args ...any) (rpc.FunctionCall, error) {
+	fn := a.Function(name)
+	if fn == nil {
+		return rpc.FunctionCall{}, fmt.Errorf("%w: %s", ErrUnknownFunction, name)
+	}
+	calldata, err := a.EncodeInputs(fn, args...)
+	if err != nil {
+		return rpc.FunctionCall{}, err
+	}
+	return rpc.FunctionCall{EntryPointSelector: fn.Selector, Calldata: calldata}, nil
+}
+
+// EncodeInputs serializes the arguments of a function, constructor or l1
+// handler as EncodeCall does.
+//
+// Parameters:
+// - fn: the function
+// - args: the arguments, one per input
+// Returns:
+// - []*felt.Felt: the calldata
+// - error: ErrArgumentCount, or an error wrapping ErrTypeMismatch or a codec error naming the input
+func (a *ABI) EncodeInputs(fn *Function, args ...any) ([]*felt.Felt, error) {
+	if len(args) != len(fn.Inputs) {
+		return nil, fmt.Errorf("%w: %s takes %d, got %d", ErrArgumentCount, fn.Name, len(fn.Inputs), len(args))
+	}
+	calldata := []*felt.Felt{}
+	for i, input := range fn.Inputs {
+		t, err := a.Resolve(input.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", fn.Name, input.Name, err)
+		}
+		data, err := a.encode(t, reflect.ValueOf(args[i]))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", fn.Name, input.Name, err)
+		}
+		calldata = append(calldata, data...)
+	}
+	return calldata, nil
+}
+
+// DecodeResult deserializes the result of a call of a function of the ABI
+// into one pointer per output, as the types of the outputs. Pointers to any
+// receive *felt.Felt for felt252, ContractAddress and ClassHash, *big.Int for
+// integers, bool, string for ByteArray, []any for arrays and tuples,
+// map[string]any for structs and Variant for enums.
+//
+// Parameters:
+// - a: the ABI
+// - name: the name of the function
+// - result: the result of the call
+// - out: the destinations, one non-nil pointer per output
+// Returns:
+// - error: ErrUnknownFunction, ErrArgumentCount, codec.ErrTrailingData, or an error wrapping ErrTypeMismatch or a codec error
+func DecodeResult(a *ABI, name string, result []*felt.Felt, out ...any) error {
+	fn := a.Function(name)
+	if fn == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownFunction, name)
+	}
+	if len(out) != len(fn.Outputs) {
+		return fmt.Errorf("%w: %s returns %d values, got %d destinations", ErrArgumentCount, fn.Name, len(fn.Outputs), len(out))
+	}
+	d := &decoder{data: result}
+	for i, output := range fn.Outputs {
+		t, err := a.Resolve(output.Type)
+		if err != nil {
+			return fmt.Errorf("%s: output %d: %w", fn.Name, i, err)
+		}
+		rv := reflect.ValueOf(out[i])
+		if rv.Kind() != reflect.Pointer || rv.IsNil() {
+			return fmt.Errorf("%w: %s: output %d: destination must be a non-nil pointer, got %T", ErrTypeMismatch, fn.Name, i, out[i])
+		}
+		if err := a.decode(t, d, rv.Elem()); err != nil {
+			return fmt.Errorf("%s: output %d: %w", fn.Name, i, err)
+		}
+	}
+	if d.pos != len(result) {
+		return fmt.Errorf("%w: %d left", codec.ErrTrailingData, len(result)-d.pos)
+	}
+	return nil
+}
+
+// DecodeEvent deserializes an event emitted by a contract of the ABI, found
+// among the struct events by its first key, into its members keyed by name:
+// the key members from the other keys, the data members from the data, as
+// DecodeResult decodes into an any.
+//
+// Parameters:
+// - a: the ABI
+// - keys: the keys of the event, the selector first
+// - data: the data of the event
+// Returns:
+// - *Event: the event
+// - map[string]any: the values of the members
+// - error: ErrUnknownEvent, codec.ErrTrailingData, or a codec error naming the member
+func DecodeEvent(a *ABI, keys, data []*felt.Felt) (*Event, map[string]any, error) {
+	if len(keys) == 0 {
+		return nil, nil, fmt.Errorf("%w: no keys", ErrUnknownEvent)
+	}
+	ev := a.EventBySelector(keys[0])
+	if ev == nil {
+		return nil, nil, fmt.Errorf("%w: selector %s", ErrUnknownEvent, keys[0])
+	}
+	values, err := a.decodeEvent(ev, keys, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ev, values, nil
+}
+
+// decodeEvent deserializes the members of a struct event of the ABI.
+//
+// Parameters:
+// - ev: the event
+// - keys: the keys of the event, the selector first
+// - data: the data of the event
+// Returns:
+// - map[string]any: the values of the members
+// - error: codec.ErrTrailingData, or a codec error naming the member
+func (a *ABI) decodeEvent(ev *Event, keys, data []*felt.Felt) (map[string]any, error) {
+	keyDecoder, dataDecoder := &decoder{data: keys[1:]}, &decoder{data: data}
+	values := make(map[string]any, len(ev.Members))
+	for _, member := range ev.Members {
+		t, err := a.Resolve(member.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", ev.Name, member.Name, err)
+		}
+		d := dataDecoder
+		if member.Kind == MemberKey {
+			d = keyDecoder
+		}
+		var v any
+		if err := a.decode(t, d, reflect.ValueOf(&v).Elem()); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", ev.Name, member.Name, err)
+		}
+		values[member.Name] = v
+	}
+	if keyDecoder.pos != len(keyDecoder.data) || dataDecoder.pos != len(data) {
+		return nil, fmt.Errorf("%w: %s: %d keys and %d data left", codec.ErrTrailingData, ev.Name, len(keyDecoder.data)-keyDecoder.pos, len(data)-dataDecoder.pos)
+	}
+	return values, nil
+}
+
+// mismatch returns an error wrapping ErrTypeMismatch.
+//
+// Parameters:
+// - t: the ABI type
+// - rt: the Go type
+// Returns:
+// - error: the error
+func mismatch(t *Type, rt reflect.Type) error {
+	return fmt.Errorf("%w: %s can not be a %s", ErrTypeMismatch, rt, t.Name)
+}
+
+// scalarAccepts returns whether a Go type can hold a value of a core scalar
+// type.
+//
+// Parameters:
+// - kind: the codec kind of the core type
+// - rt: the Go type
+// Returns:
+// - bool: true if the Go type is accepted
+func scalarAccepts(kind string, rt reflect.Type) bool {
+	for rt.Kind() == reflect.Pointer && rt != feltPtrType && rt != bigIntPtrType {
+		rt = rt.Elem()
+	}
+	switch kind {
+	case codec.KindBool:
+		return rt.Kind() == reflect.Bool
+	case codec.KindByteArray:
+		return rt.Kind() == reflect.String
+	}
+	switch rt {
+	case feltPtrType, feltPtrType.Elem(), bigIntPtrType, bigIntPtrType.Elem():
+		return true
+	}
+	switch rt.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	case reflect.String:
+		// felt252 values can be given as short strings
+		return kind == codec.KindFelt
+	}
+	return false
+}
+
+// encode serializes a value as an ABI type.
+//
+// Parameters:
+// - t: the resolved type
+// - rv: the value
+// Returns:
+// - []*felt.Felt: the serialized value
+// - error: an error wrapping ErrTypeMismatch or a codec error
+func (a *ABI) encode(t *Type, rv reflect.Value) ([]*felt.Felt, error) {
+	if t.Tuple && len(t.Args) == 0 {
+		return nil, nil
+	}
+	for rv.IsValid() && rv.Kind() == reflect.Interface && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Kind() == reflect.Interface {
+		return nil, fmt.Errorf("%w: nil can not be a %s", ErrTypeMismatch, t.Name)
+	}
+	if rv.Type().Implements(marshalerType) || reflect.PointerTo(rv.Type()).Implements(marshalerType) {
+		return codec.Marshal(rv.Interface())
+	}
+	if t.Kind != "" {
+		if !scalarAccepts(t.Kind, rv.Type()) {
+			return nil, mismatch(t, rv.Type())
+		}
+		return codec.MarshalAs(rv.Interface(), t.Kind)
+	}
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("%w: nil can not be a %s", ErrTypeMismatch, t.Name)
+		}
+		rv = rv.Elem()
+	}
+
+	switch {
+	case t.Array:
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, mismatch(t, rv.Type())
+		}
+		elems, err := a.encodeElems(t.Args[0], rv)
+		if err != nil {
+			return nil, err
+		}
+		return append([]*felt.Felt{new(felt.Felt).SetUint64(uint64(rv.Len()))}, elems...), nil
+	case t.Len > 0:
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array || rv.Len() != t.Len {
+			return nil, mismatch(t, rv.Type())
+		}
+		return a.encodeElems(t.Args[0], rv)
+	case t.Tuple:
+		return a.encodeMembers(t, rv, t.Args, nil)
+	case t.Struct != nil:
+		types, names, err := a.structTypes(t.Struct)
+		if err != nil {
+			return nil, err
+		}
+		return a.encodeMembers(t, rv, types, names)
+	case t.Enum != nil:
+		if rv.Type() != variantType {
+			if rv.Kind() != reflect.Struct {
+				return nil, mismatch(t, rv.Type())
+			}
+			// codec enums and options
+			return codec.Marshal(rv.Interface())
+		}
+		v := rv.Interface().(Variant)
+		for i, variant := range t.Enum.Variants {
+			if variant.Name != v.Name {
+				continue
+			}
+			vt, err := a.Resolve(variant.Type)
+			if err != nil {
+				return nil, err
+			}
+			data, err := a.encode(vt, reflect.ValueOf(v.Value))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", v.Name, err)
+			}
+			return append([]*felt.Felt{new(felt.Felt).SetUint64(uint64(i))}, data...), nil
+		}
+		return nil, fmt.Errorf("%w: %s has no variant %s", ErrTypeMismatch, t.Name, v.Name)
+	}
+	return nil, mismatch(t, rv.Type())
+}
+
+// encodeElems serializes the elements of a slice or an array as an ABI type.
+//
+// Parameters:
+// - t: the type of the elements
+// - rv: the slice or array
+// Returns:
+// - []*felt.Felt: the serialized elements
+// - error: an error naming the index of the element
+func (a *ABI) encodeElems(t *Type, rv reflect.Value) ([]*felt.Felt, error) {
+	result := []*felt.Felt{}
+	for i := 0; i < rv.Len(); i++ {
+		data, err := a.encode(t, rv.Index(i))
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", i, err)
+		}
+		result = append(result, data...)
+	}
+	return result, nil
+}
+
+// encodeMembers serializes the members of a struct or a tuple from a Go
+// struct, a slice, an array or, for structs, a map keyed by member name.
+//
+// Parameters:
+// - t: the struct or tuple type
+// - rv: the value
+// - types: the types of the members
+// - names: the names of the members, nil for tuples
+// Returns:
+// - []*felt.Felt: the serialized members
+// - error: an error wrapping ErrTypeMismatch or naming the member
+func (a *ABI) encodeMembers(t *Type, rv reflect.Value, types []*Type, names []string) ([]*felt.Felt, error) {
+	var values []reflect.Value
+	switch rv.Kind() {
+	case reflect.Struct:
+		values = fields(rv)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, rv.Index(i))
+		}
+	case reflect.Map:
+		if names == nil || rv.Type().Key().Kind() != reflect.String {
+			return nil, mismatch(t, rv.Type())
+		}
+		if rv.Len() != len(names) {
+			return nil, fmt.Errorf("%w: %s has %d members, got %d", ErrTypeMismatch, t.Name, len(names), rv.Len())
+		}
+		for _, name := range names {
+			v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+			if !v.IsValid() {
+				return nil, fmt.Errorf("%w: %s: missing member %s", ErrTypeMismatch, t.Name, name)
+			}
+			values = append(values, v)
+		}
+	default:
+		return nil, mismatch(t, rv.Type())
+	}
+	if len(values) != len(types) {
+		return nil, fmt.Errorf("%w: %s has %d members, got %d", ErrTypeMismatch, t.Name, len(types), len(values))
+	}
+	result := []*felt.Felt{}
+	for i, mt := range types {
+		data, err := a.encode(mt, values[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", memberName(names, i), err)
+		}
+		result = append(result, data...)
+	}
+	return result, nil
+}
+
+// fields returns the exported fields of a struct not tagged `cairo:"-"`, in
+// declaration order.
+//
+// Parameters:
+// - rv: the struct
+// Returns:
+// - []reflect.Value: the fields
+func fields(rv reflect.Value) []reflect.Value {
+	var result []reflect.Value
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Type().Field(i)
+		if !f.IsExported() || f.Tag.Get("cairo") == "-" {
+			continue
+		}
+		result = append(result, rv.Field(i))
+	}
+	return result
+}
+
+// memberName returns the name of a member for errors, its index for tuples.
+//
+// Parameters:
+// - names: the names of the members, nil for tuples
+// - i: the index of the member
+// Returns:
+// - string: the name
+func memberName(names []string, i int) string {
+	if names == nil {
+		return fmt.Sprintf("element %d", i)
+	}
+	return names[i]
+}
+
+// decoder reads the felts of a result sequentially.
+type decoder struct {
+	data []*felt.Felt
+	pos  int
+}
+
+// decode deserializes an ABI type into a settable value.
+//
+// Parameters:
+// - t: the resolved type
+// - d: the decoder
+// - rv: the destination
+// Returns:
+// - error: an error wrapping ErrTypeMismatch or a codec error
+func (a *ABI) decode(t *Type, d *decoder, rv reflect.Value) error {
+	if rv.Kind() == reflect.Interface && rv.NumMethod() == 0 {
+		return a.decodeAny(t, d, rv)
+	}
+	if t.Tuple && len(t.Args) == 0 {
+		return nil
+	}
+	if rv.CanAddr() && reflect.PointerTo(rv.Type()).Implements(unmarshalerType) {
+		return d.codec(rv, "")
+	}
+	if t.Kind != "" {
+		if !scalarAccepts(t.Kind, rv.Type()) {
+			return mismatch(t, rv.Type())
+		}
+		return d.codec(rv, t.Kind)
+	}
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return a.decode(t, d, rv.Elem())
+	}
+
+	switch {
+	case t.Array:
+		if rv.Kind() != reflect.Slice {
+			return mismatch(t, rv.Type())
+		}
+		n, err := d.codecLen()
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.MakeSlice(rv.Type(), n, n))
+		return a.decodeElems(t.Args[0], d, rv)
+	case t.Len > 0:
+		switch {
+		case rv.Kind() == reflect.Slice:
+			rv.Set(reflect.MakeSlice(rv.Type(), t.Len, t.Len))
+		case rv.Kind() != reflect.Array || rv.Len() != t.Len:
+			return mismatch(t, rv.Type())
+		}
+		return a.decodeElems(t.Args[0], d, rv)
+	case t.Tuple:
+		return a.decodeMembers(t, d, rv, t.Args, nil)
+	case t.Struct != nil:
+		types, names, err := a.structTypes(t.Struct)
+		if err != nil {
+			return err
+		}
+		return a.decodeMembers(t, d, rv, types, names)
+	case t.Enum != nil:
+		if rv.Type() != variantType {
+			if rv.Kind() != reflect.Struct {
+				return mismatch(t, rv.Type())
+			}
+			// codec enums and options
+			return d.codec(rv, "")
+		}
+		v, err := a.decodeVariant(t, d)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	}
+	return mismatch(t, rv.Type())
+}
+
+// decodeAny deserializes an ABI type into an any, as documented by
+// DecodeResult.
+//
+// Parameters:
+// - t: the resolved type
+// - d: the decoder
+// - rv: the destination, an empty interface
+// Returns:
+// - error: a codec error
+func (a *ABI) decodeAny(t *Type, d *decoder, rv reflect.Value) error {
+	var v reflect.Value
+	switch {
+	case t.Tuple && len(t.Args) == 0:
+		return nil
+	case t.Kind == codec.KindBool:
+		v = reflect.New(reflect.TypeOf(false)).Elem()
+	case t.Kind == codec.KindByteArray:
+		v = reflect.New(reflect.TypeOf("")).Elem()
+	case t.Kind == codec.KindFelt || t.Kind == codec.KindContractAddress || t.Kind == codec.KindClassHash:
+		v = reflect.New(feltPtrType).Elem()
+	case t.Kind != "":
+		v = reflect.New(bigIntPtrType).Elem()
+	case t.Array || t.Len > 0 || t.Tuple:
+		v = reflect.New(reflect.TypeOf([]any{})).Elem()
+	case t.Struct != nil:
+		v = reflect.ValueOf(map[string]any{})
+	case t.Enum != nil:
+		v = reflect.New(variantType).Elem()
+	default:
+		return mismatch(t, rv.Type())
+	}
+	if err := a.decode(t, d, v); err != nil {
+		return err
+	}
+	rv.Set(v)
+	return nil
+}
+
+// decodeElems deserializes the elements of a slice or an array.
+//
+// Parameters:
+// - t: the type of the elements
+// - d: the decoder
+// - rv: the slice or array, of the length of the data
+// Returns:
+// - error: an error naming the index of the element
+func (a *ABI) decodeElems(t *Type, d *decoder, rv reflect.Value) error {
+	for i := 0; i < rv.Len(); i++ {
+		if err := a.decode(t, d, rv.Index(i)); err != nil {
+			return fmt.Errorf("index %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// decodeMembers deserializes the members of a struct or a tuple into a Go
+// struct, a slice, an array or, for structs, a map keyed by member name.
+//
+// Parameters:
+// - t: the struct or tuple type
+// - d: the decoder
+// - rv: the destination
+// - types: the types of the members
+// - names: the names of the members, nil for tuples
+// Returns:
+// - error: an error wrapping ErrTypeMismatch or naming the member
+func (a *ABI) decodeMembers(t *Type, d *decoder, rv reflect.Value, types []*Type, names []string) error {
+	switch rv.Kind() {
+	case reflect.Struct:
+		values := fields(rv)
+		if len(values) != len(types) {
+			return fmt.Errorf("%w: %s has %d members, %s has %d fields", ErrTypeMismatch, t.Name, len(types), rv.Type(), len(values))
+		}
+		for i, mt := range types {
+			if err := a.decode(mt, d, values[i]); err != nil {
+				return fmt.Errorf("%s: %w", memberName(names, i), err)
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice {
+			rv.Set(reflect.MakeSlice(rv.Type(), len(types), len(types)))
+		} else if rv.Len() != len(types) {
+			return mismatch(t, rv.Type())
+		}
+		for i, mt := range types {
+			if err := a.decode(mt, d, rv.Index(i)); err != nil {
+				return fmt.Errorf("%s: %w", memberName(names, i), err)
+			}
+		}
+		return nil
+	case reflect.Map:
+		if names == nil || rv.Type().Key().Kind() != reflect.String {
+			return mismatch(t, rv.Type())
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
+		for i, mt := range types {
+			v := reflect.New(rv.Type().Elem()).Elem()
+			if err := a.decode(mt, d, v); err != nil {
+				return fmt.Errorf("%s: %w", names[i], err)
+			}
+			rv.SetMapIndex(reflect.ValueOf(names[i]).Convert(rv.Type().Key()), v)
+		}
+		return nil
+	}
+	return mismatch(t, rv.Type())
+}
+
+// decodeVariant deserializes an enum value as a Variant.
+//
+// Parameters:
+// - t: the enum type
+// - d: the decoder
+// Returns:
+// - Variant: the variant, its value decoded as an any
+// - error: codec.ErrInvalidEnum for unknown variants, or a codec error
+func (a *ABI) decodeVariant(t *Type, d *decoder) (Variant, error) {
+	var index uint64
+	if err := d.codec(reflect.ValueOf(&index).Elem(), codec.KindU32); err != nil {
+		return Variant{}, err
+	}
+	if index >= uint64(len(t.Enum.Variants)) {
+		return Variant{}, fmt.Errorf("%w: %s has no variant %d", codec.ErrInvalidEnum, t.Name, index)
+	}
+	variant := t.Enum.Variants[index]
+	vt, err := a.Resolve(variant.Type)
+	if err != nil {
+		return Variant{}, err
+	}
+	v := Variant{Name: variant.Name}
+	if err := a.decode(vt, d, reflect.ValueOf(&v.Value).Elem()); err != nil {
+		return Variant{}, fmt.Errorf("%s: %w", variant.Name, err)
+	}
+	return v, nil
+}
+
+// structTypes resolves the types of the members of a struct.
+//
+// Parameters:
+// - s: the struct
+// Returns:
+// - []*Type: the types of the members
+// - []string: the names of the members
+// - error: an error of Resolve
+func (a *ABI) structTypes(s *Struct) ([]*Type, []string, error) {
+	types := make([]*Type, len(s.Members))
+	names := make([]string, len(s.Members))
+	for i, member := range s.Members {
+		t, err := a.Resolve(member.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		types[i], names[i] = t, member.Name
+	}
+	return types, names, nil
+}
+
+// codec deserializes the next felts into an addressable value with the codec.
+//
+// Parameters:
+// - rv: the destination
+// - kind: the Cairo type, empty to infer it from the Go type
+// Returns:
+// - error: a codec error
+func (d *decoder) codec(rv reflect.Value, kind string) error {
+	n, err := codec.UnmarshalPrefixAs(d.data[d.pos:], rv.Addr().Interface(), kind)
+	d.pos += n
+	return err
+}
+
+// codecLen deserializes the length prefix of an array.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - int: the length
+// - error: a codec error
+func (d *decoder) codecLen() (int, error) {
+	var n uint32
+	if err := d.codec(reflect.ValueOf(&n).Elem(), codec.KindU32); err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
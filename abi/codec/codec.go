@@ -0,0 +1,191 @@
+// Package codec implements the Cairo serde serialization of Go values to and
+// from felts, as used by calldata, call results and event data.
+//
+// Go types map to Cairo types as follows:
+//   - bool: bool
+//   - uint8 ... uint64, int8 ... int64: u8 ... u64, i8 ... i64
+//   - *big.Int, *felt.Felt, felt.Felt: felt252 (ContractAddress, ClassHash, ...)
+//   - utils.Uint256: u256
+//   - string: ByteArray
+//   - slices: Array<T> and Span<T>, prefixed with their length
+//   - arrays: tuples of the same type and fixed-size arrays, without prefix
+//   - structs: structs and tuples, serialized field by field
+//   - Option[T]: Option<T>
+//   - structs embedding Enum: enums, one pointer field per variant
+//
+// The `cairo` struct tag refines the Cairo type of a field, for instance
+// `cairo:"u256"` on a *big.Int, `cairo:"u128"` on a *felt.Felt or
+// `cairo:"shortstring"` on a string. A field tagged `cairo:"-"` is ignored.
+// On slices and arrays the tag applies to the elements.
+//
+// Types implementing Marshaler and Unmarshaler control their own serialization.
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var (
+	ErrUnsupportedType = errors.New("codec: unsupported type")
+	ErrOutOfRange      = errors.New("codec: value out of range")
+	ErrShortData       = errors.New("codec: not enough felts to decode")
+	ErrInvalidEnum     = errors.New("codec: invalid enum")
+	ErrTrailingData    = errors.New("codec: unused felts after decoding")
+)
+
+// Marshaler is implemented by types that serialize themselves to felts.
+type Marshaler interface {
+	MarshalCairo() ([]*felt.Felt, error)
+}
+
+// Unmarshaler is implemented by types that deserialize themselves from felts.
+// UnmarshalCairo receives the remaining felts and returns how many it consumed.
+type Unmarshaler interface {
+	UnmarshalCairo(data []*felt.Felt) (int, error)
+}
+
+// Enum is embedded in a struct to serialize it as a Cairo enum. Every other
+// exported field of the struct must be a pointer and is a variant, in
+// declaration order. Exactly one variant must be set when marshaling; unit
+// variants are declared as *struct{}.
+type Enum struct{}
+
+// Option is the Go counterpart of the Cairo Option<T>.
+type Option[T any] struct {
+	Value T
+	Valid bool
+}
+
+// Some returns an Option holding v.
+//
+// Parameters:
+// - v: the value of the option
+// Returns:
+// - Option[T]: a valid option
+func Some[T any](v T) Option[T] {
+	return Option[T]{Value: v, Valid: true}
+}
+
+// None returns an empty Option.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - Option[T]: an empty option
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+func (Option[T]) isCairoOption() {}
+
+type cairoOption interface {
+	isCairoOption()
+}
+
+// Marshal serializes v to felts following the Cairo serde rules.
+//
+// Parameters:
+// - v: the value to serialize
+// Returns:
+// - []*felt.Felt: the serialized value
+// - error: an error if v contains an unsupported type or an out of range value
+func Marshal(v any) ([]*felt.Felt, error) {
+	return MarshalAs(v, "")
+}
+
+// MarshalAs serializes v as the given Cairo type, as the `cairo` tag of a
+// field does, e.g. a *big.Int as a u256.
+//
+// Parameters:
+// - v: the value to serialize
+// - kind: the Cairo type, empty to infer it from the Go type
+// Returns:
+// - []*felt.Felt: the serialized value
+// - error: an error if v contains an unsupported type or an out of range value
+func MarshalAs(v any, kind string) ([]*felt.Felt, error) {
+	if v == nil {
+		return nil, fmt.Errorf("%w: nil", ErrUnsupportedType)
+	}
+	return encodeValue(reflect.ValueOf(v), kind)
+}
+
+// MarshalAll serializes each value and concatenates the results, which is the
+// layout of the calldata of a function taking several arguments.
+//
+// Parameters:
+// - values: the values to serialize, in order
+// Returns:
+// - []*felt.Felt: the concatenated serialized values
+// - error: an error if any of the values can not be serialized
+func MarshalAll(values ...any) ([]*felt.Felt, error) {
+	result := []*felt.Felt{}
+	for i, v := range values {
+		data, err := Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		result = append(result, data...)
+	}
+	return result, nil
+}
+
+// Unmarshal deserializes data into the value pointed to by v. Every felt of
+// data must be consumed.
+//
+// Parameters:
+// - data: the felts to deserialize
+// - v: a non-nil pointer to the destination
+// Returns:
+// - error: an error if the data does not match the destination type
+func Unmarshal(data []*felt.Felt, v any) error {
+	n, err := UnmarshalPrefix(data, v)
+	if err != nil {
+		return err
+	}
+	if n != len(data) {
+		return fmt.Errorf("%w: %d left", ErrTrailingData, len(data)-n)
+	}
+	return nil
+}
+
+// UnmarshalPrefix deserializes the beginning of data into the value pointed
+// to by v and returns the number of felts consumed.
+//
+// Parameters:
+// - data: the felts to deserialize
+// - v: a non-nil pointer to the destination
+// Returns:
+// - int: the number of felts consumed
+// - error: an error if the data does not match the destination type
+func UnmarshalPrefix(data []*felt.Felt, v any) (int, error) {
+	return UnmarshalPrefixAs(data, v, "")
+}
+
+// UnmarshalPrefixAs deserializes the beginning of data as the given Cairo
+// type into the value pointed to by v and returns the number of felts
+// consumed.
+//
+// Parameters:
+// - data: the felts to deserialize
+// - v: a non-nil pointer to the destination
+// - kind: the Cairo type, empty to infer it from the Go type
+// Returns:
+// - int: the number of felts consumed
+// - error: an error if the data does not match the destination type
+func UnmarshalPrefixAs(data []*felt.Felt, v any, kind string) (int, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return 0, fmt.Errorf("%w: destination must be a non-nil pointer, got %T", ErrUnsupportedType, v)
+	}
+	d := &decoder{data: data}
+	if err := d.decodeValue(rv.Elem(), kind); err != nil {
+		return d.pos, err
+	}
+	return d.pos, nil
+}
@@ -0,0 +1,145 @@
+package codec
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+type transfer struct {
+	Recipient *felt.Felt `cairo:"ContractAddress"`
+	Amount    *big.Int   `cairo:"u256"`
+	Memo      string
+	Symbol    string  `cairo:"shortstring"`
+	Ids       []uint8 `cairo:"u8"`
+	Delta     int32
+	Pair      [2]uint64
+	Fee       Option[uint128]
+	Kind      transferKind
+	internal  int
+	Ignored   string `cairo:"-"`
+}
+
+type uint128 struct {
+	Value *big.Int `cairo:"u128"`
+}
+
+type transferKind struct {
+	Enum
+	Instant  *struct{}
+	Delayed  *uint64
+	Approved *[]bool
+}
+
+// TestMarshal_RoundTrip tests the serialization of a struct covering every
+// supported Cairo type and its deserialization back to the same value.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestMarshal_RoundTrip(t *testing.T) {
+	delay := uint64(3600)
+	amount, _ := new(big.Int).SetString("340282366920938463463374607431768211457", 10) // 2^128 + 1
+	value := transfer{
+		Recipient: utils.TestHexToFelt(t, "0x1234"),
+		Amount:    amount,
+		Memo:      "a memo that is longer than thirty-one bytes",
+		Symbol:    "ETH",
+		Ids:       []uint8{1, 2},
+		Delta:     -5,
+		Pair:      [2]uint64{7, 8},
+		Fee:       Some(uint128{Value: big.NewInt(10)}),
+		Kind:      transferKind{Delayed: &delay},
+		internal:  1,
+		Ignored:   "ignored",
+	}
+
+	data, err := Marshal(value)
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{
+		utils.TestHexToFelt(t, "0x1234"),
+		utils.TestHexToFelt(t, "0x1"), utils.TestHexToFelt(t, "0x1"),
+		utils.TestHexToFelt(t, "0x1"),
+		new(felt.Felt).SetBytes([]byte("a memo that is longer than thir")),
+		new(felt.Felt).SetBytes([]byte("ty-one bytes")),
+		utils.TestHexToFelt(t, "0xc"),
+		new(felt.Felt).SetBytes([]byte("ETH")),
+		utils.TestHexToFelt(t, "0x2"), utils.TestHexToFelt(t, "0x1"), utils.TestHexToFelt(t, "0x2"),
+		new(felt.Felt).SetBigInt(big.NewInt(-5)),
+		utils.TestHexToFelt(t, "0x7"), utils.TestHexToFelt(t, "0x8"),
+		utils.TestHexToFelt(t, "0x0"), utils.TestHexToFelt(t, "0xa"),
+		utils.TestHexToFelt(t, "0x1"), utils.TestHexToFelt(t, "0xe10"),
+	}, data)
+
+	var decoded transfer
+	require.NoError(t, Unmarshal(data, &decoded))
+	value.internal, value.Ignored = 0, ""
+	require.Equal(t, value, decoded)
+
+	require.True(t, errors.Is(Unmarshal(append(data, new(felt.Felt)), &decoded), ErrTrailingData))
+	require.True(t, errors.Is(Unmarshal(data[:len(data)-1], &decoded), ErrShortData))
+}
+
+// TestMarshal_Errors tests that out of range values and invalid enums are rejected.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestMarshal_Errors(t *testing.T) {
+	type small struct {
+		Value uint64 `cairo:"u8"`
+	}
+	type signed struct {
+		Value int64 `cairo:"i8"`
+	}
+
+	_, err := Marshal(small{Value: 256})
+	require.True(t, errors.Is(err, ErrOutOfRange))
+	_, err = Marshal(signed{Value: -129})
+	require.True(t, errors.Is(err, ErrOutOfRange))
+	_, err = Marshal(big.NewInt(-1))
+	require.True(t, errors.Is(err, ErrOutOfRange))
+	_, err = Marshal(transferKind{})
+	require.True(t, errors.Is(err, ErrInvalidEnum))
+	_, err = Marshal(transferKind{Instant: &struct{}{}, Approved: &[]bool{}})
+	require.True(t, errors.Is(err, ErrInvalidEnum))
+
+	var s small
+	require.True(t, errors.Is(Unmarshal([]*felt.Felt{new(felt.Felt).SetUint64(300)}, &s), ErrOutOfRange))
+	var k transferKind
+	require.True(t, errors.Is(Unmarshal([]*felt.Felt{new(felt.Felt).SetUint64(3)}, &k), ErrInvalidEnum))
+}
+
+// TestMarshal_Uint256 tests that utils.Uint256 values and fields are
+// serialized through their Marshaler implementation.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestMarshal_Uint256(t *testing.T) {
+	type balance struct {
+		Amount utils.Uint256
+		Limit  *utils.Uint256
+	}
+	amount, err := utils.HexToUint256("0x100000000000000000000000000000002")
+	require.NoError(t, err)
+
+	data, err := Marshal(balance{Amount: *amount, Limit: utils.Uint64ToUint256(7)})
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexArrToFelt(t, []string{"0x2", "0x1", "0x7", "0x0"}), data)
+
+	var decoded balance
+	require.NoError(t, Unmarshal(data, &decoded))
+	require.Equal(t, 0, amount.Cmp(&decoded.Amount))
+	require.Equal(t, "7", decoded.Limit.String())
+}
@@ -0,0 +1,371 @@
+package codec
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// decoder reads felts sequentially while deserializing a value.
+type decoder struct {
+	data []*felt.Felt
+	pos  int
+}
+
+// next returns the next felt of the data.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the next felt
+// - error: ErrShortData if every felt was consumed
+func (d *decoder) next() (*felt.Felt, error) {
+	if d.pos >= len(d.data) {
+		return nil, ErrShortData
+	}
+	f := d.data[d.pos]
+	if f == nil {
+		return nil, fmt.Errorf("%w: nil felt at position %d", ErrUnsupportedType, d.pos)
+	}
+	d.pos++
+	return f, nil
+}
+
+// nextBig returns the next felt as an integer of the given Cairo type.
+//
+// Parameters:
+// - kind: the Cairo type, felt252 when empty
+// Returns:
+// - *big.Int: the decoded integer
+// - error: an error if the data is too short or the value out of range
+func (d *decoder) nextBig(kind string) (*big.Int, error) {
+	switch kind {
+	case "", KindFelt, KindContractAddress, KindClassHash:
+		f, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		return f.BigInt(new(big.Int)), nil
+	case KindU256:
+		low, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		high, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		v := low.BigInt(new(big.Int))
+		if v.BitLen() > 128 {
+			return nil, fmt.Errorf("%w: u256 low limb %s", ErrOutOfRange, v)
+		}
+		h := high.BigInt(new(big.Int))
+		if h.BitLen() > 128 {
+			return nil, fmt.Errorf("%w: u256 high limb %s", ErrOutOfRange, h)
+		}
+		return v.Or(v, h.Lsh(h, 128)), nil
+	}
+	bits, signed := intBits(kind)
+	if bits == 0 {
+		return nil, fmt.Errorf("%w: integer as %s", ErrUnsupportedType, kind)
+	}
+	f, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+	v := f.BigInt(new(big.Int))
+	if signed && v.Cmp(halfPrime) > 0 {
+		v.Sub(v, utils.FieldPrime)
+	}
+	if !checkRange(v, kind) {
+		return nil, fmt.Errorf("%w: %s is not a valid %s", ErrOutOfRange, v, kind)
+	}
+	return v, nil
+}
+
+// nextLen returns the next felt as a length.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - int: the decoded length
+// - error: an error if the length exceeds the remaining data
+func (d *decoder) nextLen() (int, error) {
+	f, err := d.next()
+	if err != nil {
+		return 0, err
+	}
+	v := f.BigInt(new(big.Int))
+	// every element takes at least one felt
+	if !v.IsInt64() || v.Int64() > int64(len(d.data)-d.pos) {
+		return 0, fmt.Errorf("%w: length %s", ErrShortData, v)
+	}
+	return int(v.Int64()), nil
+}
+
+// decodeValue deserializes the next felts into a reflected value.
+//
+// Parameters:
+// - rv: the settable destination
+// - kind: the Cairo type from the struct tag, empty to infer it from the Go type
+// Returns:
+// - error: an error if the data does not match the destination
+func (d *decoder) decodeValue(rv reflect.Value, kind string) error {
+	t := rv.Type()
+
+	if rv.CanAddr() && reflect.PointerTo(t).Implements(unmarshalerType) {
+		n, err := rv.Addr().Interface().(Unmarshaler).UnmarshalCairo(d.data[d.pos:])
+		if err != nil {
+			return err
+		}
+		if n < 0 || n > len(d.data)-d.pos {
+			return fmt.Errorf("%w: %s consumed %d felts", ErrShortData, t, n)
+		}
+		d.pos += n
+		return nil
+	}
+
+	switch t {
+	case feltPtrType, bigIntPtrType:
+		if rv.IsNil() {
+			rv.Set(reflect.New(t.Elem()))
+		}
+		return d.decodeValue(rv.Elem(), kind)
+	case feltType:
+		v, err := d.nextBig(kind)
+		if err != nil {
+			return err
+		}
+		if !checkRange(v, "") {
+			return fmt.Errorf("%w: %s does not fit in a felt", ErrOutOfRange, v)
+		}
+		rv.Set(reflect.ValueOf(*new(felt.Felt).SetBigInt(v)))
+		return nil
+	case bigIntType:
+		v, err := d.nextBig(kind)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(*v))
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		f, err := d.next()
+		if err != nil {
+			return err
+		}
+		switch {
+		case f.IsZero():
+			rv.SetBool(false)
+		case f.IsOne():
+			rv.SetBool(true)
+		default:
+			return fmt.Errorf("%w: %s is not a valid bool", ErrOutOfRange, f)
+		}
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if kind == "" {
+			kind = goKind(t.Kind())
+		}
+		v, err := d.nextBig(kind)
+		if err != nil {
+			return err
+		}
+		if v.Sign() < 0 || !v.IsUint64() || rv.OverflowUint(v.Uint64()) {
+			return fmt.Errorf("%w: %s does not fit in %s", ErrOutOfRange, v, t)
+		}
+		rv.SetUint(v.Uint64())
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if kind == "" {
+			kind = goKind(t.Kind())
+		}
+		v, err := d.nextBig(kind)
+		if err != nil {
+			return err
+		}
+		if !v.IsInt64() || rv.OverflowInt(v.Int64()) {
+			return fmt.Errorf("%w: %s does not fit in %s", ErrOutOfRange, v, t)
+		}
+		rv.SetInt(v.Int64())
+		return nil
+	case reflect.String:
+		if kind == KindShortString || kind == KindFelt {
+			f, err := d.next()
+			if err != nil {
+				return err
+			}
+			rv.SetString(shortString(f))
+			return nil
+		}
+		s, err := d.decodeByteArray()
+		if err != nil {
+			return err
+		}
+		rv.SetString(s)
+		return nil
+	case reflect.Slice:
+		n, err := d.nextLen()
+		if err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(t, n, n)
+		for i := 0; i < n; i++ {
+			if err := d.decodeValue(slice.Index(i), kind); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		rv.Set(slice)
+		return nil
+	case reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := d.decodeValue(rv.Index(i), kind); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		return nil
+	case reflect.Struct:
+		if t.Implements(optionType) {
+			return d.decodeOption(rv, kind)
+		}
+		if isEnum(t) {
+			return d.decodeEnum(rv)
+		}
+		return d.decodeStruct(rv)
+	case reflect.Pointer:
+		if rv.IsNil() {
+			rv.Set(reflect.New(t.Elem()))
+		}
+		return d.decodeValue(rv.Elem(), kind)
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+}
+
+// decodeByteArray deserializes a Cairo ByteArray.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the decoded string
+// - error: an error if the data is not a valid ByteArray
+func (d *decoder) decodeByteArray() (string, error) {
+	start := d.pos
+	full, err := d.nextLen()
+	if err != nil {
+		return "", err
+	}
+	end := start + full + 3
+	if end > len(d.data) {
+		return "", ErrShortData
+	}
+	s, err := utils.ByteArrayFeltsToString(d.data[start:end])
+	if err != nil {
+		return "", err
+	}
+	d.pos = end
+	return s, nil
+}
+
+// shortString decodes a felt holding an ASCII short string.
+//
+// Parameters:
+// - f: the felt
+// Returns:
+// - string: the decoded string
+func shortString(f *felt.Felt) string {
+	b := f.Bytes()
+	i := 0
+	for i < len(b) && b[i] == 0 {
+		i++
+	}
+	return string(b[i:])
+}
+
+// decodeStruct deserializes the exported fields of a struct in declaration order.
+//
+// Parameters:
+// - rv: the settable struct
+// Returns:
+// - error: an error if a field can not be deserialized
+func (d *decoder) decodeStruct(rv reflect.Value) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		kind, skip := parseTag(field)
+		if skip {
+			continue
+		}
+		if err := d.decodeValue(rv.Field(i), kind); err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+	}
+	return nil
+}
+
+// decodeOption deserializes an Option.
+//
+// Parameters:
+// - rv: the settable Option
+// - kind: the Cairo type of the value
+// Returns:
+// - error: an error if the variant index is not 0 or 1
+func (d *decoder) decodeOption(rv reflect.Value, kind string) error {
+	f, err := d.next()
+	if err != nil {
+		return err
+	}
+	switch {
+	case f.IsZero():
+		rv.FieldByName("Valid").SetBool(true)
+		return d.decodeValue(rv.FieldByName("Value"), kind)
+	case f.IsOne():
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	}
+	return fmt.Errorf("%w: option variant %s", ErrInvalidEnum, f)
+}
+
+// decodeEnum deserializes an enum, setting the variant matching the index.
+//
+// Parameters:
+// - rv: the settable enum struct
+// Returns:
+// - error: an error if the index does not match a variant
+func (d *decoder) decodeEnum(rv reflect.Value) error {
+	t := rv.Type()
+	f, err := d.next()
+	if err != nil {
+		return err
+	}
+	idx := variants(t)
+	v := f.BigInt(new(big.Int))
+	if !v.IsInt64() || v.Int64() >= int64(len(idx)) {
+		return fmt.Errorf("%w: %s has no variant %s", ErrInvalidEnum, t.Name(), v)
+	}
+	rv.Set(reflect.Zero(t))
+	field := t.Field(idx[v.Int64()])
+	if field.Type.Kind() != reflect.Pointer {
+		return fmt.Errorf("%w: variant %s.%s must be a pointer", ErrInvalidEnum, t.Name(), field.Name)
+	}
+	kind, _ := parseTag(field)
+	value := reflect.New(field.Type.Elem())
+	if err := d.decodeValue(value.Elem(), kind); err != nil {
+		return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+	}
+	rv.Field(idx[v.Int64()]).Set(value)
+	return nil
+}
@@ -0,0 +1,259 @@
+package codec
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// encodeValue serializes a reflected value as the given Cairo type.
+//
+// Parameters:
+// - rv: the value to serialize
+// - kind: the Cairo type from the struct tag, empty to infer it from the Go type
+// Returns:
+// - []*felt.Felt: the serialized value
+// - error: an error if the value can not be serialized
+func encodeValue(rv reflect.Value, kind string) ([]*felt.Felt, error) {
+	if !rv.IsValid() {
+		return nil, fmt.Errorf("%w: invalid value", ErrUnsupportedType)
+	}
+	t := rv.Type()
+
+	if t.Implements(marshalerType) {
+		if t.Kind() == reflect.Pointer && rv.IsNil() {
+			return nil, fmt.Errorf("%w: nil %s", ErrUnsupportedType, t)
+		}
+		return rv.Interface().(Marshaler).MarshalCairo()
+	}
+	if reflect.PointerTo(t).Implements(marshalerType) {
+		if !rv.CanAddr() {
+			addressable := reflect.New(t).Elem()
+			addressable.Set(rv)
+			rv = addressable
+		}
+		return rv.Addr().Interface().(Marshaler).MarshalCairo()
+	}
+
+	switch t {
+	case feltPtrType:
+		if rv.IsNil() {
+			return nil, fmt.Errorf("%w: nil *felt.Felt", ErrUnsupportedType)
+		}
+		return encodeBig(rv.Interface().(*felt.Felt).BigInt(new(big.Int)), kind)
+	case feltType:
+		f := rv.Interface().(felt.Felt)
+		return encodeBig(f.BigInt(new(big.Int)), kind)
+	case bigIntPtrType:
+		if rv.IsNil() {
+			return nil, fmt.Errorf("%w: nil *big.Int", ErrUnsupportedType)
+		}
+		return encodeBig(rv.Interface().(*big.Int), kind)
+	case bigIntType:
+		b := rv.Interface().(big.Int)
+		return encodeBig(&b, kind)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			return []*felt.Felt{new(felt.Felt).SetUint64(1)}, nil
+		}
+		return []*felt.Felt{new(felt.Felt)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if kind == "" {
+			kind = goKind(t.Kind())
+		}
+		return encodeBig(new(big.Int).SetUint64(rv.Uint()), kind)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if kind == "" {
+			kind = goKind(t.Kind())
+		}
+		return encodeBig(big.NewInt(rv.Int()), kind)
+	case reflect.String:
+		if kind == KindShortString || kind == KindFelt {
+			return encodeShortString(rv.String())
+		}
+		return utils.StringToByteArrayFelts(rv.String()), nil
+	case reflect.Slice:
+		result := []*felt.Felt{new(felt.Felt).SetUint64(uint64(rv.Len()))}
+		elems, err := encodeElems(rv, kind)
+		if err != nil {
+			return nil, err
+		}
+		return append(result, elems...), nil
+	case reflect.Array:
+		return encodeElems(rv, kind)
+	case reflect.Struct:
+		if t.Implements(optionType) {
+			return encodeOption(rv, kind)
+		}
+		if isEnum(t) {
+			return encodeEnum(rv)
+		}
+		return encodeStruct(rv)
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil, fmt.Errorf("%w: nil %s", ErrUnsupportedType, t)
+		}
+		return encodeValue(rv.Elem(), kind)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+}
+
+// encodeBig serializes an integer as the given Cairo type. Negative values of
+// signed types are represented as P - |v|, u256 values as their low and high
+// 128-bit limbs.
+//
+// Parameters:
+// - v: the integer to serialize
+// - kind: the Cairo type, felt252 when empty
+// Returns:
+// - []*felt.Felt: the serialized integer
+// - error: an error if v does not fit in the type
+func encodeBig(v *big.Int, kind string) ([]*felt.Felt, error) {
+	switch kind {
+	case "", KindFelt, KindContractAddress, KindClassHash:
+		kind = ""
+	default:
+		if bits, _ := intBits(kind); bits == 0 {
+			return nil, fmt.Errorf("%w: integer as %s", ErrUnsupportedType, kind)
+		}
+	}
+	if !checkRange(v, kind) {
+		if kind == "" {
+			kind = KindFelt
+		}
+		return nil, fmt.Errorf("%w: %s is not a valid %s", ErrOutOfRange, v, kind)
+	}
+	if kind == KindU256 {
+		mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+		low := new(big.Int).And(v, mask)
+		high := new(big.Int).Rsh(v, 128)
+		return []*felt.Felt{new(felt.Felt).SetBigInt(low), new(felt.Felt).SetBigInt(high)}, nil
+	}
+	// SetBigInt reduces negative values modulo P
+	return []*felt.Felt{new(felt.Felt).SetBigInt(v)}, nil
+}
+
+// encodeShortString serializes an ASCII string of at most 31 characters as a
+// single felt.
+//
+// Parameters:
+// - s: the string to serialize
+// Returns:
+// - []*felt.Felt: the serialized string
+// - error: an error if s is too long
+func encodeShortString(s string) ([]*felt.Felt, error) {
+	if len(s) > 31 {
+		return nil, fmt.Errorf("%w: short string %q is longer than 31 bytes", ErrOutOfRange, s)
+	}
+	return []*felt.Felt{new(felt.Felt).SetBytes([]byte(s))}, nil
+}
+
+// encodeElems serializes the elements of a slice or an array one after the other.
+//
+// Parameters:
+// - rv: the slice or array
+// - kind: the Cairo type of the elements
+// Returns:
+// - []*felt.Felt: the serialized elements
+// - error: an error if an element can not be serialized
+func encodeElems(rv reflect.Value, kind string) ([]*felt.Felt, error) {
+	result := []*felt.Felt{}
+	for i := 0; i < rv.Len(); i++ {
+		data, err := encodeValue(rv.Index(i), kind)
+		if err != nil {
+			return nil, fmt.Errorf("index %d: %w", i, err)
+		}
+		result = append(result, data...)
+	}
+	return result, nil
+}
+
+// encodeStruct serializes the exported fields of a struct in declaration order.
+//
+// Parameters:
+// - rv: the struct
+// Returns:
+// - []*felt.Felt: the serialized fields
+// - error: an error if a field can not be serialized
+func encodeStruct(rv reflect.Value) ([]*felt.Felt, error) {
+	t := rv.Type()
+	result := []*felt.Felt{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		kind, skip := parseTag(field)
+		if skip {
+			continue
+		}
+		data, err := encodeValue(rv.Field(i), kind)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		result = append(result, data...)
+	}
+	return result, nil
+}
+
+// encodeOption serializes an Option as the variant index, 0 for Some and 1
+// for None, followed by the value when it is set.
+//
+// Parameters:
+// - rv: the Option
+// - kind: the Cairo type of the value
+// Returns:
+// - []*felt.Felt: the serialized Option
+// - error: an error if the value can not be serialized
+func encodeOption(rv reflect.Value, kind string) ([]*felt.Felt, error) {
+	if !rv.FieldByName("Valid").Bool() {
+		return []*felt.Felt{new(felt.Felt).SetUint64(1)}, nil
+	}
+	data, err := encodeValue(rv.FieldByName("Value"), kind)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*felt.Felt{new(felt.Felt)}, data...), nil
+}
+
+// encodeEnum serializes an enum as the index of its set variant followed by
+// the variant payload.
+//
+// Parameters:
+// - rv: the enum struct
+// Returns:
+// - []*felt.Felt: the serialized enum
+// - error: an error if no or several variants are set
+func encodeEnum(rv reflect.Value) ([]*felt.Felt, error) {
+	t := rv.Type()
+	var result []*felt.Felt
+	for variant, i := range variants(t) {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Pointer {
+			return nil, fmt.Errorf("%w: variant %s.%s must be a pointer", ErrInvalidEnum, t.Name(), field.Name)
+		}
+		value := rv.Field(i)
+		if value.IsNil() {
+			continue
+		}
+		if result != nil {
+			return nil, fmt.Errorf("%w: %s has several variants set", ErrInvalidEnum, t.Name())
+		}
+		kind, _ := parseTag(field)
+		payload, err := encodeValue(value.Elem(), kind)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		result = append([]*felt.Felt{new(felt.Felt).SetUint64(uint64(variant))}, payload...)
+	}
+	if result == nil {
+		return nil, fmt.Errorf("%w: %s has no variant set", ErrInvalidEnum, t.Name())
+	}
+	return result, nil
+}
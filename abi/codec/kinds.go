@@ -0,0 +1,183 @@
+package codec
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// Cairo type names accepted in the `cairo` struct tag.
+const (
+	KindFelt            = "felt252"
+	KindBool            = "bool"
+	KindU8              = "u8"
+	KindU16             = "u16"
+	KindU32             = "u32"
+	KindU64             = "u64"
+	KindU128            = "u128"
+	KindU256            = "u256"
+	KindUsize           = "usize"
+	KindI8              = "i8"
+	KindI16             = "i16"
+	KindI32             = "i32"
+	KindI64             = "i64"
+	KindI128            = "i128"
+	KindContractAddress = "ContractAddress"
+	KindClassHash       = "ClassHash"
+	KindByteArray       = "ByteArray"
+	KindShortString     = "shortstring"
+)
+
+var (
+	feltType      = reflect.TypeOf(felt.Felt{})
+	feltPtrType   = reflect.TypeOf(&felt.Felt{})
+	bigIntType    = reflect.TypeOf(big.Int{})
+	bigIntPtrType = reflect.TypeOf(&big.Int{})
+	enumType      = reflect.TypeOf(Enum{})
+
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	optionType      = reflect.TypeOf((*cairoOption)(nil)).Elem()
+
+	halfPrime = new(big.Int).Rsh(utils.FieldPrime, 1)
+)
+
+// intBits returns the bit size and signedness of an integer Cairo type.
+//
+// Parameters:
+// - kind: the Cairo type name
+// Returns:
+// - int: the bit size, 0 if kind is not an integer type
+// - bool: true for signed types
+func intBits(kind string) (int, bool) {
+	switch kind {
+	case KindU8:
+		return 8, false
+	case KindU16:
+		return 16, false
+	case KindU32, KindUsize:
+		return 32, false
+	case KindU64:
+		return 64, false
+	case KindU128:
+		return 128, false
+	case KindU256:
+		return 256, false
+	case KindI8:
+		return 8, true
+	case KindI16:
+		return 16, true
+	case KindI32:
+		return 32, true
+	case KindI64:
+		return 64, true
+	case KindI128:
+		return 128, true
+	}
+	return 0, false
+}
+
+// goKind returns the Cairo type matching a Go integer kind.
+//
+// Parameters:
+// - k: the reflect kind
+// Returns:
+// - string: the Cairo type name
+func goKind(k reflect.Kind) string {
+	switch k {
+	case reflect.Uint8:
+		return KindU8
+	case reflect.Uint16:
+		return KindU16
+	case reflect.Uint32:
+		return KindU32
+	case reflect.Uint64, reflect.Uint, reflect.Uintptr:
+		return KindU64
+	case reflect.Int8:
+		return KindI8
+	case reflect.Int16:
+		return KindI16
+	case reflect.Int32:
+		return KindI32
+	case reflect.Int64, reflect.Int:
+		return KindI64
+	}
+	return ""
+}
+
+// checkRange checks that v fits in the given Cairo type.
+//
+// Parameters:
+// - v: the value to check
+// - kind: the Cairo type name, felt252 when empty
+// Returns:
+// - bool: true if v is a valid value of the type
+func checkRange(v *big.Int, kind string) bool {
+	bits, signed := intBits(kind)
+	if bits == 0 {
+		return v.Sign() >= 0 && v.Cmp(utils.FieldPrime) < 0
+	}
+	if !signed {
+		return v.Sign() >= 0 && v.BitLen() <= bits
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	return v.Cmp(new(big.Int).Neg(limit)) >= 0 && v.Cmp(limit) < 0
+}
+
+// parseTag returns the Cairo type of a struct field and whether it is skipped.
+//
+// Parameters:
+// - field: the struct field
+// Returns:
+// - string: the Cairo type name, empty when not specified
+// - bool: true if the field must be skipped
+func parseTag(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("cairo")
+	if !ok {
+		return "", false
+	}
+	tag = strings.TrimSpace(tag)
+	if tag == "-" {
+		return "", true
+	}
+	return tag, false
+}
+
+// isEnum returns whether the struct type embeds Enum.
+//
+// Parameters:
+// - t: the struct type
+// Returns:
+// - bool: true if t is an enum
+func isEnum(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous && f.Type == enumType {
+			return true
+		}
+	}
+	return false
+}
+
+// variants returns the indexes of the variant fields of an enum struct.
+//
+// Parameters:
+// - t: the enum struct type
+// Returns:
+// - []int: the field indexes of the variants, in declaration order
+func variants(t reflect.Type) []int {
+	var idx []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type == enumType || !f.IsExported() {
+			continue
+		}
+		if _, skip := parseTag(f); skip {
+			continue
+		}
+		idx = append(idx, i)
+	}
+	return idx
+}
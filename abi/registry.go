@@ -0,0 +1,135 @@
+package abi
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// standardABIs are the events of the standards pre-registered in the
+// registries of NewStandardRegistry, as emitted by the OpenZeppelin
+// components: ERC20 Transfer and Approval, with keys or, as emitted by
+// contracts before OpenZeppelin 0.8 and Cairo 0 tokens, all data, ERC721
+// Transfer and Approval, UDC ContractDeployed and Upgraded. A Cairo 0 ERC721
+// Transfer has the layout of an ERC20 Transfer with all data and decodes as
+// one, its value being the token ID.
+var standardABIs = []string{
+	`[
+  {"type": "event", "name": "openzeppelin::token::erc20::erc20::ERC20::Transfer", "kind": "struct", "members": [{"name": "from", "type": "core::starknet::contract_address::ContractAddress", "kind": "data"}, {"name": "to", "type": "core::starknet::contract_address::ContractAddress", "kind": "data"}, {"name": "value", "type": "core::integer::u256", "kind": "data"}]},
+  {"type": "event", "name": "openzeppelin::token::erc20::erc20::ERC20::Approval", "kind": "struct", "members": [{"name": "owner", "type": "core::starknet::contract_address::ContractAddress", "kind": "data"}, {"name": "spender", "type": "core::starknet::contract_address::ContractAddress", "kind": "data"}, {"name": "value", "type": "core::integer::u256", "kind": "data"}]}
+]`,
+	`[
+  {"type": "event", "name": "openzeppelin::token::erc721::erc721::ERC721Component::Transfer", "kind": "struct", "members": [{"name": "from", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"}, {"name": "to", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"}, {"name": "token_id", "type": "core::integer::u256", "kind": "key"}]},
+  {"type": "event", "name": "openzeppelin::token::erc721::erc721::ERC721Component::Approval", "kind": "struct", "members": [{"name": "owner", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"}, {"name": "approved", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"}, {"name": "token_id", "type": "core::integer::u256", "kind": "key"}]}
+]`,
+	`[
+  {"type": "event", "name": "openzeppelin::token::erc20::erc20::ERC20Component::Transfer", "kind": "struct", "members": [{"name": "from", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"}, {"name": "to", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"}, {"name": "value", "type": "core::integer::u256", "kind": "data"}]},
+  {"type": "event", "name": "openzeppelin::token::erc20::erc20::ERC20Component::Approval", "kind": "struct", "members": [{"name": "owner", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"}, {"name": "spender", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"}, {"name": "value", "type": "core::integer::u256", "kind": "data"}]},
+  {"type": "event", "name": "openzeppelin::presets::universal_deployer::UniversalDeployer::ContractDeployed", "kind": "struct", "members": [{"name": "address", "type": "core::starknet::contract_address::ContractAddress", "kind": "data"}, {"name": "deployer", "type": "core::starknet::contract_address::ContractAddress", "kind": "data"}, {"name": "not_from_zero", "type": "core::bool", "kind": "data"}, {"name": "class_hash", "type": "core::starknet::class_hash::ClassHash", "kind": "data"}, {"name": "calldata", "type": "core::array::Span::<core::felt252>", "kind": "data"}, {"name": "salt", "type": "core::felt252", "kind": "data"}]},
+  {"type": "event", "name": "openzeppelin::upgrades::upgradeable::UpgradeableComponent::Upgraded", "kind": "struct", "members": [{"name": "class_hash", "type": "core::starknet::class_hash::ClassHash", "kind": "data"}]}
+]`,
+}
+
+// DefaultRegistry is the registry of the standard events, used to decode the
+// events of the receipts of contracts whose ABI is not given. ABIs
+// registered in it decode the events of every contract.
+var DefaultRegistry = NewStandardRegistry()
+
+// registeredEvent is a struct event of a registered ABI.
+type registeredEvent struct {
+	abi   *ABI
+	event *Event
+}
+
+// Registry maps event selectors to the struct events of registered ABIs, to
+// decode events by their first key without the ABI of their emitter. Events
+// sharing a selector, as the ERC20 and ERC721 Transfer events, are told apart
+// by their layout, the events registered last being tried first. It is safe
+// for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	events map[felt.Felt][]registeredEvent
+}
+
+// NewRegistry creates an empty registry.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *Registry: the registry
+func NewRegistry() *Registry {
+	return &Registry{events: map[felt.Felt][]registeredEvent{}}
+}
+
+// NewStandardRegistry creates a registry of the standard events: ERC20
+// Transfer and Approval, ERC721 Transfer and Approval, UDC ContractDeployed
+// and Upgraded.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *Registry: the registry
+func NewStandardRegistry() *Registry {
+	r := NewRegistry()
+	for _, data := range standardABIs {
+		parsed, err := Parse([]byte(data))
+		if err != nil {
+			panic(fmt.Sprintf("abi: standard events: %v", err))
+		}
+		r.Register(parsed)
+	}
+	return r
+}
+
+// Register registers the struct events of an ABI.
+//
+// Parameters:
+// - a: the ABI
+// Returns:
+//
+//	none
+func (r *Registry) Register(a *ABI) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ev := range a.Events {
+		if ev.Kind == EntryStruct {
+			r.events[*ev.Selector] = append(r.events[*ev.Selector], registeredEvent{abi: a, event: ev})
+		}
+	}
+}
+
+// Decode deserializes an event by its first key into its members keyed by
+// name, as DecodeEvent does, with the events of the selector tried from the
+// most recently registered, the first the keys and data decode as winning.
+//
+// Parameters:
+// - keys: the keys of the event, the selector first
+// - data: the data of the event
+// Returns:
+// - *Event: the event
+// - map[string]any: the values of the members
+// - error: ErrUnknownEvent, or the error of the last event tried
+func (r *Registry) Decode(keys, data []*felt.Felt) (*Event, map[string]any, error) {
+	if len(keys) == 0 {
+		return nil, nil, fmt.Errorf("%w: no keys", ErrUnknownEvent)
+	}
+	r.mu.RLock()
+	candidates := r.events[*keys[0]]
+	r.mu.RUnlock()
+	if len(candidates) == 0 {
+		return nil, nil, fmt.Errorf("%w: selector %s", ErrUnknownEvent, keys[0])
+	}
+	var err error
+	for i := len(candidates) - 1; i >= 0; i-- {
+		var values map[string]any
+		if values, err = candidates[i].abi.decodeEvent(candidates[i].event, keys, data); err == nil {
+			return candidates[i].event, values, nil
+		}
+	}
+	return nil, nil, err
+}
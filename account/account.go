@@ -7,10 +7,12 @@ import (
 
 	"github.com/NethermindEth/juno/core/crypto"
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/contracts"
 	"github.com/xiang-xx/starknet.go/curve"
 	"github.com/xiang-xx/starknet.go/hash"
 	"github.com/xiang-xx/starknet.go/rpc"
 	"github.com/xiang-xx/starknet.go/utils"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -18,6 +20,7 @@ var (
 	ErrTxnTypeUnSupported    = errors.New("Unsupported transction type")
 	ErrTxnVersionUnSupported = errors.New("Unsupported transction version")
 	ErrFeltToBigInt          = errors.New("Felt to BigInt error")
+	ErrNoProvider            = errors.New("account has no provider")
 )
 
 var (
@@ -37,7 +40,7 @@ type AccountInterface interface {
 	SignDeployAccountTransaction(ctx context.Context, tx *rpc.DeployAccountTxn, precomputeAddress *felt.Felt) error
 	SignDeclareTransaction(ctx context.Context, tx *rpc.DeclareTxnV2) error
 	PrecomputeAddress(deployerAddress *felt.Felt, salt *felt.Felt, classHash *felt.Felt, constructorCalldata []*felt.Felt) (*felt.Felt, error)
-	WaitForTransactionReceipt(ctx context.Context, transactionHash *felt.Felt, pollInterval time.Duration) (*rpc.TransactionReceipt, error)
+	WaitForTransactionReceipt(ctx context.Context, transactionHash *felt.Felt, pollInterval time.Duration) (*rpc.Receipt, error)
 }
 
 var _ AccountInterface = &Account{}
@@ -50,6 +53,21 @@ type Account struct {
 	publicKey      string
 	CairoVersion   int
 	ks             Keystore
+	signer         Signer
+	tracer         trace.Tracer
+}
+
+// WithChainID sets the chain ID of the account, e.g. "SN_MAIN", instead of
+// asking it to the provider when the account is created.
+//
+// Parameters:
+// - chainID: the chain ID
+// Returns:
+// - Option: the option
+func WithChainID(chainID string) Option {
+	return func(account *Account) {
+		account.ChainId = new(felt.Felt).SetBytes([]byte(chainID))
+	}
 }
 
 // NewAccount creates a new Account instance.
@@ -59,10 +77,12 @@ type Account struct {
 // - accountAddress: is the account address of type *felt.Felt
 // - publicKey: is the public key of type string
 // - keystore: is the keystore of type Keystore
+// - cairoVersion: is the Cairo version of the account
+// - opts: are the options of the account, e.g. WithTracerProvider, or WithChainID to not ask the chain ID to the provider
 // It returns:
 // - *Account: a pointer to newly created Account
 // - error: an error if any
-func NewAccount(provider rpc.RpcProvider, accountAddress *felt.Felt, publicKey string, keystore Keystore, cairoVersion int) (*Account, error) {
+func NewAccount(provider rpc.RpcProvider, accountAddress *felt.Felt, publicKey string, keystore Keystore, cairoVersion int, opts ...Option) (*Account, error) {
 	account := &Account{
 		provider:       provider,
 		AccountAddress: accountAddress,
@@ -70,6 +90,15 @@ func NewAccount(provider rpc.RpcProvider, accountAddress *felt.Felt, publicKey s
 		ks:             keystore,
 		CairoVersion:   cairoVersion,
 	}
+	for _, opt := range opts {
+		opt(account)
+	}
+	if account.ChainId != nil {
+		return account, nil
+	}
+	if provider == nil {
+		return nil, ErrNoProvider
+	}
 
 	chainID, err := provider.ChainID(context.Background())
 	if err != nil {
@@ -80,6 +109,24 @@ func NewAccount(provider rpc.RpcProvider, accountAddress *felt.Felt, publicKey s
 	return account, nil
 }
 
+// NewOfflineAccount creates an Account without a provider, for cold wallets:
+// it hashes and signs transactions and typed data of the given chain without
+// any network access. The nonce of the transactions must be set explicitly,
+// the methods reading or writing the node failing with ErrNoProvider.
+//
+// Parameters:
+// - chainID: is the chain ID of the account, e.g. "SN_MAIN"
+// - accountAddress: is the account address of type *felt.Felt
+// - publicKey: is the public key of type string
+// - keystore: is the keystore of type Keystore
+// - cairoVersion: is the Cairo version of the account
+// It returns:
+// - *Account: a pointer to newly created Account
+func NewOfflineAccount(chainID string, accountAddress *felt.Felt, publicKey string, keystore Keystore, cairoVersion int) *Account {
+	account, _ := NewAccount(nil, accountAddress, publicKey, keystore, cairoVersion, WithChainID(chainID))
+	return account
+}
+
 // Sign signs the given felt message using the account's private key.
 //
 // Parameters:
@@ -89,6 +136,9 @@ func NewAccount(provider rpc.RpcProvider, accountAddress *felt.Felt, publicKey s
 // - []*felt.Felt: an array of signed felt messages
 // - error: an error, if any
 func (account *Account) Sign(ctx context.Context, msg *felt.Felt) ([]*felt.Felt, error) {
+	if account.signer != nil {
+		return account.signer.SignHash(ctx, msg)
+	}
 
 	msgBig := utils.FeltToBigInt(msg)
 
@@ -96,6 +146,10 @@ func (account *Account) Sign(ctx context.Context, msg *felt.Felt) ([]*felt.Felt,
 	if err != nil {
 		return nil, err
 	}
+	// keystores may be remote, reject malformed signatures before they are submitted
+	if err := curve.Curve.ValidateSignature(s1, s2); err != nil {
+		return nil, err
+	}
 	s1Felt := utils.BigIntToFelt(s1)
 	s2Felt := utils.BigIntToFelt(s2)
 
@@ -247,15 +301,20 @@ func (account *Account) TransactionHashDeployAccount(tx rpc.DeployAccountType, c
 //
 // Parameters:
 // - tx: The invoke transaction to calculate the hash for.
-//     The transaction can be of type InvokeTxnV0 or InvokeTxnV1.
+//     The transaction can be of type InvokeTxnV0, InvokeTxnV1 or InvokeTxnV3.
 //     For InvokeTxnV0:
 //         the function checks if all the required parameters are set and then computes the transaction hash using the provided data.
+//         V0 transactions are no longer accepted by the networks and are only hashed to verify old transactions.
 //     For InvokeTxnV1:
-//         the function performs similar checks and computes the transaction hash using the provided data.
+//         the nonce is hashed as a field of the transaction, after the chain ID, and is not part of the calldata.
+//         This is the hash of the transactions of BuildInvokeTxn and Execute, for Cairo 0 and Cairo 1 accounts alike,
+//         the Cairo version only changing the format of the calldata.
+//     For InvokeTxnV3:
+//         the function computes the Poseidon hash of SNIP-8.
 // Returns:
 // - *felt.Felt: The calculated transaction hash as a *felt.Felt
 // - error: an error, if any
-
+//
 // If the transaction type is unsupported, the function returns an error.
 func (account *Account) TransactionHashInvoke(tx rpc.InvokeTxnType) (*felt.Felt, error) {
 
@@ -523,11 +582,14 @@ func (account *Account) PrecomputeAddress(deployerAddress *felt.Felt, salt *felt
 // - transactionHash: The hash
 // - pollInterval: The poll interval as parameters
 // It returns:
-// - *rpc.TransactionReceipt: the transaction receipt
+// - *rpc.Receipt: the transaction receipt
 // - error: an error
-func (account *Account) WaitForTransactionReceipt(ctx context.Context, transactionHash *felt.Felt, pollInterval time.Duration) (*rpc.TransactionReceipt, error) {
+func (account *Account) WaitForTransactionReceipt(ctx context.Context, transactionHash *felt.Felt, pollInterval time.Duration) (_ *rpc.Receipt, err error) {
+	ctx, span := account.startSpan(ctx, "Account.WaitForTransactionReceipt")
+	defer func() { endSpan(span, transactionHash, err) }()
+
 	t := time.NewTicker(pollInterval)
-	for {
+	for polls := 1; ; polls++ {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
@@ -540,11 +602,44 @@ func (account *Account) WaitForTransactionReceipt(ctx context.Context, transacti
 					return nil, err
 				}
 			}
-			return &receipt, nil
+			span.SetAttributes(AttributePolls.Int(polls), AttributeExecutionStatus.String(string(receipt.ExecutionStatus())))
+			return receipt, nil
 		}
 	}
 }
 
+// SubscribeTransactionStatus watches the status of a transaction until it is accepted on L1 or rejected.
+//
+// Parameters:
+// - ctx: The context, cancelling the watch
+// - transactionHash: The hash of the transaction
+// Returns:
+// - <-chan rpc.TxnStatusUpdate: the status transitions
+// - error: an error if the subscription fails
+func (account *Account) SubscribeTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (<-chan rpc.TxnStatusUpdate, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	return account.provider.SubscribeTransactionStatus(ctx, transactionHash)
+}
+
+// StreamEvents delivers the events of a set of contracts from a block on, then follows the chain.
+//
+// Parameters:
+// - ctx: The context, cancelling the stream
+// - contracts: The contracts emitting the events, all contracts if empty
+// - keys: The accepted values of the keys
+// - fromBlock: The number of the first block
+// Returns:
+// - <-chan rpc.StreamedEvent: the events, the last one carrying the error ending the stream if any
+// - error: an error if the stream can not be started
+func (account *Account) StreamEvents(ctx context.Context, contracts []*felt.Felt, keys [][]*felt.Felt, fromBlock uint64) (<-chan rpc.StreamedEvent, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	return account.provider.StreamEvents(ctx, contracts, keys, fromBlock)
+}
+
 // AddInvokeTransaction generates an invoke transaction and adds it to the account's provider.
 //
 // Parameters:
@@ -554,6 +649,9 @@ func (account *Account) WaitForTransactionReceipt(ctx context.Context, transacti
 // - *rpc.AddInvokeTransactionResponse: The response for the AddInvokeTransactionResponse
 // - error: an error if any.
 func (account *Account) AddInvokeTransaction(ctx context.Context, invokeTx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.AddInvokeTransaction(ctx, invokeTx)
 }
 
@@ -566,6 +664,9 @@ func (account *Account) AddInvokeTransaction(ctx context.Context, invokeTx rpc.B
 // - *rpc.AddDeclareTransactionResponse: The response for adding a declare transaction
 // - error: an error, if any
 func (account *Account) AddDeclareTransaction(ctx context.Context, declareTransaction rpc.BroadcastDeclareTxnType) (*rpc.AddDeclareTransactionResponse, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.AddDeclareTransaction(ctx, declareTransaction)
 }
 
@@ -578,6 +679,9 @@ func (account *Account) AddDeclareTransaction(ctx context.Context, declareTransa
 // - *rpc.AddDeployAccountTransactionResponse: a pointer to rpc.AddDeployAccountTransactionResponse
 // - error: an error if any
 func (account *Account) AddDeployAccountTransaction(ctx context.Context, deployAccountTransaction rpc.BroadcastAddDeployTxnType) (*rpc.AddDeployAccountTransactionResponse, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.AddDeployAccountTransaction(ctx, deployAccountTransaction)
 }
 
@@ -589,6 +693,9 @@ func (account *Account) AddDeployAccountTransaction(ctx context.Context, deployA
 // - rpc.BlockHashAndNumberOutput: the block hash and number as an rpc.BlockHashAndNumberOutput object.
 // - error: an error if there was an issue retrieving the block hash and number.
 func (account *Account) BlockHashAndNumber(ctx context.Context) (*rpc.BlockHashAndNumberOutput, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.BlockHashAndNumber(ctx)
 }
 
@@ -600,6 +707,9 @@ func (account *Account) BlockHashAndNumber(ctx context.Context) (*rpc.BlockHashA
 // - uint64: the block number as a uint64
 // - error: an error encountered
 func (account *Account) BlockNumber(ctx context.Context) (uint64, error) {
+	if account.provider == nil {
+		return 0, ErrNoProvider
+	}
 	return account.provider.BlockNumber(ctx)
 }
 
@@ -612,6 +722,9 @@ func (account *Account) BlockNumber(ctx context.Context) (uint64, error) {
 // - uint64: the number of transactions in the block
 //   - error: an error, if any
 func (account *Account) BlockTransactionCount(ctx context.Context, blockID rpc.BlockID) (uint64, error) {
+	if account.provider == nil {
+		return 0, ErrNoProvider
+	}
 	return account.provider.BlockTransactionCount(ctx, blockID)
 }
 
@@ -621,9 +734,12 @@ func (account *Account) BlockTransactionCount(ctx context.Context, blockID rpc.B
 // - ctx: the context.Context object for the request.
 // - blockID: the rpc.BlockID object specifying the block to retrieve.
 // Returns:
-// - interface{}: an interface{} representing the retrieved block
+// - *rpc.BlockTxHashesResult: the retrieved block, accepted or pending
 // - error: an error if there was any issue retrieving the block
-func (account *Account) BlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (interface{}, error) {
+func (account *Account) BlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockTxHashesResult, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.BlockWithTxHashes(ctx, blockID)
 }
 
@@ -633,12 +749,31 @@ func (account *Account) BlockWithTxHashes(ctx context.Context, blockID rpc.Block
 // - ctx: The context.Context object for the function.
 // - blockID: The rpc.BlockID parameter for the function.
 // Returns:
-// - interface{}: An interface{}
+// - *rpc.BlockResult: the retrieved block, accepted or pending
 // - error: An error
-func (account *Account) BlockWithTxs(ctx context.Context, blockID rpc.BlockID) (interface{}, error) {
+func (account *Account) BlockWithTxs(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockResult, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.BlockWithTxs(ctx, blockID)
 }
 
+// BlockWithReceipts retrieves the specified block along with its transactions
+// and their receipts.
+//
+// Parameters:
+// - ctx: The context.Context object for the function.
+// - blockID: The rpc.BlockID parameter for the function.
+// Returns:
+// - *rpc.BlockWithReceiptsResult: the retrieved block, accepted or pending
+// - error: An error
+func (account *Account) BlockWithReceipts(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockWithReceiptsResult, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	return account.provider.BlockWithReceipts(ctx, blockID)
+}
+
 // Call is a function that performs a function call on an Account.
 //
 // Parameters:
@@ -649,9 +784,30 @@ func (account *Account) BlockWithTxs(ctx context.Context, blockID rpc.BlockID) (
 // - []*felt.Felt: a slice of *felt.Felt
 // - error: an error object.
 func (account *Account) Call(ctx context.Context, call rpc.FunctionCall, blockId rpc.BlockID) ([]*felt.Felt, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.Call(ctx, call, blockId)
 }
 
+// CallWithStateOverrides performs a function call on the state of a block
+// with overrides, on nodes supporting them.
+//
+// Parameters:
+// - ctx: The context.Context object for the function.
+// - call: The rpc.FunctionCall object representing the function call.
+// - blockID: The rpc.BlockID object representing the block ID.
+// - overrides: The overrides of the state of the block.
+// Returns:
+// - []*felt.Felt: a slice of *felt.Felt
+// - error: rpc.ErrStateOverridesNotSupported if the node rejects the overrides, or an error object.
+func (account *Account) CallWithStateOverrides(ctx context.Context, call rpc.FunctionCall, blockID rpc.BlockID, overrides []rpc.StateOverride) ([]*felt.Felt, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	return account.provider.CallWithStateOverrides(ctx, call, blockID, overrides)
+}
+
 // ChainID returns the chain ID associated with the account.
 //
 // Parameters:
@@ -660,6 +816,9 @@ func (account *Account) Call(ctx context.Context, call rpc.FunctionCall, blockId
 //   - string: the chain ID.
 //   - error: any error encountered while retrieving the chain ID.
 func (account *Account) ChainID(ctx context.Context) (string, error) {
+	if account.provider == nil {
+		return "", ErrNoProvider
+	}
 	return account.provider.ChainID(ctx)
 }
 
@@ -674,6 +833,9 @@ func (account *Account) ChainID(ctx context.Context) (string, error) {
 //     or just a Contract class depending on the contract version)
 //   - error: An error if any occurred.
 func (account *Account) Class(ctx context.Context, blockID rpc.BlockID, classHash *felt.Felt) (rpc.ClassOutput, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.Class(ctx, blockID, classHash)
 }
 
@@ -687,6 +849,9 @@ func (account *Account) Class(ctx context.Context, blockID rpc.BlockID, classHas
 //     or just a Contract class depending on the contract version)
 //   - error: An error if any occurred.
 func (account *Account) ClassAt(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (rpc.ClassOutput, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.ClassAt(ctx, blockID, contractAddress)
 }
 
@@ -700,19 +865,75 @@ func (account *Account) ClassAt(ctx context.Context, blockID rpc.BlockID, contra
 // - *felt.Felt: the class hash as a *felt.Felt
 // - error: an error if any occurred.
 func (account *Account) ClassHashAt(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.ClassHashAt(ctx, blockID, contractAddress)
 }
 
+// CompiledCasm returns the CASM class compiled from a Sierra class.
+//
+// Parameters:
+// - ctx: The context to use for the function call.
+// - classHash: The hash of the Sierra class.
+// Returns:
+// - *contracts.CasmClass: the CASM class
+// - error: an error if any occurred.
+func (account *Account) CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	return account.provider.CompiledCasm(ctx, classHash)
+}
+
+// FeeHistory returns the gas prices of the last n accepted blocks.
+//
+// Parameters:
+// - ctx: The context to use for the function call.
+// - n: The number of blocks.
+// Returns:
+// - rpc.FeeHistory: the gas prices of the blocks, the latest block last
+// - error: an error if any occurred.
+func (account *Account) FeeHistory(ctx context.Context, n int) (rpc.FeeHistory, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	return account.provider.FeeHistory(ctx, n)
+}
+
+// GasPrices returns the gas prices of a block.
+//
+// Parameters:
+// - ctx: The context to use for the function call.
+// - blockID: The ID of the block.
+// Returns:
+// - *rpc.BlockGasPrices: the gas prices of the block
+// - error: an error if any occurred.
+func (account *Account) GasPrices(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockGasPrices, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	return account.provider.GasPrices(ctx, blockID)
+}
+
 // EstimateFee estimates the fee for a set of requests in the given block ID.
+// With rpc.SKIP_VALIDATE, the fee of transactions of accounts not deployed yet
+// or with placeholder signatures is estimated.
 //
 // Parameters:
 // - ctx: The context.Context object for the function.
 // - requests: An array of rpc.BroadcastTxn objects representing the requests to estimate the fee for.
+// - simulationFlags: The simulation flags, none if nil
 // - blockID: The rpc.BlockID object representing the block ID for which to estimate the fee.
 // Returns:
 // - []rpc.FeeEstimate: An array of rpc.FeeEstimate objects representing the estimated fees.
 // - error: An error object if any error occurred during the estimation process.
-func (account *Account) EstimateFee(ctx context.Context, requests []rpc.BroadcastTxn, simulationFlags []rpc.SimulationFlag, blockID rpc.BlockID) ([]rpc.FeeEstimate, error) {
+func (account *Account) EstimateFee(ctx context.Context, requests []rpc.BroadcastTxn, simulationFlags []rpc.SimulationFlag, blockID rpc.BlockID) (_ []rpc.FeeEstimate, err error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	ctx, span := account.startSpan(ctx, "Account.EstimateFee", blockIDAttribute(blockID))
+	defer func() { endSpan(span, nil, err) }()
 	return account.provider.EstimateFee(ctx, requests, simulationFlags, blockID)
 }
 
@@ -726,6 +947,9 @@ func (account *Account) EstimateFee(ctx context.Context, requests []rpc.Broadcas
 // - *rpc.FeeEstimate: a pointer to rpc.FeeEstimate
 // - error: an error if any.
 func (account *Account) EstimateMessageFee(ctx context.Context, msg rpc.MsgFromL1, blockID rpc.BlockID) (*rpc.FeeEstimate, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.EstimateMessageFee(ctx, msg, blockID)
 }
 
@@ -738,10 +962,16 @@ func (account *Account) EstimateMessageFee(ctx context.Context, msg rpc.MsgFromL
 // - *rpc.EventChunk: the chunk of events retrieved.
 // - error: an error if the retrieval fails.
 func (account *Account) Events(ctx context.Context, input rpc.EventsInput) (*rpc.EventChunk, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.Events(ctx, input)
 }
 
-// Nonce retrieves the nonce for a given block ID and contract address.
+// Nonce retrieves the nonce for a given block ID and contract address, with
+// starknet_getNonce. If the node fails to return it for a Cairo 0 contract,
+// detected from the class at the address, the nonce is read with the legacy
+// get_nonce entrypoint of the contract instead.
 //
 // Parameters:
 // - ctx: is the context.Context for the function call
@@ -751,22 +981,67 @@ func (account *Account) Events(ctx context.Context, input rpc.EventsInput) (*rpc
 // - *felt.Felt: the contract's nonce at the requested state
 // - error: an error if any
 func (account *Account) Nonce(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
-	return account.provider.Nonce(ctx, blockID, contractAddress)
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	nonce, err := account.provider.Nonce(ctx, blockID, contractAddress)
+	if err == nil || errors.Is(err, rpc.ErrContractNotFound) || errors.Is(err, rpc.ErrBlockNotFound) || ctx.Err() != nil {
+		return nonce, err
+	}
+	class, classErr := account.provider.ClassAt(ctx, blockID, contractAddress)
+	if _, cairo0 := class.(*rpc.DeprecatedContractClass); classErr != nil || !cairo0 {
+		return nil, err
+	}
+	result, callErr := account.provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    contractAddress,
+		EntryPointSelector: utils.GetSelectorFromNameCached("get_nonce"),
+		Calldata:           []*felt.Felt{},
+	}, blockID)
+	if callErr != nil || len(result) != 1 {
+		return nil, err
+	}
+	return result[0], nil
 }
 
-// SimulateTransactions simulates transactions using the provided context
+// SimulateTransactions simulates transactions using the provided context.
+// With rpc.SKIP_VALIDATE, transactions of accounts not deployed yet or with
+// placeholder signatures are simulated, and with rpc.SKIP_FEE_CHARGE
+// transactions of accounts without funds.
+//
 // Parameters:
 // - ctx: The context.Context object
 // - blockID: The rpc.BlockID object for the block referencing the state or call the transactions are on
 // - txns: The slice of rpc.Transaction objects representing the transactions to simulate
-// - simulationFlags: The slice of rpc.simulationFlags
+// - simulationFlags: The slice of rpc.simulationFlags, none if nil
 // Returns:
 // - []rpc.SimulatedTransaction: a list of simulated transactions
 // - error: an error, if any.
 func (account *Account) SimulateTransactions(ctx context.Context, blockID rpc.BlockID, txns []rpc.Transaction, simulationFlags []rpc.SimulationFlag) ([]rpc.SimulatedTransaction, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.SimulateTransactions(ctx, blockID, txns, simulationFlags)
 }
 
+// SimulateTransactionsWithStateOverrides simulates transactions on the state
+// of a block with overrides, on nodes supporting them.
+//
+// Parameters:
+// - ctx: The context.Context object for the function.
+// - blockID: The rpc.BlockID object for the function.
+// - txns: The slice of rpc.Transaction objects to simulate.
+// - simulationFlags: The slice of rpc.SimulationFlag objects, nil for none.
+// - overrides: The overrides of the state of the block.
+// Returns:
+// - []rpc.SimulatedTransaction: a list of simulated transactions.
+// - error: rpc.ErrStateOverridesNotSupported if the node rejects the overrides, or an error, if any.
+func (account *Account) SimulateTransactionsWithStateOverrides(ctx context.Context, blockID rpc.BlockID, txns []rpc.Transaction, simulationFlags []rpc.SimulationFlag, overrides []rpc.StateOverride) ([]rpc.SimulatedTransaction, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	return account.provider.SimulateTransactionsWithStateOverrides(ctx, blockID, txns, simulationFlags, overrides)
+}
+
 // StorageAt is a function that retrieves the storage value at the given key for a contract address.
 //
 // Parameters:
@@ -778,9 +1053,44 @@ func (account *Account) SimulateTransactions(ctx context.Context, blockID rpc.Bl
 // - string: The storage value at the given key.
 // - error: An error if the retrieval fails.
 func (account *Account) StorageAt(ctx context.Context, contractAddress *felt.Felt, key string, blockID rpc.BlockID) (string, error) {
+	if account.provider == nil {
+		return "", ErrNoProvider
+	}
 	return account.provider.StorageAt(ctx, contractAddress, key, blockID)
 }
 
+// StorageAtKeys retrieves the storage values at several storage addresses of a contract.
+//
+// Parameters:
+// - ctx: The context.Context object for the function
+// - contractAddress: The contract address for which to retrieve the storage values
+// - keys: The storage addresses to read
+// - blockID: The block ID at which to retrieve the storage values
+// Returns:
+// - map[felt.Felt]*felt.Felt: The storage values, by storage address
+// - error: An error if the retrieval fails.
+func (account *Account) StorageAtKeys(ctx context.Context, contractAddress *felt.Felt, keys []*felt.Felt, blockID rpc.BlockID) (map[felt.Felt]*felt.Felt, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	return account.provider.StorageAtKeys(ctx, contractAddress, keys, blockID)
+}
+
+// StorageProof retrieves the Merkle proofs of classes, contracts and storage keys in the state of a block.
+//
+// Parameters:
+// - ctx: The context.Context object for the function
+// - input: The block and the classes, contracts and storage keys to prove
+// Returns:
+// - *rpc.StorageProofResult: The proofs
+// - error: An error if the retrieval fails.
+func (account *Account) StorageProof(ctx context.Context, input rpc.StorageProofInput) (*rpc.StorageProofResult, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	return account.provider.StorageProof(ctx, input)
+}
+
 // StateUpdate updates the state of the Account.
 //
 // Parameters:
@@ -790,6 +1100,9 @@ func (account *Account) StorageAt(ctx context.Context, contractAddress *felt.Fel
 // - *rpc.StateUpdateOutput: a *rpc.StateUpdateOutput
 // - error: an error
 func (account *Account) StateUpdate(ctx context.Context, blockID rpc.BlockID) (*rpc.StateUpdateOutput, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.StateUpdate(ctx, blockID)
 }
 
@@ -802,6 +1115,9 @@ func (account *Account) StateUpdate(ctx context.Context, blockID rpc.BlockID) (*
 // - string: The spec version
 // - error: An error if any
 func (account *Account) SpecVersion(ctx context.Context) (string, error) {
+	if account.provider == nil {
+		return "", ErrNoProvider
+	}
 	return account.provider.SpecVersion(ctx)
 }
 
@@ -813,6 +1129,9 @@ func (account *Account) SpecVersion(ctx context.Context) (string, error) {
 // - *rpc.SyncStatus: *rpc.SyncStatus
 // - error: an error.
 func (account *Account) Syncing(ctx context.Context) (*rpc.SyncStatus, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.Syncing(ctx)
 }
 
@@ -825,6 +1144,9 @@ func (account *Account) Syncing(ctx context.Context) (*rpc.SyncStatus, error) {
 // - []rpc.Trace: The list of trace transactions for the given block.
 // - error: An error if there was a problem retrieving the trace transactions.
 func (account *Account) TraceBlockTransactions(ctx context.Context, blockID rpc.BlockID) ([]rpc.Trace, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.TraceBlockTransactions(ctx, blockID)
 }
 
@@ -834,8 +1156,12 @@ func (account *Account) TraceBlockTransactions(ctx context.Context, blockID rpc.
 // - ctx: The context to use for the request.
 // - transactionHash: The hash of the transaction.
 // Returns:
-// - rpc.Transactiontype: rpc.TransactionReceipt, error.
-func (account *Account) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (rpc.TransactionReceipt, error) {
+// - *rpc.Receipt: the transaction receipt
+// - error: an error if any
+func (account *Account) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*rpc.Receipt, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.TransactionReceipt(ctx, transactionHash)
 }
 
@@ -847,6 +1173,9 @@ func (account *Account) TransactionReceipt(ctx context.Context, transactionHash
 // Returns:
 // - rpc.TxnTrace: The rpc.TxnTrace object representing the transaction trace, and an error if any.
 func (account *Account) TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (rpc.TxnTrace, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.TraceTransaction(ctx, transactionHash)
 }
 
@@ -859,6 +1188,9 @@ func (account *Account) TraceTransaction(ctx context.Context, transactionHash *f
 // Returns:
 // - rpc.Transaction: The transaction and an error, if any.
 func (account *Account) TransactionByBlockIdAndIndex(ctx context.Context, blockID rpc.BlockID, index uint64) (rpc.Transaction, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.TransactionByBlockIdAndIndex(ctx, blockID, index)
 }
 
@@ -871,6 +1203,9 @@ func (account *Account) TransactionByBlockIdAndIndex(ctx context.Context, blockI
 // - rpc.Transaction
 // - error
 func (account *Account) TransactionByHash(ctx context.Context, hash *felt.Felt) (rpc.Transaction, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.TransactionByHash(ctx, hash)
 }
 
@@ -883,9 +1218,28 @@ func (account *Account) TransactionByHash(ctx context.Context, hash *felt.Felt)
 // - *rpc.TxnStatusResp: the transaction status
 // - error: anerror if any
 func (account *Account) GetTransactionStatus(ctx context.Context, Txnhash *felt.Felt) (*rpc.TxnStatusResp, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
 	return account.provider.GetTransactionStatus(ctx, Txnhash)
 }
 
+// MessagesStatus returns the status of the l1_handler transactions executing
+// the messages sent by an L1 transaction.
+//
+// Parameters:
+// - ctx: The context.Context
+// - l1TransactionHash: The hash of the L1 transaction
+// Returns:
+// - []rpc.MessageStatus: the status of each message
+// - error: an error if any
+func (account *Account) MessagesStatus(ctx context.Context, l1TransactionHash rpc.NumAsHex) ([]rpc.MessageStatus, error) {
+	if account.provider == nil {
+		return nil, ErrNoProvider
+	}
+	return account.provider.MessagesStatus(ctx, l1TransactionHash)
+}
+
 // FmtCalldata generates the formatted calldata for the given function calls and Cairo version.
 //
 // Parameters:
@@ -0,0 +1,201 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// newMockAccount creates the account 0xacc on SN_SEPOLIA backed by a mock
+// provider, with a random key.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// - cairoVersion: the Cairo version of the account
+// - opts: the options of the account, applied after the chain ID
+// Returns:
+// - *Account: the account
+// - *mocks.MockRpcProvider: the provider of the account
+func newMockAccount(t *testing.T, cairoVersion int, opts ...Option) (*Account, *mocks.MockRpcProvider) {
+	provider := mocks.NewMockRpcProvider(gomock.NewController(t))
+	ks, pub, _ := GetRandomKeys()
+	acc, err := NewAccount(provider, utils.TestHexToFelt(t, "0xacc"), pub.String(), ks, cairoVersion, append([]Option{WithChainID("SN_SEPOLIA")}, opts...)...)
+	require.NoError(t, err)
+	return acc, provider
+}
+
+// TestOfflineAccount tests that an account without provider hashes and signs
+// invoke, declare and deploy account transactions as an account asking the
+// chain ID to its provider, and that an account needs one of the two.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestOfflineAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	provider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+	ks, pub, _ := GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0xacc")
+	online, err := NewAccount(provider, address, pub.String(), ks, 2)
+	require.NoError(t, err)
+	offline := NewOfflineAccount("SN_SEPOLIA", address, pub.String(), ks, 2)
+	require.Equal(t, online.ChainId, offline.ChainId)
+
+	_, err = NewAccount(nil, address, pub.String(), ks, 2)
+	require.True(t, errors.Is(err, ErrNoProvider))
+
+	calls := []rpc.FunctionCall{{ContractAddress: utils.TestHexToFelt(t, "0x10"), EntryPointSelector: utils.SelectorTransfer, Calldata: []*felt.Felt{}}}
+	txV1, err := offline.BuildInvokeTxn(context.Background(), calls, utils.Uint64ToFelt(1), utils.Uint64ToFelt(100))
+	require.NoError(t, err)
+	require.Len(t, txV1.Signature, 2)
+	onlineHash, err := online.TransactionHashInvoke(*txV1)
+	require.NoError(t, err)
+	offlineHash, err := offline.TransactionHashInvoke(*txV1)
+	require.NoError(t, err)
+	require.Equal(t, onlineHash, offlineHash)
+
+	txV3 := rpc.InvokeTxnV3{
+		Type:                  rpc.TransactionType_Invoke,
+		SenderAddress:         address,
+		Calldata:              txV1.Calldata,
+		Version:               rpc.TransactionV3,
+		Nonce:                 utils.Uint64ToFelt(1),
+		ResourceBounds:        rpc.ResourceBoundsMapping{L1Gas: rpc.ResourceBounds{MaxAmount: "0x100", MaxPricePerUnit: "0x10"}, L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"}},
+		Tip:                   "0x0",
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+	}
+	onlineHash, err = online.TransactionHashInvoke(txV3)
+	require.NoError(t, err)
+	offlineHash, err = offline.TransactionHashInvoke(txV3)
+	require.NoError(t, err)
+	require.Equal(t, onlineHash, offlineHash)
+
+	declare := &rpc.DeclareTxnV2{
+		Type:              rpc.TransactionType_Declare,
+		SenderAddress:     address,
+		CompiledClassHash: utils.Uint64ToFelt(2),
+		MaxFee:            utils.Uint64ToFelt(100),
+		Version:           rpc.TransactionV2,
+		Nonce:             utils.Uint64ToFelt(1),
+		ClassHash:         utils.Uint64ToFelt(3),
+	}
+	require.NoError(t, offline.SignDeclareTransaction(context.Background(), declare))
+	require.Len(t, declare.Signature, 2)
+
+	deploy := &rpc.DeployAccountTxn{
+		Type:                rpc.TransactionType_DeployAccount,
+		MaxFee:              utils.Uint64ToFelt(100),
+		Version:             rpc.TransactionV1,
+		Nonce:               &felt.Zero,
+		ContractAddressSalt: pub,
+		ConstructorCalldata: []*felt.Felt{pub},
+		ClassHash:           utils.Uint64ToFelt(3),
+	}
+	precomputed, err := offline.PrecomputeAddress(&felt.Zero, pub, deploy.ClassHash, deploy.ConstructorCalldata)
+	require.NoError(t, err)
+	require.NoError(t, offline.SignDeployAccountTransaction(context.Background(), deploy, precomputed))
+	require.Len(t, deploy.Signature, 2)
+
+	// the methods reading or writing the node fail instead of panicking
+	_, err = offline.Nonce(context.Background(), rpc.WithBlockTag("pending"), address)
+	require.True(t, errors.Is(err, ErrNoProvider))
+	_, err = offline.Call(context.Background(), calls[0], rpc.WithBlockTag("latest"))
+	require.True(t, errors.Is(err, ErrNoProvider))
+	_, err = offline.EstimateFee(context.Background(), nil, nil, rpc.WithBlockTag("pending"))
+	require.True(t, errors.Is(err, ErrNoProvider))
+	_, err = offline.Execute(context.Background(), calls)
+	require.True(t, errors.Is(err, ErrNoProvider))
+	_, err = offline.WaitForTransactionReceipt(context.Background(), utils.Uint64ToFelt(1), time.Millisecond)
+	require.True(t, errors.Is(err, ErrNoProvider))
+}
+
+// TestNonce_Cairo0Fallback tests that Nonce falls back to the get_nonce
+// entrypoint only for Cairo 0 contracts when starknet_getNonce fails.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestNonce_Cairo0Fallback(t *testing.T) {
+	acc, provider := newMockAccount(t, 0)
+	latest := rpc.WithBlockTag("latest")
+	legacy, modern, missing := utils.TestHexToFelt(t, "0x1"), utils.TestHexToFelt(t, "0x2"), utils.TestHexToFelt(t, "0x3")
+	failure := errors.New("method not supported")
+
+	provider.EXPECT().Nonce(gomock.Any(), latest, missing).Return(nil, rpc.ErrContractNotFound)
+	_, err := acc.Nonce(context.Background(), latest, missing)
+	require.Equal(t, rpc.ErrContractNotFound, err)
+
+	provider.EXPECT().Nonce(gomock.Any(), latest, legacy).Return(nil, failure)
+	provider.EXPECT().ClassAt(gomock.Any(), latest, legacy).Return(&rpc.DeprecatedContractClass{}, nil)
+	provider.EXPECT().Call(gomock.Any(), rpc.FunctionCall{
+		ContractAddress:    legacy,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("get_nonce"),
+		Calldata:           []*felt.Felt{},
+	}, latest).Return([]*felt.Felt{utils.Uint64ToFelt(7)}, nil)
+	nonce, err := acc.Nonce(context.Background(), latest, legacy)
+	require.NoError(t, err)
+	require.Equal(t, utils.Uint64ToFelt(7), nonce)
+
+	provider.EXPECT().Nonce(gomock.Any(), latest, modern).Return(nil, failure)
+	provider.EXPECT().ClassAt(gomock.Any(), latest, modern).Return(&rpc.ContractClass{}, nil)
+	_, err = acc.Nonce(context.Background(), latest, modern)
+	require.Equal(t, failure, err)
+}
+
+// TestTransactionHashInvoke_V1 tests that the V1 invoke transactions of
+// Cairo 0 and Cairo 1 accounts hash the nonce as a field of the transaction,
+// without appending it to the calldata.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestTransactionHashInvoke_V1(t *testing.T) {
+	ks, pub, _ := GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0xacc")
+	nonce, maxFee := utils.Uint64ToFelt(7), utils.Uint64ToFelt(100)
+	calls := []rpc.FunctionCall{{ContractAddress: utils.TestHexToFelt(t, "0x10"), EntryPointSelector: utils.SelectorTransfer, Calldata: []*felt.Felt{utils.Uint64ToFelt(1)}}}
+	for cairoVersion, calldata := range map[int][]*felt.Felt{0: FmtCallDataCairo0(calls), 2: FmtCallDataCairo2(calls)} {
+		acc := NewOfflineAccount("SN_SEPOLIA", address, pub.String(), ks, cairoVersion)
+		tx, err := acc.BuildInvokeTxn(context.Background(), calls, nonce, maxFee)
+		require.NoError(t, err)
+		require.Equal(t, rpc.TransactionV1, tx.Version)
+		require.Equal(t, calldata, tx.Calldata)
+
+		calldataHash, err := hash.ComputeHashOnElementsFelt(calldata)
+		require.NoError(t, err)
+		expected, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{PREFIX_TRANSACTION, utils.Uint64ToFelt(1), address, &felt.Zero, calldataHash, maxFee, acc.ChainId, nonce})
+		require.NoError(t, err)
+		txHash, err := acc.TransactionHashInvoke(*tx)
+		require.NoError(t, err)
+		require.Equal(t, expected, txHash)
+
+		v0Hash, err := acc.TransactionHashInvoke(rpc.InvokeTxnV0{
+			Type:         rpc.TransactionType_Invoke,
+			MaxFee:       maxFee,
+			Version:      rpc.TransactionV0,
+			Signature:    tx.Signature,
+			FunctionCall: rpc.FunctionCall{ContractAddress: address, EntryPointSelector: utils.GetSelectorFromNameFelt("__execute__"), Calldata: calldata},
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, txHash, v0Hash)
+	}
+}
@@ -0,0 +1,328 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/contracts"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+type declareOptions struct {
+	maxFee        *felt.Felt
+	nonce         *felt.Felt
+	feeMultiplier float64
+	support       *contracts.ChainSupport
+	scarbVersion  string
+	blockID       rpc.BlockID
+	limits        contracts.ClassLimits
+	flags         []rpc.SimulationFlag
+}
+
+// DeclareOption configures Account.Declare.
+type DeclareOption func(*declareOptions)
+
+// WithDeclareMaxFee sets the max fee of the declaration instead of estimating it.
+//
+// Parameters:
+// - maxFee: the max fee
+// Returns:
+// - DeclareOption: the option
+func WithDeclareMaxFee(maxFee *felt.Felt) DeclareOption {
+	return func(o *declareOptions) {
+		o.maxFee = maxFee
+	}
+}
+
+// WithDeclareNonce sets the nonce of the declaration instead of reading it
+// from the provider.
+//
+// Parameters:
+// - nonce: the nonce
+// Returns:
+// - DeclareOption: the option
+func WithDeclareNonce(nonce *felt.Felt) DeclareOption {
+	return func(o *declareOptions) {
+		o.nonce = nonce
+	}
+}
+
+// WithDeclareBlockID sets the block the nonce is read from and the fee is
+// estimated against, instead of the pending block.
+//
+// Parameters:
+// - blockID: the block
+// Returns:
+// - DeclareOption: the option
+func WithDeclareBlockID(blockID rpc.BlockID) DeclareOption {
+	return func(o *declareOptions) {
+		o.blockID = blockID
+	}
+}
+
+// WithDeclareSimulationFlags sets the flags of the fee estimation of the
+// declaration, e.g. rpc.SKIP_VALIDATE.
+//
+// Parameters:
+// - flags: the simulation flags
+// Returns:
+// - DeclareOption: the option
+func WithDeclareSimulationFlags(flags ...rpc.SimulationFlag) DeclareOption {
+	return func(o *declareOptions) {
+		o.flags = flags
+	}
+}
+
+// WithChainSupport sets the versions accepted by the chain instead of
+// looking them up from the Starknet version of the latest block, e.g. for
+// appchains or versions missing from contracts.SupportedVersions.
+//
+// Parameters:
+// - support: the accepted versions
+// Returns:
+// - DeclareOption: the option
+func WithChainSupport(support contracts.ChainSupport) DeclareOption {
+	return func(o *declareOptions) {
+		o.support = &support
+	}
+}
+
+// WithClassLimits sets the size limits the class is checked against instead
+// of contracts.DefaultClassLimits, e.g. for appchains with other limits.
+//
+// Parameters:
+// - limits: the limits, zero for no checks
+// Returns:
+// - DeclareOption: the option
+func WithClassLimits(limits contracts.ClassLimits) DeclareOption {
+	return func(o *declareOptions) {
+		o.limits = limits
+	}
+}
+
+// WithScarbVersion records the version of Scarb the class was built with in
+// the metadata of the declaration.
+//
+// Parameters:
+// - version: the Scarb version
+// Returns:
+// - DeclareOption: the option
+func WithScarbVersion(version string) DeclareOption {
+	return func(o *declareOptions) {
+		o.scarbVersion = version
+	}
+}
+
+// DeclareResponse is the response of Account.Declare.
+type DeclareResponse struct {
+	rpc.AddDeclareTransactionResponse
+	// Metadata are the versions the class was built with
+	Metadata *contracts.ClassMetadata
+}
+
+// ChainSupport returns the versions of the classes accepted by the chain,
+// from the Starknet version of the latest block.
+//
+// Parameters:
+// - ctx: the context
+// Returns:
+// - contracts.ChainSupport: the accepted versions
+// - error: an error if the block can not be read or its Starknet version is unknown
+func (account *Account) ChainSupport(ctx context.Context) (contracts.ChainSupport, error) {
+	block, err := account.BlockWithTxHashes(ctx, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return contracts.ChainSupport{}, err
+	}
+	return contracts.SupportFor(block.Header().StarknetVersion)
+}
+
+// Declare declares a Sierra class in a V2 declare transaction.
+//
+// The versions the class was built with are checked against the versions
+// accepted by the chain, and its size against the limits of the chain, before
+// anything is signed, so that a class built with a too recent compiler fails
+// with contracts.ErrUnsupportedSierraVersion, and a class too large with
+// contracts.ErrClassTooLarge, instead of a rejection by the node. Unless set
+// with options, the accepted versions are looked up with ChainSupport, the
+// limits are contracts.DefaultClassLimits, the nonce is read from the pending
+// block and the max fee is the estimated fee multiplied by
+// DefaultFeeMultiplier.
+//
+// Parameters:
+// - ctx: the context
+// - class: the Sierra class
+// - casm: the CASM class compiled from it
+// - opts: the declaration options
+// Returns:
+// - *DeclareResponse: the response of the provider and the metadata of the class
+// - error: an error if the versions or the size are not accepted, or the fee estimation, the signature or the submission fails
+func (account *Account) Declare(ctx context.Context, class rpc.ContractClass, casm contracts.CasmClass, opts ...DeclareOption) (*DeclareResponse, error) {
+	options := newDeclareOptions(opts)
+	tx, metadata, err := account.prepareDeclare(ctx, class, casm, &options)
+	if err != nil {
+		return nil, err
+	}
+	if tx.MaxFee == nil {
+		estimate, err := account.estimateDeclare(ctx, tx, class, options.blockID, options.flags)
+		if err != nil {
+			return nil, err
+		}
+		if tx.MaxFee, err = estimate.MaxFee(options.feeMultiplier); err != nil {
+			return nil, err
+		}
+	}
+	if err := account.SignDeclareTransaction(ctx, &tx); err != nil {
+		return nil, err
+	}
+
+	resp, err := account.AddDeclareTransaction(ctx, broadcastDeclare(tx, class))
+	if err != nil {
+		return nil, err
+	}
+	return &DeclareResponse{AddDeclareTransactionResponse: *resp, Metadata: metadata}, nil
+}
+
+// EstimateDeclareFee estimates the fee of the V2 declare transaction of a
+// Sierra class, after the checks of Declare, signed with the query version.
+// The max fee options are ignored.
+//
+// Parameters:
+// - ctx: the context
+// - class: the Sierra class
+// - casm: the CASM class compiled from it
+// - opts: the declaration options
+// Returns:
+// - *rpc.FeeEstimate: the estimated fee
+// - error: an error if the versions or the size are not accepted, or the fee estimation fails
+func (account *Account) EstimateDeclareFee(ctx context.Context, class rpc.ContractClass, casm contracts.CasmClass, opts ...DeclareOption) (*rpc.FeeEstimate, error) {
+	options := newDeclareOptions(opts)
+	options.maxFee = nil
+	tx, _, err := account.prepareDeclare(ctx, class, casm, &options)
+	if err != nil {
+		return nil, err
+	}
+	return account.estimateDeclare(ctx, tx, class, options.blockID, options.flags)
+}
+
+// newDeclareOptions applies declaration options to the defaults.
+//
+// Parameters:
+// - opts: the declaration options
+// Returns:
+// - declareOptions: the options
+func newDeclareOptions(opts []DeclareOption) declareOptions {
+	options := declareOptions{feeMultiplier: DefaultFeeMultiplier, blockID: rpc.WithBlockTag("pending"), limits: contracts.DefaultClassLimits}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// prepareDeclare checks a class against the versions and the limits of the
+// chain, and builds its unsigned declare transaction.
+//
+// Parameters:
+// - ctx: the context
+// - class: the Sierra class
+// - casm: the CASM class compiled from it
+// - options: the declaration options, whose chain support is looked up if unset
+// Returns:
+// - rpc.DeclareTxnV2: the transaction, with the max fee of the options
+// - *contracts.ClassMetadata: the metadata of the class
+// - error: an error if the checks fail or the nonce can not be read
+func (account *Account) prepareDeclare(ctx context.Context, class rpc.ContractClass, casm contracts.CasmClass, options *declareOptions) (rpc.DeclareTxnV2, *contracts.ClassMetadata, error) {
+	metadata, err := contracts.ReadMetadata(class.SierraProgram, &casm)
+	if err != nil {
+		return rpc.DeclareTxnV2{}, nil, err
+	}
+	metadata.ScarbVersion = options.scarbVersion
+	if options.support == nil {
+		support, err := account.ChainSupport(ctx)
+		if err != nil {
+			return rpc.DeclareTxnV2{}, nil, err
+		}
+		options.support = &support
+	}
+	if err := metadata.Check(*options.support); err != nil {
+		return rpc.DeclareTxnV2{}, nil, err
+	}
+	encoded, err := json.Marshal(class)
+	if err != nil {
+		return rpc.DeclareTxnV2{}, nil, err
+	}
+	if err := options.limits.Check(class.SierraProgram, len(encoded), &casm); err != nil {
+		return rpc.DeclareTxnV2{}, nil, err
+	}
+
+	classHash, err := hash.ClassHash(class)
+	if err != nil {
+		return rpc.DeclareTxnV2{}, nil, err
+	}
+	nonce := options.nonce
+	if nonce == nil {
+		nonce, err = account.Nonce(ctx, options.blockID, account.AccountAddress)
+		if err != nil {
+			return rpc.DeclareTxnV2{}, nil, err
+		}
+	}
+	return rpc.DeclareTxnV2{
+		Type:              rpc.TransactionType_Declare,
+		SenderAddress:     account.AccountAddress,
+		CompiledClassHash: hash.CompiledClassHash(casm),
+		MaxFee:            options.maxFee,
+		Version:           rpc.TransactionV2,
+		Nonce:             nonce,
+		ClassHash:         classHash,
+	}, metadata, nil
+}
+
+// estimateDeclare signs a declare transaction with a zero max fee and the
+// query version, and estimates its fee.
+//
+// Parameters:
+// - ctx: the context
+// - tx: the unsigned transaction
+// - class: the Sierra class
+// - blockID: the block the fee is estimated on
+// - flags: the simulation flags
+// Returns:
+// - *rpc.FeeEstimate: the estimated fee
+// - error: an error if the signature or the estimation fails
+func (account *Account) estimateDeclare(ctx context.Context, tx rpc.DeclareTxnV2, class rpc.ContractClass, blockID rpc.BlockID, flags []rpc.SimulationFlag) (*rpc.FeeEstimate, error) {
+	tx.MaxFee = new(felt.Felt)
+	tx.Version = rpc.TransactionV2WithQueryBit
+	if err := account.SignDeclareTransaction(ctx, &tx); err != nil {
+		return nil, err
+	}
+	estimates, err := account.EstimateFee(ctx, []rpc.BroadcastTxn{broadcastDeclare(tx, class)}, estimateFlags(flags), blockID)
+	if err != nil {
+		return nil, err
+	}
+	if len(estimates) != 1 {
+		return nil, fmt.Errorf("fee estimation returned %d estimates", len(estimates))
+	}
+	return &estimates[0], nil
+}
+
+// broadcastDeclare builds the broadcast form of a signed declare transaction.
+//
+// Parameters:
+// - tx: the signed transaction
+// - class: the Sierra class
+// Returns:
+// - rpc.BroadcastDeclareTxnV2: the transaction to broadcast
+func broadcastDeclare(tx rpc.DeclareTxnV2, class rpc.ContractClass) rpc.BroadcastDeclareTxnV2 {
+	return rpc.BroadcastDeclareTxnV2{
+		Type:              tx.Type,
+		SenderAddress:     tx.SenderAddress,
+		CompiledClassHash: tx.CompiledClassHash,
+		MaxFee:            tx.MaxFee,
+		Version:           rpc.NumAsHex(tx.Version),
+		Signature:         tx.Signature,
+		Nonce:             tx.Nonce,
+		ContractClass:     class,
+	}
+}
@@ -0,0 +1,105 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/contracts"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestDeclare_Versions tests that Declare fails before signing when the chain
+// does not accept the Sierra version of the class, and declares it otherwise.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDeclare_Versions(t *testing.T) {
+	content, err := os.ReadFile("./tests/hello_starknet_compiled.sierra.json")
+	require.NoError(t, err)
+	var class rpc.ContractClass
+	require.NoError(t, json.Unmarshal(content, &class))
+	casm, err := contracts.UnmarshalCasmClass("./tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+
+	acc, provider := newMockAccount(t, 2)
+
+	provider.EXPECT().BlockWithTxHashes(gomock.Any(), rpc.WithBlockTag("latest")).
+		Return(&rpc.BlockTxHashesResult{Block: &rpc.BlockTxHashes{BlockHeader: rpc.BlockHeader{StarknetVersion: "0.12.0"}}}, nil)
+	_, err = acc.Declare(context.Background(), class, *casm)
+	require.True(t, errors.Is(err, contracts.ErrUnsupportedSierraVersion))
+
+	pinned := contracts.ChainSupport{Sierra: contracts.VersionRange{Min: contracts.Version{Major: 1}, Max: contracts.Version{Major: 1, Minor: 3}}}
+	provider.EXPECT().AddDeclareTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, tx rpc.BroadcastDeclareTxnType) (*rpc.AddDeclareTransactionResponse, error) {
+			declare := tx.(rpc.BroadcastDeclareTxnV2)
+			require.Equal(t, "0x974a", declare.MaxFee.String())
+			require.Len(t, declare.Signature, 2)
+			return &rpc.AddDeclareTransactionResponse{TransactionHash: utils.TestHexToFelt(t, "0xabc")}, nil
+		})
+	resp, err := acc.Declare(context.Background(), class, *casm,
+		WithChainSupport(pinned), WithDeclareNonce(new(felt.Felt)), WithDeclareMaxFee(utils.Uint64ToFelt(0x974a)), WithScarbVersion("0.7.0"))
+	require.NoError(t, err)
+	require.Equal(t, "0xabc", resp.TransactionHash.String())
+	require.Equal(t, &contracts.ClassMetadata{
+		SierraVersion:       contracts.Version{Major: 1, Minor: 3},
+		CompilerVersion:     contracts.Version{Major: 2, Minor: 1},
+		CasmCompilerVersion: contracts.Version{Major: 2, Minor: 1},
+		ScarbVersion:        "0.7.0",
+	}, resp.Metadata)
+}
+
+// TestDeclare_FeeAndLimits tests that EstimateDeclareFee estimates the fee of
+// the declaration of a class within the limits, and that classes exceeding
+// them fail before anything is signed.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDeclare_FeeAndLimits(t *testing.T) {
+	content, err := os.ReadFile("./tests/hello_starknet_compiled.sierra.json")
+	require.NoError(t, err)
+	var class rpc.ContractClass
+	require.NoError(t, json.Unmarshal(content, &class))
+	casm, err := contracts.UnmarshalCasmClass("./tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+
+	acc, provider := newMockAccount(t, 2)
+	pinned := WithChainSupport(contracts.ChainSupport{Sierra: contracts.VersionRange{Min: contracts.Version{Major: 1}}})
+
+	provider.EXPECT().Nonce(gomock.Any(), rpc.WithBlockTag("latest"), acc.AccountAddress).Return(utils.Uint64ToFelt(4), nil)
+	provider.EXPECT().EstimateFee(gomock.Any(), gomock.Any(), gomock.Any(), rpc.WithBlockTag("latest")).DoAndReturn(
+		func(_ context.Context, requests []rpc.BroadcastTxn, _ []rpc.SimulationFlag, _ rpc.BlockID) ([]rpc.FeeEstimate, error) {
+			declare := requests[0].(rpc.BroadcastDeclareTxnV2)
+			require.Equal(t, new(felt.Felt), declare.MaxFee)
+			require.Equal(t, utils.Uint64ToFelt(4), declare.Nonce)
+			require.Equal(t, rpc.NumAsHex(rpc.TransactionV2WithQueryBit), declare.Version)
+			return []rpc.FeeEstimate{{OverallFee: utils.Uint64ToFelt(0x100)}}, nil
+		})
+	estimate, err := acc.EstimateDeclareFee(context.Background(), class, *casm, pinned,
+		WithDeclareBlockID(rpc.WithBlockTag("latest")), WithDeclareMaxFee(utils.Uint64ToFelt(1)))
+	require.NoError(t, err)
+	require.Equal(t, utils.Uint64ToFelt(0x100), estimate.OverallFee)
+
+	for _, limits := range []contracts.ClassLimits{
+		{MaxSierraProgramLength: len(class.SierraProgram) - 1},
+		{MaxCasmBytecodeLength: len(casm.ByteCode) - 1},
+		{MaxClassSize: 1000},
+	} {
+		_, err = acc.EstimateDeclareFee(context.Background(), class, *casm, pinned, WithClassLimits(limits))
+		require.True(t, errors.Is(err, contracts.ErrClassTooLarge))
+		_, err = acc.Declare(context.Background(), class, *casm, pinned, WithClassLimits(limits))
+		require.True(t, errors.Is(err, contracts.ErrClassTooLarge))
+	}
+}
@@ -0,0 +1,709 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/abi"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// DefaultFeeMultiplier is applied to the estimated fee when no max fee is given.
+const DefaultFeeMultiplier = 1.5
+
+// waitPollInterval is the interval the receipt is polled at by
+// WithWaitForAcceptance, a variable for the tests.
+var waitPollInterval = time.Second
+
+var ErrNoCalls = errors.New("no calls to execute")
+
+// RevertError is returned when the simulation of a transaction reverts, or
+// the transaction itself when waited for.
+type RevertError struct {
+	// Reason is the revert reason
+	Reason string
+	// Trace is the trace of the simulated transaction, nil for a transaction
+	// reverted on chain
+	Trace *rpc.InvokeTxnTrace
+}
+
+// Error returns the revert reason, telling a simulated revert, with a trace,
+// from an on-chain revert.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the error message
+func (e *RevertError) Error() string {
+	if e.Trace != nil {
+		return fmt.Sprintf("transaction reverted in simulation: %s", e.Reason)
+	}
+	return fmt.Sprintf("transaction reverted: %s", e.Reason)
+}
+
+// Sponsor executes calls on behalf of an account instead of the account
+// sending and paying for its own invoke transaction, e.g. a paymaster.
+type Sponsor interface {
+	ExecuteSponsored(ctx context.Context, account *Account, calls []rpc.FunctionCall) (*rpc.AddInvokeTransactionResponse, error)
+}
+
+type executeOptions struct {
+	maxFee        *felt.Felt
+	nonce         *felt.Felt
+	feeMultiplier float64
+	sponsor       Sponsor
+	simulate      bool
+	blockID       rpc.BlockID
+	queryVersion  bool
+	flags         []rpc.SimulationFlag
+	// tip, resourceBounds and paymasterData, when set, send a V3 transaction
+	tip            *uint64
+	resourceBounds *rpc.ResourceBoundsMapping
+	paymasterData  []*felt.Felt
+	waitTimeout    time.Duration
+	pollInterval   time.Duration
+	// flavor encodes the upgrade call of Upgrade
+	flavor AccountFlavor
+	// eventABIs decode the events of ExecuteAndWait by emitting contract
+	eventABIs map[felt.Felt]*abi.ABI
+}
+
+// v3 reports whether the options send a V3 transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if a tip, resource bounds or paymaster data are set
+func (o *executeOptions) v3() bool {
+	return o.tip != nil || o.resourceBounds != nil || o.paymasterData != nil
+}
+
+// receiptPollInterval returns the interval the receipt of the transaction
+// is polled at.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - time.Duration: the interval of WithPollInterval, or waitPollInterval
+func (o *executeOptions) receiptPollInterval() time.Duration {
+	if o.pollInterval > 0 {
+		return o.pollInterval
+	}
+	return waitPollInterval
+}
+
+// ExecuteDetails are the max fee and the nonce of an execution as a struct,
+// for callers passing them around before calling Execute. WithDetails turns
+// them into options.
+type ExecuteDetails struct {
+	// MaxFee is the max fee, estimated if nil
+	MaxFee *felt.Felt
+	// Nonce is the nonce, read from the provider if nil
+	Nonce *felt.Felt
+}
+
+// ExecuteOption configures Account.Execute.
+type ExecuteOption func(*executeOptions)
+
+// WithMaxFee sets the max fee of the transaction instead of estimating it.
+//
+// Parameters:
+// - maxFee: the max fee
+// Returns:
+// - ExecuteOption: the option
+func WithMaxFee(maxFee *felt.Felt) ExecuteOption {
+	return func(o *executeOptions) {
+		o.maxFee = maxFee
+	}
+}
+
+// WithNonce sets the nonce of the transaction instead of reading it from the provider.
+//
+// Parameters:
+// - nonce: the nonce
+// Returns:
+// - ExecuteOption: the option
+func WithNonce(nonce *felt.Felt) ExecuteOption {
+	return func(o *executeOptions) {
+		o.nonce = nonce
+	}
+}
+
+// WithFeeMultiplier sets the factor applied to the estimated fee to get the
+// max fee. It defaults to DefaultFeeMultiplier.
+//
+// Parameters:
+// - multiplier: the factor
+// Returns:
+// - ExecuteOption: the option
+func WithFeeMultiplier(multiplier float64) ExecuteOption {
+	return func(o *executeOptions) {
+		o.feeMultiplier = multiplier
+	}
+}
+
+// WithDetails sets the max fee and the nonce of details, as WithMaxFee and
+// WithNonce do for the fields set.
+//
+// Parameters:
+// - details: the details
+// Returns:
+// - ExecuteOption: the option
+func WithDetails(details ExecuteDetails) ExecuteOption {
+	return func(o *executeOptions) {
+		if details.MaxFee != nil {
+			o.maxFee = details.MaxFee
+		}
+		if details.Nonce != nil {
+			o.nonce = details.Nonce
+		}
+	}
+}
+
+// WithTip sets the tip of the transaction, sent as a V3 invoke transaction
+// paid in STRK.
+//
+// Parameters:
+// - tip: the tip, in FRI per unit of gas
+// Returns:
+// - ExecuteOption: the option
+func WithTip(tip uint64) ExecuteOption {
+	return func(o *executeOptions) {
+		o.tip = &tip
+	}
+}
+
+// WithResourceBounds sets the resource bounds of the transaction, sent as a
+// V3 invoke transaction, instead of estimating them. Without it, the L1 gas
+// bounds of a V3 transaction are the estimated gas and gas price
+// multiplied by the fee multiplier. The max fee is ignored.
+//
+// Parameters:
+// - bounds: the resource bounds
+// Returns:
+// - ExecuteOption: the option
+func WithResourceBounds(bounds rpc.ResourceBoundsMapping) ExecuteOption {
+	return func(o *executeOptions) {
+		o.resourceBounds = &bounds
+	}
+}
+
+// WithPaymasterData sets the paymaster data of the transaction, sent as a V3
+// invoke transaction. Paymasters executing the calls in place of the account
+// are set with WithSponsor, e.g. paymaster.WithPaymaster.
+//
+// Parameters:
+// - data: the paymaster data
+// Returns:
+// - ExecuteOption: the option
+func WithPaymasterData(data ...*felt.Felt) ExecuteOption {
+	return func(o *executeOptions) {
+		o.paymasterData = append([]*felt.Felt{}, data...)
+	}
+}
+
+// WithWaitForAcceptance waits for the receipt of the transaction after
+// sending it. Execute returns the response along with a *RevertError if the
+// transaction reverts, or with the error of the context if the receipt is
+// not found within the timeout.
+//
+// Parameters:
+// - timeout: the bound of the wait
+// Returns:
+// - ExecuteOption: the option
+func WithWaitForAcceptance(timeout time.Duration) ExecuteOption {
+	return func(o *executeOptions) {
+		o.waitTimeout = timeout
+	}
+}
+
+// WithPollInterval sets the interval the receipt of the transaction is
+// polled at by WithWaitForAcceptance and Upgrade, one second by default.
+//
+// Parameters:
+// - interval: the interval between two polls of the receipt
+// Returns:
+// - ExecuteOption: the option
+func WithPollInterval(interval time.Duration) ExecuteOption {
+	return func(o *executeOptions) {
+		o.pollInterval = interval
+	}
+}
+
+// WithSponsor hands the calls to a Sponsor, which executes them in place of
+// the account. Nonce and fee options are ignored.
+//
+// Parameters:
+// - sponsor: the sponsor
+// Returns:
+// - ExecuteOption: the option
+func WithSponsor(sponsor Sponsor) ExecuteOption {
+	return func(o *executeOptions) {
+		o.sponsor = sponsor
+	}
+}
+
+// WithBlockID sets the block the nonce is read from and the fee is estimated
+// and the transaction simulated against, instead of the pending block, e.g.
+// WithBlockTag("latest") to ignore the transactions not yet in a block.
+//
+// Parameters:
+// - blockID: the block
+// Returns:
+// - ExecuteOption: the option
+func WithBlockID(blockID rpc.BlockID) ExecuteOption {
+	return func(o *executeOptions) {
+		o.blockID = blockID
+	}
+}
+
+// WithSimulate simulates the transaction before sending it. The transaction is not sent if the simulation fails or reverts, in which
+// case Execute returns a *RevertError holding the trace.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - ExecuteOption: the option
+func WithSimulate() ExecuteOption {
+	return func(o *executeOptions) {
+		o.simulate = true
+	}
+}
+
+// WithQueryVersion sets whether the fee estimation and the simulation of the
+// transaction use the query version, 2^128 + 1, as the version of the signed
+// transaction. It is enabled by default: the query version keeps the signed
+// transaction from being sent, and accounts checking the version in
+// __validate__ expect it when estimating. Accounts rejecting it need
+// WithQueryVersion(false).
+//
+// Parameters:
+// - enabled: whether the query version is used
+// Returns:
+// - ExecuteOption: the option
+func WithQueryVersion(enabled bool) ExecuteOption {
+	return func(o *executeOptions) {
+		o.queryVersion = enabled
+	}
+}
+
+// WithSimulationFlags sets the flags of the fee estimation and of the
+// simulation of the transaction, e.g. rpc.SKIP_VALIDATE to estimate the fee
+// of an account not deployed yet. rpc.SKIP_FEE_CHARGE only applies to the
+// simulation, the fee estimation accepting only rpc.SKIP_VALIDATE.
+//
+// Parameters:
+// - flags: the simulation flags
+// Returns:
+// - ExecuteOption: the option
+func WithSimulationFlags(flags ...rpc.SimulationFlag) ExecuteOption {
+	return func(o *executeOptions) {
+		o.flags = flags
+	}
+}
+
+// Execute sends the calls in a single V1 invoke transaction, or V3 with a
+// tip, resource bounds or paymaster data.
+//
+// Unless set with options, the nonce is read from the pending block, on which
+// the fee is estimated, and the max fee is the estimated fee multiplied by
+// DefaultFeeMultiplier. The pending block includes the transactions sent
+// before, so that dependent transactions can be executed without waiting for
+// a block. The fee is estimated and the transaction simulated with the query
+// version, see WithQueryVersion.
+//
+// Callers holding ExecuteDetails pass them with WithDetails.
+//
+// Parameters:
+// - ctx: the context
+// - calls: the calls to execute
+// - opts: the execution options
+// Returns:
+// - *rpc.AddInvokeTransactionResponse: the response of the provider, also on the errors of WithWaitForAcceptance
+// - error: an error if the fee estimation, the signature or the submission fails, or the wait of WithWaitForAcceptance
+func (account *Account) Execute(ctx context.Context, calls []rpc.FunctionCall, opts ...ExecuteOption) (resp *rpc.AddInvokeTransactionResponse, err error) {
+	ctx, span := account.startSpan(ctx, "Account.Execute", AttributeCalls.Int(len(calls)))
+	defer func() {
+		var txHash *felt.Felt
+		if resp != nil {
+			txHash = resp.TransactionHash
+		}
+		endSpan(span, txHash, err)
+	}()
+
+	if len(calls) == 0 {
+		return nil, ErrNoCalls
+	}
+	options := executeOptions{feeMultiplier: DefaultFeeMultiplier, blockID: rpc.WithBlockTag("pending"), queryVersion: true}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.sponsor != nil {
+		resp, err = options.sponsor.ExecuteSponsored(ctx, account, calls)
+	} else {
+		resp, err = account.execute(ctx, calls, options)
+	}
+	if err != nil || options.waitTimeout <= 0 {
+		return resp, err
+	}
+	return resp, account.waitForAcceptance(ctx, resp.TransactionHash, options.waitTimeout, options.receiptPollInterval())
+}
+
+// execute sends the calls in an invoke transaction of the account.
+//
+// Parameters:
+// - ctx: the context
+// - calls: the calls to execute
+// - options: the execution options
+// Returns:
+// - *rpc.AddInvokeTransactionResponse: the response of the provider
+// - error: an error if the fee estimation, the signature or the submission fails
+func (account *Account) execute(ctx context.Context, calls []rpc.FunctionCall, options executeOptions) (*rpc.AddInvokeTransactionResponse, error) {
+	var err error
+	if options.nonce == nil {
+		options.nonce, err = account.Nonce(ctx, options.blockID, account.AccountAddress)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if options.v3() {
+		calldata, err := account.FmtCalldata(calls)
+		if err != nil {
+			return nil, err
+		}
+		return account.executeV3(ctx, calldata, options)
+	}
+	tx, err := account.BuildInvokeTxn(ctx, calls, options.nonce, options.maxFee)
+	if err != nil {
+		return nil, err
+	}
+	return account.executeV1(ctx, tx, options)
+}
+
+// executeV1 sends a signed V1 invoke transaction, whose max fee is estimated
+// unless set.
+//
+// Parameters:
+// - ctx: the context
+// - tx: the signed transaction
+// - options: the execution options
+// Returns:
+// - *rpc.AddInvokeTransactionResponse: the response of the provider
+// - error: an error if the fee estimation, the signature or the submission fails
+func (account *Account) executeV1(ctx context.Context, tx *rpc.InvokeTxnV1, options executeOptions) (*rpc.AddInvokeTransactionResponse, error) {
+	if options.maxFee == nil {
+		estimated, err := account.estimatedInvokeTxn(ctx, tx, options.queryVersion)
+		if err != nil {
+			return nil, err
+		}
+		estimates, err := account.EstimateFee(ctx, []rpc.BroadcastTxn{rpc.BroadcastInvokev1Txn{InvokeTxnV1: *estimated}}, estimateFlags(options.flags), options.blockID)
+		if err != nil {
+			return nil, err
+		}
+		if len(estimates) != 1 {
+			return nil, fmt.Errorf("fee estimation returned %d estimates", len(estimates))
+		}
+		if tx.MaxFee, err = estimates[0].MaxFee(options.feeMultiplier); err != nil {
+			return nil, err
+		}
+		if err := account.SignInvokeTransaction(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+	if options.simulate {
+		simulated, err := account.estimatedInvokeTxn(ctx, tx, options.queryVersion)
+		if err != nil {
+			return nil, err
+		}
+		if _, _, err := account.simulateInvoke(ctx, options.blockID, *simulated, options.flags); err != nil {
+			return nil, err
+		}
+	}
+	return account.AddInvokeTransaction(ctx, rpc.BroadcastInvokev1Txn{InvokeTxnV1: *tx})
+}
+
+// executeV3 sends calldata in a V3 invoke transaction, paid in STRK, whose
+// L1 gas bounds are estimated unless set.
+//
+// Parameters:
+// - ctx: the context
+// - calldata: the calldata of __execute__
+// - options: the execution options, with the nonce
+// Returns:
+// - *rpc.AddInvokeTransactionResponse: the response of the provider
+// - error: an error if the fee estimation, the signature or the submission fails
+func (account *Account) executeV3(ctx context.Context, calldata []*felt.Felt, options executeOptions) (*rpc.AddInvokeTransactionResponse, error) {
+	var tip uint64
+	if options.tip != nil {
+		tip = *options.tip
+	}
+	paymasterData := options.paymasterData
+	if paymasterData == nil {
+		paymasterData = []*felt.Felt{}
+	}
+	zero := rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"}
+	tx := rpc.InvokeTxnV3{
+		Type:                  rpc.TransactionType_Invoke,
+		SenderAddress:         account.AccountAddress,
+		Calldata:              calldata,
+		Version:               rpc.TransactionV3,
+		Nonce:                 options.nonce,
+		ResourceBounds:        rpc.ResourceBoundsMapping{L1Gas: zero, L2Gas: zero},
+		Tip:                   rpc.U64(fmt.Sprintf("0x%x", tip)),
+		PayMasterData:         paymasterData,
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+	}
+	if options.resourceBounds != nil {
+		tx.ResourceBounds = *options.resourceBounds
+	} else {
+		estimated, err := account.estimatedInvokeTxnV3(ctx, tx, options.queryVersion)
+		if err != nil {
+			return nil, err
+		}
+		estimates, err := account.EstimateFee(ctx, []rpc.BroadcastTxn{rpc.BroadcastInvokev3Txn{InvokeTxnV3: estimated}}, estimateFlags(options.flags), options.blockID)
+		if err != nil {
+			return nil, err
+		}
+		if len(estimates) != 1 {
+			return nil, fmt.Errorf("fee estimation returned %d estimates", len(estimates))
+		}
+		if tx.ResourceBounds.L1Gas, err = estimates[0].ResourceBounds(options.feeMultiplier, options.feeMultiplier); err != nil {
+			return nil, err
+		}
+	}
+	if err := account.signInvokeTxnV3(ctx, &tx); err != nil {
+		return nil, err
+	}
+	if options.simulate {
+		simulated, err := account.estimatedInvokeTxnV3(ctx, tx, options.queryVersion)
+		if err != nil {
+			return nil, err
+		}
+		if _, _, err := account.simulateInvoke(ctx, options.blockID, simulated, options.flags); err != nil {
+			return nil, err
+		}
+	}
+	return account.AddInvokeTransaction(ctx, rpc.BroadcastInvokev3Txn{InvokeTxnV3: tx})
+}
+
+// estimatedInvokeTxnV3 returns the signed V3 transaction to estimate or
+// simulate: with the query version if enabled.
+//
+// Parameters:
+// - ctx: the context
+// - tx: the transaction
+// - queryVersion: whether the query version is used
+// Returns:
+// - rpc.InvokeTxnV3: the signed transaction to estimate or simulate
+// - error: an error if the signature fails
+func (account *Account) estimatedInvokeTxnV3(ctx context.Context, tx rpc.InvokeTxnV3, queryVersion bool) (rpc.InvokeTxnV3, error) {
+	if queryVersion {
+		tx.Version = rpc.TransactionV3WithQueryBit
+	}
+	if err := account.signInvokeTxnV3(ctx, &tx); err != nil {
+		return rpc.InvokeTxnV3{}, err
+	}
+	return tx, nil
+}
+
+// signInvokeTxnV3 signs a V3 invoke transaction.
+//
+// Parameters:
+// - ctx: the context
+// - tx: the transaction, whose signature is set
+// Returns:
+// - error: an error if the hash or the signature fails
+func (account *Account) signInvokeTxnV3(ctx context.Context, tx *rpc.InvokeTxnV3) error {
+	txHash, err := account.TransactionHashInvoke(*tx)
+	if err != nil {
+		return err
+	}
+	signature, err := account.Sign(ctx, txHash)
+	if err != nil {
+		return err
+	}
+	tx.Signature = signature
+	return nil
+}
+
+// waitForAcceptance waits for the receipt of a transaction.
+//
+// Parameters:
+// - ctx: the context
+// - txHash: the hash of the transaction
+// - timeout: the bound of the wait
+// - pollInterval: the interval between two polls of the receipt
+// Returns:
+// - error: a *RevertError if the transaction reverted, or the error of the wait
+func (account *Account) waitForAcceptance(ctx context.Context, txHash *felt.Felt, timeout, pollInterval time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	receipt, err := account.WaitForTransactionReceipt(ctx, txHash, pollInterval)
+	if err != nil {
+		return err
+	}
+	if receipt.ExecutionStatus() == rpc.TxnExecutionStatusREVERTED {
+		return &RevertError{Reason: receipt.RevertReason()}
+	}
+	return nil
+}
+
+// estimatedInvokeTxn returns the transaction to estimate or simulate in place
+// of a signed transaction: a copy with the query version, signed again, or
+// the transaction itself.
+//
+// Parameters:
+// - ctx: the context
+// - tx: the signed transaction
+// - queryVersion: whether the query version is used
+// Returns:
+// - *rpc.InvokeTxnV1: the transaction to estimate or simulate
+// - error: an error if the signature fails
+func (account *Account) estimatedInvokeTxn(ctx context.Context, tx *rpc.InvokeTxnV1, queryVersion bool) (*rpc.InvokeTxnV1, error) {
+	if !queryVersion {
+		return tx, nil
+	}
+	query := *tx
+	query.Version = rpc.TransactionV1WithQueryBit
+	if err := account.SignInvokeTransaction(ctx, &query); err != nil {
+		return nil, err
+	}
+	return &query, nil
+}
+
+// SimulateInvoke simulates a signed V1 invoke transaction on the pending block.
+//
+// Parameters:
+// - ctx: the context
+// - tx: the signed transaction
+// - flags: the simulation flags, e.g. rpc.SKIP_VALIDATE for a placeholder signature
+// Returns:
+// - *rpc.InvokeTxnTrace: the trace of the transaction
+// - *rpc.FeeEstimate: the fee of the transaction
+// - error: a *RevertError if the transaction reverts, or an error if the simulation fails
+func (account *Account) SimulateInvoke(ctx context.Context, tx *rpc.InvokeTxnV1, flags ...rpc.SimulationFlag) (*rpc.InvokeTxnTrace, *rpc.FeeEstimate, error) {
+	return account.simulateInvoke(ctx, rpc.WithBlockTag("pending"), *tx, flags)
+}
+
+// simulateInvoke simulates a signed invoke transaction on a block.
+//
+// Parameters:
+// - ctx: the context
+// - blockID: the block
+// - tx: the signed transaction
+// - flags: the simulation flags
+// Returns:
+// - *rpc.InvokeTxnTrace: the trace of the transaction
+// - *rpc.FeeEstimate: the fee of the transaction
+// - error: a *RevertError if the transaction reverts, or an error if the simulation fails
+func (account *Account) simulateInvoke(ctx context.Context, blockID rpc.BlockID, tx rpc.Transaction, flags []rpc.SimulationFlag) (*rpc.InvokeTxnTrace, *rpc.FeeEstimate, error) {
+	simulated, err := account.SimulateTransactions(ctx, blockID, []rpc.Transaction{tx}, flags)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(simulated) != 1 {
+		return nil, nil, fmt.Errorf("simulation returned %d transactions", len(simulated))
+	}
+	trace, err := decodeInvokeTrace(simulated[0].TxnTrace)
+	if err != nil {
+		return nil, nil, err
+	}
+	if trace.ExecuteInvocation.RevertReason != "" {
+		return trace, &simulated[0].FeeEstimate, &RevertError{Reason: trace.ExecuteInvocation.RevertReason, Trace: trace}
+	}
+	return trace, &simulated[0].FeeEstimate, nil
+}
+
+// estimateFlags returns the simulation flags accepted by the fee estimation.
+//
+// Parameters:
+// - flags: the simulation flags
+// Returns:
+// - []rpc.SimulationFlag: the flags, without the flags only applying to simulations
+func estimateFlags(flags []rpc.SimulationFlag) []rpc.SimulationFlag {
+	accepted := []rpc.SimulationFlag{}
+	for _, flag := range flags {
+		if flag == rpc.SKIP_VALIDATE {
+			accepted = append(accepted, flag)
+		}
+	}
+	return accepted
+}
+
+// decodeInvokeTrace decodes the trace of a simulated invoke transaction.
+//
+// Parameters:
+// - trace: the trace, as decoded by the provider
+// Returns:
+// - *rpc.InvokeTxnTrace: the trace
+// - error: an error if the trace is not an invoke trace
+func decodeInvokeTrace(trace rpc.TxnTrace) (*rpc.InvokeTxnTrace, error) {
+	switch trace := trace.(type) {
+	case rpc.InvokeTxnTrace:
+		return &trace, nil
+	case *rpc.InvokeTxnTrace:
+		return trace, nil
+	}
+	raw, err := json.Marshal(trace)
+	if err != nil {
+		return nil, err
+	}
+	var decoded rpc.InvokeTxnTrace
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, err
+	}
+	return &decoded, nil
+}
+
+// BuildInvokeTxn builds and signs the V1 invoke transaction executing the calls.
+//
+// Parameters:
+// - ctx: the context
+// - calls: the calls to execute
+// - nonce: the nonce, read from the pending block when nil
+// - maxFee: the max fee, zero when nil
+// Returns:
+// - *rpc.InvokeTxnV1: the signed transaction
+// - error: an error if the nonce can not be read or the signature fails
+func (account *Account) BuildInvokeTxn(ctx context.Context, calls []rpc.FunctionCall, nonce, maxFee *felt.Felt) (*rpc.InvokeTxnV1, error) {
+	if nonce == nil {
+		var err error
+		nonce, err = account.Nonce(ctx, rpc.WithBlockTag("pending"), account.AccountAddress)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if maxFee == nil {
+		maxFee = new(felt.Felt)
+	}
+	calldata, err := account.FmtCalldata(calls)
+	if err != nil {
+		return nil, err
+	}
+	tx := &rpc.InvokeTxnV1{
+		MaxFee:        maxFee,
+		Version:       rpc.TransactionV1,
+		Nonce:         nonce,
+		Type:          rpc.TransactionType_Invoke,
+		SenderAddress: account.AccountAddress,
+		Calldata:      calldata,
+	}
+	if err := account.SignInvokeTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
@@ -0,0 +1,389 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/abi"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestExecute_Simulate tests that Execute with WithSimulate sends the
+// transaction when the simulation succeeds and refuses to when it reverts.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestExecute_Simulate(t *testing.T) {
+	acc, provider := newMockAccount(t, 2)
+
+	call := rpc.FunctionCall{ContractAddress: utils.TestHexToFelt(t, "0xc0ffee"), EntryPointSelector: utils.GetSelectorFromNameFelt("transfer")}
+	reverted := map[string]any{
+		"type":               "INVOKE",
+		"execute_invocation": map[string]any{"revert_reason": "u256_sub Overflow"},
+	}
+	gomock.InOrder(
+		provider.EXPECT().SimulateTransactions(gomock.Any(), rpc.WithBlockTag("pending"), gomock.Any(), gomock.Any()).
+			Return([]rpc.SimulatedTransaction{{TxnTrace: rpc.InvokeTxnTrace{Type: rpc.TransactionType_Invoke}}}, nil),
+		provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).
+			Return(&rpc.AddInvokeTransactionResponse{TransactionHash: utils.TestHexToFelt(t, "0xabc")}, nil),
+		provider.EXPECT().SimulateTransactions(gomock.Any(), rpc.WithBlockTag("pending"), gomock.Any(), gomock.Any()).
+			Return([]rpc.SimulatedTransaction{{TxnTrace: reverted}}, nil),
+	)
+
+	resp, err := acc.Execute(context.Background(), []rpc.FunctionCall{call},
+		WithNonce(utils.Uint64ToFelt(1)), WithMaxFee(utils.Uint64ToFelt(100)), WithSimulate())
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0xabc"), resp.TransactionHash)
+
+	_, err = acc.Execute(context.Background(), []rpc.FunctionCall{call},
+		WithNonce(utils.Uint64ToFelt(2)), WithMaxFee(utils.Uint64ToFelt(100)), WithSimulate())
+	var revertErr *RevertError
+	require.True(t, errors.As(err, &revertErr))
+	require.Equal(t, "u256_sub Overflow", revertErr.Reason)
+	require.Equal(t, "transaction reverted in simulation: u256_sub Overflow", revertErr.Error())
+}
+
+// TestExecute_Tracing tests the spans recorded for Execute, EstimateFee and
+// WaitForTransactionReceipt.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestExecute_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	acc, provider := newMockAccount(t, 2, WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+
+	txHash := utils.TestHexToFelt(t, "0xabc")
+	provider.EXPECT().EstimateFee(gomock.Any(), gomock.Any(), gomock.Any(), rpc.WithBlockTag("pending")).
+		Return([]rpc.FeeEstimate{{OverallFee: utils.Uint64ToFelt(100)}}, nil)
+	provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).
+		Return(&rpc.AddInvokeTransactionResponse{TransactionHash: txHash}, nil)
+	gomock.InOrder(
+		provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(nil, rpc.ErrHashNotFound),
+		provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).
+			Return(&rpc.Receipt{TransactionReceipt: rpc.InvokeTransactionReceipt{ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED}}, nil),
+	)
+
+	call := rpc.FunctionCall{ContractAddress: utils.TestHexToFelt(t, "0xc0ffee"), EntryPointSelector: utils.GetSelectorFromNameFelt("transfer")}
+	_, err := acc.Execute(context.Background(), []rpc.FunctionCall{call}, WithNonce(utils.Uint64ToFelt(1)))
+	require.NoError(t, err)
+	_, err = acc.WaitForTransactionReceipt(context.Background(), txHash, time.Millisecond)
+	require.NoError(t, err)
+	_, err = acc.Execute(context.Background(), nil)
+	require.Equal(t, ErrNoCalls, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	attributes := func(span sdktrace.ReadOnlySpan) map[attribute.Key]string {
+		m := map[attribute.Key]string{}
+		for _, kv := range span.Attributes() {
+			m[kv.Key] = kv.Value.Emit()
+		}
+		return m
+	}
+	estimate, execute, wait, failed := spans[0], spans[1], spans[2], spans[3]
+	require.Equal(t, "Account.EstimateFee", estimate.Name())
+	require.Equal(t, execute.SpanContext().SpanID(), estimate.Parent().SpanID())
+	require.Equal(t, "pending", attributes(estimate)[rpc.AttributeBlockID])
+	require.Equal(t, "Account.Execute", execute.Name())
+	require.Equal(t, map[attribute.Key]string{AttributeCalls: "1", AttributeAccountAddress: "0xacc", rpc.AttributeTransactionHash: "0xabc"}, attributes(execute))
+	require.Equal(t, "Account.WaitForTransactionReceipt", wait.Name())
+	require.Equal(t, "2", attributes(wait)[AttributePolls])
+	require.Equal(t, "SUCCEEDED", attributes(wait)[AttributeExecutionStatus])
+	require.Equal(t, codes.Error, failed.Status().Code)
+}
+
+// TestExecute_BlockID tests that Execute reads the nonce and estimates the fee
+// on the pending block, or on the block given with WithBlockID.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestExecute_BlockID(t *testing.T) {
+	acc, provider := newMockAccount(t, 2)
+	address := acc.AccountAddress
+	call := rpc.FunctionCall{ContractAddress: utils.TestHexToFelt(t, "0xc0ffee"), EntryPointSelector: utils.SelectorTransfer}
+
+	for _, blockID := range []rpc.BlockID{rpc.WithBlockTag("pending"), rpc.WithBlockTag("latest"), rpc.WithBlockNumber(100)} {
+		provider.EXPECT().Nonce(gomock.Any(), blockID, address).Return(utils.Uint64ToFelt(1), nil)
+		provider.EXPECT().EstimateFee(gomock.Any(), gomock.Any(), gomock.Any(), blockID).
+			Return([]rpc.FeeEstimate{{OverallFee: utils.Uint64ToFelt(100)}}, nil)
+		provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).
+			Return(&rpc.AddInvokeTransactionResponse{TransactionHash: utils.TestHexToFelt(t, "0xabc")}, nil)
+		var opts []ExecuteOption
+		if blockID.Tag != "pending" {
+			opts = append(opts, WithBlockID(blockID))
+		}
+		_, err := acc.Execute(context.Background(), []rpc.FunctionCall{call}, opts...)
+		require.NoError(t, err)
+	}
+}
+
+// TestExecute_QueryVersion tests that Execute estimates the fee and simulates
+// the transaction with the query version, unless disabled with
+// WithQueryVersion, and sends it with version 1.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestExecute_QueryVersion(t *testing.T) {
+	acc, provider := newMockAccount(t, 2)
+	call := rpc.FunctionCall{ContractAddress: utils.TestHexToFelt(t, "0xc0ffee"), EntryPointSelector: utils.SelectorTransfer}
+
+	for _, queryVersion := range []bool{true, false} {
+		var estimated, simulated, sent rpc.InvokeTxnV1
+		provider.EXPECT().EstimateFee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, requests []rpc.BroadcastTxn, _ []rpc.SimulationFlag, _ rpc.BlockID) ([]rpc.FeeEstimate, error) {
+				estimated = requests[0].(rpc.BroadcastInvokev1Txn).InvokeTxnV1
+				return []rpc.FeeEstimate{{OverallFee: utils.Uint64ToFelt(100)}}, nil
+			})
+		provider.EXPECT().SimulateTransactions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ rpc.BlockID, txns []rpc.Transaction, _ []rpc.SimulationFlag) ([]rpc.SimulatedTransaction, error) {
+				simulated = txns[0].(rpc.InvokeTxnV1)
+				return []rpc.SimulatedTransaction{{TxnTrace: rpc.InvokeTxnTrace{Type: rpc.TransactionType_Invoke}}}, nil
+			})
+		provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+				sent = tx.(rpc.BroadcastInvokev1Txn).InvokeTxnV1
+				return &rpc.AddInvokeTransactionResponse{TransactionHash: utils.TestHexToFelt(t, "0xabc")}, nil
+			})
+		_, err := acc.Execute(context.Background(), []rpc.FunctionCall{call},
+			WithNonce(utils.Uint64ToFelt(1)), WithSimulate(), WithQueryVersion(queryVersion))
+		require.NoError(t, err)
+
+		expected := rpc.TransactionV1
+		if queryVersion {
+			expected = rpc.TransactionV1WithQueryBit
+		}
+		require.Equal(t, expected, estimated.Version)
+		require.Equal(t, expected, simulated.Version)
+		require.Equal(t, rpc.TransactionV1, sent.Version)
+		require.Equal(t, queryVersion, simulated.Signature[0].Cmp(sent.Signature[0]) != 0)
+	}
+}
+
+// TestExecute_SimulationFlags tests that the flags of WithSimulationFlags are
+// passed to the simulation, and those accepted to the fee estimation.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestExecute_SimulationFlags(t *testing.T) {
+	acc, provider := newMockAccount(t, 2)
+	call := rpc.FunctionCall{ContractAddress: utils.TestHexToFelt(t, "0xc0ffee"), EntryPointSelector: utils.SelectorTransfer}
+
+	provider.EXPECT().EstimateFee(gomock.Any(), gomock.Any(), []rpc.SimulationFlag{rpc.SKIP_VALIDATE}, gomock.Any()).
+		Return([]rpc.FeeEstimate{{OverallFee: utils.Uint64ToFelt(100)}}, nil)
+	provider.EXPECT().SimulateTransactions(gomock.Any(), gomock.Any(), gomock.Any(), []rpc.SimulationFlag{rpc.SKIP_VALIDATE, rpc.SKIP_FEE_CHARGE}).
+		Return([]rpc.SimulatedTransaction{{TxnTrace: rpc.InvokeTxnTrace{Type: rpc.TransactionType_Invoke}}}, nil)
+	provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).
+		Return(&rpc.AddInvokeTransactionResponse{TransactionHash: utils.TestHexToFelt(t, "0xabc")}, nil)
+	_, err := acc.Execute(context.Background(), []rpc.FunctionCall{call},
+		WithNonce(utils.Uint64ToFelt(1)), WithSimulate(), WithSimulationFlags(rpc.SKIP_VALIDATE, rpc.SKIP_FEE_CHARGE))
+	require.NoError(t, err)
+
+	provider.EXPECT().SimulateTransactions(gomock.Any(), rpc.WithBlockTag("pending"), gomock.Any(), []rpc.SimulationFlag{rpc.SKIP_VALIDATE}).
+		Return([]rpc.SimulatedTransaction{{TxnTrace: rpc.InvokeTxnTrace{Type: rpc.TransactionType_Invoke}}}, nil)
+	tx, err := acc.BuildInvokeTxn(context.Background(), []rpc.FunctionCall{call}, utils.Uint64ToFelt(1), nil)
+	require.NoError(t, err)
+	_, _, err = acc.SimulateInvoke(context.Background(), tx, rpc.SKIP_VALIDATE)
+	require.NoError(t, err)
+}
+
+// TestExecute_V3 tests that a tip or resource bounds send a V3 transaction,
+// whose L1 gas bounds are estimated with the query version unless set, and
+// that ExecuteDetails are passed as options.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestExecute_V3(t *testing.T) {
+	acc, provider := newMockAccount(t, 2)
+	call := rpc.FunctionCall{ContractAddress: utils.TestHexToFelt(t, "0xc0ffee"), EntryPointSelector: utils.SelectorTransfer}
+
+	var estimated, sent rpc.InvokeTxnV3
+	provider.EXPECT().EstimateFee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, requests []rpc.BroadcastTxn, _ []rpc.SimulationFlag, _ rpc.BlockID) ([]rpc.FeeEstimate, error) {
+			estimated = requests[0].(rpc.BroadcastInvokev3Txn).InvokeTxnV3
+			return []rpc.FeeEstimate{{GasConsumed: utils.Uint64ToFelt(100), GasPrice: utils.Uint64ToFelt(10), FeeUnit: rpc.UnitStrk}}, nil
+		})
+	provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(_ context.Context, tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+			sent = tx.(rpc.BroadcastInvokev3Txn).InvokeTxnV3
+			return &rpc.AddInvokeTransactionResponse{TransactionHash: utils.TestHexToFelt(t, "0xabc")}, nil
+		})
+	_, err := acc.Execute(context.Background(), []rpc.FunctionCall{call},
+		WithDetails(ExecuteDetails{Nonce: utils.Uint64ToFelt(3)}), WithTip(7), WithFeeMultiplier(2))
+	require.NoError(t, err)
+	require.Equal(t, rpc.TransactionV3WithQueryBit, estimated.Version)
+	require.Equal(t, rpc.TransactionV3, sent.Version)
+	require.Equal(t, uint64(3), sent.Nonce.Uint64())
+	require.Equal(t, rpc.U64("0x7"), sent.Tip)
+	require.Equal(t, rpc.ResourceBounds{MaxAmount: "0xc8", MaxPricePerUnit: "0x14"}, sent.ResourceBounds.L1Gas)
+	txHash, err := acc.TransactionHashInvoke(sent)
+	require.NoError(t, err)
+	signature, err := acc.Sign(context.Background(), txHash)
+	require.NoError(t, err)
+	require.Equal(t, signature, sent.Signature)
+
+	bounds := rpc.ResourceBoundsMapping{
+		L1Gas: rpc.ResourceBounds{MaxAmount: "0x64", MaxPricePerUnit: "0x5"},
+		L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+	}
+	_, err = acc.Execute(context.Background(), []rpc.FunctionCall{call}, WithNonce(utils.Uint64ToFelt(4)), WithResourceBounds(bounds))
+	require.NoError(t, err)
+	require.Equal(t, bounds, sent.ResourceBounds)
+	require.Equal(t, rpc.U64("0x0"), sent.Tip)
+}
+
+// TestExecute_Estimates tests that Execute fails, without sending the
+// transaction, when the fee estimation does not return exactly one estimate.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestExecute_Estimates(t *testing.T) {
+	acc, provider := newMockAccount(t, 2)
+	call := rpc.FunctionCall{ContractAddress: utils.TestHexToFelt(t, "0xc0ffee"), EntryPointSelector: utils.SelectorTransfer}
+
+	for _, opts := range [][]ExecuteOption{
+		{WithNonce(utils.Uint64ToFelt(1))},
+		{WithNonce(utils.Uint64ToFelt(1)), WithTip(7)},
+	} {
+		provider.EXPECT().EstimateFee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]rpc.FeeEstimate{}, nil)
+		_, err := acc.Execute(context.Background(), []rpc.FunctionCall{call}, opts...)
+		require.EqualError(t, err, "fee estimation returned 0 estimates")
+	}
+}
+
+// TestExecute_WaitForAcceptance tests that Execute waits for the receipt of
+// the transaction, failing with a *RevertError if it reverts.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestExecute_WaitForAcceptance(t *testing.T) {
+	waitPollInterval = time.Millisecond
+	defer func() { waitPollInterval = time.Second }()
+	acc, provider := newMockAccount(t, 2)
+	call := rpc.FunctionCall{ContractAddress: utils.TestHexToFelt(t, "0xc0ffee"), EntryPointSelector: utils.SelectorTransfer}
+	txHash := utils.TestHexToFelt(t, "0xabc")
+
+	provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).Times(2).
+		Return(&rpc.AddInvokeTransactionResponse{TransactionHash: txHash}, nil)
+	gomock.InOrder(
+		provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(nil, rpc.ErrHashNotFound),
+		provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).
+			Return(&rpc.Receipt{TransactionReceipt: rpc.InvokeTransactionReceipt{ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED}}, nil),
+		provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).
+			Return(&rpc.Receipt{TransactionReceipt: rpc.InvokeTransactionReceipt{ExecutionStatus: rpc.TxnExecutionStatusREVERTED, RevertReason: "insufficient balance"}}, nil),
+	)
+	opts := []ExecuteOption{WithNonce(utils.Uint64ToFelt(1)), WithMaxFee(utils.Uint64ToFelt(100)), WithWaitForAcceptance(5 * time.Second)}
+	resp, err := acc.Execute(context.Background(), []rpc.FunctionCall{call}, opts...)
+	require.NoError(t, err)
+	require.Equal(t, txHash, resp.TransactionHash)
+
+	resp, err = acc.Execute(context.Background(), []rpc.FunctionCall{call}, opts...)
+	var revert *RevertError
+	require.True(t, errors.As(err, &revert))
+	require.Equal(t, "insufficient balance", revert.Reason)
+	require.Equal(t, "transaction reverted: insufficient balance", revert.Error())
+	require.Equal(t, txHash, resp.TransactionHash)
+}
+
+// TestExecuteAndWait tests that ExecuteAndWait polls the receipt until the
+// transaction is in a block, decodes the events of the contracts with an ABI
+// and the standard events of the other contracts, and returns the revert
+// reason of reverted transactions.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestExecuteAndWait(t *testing.T) {
+	waitPollInterval = time.Millisecond
+	defer func() { waitPollInterval = time.Second }()
+	acc, provider := newMockAccount(t, 2)
+	token := utils.TestHexToFelt(t, "0xc0ffee")
+	call := rpc.FunctionCall{ContractAddress: token, EntryPointSelector: utils.SelectorTransfer}
+	txHash := utils.TestHexToFelt(t, "0xabc")
+	parsed, err := abi.Parse([]byte(`[
+  {"type": "struct", "name": "core::integer::u256", "members": [{"name": "low", "type": "core::integer::u128"}, {"name": "high", "type": "core::integer::u128"}]},
+  {"type": "event", "name": "my::Transfer", "kind": "struct", "members": [{"name": "from", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"}, {"name": "value", "type": "core::integer::u256", "kind": "data"}]}
+]`))
+	require.NoError(t, err)
+	transfer := rpc.Event{FromAddress: token, Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Transfer"), acc.AccountAddress}, Data: []*felt.Felt{utils.Uint64ToFelt(5), utils.Uint64ToFelt(0)}}
+	other := rpc.Event{FromAddress: utils.TestHexToFelt(t, "0x0e"), Keys: []*felt.Felt{utils.Uint64ToFelt(1)}}
+	standard := rpc.Event{FromAddress: utils.TestHexToFelt(t, "0x5ca1e"), Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Transfer"), acc.AccountAddress, token}, Data: []*felt.Felt{utils.Uint64ToFelt(7), utils.Uint64ToFelt(0)}}
+	fee := rpc.FeePayment{Amount: utils.Uint64ToFelt(42), Unit: rpc.UnitStrk}
+
+	provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).Times(2).
+		Return(&rpc.AddInvokeTransactionResponse{TransactionHash: txHash}, nil)
+	gomock.InOrder(
+		provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(nil, rpc.ErrHashNotFound),
+		provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).
+			Return(&rpc.Receipt{TransactionReceipt: rpc.PendingInvokeTransactionReceipt{Type: rpc.TransactionType_Invoke}}, nil),
+		provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).
+			Return(&rpc.Receipt{TransactionReceipt: rpc.InvokeTransactionReceipt{
+				TransactionHash: txHash, ActualFee: fee, ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+				FinalityStatus: rpc.TxnFinalityStatusAcceptedOnL2, BlockHash: utils.Uint64ToFelt(0xb), BlockNumber: 11,
+				Events: []rpc.Event{transfer, other, standard},
+			}}, nil),
+		provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).
+			Return(&rpc.Receipt{TransactionReceipt: rpc.InvokeTransactionReceipt{
+				TransactionHash: txHash, ActualFee: fee, ExecutionStatus: rpc.TxnExecutionStatusREVERTED, RevertReason: "insufficient balance",
+				FinalityStatus: rpc.TxnFinalityStatusAcceptedOnL2, BlockHash: utils.Uint64ToFelt(0xc), BlockNumber: 12,
+			}}, nil),
+	)
+	opts := []ExecuteOption{WithNonce(utils.Uint64ToFelt(1)), WithMaxFee(utils.Uint64ToFelt(100)), WithEventABI(token, parsed), WithWaitForAcceptance(5 * time.Second)}
+	result, err := acc.ExecuteAndWait(context.Background(), []rpc.FunctionCall{call}, opts...)
+	require.NoError(t, err)
+	require.Equal(t, txHash, result.TransactionHash)
+	require.Equal(t, fee, result.ActualFee)
+	require.Nil(t, result.Revert)
+	require.Len(t, result.Events, 3)
+	require.Equal(t, "my::Transfer", result.Events[0].Name)
+	require.Equal(t, acc.AccountAddress, result.Events[0].Values["from"])
+	require.Equal(t, "5", result.Events[0].Values["value"].(fmt.Stringer).String())
+	require.Equal(t, other, result.Events[1].Event)
+	require.Empty(t, result.Events[1].Name)
+	require.Equal(t, "openzeppelin::token::erc20::erc20::ERC20Component::Transfer", result.Events[2].Name)
+	require.Equal(t, token, result.Events[2].Values["to"])
+	require.Equal(t, "7", result.Events[2].Values["value"].(fmt.Stringer).String())
+
+	result, err = acc.ExecuteAndWait(context.Background(), []rpc.FunctionCall{call}, opts...)
+	var revert *RevertError
+	require.True(t, errors.As(err, &revert))
+	require.Equal(t, "insufficient balance", revert.Reason)
+	require.Equal(t, revert, result.Revert)
+	require.Equal(t, fee, result.ActualFee)
+}
@@ -0,0 +1,386 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/lifecycle"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+const (
+	// DefaultQueueSize is the number of transactions a TxQueue holds before
+	// Submit blocks.
+	DefaultQueueSize = 64
+	// DefaultQueueRetries is the number of retries of a failing transaction.
+	DefaultQueueRetries = 3
+	// DefaultQueueRetryDelay is the delay before the first retry, doubled on each retry.
+	DefaultQueueRetryDelay = time.Second
+	// DefaultReceivedTimeout bounds the wait of a sent transaction to be received by the node.
+	DefaultReceivedTimeout = 30 * time.Second
+)
+
+var (
+	ErrQueueStopped        = errors.New("transaction queue stopped")
+	ErrTransactionRejected = errors.New("transaction rejected")
+)
+
+// TxQueue sends the transactions of an account one after the other, in the
+// order they are submitted, assigning them consecutive nonces without
+// reading the nonce of the account between them, so that dependent
+// transactions land in the same block. Each transaction is sent once the
+// previous one is received by the node.
+//
+// The nonce is read from the pending block before the first transaction, and
+// again after a failure. A failing transaction is retried, and the
+// transactions after it wait for it.
+//
+// The queue is run by Run, or started and stopped as a lifecycle.Lifecycle
+// component, Stop waiting for the transaction in flight.
+type TxQueue struct {
+	lifecycle.Base
+	account         *Account
+	jobs            chan *QueuedTx
+	stopped         chan struct{}
+	stop            sync.Once
+	retries         int
+	retryDelay      time.Duration
+	receivedTimeout time.Duration
+
+	// nonce is the nonce of the next transaction, nil when it must be read
+	nonce *felt.Felt
+}
+
+// QueuedTx is a transaction submitted to a TxQueue.
+type QueuedTx struct {
+	calls []rpc.FunctionCall
+	opts  []ExecuteOption
+	done  chan struct{}
+	resp  *rpc.AddInvokeTransactionResponse
+	err   error
+	// stopped is closed when the queue stops
+	stopped <-chan struct{}
+}
+
+// QueueOption configures a TxQueue.
+type QueueOption func(*TxQueue)
+
+// WithQueueSize sets the number of transactions the queue holds before
+// Submit blocks, DefaultQueueSize by default.
+//
+// Parameters:
+// - size: the size of the queue, ignored if not positive
+// Returns:
+// - QueueOption: the option
+func WithQueueSize(size int) QueueOption {
+	return func(q *TxQueue) {
+		if size > 0 {
+			q.jobs = make(chan *QueuedTx, size)
+		}
+	}
+}
+
+// WithQueueRetries sets the number of retries of a failing transaction and
+// the delay before the first one, doubled on each retry, DefaultQueueRetries
+// and DefaultQueueRetryDelay by default.
+//
+// Parameters:
+// - retries: the number of retries, 0 to fail on the first error
+// - delay: the delay before the first retry
+// Returns:
+// - QueueOption: the option
+func WithQueueRetries(retries int, delay time.Duration) QueueOption {
+	return func(q *TxQueue) {
+		q.retries = retries
+		q.retryDelay = delay
+	}
+}
+
+// WithReceivedTimeout bounds the wait of a sent transaction to be received
+// by the node before it is sent again, DefaultReceivedTimeout by default.
+//
+// Parameters:
+// - timeout: the bound of the wait, ignored if not positive
+// Returns:
+// - QueueOption: the option
+func WithReceivedTimeout(timeout time.Duration) QueueOption {
+	return func(q *TxQueue) {
+		if timeout > 0 {
+			q.receivedTimeout = timeout
+		}
+	}
+}
+
+// NewTxQueue returns a queue of the transactions of the account, sending
+// them once Run is called.
+//
+// Parameters:
+// - opts: the options
+// Returns:
+// - *TxQueue: the queue
+func (account *Account) NewTxQueue(opts ...QueueOption) *TxQueue {
+	q := &TxQueue{
+		account:         account,
+		jobs:            make(chan *QueuedTx, DefaultQueueSize),
+		stopped:         make(chan struct{}),
+		retries:         DefaultQueueRetries,
+		retryDelay:      DefaultQueueRetryDelay,
+		receivedTimeout: DefaultReceivedTimeout,
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Submit adds calls to the queue, to be sent in one invoke transaction after
+// the transactions submitted before. The nonce is assigned by the queue,
+// nonce options being ignored.
+//
+// Parameters:
+// - ctx: the context, bounding the wait for room in the queue
+// - calls: the calls to execute
+// - opts: the execution options of the transaction
+// Returns:
+// - *QueuedTx: the transaction, to wait for
+// - error: ErrNoCalls, ErrQueueStopped, or the error of the context
+func (q *TxQueue) Submit(ctx context.Context, calls []rpc.FunctionCall, opts ...ExecuteOption) (*QueuedTx, error) {
+	if len(calls) == 0 {
+		return nil, ErrNoCalls
+	}
+	job := &QueuedTx{calls: calls, opts: opts, done: make(chan struct{}), stopped: q.stopped}
+	select {
+	case <-q.stopped:
+		return nil, ErrQueueStopped
+	default:
+	}
+	select {
+	case q.jobs <- job:
+		return job, nil
+	case <-q.stopped:
+		return nil, ErrQueueStopped
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Run sends the submitted transactions until ctx is done. The transactions
+// still in the queue then fail with ErrQueueStopped, as the submissions
+// after. It must not be called on a queue started with Start.
+//
+// Parameters:
+// - ctx: the context, stopping the queue
+// Returns:
+//
+//	none
+func (q *TxQueue) Run(ctx context.Context) {
+	q.run(ctx, nil)
+}
+
+// Start runs the queue in the background until Stop.
+//
+// Parameters:
+// - ctx: the context, unused
+// Returns:
+// - error: lifecycle.ErrAlreadyStarted or lifecycle.ErrStopped
+func (q *TxQueue) Start(ctx context.Context) error {
+	if _, err := q.Begin(); err != nil {
+		return err
+	}
+	stopping := q.Stopping()
+	q.Go(func(ctx context.Context) {
+		q.run(ctx, stopping)
+	})
+	return nil
+}
+
+// Stop stops sending the submitted transactions and waits for the
+// transaction in flight to be received. The transactions still in the queue
+// then fail with ErrQueueStopped, as the submissions after.
+//
+// Parameters:
+// - ctx: the context, bounding the wait of the transaction in flight
+// Returns:
+// - error: the error of the context if the wait timed out
+func (q *TxQueue) Stop(ctx context.Context) error {
+	return q.Shutdown(ctx, nil)
+}
+
+// run sends the submitted transactions until ctx is done or stopping is
+// closed, then fails the transactions still in the queue.
+//
+// Parameters:
+// - ctx: the context, stopping the queue
+// - stopping: the channel stopping the queue, nil to run until ctx is done
+// Returns:
+//
+//	none
+func (q *TxQueue) run(ctx context.Context, stopping <-chan struct{}) {
+	defer q.stop.Do(func() {
+		close(q.stopped)
+		for {
+			select {
+			case job := <-q.jobs:
+				job.finish(nil, ErrQueueStopped)
+			default:
+				return
+			}
+		}
+	})
+	for {
+		select {
+		case <-stopping:
+			return
+		default:
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-stopping:
+			return
+		case job := <-q.jobs:
+			job.finish(q.send(ctx, job))
+		}
+	}
+}
+
+// send sends a transaction of the queue with the next nonce and waits for it
+// to be received, retrying on failure. A transaction whose wait failed is not
+// sent again but polled again, keeping its nonce, unless the node rejected
+// it.
+//
+// Parameters:
+// - ctx: the context
+// - job: the transaction
+// Returns:
+// - *rpc.AddInvokeTransactionResponse: the response of the provider
+// - error: the last error, a *RevertError of the simulation not being retried
+func (q *TxQueue) send(ctx context.Context, job *QueuedTx) (*rpc.AddInvokeTransactionResponse, error) {
+	delay := q.retryDelay
+	var sent *rpc.AddInvokeTransactionResponse
+	for attempt := 0; ; attempt++ {
+		resp, err := q.attempt(ctx, job, sent)
+		if err == nil {
+			q.nonce = new(felt.Felt).Add(q.nonce, new(felt.Felt).SetUint64(1))
+			return resp, nil
+		}
+		var revert *RevertError
+		if attempt == q.retries || errors.As(err, &revert) || ctx.Err() != nil {
+			// the nonce after a transaction maybe never received is read again
+			q.nonce = nil
+			return resp, err
+		}
+		sent = resp
+		if sent == nil || errors.Is(err, ErrTransactionRejected) {
+			sent, q.nonce = nil, nil
+		}
+		select {
+		case <-ctx.Done():
+			q.nonce = nil
+			return resp, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// attempt sends a transaction of the queue with the next nonce, read first
+// if unknown, and waits for it to be received. A transaction already sent is
+// only waited for.
+//
+// Parameters:
+// - ctx: the context
+// - job: the transaction
+// - sent: the response of the provider of the transaction already sent, or nil
+// Returns:
+// - *rpc.AddInvokeTransactionResponse: the response of the provider, nil if the transaction was not sent
+// - error: an error of the nonce or of Execute, ErrTransactionRejected, or the error of the wait
+func (q *TxQueue) attempt(ctx context.Context, job *QueuedTx, sent *rpc.AddInvokeTransactionResponse) (*rpc.AddInvokeTransactionResponse, error) {
+	if sent != nil {
+		return sent, q.waitReceived(ctx, sent.TransactionHash)
+	}
+	if q.nonce == nil {
+		nonce, err := q.account.Nonce(ctx, rpc.WithBlockTag("pending"), q.account.AccountAddress)
+		if err != nil {
+			return nil, err
+		}
+		q.nonce = nonce
+	}
+	opts := make([]ExecuteOption, 0, len(job.opts)+2)
+	opts = append(opts, job.opts...)
+	resp, err := q.account.Execute(ctx, job.calls, append(opts, WithNonce(q.nonce), WithWaitForAcceptance(0))...)
+	if err != nil {
+		return nil, err
+	}
+	return resp, q.waitReceived(ctx, resp.TransactionHash)
+}
+
+// waitReceived polls the status of a transaction until the node received it.
+//
+// Parameters:
+// - ctx: the context
+// - txHash: the hash of the transaction
+// Returns:
+// - error: ErrTransactionRejected, the error of the context after the received timeout, or of the provider other than rpc.ErrHashNotFound
+func (q *TxQueue) waitReceived(ctx context.Context, txHash *felt.Felt) error {
+	ctx, cancel := context.WithTimeout(ctx, q.receivedTimeout)
+	defer cancel()
+	for {
+		status, err := q.account.GetTransactionStatus(ctx, txHash)
+		switch {
+		case err != nil && !errors.Is(err, rpc.ErrHashNotFound):
+			return err
+		case err != nil:
+		case status.FinalityStatus == rpc.TxnStatus_Rejected:
+			return fmt.Errorf("%w: %s", ErrTransactionRejected, status.FailureReason)
+		case status.FinalityStatus != "":
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitPollInterval):
+		}
+	}
+}
+
+// Wait waits for the transaction to be received by the node.
+//
+// Parameters:
+// - ctx: the context
+// Returns:
+// - *rpc.AddInvokeTransactionResponse: the response of the provider of the last attempt
+// - error: the error of the last attempt, ErrQueueStopped, or the error of the context
+func (tx *QueuedTx) Wait(ctx context.Context) (*rpc.AddInvokeTransactionResponse, error) {
+	select {
+	case <-tx.done:
+		return tx.resp, tx.err
+	case <-tx.stopped:
+		// the queue stopped, after sending it or without ever sending it
+		select {
+		case <-tx.done:
+			return tx.resp, tx.err
+		default:
+			return nil, ErrQueueStopped
+		}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// finish records the outcome of the transaction and wakes its waiters.
+//
+// Parameters:
+// - resp: the response of the provider
+// - err: the error
+// Returns:
+//
+//	none
+func (tx *QueuedTx) finish(resp *rpc.AddInvokeTransactionResponse, err error) {
+	tx.resp, tx.err = resp, err
+	close(tx.done)
+}
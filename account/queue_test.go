@@ -0,0 +1,181 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/lifecycle"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestTxQueue tests that a TxQueue sends the transactions in order with
+// consecutive nonces, waits for each to be received, reads the nonce again
+// and retries after a failure, and fails the transactions once stopped.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestTxQueue(t *testing.T) {
+	waitPollInterval = time.Millisecond
+	defer func() { waitPollInterval = time.Second }()
+	acc, provider := newMockAccount(t, 2)
+	call := rpc.FunctionCall{ContractAddress: utils.TestHexToFelt(t, "0xc0ffee"), EntryPointSelector: utils.SelectorTransfer}
+
+	var nonces []uint64
+	provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).Times(4).DoAndReturn(
+		func(_ context.Context, tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+			nonce := tx.(rpc.BroadcastInvokev1Txn).Nonce.Uint64()
+			nonces = append(nonces, nonce)
+			if len(nonces) == 3 {
+				return nil, rpc.ErrInvalidTransactionNonce
+			}
+			return &rpc.AddInvokeTransactionResponse{TransactionHash: utils.Uint64ToFelt(0x100 + nonce)}, nil
+		})
+	gomock.InOrder(
+		provider.EXPECT().Nonce(gomock.Any(), rpc.WithBlockTag("pending"), acc.AccountAddress).Return(utils.Uint64ToFelt(5), nil),
+		provider.EXPECT().Nonce(gomock.Any(), rpc.WithBlockTag("pending"), acc.AccountAddress).Return(utils.Uint64ToFelt(7), nil),
+	)
+	provider.EXPECT().GetTransactionStatus(gomock.Any(), utils.Uint64ToFelt(0x105)).Return(nil, rpc.ErrHashNotFound)
+	provider.EXPECT().GetTransactionStatus(gomock.Any(), gomock.Any()).Return(&rpc.TxnStatusResp{FinalityStatus: rpc.TxnStatus_Received}, nil).Times(3)
+
+	q := acc.NewTxQueue(WithQueueRetries(2, time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		q.Run(ctx)
+		close(stopped)
+	}()
+	var queued []*QueuedTx
+	for i := 0; i < 3; i++ {
+		tx, err := q.Submit(ctx, []rpc.FunctionCall{call}, WithMaxFee(utils.Uint64ToFelt(100)))
+		require.NoError(t, err)
+		queued = append(queued, tx)
+	}
+	for i, tx := range queued {
+		resp, err := tx.Wait(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(0x105+i), resp.TransactionHash.Uint64())
+	}
+	require.Equal(t, []uint64{5, 6, 7, 7}, nonces)
+
+	_, err := q.Submit(ctx, nil)
+	require.True(t, errors.Is(err, ErrNoCalls))
+	cancel()
+	<-stopped
+	_, err = q.Submit(context.Background(), []rpc.FunctionCall{call})
+	require.True(t, errors.Is(err, ErrQueueStopped))
+}
+
+// TestTxQueue_Wait tests that a TxQueue polls a sent transaction again,
+// without sending it again, when its wait fails, and sends it again with the
+// nonce read again when the node rejects it.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestTxQueue_Wait(t *testing.T) {
+	waitPollInterval = time.Millisecond
+	defer func() { waitPollInterval = time.Second }()
+	acc, provider := newMockAccount(t, 2)
+	call := rpc.FunctionCall{ContractAddress: utils.TestHexToFelt(t, "0xc0ffee"), EntryPointSelector: utils.SelectorTransfer}
+
+	var nonces []uint64
+	provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).Times(3).DoAndReturn(
+		func(_ context.Context, tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+			nonce := tx.(rpc.BroadcastInvokev1Txn).Nonce.Uint64()
+			nonces = append(nonces, nonce)
+			return &rpc.AddInvokeTransactionResponse{TransactionHash: utils.Uint64ToFelt(0x100 + uint64(len(nonces)))}, nil
+		})
+	gomock.InOrder(
+		provider.EXPECT().Nonce(gomock.Any(), rpc.WithBlockTag("pending"), acc.AccountAddress).Return(utils.Uint64ToFelt(5), nil),
+		provider.EXPECT().Nonce(gomock.Any(), rpc.WithBlockTag("pending"), acc.AccountAddress).Return(utils.Uint64ToFelt(6), nil),
+	)
+	failure := errors.New("connection reset")
+	gomock.InOrder(
+		provider.EXPECT().GetTransactionStatus(gomock.Any(), utils.Uint64ToFelt(0x101)).Return(nil, failure),
+		provider.EXPECT().GetTransactionStatus(gomock.Any(), utils.Uint64ToFelt(0x101)).Return(&rpc.TxnStatusResp{FinalityStatus: rpc.TxnStatus_Received}, nil),
+	)
+	gomock.InOrder(
+		provider.EXPECT().GetTransactionStatus(gomock.Any(), utils.Uint64ToFelt(0x102)).Return(&rpc.TxnStatusResp{FinalityStatus: rpc.TxnStatus_Rejected}, nil),
+		provider.EXPECT().GetTransactionStatus(gomock.Any(), utils.Uint64ToFelt(0x103)).Return(&rpc.TxnStatusResp{FinalityStatus: rpc.TxnStatus_Received}, nil),
+	)
+
+	q := acc.NewTxQueue(WithQueueRetries(2, time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+	opts := make([]ExecuteOption, 1, 2)
+	opts[0] = WithMaxFee(utils.Uint64ToFelt(100))
+	for _, hash := range []uint64{0x101, 0x103} {
+		tx, err := q.Submit(ctx, []rpc.FunctionCall{call}, opts...)
+		require.NoError(t, err)
+		resp, err := tx.Wait(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, hash, resp.TransactionHash.Uint64())
+	}
+	require.Equal(t, []uint64{5, 6, 6}, nonces)
+	// the options of the submission are not appended to in place
+	require.Nil(t, opts[:2][1])
+}
+
+// TestTxQueue_Lifecycle tests that a TxQueue started with Start sends the
+// submitted transactions, and that Stop waits for the transaction in flight
+// and fails the submissions after.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestTxQueue_Lifecycle(t *testing.T) {
+	waitPollInterval = time.Millisecond
+	defer func() { waitPollInterval = time.Second }()
+	acc, provider := newMockAccount(t, 2)
+	call := rpc.FunctionCall{ContractAddress: utils.TestHexToFelt(t, "0xc0ffee"), EntryPointSelector: utils.SelectorTransfer}
+
+	sending := make(chan struct{})
+	release := make(chan struct{})
+	provider.EXPECT().Nonce(gomock.Any(), rpc.WithBlockTag("pending"), acc.AccountAddress).Return(utils.Uint64ToFelt(5), nil)
+	provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+			close(sending)
+			<-release
+			return &rpc.AddInvokeTransactionResponse{TransactionHash: utils.Uint64ToFelt(0x105)}, nil
+		})
+	provider.EXPECT().GetTransactionStatus(gomock.Any(), utils.Uint64ToFelt(0x105)).Return(&rpc.TxnStatusResp{FinalityStatus: rpc.TxnStatus_Received}, nil)
+
+	q := acc.NewTxQueue()
+	require.NoError(t, q.Start(context.Background()))
+	require.True(t, errors.Is(q.Start(context.Background()), lifecycle.ErrAlreadyStarted))
+	first, err := q.Submit(context.Background(), []rpc.FunctionCall{call}, WithMaxFee(utils.Uint64ToFelt(100)))
+	require.NoError(t, err)
+	<-sending
+	second, err := q.Submit(context.Background(), []rpc.FunctionCall{call}, WithMaxFee(utils.Uint64ToFelt(100)))
+	require.NoError(t, err)
+
+	stopped := make(chan error)
+	go func() {
+		stopped <- q.Stop(context.Background())
+	}()
+	for q.State() != lifecycle.StateStopping {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	require.NoError(t, <-stopped)
+	resp, err := first.Wait(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(0x105), resp.TransactionHash.Uint64())
+	_, err = second.Wait(context.Background())
+	require.True(t, errors.Is(err, ErrQueueStopped))
+	_, err = q.Submit(context.Background(), []rpc.FunctionCall{call})
+	require.True(t, errors.Is(err, ErrQueueStopped))
+}
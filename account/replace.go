@@ -0,0 +1,120 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+var (
+	ErrNotReplaceable = errors.New("transaction can not be replaced")
+	ErrFeeNotBumped   = errors.New("max fee not above the fee of the replaced transaction")
+)
+
+// IsStuck reports whether a transaction sent by the account is stuck: still
+// received by the node and not included in a block a while after it was sent.
+//
+// Parameters:
+// - ctx: the context
+// - txHash: the hash of the transaction
+// - sentAt: the time the transaction was sent
+// - maxAge: the age after which a received transaction is stuck
+// Returns:
+// - bool: true if the transaction is received and older than maxAge
+// - error: an error if the status can not be read
+func (account *Account) IsStuck(ctx context.Context, txHash *felt.Felt, sentAt time.Time, maxAge time.Duration) (bool, error) {
+	if time.Since(sentAt) < maxAge {
+		return false, nil
+	}
+	status, err := account.GetTransactionStatus(ctx, txHash)
+	if err != nil {
+		return false, err
+	}
+	return status.FinalityStatus == rpc.TxnStatus_Received, nil
+}
+
+// Replace sends again the calldata of an invoke transaction of the account
+// not yet included in a block, with the same nonce and a higher fee, so that
+// the sequencer includes the replacement in place of the stuck transaction.
+//
+// The max fee of a V1 replacement is the max fee of the replaced transaction
+// multiplied by the fee multiplier, DefaultFeeMultiplier unless set with
+// WithFeeMultiplier, or the max fee of WithMaxFee, which must be higher. With
+// a tip or resource bounds the replacement is a V3 transaction, whose L1 gas
+// bounds are estimated unless set, as Execute does. Nonce options are
+// ignored.
+//
+// Parameters:
+// - ctx: the context
+// - txHash: the hash of the transaction to replace
+// - opts: the execution options of the replacement
+// Returns:
+// - *rpc.AddInvokeTransactionResponse: the response of the provider
+// - error: ErrNotReplaceable if the transaction is in a block or rejected, or not an invoke transaction of the account, ErrFeeNotBumped, or an error of the submission
+func (account *Account) Replace(ctx context.Context, txHash *felt.Felt, opts ...ExecuteOption) (resp *rpc.AddInvokeTransactionResponse, err error) {
+	ctx, span := account.startSpan(ctx, "Account.Replace")
+	defer func() {
+		var newHash *felt.Felt
+		if resp != nil {
+			newHash = resp.TransactionHash
+		}
+		endSpan(span, newHash, err)
+	}()
+
+	options := executeOptions{feeMultiplier: DefaultFeeMultiplier, blockID: rpc.WithBlockTag("pending"), queryVersion: true}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	status, err := account.GetTransactionStatus(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if status.FinalityStatus != rpc.TxnStatus_Received {
+		return nil, fmt.Errorf("%w: %s", ErrNotReplaceable, status.FinalityStatus)
+	}
+	tx, err := account.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	replaced, ok := tx.(rpc.InvokeTxnV1)
+	if !ok || replaced.SenderAddress == nil || !replaced.SenderAddress.Equal(account.AccountAddress) {
+		return nil, fmt.Errorf("%w: not an invoke transaction of the account", ErrNotReplaceable)
+	}
+	options.nonce = replaced.Nonce
+	if options.v3() {
+		return account.executeV3(ctx, replaced.Calldata, options)
+	}
+
+	// V3 transactions are returned without max fee, whose replacement is
+	// estimated
+	if replaced.MaxFee != nil && !replaced.MaxFee.IsZero() {
+		if options.maxFee == nil {
+			if options.maxFee, err = (rpc.FeeEstimate{OverallFee: replaced.MaxFee}).MaxFee(options.feeMultiplier); err != nil {
+				return nil, err
+			}
+		}
+		if options.maxFee.Cmp(replaced.MaxFee) <= 0 {
+			return nil, fmt.Errorf("%w: %s, replaced %s", ErrFeeNotBumped, options.maxFee, replaced.MaxFee)
+		}
+	}
+	maxFee := options.maxFee
+	if maxFee == nil {
+		maxFee = new(felt.Felt)
+	}
+	replacement := &rpc.InvokeTxnV1{
+		MaxFee:        maxFee,
+		Version:       rpc.TransactionV1,
+		Nonce:         replaced.Nonce,
+		Type:          rpc.TransactionType_Invoke,
+		SenderAddress: account.AccountAddress,
+		Calldata:      replaced.Calldata,
+	}
+	if err := account.SignInvokeTransaction(ctx, replacement); err != nil {
+		return nil, err
+	}
+	return account.executeV1(ctx, replacement, options)
+}
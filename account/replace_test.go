@@ -0,0 +1,79 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestReplace tests that Replace sends the calldata of a received transaction
+// with its nonce and a bumped max fee, and refuses to replace transactions in
+// a block, of other accounts or without a higher fee, and that IsStuck
+// reports received transactions older than the max age.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestReplace(t *testing.T) {
+	acc, provider := newMockAccount(t, 2)
+	ctx := context.Background()
+	txHash, newHash := utils.TestHexToFelt(t, "0xabc"), utils.TestHexToFelt(t, "0xdef")
+	calldata := []*felt.Felt{utils.Uint64ToFelt(1), utils.TestHexToFelt(t, "0xc0ffee")}
+	stuck := rpc.InvokeTxnV1{
+		MaxFee:        utils.Uint64ToFelt(100),
+		Version:       rpc.TransactionV1,
+		Nonce:         utils.Uint64ToFelt(7),
+		Type:          rpc.TransactionType_Invoke,
+		SenderAddress: acc.AccountAddress,
+		Calldata:      calldata,
+	}
+	received := &rpc.TxnStatusResp{FinalityStatus: rpc.TxnStatus_Received}
+
+	provider.EXPECT().GetTransactionStatus(ctx, txHash).Return(received, nil).AnyTimes()
+	provider.EXPECT().TransactionByHash(ctx, txHash).Return(stuck, nil).Times(2)
+	provider.EXPECT().AddInvokeTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+			replacement := tx.(rpc.BroadcastInvokev1Txn).InvokeTxnV1
+			require.Equal(t, uint64(150), replacement.MaxFee.Uint64())
+			require.Equal(t, stuck.Nonce, replacement.Nonce)
+			require.Equal(t, calldata, replacement.Calldata)
+			hash, err := acc.TransactionHashInvoke(replacement)
+			require.NoError(t, err)
+			signature, err := acc.Sign(ctx, hash)
+			require.NoError(t, err)
+			require.Equal(t, signature, replacement.Signature)
+			return &rpc.AddInvokeTransactionResponse{TransactionHash: newHash}, nil
+		})
+	resp, err := acc.Replace(ctx, txHash)
+	require.NoError(t, err)
+	require.Equal(t, newHash, resp.TransactionHash)
+	_, err = acc.Replace(ctx, txHash, WithMaxFee(utils.Uint64ToFelt(100)))
+	require.True(t, errors.Is(err, ErrFeeNotBumped))
+
+	stuckFor, err := acc.IsStuck(ctx, txHash, time.Now(), time.Minute)
+	require.NoError(t, err)
+	require.False(t, stuckFor)
+	stuckFor, err = acc.IsStuck(ctx, txHash, time.Now().Add(-time.Hour), time.Minute)
+	require.NoError(t, err)
+	require.True(t, stuckFor)
+
+	accepted, foreign := utils.TestHexToFelt(t, "0xacce"), utils.TestHexToFelt(t, "0xf0")
+	provider.EXPECT().GetTransactionStatus(ctx, accepted).Return(&rpc.TxnStatusResp{FinalityStatus: rpc.TxnStatus_Accepted_On_L2}, nil)
+	_, err = acc.Replace(ctx, accepted)
+	require.True(t, errors.Is(err, ErrNotReplaceable))
+	other := stuck
+	other.SenderAddress = utils.TestHexToFelt(t, "0xb0b")
+	provider.EXPECT().GetTransactionStatus(ctx, foreign).Return(received, nil)
+	provider.EXPECT().TransactionByHash(ctx, foreign).Return(other, nil)
+	_, err = acc.Replace(ctx, foreign)
+	require.True(t, errors.Is(err, ErrNotReplaceable))
+}
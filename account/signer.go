@@ -0,0 +1,29 @@
+package account
+
+import (
+	"context"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// Signer signs the hashes of an account whose signature is not a Stark
+// signature (r, s), e.g. the multi-felt signatures of accounts validating
+// secp256k1 or secp256r1 keys.
+type Signer interface {
+	// SignHash returns the signature of a hash, in the layout expected by
+	// the __validate__ of the account
+	SignHash(ctx context.Context, hash *felt.Felt) ([]*felt.Felt, error)
+}
+
+// WithSigner signs the transactions and messages of the account with a
+// Signer instead of the keystore, which can then be nil.
+//
+// Parameters:
+// - signer: the signer
+// Returns:
+// - Option: the option
+func WithSigner(signer Signer) Option {
+	return func(account *Account) {
+		account.signer = signer
+	}
+}
@@ -0,0 +1,96 @@
+package account
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// TracerName is the name of the OpenTelemetry tracer of the package.
+const TracerName = "github.com/xiang-xx/starknet.go/account"
+
+// Attributes of the spans of the account flows, in addition to those of rpc.
+const (
+	AttributeAccountAddress  = attribute.Key("starknet.account_address")
+	AttributeCalls           = attribute.Key("starknet.calls")
+	AttributeExecutionStatus = attribute.Key("starknet.execution_status")
+	AttributePolls           = attribute.Key("starknet.polls")
+)
+
+// Option configures an Account.
+type Option func(*Account)
+
+// WithTracerProvider records OpenTelemetry spans for Execute, EstimateFee
+// and WaitForTransactionReceipt. The requests of the provider are traced by
+// the provider, see rpc.WithTracerProvider.
+//
+// Parameters:
+// - tp: the tracer provider
+// Returns:
+// - Option: the option
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(account *Account) {
+		account.tracer = tp.Tracer(TracerName)
+	}
+}
+
+// startSpan starts a span of an account flow, or a non-recording span when
+// tracing is disabled.
+//
+// Parameters:
+// - ctx: the context
+// - name: the name of the span
+// - attrs: the attributes of the span
+// Returns:
+// - context.Context: the context of the span
+// - trace.Span: the span
+func (account *Account) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if account.tracer == nil {
+		return ctx, noop.Span{}
+	}
+	attrs = append(attrs, AttributeAccountAddress.String(account.AccountAddress.String()))
+	return account.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends a span, recording the error of the flow and the hash of the
+// transaction.
+//
+// Parameters:
+// - span: the span
+// - txHash: the hash of the transaction, nil if none
+// - err: the error of the flow
+// Returns:
+//
+//	none
+func endSpan(span trace.Span, txHash *felt.Felt, err error) {
+	if txHash != nil {
+		span.SetAttributes(rpc.AttributeTransactionHash.String(txHash.String()))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// blockIDAttribute returns the attribute of a block ID.
+//
+// Parameters:
+// - blockID: the block ID
+// Returns:
+// - attribute.KeyValue: the number, hash or tag of the block
+func blockIDAttribute(blockID rpc.BlockID) attribute.KeyValue {
+	switch {
+	case blockID.Number != nil:
+		return rpc.AttributeBlockID.String(strconv.FormatUint(*blockID.Number, 10))
+	case blockID.Hash != nil:
+		return rpc.AttributeBlockID.String(blockID.Hash.String())
+	}
+	return rpc.AttributeBlockID.String(blockID.Tag)
+}
@@ -0,0 +1,114 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var (
+	ErrClassNotDeclared = errors.New("class not declared")
+	ErrSameClass        = errors.New("account already has the class")
+)
+
+// AccountFlavor is the account class whose upgrade entrypoint a call is
+// encoded for.
+type AccountFlavor int
+
+const (
+	// FlavorOpenZeppelin is the OpenZeppelin account, whose upgrade takes the
+	// new class hash.
+	FlavorOpenZeppelin AccountFlavor = iota
+	// FlavorArgent is the Argent account, whose upgrade takes the new class
+	// hash and the calldata of its upgrade callback, empty.
+	FlavorArgent
+	// FlavorBraavos is the Braavos account, whose upgrade takes the new class
+	// hash.
+	FlavorBraavos
+)
+
+// UpgradeCall returns the call upgrading an account to a new class, the
+// upgrade entrypoint of the OpenZeppelin, Argent and Braavos accounts, which
+// replace their class with the replace_class syscall.
+//
+// Parameters:
+// - accountAddress: the address of the account
+// - newClassHash: the hash of the new class
+// - flavor: the class of the account, encoding the calldata
+// Returns:
+// - rpc.FunctionCall: the call
+func UpgradeCall(accountAddress, newClassHash *felt.Felt, flavor AccountFlavor) rpc.FunctionCall {
+	calldata := []*felt.Felt{newClassHash}
+	if flavor == FlavorArgent {
+		// the empty calldata of the upgrade callback
+		calldata = append(calldata, new(felt.Felt))
+	}
+	return rpc.FunctionCall{
+		ContractAddress:    accountAddress,
+		EntryPointSelector: utils.GetSelectorFromNameCached("upgrade"),
+		Calldata:           calldata,
+	}
+}
+
+// WithAccountFlavor encodes the upgrade call of Upgrade for the class of the
+// account, FlavorOpenZeppelin by default. Execute ignores it.
+//
+// Parameters:
+// - flavor: the class of the account
+// Returns:
+// - ExecuteOption: the option
+func WithAccountFlavor(flavor AccountFlavor) ExecuteOption {
+	return func(o *executeOptions) {
+		o.flavor = flavor
+	}
+}
+
+// Upgrade replaces the class of the account with a declared class and waits
+// for the receipt of the transaction. Nothing is sent if the class is not
+// declared or is already the class of the account. The upgrade call is
+// encoded for the flavor of WithAccountFlavor and the receipt polled at the
+// interval of WithPollInterval.
+//
+// Parameters:
+// - ctx: the context
+// - newClassHash: the hash of the new class
+// - opts: the execution options of the upgrade transaction
+// Returns:
+// - *rpc.Receipt: the receipt of the upgrade transaction
+// - error: ErrClassNotDeclared, ErrSameClass, an error of Execute, or a *RevertError along with the receipt if the upgrade reverts
+func (account *Account) Upgrade(ctx context.Context, newClassHash *felt.Felt, opts ...ExecuteOption) (*rpc.Receipt, error) {
+	var options executeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if _, err := account.Class(ctx, rpc.WithBlockTag("pending"), newClassHash); err != nil {
+		if errors.Is(err, rpc.ErrClassHashNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrClassNotDeclared, newClassHash)
+		}
+		return nil, err
+	}
+	current, err := account.ClassHashAt(ctx, rpc.WithBlockTag("pending"), account.AccountAddress)
+	if err != nil {
+		return nil, err
+	}
+	if current.Equal(newClassHash) {
+		return nil, fmt.Errorf("%w: %s", ErrSameClass, newClassHash)
+	}
+
+	resp, err := account.Execute(ctx, []rpc.FunctionCall{UpgradeCall(account.AccountAddress, newClassHash, options.flavor)}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := account.WaitForTransactionReceipt(ctx, resp.TransactionHash, options.receiptPollInterval())
+	if err != nil {
+		return nil, err
+	}
+	if receipt.ExecutionStatus() == rpc.TxnExecutionStatusREVERTED {
+		return receipt, &RevertError{Reason: receipt.RevertReason()}
+	}
+	return receipt, nil
+}
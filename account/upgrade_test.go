@@ -0,0 +1,83 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestUpgrade tests that Upgrade checks the new class before sending the
+// upgrade call, and reports a reverted upgrade.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestUpgrade(t *testing.T) {
+	acc, provider := newMockAccount(t, 2)
+	address := acc.AccountAddress
+
+	oldClass, newClass, undeclared := utils.TestHexToFelt(t, "0x1"), utils.TestHexToFelt(t, "0x2"), utils.TestHexToFelt(t, "0x3")
+	provider.EXPECT().Class(gomock.Any(), rpc.WithBlockTag("pending"), undeclared).Return(nil, rpc.ErrClassHashNotFound)
+	provider.EXPECT().Class(gomock.Any(), rpc.WithBlockTag("pending"), gomock.Any()).Return(&rpc.ContractClass{}, nil).Times(3)
+	provider.EXPECT().ClassHashAt(gomock.Any(), rpc.WithBlockTag("pending"), address).Return(oldClass, nil).Times(3)
+
+	_, err := acc.Upgrade(context.Background(), undeclared, WithPollInterval(time.Millisecond))
+	require.True(t, errors.Is(err, ErrClassNotDeclared))
+	_, err = acc.Upgrade(context.Background(), oldClass)
+	require.True(t, errors.Is(err, ErrSameClass))
+
+	txHash := utils.TestHexToFelt(t, "0xabc")
+	provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+			calldata := tx.(rpc.BroadcastInvokev1Txn).Calldata
+			require.Equal(t, UpgradeCall(address, newClass, FlavorArgent).EntryPointSelector, calldata[2])
+			require.Equal(t, []*felt.Felt{newClass, new(felt.Felt)}, calldata[len(calldata)-2:])
+			return &rpc.AddInvokeTransactionResponse{TransactionHash: txHash}, nil
+		}).Times(2)
+	gomock.InOrder(
+		provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).
+			Return(&rpc.Receipt{TransactionReceipt: rpc.InvokeTransactionReceipt{ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED}}, nil),
+		provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).
+			Return(&rpc.Receipt{TransactionReceipt: rpc.InvokeTransactionReceipt{ExecutionStatus: rpc.TxnExecutionStatusREVERTED, RevertReason: "invalid-implementation"}}, nil),
+	)
+	receipt, err := acc.Upgrade(context.Background(), newClass, WithAccountFlavor(FlavorArgent), WithPollInterval(time.Millisecond), WithNonce(utils.Uint64ToFelt(1)), WithMaxFee(utils.Uint64ToFelt(100)))
+	require.NoError(t, err)
+	require.Equal(t, rpc.TxnExecutionStatusSUCCEEDED, receipt.ExecutionStatus())
+
+	receipt, err = acc.Upgrade(context.Background(), newClass, WithAccountFlavor(FlavorArgent), WithPollInterval(time.Millisecond), WithNonce(utils.Uint64ToFelt(2)), WithMaxFee(utils.Uint64ToFelt(100)))
+	var revertErr *RevertError
+	require.True(t, errors.As(err, &revertErr))
+	require.Equal(t, "invalid-implementation", revertErr.Reason)
+	require.NotNil(t, receipt)
+}
+
+// TestUpgradeCall tests the calldata of the upgrade call of the OpenZeppelin,
+// Argent and Braavos accounts.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestUpgradeCall(t *testing.T) {
+	address, classHash := utils.TestHexToFelt(t, "0xacc"), utils.TestHexToFelt(t, "0x2")
+	for flavor, calldata := range map[AccountFlavor][]*felt.Felt{
+		FlavorOpenZeppelin: {classHash},
+		FlavorArgent:       {classHash, new(felt.Felt)},
+		FlavorBraavos:      {classHash},
+	} {
+		call := UpgradeCall(address, classHash, flavor)
+		require.Equal(t, address, call.ContractAddress)
+		require.Equal(t, utils.GetSelectorFromNameFelt("upgrade"), call.EntryPointSelector)
+		require.Equal(t, calldata, call.Calldata)
+	}
+}
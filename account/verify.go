@@ -0,0 +1,161 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/forks"
+	"github.com/xiang-xx/starknet.go/revert"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var (
+	ErrHashMismatch               = errors.New("transaction hash mismatch")
+	ErrSignatureValidationMissing = errors.New("account has no signature validation entrypoint")
+)
+
+// validMagic is the value returned by the is_valid_signature of the SNIP-6
+// accounts for valid signatures, the short string 'VALID'.
+var validMagic = new(felt.Felt).SetBytes([]byte("VALID"))
+
+// signatureEntrypoints are the entrypoints validating signatures, of the
+// SNIP-6 and Cairo 0 accounts and of the camel case accounts of OpenZeppelin.
+var signatureEntrypoints = []string{"is_valid_signature", "isValidSignature"}
+
+// TransactionHashAt calculates the hash of a transaction included in a block,
+// with the rules in force at the height of the block: transactions of a
+// version not yet activated at the block are rejected.
+//
+// Parameters:
+// - tx: the transaction, e.g. as returned by TransactionByHash
+// - blockNumber: the number of the block including the transaction
+// - table: the activations of the chains, forks.Default if nil
+// Returns:
+// - *felt.Felt: the transaction hash
+// - error: an error wrapping forks.ErrNotActive if the transaction version is not active at the block,
+// forks.ErrUnknownChain if the table has no activations for the chain of the account, or ErrTxnTypeUnSupported
+func (account *Account) TransactionHashAt(tx rpc.Transaction, blockNumber uint64, table *forks.Table) (*felt.Felt, error) {
+	if table == nil {
+		table = forks.Default
+	}
+	chainID := chainName(account.ChainId)
+	switch txn := tx.(type) {
+	case rpc.InvokeTxnV0, rpc.InvokeTxnV1:
+		return account.TransactionHashInvoke(txn)
+	case rpc.InvokeTxnV3:
+		if err := table.Require(chainID, forks.FeatureV3Transactions, blockNumber); err != nil {
+			return nil, err
+		}
+		return account.TransactionHashInvoke(txn)
+	case rpc.DeclareTxnV0, rpc.DeclareTxnV1:
+		return account.TransactionHashDeclare(txn)
+	case rpc.DeclareTxnV2:
+		if err := table.Require(chainID, forks.FeatureDeclareV2, blockNumber); err != nil {
+			return nil, err
+		}
+		return account.TransactionHashDeclare(txn)
+	case rpc.DeclareTxnV3:
+		if err := table.Require(chainID, forks.FeatureV3Transactions, blockNumber); err != nil {
+			return nil, err
+		}
+		return account.TransactionHashDeclare(txn)
+	case rpc.DeployAccountTxn:
+		address, err := account.PrecomputeAddress(&felt.Zero, txn.ContractAddressSalt, txn.ClassHash, txn.ConstructorCalldata)
+		if err != nil {
+			return nil, err
+		}
+		return account.TransactionHashDeployAccount(txn, address)
+	case rpc.DeployAccountTxnV3:
+		if err := table.Require(chainID, forks.FeatureV3Transactions, blockNumber); err != nil {
+			return nil, err
+		}
+		address, err := account.PrecomputeAddress(&felt.Zero, txn.ContractAddressSalt, txn.ClassHash, txn.ConstructorCalldata)
+		if err != nil {
+			return nil, err
+		}
+		return account.TransactionHashDeployAccount(txn, address)
+	}
+	return nil, ErrTxnTypeUnSupported
+}
+
+// VerifyTransactionHash checks the hash of a transaction included in a block,
+// with the rules in force at the height of the block.
+//
+// Parameters:
+// - tx: the transaction
+// - expected: the hash of the transaction, e.g. from the block
+// - blockNumber: the number of the block including the transaction
+// - table: the activations of the chains, forks.Default if nil
+// Returns:
+// - error: an error wrapping ErrHashMismatch if the hashes differ, or the error of TransactionHashAt
+func (account *Account) VerifyTransactionHash(tx rpc.Transaction, expected *felt.Felt, blockNumber uint64, table *forks.Table) error {
+	hash, err := account.TransactionHashAt(tx, blockNumber, table)
+	if err != nil {
+		return err
+	}
+	if !hash.Equal(expected) {
+		return fmt.Errorf("%w: computed %s, expected %s at block %d", ErrHashMismatch, hash, expected, blockNumber)
+	}
+	return nil
+}
+
+// chainName decodes a chain ID felt to its short string, e.g. "SN_MAIN".
+//
+// Parameters:
+// - chainID: the chain ID
+// Returns:
+// - string: the short string
+func chainName(chainID *felt.Felt) string {
+	if chainID == nil {
+		return ""
+	}
+	b := chainID.Bytes()
+	return strings.TrimLeft(string(b[:]), "\x00")
+}
+
+// VerifySignature checks a signature of a message hash against an account
+// deployed on chain, calling its is_valid_signature entrypoint, or
+// isValidSignature for accounts without it, on the pending block, e.g. to
+// authenticate the users of a login with Starknet. Cairo 1 accounts return
+// 'VALID' for valid signatures, Cairo 0 accounts return 1; accounts
+// returning anything else, or failing on the signature as Cairo 0 accounts
+// do, reject it. No account of the verifier is needed.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider calling the account
+// - address: the address of the account having signed
+// - msgHash: the signed hash, e.g. the typed data hash of the message
+// - signature: the signature
+// Returns:
+// - bool: true if the account accepts the signature
+// - error: ErrSignatureValidationMissing if the contract has neither entrypoint, or an error of the provider other than a contract error
+func VerifySignature(ctx context.Context, provider rpc.RpcProvider, address, msgHash *felt.Felt, signature []*felt.Felt) (bool, error) {
+	if provider == nil {
+		return false, ErrNoProvider
+	}
+	calldata := append([]*felt.Felt{msgHash, new(felt.Felt).SetUint64(uint64(len(signature)))}, signature...)
+	for _, entrypoint := range signatureEntrypoints {
+		result, err := provider.Call(ctx, rpc.FunctionCall{
+			ContractAddress:    address,
+			EntryPointSelector: utils.GetSelectorFromNameCached(entrypoint),
+			Calldata:           calldata,
+		}, rpc.WithBlockTag("pending"))
+		if err != nil {
+			if errors.Is(revert.FromError(err), revert.ErrEntrypointNotFound) {
+				continue
+			}
+			var rpcErr *rpc.RPCError
+			if errors.As(err, &rpcErr) && rpcErr.Code() == rpc.ErrContractError.Code() {
+				return false, nil
+			}
+			return false, err
+		}
+		return len(result) > 0 && (result[0].Equal(validMagic) || result[0].IsOne()), nil
+	}
+	return false, fmt.Errorf("%w: %s", ErrSignatureValidationMissing, address)
+}
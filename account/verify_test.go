@@ -0,0 +1,104 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/contracts"
+	"github.com/xiang-xx/starknet.go/forks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestVerifyTransactionHash tests that V3 transactions are only verified in
+// blocks where V3 transactions are active.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestVerifyTransactionHash(t *testing.T) {
+	acc, _ := newMockAccount(t, 2, WithChainID("SN_TEST"))
+
+	table := forks.NewTable()
+	table.Register("SN_TEST",
+		forks.Activation{Version: contracts.Version{Minor: 12, Patch: 3}, Block: 0},
+		forks.Activation{Version: contracts.Version{Minor: 13}, Block: 100},
+	)
+	tx := rpc.InvokeTxnV3{
+		Type:                  rpc.TransactionType_Invoke,
+		SenderAddress:         acc.AccountAddress,
+		Calldata:              []*felt.Felt{utils.Uint64ToFelt(1)},
+		Version:               rpc.TransactionV3,
+		Nonce:                 utils.Uint64ToFelt(1),
+		ResourceBounds:        rpc.ResourceBoundsMapping{L1Gas: rpc.ResourceBounds{MaxAmount: "0x100", MaxPricePerUnit: "0x10"}, L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"}},
+		Tip:                   "0x0",
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+	}
+	hash, err := acc.TransactionHashInvoke(tx)
+	require.NoError(t, err)
+
+	require.NoError(t, acc.VerifyTransactionHash(tx, hash, 100, table))
+	err = acc.VerifyTransactionHash(tx, hash, 99, table)
+	require.True(t, errors.Is(err, forks.ErrNotActive))
+	err = acc.VerifyTransactionHash(tx, utils.Uint64ToFelt(1), 100, table)
+	require.True(t, errors.Is(err, ErrHashMismatch))
+	_, err = acc.TransactionHashAt(tx, 100, forks.NewTable())
+	require.True(t, errors.Is(err, forks.ErrUnknownChain))
+}
+
+// TestVerifySignature tests that VerifySignature accepts the 'VALID' of
+// Cairo 1 accounts and the 1 of Cairo 0 accounts, falls back to
+// isValidSignature, and rejects signatures the account fails on.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestVerifySignature(t *testing.T) {
+	_, provider := newMockAccount(t, 2)
+	ctx := context.Background()
+	msgHash := utils.TestHexToFelt(t, "0x1234")
+	signature := []*felt.Felt{utils.Uint64ToFelt(1), utils.Uint64ToFelt(2)}
+	notFound := rpc.Err(rpc.InternalError, map[string]any{"revert_error": "Execution failed. Failure reason: 0x454e545259504f494e545f4e4f545f464f554e44 ('ENTRYPOINT_NOT_FOUND')."})
+
+	type response struct {
+		result []*felt.Felt
+		err    error
+	}
+	results := map[string]response{}
+	provider.EXPECT().Call(ctx, gomock.Any(), rpc.WithBlockTag("pending")).DoAndReturn(
+		func(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+			require.Equal(t, append([]*felt.Felt{msgHash, utils.Uint64ToFelt(2)}, signature...), call.Calldata)
+			r, ok := results[call.ContractAddress.String()+"/"+call.EntryPointSelector.String()]
+			if !ok {
+				return nil, notFound
+			}
+			return r.result, r.err
+		}).AnyTimes()
+	snake, camel := utils.GetSelectorFromNameFelt("is_valid_signature").String(), utils.GetSelectorFromNameFelt("isValidSignature").String()
+	results["0x1/"+snake] = response{result: []*felt.Felt{new(felt.Felt).SetBytes([]byte("VALID"))}}
+	results["0x2/"+snake] = response{result: []*felt.Felt{utils.Uint64ToFelt(1)}}
+	results["0x3/"+camel] = results["0x1/"+snake]
+	results["0x4/"+snake] = response{err: rpc.ErrContractError}
+	results["0x5/"+snake] = response{result: []*felt.Felt{utils.Uint64ToFelt(0)}}
+
+	for address, expected := range map[uint64]bool{1: true, 2: true, 3: true, 4: false, 5: false} {
+		valid, err := VerifySignature(ctx, provider, utils.Uint64ToFelt(address), msgHash, signature)
+		require.NoError(t, err)
+		require.Equal(t, expected, valid, address)
+	}
+	_, err := VerifySignature(ctx, provider, utils.Uint64ToFelt(6), msgHash, signature)
+	require.True(t, errors.Is(err, ErrSignatureValidationMissing))
+	_, err = VerifySignature(ctx, nil, utils.Uint64ToFelt(1), msgHash, signature)
+	require.Equal(t, ErrNoProvider, err)
+}
@@ -0,0 +1,154 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/abi"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// maxWaitBackoff bounds the interval the receipt is polled at by
+// ExecuteAndWait, as a multiple of waitPollInterval.
+const maxWaitBackoff = 16
+
+// ExecuteResult is the outcome of a transaction sent and waited for by
+// ExecuteAndWait.
+type ExecuteResult struct {
+	TransactionHash *felt.Felt
+	// Receipt is the receipt of the transaction in its block
+	Receipt *rpc.Receipt
+	// ActualFee is the fee charged for the transaction
+	ActualFee rpc.FeePayment
+	// Events are the events emitted by the transaction, in order
+	Events []ExecutedEvent
+	// Revert is the revert reason of a reverted transaction, nil otherwise
+	Revert *RevertError
+}
+
+// ExecutedEvent is an event emitted by a transaction, decoded when the ABI of
+// its emitter is given with WithEventABI or the event is registered in
+// abi.DefaultRegistry.
+type ExecutedEvent struct {
+	rpc.Event
+	// Name is the full name of the event, empty if the event is not decoded
+	Name string
+	// Values are the members of the event keyed by name, as abi.DecodeEvent
+	// returns them, nil if the event is not decoded
+	Values map[string]any
+}
+
+// WithEventABI decodes the events emitted by a contract in the result of
+// ExecuteAndWait with its ABI. Execute ignores it.
+//
+// Parameters:
+// - contract: the address of the contract
+// - parsed: the ABI of the class of the contract
+// Returns:
+// - ExecuteOption: the option
+func WithEventABI(contract *felt.Felt, parsed *abi.ABI) ExecuteOption {
+	return func(o *executeOptions) {
+		if o.eventABIs == nil {
+			o.eventABIs = map[felt.Felt]*abi.ABI{}
+		}
+		o.eventABIs[*contract] = parsed
+	}
+}
+
+// ExecuteAndWait sends the calls as Execute does and waits for the
+// transaction to be accepted on L2 in a block, polling the receipt with an
+// exponential backoff, from one second to 16 seconds. The wait is bounded by
+// the context, or by the timeout of WithWaitForAcceptance if set.
+//
+// The events of the contracts given with WithEventABI are decoded; the other
+// events are decoded with abi.DefaultRegistry, i.e. the standard events such
+// as ERC20 transfers, or returned undecoded.
+//
+// Parameters:
+// - ctx: the context
+// - calls: the calls to execute
+// - opts: the execution options
+// Returns:
+// - *ExecuteResult: the result, also along with the *RevertError of a reverted transaction
+// - error: an error of Execute, the error of the wait, or a *RevertError if the transaction reverted
+func (account *Account) ExecuteAndWait(ctx context.Context, calls []rpc.FunctionCall, opts ...ExecuteOption) (*ExecuteResult, error) {
+	var options executeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	resp, err := account.Execute(ctx, calls, append(opts, WithWaitForAcceptance(0))...)
+	if err != nil {
+		return nil, err
+	}
+	if options.waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.waitTimeout)
+		defer cancel()
+	}
+	receipt, err := account.waitForBlock(ctx, resp.TransactionHash)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExecuteResult{
+		TransactionHash: resp.TransactionHash,
+		Receipt:         receipt,
+		ActualFee:       receipt.ActualFee(),
+	}
+	for _, event := range receipt.Events() {
+		executed := ExecutedEvent{Event: event}
+		if parsed := options.eventABIs[*event.FromAddress]; parsed != nil {
+			if ev, values, err := abi.DecodeEvent(parsed, event.Keys, event.Data); err == nil {
+				executed.Name, executed.Values = ev.Name, values
+			}
+		}
+		if executed.Name == "" {
+			if ev, values, err := abi.DefaultRegistry.Decode(event.Keys, event.Data); err == nil {
+				executed.Name, executed.Values = ev.Name, values
+			}
+		}
+		result.Events = append(result.Events, executed)
+	}
+	if receipt.ExecutionStatus() == rpc.TxnExecutionStatusREVERTED {
+		result.Revert = &RevertError{Reason: receipt.RevertReason()}
+		return result, result.Revert
+	}
+	return result, nil
+}
+
+// waitForBlock polls the receipt of a transaction, doubling the interval up
+// to maxWaitBackoff times waitPollInterval, until the transaction is accepted
+// in a block.
+//
+// Parameters:
+// - ctx: the context, bounding the wait
+// - txHash: the hash of the transaction
+// Returns:
+// - *rpc.Receipt: the receipt
+// - error: the error of the context, or of the provider other than rpc.ErrHashNotFound
+func (account *Account) waitForBlock(ctx context.Context, txHash *felt.Felt) (*rpc.Receipt, error) {
+	interval := waitPollInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		receipt, err := account.TransactionReceipt(ctx, txHash)
+		if err != nil && !errors.Is(err, rpc.ErrHashNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			blockHash, _ := receipt.Block()
+			finality := receipt.FinalityStatus()
+			if blockHash != nil && (finality == rpc.TxnFinalityStatusAcceptedOnL2 || finality == rpc.TxnFinalityStatusAcceptedOnL1) {
+				return receipt, nil
+			}
+		}
+		if interval < maxWaitBackoff*waitPollInterval {
+			interval *= 2
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// Package airdrop builds the Merkle trees of the distributor contracts of
+// airdrops and allowlists on top of the merkle package: each recipient is a
+// leaf hashing its address and amount, the contract stores the root, and each
+// recipient claims with the proof of its leaf.
+//
+//	tree, err := airdrop.BuildTree(airdrop.Poseidon, recipients)
+//	// deploy the distributor with tree.Root, then for each recipient
+//	call, err := tree.ClaimCall(distributor, recipient.Address)
+package airdrop
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/merkle"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var (
+	ErrNoRecipients       = errors.New("airdrop: no recipients")
+	ErrDuplicateRecipient = errors.New("airdrop: duplicate recipient")
+	ErrUnknownRecipient   = errors.New("airdrop: unknown recipient")
+	ErrUnsupportedHash    = errors.New("airdrop: unsupported hash")
+)
+
+// Hash selects the hash of the leaves and of the nodes of a tree.
+type Hash int
+
+const (
+	// Pedersen hashes the leaves with the Pedersen hash chain of
+	// compute_hash_on_elements and the nodes with Pedersen
+	Pedersen Hash = iota
+	// Poseidon hashes the leaves with poseidon_hash_many and the nodes with
+	// Poseidon
+	Poseidon
+)
+
+// Recipient is a recipient of an airdrop.
+type Recipient struct {
+	Address *felt.Felt
+	// Amount is the amount claimable, in the base unit of the token
+	Amount *utils.Uint256
+}
+
+// Leaf returns the leaf of a recipient, the hash of its address and the low
+// and high limbs of its amount, as the distributors hash the caller of claim
+// and the claimed amount.
+//
+// Parameters:
+// - h: the hash of the tree
+// - r: the recipient
+// Returns:
+// - *felt.Felt: the leaf
+// - error: ErrUnsupportedHash, or an error if hashing fails
+func Leaf(h Hash, r Recipient) (*felt.Felt, error) {
+	elements := []*felt.Felt{r.Address, r.Amount.Low(), r.Amount.High()}
+	switch h {
+	case Pedersen:
+		return hash.ComputeHashOnElementsFelt(elements)
+	case Poseidon:
+		return crypto.PoseidonArray(elements...), nil
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnsupportedHash, h)
+}
+
+// Tree is the Merkle tree of the recipients of an airdrop.
+type Tree struct {
+	// Root is the root stored by the distributor
+	Root       *felt.Felt
+	Merkle     *merkle.Tree
+	Recipients []Recipient
+	hash       Hash
+	// index maps the addresses to the indexes of their leaves
+	index map[felt.Felt]int
+}
+
+// BuildTree builds the tree of the recipients of an airdrop, in their order.
+//
+// Parameters:
+// - h: the hash of the tree
+// - recipients: the recipients, each address once
+// Returns:
+// - *Tree: the tree
+// - error: ErrNoRecipients, ErrDuplicateRecipient, ErrUnsupportedHash, or an error if hashing fails
+func BuildTree(h Hash, recipients []Recipient) (*Tree, error) {
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+	t := &Tree{Recipients: recipients, hash: h, index: make(map[felt.Felt]int, len(recipients))}
+	leaves := make([]*felt.Felt, len(recipients))
+	for i, r := range recipients {
+		if _, ok := t.index[*r.Address]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateRecipient, r.Address)
+		}
+		t.index[*r.Address] = i
+		leaf, err := Leaf(h, r)
+		if err != nil {
+			return nil, err
+		}
+		leaves[i] = leaf
+	}
+	tree, err := merkle.NewTree(nodeHash(h), leaves...)
+	if err != nil {
+		return nil, err
+	}
+	t.Root, t.Merkle = tree.Root, tree
+	return t, nil
+}
+
+// ProofOf returns the recipient of an address and the proof of its leaf.
+//
+// Parameters:
+// - address: the address of the recipient
+// Returns:
+// - Recipient: the recipient
+// - []*felt.Felt: the proof
+// - error: ErrUnknownRecipient if the address is not a recipient
+func (t *Tree) ProofOf(address *felt.Felt) (Recipient, []*felt.Felt, error) {
+	i, ok := t.index[*address]
+	if !ok {
+		return Recipient{}, nil, fmt.Errorf("%w: %s", ErrUnknownRecipient, address)
+	}
+	proof, err := t.Merkle.Proof(i)
+	if err != nil {
+		return Recipient{}, nil, err
+	}
+	return t.Recipients[i], proof, nil
+}
+
+// ClaimCalldata returns the calldata of the claim of a recipient, as taken by
+// claim(amount: u256, proof: Span<felt252>): the low and high limbs of the
+// amount, then the length and the nodes of the proof.
+//
+// Parameters:
+// - address: the address of the recipient
+// Returns:
+// - []*felt.Felt: the calldata
+// - error: ErrUnknownRecipient if the address is not a recipient
+func (t *Tree) ClaimCalldata(address *felt.Felt) ([]*felt.Felt, error) {
+	r, proof, err := t.ProofOf(address)
+	if err != nil {
+		return nil, err
+	}
+	calldata := []*felt.Felt{r.Amount.Low(), r.Amount.High(), new(felt.Felt).SetUint64(uint64(len(proof)))}
+	return append(calldata, proof...), nil
+}
+
+// ClaimCall returns the claim call of a recipient, to be sent by the
+// recipient to the distributor.
+//
+// Parameters:
+// - distributor: the address of the distributor contract
+// - address: the address of the recipient
+// Returns:
+// - rpc.FunctionCall: the call
+// - error: ErrUnknownRecipient if the address is not a recipient
+func (t *Tree) ClaimCall(distributor, address *felt.Felt) (rpc.FunctionCall, error) {
+	calldata, err := t.ClaimCalldata(address)
+	if err != nil {
+		return rpc.FunctionCall{}, err
+	}
+	return rpc.FunctionCall{
+		ContractAddress:    distributor,
+		EntryPointSelector: utils.GetSelectorFromNameCached("claim"),
+		Calldata:           calldata,
+	}, nil
+}
+
+// Verify checks that a recipient may claim its amount with a proof against
+// the root of a tree, as the distributor does.
+//
+// Parameters:
+// - h: the hash of the tree
+// - root: the root of the tree
+// - r: the recipient
+// - proof: the proof of its leaf
+// Returns:
+// - bool: true if the proof leads from the leaf of the recipient to the root
+// - error: ErrUnsupportedHash, or an error if hashing fails
+func Verify(h Hash, root *felt.Felt, r Recipient, proof []*felt.Felt) (bool, error) {
+	leaf, err := Leaf(h, r)
+	if err != nil {
+		return false, err
+	}
+	return merkle.VerifyProof(nodeHash(h), root, leaf, proof), nil
+}
+
+// nodeHash returns the hash of the nodes of the trees of a hash.
+//
+// Parameters:
+// - h: the hash of the tree, valid
+// Returns:
+// - merkle.HashFunc: the hash of the nodes
+func nodeHash(h Hash) merkle.HashFunc {
+	if h == Poseidon {
+		return crypto.Poseidon
+	}
+	return crypto.Pedersen
+}
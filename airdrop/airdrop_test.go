@@ -0,0 +1,64 @@
+package airdrop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestBuildTree tests that the claims of the recipients of Pedersen and
+// Poseidon trees verify against the root, with the calldata of claim, and
+// that duplicate and unknown recipients are rejected.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestBuildTree(t *testing.T) {
+	var recipients []Recipient
+	for i := uint64(1); i <= 5; i++ {
+		recipients = append(recipients, Recipient{Address: utils.Uint64ToFelt(0xa0 + i), Amount: utils.Uint64ToUint256(i * 1000)})
+	}
+	distributor := utils.TestHexToFelt(t, "0xd15")
+
+	for _, h := range []Hash{Pedersen, Poseidon} {
+		tree, err := BuildTree(h, recipients)
+		require.NoError(t, err)
+		for _, r := range recipients {
+			recipient, proof, err := tree.ProofOf(r.Address)
+			require.NoError(t, err)
+			require.Equal(t, r, recipient)
+			valid, err := Verify(h, tree.Root, r, proof)
+			require.NoError(t, err)
+			require.True(t, valid)
+			valid, err = Verify(h, tree.Root, Recipient{Address: r.Address, Amount: utils.Uint64ToUint256(1)}, proof)
+			require.NoError(t, err)
+			require.False(t, valid)
+
+			call, err := tree.ClaimCall(distributor, r.Address)
+			require.NoError(t, err)
+			require.Equal(t, distributor, call.ContractAddress)
+			require.Equal(t, utils.GetSelectorFromNameFelt("claim"), call.EntryPointSelector)
+			require.Equal(t, append([]*felt.Felt{r.Amount.Low(), r.Amount.High(), utils.Uint64ToFelt(uint64(len(proof)))}, proof...), call.Calldata)
+		}
+		_, err = tree.ClaimCalldata(utils.Uint64ToFelt(0xb0b))
+		require.True(t, errors.Is(err, ErrUnknownRecipient))
+	}
+
+	pedersen, err := BuildTree(Pedersen, recipients)
+	require.NoError(t, err)
+	poseidon, err := BuildTree(Poseidon, recipients)
+	require.NoError(t, err)
+	require.NotEqual(t, pedersen.Root, poseidon.Root)
+
+	_, err = BuildTree(Pedersen, nil)
+	require.True(t, errors.Is(err, ErrNoRecipients))
+	_, err = BuildTree(Pedersen, append(recipients, recipients[0]))
+	require.True(t, errors.Is(err, ErrDuplicateRecipient))
+	_, err = BuildTree(Hash(9), recipients)
+	require.True(t, errors.Is(err, ErrUnsupportedHash))
+}
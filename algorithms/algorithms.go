@@ -0,0 +1,154 @@
+// Package algorithms reports the hash and serialization algorithms supported
+// by this build of starknet.go, so that services exchanging signed payloads
+// can check they compute the same hashes before trusting each other.
+//
+// Version follows semantic versioning: the minor version is bumped when an
+// algorithm is added and the major version when the output of a supported
+// algorithm changes.
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Version is the version of the set of supported algorithms.
+const Version = "1.0.0"
+
+var ErrIncompatible = errors.New("algorithms: incompatible")
+
+// Kind is a family of algorithms.
+type Kind string
+
+const (
+	// KindTransactionHash is a transaction hash formula, by transaction type
+	KindTransactionHash Kind = "transaction_hash"
+	// KindClassHash is a class hash formula, by class version prefix
+	KindClassHash Kind = "class_hash"
+	// KindTypedData is a SNIP-12 typed data hash, by revision
+	KindTypedData Kind = "typed_data"
+	// KindOutsideExecution is a SNIP-9 outside execution hash, by version
+	KindOutsideExecution Kind = "outside_execution"
+	// KindSerialization is a Cairo serialization format
+	KindSerialization Kind = "serialization"
+)
+
+// Algorithm is a supported algorithm.
+type Algorithm struct {
+	Kind Kind `json:"kind"`
+	// Name identifies the algorithm within its kind
+	Name string `json:"name"`
+	// Versions are the supported versions of the algorithm
+	Versions []string `json:"versions"`
+}
+
+// supported are the algorithms of this build.
+var supported = []Algorithm{
+	{Kind: KindTransactionHash, Name: "invoke", Versions: []string{"0", "1", "3"}},
+	{Kind: KindTransactionHash, Name: "declare", Versions: []string{"1", "2", "3"}},
+	{Kind: KindTransactionHash, Name: "deploy_account", Versions: []string{"1", "3"}},
+	{Kind: KindClassHash, Name: "sierra", Versions: []string{"CONTRACT_CLASS_V0.1.0"}},
+	{Kind: KindClassHash, Name: "casm", Versions: []string{"COMPILED_CLASS_V1"}},
+	{Kind: KindTypedData, Name: "snip12", Versions: []string{"0"}},
+	{Kind: KindOutsideExecution, Name: "snip9", Versions: []string{"1", "2"}},
+	{Kind: KindSerialization, Name: "cairo", Versions: []string{"1"}},
+	{Kind: KindSerialization, Name: "byte_array", Versions: []string{"1"}},
+}
+
+// Manifest describes the algorithms supported by a build.
+type Manifest struct {
+	Version    string      `json:"version"`
+	Algorithms []Algorithm `json:"algorithms"`
+}
+
+// Current returns the manifest of this build, e.g. to publish it on a health
+// endpoint.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - Manifest: the manifest
+func Current() Manifest {
+	algorithms := make([]Algorithm, len(supported))
+	for i, algorithm := range supported {
+		algorithms[i] = Algorithm{Kind: algorithm.Kind, Name: algorithm.Name, Versions: append([]string{}, algorithm.Versions...)}
+	}
+	return Manifest{Version: Version, Algorithms: algorithms}
+}
+
+// Supports returns whether the manifest supports a version of an algorithm.
+//
+// Parameters:
+// - kind: the kind of the algorithm
+// - name: the name of the algorithm
+// - version: the version of the algorithm
+// Returns:
+// - bool: true if the version is supported
+func (m Manifest) Supports(kind Kind, name, version string) bool {
+	for _, algorithm := range m.Algorithms {
+		if algorithm.Kind != kind || algorithm.Name != name {
+			continue
+		}
+		for _, v := range algorithm.Versions {
+			if v == version {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Supports returns whether this build supports a version of an algorithm.
+//
+// Parameters:
+// - kind: the kind of the algorithm
+// - name: the name of the algorithm
+// - version: the version of the algorithm
+// Returns:
+// - bool: true if the version is supported
+func Supports(kind Kind, name, version string) bool {
+	return Current().Supports(kind, name, version)
+}
+
+// Compatible checks that the manifest of another service shares the major
+// version of this build and supports the algorithms it requires.
+//
+// Parameters:
+// - other: the manifest of the other service
+// - required: the algorithms the payloads exchanged with the service use
+// Returns:
+// - error: ErrIncompatible, wrapped with the differences, if the manifests are incompatible
+func Compatible(other Manifest, required ...Algorithm) error {
+	if major(other.Version) != major(Version) {
+		return fmt.Errorf("%w: version %s, this build has %s", ErrIncompatible, other.Version, Version)
+	}
+	current := Current()
+	var missing []string
+	for _, algorithm := range required {
+		for _, version := range algorithm.Versions {
+			for _, m := range []Manifest{current, other} {
+				if !m.Supports(algorithm.Kind, algorithm.Name, version) {
+					missing = append(missing, fmt.Sprintf("%s %s %s (manifest %s)", algorithm.Kind, algorithm.Name, version, m.Version))
+				}
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: unsupported %s", ErrIncompatible, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// major returns the major component of a semantic version.
+//
+// Parameters:
+// - version: the version
+// Returns:
+// - string: the major version
+func major(version string) string {
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	return major
+}
@@ -0,0 +1,36 @@
+package algorithms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/test-go/testify/require"
+)
+
+// TestCompatible tests the compatibility checks between manifests.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestCompatible(t *testing.T) {
+	require.True(t, Supports(KindTransactionHash, "invoke", "3"))
+	require.False(t, Supports(KindTransactionHash, "declare", "0"))
+
+	invokeV3 := Algorithm{Kind: KindTransactionHash, Name: "invoke", Versions: []string{"3"}}
+	require.NoError(t, Compatible(Current(), invokeV3))
+
+	older := Manifest{Version: "1.0.0", Algorithms: []Algorithm{{Kind: KindTransactionHash, Name: "invoke", Versions: []string{"1"}}}}
+	require.NoError(t, Compatible(older, Algorithm{Kind: KindTransactionHash, Name: "invoke", Versions: []string{"1"}}))
+	require.True(t, errors.Is(Compatible(older, invokeV3), ErrIncompatible))
+
+	next := Current()
+	next.Version = "2.0.0"
+	require.True(t, errors.Is(Compatible(next), ErrIncompatible))
+
+	// the manifest returned by Current is a copy
+	current := Current()
+	current.Algorithms[0].Versions[0] = "9"
+	require.True(t, Supports(KindTransactionHash, "invoke", "0"))
+}
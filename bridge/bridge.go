@@ -0,0 +1,205 @@
+// Package bridge quotes the cost of L1 to L2 deposits. A deposit is an L1
+// transaction sending a message to L2: the depositor pays the L1 gas of the
+// transaction and, as the value of the message, the L2 fee of the l1_handler
+// executing it.
+//
+// The package also decodes the messages logged by the Starknet core contract,
+// computes the hashes identifying them on L1 and L2, and computes the hashes
+// of the L2 to L1 messages consumed by withdrawals.
+package bridge
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// DefaultDepositGas is the L1 gas of a StarkGate deposit, used for deposits
+// that do not set their own L1 gas.
+const DefaultDepositGas = 150_000
+
+var ErrNoGasPrice = errors.New("bridge: missing L1 gas price")
+
+// Deposit is an L1 to L2 message to quote.
+type Deposit struct {
+	// Message is the message sent to L2
+	Message rpc.MsgFromL1
+	// L1Gas is the gas of the L1 transaction, the calculator default when zero
+	L1Gas uint64
+}
+
+// Quote is the cost of a deposit, in wei.
+type Quote struct {
+	// L1Gas is the gas of the L1 transaction
+	L1Gas uint64
+	// L1GasPrice is the L1 gas price the quote is computed with
+	L1GasPrice *big.Int
+	// L1Fee is the cost of the L1 transaction, L1Gas * L1GasPrice
+	L1Fee *big.Int
+	// L2Estimate is the estimate of the l1_handler returned by the provider
+	L2Estimate *rpc.FeeEstimate
+	// MessageFee is the value to send with the message to pay the L2 fee
+	MessageFee *big.Int
+	// Total is the cost of the deposit, L1Fee + MessageFee
+	Total *big.Int
+}
+
+// Calculator quotes deposits with a provider and an L1 gas price.
+type Calculator struct {
+	provider      rpc.RpcProvider
+	l1GasPrice    *big.Int
+	defaultL1Gas  uint64
+	feeMultiplier float64
+	blockID       rpc.BlockID
+}
+
+// Option configures a Calculator.
+type Option func(*Calculator)
+
+// WithDefaultL1Gas sets the L1 gas of the deposits that do not set their own.
+// It defaults to DefaultDepositGas.
+//
+// Parameters:
+// - gas: the L1 gas
+// Returns:
+// - Option: the option
+func WithDefaultL1Gas(gas uint64) Option {
+	return func(c *Calculator) {
+		c.defaultL1Gas = gas
+	}
+}
+
+// WithFeeMultiplier sets the factor applied to the estimated L2 fee to get
+// the message fee, leaving a margin for the L2 gas price to rise before the
+// message is consumed. It defaults to 1.
+//
+// Parameters:
+// - multiplier: the factor
+// Returns:
+// - Option: the option
+func WithFeeMultiplier(multiplier float64) Option {
+	return func(c *Calculator) {
+		c.feeMultiplier = multiplier
+	}
+}
+
+// WithBlockID sets the block the L2 fee is estimated in. It defaults to the
+// latest block.
+//
+// Parameters:
+// - blockID: the block
+// Returns:
+// - Option: the option
+func WithBlockID(blockID rpc.BlockID) Option {
+	return func(c *Calculator) {
+		c.blockID = blockID
+	}
+}
+
+// NewCalculator creates a Calculator.
+//
+// Parameters:
+// - provider: the provider estimating the L2 fee
+// - l1GasPrice: the L1 gas price, in wei
+// - opts: the calculator options
+// Returns:
+// - *Calculator: the calculator
+// - error: an error if the L1 gas price is missing
+func NewCalculator(provider rpc.RpcProvider, l1GasPrice *big.Int, opts ...Option) (*Calculator, error) {
+	if l1GasPrice == nil {
+		return nil, ErrNoGasPrice
+	}
+	c := &Calculator{
+		provider:      provider,
+		l1GasPrice:    new(big.Int).Set(l1GasPrice),
+		defaultL1Gas:  DefaultDepositGas,
+		feeMultiplier: 1,
+		blockID:       rpc.WithBlockTag("latest"),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// SetL1GasPrice updates the L1 gas price of the following quotes.
+//
+// Parameters:
+// - l1GasPrice: the L1 gas price, in wei
+// Returns:
+//
+//	none
+func (c *Calculator) SetL1GasPrice(l1GasPrice *big.Int) {
+	c.l1GasPrice = new(big.Int).Set(l1GasPrice)
+}
+
+// Quote quotes a deposit.
+//
+// Parameters:
+// - ctx: the context
+// - deposit: the deposit
+// Returns:
+// - *Quote: the cost of the deposit
+// - error: an error if the L2 fee can not be estimated
+func (c *Calculator) Quote(ctx context.Context, deposit Deposit) (*Quote, error) {
+	estimate, err := c.provider.EstimateMessageFee(ctx, deposit.Message, c.blockID)
+	if err != nil {
+		return nil, err
+	}
+	gas := deposit.L1Gas
+	if gas == 0 {
+		gas = c.defaultL1Gas
+	}
+	l1Fee := new(big.Int).Mul(new(big.Int).SetUint64(gas), c.l1GasPrice)
+	messageFee := multiply(utils.FeltToBigInt(estimate.OverallFee), c.feeMultiplier)
+	return &Quote{
+		L1Gas:      gas,
+		L1GasPrice: new(big.Int).Set(c.l1GasPrice),
+		L1Fee:      l1Fee,
+		L2Estimate: estimate,
+		MessageFee: messageFee,
+		Total:      new(big.Int).Add(l1Fee, messageFee),
+	}, nil
+}
+
+// QuoteBatch quotes several deposits, e.g. the tokens offered by a bridge UI.
+//
+// Parameters:
+// - ctx: the context
+// - deposits: the deposits
+// Returns:
+// - []*Quote: the costs of the deposits, in order
+// - error: an error if the L2 fee of a deposit can not be estimated
+func (c *Calculator) QuoteBatch(ctx context.Context, deposits []Deposit) ([]*Quote, error) {
+	quotes := make([]*Quote, len(deposits))
+	for i, deposit := range deposits {
+		quote, err := c.Quote(ctx, deposit)
+		if err != nil {
+			return nil, err
+		}
+		quotes[i] = quote
+	}
+	return quotes, nil
+}
+
+// multiply multiplies an amount by a factor, rounding up.
+//
+// Parameters:
+// - amount: the amount
+// - multiplier: the factor
+// Returns:
+// - *big.Int: the multiplied amount
+func multiply(amount *big.Int, multiplier float64) *big.Int {
+	if multiplier == 1 {
+		return amount
+	}
+	product := new(big.Float).Mul(new(big.Float).SetInt(amount), big.NewFloat(multiplier))
+	result, accuracy := product.Int(nil)
+	if accuracy == big.Below {
+		result.Add(result, big.NewInt(1))
+	}
+	return result
+}
@@ -0,0 +1,169 @@
+package bridge
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestCalculator_QuoteBatch tests that a quote adds the L1 gas cost to the
+// message fee, with the default and per-deposit L1 gas.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestCalculator_QuoteBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+
+	first := rpc.MsgFromL1{FromAddress: "0xae0ee0a63a2ce6baeeffe56e7714fb4efe48d419", ToAddress: utils.TestHexToFelt(t, "0x73314940630fd6dcda0d772d4c972c4e0a9946bef9dabf4ef84eda8ef542b82")}
+	second := rpc.MsgFromL1{FromAddress: "0xae0ee0a63a2ce6baeeffe56e7714fb4efe48d419", ToAddress: utils.TestHexToFelt(t, "0x1")}
+	provider.EXPECT().EstimateMessageFee(gomock.Any(), first, rpc.WithBlockTag("latest")).
+		Return(&rpc.FeeEstimate{OverallFee: utils.Uint64ToFelt(1000)}, nil)
+	provider.EXPECT().EstimateMessageFee(gomock.Any(), second, rpc.WithBlockTag("latest")).
+		Return(&rpc.FeeEstimate{OverallFee: utils.Uint64ToFelt(3)}, nil)
+
+	_, err := NewCalculator(provider, nil)
+	require.Equal(t, ErrNoGasPrice, err)
+	calculator, err := NewCalculator(provider, big.NewInt(10), WithDefaultL1Gas(100), WithFeeMultiplier(1.5))
+	require.NoError(t, err)
+
+	quotes, err := calculator.QuoteBatch(context.Background(), []Deposit{{Message: first}, {Message: second, L1Gas: 7}})
+	require.NoError(t, err)
+	require.Len(t, quotes, 2)
+
+	require.Equal(t, uint64(100), quotes[0].L1Gas)
+	require.Equal(t, big.NewInt(1000), quotes[0].L1Fee)
+	require.Equal(t, big.NewInt(1500), quotes[0].MessageFee)
+	require.Equal(t, big.NewInt(2500), quotes[0].Total)
+
+	require.Equal(t, uint64(7), quotes[1].L1Gas)
+	require.Equal(t, big.NewInt(70), quotes[1].L1Fee)
+	require.Equal(t, big.NewInt(5), quotes[1].MessageFee)
+	require.Equal(t, big.NewInt(75), quotes[1].Total)
+
+	provider.EXPECT().EstimateMessageFee(gomock.Any(), first, rpc.WithBlockTag("latest")).Return(nil, errors.New("unavailable"))
+	_, err = calculator.QuoteBatch(context.Background(), []Deposit{{Message: first}})
+	require.Error(t, err)
+}
+
+// testMessage returns the message of the starknet.js test vectors of the L1
+// to L2 message hashes.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+// - *L1Message: the message
+func testMessage(t *testing.T) *L1Message {
+	return &L1Message{
+		MsgFromL1: rpc.MsgFromL1{
+			FromAddress: "0x8453fc6cd1bcfe8d4dfc069c400b433054d47bdc",
+			ToAddress:   utils.TestHexToFelt(t, "0x04c5772d1914fe6ce891b64eb35bf3522aeae1315647314aac58b01137607f3f"),
+			Selector:    utils.TestHexToFelt(t, "0x01b64b1b3b690b43b9b514fb81377518f4039cd3e4f4914d8a6bdf01d679fb19"),
+			Payload: []*felt.Felt{
+				utils.Uint64ToFelt(4543560),
+				utils.TestHexToFelt(t, "0x914f021563b57a5f785b63661c709da629f3508c"),
+				utils.TestHexToFelt(t, "0x07a75bbfece99f70a4862093d16124b5c179b94640e615e9d5384d7e1d463549"),
+				utils.Uint64ToFelt(9000000000000000),
+				new(felt.Felt),
+			},
+		},
+		Nonce: utils.Uint64ToFelt(8288),
+		Fee:   big.NewInt(0),
+	}
+}
+
+// TestParseLogMessageToL2 tests the decoding of a LogMessageToL2 event and
+// the hashes of its message.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestParseLogMessageToL2(t *testing.T) {
+	msg := testMessage(t)
+	word := func(x *big.Int) []byte { return x.FillBytes(make([]byte, 32)) }
+	topics := [][]byte{
+		LogMessageToL2Topic,
+		word(utils.HexToBN(msg.FromAddress)),
+		word(utils.FeltToBigInt(msg.ToAddress)),
+		word(utils.FeltToBigInt(msg.Selector)),
+	}
+	data := append(word(big.NewInt(0x60)), word(big.NewInt(8288))...)
+	data = append(data, word(big.NewInt(1000))...)
+	data = append(data, word(big.NewInt(int64(len(msg.Payload))))...)
+	for _, element := range msg.Payload {
+		data = append(data, word(utils.FeltToBigInt(element))...)
+	}
+
+	parsed, err := ParseLogMessageToL2(topics, data)
+	require.NoError(t, err)
+	require.Equal(t, msg.FromAddress, parsed.FromAddress)
+	require.Equal(t, msg.Payload, parsed.Payload)
+	require.Equal(t, big.NewInt(1000), parsed.Fee)
+	require.Equal(t, "2e350fa9d830482605cb68be4fdb9f0cb3e1f95a0c51623ac1a5d1bd997c2090", hex.EncodeToString(parsed.Hash()))
+	txHash, err := parsed.TransactionHash("SN_SEPOLIA")
+	require.NoError(t, err)
+	require.Equal(t, "0x67d959200d65d4ad293aa4b0da21bb050a1f669bce37d215c6edbf041269c07", txHash.String())
+
+	_, err = ParseLogMessageToL2(topics, data[:len(data)-1])
+	require.True(t, errors.Is(err, ErrMalformedLog), "unexpected error %v", err)
+	_, err = ParseLogMessageToL2(topics[:3], data)
+	require.Equal(t, ErrNotLogMessageToL2, err)
+}
+
+// TestL1Message_Track tests that the l1_handler transaction of a message is
+// watched by its hash.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestL1Message_Track(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	msg := testMessage(t)
+	updates := make(chan rpc.TxnStatusUpdate)
+	provider.EXPECT().SubscribeTransactionStatus(gomock.Any(), utils.TestHexToFelt(t, "0x67d959200d65d4ad293aa4b0da21bb050a1f669bce37d215c6edbf041269c07")).
+		Return((<-chan rpc.TxnStatusUpdate)(updates), nil)
+
+	tracked, err := msg.Track(context.Background(), provider, "SN_SEPOLIA")
+	require.NoError(t, err)
+	require.True(t, tracked == (<-chan rpc.TxnStatusUpdate)(updates))
+}
+
+// TestL2MessageHash tests the hash of an L2 to L1 message against its
+// abi.encodePacked encoding.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestL2MessageHash(t *testing.T) {
+	msg := rpc.MsgToL1{
+		FromAddress: utils.TestHexToFelt(t, "0x73314940630fd6dcda0d772d4c972c4e0a9946bef9dabf4ef84eda8ef542b82"),
+		ToAddress:   utils.TestHexToFelt(t, "0xae0ee0a63a2ce6baeeffe56e7714fb4efe48d419"),
+		Payload:     []*felt.Felt{new(felt.Felt), utils.Uint64ToFelt(7)},
+	}
+	encoded, err := hex.DecodeString("073314940630fd6dcda0d772d4c972c4e0a9946bef9dabf4ef84eda8ef542b82" +
+		"000000000000000000000000ae0ee0a63a2ce6baeeffe56e7714fb4efe48d419" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000007")
+	require.NoError(t, err)
+	require.Equal(t, utils.Keccak256(encoded), L2MessageHash(msg))
+}
@@ -0,0 +1,213 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// LogMessageToL2Topic is the topic of the LogMessageToL2 event of the
+// Starknet core contract.
+var LogMessageToL2Topic = utils.Keccak256([]byte("LogMessageToL2(address,uint256,uint256,uint256[],uint256,uint256)"))
+
+var (
+	ErrNotLogMessageToL2 = errors.New("bridge: not a LogMessageToL2 event")
+	ErrMalformedLog      = errors.New("bridge: malformed LogMessageToL2 event")
+)
+
+var prefixL1Handler = new(felt.Felt).SetBytes([]byte("l1_handler"))
+
+// L1Message is a message sent to L2 by the Starknet core contract, as logged
+// by its LogMessageToL2 event.
+type L1Message struct {
+	rpc.MsgFromL1
+	// Nonce is the nonce of the message in the core contract
+	Nonce *felt.Felt
+	// Fee is the value paid with the message, in wei
+	Fee *big.Int
+}
+
+// ParseLogMessageToL2 parses a LogMessageToL2 event of the Starknet core
+// contract, from the topics and the data of its Ethereum log.
+//
+// Parameters:
+// - topics: the topics of the log: the event topic, the L1 sender, the L2 recipient and the selector
+// - data: the data of the log: the ABI encoding of the payload, the nonce and the fee
+// Returns:
+// - *L1Message: the message
+// - error: ErrNotLogMessageToL2 for another event, or ErrMalformedLog if the log can not be decoded
+func ParseLogMessageToL2(topics [][]byte, data []byte) (*L1Message, error) {
+	if len(topics) != 4 || string(topics[0]) != string(LogMessageToL2Topic) {
+		return nil, ErrNotLogMessageToL2
+	}
+	word := func(i int) (*big.Int, error) {
+		if i < 0 || len(data) < (i+1)*32 {
+			return nil, fmt.Errorf("%w: no word %d in %d bytes", ErrMalformedLog, i, len(data))
+		}
+		return new(big.Int).SetBytes(data[i*32 : (i+1)*32]), nil
+	}
+	offset, err := word(0)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := word(1)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := word(2)
+	if err != nil {
+		return nil, err
+	}
+	if !offset.IsInt64() || offset.Int64()%32 != 0 {
+		return nil, fmt.Errorf("%w: payload offset %s", ErrMalformedLog, offset)
+	}
+	start := int(offset.Int64() / 32)
+	length, err := word(start)
+	if err != nil {
+		return nil, err
+	}
+	if !length.IsInt64() || len(data) < (start+1+int(length.Int64()))*32 {
+		return nil, fmt.Errorf("%w: payload of %s words in %d bytes", ErrMalformedLog, length, len(data))
+	}
+	payload := make([]*felt.Felt, length.Int64())
+	for i := range payload {
+		element, _ := word(start + 1 + i)
+		payload[i] = utils.BigIntToFelt(element)
+	}
+	return &L1Message{
+		MsgFromL1: rpc.MsgFromL1{
+			FromAddress: fmt.Sprintf("0x%040x", new(big.Int).SetBytes(topics[1])),
+			ToAddress:   new(felt.Felt).SetBytes(topics[2]),
+			Selector:    new(felt.Felt).SetBytes(topics[3]),
+			Payload:     payload,
+		},
+		Nonce: utils.BigIntToFelt(nonce),
+		Fee:   fee,
+	}, nil
+}
+
+// Hash calculates the hash of the message in the Starknet core contract,
+// keccak256(from_address, to_address, nonce, selector, len(payload), payload),
+// which identifies the message in the core contract and in
+// starknet_getMessagesStatus.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the 32-byte hash
+func (m *L1Message) Hash() []byte {
+	words := []*big.Int{
+		utils.HexToBN(m.FromAddress),
+		utils.FeltToBigInt(m.ToAddress),
+		utils.FeltToBigInt(m.Nonce),
+		utils.FeltToBigInt(m.Selector),
+		big.NewInt(int64(len(m.Payload))),
+	}
+	for _, element := range m.Payload {
+		words = append(words, utils.FeltToBigInt(element))
+	}
+	return keccakWords(words)
+}
+
+// TransactionHash calculates the hash of the l1_handler transaction executing
+// the message on L2.
+//
+// Parameters:
+// - chainID: the chain ID of the L2, e.g. "SN_MAIN"
+// Returns:
+// - *felt.Felt: the hash of the l1_handler transaction
+// - error: an error if the hash can not be calculated
+func (m *L1Message) TransactionHash(chainID string) (*felt.Felt, error) {
+	from, err := utils.HexToFelt(m.FromAddress)
+	if err != nil {
+		return nil, err
+	}
+	calldataHash, err := hash.ComputeHashOnElementsFelt(append([]*felt.Felt{from}, m.Payload...))
+	if err != nil {
+		return nil, err
+	}
+	return hash.CalculateTransactionHashCommon(
+		prefixL1Handler,
+		new(felt.Felt),
+		m.ToAddress,
+		m.Selector,
+		calldataHash,
+		new(felt.Felt),
+		new(felt.Felt).SetBytes([]byte(chainID)),
+		[]*felt.Felt{m.Nonce},
+	)
+}
+
+// EstimateFee estimates the L2 fee of the l1_handler executing the message.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider estimating the fee
+// - blockID: the block the fee is estimated in
+// Returns:
+// - *rpc.FeeEstimate: the estimate
+// - error: an error if the fee can not be estimated
+func (m *L1Message) EstimateFee(ctx context.Context, provider rpc.RpcProvider, blockID rpc.BlockID) (*rpc.FeeEstimate, error) {
+	return provider.EstimateMessageFee(ctx, m.MsgFromL1, blockID)
+}
+
+// Track watches the status of the l1_handler transaction executing the
+// message, until it is accepted on L1 or rejected.
+//
+// Parameters:
+// - ctx: the context, cancelling the watch
+// - provider: the provider watching the transaction
+// - chainID: the chain ID of the L2, e.g. "SN_MAIN"
+// Returns:
+// - <-chan rpc.TxnStatusUpdate: the status transitions of the transaction
+// - error: an error if the watch can not start
+func (m *L1Message) Track(ctx context.Context, provider rpc.RpcProvider, chainID string) (<-chan rpc.TxnStatusUpdate, error) {
+	txHash, err := m.TransactionHash(chainID)
+	if err != nil {
+		return nil, err
+	}
+	return provider.SubscribeTransactionStatus(ctx, txHash)
+}
+
+// L2MessageHash calculates the hash of an L2 to L1 message, as consumed on L1
+// with consumeMessageFromL2 once the block sending it is proven,
+// keccak256(from_address, to_address, len(payload), payload).
+//
+// Parameters:
+// - msg: the message, e.g. from the receipt of a withdrawal
+// Returns:
+// - []byte: the 32-byte hash
+func L2MessageHash(msg rpc.MsgToL1) []byte {
+	words := []*big.Int{
+		utils.FeltToBigInt(msg.FromAddress),
+		utils.FeltToBigInt(msg.ToAddress),
+		big.NewInt(int64(len(msg.Payload))),
+	}
+	for _, element := range msg.Payload {
+		words = append(words, utils.FeltToBigInt(element))
+	}
+	return keccakWords(words)
+}
+
+// keccakWords hashes words encoded as 32-byte big-endian integers, as
+// abi.encodePacked does for uint256 values.
+//
+// Parameters:
+// - words: the words
+// Returns:
+// - []byte: the 32-byte hash
+func keccakWords(words []*big.Int) []byte {
+	data := make([]byte, 32*len(words))
+	for i, w := range words {
+		w.FillBytes(data[i*32 : (i+1)*32])
+	}
+	return utils.Keccak256(data)
+}
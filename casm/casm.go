@@ -0,0 +1,469 @@
+// Package casm disassembles the bytecode of compiled (CASM) classes into
+// Cairo instructions, with the hints run before them and the entry points
+// starting at them, for debuggers, tracers and audits of deployed code.
+//
+// An instruction is a 63 bit word holding three biased 16 bit offsets and 15
+// flags, followed by an immediate value when its second operand is one, as
+// described in section 4.5 of the Cairo paper.
+package casm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/contracts"
+)
+
+var (
+	ErrInvalidInstruction = errors.New("invalid instruction")
+	ErrMissingImmediate   = errors.New("missing immediate value")
+	ErrInvalidEntryPoint  = errors.New("entry point not at the start of an instruction")
+)
+
+// Register is a register addressed by an operand.
+type Register uint8
+
+const (
+	AP Register = iota
+	FP
+)
+
+// Op1Source is the address of the second operand.
+type Op1Source uint8
+
+const (
+	// Op1Op0 is [[op0] + off_op1]
+	Op1Op0 Op1Source = iota
+	// Op1Imm is the immediate value following the instruction
+	Op1Imm
+	// Op1FP is [fp + off_op1]
+	Op1FP
+	// Op1AP is [ap + off_op1]
+	Op1AP
+)
+
+// ResLogic is the computation of the result from the operands.
+type ResLogic uint8
+
+const (
+	ResOp1 ResLogic = iota
+	ResAdd
+	ResMul
+)
+
+// PcUpdate is the update of the program counter.
+type PcUpdate uint8
+
+const (
+	PcRegular PcUpdate = iota
+	PcJumpAbs
+	PcJumpRel
+	PcJnz
+)
+
+// ApUpdate is the update of the allocation pointer.
+type ApUpdate uint8
+
+const (
+	ApRegular ApUpdate = iota
+	ApAdd
+	ApAdd1
+	// ApAdd2 is the implicit update of a call
+	ApAdd2
+)
+
+// Opcode is the operation of an instruction.
+type Opcode uint8
+
+const (
+	OpNop Opcode = iota
+	OpCall
+	OpRet
+	OpAssertEq
+)
+
+// offsetBias is the bias of the offsets encoded in an instruction.
+const offsetBias = 1 << 15
+
+// EntryPoint is an entry point of a class starting at an instruction.
+type EntryPoint struct {
+	// Type is CONSTRUCTOR, EXTERNAL or L1_HANDLER
+	Type     string
+	Selector *felt.Felt
+	Offset   int
+	Builtins []string
+}
+
+// Instruction is a decoded instruction.
+type Instruction struct {
+	// Offset is the offset of the instruction in the bytecode
+	Offset int
+	// Size is the number of words of the instruction, 2 with an immediate
+	Size     int
+	Encoded  *felt.Felt
+	OffDst   int16
+	OffOp0   int16
+	OffOp1   int16
+	DstReg   Register
+	Op0Reg   Register
+	Op1Src   Op1Source
+	Res      ResLogic
+	PcUpdate PcUpdate
+	ApUpdate ApUpdate
+	Opcode   Opcode
+	// Immediate is the immediate value, if Op1Src is Op1Imm
+	Immediate *felt.Felt
+	// Data is true if the word is not an instruction, e.g. a constant
+	// emitted by the compiler after the code; only Offset, Size and Encoded
+	// are set
+	Data bool
+	// Hints are the hints run before the instruction
+	Hints []json.RawMessage
+	// EntryPoints are the entry points starting at the instruction
+	EntryPoints []EntryPoint
+}
+
+// Program is the disassembled bytecode of a class.
+type Program struct {
+	Instructions []Instruction
+	EntryPoints  []EntryPoint
+	// index maps the offsets of the instructions to their index
+	index map[int]int
+}
+
+// Decode decodes an instruction.
+//
+// Parameters:
+// - encoded: the encoded instruction
+// - immediate: the word following the instruction, or nil at the end of the bytecode
+// Returns:
+// - Instruction: the instruction, at offset 0
+// - error: ErrInvalidInstruction if the word is not an instruction, or ErrMissingImmediate
+func Decode(encoded, immediate *felt.Felt) (Instruction, error) {
+	limbs := encoded.Bits()
+	if limbs[1] != 0 || limbs[2] != 0 || limbs[3] != 0 || limbs[0]>>63 != 0 {
+		return Instruction{}, fmt.Errorf("%w: %s does not fit in 63 bits", ErrInvalidInstruction, encoded)
+	}
+	word := limbs[0]
+	flags := word >> 48
+	inst := Instruction{
+		Size:    1,
+		Encoded: encoded,
+		OffDst:  int16(int64(word&0xffff) - offsetBias),
+		OffOp0:  int16(int64(word>>16&0xffff) - offsetBias),
+		OffOp1:  int16(int64(word>>32&0xffff) - offsetBias),
+		DstReg:  Register(flags & 1),
+		Op0Reg:  Register(flags >> 1 & 1),
+	}
+
+	var ok bool
+	if inst.Op1Src, ok = map[uint64]Op1Source{0: Op1Op0, 1: Op1Imm, 2: Op1FP, 4: Op1AP}[flags>>2&7]; !ok {
+		return Instruction{}, fmt.Errorf("%w: %s has an invalid op1 source", ErrInvalidInstruction, encoded)
+	}
+	if inst.Res = ResLogic(flags >> 5 & 3); inst.Res > ResMul {
+		return Instruction{}, fmt.Errorf("%w: %s has an invalid res logic", ErrInvalidInstruction, encoded)
+	}
+	if inst.PcUpdate, ok = map[uint64]PcUpdate{0: PcRegular, 1: PcJumpAbs, 2: PcJumpRel, 4: PcJnz}[flags>>7&7]; !ok {
+		return Instruction{}, fmt.Errorf("%w: %s has an invalid pc update", ErrInvalidInstruction, encoded)
+	}
+	if inst.ApUpdate = ApUpdate(flags >> 10 & 3); inst.ApUpdate > ApAdd1 {
+		return Instruction{}, fmt.Errorf("%w: %s has an invalid ap update", ErrInvalidInstruction, encoded)
+	}
+	if inst.Opcode, ok = map[uint64]Opcode{0: OpNop, 1: OpCall, 2: OpRet, 4: OpAssertEq}[flags>>12&7]; !ok {
+		return Instruction{}, fmt.Errorf("%w: %s has an invalid opcode", ErrInvalidInstruction, encoded)
+	}
+	if flags>>15 != 0 {
+		return Instruction{}, fmt.Errorf("%w: %s has a non-zero high flag", ErrInvalidInstruction, encoded)
+	}
+	if inst.Opcode == OpCall {
+		if inst.ApUpdate != ApRegular {
+			return Instruction{}, fmt.Errorf("%w: %s is a call updating ap", ErrInvalidInstruction, encoded)
+		}
+		inst.ApUpdate = ApAdd2
+	}
+	if inst.Op1Src == Op1Imm {
+		if inst.OffOp1 != 1 {
+			return Instruction{}, fmt.Errorf("%w: %s has an immediate at offset %d", ErrInvalidInstruction, encoded, inst.OffOp1)
+		}
+		if immediate == nil {
+			return Instruction{}, fmt.Errorf("%w: %s", ErrMissingImmediate, encoded)
+		}
+		inst.Size = 2
+		inst.Immediate = immediate
+	}
+	return inst, nil
+}
+
+// Disassemble decodes the bytecode of a class. Words that are not
+// instructions are kept as data, since compilers may emit constants after
+// the code.
+//
+// Parameters:
+// - class: the compiled class
+// Returns:
+// - *Program: the instructions, with their hints and entry points
+// - error: ErrInvalidEntryPoint if an entry point does not start at an instruction
+func Disassemble(class *contracts.CasmClass) (*Program, error) {
+	hints := make(map[int][]json.RawMessage, len(class.Hints))
+	for _, h := range class.Hints {
+		hints[h.Offset] = append(hints[h.Offset], h.Hints...)
+	}
+
+	p := &Program{index: make(map[int]int, len(class.ByteCode))}
+	for offset := 0; offset < len(class.ByteCode); {
+		var immediate *felt.Felt
+		if offset+1 < len(class.ByteCode) {
+			immediate = class.ByteCode[offset+1]
+		}
+		inst, err := Decode(class.ByteCode[offset], immediate)
+		if err != nil {
+			inst = Instruction{Size: 1, Encoded: class.ByteCode[offset], Data: true}
+		}
+		inst.Offset = offset
+		inst.Hints = hints[offset]
+		p.index[offset] = len(p.Instructions)
+		p.Instructions = append(p.Instructions, inst)
+		offset += inst.Size
+	}
+
+	for _, group := range []struct {
+		kind        string
+		entryPoints []contracts.CasmClassEntryPoint
+	}{
+		{"CONSTRUCTOR", class.EntryPointByType.Constructor},
+		{"EXTERNAL", class.EntryPointByType.External},
+		{"L1_HANDLER", class.EntryPointByType.L1Handler},
+	} {
+		for _, ep := range group.entryPoints {
+			i, ok := p.index[ep.Offset]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s %s at %d", ErrInvalidEntryPoint, group.kind, ep.Selector, ep.Offset)
+			}
+			entryPoint := EntryPoint{Type: group.kind, Selector: ep.Selector, Offset: ep.Offset, Builtins: ep.Builtins}
+			p.Instructions[i].EntryPoints = append(p.Instructions[i].EntryPoints, entryPoint)
+			p.EntryPoints = append(p.EntryPoints, entryPoint)
+		}
+	}
+	sort.SliceStable(p.EntryPoints, func(i, j int) bool { return p.EntryPoints[i].Offset < p.EntryPoints[j].Offset })
+	return p, nil
+}
+
+// At returns the instruction starting at an offset of the bytecode.
+//
+// Parameters:
+// - offset: the offset
+// Returns:
+// - *Instruction: the instruction
+// - bool: false if no instruction starts at the offset
+func (p *Program) At(offset int) (*Instruction, bool) {
+	i, ok := p.index[offset]
+	if !ok {
+		return nil, false
+	}
+	return &p.Instructions[i], true
+}
+
+// String returns the listing of the program: the offsets and instructions,
+// preceded by the entry points starting at them and the names of their hints.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the listing
+func (p *Program) String() string {
+	var b strings.Builder
+	for _, inst := range p.Instructions {
+		for _, ep := range inst.EntryPoints {
+			fmt.Fprintf(&b, "%s %s:\n", ep.Type, ep.Selector)
+		}
+		if names := inst.HintNames(); len(names) > 0 {
+			fmt.Fprintf(&b, "\t// hints: %s\n", strings.Join(names, ", "))
+		}
+		fmt.Fprintf(&b, "%6d\t%s\n", inst.Offset, inst.String())
+	}
+	return b.String()
+}
+
+// HintNames returns the names of the hints of the instruction, e.g.
+// TestLessThanOrEqual, in order.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []string: the names
+func (inst *Instruction) HintNames() []string {
+	names := make([]string, 0, len(inst.Hints))
+	for _, hint := range inst.Hints {
+		var variant map[string]json.RawMessage
+		if err := json.Unmarshal(hint, &variant); err != nil || len(variant) != 1 {
+			names = append(names, "?")
+			continue
+		}
+		for name := range variant {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// String returns the instruction in Cairo assembly, e.g.
+// "[ap + 0] = [fp + -3] + 1, ap++".
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the instruction
+func (inst *Instruction) String() string {
+	if inst.Data {
+		return "dw " + inst.Encoded.String()
+	}
+	var s string
+	switch inst.Opcode {
+	case OpAssertEq:
+		s = fmt.Sprintf("%s = %s", inst.dst(), inst.res())
+	case OpCall:
+		s = fmt.Sprintf("call %s %s", jumpKind(inst.PcUpdate), inst.res())
+	case OpRet:
+		s = "ret"
+	default:
+		switch inst.PcUpdate {
+		case PcJnz:
+			s = fmt.Sprintf("jmp rel %s if %s != 0", inst.op1(), inst.dst())
+		case PcJumpAbs, PcJumpRel:
+			s = fmt.Sprintf("jmp %s %s", jumpKind(inst.PcUpdate), inst.res())
+		default:
+			if inst.ApUpdate == ApAdd {
+				return "ap += " + inst.res()
+			}
+			s = "nop"
+		}
+	}
+	switch inst.ApUpdate {
+	case ApAdd:
+		s += fmt.Sprintf(", ap += %s", inst.res())
+	case ApAdd1:
+		s += ", ap++"
+	}
+	return s
+}
+
+// dst returns the destination operand.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the operand
+func (inst *Instruction) dst() string {
+	return deref(inst.DstReg, inst.OffDst)
+}
+
+// op1 returns the second operand.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the operand
+func (inst *Instruction) op1() string {
+	switch inst.Op1Src {
+	case Op1Imm:
+		return signed(inst.Immediate)
+	case Op1FP:
+		return deref(FP, inst.OffOp1)
+	case Op1AP:
+		return deref(AP, inst.OffOp1)
+	default:
+		return fmt.Sprintf("[%s + %d]", deref(inst.Op0Reg, inst.OffOp0), inst.OffOp1)
+	}
+}
+
+// res returns the result computed from the operands.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the result
+func (inst *Instruction) res() string {
+	switch inst.Res {
+	case ResAdd:
+		return deref(inst.Op0Reg, inst.OffOp0) + " + " + inst.op1()
+	case ResMul:
+		return deref(inst.Op0Reg, inst.OffOp0) + " * " + inst.op1()
+	default:
+		return inst.op1()
+	}
+}
+
+// String returns the name of the register.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: ap or fp
+func (r Register) String() string {
+	if r == FP {
+		return "fp"
+	}
+	return "ap"
+}
+
+// deref returns a memory operand, e.g. [fp + -3].
+//
+// Parameters:
+// - reg: the register
+// - offset: the offset from the register
+// Returns:
+// - string: the operand
+func deref(reg Register, offset int16) string {
+	return fmt.Sprintf("[%s + %d]", reg, offset)
+}
+
+// jumpKind returns abs or rel for a jump.
+//
+// Parameters:
+// - update: the pc update of the jump
+// Returns:
+// - string: the kind of the jump
+func jumpKind(update PcUpdate) string {
+	if update == PcJumpAbs {
+		return "abs"
+	}
+	return "rel"
+}
+
+// signed returns an immediate value as a signed decimal when it is small,
+// e.g. -12 for a backward jump, and in hexadecimal otherwise.
+//
+// Parameters:
+// - value: the value
+// Returns:
+// - string: the value
+func signed(value *felt.Felt) string {
+	v := value.BigInt(new(big.Int))
+	if v.BitLen() <= 63 {
+		return v.String()
+	}
+	neg := new(felt.Felt).Sub(new(felt.Felt), value).BigInt(new(big.Int))
+	if neg.BitLen() <= 63 {
+		return "-" + neg.String()
+	}
+	return value.String()
+}
@@ -0,0 +1,116 @@
+package casm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/contracts"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestDecode tests the decoding and printing of instructions of each kind,
+// and the rejection of words that are not instructions.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDecode(t *testing.T) {
+	for _, test := range []struct {
+		encoded   string
+		immediate string
+		expected  string
+		size      int
+	}{
+		{"0xa0680017fff8000", "0x7", "jmp rel 7 if [ap + 0] != 0, ap++", 2},
+		{"0x482680017ffa8000", "0xffffffffffffffffffffffffffffa9e8", "[ap + 0] = [fp + -6] + 0xffffffffffffffffffffffffffffa9e8, ap++", 2},
+		{"0x400280007ff97fff", "", "[ap + -1] = [[fp + -7] + 0]", 1},
+		{"0x10780017fff7fff", "0x6e", "jmp rel 110", 2},
+		{"0x1104800180018000", "0xe8", "call rel 232", 2},
+		{"0x40780017fff7fff", "0x1", "ap += 1", 2},
+		{"0x208b7fff7fff7ffe", "", "ret", 1},
+		{"0x10780017fff7fff", "0x800000000000011000000000000000000000000000000000000000000000000", "jmp rel -1", 2},
+	} {
+		var immediate *felt.Felt
+		if test.immediate != "" {
+			immediate = utils.TestHexToFelt(t, test.immediate)
+		}
+		inst, err := Decode(utils.TestHexToFelt(t, test.encoded), immediate)
+		require.NoError(t, err, test.encoded)
+		require.Equal(t, test.expected, inst.String(), test.encoded)
+		require.Equal(t, test.size, inst.Size, test.encoded)
+	}
+
+	call, err := Decode(utils.TestHexToFelt(t, "0x1104800180018000"), new(felt.Felt).SetUint64(0xe8))
+	require.NoError(t, err)
+	require.Equal(t, OpCall, call.Opcode)
+	require.Equal(t, ApAdd2, call.ApUpdate)
+	require.Equal(t, Op1Imm, call.Op1Src)
+
+	_, err = Decode(utils.TestHexToFelt(t, "0xa0680017fff8000"), nil)
+	require.True(t, errors.Is(err, ErrMissingImmediate))
+	for _, encoded := range []string{
+		"0x496e70757420746f6f206c6f6e6720666f7220617267756d656e7473",
+		"0x8000000000000000",
+		"0x3000800080008000",
+	} {
+		_, err = Decode(utils.TestHexToFelt(t, encoded), nil)
+		require.True(t, errors.Is(err, ErrInvalidInstruction), encoded)
+	}
+}
+
+// TestDisassemble tests the disassembly of a compiled class, with its hints
+// and entry points, of a constant emitted after the code, and the rejection
+// of an entry point inside an instruction.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDisassemble(t *testing.T) {
+	class, err := contracts.UnmarshalCasmClass("../contracts/tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+	program, err := Disassemble(class)
+	require.NoError(t, err)
+
+	size := 0
+	for _, inst := range program.Instructions {
+		require.False(t, inst.Data, inst.Offset)
+		size += inst.Size
+	}
+	require.Equal(t, len(class.ByteCode), size)
+	require.Len(t, program.EntryPoints, 2)
+
+	first, ok := program.At(0)
+	require.True(t, ok)
+	require.Equal(t, []string{"TestLessThanOrEqual"}, first.HintNames())
+	require.Len(t, first.EntryPoints, 1)
+	require.Equal(t, "EXTERNAL", first.EntryPoints[0].Type)
+	second, ok := program.At(130)
+	require.True(t, ok)
+	require.Len(t, second.EntryPoints, 1)
+	_, ok = program.At(1)
+	require.False(t, ok)
+
+	listing := program.String()
+	require.True(t, strings.HasPrefix(listing, "EXTERNAL 0x362398bec32bc0ebb411203221a35a0301193a96f317ebe5e40be9f60d15320:\n\t// hints: TestLessThanOrEqual\n     0\tjmp rel 7 if [ap + 0] != 0, ap++\n"), listing)
+	require.Contains(t, listing, "    28\tap += 1\n")
+
+	withData := *class
+	withData.ByteCode = append(append([]*felt.Felt{}, class.ByteCode...), utils.TestHexToFelt(t, "0x496e70757420746f6f206c6f6e67"))
+	program, err = Disassemble(&withData)
+	require.NoError(t, err)
+	last := program.Instructions[len(program.Instructions)-1]
+	require.True(t, last.Data)
+	require.Equal(t, "dw 0x496e70757420746f6f206c6f6e67", last.String())
+
+	inside := *class
+	inside.EntryPointByType.External = []contracts.CasmClassEntryPoint{{Selector: new(felt.Felt).SetUint64(1), Offset: 1}}
+	_, err = Disassemble(&inside)
+	require.True(t, errors.Is(err, ErrInvalidEntryPoint))
+}
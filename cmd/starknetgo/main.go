@@ -0,0 +1,322 @@
+// Command starknetgo runs the common operations of the SDK from the command
+// line, and is a reference of their use of the library:
+//
+//	starknetgo [-rpc url] block [latest|pending|number|hash]
+//	starknetgo [-rpc url] tx <hash>
+//	starknetgo [-rpc url] events [-address a] [-from block] [-to block] [-key k]... [-chunk n]
+//	starknetgo [-rpc url] call [-block id] <address> <function> [calldata...]
+//	starknetgo class-hash <class.json>
+//	starknetgo address [-deployer a] [-salt s] <class hash> [calldata...]
+//	starknetgo account new [-class-hash h]
+//	starknetgo [-rpc url] account deploy [-class-hash h]
+//	starknetgo [-rpc url] transfer [-token a] <recipient> <amount>
+//	starknetgo sign <hash>
+//
+// The node is read from -rpc or STARKNET_RPC_URL. The account sending
+// transactions and signing is read from STARKNET_ACCOUNT, its address, and
+// STARKNET_PRIVATE_KEY. Results are printed as JSON.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/curve"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var (
+	errUsage      = errors.New("usage")
+	errMissingEnv = errors.New("missing environment variable")
+)
+
+// command is a subcommand of the CLI.
+type command struct {
+	usage string
+	run   func(ctx context.Context, c *cli, args []string) error
+}
+
+// commands are the subcommands of the CLI, by name.
+var commands = map[string]command{
+	"block":      {"block [latest|pending|number|hash]", runBlock},
+	"tx":         {"tx <hash>", runTx},
+	"events":     {"events [-address a] [-from block] [-to block] [-key k]... [-chunk n]", runEvents},
+	"call":       {"call [-block id] <address> <function> [calldata...]", runCall},
+	"class-hash": {"class-hash <class.json>", runClassHash},
+	"address":    {"address [-deployer a] [-salt s] <class hash> [calldata...]", runAddress},
+	"account":    {"account new|deploy [-class-hash h]", runAccount},
+	"transfer":   {"transfer [-token a] <recipient> <amount>", runTransfer},
+	"sign":       {"sign <hash>", runSign},
+}
+
+// cli is the configuration of a run of the CLI.
+type cli struct {
+	stdout io.Writer
+	getenv func(string) string
+	rpcURL string
+	cairo  int
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Getenv); err != nil {
+		fmt.Fprintln(os.Stderr, "starknetgo:", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the CLI.
+//
+// Parameters:
+// - ctx: the context
+// - args: the arguments, without the name of the program
+// - stdout: the output of the results
+// - getenv: the lookup of the environment variables
+// Returns:
+// - error: an error wrapping errUsage for invalid arguments, or the error of the command
+func run(ctx context.Context, args []string, stdout io.Writer, getenv func(string) string) error {
+	c := &cli{stdout: stdout, getenv: getenv}
+	fs := flag.NewFlagSet("starknetgo", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&c.rpcURL, "rpc", getenv("STARKNET_RPC_URL"), "the URL of the node")
+	fs.IntVar(&c.cairo, "cairo", 2, "the Cairo version of the account")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: starknetgo [-rpc url] [-cairo version] <command>, with the commands:\n%s", errUsage, commandList())
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q, expected one of:\n%s", errUsage, fs.Arg(0), commandList())
+	}
+	if err := cmd.run(ctx, c, fs.Args()[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			return fmt.Errorf("%w: starknetgo %s: %s", errUsage, cmd.usage, strings.TrimPrefix(err.Error(), errUsage.Error()+": "))
+		}
+		return err
+	}
+	return nil
+}
+
+// commandList returns the usages of the commands, one per line.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the usages
+func commandList() string {
+	var lines []string
+	for _, name := range []string{"block", "tx", "events", "call", "class-hash", "address", "account", "transfer", "sign"} {
+		lines = append(lines, "  "+commands[name].usage)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// usage returns an error wrapping errUsage.
+//
+// Parameters:
+// - format: the format of the message
+// - args: the arguments of the format
+// Returns:
+// - error: the error
+func usage(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", errUsage, fmt.Sprintf(format, args...))
+}
+
+// flags returns the flag set of a command.
+//
+// Parameters:
+// - name: the name of the command
+// Returns:
+// - *flag.FlagSet: the flag set
+func flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parse parses the flags of a command.
+//
+// Parameters:
+// - fs: the flag set
+// - args: the arguments of the command
+// - minArgs: the minimum number of positional arguments
+// Returns:
+// - error: an error wrapping errUsage
+func parse(fs *flag.FlagSet, args []string, minArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		return usage("%v", err)
+	}
+	if fs.NArg() < minArgs {
+		return usage("expected %d arguments, got %d", minArgs, fs.NArg())
+	}
+	return nil
+}
+
+// provider returns the provider of the node of -rpc or STARKNET_RPC_URL.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *rpc.Provider: the provider
+// - error: errMissingEnv without a URL, or an error of rpc.NewHTTPProvider
+func (c *cli) provider() (*rpc.Provider, error) {
+	if c.rpcURL == "" {
+		return nil, fmt.Errorf("%w: STARKNET_RPC_URL, or the -rpc flag", errMissingEnv)
+	}
+	return rpc.NewHTTPProvider(c.rpcURL)
+}
+
+// key returns the private key of STARKNET_PRIVATE_KEY, in a keystore under
+// its public key.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *account.MemKeystore: the keystore holding the key
+// - *felt.Felt: the public key
+// - error: errMissingEnv, or an error if the key is not a valid private key
+func (c *cli) key() (*account.MemKeystore, *felt.Felt, error) {
+	raw := c.getenv("STARKNET_PRIVATE_KEY")
+	if raw == "" {
+		return nil, nil, fmt.Errorf("%w: STARKNET_PRIVATE_KEY", errMissingEnv)
+	}
+	priv, err := parseFelt(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("STARKNET_PRIVATE_KEY: %w", err)
+	}
+	privateKey := priv.BigInt(new(big.Int))
+	pubX, _, err := curve.Curve.PrivateToPoint(privateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("STARKNET_PRIVATE_KEY: %w", err)
+	}
+	pub := utils.BigIntToFelt(pubX)
+	return account.SetNewMemKeystore(pub.String(), privateKey), pub, nil
+}
+
+// account returns the account of STARKNET_ACCOUNT and STARKNET_PRIVATE_KEY.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *account.Account: the account
+// - error: errMissingEnv, or an error of the provider or of account.NewAccount
+func (c *cli) account() (*account.Account, error) {
+	raw := c.getenv("STARKNET_ACCOUNT")
+	if raw == "" {
+		return nil, fmt.Errorf("%w: STARKNET_ACCOUNT", errMissingEnv)
+	}
+	address, err := parseFelt(raw)
+	if err != nil {
+		return nil, fmt.Errorf("STARKNET_ACCOUNT: %w", err)
+	}
+	ks, pub, err := c.key()
+	if err != nil {
+		return nil, err
+	}
+	provider, err := c.provider()
+	if err != nil {
+		return nil, err
+	}
+	return account.NewAccount(provider, address, pub.String(), ks, c.cairo)
+}
+
+// print prints a result as indented JSON.
+//
+// Parameters:
+// - v: the result
+// Returns:
+// - error: an error of the encoding or of the output
+func (c *cli) print(v any) error {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// parseFelt parses a felt, in hexadecimal with a 0x prefix or in decimal.
+//
+// Parameters:
+// - s: the felt
+// Returns:
+// - *felt.Felt: the felt
+// - error: an error if s is not a felt
+func parseFelt(s string) (*felt.Felt, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		return new(felt.Felt).SetString(s)
+	}
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok || v.Sign() < 0 {
+		return nil, fmt.Errorf("invalid felt %q", s)
+	}
+	f, err := new(felt.Felt).SetString("0x" + v.Text(16))
+	if err != nil {
+		return nil, fmt.Errorf("invalid felt %q: %w", s, err)
+	}
+	return f, nil
+}
+
+// parseFelts parses felts as parseFelt does.
+//
+// Parameters:
+// - values: the felts
+// Returns:
+// - []*felt.Felt: the felts
+// - error: an error naming the invalid felt
+func parseFelts(values []string) ([]*felt.Felt, error) {
+	felts := make([]*felt.Felt, len(values))
+	for i, s := range values {
+		f, err := parseFelt(s)
+		if err != nil {
+			return nil, err
+		}
+		felts[i] = f
+	}
+	return felts, nil
+}
+
+// parseBlockID parses a block ID: a tag, a block number or a block hash.
+//
+// Parameters:
+// - s: the block ID, "latest", "pending", a decimal number or a 0x hash
+// Returns:
+// - rpc.BlockID: the block ID
+// - error: an error if s is not a block ID
+func parseBlockID(s string) (rpc.BlockID, error) {
+	switch {
+	case s == rpc.BlockTagLatest || s == rpc.BlockTagPending:
+		return rpc.WithBlockTag(s), nil
+	case strings.HasPrefix(s, "0x"):
+		hash, err := new(felt.Felt).SetString(s)
+		if err != nil {
+			return rpc.BlockID{}, fmt.Errorf("invalid block hash %q: %w", s, err)
+		}
+		return rpc.BlockIDFromHash(hash)
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return rpc.BlockID{}, fmt.Errorf("invalid block %q, expected latest, pending, a number or a hash", s)
+	}
+	return rpc.BlockIDFromNumber(n), nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/curve"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// runCLI runs the CLI with an environment and decodes its output.
+//
+// Parameters:
+// - t: the testing object
+// - env: the environment variables
+// - out: the value the output is decoded into, ignored if nil
+// - args: the arguments
+// Returns:
+// - error: the error of the CLI
+func runCLI(t *testing.T, env map[string]string, out any, args ...string) error {
+	var stdout bytes.Buffer
+	err := run(context.Background(), args, &stdout, func(key string) string { return env[key] })
+	if err == nil && out != nil {
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), out))
+	}
+	return err
+}
+
+// TestOffline tests the commands not reading the node: the usage errors, the
+// class hash, the address and the signature.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestOffline(t *testing.T) {
+	require.True(t, errors.Is(runCLI(t, nil, nil), errUsage))
+	require.True(t, errors.Is(runCLI(t, nil, nil, "nope"), errUsage))
+	require.True(t, errors.Is(runCLI(t, nil, nil, "address"), errUsage))
+	require.True(t, errors.Is(runCLI(t, nil, nil, "block"), errMissingEnv))
+
+	var classHash map[string]*felt.Felt
+	require.NoError(t, runCLI(t, nil, &classHash, "class-hash", "../../contracts/tests/hello_starknet_compiled.sierra.json"))
+	require.NotNil(t, classHash["class_hash"])
+	require.NotNil(t, classHash["compiled_class_hash"])
+
+	var address map[string]*felt.Felt
+	require.NoError(t, runCLI(t, nil, &address, "address", "-salt", "0x5", "0x1234", "1", "0x2"))
+	expected, err := new(account.Account).PrecomputeAddress(&felt.Zero, new(felt.Felt).SetUint64(5), new(felt.Felt).SetUint64(0x1234),
+		[]*felt.Felt{new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(2)})
+	require.NoError(t, err)
+	require.Equal(t, expected, address["address"])
+
+	var keys map[string]*felt.Felt
+	require.NoError(t, runCLI(t, nil, &keys, "account", "new", "-class-hash", "0x1234"))
+	expected, err = new(account.Account).PrecomputeAddress(&felt.Zero, keys["public_key"], new(felt.Felt).SetUint64(0x1234), []*felt.Felt{keys["public_key"]})
+	require.NoError(t, err)
+	require.Equal(t, expected, keys["address"])
+
+	env := map[string]string{"STARKNET_PRIVATE_KEY": keys["private_key"].String()}
+	var signature map[string]*felt.Felt
+	require.NoError(t, runCLI(t, env, &signature, "sign", "0xabc"))
+	require.Equal(t, keys["public_key"], signature["public_key"])
+	pubX := keys["public_key"].BigInt(new(big.Int))
+	pubY := curve.Curve.GetYCoordinate(pubX)
+	require.True(t, curve.Curve.Verify(big.NewInt(0xabc), signature["r"].BigInt(new(big.Int)), signature["s"].BigInt(new(big.Int)), pubX, pubY) ||
+		curve.Curve.Verify(big.NewInt(0xabc), signature["r"].BigInt(new(big.Int)), signature["s"].BigInt(new(big.Int)), pubX, new(big.Int).Neg(pubY)))
+	require.True(t, errors.Is(runCLI(t, nil, nil, "sign", "0xabc"), errMissingEnv))
+}
+
+// TestQueries tests the commands reading the node.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestQueries(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		methods = append(methods, req.Method+" "+string(req.Params))
+		result := `null`
+		switch req.Method {
+		case "starknet_getBlockWithTxHashes":
+			result = `{"status": "ACCEPTED_ON_L2", "block_hash": "0x1", "parent_hash": "0x0", "block_number": 7, "new_root": "0x2", "timestamp": 1, "sequencer_address": "0x3", "l1_gas_price": {"price_in_fri": "0x1", "price_in_wei": "0x1"}, "starknet_version": "0.13.1", "transactions": ["0x9"]}`
+		case "starknet_call":
+			result = `["0x2a"]`
+		case "starknet_getEvents":
+			result = `{"events": [{"from_address": "0xa", "keys": ["0x1"], "data": [], "block_hash": "0x1", "block_number": 7, "transaction_hash": "0x9"}]}`
+		}
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": %s}`, req.ID, result)
+	}))
+	defer server.Close()
+
+	var block map[string]any
+	require.NoError(t, runCLI(t, nil, &block, "-rpc", server.URL, "block", "7"))
+	require.Equal(t, `starknet_getBlockWithTxHashes [{"block_number":7}]`, methods[0])
+	require.Contains(t, fmt.Sprint(block), "0x9")
+
+	env := map[string]string{"STARKNET_RPC_URL": server.URL}
+	var result []*felt.Felt
+	require.NoError(t, runCLI(t, env, &result, "call", "-block", "latest", "0xa", "balance_of", "0xb"))
+	require.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(0x2a)}, result)
+	require.Equal(t, fmt.Sprintf(`starknet_call [{"contract_address":"0xa","entry_point_selector":"%s","calldata":["0xb"]},"latest"]`, utils.GetSelectorFromNameFelt("balance_of")), methods[1])
+
+	var events []map[string]any
+	require.NoError(t, runCLI(t, env, &events, "events", "-address", "0xa", "-from", "1", "-key", "Transfer", "-chunk", "10"))
+	require.Len(t, events, 1)
+	require.Contains(t, methods[2], utils.SelectorTransferEvent.String())
+
+	require.True(t, errors.Is(runCLI(t, env, nil, "call", "-block", "soon", "0xa", "f"), errUsage))
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/contracts/artifacts"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// runClassHash prints the hash of a Sierra class, and the hash of its CASM
+// class if it is next to it.
+//
+// Parameters:
+// - ctx: the context
+// - c: the CLI
+// - args: the path of the class
+// Returns:
+// - error: an error of the arguments or of the loading of the class
+func runClassHash(ctx context.Context, c *cli, args []string) error {
+	fs := flags("class-hash")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	result := struct {
+		ClassHash         *felt.Felt `json:"class_hash"`
+		CompiledClassHash *felt.Felt `json:"compiled_class_hash,omitempty"`
+	}{}
+	artifact, err := artifacts.Load(fs.Arg(0))
+	switch {
+	case err == nil:
+		result.ClassHash, result.CompiledClassHash = artifact.ClassHash, artifact.CompiledClassHash
+	case errors.Is(err, artifacts.ErrCasmNotFound):
+		class, err := artifacts.LoadSierra(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		if result.ClassHash, err = hash.ClassHash(*class); err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+	return c.print(result)
+}
+
+// runAddress prints the address of a contract deployed with a class, salt,
+// deployer and constructor calldata.
+//
+// Parameters:
+// - ctx: the context
+// - c: the CLI
+// - args: the class hash and the constructor calldata
+// Returns:
+// - error: an error of the arguments
+func runAddress(ctx context.Context, c *cli, args []string) error {
+	fs := flags("address")
+	deployer := fs.String("deployer", "0x0", "the deployer, 0 for deploy account transactions and UDC deployments not unique to the caller")
+	salt := fs.String("salt", "0x0", "the salt")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	felts, err := parseFelts(append([]string{*deployer, *salt}, fs.Args()...))
+	if err != nil {
+		return usage("%v", err)
+	}
+	address, err := new(account.Account).PrecomputeAddress(felts[0], felts[1], felts[2], felts[3:])
+	if err != nil {
+		return err
+	}
+	return c.print(map[string]*felt.Felt{"address": address})
+}
+
+// runSign prints the signature of a hash with STARKNET_PRIVATE_KEY.
+//
+// Parameters:
+// - ctx: the context
+// - c: the CLI
+// - args: the hash
+// Returns:
+// - error: an error of the arguments, of the key or of the signature
+func runSign(ctx context.Context, c *cli, args []string) error {
+	fs := flags("sign")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	msg, err := parseFelt(fs.Arg(0))
+	if err != nil {
+		return usage("%v", err)
+	}
+	ks, pub, err := c.key()
+	if err != nil {
+		return err
+	}
+	r, s, err := ks.Sign(ctx, pub.String(), msg.BigInt(new(big.Int)))
+	if err != nil {
+		return err
+	}
+	return c.print(map[string]*felt.Felt{"public_key": pub, "r": utils.BigIntToFelt(r), "s": utils.BigIntToFelt(s)})
+}
+
+// runAccount creates or deploys an account whose constructor takes the
+// public key, as OpenZeppelin accounts.
+//
+// Parameters:
+// - ctx: the context
+// - c: the CLI
+// - args: new or deploy, and their flags
+// Returns:
+// - error: an error of the arguments, of the key or of the deployment
+func runAccount(ctx context.Context, c *cli, args []string) error {
+	if len(args) == 0 {
+		return usage("expected new or deploy")
+	}
+	fs := flags("account " + args[0])
+	classHashFlag := fs.String("class-hash", "", "the class of the account")
+	if err := parse(fs, args[1:], 0); err != nil {
+		return err
+	}
+	var classHash *felt.Felt
+	if *classHashFlag != "" {
+		var err error
+		if classHash, err = parseFelt(*classHashFlag); err != nil {
+			return usage("%v", err)
+		}
+	}
+
+	switch args[0] {
+	case "new":
+		_, pub, priv := account.GetRandomKeys()
+		result := struct {
+			PrivateKey *felt.Felt `json:"private_key"`
+			PublicKey  *felt.Felt `json:"public_key"`
+			Address    *felt.Felt `json:"address,omitempty"`
+		}{PrivateKey: priv, PublicKey: pub}
+		if classHash != nil {
+			address, err := new(account.Account).PrecomputeAddress(&felt.Zero, pub, classHash, []*felt.Felt{pub})
+			if err != nil {
+				return err
+			}
+			result.Address = address
+		}
+		return c.print(result)
+	case "deploy":
+		if classHash == nil {
+			return usage("-class-hash is required")
+		}
+		return deployAccount(ctx, c, classHash)
+	}
+	return usage("expected new or deploy, got %q", args[0])
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// runBlock prints a block with the hashes of its transactions.
+//
+// Parameters:
+// - ctx: the context
+// - c: the CLI
+// - args: the block ID, latest if none
+// Returns:
+// - error: an error of the arguments or of the provider
+func runBlock(ctx context.Context, c *cli, args []string) error {
+	fs := flags("block")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	id := rpc.BlockTagLatest
+	if fs.NArg() > 0 {
+		id = fs.Arg(0)
+	}
+	blockID, err := parseBlockID(id)
+	if err != nil {
+		return usage("%v", err)
+	}
+	provider, err := c.provider()
+	if err != nil {
+		return err
+	}
+	block, err := provider.BlockWithTxHashes(ctx, blockID)
+	if err != nil {
+		return err
+	}
+	return c.print(block)
+}
+
+// runTx prints a transaction and its receipt, without receipt if the
+// transaction is not executed yet.
+//
+// Parameters:
+// - ctx: the context
+// - c: the CLI
+// - args: the hash of the transaction
+// Returns:
+// - error: an error of the arguments or of the provider
+func runTx(ctx context.Context, c *cli, args []string) error {
+	fs := flags("tx")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	hash, err := parseFelt(fs.Arg(0))
+	if err != nil {
+		return usage("%v", err)
+	}
+	provider, err := c.provider()
+	if err != nil {
+		return err
+	}
+	tx, err := provider.TransactionByHash(ctx, hash)
+	if err != nil {
+		return err
+	}
+	result := struct {
+		Transaction rpc.Transaction `json:"transaction"`
+		Receipt     *rpc.Receipt    `json:"receipt,omitempty"`
+	}{Transaction: tx}
+	if receipt, err := provider.TransactionReceipt(ctx, hash); err == nil {
+		result.Receipt = receipt
+	}
+	return c.print(result)
+}
+
+// keyFlags collects the repeated -key flags of the events command, each a
+// comma-separated list of the accepted values of a key.
+type keyFlags [][]*felt.Felt
+
+// String returns the keys, as required by flag.Value.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the keys
+func (k *keyFlags) String() string {
+	var keys []string
+	for _, values := range *k {
+		var s []string
+		for _, v := range values {
+			s = append(s, v.String())
+		}
+		keys = append(keys, strings.Join(s, ","))
+	}
+	return strings.Join(keys, " ")
+}
+
+// Set adds a key, as required by flag.Value. Values that are not felts are
+// event names, replaced with their selector.
+//
+// Parameters:
+// - s: the comma-separated values of the key, empty for any value
+// Returns:
+// - error: nil
+func (k *keyFlags) Set(s string) error {
+	values := []*felt.Felt{}
+	if s != "" {
+		for _, v := range strings.Split(s, ",") {
+			f, err := parseFelt(v)
+			if err != nil {
+				f = utils.GetSelectorFromNameFelt(v)
+			}
+			values = append(values, f)
+		}
+	}
+	*k = append(*k, values)
+	return nil
+}
+
+var _ flag.Value = &keyFlags{}
+
+// runEvents prints the events of a block range, following the continuation
+// tokens until -chunk events are read.
+//
+// Parameters:
+// - ctx: the context
+// - c: the CLI
+// - args: the flags of the filter
+// Returns:
+// - error: an error of the arguments or of the provider
+func runEvents(ctx context.Context, c *cli, args []string) error {
+	fs := flags("events")
+	address := fs.String("address", "", "the contract emitting the events")
+	from := fs.String("from", rpc.BlockTagLatest, "the first block")
+	to := fs.String("to", rpc.BlockTagLatest, "the last block")
+	chunk := fs.Int("chunk", 100, "the maximum number of events")
+	var keys keyFlags
+	fs.Var(&keys, "key", "the accepted values of a key, comma-separated, for each key in order, event names standing for their selector")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	filter := rpc.EventFilter{Keys: keys}
+	var err error
+	if filter.FromBlock, err = parseBlockID(*from); err != nil {
+		return usage("%v", err)
+	}
+	if filter.ToBlock, err = parseBlockID(*to); err != nil {
+		return usage("%v", err)
+	}
+	if *address != "" {
+		if filter.Address, err = parseFelt(*address); err != nil {
+			return usage("%v", err)
+		}
+	}
+	provider, err := c.provider()
+	if err != nil {
+		return err
+	}
+
+	events := []rpc.EmittedEvent{}
+	token := ""
+	for len(events) < *chunk {
+		page, err := provider.Events(ctx, rpc.EventsInput{
+			EventFilter:       filter,
+			ResultPageRequest: rpc.ResultPageRequest{ContinuationToken: token, ChunkSize: *chunk - len(events)},
+		})
+		if err != nil {
+			return err
+		}
+		events = append(events, page.Events...)
+		if page.ContinuationToken == "" {
+			break
+		}
+		token = page.ContinuationToken
+	}
+	return c.print(events)
+}
+
+// runCall prints the result of a call to a contract.
+//
+// Parameters:
+// - ctx: the context
+// - c: the CLI
+// - args: the address of the contract, the name of the function and the calldata
+// Returns:
+// - error: an error of the arguments or of the provider
+func runCall(ctx context.Context, c *cli, args []string) error {
+	fs := flags("call")
+	block := fs.String("block", rpc.BlockTagPending, "the block of the call")
+	if err := parse(fs, args, 2); err != nil {
+		return err
+	}
+	blockID, err := parseBlockID(*block)
+	if err != nil {
+		return usage("%v", err)
+	}
+	address, err := parseFelt(fs.Arg(0))
+	if err != nil {
+		return usage("%v", err)
+	}
+	calldata, err := parseFelts(fs.Args()[2:])
+	if err != nil {
+		return usage("%v", err)
+	}
+	provider, err := c.provider()
+	if err != nil {
+		return err
+	}
+	result, err := provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    address,
+		EntryPointSelector: utils.GetSelectorFromNameFelt(fs.Arg(1)),
+		Calldata:           calldata,
+	}, blockID)
+	if err != nil {
+		return err
+	}
+	return c.print(result)
+}
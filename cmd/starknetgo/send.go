@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// strkAddress is the address of the STRK token on mainnet and Sepolia, the
+// default token of transfers.
+const strkAddress = "0x4718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d"
+
+// deployAccount deploys the account of STARKNET_PRIVATE_KEY, its public key
+// being the salt and the constructor calldata. The address must be funded
+// beforehand, e.g. from the output of account new.
+//
+// Parameters:
+// - ctx: the context
+// - c: the CLI
+// - classHash: the class of the account
+// Returns:
+// - error: an error of the key, of the estimation or of the transaction
+func deployAccount(ctx context.Context, c *cli, classHash *felt.Felt) error {
+	ks, pub, err := c.key()
+	if err != nil {
+		return err
+	}
+	provider, err := c.provider()
+	if err != nil {
+		return err
+	}
+	calldata := []*felt.Felt{pub}
+	acc, err := account.NewAccount(provider, &felt.Zero, pub.String(), ks, c.cairo)
+	if err != nil {
+		return err
+	}
+	address, err := acc.PrecomputeAddress(&felt.Zero, pub, classHash, calldata)
+	if err != nil {
+		return err
+	}
+	acc.AccountAddress = address
+
+	tx := rpc.DeployAccountTxn{
+		Type:                rpc.TransactionType_DeployAccount,
+		Version:             rpc.TransactionV1,
+		Nonce:               new(felt.Felt),
+		MaxFee:              new(felt.Felt),
+		ClassHash:           classHash,
+		ContractAddressSalt: pub,
+		ConstructorCalldata: calldata,
+	}
+	if err := acc.SignDeployAccountTransaction(ctx, &tx, address); err != nil {
+		return err
+	}
+	estimates, err := acc.EstimateFee(ctx, []rpc.BroadcastTxn{rpc.BroadcastDeployAccountTxn{DeployAccountTxn: tx}}, []rpc.SimulationFlag{}, rpc.WithBlockTag(rpc.BlockTagPending))
+	if err != nil {
+		return err
+	}
+	overall := estimates[0].OverallFee.BigInt(new(big.Int))
+	tx.MaxFee = utils.BigIntToFelt(overall.Mul(overall, big.NewInt(2)))
+	if err := acc.SignDeployAccountTransaction(ctx, &tx, address); err != nil {
+		return err
+	}
+	resp, err := acc.AddDeployAccountTransaction(ctx, rpc.BroadcastDeployAccountTxn{DeployAccountTxn: tx})
+	if err != nil {
+		return err
+	}
+	return c.print(rpc.AddDeployAccountTransactionResponse{TransactionHash: resp.TransactionHash, ContractAddress: address})
+}
+
+// runTransfer transfers ERC20 tokens from the account of STARKNET_ACCOUNT.
+//
+// Parameters:
+// - ctx: the context
+// - c: the CLI
+// - args: the recipient and the amount, e.g. "1.5 strk" or a number of base units
+// Returns:
+// - error: an error of the arguments, of the account or of the transaction
+func runTransfer(ctx context.Context, c *cli, args []string) error {
+	fs := flags("transfer")
+	tokenFlag := fs.String("token", strkAddress, "the ERC20 token, STRK by default")
+	if err := parse(fs, args, 2); err != nil {
+		return err
+	}
+	token, err := parseFelt(*tokenFlag)
+	if err != nil {
+		return usage("%v", err)
+	}
+	recipient, err := parseFelt(fs.Arg(0))
+	if err != nil {
+		return usage("%v", err)
+	}
+	amount, err := utils.ParseAmount(fs.Arg(1))
+	if err != nil {
+		return usage("%v", err)
+	}
+	acc, err := c.account()
+	if err != nil {
+		return err
+	}
+	resp, err := acc.Execute(ctx, []rpc.FunctionCall{{
+		ContractAddress:    token,
+		EntryPointSelector: utils.SelectorTransfer,
+		Calldata:           []*felt.Felt{recipient, amount.Low(), amount.High()},
+	}})
+	if err != nil {
+		return err
+	}
+	return c.print(resp)
+}
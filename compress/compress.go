@@ -0,0 +1,213 @@
+// Package compress packs small values into felts following a schema, for
+// contracts accepting compressed calldata. Each felt holds up to 251 bits; the
+// fields of a schema are laid out from the least significant bits of the
+// first felt, a field that does not fit in the remaining bits starting a new
+// felt. Contracts unpack the felts with the same schema, e.g. by shifting and
+// masking.
+package compress
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// FeltBits is the number of bits usable in a packed felt.
+const FeltBits = 251
+
+var (
+	ErrInvalidField  = errors.New("compress: invalid field")
+	ErrValueTooLarge = errors.New("compress: value too large for its field")
+	ErrLength        = errors.New("compress: unexpected number of values")
+)
+
+// Field is a field of a schema.
+type Field struct {
+	Name string
+	// Bits is the width of the field, between 1 and FeltBits
+	Bits uint
+}
+
+// slot is the position of a field in the packed felts.
+type slot struct {
+	felt  int
+	shift uint
+}
+
+// Schema is the layout of packed values.
+type Schema struct {
+	fields []Field
+	slots  []slot
+	felts  int
+}
+
+// NewSchema creates a schema packing fields in the order given.
+//
+// Parameters:
+// - fields: the fields
+// Returns:
+// - *Schema: the schema
+// - error: ErrInvalidField if a field has no bits or more than FeltBits bits
+func NewSchema(fields ...Field) (*Schema, error) {
+	s := &Schema{fields: fields, slots: make([]slot, len(fields))}
+	used := uint(FeltBits)
+	for i, field := range fields {
+		if field.Bits == 0 || field.Bits > FeltBits {
+			return nil, fmt.Errorf("%w: %s has %d bits", ErrInvalidField, field.Name, field.Bits)
+		}
+		if used+field.Bits > FeltBits {
+			s.felts++
+			used = 0
+		}
+		s.slots[i] = slot{felt: s.felts - 1, shift: used}
+		used += field.Bits
+	}
+	return s, nil
+}
+
+// Repeated creates a schema of n fields of the same width, e.g. to pack an
+// array of small integers.
+//
+// Parameters:
+// - n: the number of fields
+// - bits: the width of each field
+// Returns:
+// - *Schema: the schema
+// - error: ErrInvalidField if bits is 0 or more than FeltBits
+func Repeated(n int, bits uint) (*Schema, error) {
+	fields := make([]Field, n)
+	for i := range fields {
+		fields[i] = Field{Name: fmt.Sprintf("%d", i), Bits: bits}
+	}
+	return NewSchema(fields...)
+}
+
+// Fields returns the fields of the schema.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []Field: the fields
+func (s *Schema) Fields() []Field {
+	return s.fields
+}
+
+// Felts returns the number of felts of the packed values.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - int: the number of felts
+func (s *Schema) Felts() int {
+	return s.felts
+}
+
+// Pack packs values, one per field of the schema.
+//
+// Parameters:
+// - values: the values, in the order of the fields
+// Returns:
+// - []*felt.Felt: the packed felts
+// - error: ErrLength if the number of values does not match the schema, or ErrValueTooLarge
+func (s *Schema) Pack(values []*felt.Felt) ([]*felt.Felt, error) {
+	if len(values) != len(s.fields) {
+		return nil, fmt.Errorf("%w: %d values for %d fields", ErrLength, len(values), len(s.fields))
+	}
+	packed := make([]*big.Int, s.felts)
+	for i := range packed {
+		packed[i] = new(big.Int)
+	}
+	for i, value := range values {
+		v := utils.FeltToBigInt(value)
+		if uint(v.BitLen()) > s.fields[i].Bits {
+			return nil, fmt.Errorf("%w: %s does not fit in %d bits", ErrValueTooLarge, s.fields[i].Name, s.fields[i].Bits)
+		}
+		slot := s.slots[i]
+		packed[slot.felt].Or(packed[slot.felt], v.Lsh(v, slot.shift))
+	}
+	felts := make([]*felt.Felt, len(packed))
+	for i, p := range packed {
+		felts[i] = utils.BigIntToFelt(p)
+	}
+	return felts, nil
+}
+
+// PackUint64 packs integer values, one per field of the schema.
+//
+// Parameters:
+// - values: the values, in the order of the fields
+// Returns:
+// - []*felt.Felt: the packed felts
+// - error: ErrLength if the number of values does not match the schema, or ErrValueTooLarge
+func (s *Schema) PackUint64(values []uint64) ([]*felt.Felt, error) {
+	felts := make([]*felt.Felt, len(values))
+	for i, v := range values {
+		felts[i] = utils.Uint64ToFelt(v)
+	}
+	return s.Pack(felts)
+}
+
+// Unpack unpacks felts packed with the schema.
+//
+// Parameters:
+// - felts: the packed felts
+// Returns:
+// - []*felt.Felt: the values, in the order of the fields
+// - error: ErrLength if the number of felts does not match the schema, or
+// ErrValueTooLarge if a felt has bits set outside of the fields
+func (s *Schema) Unpack(felts []*felt.Felt) ([]*felt.Felt, error) {
+	if len(felts) != s.felts {
+		return nil, fmt.Errorf("%w: %d felts for %d", ErrLength, len(felts), s.felts)
+	}
+	packed := make([]*big.Int, len(felts))
+	for i, f := range felts {
+		packed[i] = utils.FeltToBigInt(f)
+	}
+	values := make([]*felt.Felt, len(s.fields))
+	for i, field := range s.fields {
+		slot := s.slots[i]
+		mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), field.Bits), big.NewInt(1))
+		v := new(big.Int).Rsh(packed[slot.felt], slot.shift)
+		v.And(v, mask)
+		values[i] = utils.BigIntToFelt(v)
+		packed[slot.felt].AndNot(packed[slot.felt], mask.Lsh(mask, slot.shift))
+	}
+	for _, p := range packed {
+		if p.Sign() != 0 {
+			return nil, fmt.Errorf("%w: bits set outside of the fields", ErrValueTooLarge)
+		}
+	}
+	return values, nil
+}
+
+// UnpackUint64 unpacks felts packed with a schema whose fields have at most
+// 64 bits.
+//
+// Parameters:
+// - felts: the packed felts
+// Returns:
+// - []uint64: the values, in the order of the fields
+// - error: an error if the felts can not be unpacked, or ErrInvalidField if a field is wider than 64 bits
+func (s *Schema) UnpackUint64(felts []*felt.Felt) ([]uint64, error) {
+	for _, field := range s.fields {
+		if field.Bits > 64 {
+			return nil, fmt.Errorf("%w: %s has %d bits", ErrInvalidField, field.Name, field.Bits)
+		}
+	}
+	values, err := s.Unpack(felts)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]uint64, len(values))
+	for i, v := range values {
+		result[i] = v.Uint64()
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package compress
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestSchema tests the layout of packed fields and the round trip through
+// Pack and Unpack.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestSchema(t *testing.T) {
+	schema, err := NewSchema(Field{"amount", 128}, Field{"deadline", 64}, Field{"fee_bps", 16}, Field{"recipient", 251})
+	require.NoError(t, err)
+	require.Equal(t, 2, schema.Felts())
+
+	recipient := utils.TestHexToFelt(t, "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+	values := []*felt.Felt{utils.Uint64ToFelt(1000), utils.Uint64ToFelt(1700000000), utils.Uint64ToFelt(30), recipient}
+	packed, err := schema.Pack(values)
+	require.NoError(t, err)
+	// 1000 | 1700000000 << 128 | 30 << 192
+	require.Equal(t, "0x1e000000006553f100000000000000000000000000000003e8", packed[0].String())
+	require.Equal(t, recipient, packed[1])
+	unpacked, err := schema.Unpack(packed)
+	require.NoError(t, err)
+	require.Equal(t, values, unpacked)
+
+	small, err := Repeated(40, 8)
+	require.NoError(t, err)
+	require.Equal(t, 2, small.Felts())
+	bytes := make([]uint64, 40)
+	for i := range bytes {
+		bytes[i] = uint64(255 - i)
+	}
+	packed, err = small.PackUint64(bytes)
+	require.NoError(t, err)
+	round, err := small.UnpackUint64(packed)
+	require.NoError(t, err)
+	require.Equal(t, bytes, round)
+
+	_, err = small.PackUint64(append(bytes[:39], 256))
+	require.True(t, errors.Is(err, ErrValueTooLarge))
+	_, err = small.PackUint64(bytes[:3])
+	require.True(t, errors.Is(err, ErrLength))
+	_, err = small.Unpack([]*felt.Felt{packed[0], utils.BigIntToFelt(new(big.Int).Lsh(big.NewInt(1), 200))})
+	require.True(t, errors.Is(err, ErrValueTooLarge))
+	_, err = schema.UnpackUint64(packed)
+	require.True(t, errors.Is(err, ErrInvalidField))
+	_, err = NewSchema(Field{"empty", 0})
+	require.True(t, errors.Is(err, ErrInvalidField))
+}
@@ -0,0 +1,230 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/devnet"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+var (
+	ErrMissingProvider  = errors.New("conformance: a provider is required")
+	ErrMissingDevNet    = errors.New("conformance: a devnet is required to fund the accounts")
+	ErrMissingClassHash = errors.New("conformance: the account class hash is required")
+	errDeployFailed     = errors.New("skipped: the account could not be deployed")
+	errNoUpgradeClass   = errors.New("skipped: no upgrade class hash configured")
+)
+
+// Flavor is an account flavor supported by the SDK. It selects how the
+// calldata of the `__execute__` entrypoint is formatted.
+type Flavor struct {
+	Name         string
+	CairoVersion int
+}
+
+var (
+	FlavorCairo0 = Flavor{Name: "cairo0", CairoVersion: 0}
+	FlavorCairo2 = Flavor{Name: "cairo2", CairoVersion: 2}
+)
+
+// Check identifies a single conformance scenario.
+type Check string
+
+const (
+	CheckDeploy      Check = "deploy"
+	CheckEstimateFee Check = "estimate_fee"
+	CheckInvoke      Check = "invoke"
+	CheckMulticall   Check = "multicall"
+	CheckTypedData   Check = "typed_data"
+	CheckUpgrade     Check = "upgrade"
+)
+
+// Checks lists every scenario in the order they are run.
+var Checks = []Check{CheckDeploy, CheckEstimateFee, CheckInvoke, CheckMulticall, CheckTypedData, CheckUpgrade}
+
+type Status string
+
+const (
+	StatusPassed  Status = "passed"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+)
+
+// Config holds the parameters of a conformance run.
+type Config struct {
+	// Provider is used for every read and write of the run
+	Provider rpc.RpcProvider
+	// DevNet funds the accounts before they are deployed
+	DevNet *devnet.DevNet
+	// ClassHash is the declared account class under test
+	ClassHash *felt.Felt
+	// ConstructorCalldata builds the constructor calldata from the account public key.
+	// When nil, the public key is passed as the only constructor argument.
+	ConstructorCalldata func(publicKey *felt.Felt) []*felt.Felt
+	// UpgradeClassHash is the declared class the account is upgraded to. The
+	// upgrade check is skipped when it is nil.
+	UpgradeClassHash *felt.Felt
+	// UpgradeFlavor encodes the upgrade call for the account class under test,
+	// defaults to account.FlavorOpenZeppelin
+	UpgradeFlavor account.AccountFlavor
+	// Flavors to exercise, defaults to FlavorCairo0 and FlavorCairo2
+	Flavors []Flavor
+	// MaxFee used for every transaction, defaults to 1e16 wei
+	MaxFee *felt.Felt
+	// FundAmount minted to every account before deployment, defaults to 1e18 wei
+	FundAmount *big.Int
+	// PollInterval between two receipt lookups, defaults to 1 second
+	PollInterval time.Duration
+}
+
+// Result is the outcome of a check for a given flavor.
+type Result struct {
+	Flavor  Flavor
+	Check   Check
+	Status  Status
+	TxHash  *felt.Felt
+	Err     error
+	Elapsed time.Duration
+}
+
+// Report gathers the results of a conformance run.
+type Report struct {
+	ClassHash *felt.Felt
+	Results   []Result
+}
+
+// Supported returns whether every check that was not skipped passed for the flavor.
+//
+// Parameters:
+// - flavor: the flavor to look up
+// Returns:
+// - bool: true if the flavor has at least one passing check and no failure
+func (r *Report) Supported(flavor Flavor) bool {
+	passed := false
+	for _, res := range r.Results {
+		if res.Flavor != flavor {
+			continue
+		}
+		switch res.Status {
+		case StatusFailed:
+			return false
+		case StatusPassed:
+			passed = true
+		}
+	}
+	return passed
+}
+
+// SupportedFlavors returns the flavors that passed every check they ran.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []Flavor: the supported flavors, in the order they were run
+func (r *Report) SupportedFlavors() []Flavor {
+	var flavors []Flavor
+	seen := map[Flavor]bool{}
+	for _, res := range r.Results {
+		if seen[res.Flavor] {
+			continue
+		}
+		seen[res.Flavor] = true
+		if r.Supported(res.Flavor) {
+			flavors = append(flavors, res.Flavor)
+		}
+	}
+	return flavors
+}
+
+// String renders the report as a plain-text table, one line per check.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the rendered report
+func (r *Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "account class %s\n", r.ClassHash)
+	for _, res := range r.Results {
+		fmt.Fprintf(&sb, "%-8s %-14s %-8s", res.Flavor.Name, res.Check, res.Status)
+		if res.TxHash != nil {
+			fmt.Fprintf(&sb, " tx=%s", res.TxHash)
+		}
+		if res.Err != nil {
+			fmt.Fprintf(&sb, " err=%v", res.Err)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Run deploys one account per flavor from the configured class and exercises
+// deploy, fee estimation, invoke, multicall, typed-data validation and upgrade
+// against it.
+//
+// A failing check does not stop the run: the returned report lists the status
+// of every check so that teams can see which SDK flavor their account class
+// is compatible with. Only configuration errors are returned as an error.
+//
+// Parameters:
+// - ctx: the context used for every network call
+// - cfg: the run configuration
+// Returns:
+// - *Report: the results of every check for every flavor
+// - error: an error if the configuration is invalid
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if cfg.Provider == nil {
+		return nil, ErrMissingProvider
+	}
+	if cfg.DevNet == nil {
+		return nil, ErrMissingDevNet
+	}
+	if cfg.ClassHash == nil {
+		return nil, ErrMissingClassHash
+	}
+	cfg.setDefaults()
+
+	token, err := cfg.DevNet.FeeToken()
+	if err != nil {
+		return nil, fmt.Errorf("conformance: reading fee token: %w", err)
+	}
+
+	report := &Report{ClassHash: cfg.ClassHash}
+	for _, flavor := range cfg.Flavors {
+		s := &session{cfg: cfg, flavor: flavor, feeToken: token.Address}
+		report.Results = append(report.Results, s.run(ctx)...)
+	}
+	return report, nil
+}
+
+// setDefaults fills the optional configuration fields.
+func (cfg *Config) setDefaults() {
+	if len(cfg.Flavors) == 0 {
+		cfg.Flavors = []Flavor{FlavorCairo0, FlavorCairo2}
+	}
+	if cfg.ConstructorCalldata == nil {
+		cfg.ConstructorCalldata = func(publicKey *felt.Felt) []*felt.Felt {
+			return []*felt.Felt{publicKey}
+		}
+	}
+	if cfg.MaxFee == nil {
+		cfg.MaxFee = new(felt.Felt).SetUint64(10_000_000_000_000_000)
+	}
+	if cfg.FundAmount == nil {
+		cfg.FundAmount = big.NewInt(1_000_000_000_000_000_000)
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = time.Second
+	}
+}
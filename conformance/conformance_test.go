@@ -0,0 +1,74 @@
+package conformance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// TestReport_SupportedFlavors tests the aggregation of check results per flavor.
+//
+// A flavor is supported once it has passing checks and no failure, skipped
+// checks being ignored.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestReport_SupportedFlavors(t *testing.T) {
+	report := Report{Results: []Result{
+		{Flavor: FlavorCairo0, Check: CheckDeploy, Status: StatusPassed},
+		{Flavor: FlavorCairo0, Check: CheckInvoke, Status: StatusFailed, Err: errors.New("reverted")},
+		{Flavor: FlavorCairo2, Check: CheckDeploy, Status: StatusPassed},
+		{Flavor: FlavorCairo2, Check: CheckInvoke, Status: StatusPassed},
+		{Flavor: FlavorCairo2, Check: CheckUpgrade, Status: StatusSkipped, Err: errNoUpgradeClass},
+	}}
+
+	require.False(t, report.Supported(FlavorCairo0))
+	require.True(t, report.Supported(FlavorCairo2))
+	require.Equal(t, []Flavor{FlavorCairo2}, report.SupportedFlavors())
+	require.Contains(t, report.String(), "cairo0   invoke         failed   err=reverted")
+}
+
+// TestVerifyVectors tests the encoder and hasher of the SDK against the
+// golden vectors, and the mismatches reported for a faulty encoder and a
+// faulty hasher.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestVerifyVectors(t *testing.T) {
+	vectors := Vectors()
+	require.Len(t, vectors, 8)
+	require.NoError(t, VerifyVectors(EncodeCalldata, HashInvoke))
+	require.NoError(t, VerifyVectors(EncodeCalldata, nil))
+
+	vectors[0].Calldata[0] = new(felt.Felt)
+	require.Equal(t, "0x1", Vectors()[0].Calldata[0].String())
+
+	cairo2Only := func(flavor Flavor, calls []rpc.FunctionCall) ([]*felt.Felt, error) {
+		return account.FmtCallDataCairo2(calls), nil
+	}
+	err := VerifyVectors(cairo2Only, nil)
+	require.True(t, errors.Is(err, ErrVectorMismatch))
+	require.Contains(t, err.Error(), "cairo0/transfer: calldata")
+
+	wrongChain := func(vector Vector, calldata []*felt.Felt) (*felt.Felt, error) {
+		vector.ChainID = "SN_MAIN"
+		return HashInvoke(vector, calldata)
+	}
+	err = VerifyVectors(EncodeCalldata, wrongChain)
+	require.True(t, errors.Is(err, ErrVectorMismatch))
+	require.Contains(t, err.Error(), "cairo0/transfer: transaction hash")
+
+	unsupported := errors.New("unsupported")
+	err = VerifyVectors(func(Flavor, []rpc.FunctionCall) ([]*felt.Felt, error) { return nil, unsupported }, nil)
+	require.True(t, errors.Is(err, unsupported))
+}
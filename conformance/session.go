@@ -0,0 +1,347 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/curve"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/typed"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// validSignature is the 'VALID' short string returned by SRC-6 accounts.
+var validSignature = new(felt.Felt).SetBytes([]byte("VALID"))
+
+// session runs every check for a single flavor against a freshly deployed account.
+type session struct {
+	cfg      Config
+	flavor   Flavor
+	feeToken *felt.Felt
+	account  *account.Account
+}
+
+// run executes the checks in order. When the deployment fails every following
+// check is reported as skipped.
+//
+// Parameters:
+// - ctx: the context used for every network call
+// Returns:
+// - []Result: one result per check
+func (s *session) run(ctx context.Context) []Result {
+	steps := map[Check]func(context.Context) (*felt.Felt, error){
+		CheckDeploy:      s.deploy,
+		CheckEstimateFee: s.estimateFee,
+		CheckInvoke:      s.invoke,
+		CheckMulticall:   s.multicall,
+		CheckTypedData:   s.typedData,
+		CheckUpgrade:     s.upgrade,
+	}
+
+	results := make([]Result, 0, len(Checks))
+	for _, check := range Checks {
+		res := Result{Flavor: s.flavor, Check: check}
+		if check != CheckDeploy && s.account == nil {
+			res.Status = StatusSkipped
+			res.Err = errDeployFailed
+			results = append(results, res)
+			continue
+		}
+
+		start := time.Now()
+		txHash, err := steps[check](ctx)
+		res.Elapsed = time.Since(start)
+		res.TxHash = txHash
+		res.Err = err
+		switch {
+		case errors.Is(err, errNoUpgradeClass):
+			res.Status = StatusSkipped
+		case err != nil:
+			res.Status = StatusFailed
+		default:
+			res.Status = StatusPassed
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// deploy funds and deploys a new account with random keys.
+//
+// Parameters:
+// - ctx: the context used for every network call
+// Returns:
+// - *felt.Felt: the hash of the deploy account transaction
+// - error: an error if the account could not be deployed
+func (s *session) deploy(ctx context.Context) (*felt.Felt, error) {
+	ks, pub, _ := account.GetRandomKeys()
+	calldata := s.cfg.ConstructorCalldata(pub)
+
+	// The account is only needed to compute the address, it is rebuilt with it afterwards
+	acnt, err := account.NewAccount(s.cfg.Provider, &felt.Zero, pub.String(), ks, s.flavor.CairoVersion)
+	if err != nil {
+		return nil, err
+	}
+	address, err := acnt.PrecomputeAddress(&felt.Zero, pub, s.cfg.ClassHash, calldata)
+	if err != nil {
+		return nil, err
+	}
+	acnt.AccountAddress = address
+
+	if _, err := s.cfg.DevNet.Mint(address, s.cfg.FundAmount); err != nil {
+		return nil, fmt.Errorf("funding %s: %w", address, err)
+	}
+
+	tx := rpc.DeployAccountTxn{
+		Nonce:               &felt.Zero,
+		MaxFee:              s.cfg.MaxFee,
+		Type:                rpc.TransactionType_DeployAccount,
+		Version:             rpc.TransactionV1,
+		Signature:           []*felt.Felt{},
+		ClassHash:           s.cfg.ClassHash,
+		ContractAddressSalt: pub,
+		ConstructorCalldata: calldata,
+	}
+	if err := acnt.SignDeployAccountTransaction(ctx, &tx, address); err != nil {
+		return nil, err
+	}
+	resp, err := acnt.AddDeployAccountTransaction(ctx, rpc.BroadcastDeployAccountTxn{DeployAccountTxn: tx})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.waitForSuccess(ctx, acnt, resp.TransactionHash); err != nil {
+		return resp.TransactionHash, err
+	}
+	s.account = acnt
+	return resp.TransactionHash, nil
+}
+
+// estimateFee estimates the fee of a fee token transfer.
+//
+// Parameters:
+// - ctx: the context used for every network call
+// Returns:
+// - *felt.Felt: always nil, no transaction is sent
+// - error: an error if the estimation failed or returned no fee
+func (s *session) estimateFee(ctx context.Context) (*felt.Felt, error) {
+	tx, err := s.signedInvoke(ctx, []rpc.FunctionCall{s.transferCall()})
+	if err != nil {
+		return nil, err
+	}
+	estimates, err := s.account.EstimateFee(ctx, []rpc.BroadcastTxn{rpc.BroadcastInvokev1Txn{InvokeTxnV1: *tx}}, []rpc.SimulationFlag{}, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return nil, err
+	}
+	if len(estimates) != 1 || estimates[0].OverallFee == nil || estimates[0].OverallFee.IsZero() {
+		return nil, fmt.Errorf("unexpected fee estimation %v", estimates)
+	}
+	return nil, nil
+}
+
+// invoke sends a single fee token transfer.
+//
+// Parameters:
+// - ctx: the context used for every network call
+// Returns:
+// - *felt.Felt: the transaction hash
+// - error: an error if the transaction failed
+func (s *session) invoke(ctx context.Context) (*felt.Felt, error) {
+	return s.send(ctx, []rpc.FunctionCall{s.transferCall()})
+}
+
+// multicall sends two fee token transfers in a single transaction.
+//
+// Parameters:
+// - ctx: the context used for every network call
+// Returns:
+// - *felt.Felt: the transaction hash
+// - error: an error if the transaction failed
+func (s *session) multicall(ctx context.Context) (*felt.Felt, error) {
+	return s.send(ctx, []rpc.FunctionCall{s.transferCall(), s.transferCall()})
+}
+
+// typedData signs a SNIP-12 message and checks the account validates the signature,
+// trying both the `isValidSignature` and `is_valid_signature` entrypoints.
+//
+// Parameters:
+// - ctx: the context used for every network call
+// Returns:
+// - *felt.Felt: always nil, no transaction is sent
+// - error: an error if neither entrypoint accepted the signature
+func (s *session) typedData(ctx context.Context) (*felt.Felt, error) {
+	chainID, err := s.cfg.Provider.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	types := map[string]typed.TypeDef{
+		"StarkNetDomain": {Definitions: []typed.Definition{{Name: "name", Type: "felt"}, {Name: "version", Type: "felt"}, {Name: "chainId", Type: "felt"}}},
+		"Probe":          {Definitions: []typed.Definition{{Name: "nonce", Type: "felt"}}},
+	}
+	td, err := typed.NewTypedData(types, "Probe", typed.Domain{Name: "conformance", Version: "1", ChainId: chainID})
+	if err != nil {
+		return nil, err
+	}
+	msgHash, err := td.GetMessageHash(utils.FeltToBigInt(s.account.AccountAddress), probeMessage{Nonce: big.NewInt(time.Now().UnixNano())}, curve.Curve)
+	if err != nil {
+		return nil, err
+	}
+	hashFelt := utils.BigIntToFelt(msgHash)
+	signature, err := s.account.Sign(ctx, hashFelt)
+	if err != nil {
+		return nil, err
+	}
+
+	calldata := append([]*felt.Felt{hashFelt, utils.Uint64ToFelt(uint64(len(signature)))}, signature...)
+	var errs []error
+	for _, entrypoint := range []string{"isValidSignature", "is_valid_signature"} {
+		result, err := s.account.Call(ctx, rpc.FunctionCall{
+			ContractAddress:    s.account.AccountAddress,
+			EntryPointSelector: utils.GetSelectorFromNameCached(entrypoint),
+			Calldata:           calldata,
+		}, rpc.WithBlockTag("latest"))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entrypoint, err))
+			continue
+		}
+		if len(result) > 0 && (result[0].Equal(new(felt.Felt).SetUint64(1)) || result[0].Equal(validSignature)) {
+			return nil, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: signature rejected with %v", entrypoint, result))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// upgrade calls the account `upgrade` entrypoint, encoded for the configured
+// upgrade flavor, and checks the class hash changed.
+//
+// Parameters:
+// - ctx: the context used for every network call
+// Returns:
+// - *felt.Felt: the transaction hash
+// - error: errNoUpgradeClass when no upgrade class is configured, or an error if the upgrade failed
+func (s *session) upgrade(ctx context.Context) (*felt.Felt, error) {
+	if s.cfg.UpgradeClassHash == nil {
+		return nil, errNoUpgradeClass
+	}
+	txHash, err := s.send(ctx, []rpc.FunctionCall{account.UpgradeCall(s.account.AccountAddress, s.cfg.UpgradeClassHash, s.cfg.UpgradeFlavor)})
+	if err != nil {
+		return txHash, err
+	}
+	classHash, err := s.account.ClassHashAt(ctx, rpc.WithBlockTag("latest"), s.account.AccountAddress)
+	if err != nil {
+		return txHash, err
+	}
+	if !classHash.Equal(s.cfg.UpgradeClassHash) {
+		return txHash, fmt.Errorf("class hash is %s after upgrade, expected %s", classHash, s.cfg.UpgradeClassHash)
+	}
+	return txHash, nil
+}
+
+// transferCall builds a transfer of 1 wei of the fee token back to the account.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - rpc.FunctionCall: the transfer call
+func (s *session) transferCall() rpc.FunctionCall {
+	return rpc.FunctionCall{
+		ContractAddress:    s.feeToken,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{s.account.AccountAddress, new(felt.Felt).SetUint64(1), &felt.Zero},
+	}
+}
+
+// signedInvoke builds and signs an invoke v1 transaction with the flavor calldata format.
+//
+// Parameters:
+// - ctx: the context used for every network call
+// - calls: the calls to execute
+// Returns:
+// - *rpc.InvokeTxnV1: the signed transaction
+// - error: an error if any
+func (s *session) signedInvoke(ctx context.Context, calls []rpc.FunctionCall) (*rpc.InvokeTxnV1, error) {
+	nonce, err := s.account.Nonce(ctx, rpc.WithBlockTag("latest"), s.account.AccountAddress)
+	if err != nil {
+		return nil, err
+	}
+	calldata, err := s.account.FmtCalldata(calls)
+	if err != nil {
+		return nil, err
+	}
+	tx := rpc.InvokeTxnV1{
+		MaxFee:        s.cfg.MaxFee,
+		Version:       rpc.TransactionV1,
+		Nonce:         nonce,
+		Type:          rpc.TransactionType_Invoke,
+		SenderAddress: s.account.AccountAddress,
+		Calldata:      calldata,
+	}
+	if err := s.account.SignInvokeTransaction(ctx, &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// send signs, broadcasts and waits for an invoke transaction.
+//
+// Parameters:
+// - ctx: the context used for every network call
+// - calls: the calls to execute
+// Returns:
+// - *felt.Felt: the transaction hash
+// - error: an error if the transaction was rejected or reverted
+func (s *session) send(ctx context.Context, calls []rpc.FunctionCall) (*felt.Felt, error) {
+	tx, err := s.signedInvoke(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.account.AddInvokeTransaction(ctx, rpc.BroadcastInvokev1Txn{InvokeTxnV1: *tx})
+	if err != nil {
+		return nil, err
+	}
+	return resp.TransactionHash, s.waitForSuccess(ctx, s.account, resp.TransactionHash)
+}
+
+// waitForSuccess waits for the receipt of the transaction and checks it succeeded.
+//
+// Parameters:
+// - ctx: the context used for every network call
+// - acnt: the account used to poll the receipt
+// - txHash: the transaction hash
+// Returns:
+// - error: an error if the transaction reverted or the receipt could not be fetched
+func (s *session) waitForSuccess(ctx context.Context, acnt *account.Account, txHash *felt.Felt) error {
+	receipt, err := acnt.WaitForTransactionReceipt(ctx, txHash, s.cfg.PollInterval)
+	if err != nil {
+		return err
+	}
+	if status := receipt.ExecutionStatus(); status != rpc.TxnExecutionStatusSUCCEEDED {
+		return fmt.Errorf("transaction %s has status %s", txHash, status)
+	}
+	return nil
+}
+
+// probeMessage is the SNIP-12 message signed by the typed data check.
+type probeMessage struct {
+	Nonce *big.Int
+}
+
+// FmtDefinitionEncoding formats the encoding of the probe message fields.
+//
+// Parameters:
+// - field: the field to format the encoding for
+// Returns:
+// - []*big.Int: the encoded field
+func (m probeMessage) FmtDefinitionEncoding(field string) []*big.Int {
+	if field == "nonce" {
+		return []*big.Int{m.Nonce}
+	}
+	return nil
+}
@@ -0,0 +1,373 @@
+package conformance
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var ErrVectorMismatch = errors.New("conformance: vector mismatch")
+
+// Vector is a golden vector of the calldata of the `__execute__` entrypoint
+// for a flavor, and of the hash of the invoke v1 transaction carrying it.
+// Wallet implementers cross-check their own encoders against the vectors
+// with VerifyVectors, or by exporting them to JSON.
+type Vector struct {
+	Name   string             `json:"name"`
+	Flavor Flavor             `json:"flavor"`
+	Calls  []rpc.FunctionCall `json:"calls"`
+	// Calldata is the expected calldata of the calls
+	Calldata []*felt.Felt `json:"calldata"`
+	// SenderAddress, Nonce, MaxFee and ChainID are the fields of the invoke
+	// v1 transaction
+	SenderAddress *felt.Felt `json:"sender_address"`
+	Nonce         *felt.Felt `json:"nonce"`
+	MaxFee        *felt.Felt `json:"max_fee"`
+	ChainID       string     `json:"chain_id"`
+	// TransactionHash is the expected hash of the invoke v1 transaction
+	TransactionHash *felt.Felt `json:"transaction_hash"`
+}
+
+// Encoder formats calls into the calldata of a flavor.
+type Encoder func(flavor Flavor, calls []rpc.FunctionCall) ([]*felt.Felt, error)
+
+// Hasher computes the hash of the invoke v1 transaction of a vector carrying
+// a calldata.
+type Hasher func(vector Vector, calldata []*felt.Felt) (*felt.Felt, error)
+
+// Transaction fields shared by the vectors.
+const (
+	vectorSender  = "0x3b8e2e4ba9a2a2e54bfcd12ec0f2bd0cdbd9ed24b2dc7a07c7b1e1e8a55e0a1"
+	vectorNonce   = "0x7"
+	vectorMaxFee  = "0x2386f26fc10000"
+	vectorChainID = "SN_SEPOLIA"
+)
+
+// vectorCalls are the calls of the vectors, by name.
+var vectorCalls = []struct {
+	name  string
+	calls [][]string
+}{
+	// a fee token transfer
+	{"transfer", [][]string{
+		{"0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7", "transfer", "0x1234", "0x3e8", "0x0"},
+	}},
+	// an approval followed by a call using the allowance
+	{"approve_and_swap", [][]string{
+		{"0x4718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d", "approve", "0x5678", "0xde0b6b3a7640000", "0x0"},
+		{"0x5678", "swap", "0x1", "0x2", "0x3"},
+	}},
+	// a call without arguments
+	{"no_calldata", [][]string{
+		{"0xc0ffee", "pause"},
+	}},
+	// a call without arguments between calls with arguments, to check the
+	// offsets of cairo0 calldata
+	{"mixed", [][]string{
+		{"0xc0ffee", "set", "0xa"},
+		{"0xc0ffee", "pause"},
+		{"0xbeef", "set_pair", "0xb", "0xc"},
+	}},
+}
+
+// goldenVectors are the expected calldata and transaction hashes.
+var goldenVectors = []struct {
+	name            string
+	flavor          Flavor
+	calldata        []string
+	transactionHash string
+}{
+	{
+		name:   "transfer",
+		flavor: FlavorCairo0,
+		calldata: []string{
+			"0x1",
+			"0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7",
+			"0x83afd3f4caedc6eebf44246fe54e38c95e3179a5ec9ea81740eca5b482d12e",
+			"0x0",
+			"0x3",
+			"0x3",
+			"0x1234",
+			"0x3e8",
+			"0x0",
+		},
+		transactionHash: "0x402b93d48de58065c214940071e2d30ef574a9b1ca6b5e2569eec6e196b8842",
+	},
+	{
+		name:   "transfer",
+		flavor: FlavorCairo2,
+		calldata: []string{
+			"0x1",
+			"0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7",
+			"0x83afd3f4caedc6eebf44246fe54e38c95e3179a5ec9ea81740eca5b482d12e",
+			"0x3",
+			"0x1234",
+			"0x3e8",
+			"0x0",
+		},
+		transactionHash: "0x52298f6d7b12a5a594edeec7af8dbf0371183d966b52ee6305b169e06475539",
+	},
+	{
+		name:   "approve_and_swap",
+		flavor: FlavorCairo0,
+		calldata: []string{
+			"0x2",
+			"0x4718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d",
+			"0x219209e083275171774dab1df80982e9df2096516f06319c5c6d71ae0a8480c",
+			"0x0",
+			"0x3",
+			"0x5678",
+			"0x15543c3708653cda9d418b4ccd3be11368e40636c10c44b18cfe756b6d88b29",
+			"0x3",
+			"0x3",
+			"0x6",
+			"0x5678",
+			"0xde0b6b3a7640000",
+			"0x0",
+			"0x1",
+			"0x2",
+			"0x3",
+		},
+		transactionHash: "0x298552f7693da891916c555bfd0b81b217cf1454d5cc0d856092eb9d2eee53",
+	},
+	{
+		name:   "approve_and_swap",
+		flavor: FlavorCairo2,
+		calldata: []string{
+			"0x2",
+			"0x4718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d",
+			"0x219209e083275171774dab1df80982e9df2096516f06319c5c6d71ae0a8480c",
+			"0x3",
+			"0x5678",
+			"0xde0b6b3a7640000",
+			"0x0",
+			"0x5678",
+			"0x15543c3708653cda9d418b4ccd3be11368e40636c10c44b18cfe756b6d88b29",
+			"0x3",
+			"0x1",
+			"0x2",
+			"0x3",
+		},
+		transactionHash: "0x32088c40c398190c3c4d79e96bcb1209af1b3e1e0f7e5237e44bdc621642bfe",
+	},
+	{
+		name:   "no_calldata",
+		flavor: FlavorCairo0,
+		calldata: []string{
+			"0x1",
+			"0xc0ffee",
+			"0x3f618718f1cde37d9c527a9237b04e6ac0489a8647d0517bb15827758ece720",
+			"0x0",
+			"0x0",
+			"0x0",
+		},
+		transactionHash: "0x69b75ce49b548e9fdb468ad2403a6262b1e161d0f34392dd3b6c2bb3243e96",
+	},
+	{
+		name:   "no_calldata",
+		flavor: FlavorCairo2,
+		calldata: []string{
+			"0x1",
+			"0xc0ffee",
+			"0x3f618718f1cde37d9c527a9237b04e6ac0489a8647d0517bb15827758ece720",
+			"0x0",
+		},
+		transactionHash: "0x5ce89bd568a7fee6ef1a7076cbeb3f8b75f98424dd4bcba56cf9c50214a7c91",
+	},
+	{
+		name:   "mixed",
+		flavor: FlavorCairo0,
+		calldata: []string{
+			"0x3",
+			"0xc0ffee",
+			"0x2f67e6aeaad1ab7487a680eb9d3363a597afa7a3de33fa9bf3ae6edcb88435d",
+			"0x0",
+			"0x1",
+			"0xc0ffee",
+			"0x3f618718f1cde37d9c527a9237b04e6ac0489a8647d0517bb15827758ece720",
+			"0x1",
+			"0x0",
+			"0xbeef",
+			"0xcf08960b5fde68aa60de91a5bb392ca88ff5ae5625e2fd736db8aea84910a2",
+			"0x1",
+			"0x2",
+			"0x3",
+			"0xa",
+			"0xb",
+			"0xc",
+		},
+		transactionHash: "0x362a8bfe8211b25304c169121aa99f55ff0c2c399ed2d8382b3eabe526bfb73",
+	},
+	{
+		name:   "mixed",
+		flavor: FlavorCairo2,
+		calldata: []string{
+			"0x3",
+			"0xc0ffee",
+			"0x2f67e6aeaad1ab7487a680eb9d3363a597afa7a3de33fa9bf3ae6edcb88435d",
+			"0x1",
+			"0xa",
+			"0xc0ffee",
+			"0x3f618718f1cde37d9c527a9237b04e6ac0489a8647d0517bb15827758ece720",
+			"0x0",
+			"0xbeef",
+			"0xcf08960b5fde68aa60de91a5bb392ca88ff5ae5625e2fd736db8aea84910a2",
+			"0x2",
+			"0xb",
+			"0xc",
+		},
+		transactionHash: "0x4c04aae830eae7da8cbbc6fae98cbaf4009b9208f2481188f98a3fdc12d8f89",
+	},
+}
+
+// Vectors returns the golden vectors, for every flavor. The vectors are new
+// copies, the caller may modify them.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []Vector: the vectors
+func Vectors() []Vector {
+	calls := map[string][]rpc.FunctionCall{}
+	for _, vc := range vectorCalls {
+		for _, c := range vc.calls {
+			calls[vc.name] = append(calls[vc.name], rpc.FunctionCall{
+				ContractAddress:    hexFelt(c[0]),
+				EntryPointSelector: utils.GetSelectorFromNameFelt(c[1]),
+				Calldata:           hexFelts(c[2:]),
+			})
+		}
+	}
+	vectors := make([]Vector, len(goldenVectors))
+	for i, gv := range goldenVectors {
+		vectors[i] = Vector{
+			Name:            gv.name,
+			Flavor:          gv.flavor,
+			Calls:           calls[gv.name],
+			Calldata:        hexFelts(gv.calldata),
+			SenderAddress:   hexFelt(vectorSender),
+			Nonce:           hexFelt(vectorNonce),
+			MaxFee:          hexFelt(vectorMaxFee),
+			ChainID:         vectorChainID,
+			TransactionHash: hexFelt(gv.transactionHash),
+		}
+	}
+	return vectors
+}
+
+// VerifyVectors checks an encoder, and optionally a hasher, against the
+// golden vectors.
+//
+// Parameters:
+// - encode: the encoder under test
+// - hash: the hasher under test, the hashes are not checked if nil
+// Returns:
+// - error: an error wrapping ErrVectorMismatch naming the first failing vector, or the error of the encoder or hasher
+func VerifyVectors(encode Encoder, hash Hasher) error {
+	for _, v := range Vectors() {
+		calldata, err := encode(v.Flavor, v.Calls)
+		if err != nil {
+			return fmt.Errorf("conformance: vector %s/%s: %w", v.Flavor.Name, v.Name, err)
+		}
+		if !equalFelts(calldata, v.Calldata) {
+			return fmt.Errorf("%w: %s/%s: calldata %v, expected %v", ErrVectorMismatch, v.Flavor.Name, v.Name, calldata, v.Calldata)
+		}
+		if hash == nil {
+			continue
+		}
+		txHash, err := hash(v, calldata)
+		if err != nil {
+			return fmt.Errorf("conformance: vector %s/%s: %w", v.Flavor.Name, v.Name, err)
+		}
+		if txHash == nil || !txHash.Equal(v.TransactionHash) {
+			return fmt.Errorf("%w: %s/%s: transaction hash %s, expected %s", ErrVectorMismatch, v.Flavor.Name, v.Name, txHash, v.TransactionHash)
+		}
+	}
+	return nil
+}
+
+// EncodeCalldata is the encoder of the SDK, Account.FmtCalldata.
+//
+// Parameters:
+// - flavor: the flavor
+// - calls: the calls
+// Returns:
+// - []*felt.Felt: the calldata
+// - error: an error if the Cairo version of the flavor is not supported
+func EncodeCalldata(flavor Flavor, calls []rpc.FunctionCall) ([]*felt.Felt, error) {
+	acnt := &account.Account{CairoVersion: flavor.CairoVersion}
+	return acnt.FmtCalldata(calls)
+}
+
+// HashInvoke is the hasher of the SDK, Account.TransactionHashInvoke.
+//
+// Parameters:
+// - vector: the vector
+// - calldata: the calldata
+// Returns:
+// - *felt.Felt: the transaction hash
+// - error: an error if the hash can not be computed
+func HashInvoke(vector Vector, calldata []*felt.Felt) (*felt.Felt, error) {
+	acnt := &account.Account{ChainId: new(felt.Felt).SetBytes([]byte(vector.ChainID)), CairoVersion: vector.Flavor.CairoVersion}
+	return acnt.TransactionHashInvoke(rpc.InvokeTxnV1{
+		MaxFee:        vector.MaxFee,
+		Version:       rpc.TransactionV1,
+		Nonce:         vector.Nonce,
+		Type:          rpc.TransactionType_Invoke,
+		SenderAddress: vector.SenderAddress,
+		Calldata:      calldata,
+	})
+}
+
+// hexFelt converts a hexadecimal constant of the vectors to a felt.
+//
+// Parameters:
+// - s: the hexadecimal string
+// Returns:
+// - *felt.Felt: the felt
+func hexFelt(s string) *felt.Felt {
+	f, err := utils.HexToFelt(s)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// hexFelts converts hexadecimal constants of the vectors to felts.
+//
+// Parameters:
+// - s: the hexadecimal strings
+// Returns:
+// - []*felt.Felt: the felts, empty but not nil if s is empty
+func hexFelts(s []string) []*felt.Felt {
+	felts := make([]*felt.Felt, len(s))
+	for i, h := range s {
+		felts[i] = hexFelt(h)
+	}
+	return felts
+}
+
+// equalFelts reports whether two slices of felts are equal.
+//
+// Parameters:
+// - a: the first slice
+// - b: the second slice
+// Returns:
+// - bool: true if the slices have the same felts in the same order
+func equalFelts(a, b []*felt.Felt) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] == nil || b[i] == nil || !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
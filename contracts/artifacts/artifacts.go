@@ -0,0 +1,211 @@
+// Package artifacts loads the classes output by the Cairo compilers, Scarb
+// and starknet-compile for Sierra classes and starknet-compile-deprecated for
+// Cairo 0 classes, checks them, and returns them as the types declared and
+// deployed by the account package. It is a subpackage of contracts as the rpc
+// package, holding the class types, imports contracts.
+//
+//	artifact, err := artifacts.Load("target/dev/hello_HelloStarknet.contract_class.json")
+//	resp, err := acc.Declare(ctx, *artifact.Class, *artifact.Casm)
+package artifacts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/contracts"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// Prime is the prime of the field of the CASM classes compiled for Starknet
+const Prime = "0x800000000000011000000000000000000000000000000000000000000000001"
+
+// ContractClassVersion is the version of the Sierra classes accepted by Starknet
+const ContractClassVersion = "0.1.0"
+
+var (
+	ErrInvalidArtifact = errors.New("invalid contract artifact")
+	ErrCasmNotFound    = errors.New("no CASM class next to the Sierra class")
+)
+
+// Artifact is a Sierra class with its CASM class, ready to be declared.
+type Artifact struct {
+	Class *rpc.ContractClass
+	Casm  *contracts.CasmClass
+	// Metadata are the versions the class was built with
+	Metadata *contracts.ClassMetadata
+	// ClassHash is the hash of the Sierra class, e.g. to deploy it once declared
+	ClassHash *felt.Felt
+	// CompiledClassHash is the hash of the CASM class
+	CompiledClassHash *felt.Felt
+}
+
+// casmSuffixes maps the suffixes of the Sierra classes output by Scarb and
+// starknet-compile to the suffixes of their CASM classes.
+var casmSuffixes = [][2]string{
+	{".contract_class.json", ".compiled_contract_class.json"},
+	{".sierra.json", ".casm.json"},
+}
+
+// Load loads a Sierra class and the CASM class next to it, named as Scarb
+// names them, <package>_<contract>.contract_class.json and
+// <package>_<contract>.compiled_contract_class.json, or as
+// <name>.sierra.json and <name>.casm.json.
+//
+// Parameters:
+// - sierraPath: the path of the Sierra class
+// Returns:
+// - *Artifact: the classes and their hashes
+// - error: ErrCasmNotFound, or an error of LoadSierra or LoadCasm
+func Load(sierraPath string) (*Artifact, error) {
+	casmPath := ""
+	for _, suffixes := range casmSuffixes {
+		if strings.HasSuffix(sierraPath, suffixes[0]) {
+			casmPath = strings.TrimSuffix(sierraPath, suffixes[0]) + suffixes[1]
+			break
+		}
+	}
+	if casmPath == "" {
+		return nil, fmt.Errorf("%w: %s is not named as a Sierra class", ErrCasmNotFound, sierraPath)
+	}
+	return LoadPair(sierraPath, casmPath)
+}
+
+// LoadScarb loads a contract built by Scarb, from the target directory, e.g.
+// target/dev.
+//
+// Parameters:
+// - targetDir: the directory of the build output
+// - pkg: the name of the package
+// - contract: the name of the contract
+// Returns:
+// - *Artifact: the classes and their hashes
+// - error: an error of LoadPair
+func LoadScarb(targetDir, pkg, contract string) (*Artifact, error) {
+	base := filepath.Join(targetDir, pkg+"_"+contract)
+	return LoadPair(base+".contract_class.json", base+".compiled_contract_class.json")
+}
+
+// LoadPair loads a Sierra class and the CASM class compiled from it.
+//
+// Parameters:
+// - sierraPath: the path of the Sierra class
+// - casmPath: the path of the CASM class
+// Returns:
+// - *Artifact: the classes and their hashes
+// - error: ErrCasmNotFound if the CASM class does not exist, or an error of LoadSierra, LoadCasm or the hashes
+func LoadPair(sierraPath, casmPath string) (*Artifact, error) {
+	class, err := LoadSierra(sierraPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(casmPath); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrCasmNotFound, casmPath)
+	}
+	casm, err := LoadCasm(casmPath)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := contracts.ReadMetadata(class.SierraProgram, casm)
+	if err != nil {
+		return nil, err
+	}
+	classHash, err := hash.ClassHash(*class)
+	if err != nil {
+		return nil, err
+	}
+	return &Artifact{
+		Class:             class,
+		Casm:              casm,
+		Metadata:          metadata,
+		ClassHash:         classHash,
+		CompiledClassHash: hash.CompiledClassHash(*casm),
+	}, nil
+}
+
+// LoadSierra loads a Sierra class and checks its versions.
+//
+// Parameters:
+// - path: the path of the class
+// Returns:
+// - *rpc.ContractClass: the class
+// - error: an error wrapping ErrInvalidArtifact, or an error of contracts.ReadMetadata
+func LoadSierra(path string) (*rpc.ContractClass, error) {
+	var class rpc.ContractClass
+	if err := readJSON(path, &class); err != nil {
+		return nil, err
+	}
+	if class.ContractClassVersion != ContractClassVersion {
+		return nil, fmt.Errorf("%w: %s: contract class version %q, expected %q", ErrInvalidArtifact, path, class.ContractClassVersion, ContractClassVersion)
+	}
+	if _, err := contracts.ReadMetadata(class.SierraProgram, nil); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &class, nil
+}
+
+// LoadCasm loads a CASM class and checks its prime and compiler version.
+//
+// Parameters:
+// - path: the path of the class
+// Returns:
+// - *contracts.CasmClass: the class
+// - error: an error wrapping ErrInvalidArtifact, or an error wrapping contracts.ErrInvalidVersion
+func LoadCasm(path string) (*contracts.CasmClass, error) {
+	var casm contracts.CasmClass
+	if err := readJSON(path, &casm); err != nil {
+		return nil, err
+	}
+	if casm.Prime != Prime {
+		return nil, fmt.Errorf("%w: %s: prime %s, expected %s", ErrInvalidArtifact, path, casm.Prime, Prime)
+	}
+	if len(casm.ByteCode) == 0 {
+		return nil, fmt.Errorf("%w: %s: empty bytecode", ErrInvalidArtifact, path)
+	}
+	if _, err := contracts.ParseVersion(casm.Version); err != nil {
+		return nil, fmt.Errorf("%s: compiler version: %w", path, err)
+	}
+	return &casm, nil
+}
+
+// LoadLegacy loads a Cairo 0 class, as output by starknet-compile-deprecated.
+// The program is compressed and encoded as the node expects it.
+//
+// Parameters:
+// - path: the path of the class
+// Returns:
+// - *rpc.DeprecatedContractClass: the class
+// - error: an error wrapping ErrInvalidArtifact
+func LoadLegacy(path string) (*rpc.DeprecatedContractClass, error) {
+	var class rpc.DeprecatedContractClass
+	if err := readJSON(path, &class); err != nil {
+		return nil, err
+	}
+	if class.Program == "" {
+		return nil, fmt.Errorf("%w: %s: empty program", ErrInvalidArtifact, path)
+	}
+	return &class, nil
+}
+
+// readJSON decodes a JSON file.
+//
+// Parameters:
+// - path: the path of the file
+// - v: the value decoded into
+// Returns:
+// - error: an error reading the file, or an error wrapping ErrInvalidArtifact
+func readJSON(path string, v any) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidArtifact, path, err)
+	}
+	return nil
+}
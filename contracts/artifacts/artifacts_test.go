@@ -0,0 +1,156 @@
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/contracts"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// TestLoad tests the loading of the classes output by starknet-compile and
+// Scarb, and the rejection of invalid artifacts.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestLoad(t *testing.T) {
+	artifact, err := Load("../tests/hello_starknet_compiled.sierra.json")
+	require.NoError(t, err)
+	require.Equal(t, contracts.Version{Major: 1, Minor: 3}, artifact.Metadata.SierraVersion)
+	classHash, err := hash.ClassHash(*artifact.Class)
+	require.NoError(t, err)
+	require.Equal(t, classHash, artifact.ClassHash)
+	require.Equal(t, hash.CompiledClassHash(*artifact.Casm), artifact.CompiledClassHash)
+
+	dir := t.TempDir()
+	copyFile(t, "../tests/hello_starknet_compiled.sierra.json", filepath.Join(dir, "hello_HelloStarknet.contract_class.json"))
+	_, err = LoadScarb(dir, "hello", "HelloStarknet")
+	require.True(t, errors.Is(err, ErrCasmNotFound))
+	copyFile(t, "../tests/hello_starknet_compiled.casm.json", filepath.Join(dir, "hello_HelloStarknet.compiled_contract_class.json"))
+	scarb, err := LoadScarb(dir, "hello", "HelloStarknet")
+	require.NoError(t, err)
+	require.Equal(t, artifact.ClassHash, scarb.ClassHash)
+	_, err = Load(filepath.Join(dir, "hello_HelloStarknet.json"))
+	require.True(t, errors.Is(err, ErrCasmNotFound))
+
+	for name, content := range map[string]string{
+		"version.json":  `{"sierra_program": ["0x1", "0x3", "0x0", "0x2", "0x1", "0x0"], "contract_class_version": "0.2.0", "entry_points_by_type": {}}`,
+		"program.json":  `{"sierra_program": [], "contract_class_version": "0.1.0", "entry_points_by_type": {}}`,
+		"notjson.json":  `{"sierra_program": `,
+		"prime.json":    `{"prime": "0x7", "compiler_version": "2.1.0", "bytecode": ["0x1"]}`,
+		"bytecode.json": `{"prime": "` + Prime + `", "compiler_version": "2.1.0", "bytecode": []}`,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, sierraErr := LoadSierra(path)
+		_, casmErr := LoadCasm(path)
+		require.Error(t, sierraErr, name)
+		require.Error(t, casmErr, name)
+	}
+}
+
+// TestLoadLegacy tests the loading of a Cairo 0 class, whose program is
+// compressed for the node.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestLoadLegacy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.json")
+	content := `{"program": {"data": ["0x1"], "prime": "` + Prime + `"}, "entry_points_by_type": {"CONSTRUCTOR": [], "EXTERNAL": [{"offset": "0x3a", "selector": "0x1"}], "L1_HANDLER": []}, "abi": []}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	class, err := LoadLegacy(path)
+	require.NoError(t, err)
+	require.NotEmpty(t, class.Program)
+	require.Len(t, class.DeprecatedEntryPointsByType.External, 1)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"program": "", "entry_points_by_type": {}}`), 0o600))
+	_, err = LoadLegacy(path)
+	require.True(t, errors.Is(err, ErrInvalidArtifact))
+}
+
+// TestVerify tests the comparison of a deployed class with a local class,
+// identical, differing by its ABI or by its program, and the rejection of a
+// class not hashing to the class hash of the contract.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestVerify(t *testing.T) {
+	local, err := LoadSierra("../tests/hello_starknet_compiled.sierra.json")
+	require.NoError(t, err)
+	classHash, err := hash.ClassHash(*local)
+	require.NoError(t, err)
+	address := new(felt.Felt).SetUint64(0x123)
+
+	mockCtrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(mockCtrl)
+	provider.EXPECT().ClassHashAt(gomock.Any(), gomock.Any(), address).Return(classHash, nil).AnyTimes()
+	provider.EXPECT().ClassAt(gomock.Any(), gomock.Any(), address).Return(local, nil).AnyTimes()
+
+	v, err := Verify(context.Background(), provider, address, local)
+	require.NoError(t, err)
+	require.True(t, v.Match)
+	require.True(t, v.BytecodeMatch)
+	require.Empty(t, v.Differences)
+	require.Equal(t, classHash, v.DeployedClassHash)
+
+	abiChanged := *local
+	abiChanged.ABI = local.ABI + " "
+	v, err = Verify(context.Background(), provider, address, &abiChanged)
+	require.NoError(t, err)
+	require.False(t, v.Match)
+	require.True(t, v.BytecodeMatch)
+	require.Equal(t, []string{PartABI}, v.Differences)
+
+	programChanged := *local
+	programChanged.SierraProgram = append([]*felt.Felt{}, local.SierraProgram...)
+	programChanged.SierraProgram[len(programChanged.SierraProgram)-1] = new(felt.Felt).SetUint64(0xdead)
+	v, err = Verify(context.Background(), provider, address, &programChanged)
+	require.NoError(t, err)
+	require.False(t, v.Match)
+	require.False(t, v.BytecodeMatch)
+	require.Equal(t, []string{PartSierraProgram}, v.Differences)
+
+	other := new(felt.Felt).SetUint64(0x456)
+	provider.EXPECT().ClassHashAt(gomock.Any(), gomock.Any(), other).Return(classHash, nil)
+	provider.EXPECT().ClassAt(gomock.Any(), gomock.Any(), other).Return(&programChanged, nil)
+	_, err = Verify(context.Background(), provider, other, local)
+	require.True(t, errors.Is(err, ErrInconsistentClass))
+
+	legacy := new(felt.Felt).SetUint64(0x789)
+	provider.EXPECT().ClassHashAt(gomock.Any(), gomock.Any(), legacy).Return(classHash, nil)
+	provider.EXPECT().ClassAt(gomock.Any(), gomock.Any(), legacy).Return(&rpc.DeprecatedContractClass{}, nil)
+	_, err = Verify(context.Background(), provider, legacy, local)
+	require.True(t, errors.Is(err, ErrLegacyClass))
+}
+
+// copyFile copies a file.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// - from: the source
+// - to: the destination
+// Returns:
+//
+//	none
+func copyFile(t *testing.T, from, to string) {
+	content, err := os.ReadFile(from)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(to, content, 0o600))
+}
@@ -0,0 +1,114 @@
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+var (
+	ErrLegacyClass       = errors.New("contract has a Cairo 0 class")
+	ErrInconsistentClass = errors.New("class returned by the node does not hash to the class hash of the contract")
+)
+
+// Parts of a class reported by Verification.Differences.
+const (
+	PartContractClassVersion = "contract_class_version"
+	PartSierraProgram        = "sierra_program"
+	PartConstructor          = "entry_points_by_type.CONSTRUCTOR"
+	PartExternal             = "entry_points_by_type.EXTERNAL"
+	PartL1Handler            = "entry_points_by_type.L1_HANDLER"
+	PartABI                  = "abi"
+)
+
+// Verification is the comparison of the class of a deployed contract with a
+// local class.
+type Verification struct {
+	Address *felt.Felt
+	// DeployedClassHash is the class hash of the contract on chain
+	DeployedClassHash *felt.Felt
+	// LocalClassHash is the hash of the local class
+	LocalClassHash *felt.Felt
+	// Match is true if the class hashes are equal
+	Match bool
+	// BytecodeMatch is true if the Sierra program and the entry points are
+	// equal, the ABI and the version aside, e.g. for a build differing only
+	// by the formatting of its ABI
+	BytecodeMatch bool
+	// Differences are the parts of the classes differing, e.g. PartSierraProgram
+	Differences []string
+}
+
+// Verify compares the class of a deployed contract with a local Sierra
+// class, e.g. the build of a pipeline checking that the deployed code is the
+// reviewed code. The class is read from the latest block and its hash
+// recomputed, so that a node returning another class than the one of the
+// contract is detected.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider reading the class
+// - address: the address of the contract
+// - local: the local class, e.g. loaded with LoadSierra
+// Returns:
+// - *Verification: the comparison
+// - error: ErrLegacyClass, ErrInconsistentClass, or an error of the provider or of the hashes
+func Verify(ctx context.Context, provider rpc.RpcProvider, address *felt.Felt, local *rpc.ContractClass) (*Verification, error) {
+	latest := rpc.WithBlockTag(rpc.BlockTagLatest)
+	deployedHash, err := provider.ClassHashAt(ctx, latest, address)
+	if err != nil {
+		return nil, err
+	}
+	output, err := provider.ClassAt(ctx, latest, address)
+	if err != nil {
+		return nil, err
+	}
+	deployed, ok := output.(*rpc.ContractClass)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrLegacyClass, address)
+	}
+	fetchedHash, err := hash.ClassHash(*deployed)
+	if err != nil {
+		return nil, err
+	}
+	if !fetchedHash.Equal(deployedHash) {
+		return nil, fmt.Errorf("%w: %s hashes to %s, class hash %s", ErrInconsistentClass, address, fetchedHash, deployedHash)
+	}
+	localHash, err := hash.ClassHash(*local)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Verification{
+		Address:           address,
+		DeployedClassHash: deployedHash,
+		LocalClassHash:    localHash,
+		Match:             localHash.Equal(deployedHash),
+		Differences:       []string{},
+	}
+	bytecode := []bool{
+		reflect.DeepEqual(deployed.SierraProgram, local.SierraProgram),
+		reflect.DeepEqual(deployed.EntryPointsByType.Constructor, local.EntryPointsByType.Constructor),
+		reflect.DeepEqual(deployed.EntryPointsByType.External, local.EntryPointsByType.External),
+		reflect.DeepEqual(deployed.EntryPointsByType.L1Handler, local.EntryPointsByType.L1Handler),
+	}
+	v.BytecodeMatch = true
+	for i, part := range []string{PartSierraProgram, PartConstructor, PartExternal, PartL1Handler} {
+		if !bytecode[i] {
+			v.BytecodeMatch = false
+			v.Differences = append(v.Differences, part)
+		}
+	}
+	if deployed.ContractClassVersion != local.ContractClassVersion {
+		v.Differences = append([]string{PartContractClassVersion}, v.Differences...)
+	}
+	if deployed.ABI != local.ABI {
+		v.Differences = append(v.Differences, PartABI)
+	}
+	return v, nil
+}
@@ -2,6 +2,7 @@ package contracts
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 
 	"github.com/NethermindEth/juno/core/felt"
@@ -12,7 +13,55 @@ type CasmClass struct {
 	Version          string                     `json:"compiler_version"`
 	ByteCode         []*felt.Felt               `json:"bytecode"`
 	EntryPointByType CasmClassEntryPointsByType `json:"entry_points_by_type"`
-	// Hints            any                        `json:"hints"`
+	Hints            []CasmHints                `json:"hints,omitempty"`
+	// BytecodeSegmentLengths are the lengths of the segments of the bytecode,
+	// if it is split in segments
+	BytecodeSegmentLengths []int `json:"bytecode_segment_lengths,omitempty"`
+}
+
+// CasmHints are the hints run before the instruction at Offset in the
+// bytecode. The hints are kept raw, as their many variants are only
+// interpreted by a Cairo VM.
+type CasmHints struct {
+	Offset int
+	Hints  []json.RawMessage
+}
+
+// MarshalJSON marshals the hints as the tuple [offset, hints].
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the JSON data
+// - error: an error if the marshaling fails
+func (h CasmHints) MarshalJSON() ([]byte, error) {
+	hints := h.Hints
+	if hints == nil {
+		hints = []json.RawMessage{}
+	}
+	return json.Marshal([]any{h.Offset, hints})
+}
+
+// UnmarshalJSON unmarshals the hints from the tuple [offset, hints].
+//
+// Parameters:
+// - data: the JSON data
+// Returns:
+// - error: an error if the data is not a tuple of an offset and hints
+func (h *CasmHints) UnmarshalJSON(data []byte) error {
+	var tuple []json.RawMessage
+	if err := json.Unmarshal(data, &tuple); err != nil {
+		return err
+	}
+	if len(tuple) != 2 {
+		return fmt.Errorf("casm hints: expected [offset, hints], got %d elements", len(tuple))
+	}
+	if err := json.Unmarshal(tuple[0], &h.Offset); err != nil {
+		return err
+	}
+	return json.Unmarshal(tuple[1], &h.Hints)
 }
 
 type CasmClassEntryPointsByType struct {
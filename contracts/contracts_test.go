@@ -2,9 +2,11 @@ package contracts_test
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"testing"
 
+	"github.com/NethermindEth/juno/core/felt"
 	"github.com/test-go/testify/assert"
 	"github.com/test-go/testify/require"
 	"github.com/xiang-xx/starknet.go/contracts"
@@ -52,3 +54,83 @@ func TestUnmarshalCasmClass(t *testing.T) {
 	assert.Equal(t, casmClass.EntryPointByType.External[1].Offset, 130)
 	assert.Equal(t, casmClass.EntryPointByType.External[1].Builtins[0], "range_check")
 }
+
+// TestCasmHints tests that the hints of a CASM class round-trip through the
+// [offset, hints] tuples of the compiler output.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestCasmHints(t *testing.T) {
+	casmClass, err := contracts.UnmarshalCasmClass("./tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, casmClass.Hints)
+	assert.Equal(t, 0, casmClass.Hints[0].Offset)
+	require.Len(t, casmClass.Hints[0].Hints, 1)
+	assert.Contains(t, string(casmClass.Hints[0].Hints[0]), "TestLessThanOrEqual")
+
+	data, err := json.Marshal(casmClass.Hints[0])
+	require.NoError(t, err)
+	var hints contracts.CasmHints
+	require.NoError(t, json.Unmarshal(data, &hints))
+	assert.Equal(t, casmClass.Hints[0].Offset, hints.Offset)
+	require.Len(t, hints.Hints, 1)
+	assert.JSONEq(t, string(casmClass.Hints[0].Hints[0]), string(hints.Hints[0]))
+
+	require.Error(t, json.Unmarshal([]byte(`[0]`), &hints))
+}
+
+// TestReadMetadata tests the versions read from the Sierra program and the
+// CASM class, and their check against the versions accepted by Starknet.
+//
+// Parameters:
+// - t: The testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestReadMetadata(t *testing.T) {
+	content, err := os.ReadFile("./tests/hello_starknet_compiled.sierra.json")
+	require.NoError(t, err)
+	var class rpc.ContractClass
+	require.NoError(t, json.Unmarshal(content, &class))
+	casmClass, err := contracts.UnmarshalCasmClass("./tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+
+	meta, err := contracts.ReadMetadata(class.SierraProgram, casmClass)
+	require.NoError(t, err)
+	require.Equal(t, "1.3.0", meta.SierraVersion.String())
+	require.Equal(t, "2.1.0", meta.CompilerVersion.String())
+	require.Equal(t, "2.1.0", meta.CasmCompilerVersion.String())
+
+	legacy, err := contracts.ReadMetadata([]*felt.Felt{new(felt.Felt).SetBytes([]byte("0.1.0"))}, nil)
+	require.NoError(t, err)
+	require.Equal(t, contracts.Version{Minor: 1}, legacy.SierraVersion)
+	_, err = contracts.ReadMetadata(class.SierraProgram[:3], nil)
+	require.True(t, errors.Is(err, contracts.ErrInvalidSierraProgram))
+
+	support, err := contracts.SupportFor("0.13.1.1")
+	require.NoError(t, err)
+	require.Equal(t, contracts.Version{0, 13, 1}, support.StarknetVersion)
+	require.NoError(t, meta.Check(support))
+
+	support, err = contracts.SupportFor("0.12.0")
+	require.NoError(t, err)
+	err = meta.Check(support)
+	require.True(t, errors.Is(err, contracts.ErrUnsupportedSierraVersion))
+	require.Contains(t, err.Error(), "the class is Sierra 1.3.0, Starknet 0.12.0 accepts 0.1.0 - 1.2.0")
+
+	_, err = contracts.SupportFor("0.10.3")
+	require.True(t, errors.Is(err, contracts.ErrUnknownStarknetVersion))
+	_, err = contracts.SupportFor("latest")
+	require.True(t, errors.Is(err, contracts.ErrInvalidVersion))
+
+	pinned := contracts.ChainSupport{
+		Sierra:   contracts.VersionRange{Min: contracts.Version{Major: 1}},
+		Compiler: contracts.VersionRange{Min: contracts.Version{2, 4, 0}, Max: contracts.Version{2, 6, 0}},
+	}
+	err = meta.Check(pinned)
+	require.True(t, errors.Is(err, contracts.ErrUnsupportedCompilerVersion))
+	require.Contains(t, err.Error(), "the class is compiled with 2.1.0, the chain accepts 2.4.0 - 2.6.0")
+}
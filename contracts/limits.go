@@ -0,0 +1,54 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var ErrClassTooLarge = errors.New("class exceeds the size limits of the chain")
+
+// ClassLimits are the size limits a chain enforces on declared classes.
+type ClassLimits struct {
+	// MaxSierraProgramLength is the maximum number of felts of the Sierra program
+	MaxSierraProgramLength int
+	// MaxCasmBytecodeLength is the maximum number of felts of the CASM bytecode
+	MaxCasmBytecodeLength int
+	// MaxClassSize is the maximum size in bytes of the JSON of the Sierra class
+	MaxClassSize int
+}
+
+// DefaultClassLimits are the limits of Starknet mainnet and Sepolia.
+// (ref: https://docs.starknet.io/tools/limits-and-triggers/)
+var DefaultClassLimits = ClassLimits{
+	MaxSierraProgramLength: 81_920,
+	MaxCasmBytecodeLength:  81_920,
+	MaxClassSize:           4_089_446,
+}
+
+// Check checks that a class is within the limits, so that a class too large
+// fails with an explanation before it is sent, instead of a rejection by the
+// gateway. A limit of zero is not checked.
+//
+// Parameters:
+// - sierraProgram: the Sierra program of the class
+// - classSize: the size in bytes of the JSON of the Sierra class
+// - casm: the CASM class, ignored if nil
+// Returns:
+// - error: an error wrapping ErrClassTooLarge describing the exceeded limit
+func (l ClassLimits) Check(sierraProgram []*felt.Felt, classSize int, casm *CasmClass) error {
+	if l.MaxSierraProgramLength > 0 && len(sierraProgram) > l.MaxSierraProgramLength {
+		return fmt.Errorf("%w: the Sierra program has %d felts, the chain accepts %d, split the contract or remove unused code",
+			ErrClassTooLarge, len(sierraProgram), l.MaxSierraProgramLength)
+	}
+	if casm != nil && l.MaxCasmBytecodeLength > 0 && len(casm.ByteCode) > l.MaxCasmBytecodeLength {
+		return fmt.Errorf("%w: the CASM bytecode has %d felts, the chain accepts %d, split the contract or remove unused code",
+			ErrClassTooLarge, len(casm.ByteCode), l.MaxCasmBytecodeLength)
+	}
+	if l.MaxClassSize > 0 && classSize > l.MaxClassSize {
+		return fmt.Errorf("%w: the class is %d bytes, the chain accepts %d, build it without debug information or split the contract",
+			ErrClassTooLarge, classSize, l.MaxClassSize)
+	}
+	return nil
+}
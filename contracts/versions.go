@@ -0,0 +1,256 @@
+package contracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var (
+	ErrInvalidVersion             = errors.New("invalid version")
+	ErrInvalidSierraProgram       = errors.New("invalid sierra program")
+	ErrUnsupportedSierraVersion   = errors.New("sierra version not supported by the chain")
+	ErrUnsupportedCompilerVersion = errors.New("compiler version not supported by the chain")
+	ErrUnknownStarknetVersion     = errors.New("unknown starknet version")
+)
+
+// Version is a semantic version, e.g. of Sierra, of the Cairo compiler or
+// of Starknet.
+type Version struct {
+	Major uint64
+	Minor uint64
+	Patch uint64
+}
+
+// ParseVersion parses a version such as "2.6.3" or "v1.5". Missing
+// components are zero, and a fourth component, as in the Starknet version
+// "0.13.1.1", is ignored.
+//
+// Parameters:
+// - s: the version
+// Returns:
+// - Version: the version
+// - error: an error wrapping ErrInvalidVersion if s is not a version
+func ParseVersion(s string) (Version, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".")
+	if len(parts) > 4 || parts[0] == "" {
+		return Version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+	}
+	var components [3]uint64
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, err := strconv.ParseUint(parts[i], 10, 64)
+		if err != nil {
+			return Version{}, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+		}
+		components[i] = n
+	}
+	return Version{Major: components[0], Minor: components[1], Patch: components[2]}, nil
+}
+
+// String returns the version as major.minor.patch.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the version
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare compares two versions.
+//
+// Parameters:
+// - other: the version to compare to
+// Returns:
+// - int: -1 if v is lower than other, 0 if they are equal, 1 if v is greater
+func (v Version) Compare(other Version) int {
+	for _, d := range [][2]uint64{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		switch {
+		case d[0] < d[1]:
+			return -1
+		case d[0] > d[1]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// VersionRange is an inclusive range of versions. A zero Max leaves the
+// range unbounded above.
+type VersionRange struct {
+	Min Version
+	Max Version
+}
+
+// Contains reports whether a version is in the range.
+//
+// Parameters:
+// - v: the version
+// Returns:
+// - bool: true if Min <= v <= Max
+func (r VersionRange) Contains(v Version) bool {
+	return v.Compare(r.Min) >= 0 && (r.Max == Version{} || v.Compare(r.Max) <= 0)
+}
+
+// String returns the range as "min - max", or ">= min" when unbounded.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the range
+func (r VersionRange) String() string {
+	if r.Max == (Version{}) {
+		return ">= " + r.Min.String()
+	}
+	return r.Min.String() + " - " + r.Max.String()
+}
+
+// ChainSupport are the versions of the classes a chain accepts to declare.
+type ChainSupport struct {
+	// StarknetVersion is the first Starknet version with this support
+	StarknetVersion Version
+	// Sierra is the range of accepted Sierra versions
+	Sierra VersionRange
+	// Compiler is the range of accepted compiler versions, unbounded if zero
+	Compiler VersionRange
+}
+
+// SupportedVersions are the Sierra versions accepted by each Starknet
+// version, in ascending order. Chains running a newer Starknet version than
+// the last entry get the support of the last entry; entries may be appended
+// when a new version is released.
+var SupportedVersions = []ChainSupport{
+	{StarknetVersion: Version{0, 11, 0}, Sierra: VersionRange{Min: Version{0, 1, 0}, Max: Version{1, 0, 0}}},
+	{StarknetVersion: Version{0, 11, 1}, Sierra: VersionRange{Min: Version{0, 1, 0}, Max: Version{1, 1, 0}}},
+	{StarknetVersion: Version{0, 12, 0}, Sierra: VersionRange{Min: Version{0, 1, 0}, Max: Version{1, 2, 0}}},
+	{StarknetVersion: Version{0, 12, 1}, Sierra: VersionRange{Min: Version{0, 1, 0}, Max: Version{1, 3, 0}}},
+	{StarknetVersion: Version{0, 13, 0}, Sierra: VersionRange{Min: Version{0, 1, 0}, Max: Version{1, 4, 0}}},
+	{StarknetVersion: Version{0, 13, 1}, Sierra: VersionRange{Min: Version{0, 1, 0}, Max: Version{1, 5, 0}}},
+	{StarknetVersion: Version{0, 13, 2}, Sierra: VersionRange{Min: Version{0, 1, 0}, Max: Version{1, 6, 0}}},
+}
+
+// SupportFor returns the versions accepted by a Starknet version, from
+// SupportedVersions.
+//
+// Parameters:
+// - starknetVersion: the Starknet version, e.g. the starknet_version of a block header
+// Returns:
+// - ChainSupport: the accepted versions
+// - error: an error if the version can not be parsed, or ErrUnknownStarknetVersion if it predates Sierra
+func SupportFor(starknetVersion string) (ChainSupport, error) {
+	v, err := ParseVersion(starknetVersion)
+	if err != nil {
+		return ChainSupport{}, err
+	}
+	for i := len(SupportedVersions) - 1; i >= 0; i-- {
+		if v.Compare(SupportedVersions[i].StarknetVersion) >= 0 {
+			return SupportedVersions[i], nil
+		}
+	}
+	return ChainSupport{}, fmt.Errorf("%w: %s does not support Sierra classes", ErrUnknownStarknetVersion, starknetVersion)
+}
+
+// ClassMetadata are the versions of the toolchain a class was built with.
+type ClassMetadata struct {
+	// SierraVersion is the version of the Sierra program
+	SierraVersion Version `json:"sierra_version"`
+	// CompilerVersion is the version of the compiler of the Sierra program,
+	// zero for Sierra programs older than 1.1.0 that do not record it
+	CompilerVersion Version `json:"compiler_version"`
+	// CasmCompilerVersion is the version of the compiler of the CASM class
+	CasmCompilerVersion Version `json:"casm_compiler_version"`
+	// ScarbVersion is the version of Scarb, when provided by the caller
+	ScarbVersion string `json:"scarb_version,omitempty"`
+}
+
+// ReadMetadata reads the versions recorded in a Sierra program and its CASM
+// class. Since Sierra 1.1.0, the program starts with the Sierra version and
+// the compiler version, three felts each; older programs start with the
+// Sierra version as a short string.
+//
+// Parameters:
+// - sierraProgram: the Sierra program of the class
+// - casm: the CASM class, ignored if nil
+// Returns:
+// - *ClassMetadata: the versions
+// - error: an error wrapping ErrInvalidSierraProgram or ErrInvalidVersion if a version can not be read
+func ReadMetadata(sierraProgram []*felt.Felt, casm *CasmClass) (*ClassMetadata, error) {
+	if len(sierraProgram) == 0 {
+		return nil, fmt.Errorf("%w: empty program", ErrInvalidSierraProgram)
+	}
+	meta := &ClassMetadata{}
+	if legacy := shortString(sierraProgram[0]); strings.Contains(legacy, ".") {
+		v, err := ParseVersion(legacy)
+		if err != nil {
+			return nil, err
+		}
+		meta.SierraVersion = v
+	} else {
+		if len(sierraProgram) < 6 {
+			return nil, fmt.Errorf("%w: %d felts, the version header has 6", ErrInvalidSierraProgram, len(sierraProgram))
+		}
+		for _, f := range sierraProgram[:6] {
+			if !f.BigInt(new(big.Int)).IsUint64() {
+				return nil, fmt.Errorf("%w: version component %s", ErrInvalidSierraProgram, f)
+			}
+		}
+		meta.SierraVersion = Version{sierraProgram[0].Uint64(), sierraProgram[1].Uint64(), sierraProgram[2].Uint64()}
+		meta.CompilerVersion = Version{sierraProgram[3].Uint64(), sierraProgram[4].Uint64(), sierraProgram[5].Uint64()}
+	}
+	if casm != nil && casm.Version != "" {
+		v, err := ParseVersion(casm.Version)
+		if err != nil {
+			return nil, err
+		}
+		meta.CasmCompilerVersion = v
+	}
+	return meta, nil
+}
+
+// Check checks that a chain accepts the versions of the class.
+//
+// Parameters:
+// - support: the versions accepted by the chain
+// Returns:
+// - error: an error wrapping ErrUnsupportedSierraVersion or ErrUnsupportedCompilerVersion describing the mismatch
+func (m *ClassMetadata) Check(support ChainSupport) error {
+	if !support.Sierra.Contains(m.SierraVersion) {
+		return fmt.Errorf("%w: the class is Sierra %s, Starknet %s accepts %s, compile it with an older compiler",
+			ErrUnsupportedSierraVersion, m.SierraVersion, support.StarknetVersion, support.Sierra)
+	}
+	if support.Compiler == (VersionRange{}) {
+		return nil
+	}
+	for _, v := range []Version{m.CompilerVersion, m.CasmCompilerVersion} {
+		if v != (Version{}) && !support.Compiler.Contains(v) {
+			return fmt.Errorf("%w: the class is compiled with %s, the chain accepts %s", ErrUnsupportedCompilerVersion, v, support.Compiler)
+		}
+	}
+	return nil
+}
+
+// shortString decodes a felt as a short string, or returns "" if it holds
+// non-printable bytes.
+//
+// Parameters:
+// - f: the felt
+// Returns:
+// - string: the short string
+func shortString(f *felt.Felt) string {
+	b := f.Bytes()
+	s := strings.TrimLeft(string(b[:]), "\x00")
+	for _, c := range s {
+		if c < 0x20 || c > 0x7e {
+			return ""
+		}
+	}
+	return s
+}
@@ -0,0 +1,73 @@
+package curve
+
+import (
+	"errors"
+	"math/big"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var ErrBatchLength = errors.New("signature: batch inputs of different lengths")
+
+// VerifyBatch verifies signatures concurrently, on one goroutine per CPU, e.g.
+// for an indexer validating off-chain signatures. The public keys are the x
+// coordinates of the keys, as stored by Starknet accounts: a signature is
+// valid if it verifies with either y coordinate.
+//
+// Parameters:
+// - msgHashes: The message hashes
+// - r: The r components of the signatures
+// - s: The s components of the signatures
+// - pubKeys: The x coordinates of the public keys
+// Returns:
+// - []bool: whether each signature is valid, in the order of the inputs
+// - error: ErrBatchLength if the inputs have different lengths
+func (sc StarkCurve) VerifyBatch(msgHashes, r, s, pubKeys []*big.Int) ([]bool, error) {
+	n := len(msgHashes)
+	if len(r) != n || len(s) != n || len(pubKeys) != n {
+		return nil, ErrBatchLength
+	}
+	valid := make([]bool, n)
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	var next int64 = -1
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= n {
+					return
+				}
+				valid[i] = sc.verifyX(msgHashes[i], r[i], s[i], pubKeys[i])
+			}
+		}()
+	}
+	wg.Wait()
+	return valid, nil
+}
+
+// verifyX verifies a signature with the x coordinate of a public key.
+//
+// Parameters:
+// - msgHash: The message hash
+// - r: The r component of the signature
+// - s: The s component of the signature
+// - pubX: The x coordinate of the public key
+// Returns:
+// - bool: true if the signature verifies with either y coordinate
+func (sc StarkCurve) verifyX(msgHash, r, s, pubX *big.Int) bool {
+	if pubX == nil {
+		return false
+	}
+	pubY := sc.GetYCoordinate(pubX)
+	if pubY == nil {
+		return false
+	}
+	return sc.Verify(msgHash, r, s, pubX, pubY) || sc.Verify(msgHash, r, s, pubX, new(big.Int).Sub(sc.P, pubY))
+}
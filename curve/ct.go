@@ -0,0 +1,400 @@
+package curve
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// The signing path works on secrets, the private key and the nonce k, with
+// the constant-time arithmetic of this file: fixed-size Montgomery field
+// elements with branch-free reductions, complete projective point addition
+// (Renes, Costello, Batina 2016, algorithm 1), which has no exceptional case
+// for doubling or the point at infinity on a prime order curve like the Stark
+// curve, and a Montgomery ladder over all the 256 bits of the scalar. The
+// arithmetic of public values, e.g. in Verify, keeps big.Int.
+
+// ctStark is the constant-time arithmetic of Curve.
+var ctStark *ctCurve
+
+// limbs is a 256-bit integer, least significant limb first.
+type limbs [4]uint64
+
+// montField is a prime field with elements in Montgomery form, x*2^256 mod m.
+type montField struct {
+	m    limbs
+	mInv uint64 // -m^-1 mod 2^64
+	r2   limbs  // 2^512 mod m
+	one  limbs  // 2^256 mod m
+	mBig *big.Int
+}
+
+// newMontField creates the field of an odd modulus below 2^255.
+//
+// Parameters:
+// - m: the modulus
+// Returns:
+// - *montField: the field
+func newMontField(m *big.Int) *montField {
+	f := &montField{m: toLimbs(m), mBig: new(big.Int).Set(m)}
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - f.m[0]*inv
+	}
+	f.mInv = -inv
+	f.r2 = toLimbs(new(big.Int).Mod(new(big.Int).Lsh(big.NewInt(1), 512), m))
+	f.one = toLimbs(new(big.Int).Mod(new(big.Int).Lsh(big.NewInt(1), 256), m))
+	return f
+}
+
+// toLimbs converts an integer below 2^256 to limbs.
+//
+// Parameters:
+// - x: the integer
+// Returns:
+// - limbs: the limbs
+func toLimbs(x *big.Int) limbs {
+	var b [32]byte
+	x.FillBytes(b[:])
+	var l limbs
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 8; j++ {
+			l[3-i] = l[3-i]<<8 | uint64(b[i*8+j])
+		}
+	}
+	return l
+}
+
+// toBig converts limbs to an integer.
+//
+// Parameters:
+// - l: the limbs
+// Returns:
+// - *big.Int: the integer
+func (l limbs) toBig() *big.Int {
+	var b [32]byte
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 8; j++ {
+			b[i*8+j] = byte(l[3-i] >> (56 - 8*j))
+		}
+	}
+	return new(big.Int).SetBytes(b[:])
+}
+
+// fromBig converts an integer to a field element.
+//
+// Parameters:
+// - x: the integer, reduced modulo m if needed
+// Returns:
+// - limbs: the element in Montgomery form
+func (f *montField) fromBig(x *big.Int) limbs {
+	if x.Sign() < 0 || x.Cmp(f.mBig) >= 0 {
+		x = new(big.Int).Mod(x, f.mBig)
+	}
+	l := toLimbs(x)
+	return f.mul(&l, &f.r2)
+}
+
+// toBig converts a field element to an integer.
+//
+// Parameters:
+// - x: the element in Montgomery form
+// Returns:
+// - *big.Int: the integer
+func (f *montField) toBig(x *limbs) *big.Int {
+	one := limbs{1}
+	l := f.mul(x, &one)
+	return l.toBig()
+}
+
+// reduce subtracts m from x if x >= m or if carry is set, without branching.
+//
+// Parameters:
+// - x: the value, below 2m
+// - carry: the bit of x above its limbs
+// Returns:
+// - limbs: x mod m
+func (f *montField) reduce(x limbs, carry uint64) limbs {
+	var u limbs
+	var b uint64
+	u[0], b = bits.Sub64(x[0], f.m[0], 0)
+	u[1], b = bits.Sub64(x[1], f.m[1], b)
+	u[2], b = bits.Sub64(x[2], f.m[2], b)
+	u[3], b = bits.Sub64(x[3], f.m[3], b)
+	// keep x if the subtraction borrowed and x had no carry
+	_, keep := bits.Sub64(carry, 0, b)
+	return selectLimbs(keep, &u, &x)
+}
+
+// add returns x + y.
+//
+// Parameters:
+// - x, y: the elements
+// Returns:
+// - limbs: the sum
+func (f *montField) add(x, y *limbs) limbs {
+	var z limbs
+	var c uint64
+	z[0], c = bits.Add64(x[0], y[0], 0)
+	z[1], c = bits.Add64(x[1], y[1], c)
+	z[2], c = bits.Add64(x[2], y[2], c)
+	z[3], c = bits.Add64(x[3], y[3], c)
+	return f.reduce(z, c)
+}
+
+// sub returns x - y.
+//
+// Parameters:
+// - x, y: the elements
+// Returns:
+// - limbs: the difference
+func (f *montField) sub(x, y *limbs) limbs {
+	var z limbs
+	var b uint64
+	z[0], b = bits.Sub64(x[0], y[0], 0)
+	z[1], b = bits.Sub64(x[1], y[1], b)
+	z[2], b = bits.Sub64(x[2], y[2], b)
+	z[3], b = bits.Sub64(x[3], y[3], b)
+	mask := -b
+	var c uint64
+	z[0], c = bits.Add64(z[0], f.m[0]&mask, 0)
+	z[1], c = bits.Add64(z[1], f.m[1]&mask, c)
+	z[2], c = bits.Add64(z[2], f.m[2]&mask, c)
+	z[3], _ = bits.Add64(z[3], f.m[3]&mask, c)
+	return z
+}
+
+// mul returns x * y, by CIOS Montgomery multiplication.
+//
+// Parameters:
+// - x, y: the elements
+// Returns:
+// - limbs: the product
+func (f *montField) mul(x, y *limbs) limbs {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[j], y[i])
+			var c uint64
+			lo, c = bits.Add64(lo, t[j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			t[j], carry = lo, hi
+		}
+		var c uint64
+		t[4], c = bits.Add64(t[4], carry, 0)
+		t[5] = c
+
+		m := t[0] * f.mInv
+		hi, lo := bits.Mul64(m, f.m[0])
+		_, c = bits.Add64(lo, t[0], 0)
+		carry = hi + c
+		for j := 1; j < 4; j++ {
+			hi, lo = bits.Mul64(m, f.m[j])
+			lo, c = bits.Add64(lo, t[j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			t[j-1], carry = lo, hi
+		}
+		t[3], c = bits.Add64(t[4], carry, 0)
+		t[4] = t[5] + c
+	}
+	return f.reduce(limbs{t[0], t[1], t[2], t[3]}, t[4])
+}
+
+// inv returns x^-1, as x^(m-2): the exponent is public, so the square and
+// multiply sequence does not depend on x.
+//
+// Parameters:
+// - x: the element, not zero
+// Returns:
+// - limbs: the inverse
+func (f *montField) inv(x *limbs) limbs {
+	e := toLimbs(new(big.Int).Sub(f.mBig, big.NewInt(2)))
+	z := f.one
+	for i := 255; i >= 0; i-- {
+		z = f.mul(&z, &z)
+		if e[i/64]>>(i%64)&1 == 1 {
+			z = f.mul(&z, x)
+		}
+	}
+	return z
+}
+
+// selectLimbs returns x if c is 0 and y if c is 1, without branching.
+//
+// Parameters:
+// - c: the condition, 0 or 1
+// - x, y: the values
+// Returns:
+// - limbs: the selected value
+func selectLimbs(c uint64, x, y *limbs) limbs {
+	mask := -c
+	return limbs{
+		x[0]&^mask | y[0]&mask,
+		x[1]&^mask | y[1]&mask,
+		x[2]&^mask | y[2]&mask,
+		x[3]&^mask | y[3]&mask,
+	}
+}
+
+// projPoint is a point of the Stark curve in projective coordinates, with
+// coordinates in the base field.
+type projPoint struct {
+	x, y, z limbs
+}
+
+// ctCurve is the constant-time arithmetic of the Stark curve.
+type ctCurve struct {
+	fp, fn *montField
+	b3     limbs // 3b
+}
+
+// newCtCurve creates the constant-time arithmetic of a curve with a = 1.
+//
+// Parameters:
+// - sc: the curve
+// Returns:
+// - *ctCurve: the arithmetic
+func newCtCurve(sc StarkCurve) *ctCurve {
+	fp := newMontField(sc.P)
+	return &ctCurve{
+		fp: fp,
+		fn: newMontField(sc.N),
+		b3: fp.fromBig(new(big.Int).Mul(sc.B, big.NewInt(3))),
+	}
+}
+
+// add returns p + q with the complete addition formulas for a = 1.
+//
+// Parameters:
+// - p, q: the points
+// Returns:
+// - projPoint: the sum
+func (c *ctCurve) add(p, q *projPoint) projPoint {
+	f := c.fp
+	t0 := f.mul(&p.x, &q.x)
+	t1 := f.mul(&p.y, &q.y)
+	t2 := f.mul(&p.z, &q.z)
+	t3 := f.add(&p.x, &p.y)
+	t4 := f.add(&q.x, &q.y)
+	t3 = f.mul(&t3, &t4)
+	t4 = f.add(&t0, &t1)
+	t3 = f.sub(&t3, &t4)
+	t4 = f.add(&p.x, &p.z)
+	t5 := f.add(&q.x, &q.z)
+	t4 = f.mul(&t4, &t5)
+	t5 = f.add(&t0, &t2)
+	t4 = f.sub(&t4, &t5)
+	t5 = f.add(&p.y, &p.z)
+	x3 := f.add(&q.y, &q.z)
+	t5 = f.mul(&t5, &x3)
+	x3 = f.add(&t1, &t2)
+	t5 = f.sub(&t5, &x3)
+	z3 := t4 // a * t4
+	x3 = f.mul(&c.b3, &t2)
+	z3 = f.add(&x3, &z3)
+	x3 = f.sub(&t1, &z3)
+	z3 = f.add(&t1, &z3)
+	y3 := f.mul(&x3, &z3)
+	t1 = f.add(&t0, &t0)
+	t1 = f.add(&t1, &t0)
+	// t2 = a * t2
+	t4 = f.mul(&c.b3, &t4)
+	t1 = f.add(&t1, &t2)
+	t2 = f.sub(&t0, &t2)
+	// t2 = a * t2
+	t4 = f.add(&t4, &t2)
+	t0 = f.mul(&t1, &t4)
+	y3 = f.add(&y3, &t0)
+	t0 = f.mul(&t5, &t4)
+	x3 = f.mul(&t3, &x3)
+	x3 = f.sub(&x3, &t0)
+	t0 = f.mul(&t3, &t1)
+	z3 = f.mul(&t5, &z3)
+	z3 = f.add(&z3, &t0)
+	return projPoint{x3, y3, z3}
+}
+
+// swap exchanges p and q if c is 1, without branching.
+//
+// Parameters:
+// - c: the condition, 0 or 1
+// - p, q: the points
+// Returns:
+//
+//	none
+func swap(c uint64, p, q *projPoint) {
+	p.x, q.x = selectLimbs(c, &p.x, &q.x), selectLimbs(c, &q.x, &p.x)
+	p.y, q.y = selectLimbs(c, &p.y, &q.y), selectLimbs(c, &q.y, &p.y)
+	p.z, q.z = selectLimbs(c, &p.z, &q.z), selectLimbs(c, &q.z, &p.z)
+}
+
+// mult returns k * (x, y) with a Montgomery ladder over the 256 bits of k, in
+// a time independent of k.
+//
+// Parameters:
+// - k: the scalar, reduced modulo N if not below 2^256
+// - x, y: the affine coordinates of the point
+// Returns:
+// - *big.Int, *big.Int: the affine coordinates of the product, nil for the point at infinity
+func (c *ctCurve) mult(k, x, y *big.Int) (*big.Int, *big.Int) {
+	f := c.fp
+	if k.Sign() < 0 || k.BitLen() > 256 {
+		k = new(big.Int).Mod(k, c.fn.mBig)
+	}
+	r0 := projPoint{y: f.one}
+	r1 := projPoint{x: f.fromBig(x), y: f.fromBig(y), z: f.one}
+	scalar := toLimbs(k)
+	var previous uint64
+	for i := 255; i >= 0; i-- {
+		bit := scalar[i/64] >> (i % 64) & 1
+		swap(bit^previous, &r0, &r1)
+		previous = bit
+		r1 = c.add(&r0, &r1)
+		r0 = c.add(&r0, &r0)
+	}
+	swap(previous, &r0, &r1)
+	return c.affine(&r0)
+}
+
+// affine returns the affine coordinates of a point.
+//
+// Parameters:
+// - p: the point
+// Returns:
+// - *big.Int, *big.Int: the affine coordinates, nil for the point at infinity
+func (c *ctCurve) affine(p *projPoint) (*big.Int, *big.Int) {
+	f := c.fp
+	if p.z == (limbs{}) {
+		return nil, nil
+	}
+	zInv := f.inv(&p.z)
+	x, y := f.mul(&p.x, &zInv), f.mul(&p.y, &zInv)
+	return f.toBig(&x), f.toBig(&y)
+}
+
+// signScalar returns k / (r * privKey + msgHash) mod N, the w of a signature,
+// in a time independent of k and privKey.
+//
+// Parameters:
+// - k: the nonce
+// - r: the r component of the signature
+// - privKey: the private key
+// - msgHash: the message hash
+// Returns:
+// - *big.Int: w, nil if r * privKey + msgHash is zero mod N
+func (c *ctCurve) signScalar(k, r, privKey, msgHash *big.Int) *big.Int {
+	f := c.fn
+	kl, rl, pl, hl := f.fromBig(k), f.fromBig(r), f.fromBig(privKey), f.fromBig(msgHash)
+	agg := f.mul(&rl, &pl)
+	agg = f.add(&agg, &hl)
+	if agg == (limbs{}) {
+		return nil
+	}
+	aggInv := f.inv(&agg)
+	w := f.mul(&kl, &aggInv)
+	return f.toBig(&w)
+}
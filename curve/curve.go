@@ -119,6 +119,8 @@ func init() {
 	Curve.Max, _ = new(big.Int).SetString("3618502788666131106986593281521497120414687020801267626233049500247285301248", 10)              // 2 ** 251
 	Curve.Alpha = big.NewInt(1)
 	Curve.BitSize = 252
+
+	ctStark = newCtCurve(Curve)
 }
 
 // Add computes the sum of two points on the StarkCurve.
@@ -196,7 +198,7 @@ func (sc StarkCurve) Double(x1, y1 *big.Int) (x, y *big.Int) {
 // - y: The y-coordinate of the resulting point.
 func (sc StarkCurve) ScalarMult(x1, y1 *big.Int, k []byte) (x, y *big.Int) {
 	m := new(big.Int).SetBytes(k)
-	x, y = sc.EcMult(m, x1, y1)
+	x, y = ctStark.mult(m, x1, y1)
 	return x, y
 }
 
@@ -312,6 +314,7 @@ func (sc StarkCurve) MimicEcMultAir(mout, x1, y1, x2, y2 *big.Int) (x *big.Int,
 
 // EcMult multiplies a point (equation y^2 = x^3 + alpha*x + beta mod p) on the StarkCurve by a scalar value.
 // Assumes affine form (x, y) is spread (x1 *big.Int, y1 *big.Int) and that 0 < m < order(point).
+// Its time depends on m: secret scalars go through ScalarMult, which runs in constant time.
 // (ref: https://github.com/starkware-libs/cairo-lang/blob/master/src/starkware/crypto/signature/math_utils.py#L91)
 //
 // Parameters:
@@ -374,17 +377,11 @@ func (sc StarkCurve) EcMult(m, x1, y1 *big.Int) (x, y *big.Int) {
 // Returns:
 // - bool: true if the signature is valid, false otherwise
 func (sc StarkCurve) Verify(msgHash, r, s, pubX, pubY *big.Int) bool {
-	w := sc.InvModCurveSize(s)
-
-	if s.Cmp(big.NewInt(0)) != 1 || s.Cmp(sc.N) != -1 {
-		return false
-	}
-	if r.Cmp(big.NewInt(0)) != 1 || r.Cmp(sc.Max) != -1 {
-		return false
-	}
-	if w.Cmp(big.NewInt(0)) != 1 || w.Cmp(sc.Max) != -1 {
+	if msgHash == nil || pubX == nil || pubY == nil || sc.ValidateSignature(r, s) != nil {
 		return false
 	}
+	w := sc.InvModCurveSize(s)
+
 	if msgHash.Cmp(big.NewInt(0)) != 1 || msgHash.Cmp(sc.Max) != -1 {
 		return false
 	}
@@ -437,14 +434,16 @@ func (sc StarkCurve) Verify(msgHash, r, s, pubX, pubY *big.Int) bool {
 }
 
 // Sign calculates the signature of a message using the StarkCurve algorithm.
-// Secret is generated using a golang implementation of RFC 6979.
-// Implementation does not yet include "extra entropy" or "retry gen".
+// Secret is generated using a golang implementation of RFC 6979, so signing
+// is deterministic and does not use the RNG: the same message, key and seed
+// always give the same signature, the one of cairo-lang and starknet.js, which
+// makes signatures reproducible for test vectors and audits.
 // (ref: https://datatracker.ietf.org/doc/html/rfc6979)
 //
 // Parameters:
 // - msgHash: The hash of the message to be signed
 // - privKey: The private key used for signing
-// - seed: (Optional) Additional seed values used for generating the secret
+// - seed: (Optional) Additional data mixed into the secret, e.g. to get another deterministic signature of the same message; it is not modified
 // Returns:
 // - x, y: The coordinates of the signature point on the curve
 // - err: An error if any occurred during the signing process
@@ -460,15 +459,15 @@ func (sc StarkCurve) Sign(msgHash, privKey *big.Int, seed ...*big.Int) (x, y *bi
 	}
 
 	inSeed := big.NewInt(0)
-	if len(seed) == 1 && inSeed != nil {
-		inSeed = seed[0]
+	if len(seed) == 1 && seed[0] != nil {
+		inSeed.Set(seed[0])
 	}
 	for {
 		k := sc.GenerateSecret(big.NewInt(0).Set(msgHash), big.NewInt(0).Set(privKey), big.NewInt(0).Set(inSeed))
 		// In case r is rejected k shall be generated with new seed
 		inSeed = inSeed.Add(inSeed, big.NewInt(1))
 
-		r, _ := sc.EcMult(k, sc.EcGenX, sc.EcGenY)
+		r, _ := ctStark.mult(k, sc.EcGenX, sc.EcGenY)
 
 		// DIFF: in classic ECDSA, we take int(x) % n.
 		if r.Cmp(big.NewInt(0)) != 1 || r.Cmp(sc.Max) != -1 {
@@ -476,15 +475,12 @@ func (sc StarkCurve) Sign(msgHash, privKey *big.Int, seed ...*big.Int) (x, y *bi
 			continue
 		}
 
-		agg := new(big.Int).Mul(r, privKey)
-		agg = agg.Add(agg, msgHash)
-
-		if new(big.Int).Mod(agg, sc.N).Cmp(big.NewInt(0)) == 0 {
+		// w = k / (r * privKey + msgHash) mod N
+		w := ctStark.signScalar(k, r, privKey, msgHash)
+		if w == nil {
 			// Bad value. This fails with negligible probability.
 			continue
 		}
-
-		w := DivMod(k, agg, sc.N)
 		if w.Cmp(big.NewInt(0)) != 1 || w.Cmp(sc.Max) != -1 {
 			// Bad value. This fails with negligible probability.
 			continue
@@ -493,8 +489,6 @@ func (sc StarkCurve) Sign(msgHash, privKey *big.Int, seed ...*big.Int) (x, y *bi
 		s := sc.InvModCurveSize(w)
 		return r, s, nil
 	}
-
-	return x, y, nil
 }
 
 // SignFelt signs a message hash with a private key using the StarkCurve.
@@ -565,14 +559,10 @@ func (sc StarkCurve) ComputeHashOnElements(elems []*big.Int) (hash *big.Int, err
 //
 // The function requires that the precomputed constant points have been initiated.
 // If the length of `sc.ConstantPoints` is zero, an error is returned.
-// The function iterates over the elements in `elems` and performs the Pedersen hash calculation.
-// For each element, it checks if the value is within the valid range.
-// If the value is invalid, an error is returned.
-// For each bit in the element, the function performs an addition operation on `ptx` and `pty`
-// using the corresponding constant point from the precomputed constant points.
-// If the constant point is a duplicate of `ptx`, an error is returned.
-// The function returns the resulting hash and a nil error if the calculation is successful.
-// Otherwise, it returns `ptx` and an error describing the issue encountered.
+// Each element must be within the valid range, and there can not be more elements
+// than the constant points cover, two for the Starknet points.
+// The hash adds to the shift point the constant points of the bits of the elements,
+// using window tables of their sums precomputed on the first call.
 //
 // Parameters:
 // - elems: An array of big integers representing the elements to hash.
@@ -580,34 +570,10 @@ func (sc StarkCurve) ComputeHashOnElements(elems []*big.Int) (hash *big.Int, err
 // - hash: The resulting Pedersen hash as a big integer.
 // - err: An error, if any, encountered during the calculation.
 func (sc StarkCurve) PedersenHash(elems []*big.Int) (hash *big.Int, err error) {
-	if len(sc.ConstantPoints) == 0 {
-		return hash, fmt.Errorf("must initiate precomputed constant points")
-	}
-
-	ptx := new(big.Int).Set(sc.Gx)
-	pty := new(big.Int).Set(sc.Gy)
-	for i, elem := range elems {
-		x := new(big.Int).Set(elem)
-
-		if x.Cmp(big.NewInt(0)) != -1 && x.Cmp(sc.P) != -1 {
-			return ptx, fmt.Errorf("invalid x: %v", x)
-		}
-
-		for j := 0; j < 252; j++ {
-			idx := 2 + (i * 252) + j
-			xin := new(big.Int).Set(sc.ConstantPoints[idx][0])
-			yin := new(big.Int).Set(sc.ConstantPoints[idx][1])
-			if xin.Cmp(ptx) == 0 {
-				return hash, fmt.Errorf("constant point duplication: %v %v", ptx, xin)
-			}
-			if x.Bit(0) == 1 {
-				ptx, pty = sc.Add(ptx, pty, xin, yin)
-			}
-			x = x.Rsh(x, 1)
-		}
+	if err := sc.checkPedersenElements(elems); err != nil {
+		return hash, err
 	}
-
-	return ptx, nil
+	return sc.pedersen(elems...), nil
 }
 
 // PoseidonArray is a function that takes a variadic number of felt.Felt pointers as parameters and
@@ -735,6 +701,6 @@ func (sc StarkCurve) PrivateToPoint(privKey *big.Int) (x, y *big.Int, err error)
 	if privKey.Cmp(big.NewInt(0)) != 1 || privKey.Cmp(sc.N) != -1 {
 		return x, y, fmt.Errorf("private key not in curve range")
 	}
-	x, y = sc.EcMult(privKey, sc.EcGenX, sc.EcGenY)
+	x, y = ctStark.mult(privKey, sc.EcGenX, sc.EcGenY)
 	return x, y, nil
 }
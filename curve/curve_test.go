@@ -6,6 +6,8 @@ import (
 	"math/big"
 	"testing"
 
+	junoCrypto "github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
 	"github.com/xiang-xx/starknet.go/utils"
 )
 
@@ -362,6 +364,46 @@ func TestGeneral_HashAndSign(t *testing.T) {
 	}
 }
 
+// TestGeneral_DeterministicSign tests that signing reproduces the signature
+// of the cairo-lang test vector, and that a seed gives another reproducible
+// signature without being modified.
+//
+// Parameters:
+// - t: The testing.T object for running the test
+// Returns:
+//
+//	none
+func TestGeneral_DeterministicSign(t *testing.T) {
+	hash := utils.HexToBN("0xc465dd6b1bbffdb05442eb17f5ca38ad1aa78a6f56bf4415bdee219114a47")
+	priv := utils.HexToBN("0x2dccce1da22003777062ee0870e9881b460a8b7eca276870f57c601f182136c")
+
+	for i := 0; i < 2; i++ {
+		r, s, err := Curve.Sign(hash, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Text(16) != "5f496f6f210b5810b2711c74c15c05244dad43d18ecbbdbe6ed55584bc3b0a2" || s.Text(16) != "4e8657b153787f741a67c0666bad6426c3741b478c8eaa3155196fc571416f3" {
+			t.Fatalf("unexpected signature %x %x", r, s)
+		}
+	}
+
+	seed := big.NewInt(7)
+	r1, s1, err := Curve.Sign(hash, priv, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, s2, err := Curve.Sign(hash, priv, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r1.Cmp(r2) != 0 || s1.Cmp(s2) != 0 || seed.Cmp(big.NewInt(7)) != 0 {
+		t.Fatalf("seeded signatures differ: %x %x, %x %x", r1, s1, r2, s2)
+	}
+	if r1.Text(16) == "5f496f6f210b5810b2711c74c15c05244dad43d18ecbbdbe6ed55584bc3b0a2" {
+		t.Fatal("the seed did not change the signature")
+	}
+}
+
 // TestGeneral_ComputeFact tests the ComputeFact function.
 //
 // It tests the ComputeFact function by providing a set of test cases
@@ -537,3 +579,311 @@ func TestGeneral_SplitFactStr(t *testing.T) {
 		}
 	}
 }
+
+// TestGeneral_NormalizeSignature tests the validation and low-s normalization
+// of signatures. A signature (r, s) and its malleated form (r, N-s) both
+// verify; normalization maps both to the form with s <= N/2, which is the
+// only one accepted by VerifyStrict.
+//
+// Parameters:
+// - t: The testing.T object for running the test
+// Returns:
+//
+//	none
+func TestGeneral_NormalizeSignature(t *testing.T) {
+	hash, err := Curve.PedersenHash([]*big.Int{utils.HexToBN("0x12773"), utils.HexToBN("0x872362")})
+	if err != nil {
+		t.Fatalf("Hashing err: %v", err)
+	}
+	priv, _ := Curve.GetRandomPrivateKey()
+	x, y, err := Curve.PrivateToPoint(priv)
+	if err != nil {
+		t.Fatalf("Could not convert random private key to point: %v", err)
+	}
+	r, s, err := Curve.Sign(hash, priv)
+	if err != nil {
+		t.Fatalf("Could not sign: %v", err)
+	}
+
+	malleated := new(big.Int).Sub(Curve.N, s)
+	if !Curve.Verify(hash, r, malleated, x, y) {
+		t.Fatal("the malleated signature should verify")
+	}
+	low, high := s, malleated
+	if !Curve.IsLowS(s) {
+		low, high = malleated, s
+	}
+	for _, in := range []*big.Int{low, high} {
+		nr, ns, err := Curve.NormalizeSignature(r, in)
+		if err != nil || nr.Cmp(r) != 0 || ns.Cmp(low) != 0 {
+			t.Fatalf("normalizing %v should give %v, got %v, %v", in, low, ns, err)
+		}
+	}
+	if err := Curve.VerifyStrict(hash, r, low, x, y); err != nil {
+		t.Fatalf("the normalized signature should verify strictly: %v", err)
+	}
+	if err := Curve.VerifyStrict(hash, r, high, x, y); err != ErrHighS {
+		t.Fatalf("the high-s signature should be rejected with ErrHighS, got %v", err)
+	}
+	if err := Curve.VerifyStrict(new(big.Int).Add(hash, big.NewInt(1)), r, low, x, y); err != ErrInvalidSignature {
+		t.Fatalf("a signature of another message should be invalid, got %v", err)
+	}
+
+	felts, err := Curve.NormalizeSignatureFelts([]*felt.Felt{utils.BigIntToFelt(r), utils.BigIntToFelt(high)})
+	if err != nil || utils.FeltToBigInt(felts[1]).Cmp(low) != 0 {
+		t.Fatalf("normalizing felts should give %v, got %v, %v", low, felts, err)
+	}
+
+	for _, tc := range []struct {
+		r, s *big.Int
+		err  error
+	}{
+		{nil, s, ErrNilSignature},
+		{big.NewInt(0), s, ErrZeroSignature},
+		{r, big.NewInt(0), ErrZeroSignature},
+		{Curve.Max, s, ErrSignatureOutOfRange},
+		{r, Curve.N, ErrSignatureOutOfRange},
+		{big.NewInt(-1), s, ErrSignatureOutOfRange},
+	} {
+		if err := Curve.ValidateSignature(tc.r, tc.s); err != tc.err {
+			t.Fatalf("validating (%v, %v) should fail with %v, got %v", tc.r, tc.s, tc.err, err)
+		}
+		if tc.r != nil && Curve.Verify(hash, tc.r, tc.s, x, y) {
+			t.Fatalf("(%v, %v) should not verify", tc.r, tc.s)
+		}
+	}
+	if Curve.Verify(hash, r, nil, x, y) {
+		t.Fatal("a signature without s should not verify")
+	}
+}
+
+// TestGeneral_VerifyBatch tests that a batch flags the invalid signatures,
+// whatever the y coordinate of the signing keys.
+//
+// Parameters:
+// - t: a *testing.T value representing the testing context
+// Returns:
+//
+//	none
+func TestGeneral_VerifyBatch(t *testing.T) {
+	const n = 16
+	hashes, rs, ss, keys := make([]*big.Int, n), make([]*big.Int, n), make([]*big.Int, n), make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		private, err := Curve.GetRandomPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, _, err := Curve.PrivateToPoint(private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes[i] = big.NewInt(int64(i + 1))
+		if rs[i], ss[i], err = Curve.Sign(hashes[i], private); err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = x
+	}
+	hashes[3] = big.NewInt(1000)
+	keys[5] = keys[6]
+
+	valid, err := Curve.VerifyBatch(hashes, rs, ss, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ok := range valid {
+		if expected := i != 3 && i != 5; ok != expected {
+			t.Errorf("signature %d: expected valid %v, got %v", i, expected, ok)
+		}
+	}
+
+	if _, err := Curve.VerifyBatch(hashes, rs, ss, keys[1:]); err != ErrBatchLength {
+		t.Fatalf("expected ErrBatchLength, got %v", err)
+	}
+}
+
+// BenchmarkVerifyBatch benchmarks the verification of a batch of signatures.
+//
+// Parameters:
+// - b: a *testing.B value representing the testing context
+// Returns:
+//
+//	none
+func BenchmarkVerifyBatch(b *testing.B) {
+	const n = 64
+	private, _ := Curve.GetRandomPrivateKey()
+	x, _, _ := Curve.PrivateToPoint(private)
+	hashes, rs, ss, keys := make([]*big.Int, n), make([]*big.Int, n), make([]*big.Int, n), make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		hashes[i] = big.NewInt(int64(i + 1))
+		rs[i], ss[i], _ = Curve.Sign(hashes[i], private)
+		keys[i] = x
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Curve.VerifyBatch(hashes, rs, ss, keys)
+	}
+}
+
+// TestGeneral_ConstantTimeMult tests that the constant-time scalar
+// multiplication and field arithmetic give the results of their big.Int
+// counterparts.
+//
+// Parameters:
+// - t: The testing.T object for running the test
+// Returns:
+//
+//	none
+func TestGeneral_ConstantTimeMult(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		k, err := Curve.GetRandomPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, y := ctStark.mult(k, Curve.EcGenX, Curve.EcGenY)
+		ex, ey := Curve.EcMult(k, Curve.EcGenX, Curve.EcGenY)
+		if x.Cmp(ex) != 0 || y.Cmp(ey) != 0 {
+			t.Fatalf("mult(%x) = (%x, %x), expected (%x, %x)", k, x, y, ex, ey)
+		}
+
+		a, b := ctStark.fp.fromBig(k), ctStark.fp.fromBig(ex)
+		product := ctStark.fp.mul(&a, &b)
+		expected := new(big.Int).Mul(k, ex)
+		if got := ctStark.fp.toBig(&product); got.Cmp(expected.Mod(expected, Curve.P)) != 0 {
+			t.Fatalf("mul = %x, expected %x", got, expected)
+		}
+		inverse := ctStark.fp.inv(&a)
+		if got := ctStark.fp.toBig(&inverse); got.Cmp(new(big.Int).ModInverse(k, Curve.P)) != 0 {
+			t.Fatalf("inv = %x, expected %x", got, new(big.Int).ModInverse(k, Curve.P))
+		}
+	}
+
+	if x, y := ctStark.mult(Curve.N, Curve.EcGenX, Curve.EcGenY); x != nil || y != nil {
+		t.Errorf("N * G = (%x, %x), expected the point at infinity", x, y)
+	}
+	x, y := ctStark.mult(new(big.Int).Add(Curve.N, big.NewInt(1)), Curve.EcGenX, Curve.EcGenY)
+	if x.Cmp(Curve.EcGenX) != 0 || y.Cmp(Curve.EcGenY) != 0 {
+		t.Errorf("(N + 1) * G = (%x, %x), expected G", x, y)
+	}
+}
+
+// TestGeneral_PedersenArray tests that the window tables of the Pedersen hash
+// give the hashes of the Juno implementation, for PedersenHash, PedersenArray
+// and ComputeHashOnElements, and that PedersenHash rejects what the constant
+// points can not hash.
+//
+// Parameters:
+// - t: The testing.T object for running the test
+// Returns:
+//
+//	none
+func TestGeneral_PedersenArray(t *testing.T) {
+	max := new(felt.Felt).Sub(new(felt.Felt), new(felt.Felt).SetUint64(1))
+	elems := []*felt.Felt{new(felt.Felt), new(felt.Felt).SetUint64(1), max}
+	for i := 0; i < 8; i++ {
+		elem, err := new(felt.Felt).SetRandom()
+		if err != nil {
+			t.Fatal(err)
+		}
+		elems = append(elems, elem)
+	}
+
+	for i := 0; i+1 < len(elems); i++ {
+		hash, err := Curve.PedersenHash([]*big.Int{utils.FeltToBigInt(elems[i]), utils.FeltToBigInt(elems[i+1])})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := junoCrypto.Pedersen(elems[i], elems[i+1]); !utils.BigIntToFelt(hash).Equal(expected) {
+			t.Errorf("PedersenHash(%s, %s) = %x, expected %s", elems[i], elems[i+1], hash, expected)
+		}
+	}
+
+	for n := 0; n <= len(elems); n++ {
+		expected := junoCrypto.PedersenArray(elems[:n]...)
+		if got := Curve.PedersenArray(elems[:n]...); !got.Equal(expected) {
+			t.Errorf("PedersenArray of %d elements = %s, expected %s", n, got, expected)
+		}
+		hash, err := Curve.ComputeHashOnElements(utils.FeltArrToBigIntArr(elems[:n]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !utils.BigIntToFelt(hash).Equal(expected) {
+			t.Errorf("ComputeHashOnElements of %d elements = %x, expected %s", n, hash, expected)
+		}
+	}
+
+	if _, err := Curve.PedersenHash([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}); err == nil {
+		t.Error("PedersenHash of 3 elements should fail")
+	}
+	if _, err := Curve.PedersenHash([]*big.Int{Curve.P, big.NewInt(1)}); err == nil {
+		t.Error("PedersenHash of P should fail")
+	}
+}
+
+// BenchmarkPedersenArray benchmarks the Pedersen hash on the elements of a
+// multicall of 50 calls.
+//
+// Parameters:
+// - b: a *testing.B value representing the testing context
+// Returns:
+//
+//	none
+func BenchmarkPedersenArray(b *testing.B) {
+	elems := make([]*felt.Felt, 50*4)
+	for i := range elems {
+		elems[i], _ = new(felt.Felt).SetRandom()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Curve.PedersenArray(elems...)
+	}
+}
+
+// TestGeneral_Recover tests that the public key of a signature is one of the
+// candidates recovered, that every candidate verifies the signature, and the
+// rejection of invalid signatures.
+//
+// Parameters:
+// - t: The testing.T object for running the test
+// Returns:
+//
+//	none
+func TestGeneral_Recover(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		private, err := Curve.GetRandomPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		x, _, err := Curve.PrivateToPoint(private)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgHash := big.NewInt(int64(1000 + i))
+		r, s, err := Curve.Sign(msgHash, private)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		candidates, err := Curve.Recover(msgHash, r, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !containsInt(candidates, x) {
+			t.Fatalf("public key %s not in the candidates %v", x, candidates)
+		}
+		for _, candidate := range candidates {
+			if !Curve.verifyX(msgHash, r, s, candidate) {
+				t.Errorf("candidate %s does not verify the signature", candidate)
+			}
+		}
+		if other, err := Curve.Recover(new(big.Int).Add(msgHash, big.NewInt(1)), r, s); err != nil || containsInt(other, x) {
+			t.Errorf("public key recovered from another message: %v", err)
+		}
+	}
+
+	if _, err := Curve.Recover(big.NewInt(1), big.NewInt(0), big.NewInt(1)); err != ErrZeroSignature {
+		t.Errorf("expected ErrZeroSignature, got %v", err)
+	}
+	if _, err := Curve.Recover(Curve.Max, big.NewInt(1), big.NewInt(1)); err != ErrInvalidMsgHash {
+		t.Errorf("expected ErrInvalidMsgHash, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package curve
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// The Pedersen hash of two elements adds to the shift point the constant
+// points P_i * 2^j of their bits. Rather than one affine addition per bit, the
+// hash looks up the sums of the constant points of windows of 4 bits in tables
+// precomputed once, adds them in projective coordinates, and converts the
+// result to affine coordinates with a single inversion. The inputs of the
+// hash are public, so the lookups do not need to be constant time.
+
+const (
+	pedersenBits    = 252
+	pedersenWindow  = 4
+	pedersenWindows = pedersenBits / pedersenWindow
+)
+
+// pedersenTable holds, for an element of the hash and a window of its bits,
+// the sums of the constant points of all the values of the window.
+type pedersenTable [pedersenWindows][1 << pedersenWindow]projPoint
+
+var (
+	pedersenOnce   sync.Once
+	pedersenTables []pedersenTable
+)
+
+// pedersenPrecompute builds the window tables of the constant points.
+//
+// Parameters:
+// - sc: the curve holding the constant points
+// Returns:
+//
+//	none
+func pedersenPrecompute(sc StarkCurve) {
+	f := ctStark.fp
+	tables := make([]pedersenTable, (len(sc.ConstantPoints)-2)/pedersenBits)
+	for i := range tables {
+		for w := range tables[i] {
+			window := &tables[i][w]
+			window[0] = projPoint{y: f.one}
+			for v := 1; v < len(window); v++ {
+				// the sum of v is the sum of v without its lowest bit, plus
+				// the point of that bit
+				bit := 0
+				for v>>bit&1 == 0 {
+					bit++
+				}
+				point := sc.ConstantPoints[2+i*pedersenBits+w*pedersenWindow+bit]
+				q := projPoint{x: f.fromBig(point[0]), y: f.fromBig(point[1]), z: f.one}
+				window[v] = ctStark.add(&window[v&(v-1)], &q)
+			}
+		}
+	}
+	pedersenTables = tables
+}
+
+// pedersen calculates the Pedersen hash of elements checked to be below P.
+//
+// Parameters:
+// - sc: the curve holding the constant points
+// - elems: the elements, at most one per table
+// Returns:
+// - *big.Int: the hash
+func (sc StarkCurve) pedersen(elems ...*big.Int) *big.Int {
+	pedersenOnce.Do(func() { pedersenPrecompute(sc) })
+	f := ctStark.fp
+	p := projPoint{x: f.fromBig(sc.Gx), y: f.fromBig(sc.Gy), z: f.one}
+	for i, elem := range elems {
+		x := toLimbs(elem)
+		for w := range pedersenTables[i] {
+			bit := w * pedersenWindow
+			v := x[bit/64] >> (bit % 64) & (1<<pedersenWindow - 1)
+			if v != 0 {
+				p = ctStark.add(&p, &pedersenTables[i][w][v])
+			}
+		}
+	}
+	hash, _ := ctStark.affine(&p)
+	return hash
+}
+
+// PedersenArray calculates the Pedersen hash on elements of an array,
+// H(H(H(H(0, a_1), a_2), ...), n), as ComputeHashOnElements does for big.Int
+// values, without converting the elements nor allocating a slice per hash.
+// (ref: https://docs.starknet.io/architecture-and-concepts/cryptography/hash-functions/#array_hashing)
+//
+// Parameters:
+// - felts: A variadic number of pointers to felt.Felt
+// Returns:
+// - *felt.Felt: pointer to a felt.Felt
+func (sc StarkCurve) PedersenArray(felts ...*felt.Felt) *felt.Felt {
+	hash := new(big.Int)
+	element := new(big.Int)
+	for _, f := range felts {
+		hash = sc.pedersen(hash, f.BigInt(element))
+	}
+	hash = sc.pedersen(hash, element.SetInt64(int64(len(felts))))
+	return new(felt.Felt).SetBigInt(hash)
+}
+
+// checkPedersenElements checks that elements can be hashed with the constant
+// points of the curve.
+//
+// Parameters:
+// - elems: the elements
+// Returns:
+// - error: an error if the points are not initiated, or an element is out of range
+func (sc StarkCurve) checkPedersenElements(elems []*big.Int) error {
+	if len(sc.ConstantPoints) == 0 {
+		return fmt.Errorf("must initiate precomputed constant points")
+	}
+	if max := (len(sc.ConstantPoints) - 2) / pedersenBits; len(elems) > max {
+		return fmt.Errorf("can not hash %d elements with constant points for %d", len(elems), max)
+	}
+	for _, elem := range elems {
+		if elem.Sign() < 0 || elem.Cmp(sc.P) != -1 {
+			return fmt.Errorf("invalid x: %v", elem)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package curve
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var (
+	ErrNilSignature        = errors.New("signature: missing component")
+	ErrZeroSignature       = errors.New("signature: zero component")
+	ErrSignatureOutOfRange = errors.New("signature: component out of range")
+	ErrHighS               = errors.New("signature: s is not normalized")
+	ErrInvalidSignature    = errors.New("signature: invalid signature")
+	ErrInvalidMsgHash      = errors.New("signature: message hash out of range")
+)
+
+// ValidateSignature checks the ranges of the components of a signature, as
+// checked by Verify and by the ECDSA builtin: 0 < r < 2^251, 0 < s < N and
+// 0 < s^-1 < 2^251. Third-party signatures should be validated before being
+// stored or submitted.
+//
+// Parameters:
+// - r: The r component of the signature
+// - s: The s component of the signature
+// Returns:
+// - error: ErrNilSignature, ErrZeroSignature or ErrSignatureOutOfRange if a component is invalid
+func (sc StarkCurve) ValidateSignature(r, s *big.Int) error {
+	if r == nil || s == nil {
+		return ErrNilSignature
+	}
+	if r.Sign() == 0 || s.Sign() == 0 {
+		return ErrZeroSignature
+	}
+	if r.Sign() < 0 || r.Cmp(sc.Max) != -1 || s.Sign() < 0 || s.Cmp(sc.N) != -1 {
+		return ErrSignatureOutOfRange
+	}
+	if w := sc.InvModCurveSize(s); w.Sign() != 1 || w.Cmp(sc.Max) != -1 {
+		return ErrSignatureOutOfRange
+	}
+	return nil
+}
+
+// IsLowS reports whether the s component of a signature is normalized,
+// i.e. s <= N/2. Signatures are malleable: (r, s) and (r, N-s) verify for
+// the same message and key, so services identifying signatures by their value
+// should only accept normalized signatures.
+//
+// Parameters:
+// - s: The s component of the signature
+// Returns:
+// - bool: true if s <= N/2
+func (sc StarkCurve) IsLowS(s *big.Int) bool {
+	return s.Cmp(new(big.Int).Rsh(sc.N, 1)) <= 0
+}
+
+// NormalizeSignature validates a signature and returns its normalized form,
+// replacing s by N-s when s > N/2. The normalized signature verifies for the
+// same message and key.
+//
+// Parameters:
+// - r: The r component of the signature
+// - s: The s component of the signature
+// Returns:
+// - *big.Int: The r component of the normalized signature
+// - *big.Int: The s component of the normalized signature
+// - error: An error if the signature is invalid, or if its normalized form is out of range
+func (sc StarkCurve) NormalizeSignature(r, s *big.Int) (*big.Int, *big.Int, error) {
+	if err := sc.ValidateSignature(r, s); err != nil {
+		return nil, nil, err
+	}
+	if sc.IsLowS(s) {
+		return new(big.Int).Set(r), new(big.Int).Set(s), nil
+	}
+	low := new(big.Int).Sub(sc.N, s)
+	if err := sc.ValidateSignature(r, low); err != nil {
+		return nil, nil, err
+	}
+	return new(big.Int).Set(r), low, nil
+}
+
+// VerifyStrict verifies a signature like Verify, and additionally rejects
+// signatures that are not normalized.
+//
+// Parameters:
+// - msgHash: The message hash to be verified
+// - r: The r component of the signature
+// - s: The s component of the signature
+// - pubX: The x-coordinate of the public key used for verification
+// - pubY: The y-coordinate of the public key used for verification
+// Returns:
+// - error: ErrHighS if s is not normalized, a validation error, or ErrInvalidSignature
+func (sc StarkCurve) VerifyStrict(msgHash, r, s, pubX, pubY *big.Int) error {
+	if err := sc.ValidateSignature(r, s); err != nil {
+		return err
+	}
+	if !sc.IsLowS(s) {
+		return ErrHighS
+	}
+	if !sc.Verify(msgHash, r, s, pubX, pubY) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// NormalizeSignatureFelts normalizes a signature given as felts, e.g. the
+// signature of a transaction.
+//
+// Parameters:
+// - signature: The r and s components of the signature
+// Returns:
+// - []*felt.Felt: The normalized signature
+// - error: An error if the signature does not have two components or is invalid
+func (sc StarkCurve) NormalizeSignatureFelts(signature []*felt.Felt) ([]*felt.Felt, error) {
+	if len(signature) != 2 || signature[0] == nil || signature[1] == nil {
+		return nil, ErrNilSignature
+	}
+	r, s, err := sc.NormalizeSignature(signature[0].BigInt(new(big.Int)), signature[1].BigInt(new(big.Int)))
+	if err != nil {
+		return nil, err
+	}
+	return []*felt.Felt{new(felt.Felt).SetBigInt(r), new(felt.Felt).SetBigInt(s)}, nil
+}
+
+// Recover returns the candidate public keys of a signature: the x coordinates
+// of the keys for which the signature verifies. A signature does not commit
+// to the y coordinate of its point R, so there are two candidates, or more
+// when r + N is also on the curve below the field prime. A verifier knowing
+// the public key stored by an account, e.g. after a lookup of the account
+// address, checks that it is one of the candidates.
+//
+// Parameters:
+// - msgHash: The hash of the signed message
+// - r: The r component of the signature
+// - s: The s component of the signature
+// Returns:
+// - []*big.Int: The x coordinates of the candidate public keys
+// - error: ErrInvalidMsgHash, a validation error, or ErrInvalidSignature if r is not the x coordinate of a point
+func (sc StarkCurve) Recover(msgHash, r, s *big.Int) ([]*big.Int, error) {
+	if err := sc.ValidateSignature(r, s); err != nil {
+		return nil, err
+	}
+	if msgHash == nil || msgHash.Sign() != 1 || msgHash.Cmp(sc.Max) != -1 {
+		return nil, ErrInvalidMsgHash
+	}
+
+	// Q = r^-1 (s R - z G)
+	rInv := sc.InvModCurveSize(r)
+	u1 := new(big.Int).Mul(new(big.Int).Neg(msgHash), rInv)
+	u1.Mod(u1, sc.N)
+	u2 := new(big.Int).Mul(s, rInv)
+	u2.Mod(u2, sc.N)
+	gx, gy := ctStark.mult(u1, sc.EcGenX, sc.EcGenY)
+
+	var candidates []*big.Int
+	for x := new(big.Int).Set(r); x.Cmp(sc.P) == -1; x = new(big.Int).Add(x, sc.N) {
+		y := sc.GetYCoordinate(x)
+		if y == nil {
+			continue
+		}
+		for _, ry := range []*big.Int{y, new(big.Int).Sub(sc.P, y)} {
+			rx, ryu := ctStark.mult(u2, x, ry)
+			if rx == nil {
+				continue
+			}
+			qx, _ := sc.addPoints(rx, ryu, gx, gy)
+			if qx != nil && !containsInt(candidates, qx) {
+				candidates = append(candidates, qx)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, ErrInvalidSignature
+	}
+	return candidates, nil
+}
+
+// addPoints adds two points of the curve like Add, handling the doubling of
+// a point and the sum of a point and its opposite.
+//
+// Parameters:
+// - x1, y1: The coordinates of the first point
+// - x2, y2: The coordinates of the second point
+// Returns:
+// - x, y: The coordinates of the sum, nil for the point at infinity
+func (sc StarkCurve) addPoints(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	if x1.Cmp(x2) != 0 {
+		return sc.Add(x1, y1, x2, y2)
+	}
+	if y1.Cmp(y2) == 0 && y1.Sign() != 0 {
+		return sc.Double(x1, y1)
+	}
+	return nil, nil
+}
+
+// containsInt reports whether a slice holds an integer.
+//
+// Parameters:
+// - ints: The slice
+// - x: The integer
+// Returns:
+// - bool: true if x is in ints
+func containsInt(ints []*big.Int, x *big.Int) bool {
+	for _, i := range ints {
+		if i.Cmp(x) == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package devnet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// PredeployedAccount is a funded account of the DevNet, ready to send
+// transactions.
+type PredeployedAccount struct {
+	*account.Account
+	PrivateKey *felt.Felt
+	PublicKey  *felt.Felt
+	// ClassHash is the class of the account, whose Cairo version is the
+	// CairoVersion of the Account.
+	ClassHash *felt.Felt
+}
+
+// PredeployedAccounts returns the predeployed accounts of the DevNet, with
+// their keys and their class. The Cairo version of each account is read from
+// its class, so that DevNets started with Cairo 0 accounts
+// (--account-class cairo0) and with Cairo 1 accounts are both supported.
+//
+// Parameters:
+// - ctx: the context of the requests
+// Returns:
+// - []PredeployedAccount: the accounts, in the order of the DevNet
+// - error: an error if the accounts or their classes cannot be read
+func (devnet *DevNet) PredeployedAccounts(ctx context.Context) ([]PredeployedAccount, error) {
+	testAccounts, err := devnet.accounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	provider := devnet.Provider()
+	chainID, err := provider.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the predeployed accounts share a class, read once
+	cairoVersions := map[felt.Felt]int{}
+	accounts := make([]PredeployedAccount, len(testAccounts))
+	for i, testAccount := range testAccounts {
+		address, err := new(felt.Felt).SetString(testAccount.Address)
+		if err != nil {
+			return nil, fmt.Errorf("devnet: account %d: %w", i, err)
+		}
+		privateKey, err := new(felt.Felt).SetString(testAccount.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("devnet: account %s: %w", address, err)
+		}
+		publicKey, err := new(felt.Felt).SetString(testAccount.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("devnet: account %s: %w", address, err)
+		}
+		classHash, err := provider.ClassHashAt(ctx, rpc.WithBlockTag(rpc.BlockTagLatest), address)
+		if err != nil {
+			return nil, fmt.Errorf("devnet: account %s: %w", address, err)
+		}
+		cairoVersion, ok := cairoVersions[*classHash]
+		if !ok {
+			class, err := provider.Class(ctx, rpc.WithBlockTag(rpc.BlockTagLatest), classHash)
+			if err != nil {
+				return nil, fmt.Errorf("devnet: class %s: %w", classHash, err)
+			}
+			cairoVersion = 2
+			if _, deprecated := class.(*rpc.DeprecatedContractClass); deprecated {
+				cairoVersion = 0
+			}
+			cairoVersions[*classHash] = cairoVersion
+		}
+
+		ks := account.SetNewMemKeystore(publicKey.String(), privateKey.BigInt(new(big.Int)))
+		acc, err := account.NewAccount(provider, address, publicKey.String(), ks, cairoVersion, account.WithChainID(chainID))
+		if err != nil {
+			return nil, err
+		}
+		accounts[i] = PredeployedAccount{Account: acc, PrivateKey: privateKey, PublicKey: publicKey, ClassHash: classHash}
+	}
+	return accounts, nil
+}
@@ -2,18 +2,26 @@ package devnet
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math/big"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
 )
 
 type DevNet struct {
 	baseURL string
+
+	mu sync.Mutex
+	// snapshots are the paths of the files of the snapshots, by id
+	snapshots map[string]string
 }
 
 type TestAccount struct {
@@ -64,7 +72,18 @@ func (devnet *DevNet) api(uri string) string {
 // Returns:
 // - []TestAccount: a slice of TestAccount structs
 func (devnet *DevNet) Accounts() ([]TestAccount, error) {
-	req, err := http.NewRequest(http.MethodGet, devnet.api("/predeployed_accounts"), nil)
+	return devnet.accounts(context.Background())
+}
+
+// accounts retrieves the list of test accounts, as Accounts does.
+//
+// Parameters:
+// - ctx: the context of the request
+// Returns:
+// - []TestAccount: a slice of TestAccount structs
+// - error: an error if any
+func (devnet *DevNet) accounts(ctx context.Context) ([]TestAccount, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, devnet.api("/predeployed_accounts"), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -180,3 +199,147 @@ func (devnet *DevNet) FeeToken() (*FeeToken, error) {
 	err = json.NewDecoder(resp.Body).Decode(&token)
 	return &token, err
 }
+
+// Provider returns a provider connected to the JSON-RPC endpoint of the DevNet.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *rpc.Provider: the provider
+func (devnet *DevNet) Provider() *rpc.Provider {
+	return rpc.NewProvider(rpc.NewHTTPClient(devnet.api("/rpc")))
+}
+
+type TimeResponse struct {
+	BlockTimestamp uint64     `json:"block_timestamp"`
+	BlockHash      *felt.Felt `json:"block_hash"`
+}
+
+// SetTime sets the timestamp of the next blocks.
+//
+// Parameters:
+// - t: the time
+// - generateBlock: if true, a block is created with the new timestamp
+// Returns:
+// - *TimeResponse: the timestamp, and the hash of the created block if any
+// - error: an error if any
+func (devnet *DevNet) SetTime(t time.Time, generateBlock bool) (*TimeResponse, error) {
+	data := struct {
+		Time          int64 `json:"time"`
+		GenerateBlock bool  `json:"generate_block"`
+	}{
+		Time:          t.Unix(),
+		GenerateBlock: generateBlock,
+	}
+	var resp TimeResponse
+	if err := devnet.post("/set_time", data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+type IncreaseTimeResponse struct {
+	TimestampIncreasedBy uint64     `json:"timestamp_increased_by"`
+	BlockHash            *felt.Felt `json:"block_hash"`
+}
+
+// IncreaseTime moves the time of the DevNet forward and creates a block.
+//
+// Parameters:
+// - d: the duration, truncated to seconds
+// Returns:
+// - *IncreaseTimeResponse: the increase, and the hash of the created block
+// - error: an error if any
+func (devnet *DevNet) IncreaseTime(d time.Duration) (*IncreaseTimeResponse, error) {
+	data := struct {
+		Time int64 `json:"time"`
+	}{
+		Time: int64(d / time.Second),
+	}
+	var resp IncreaseTimeResponse
+	if err := devnet.post("/increase_time", data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateBlock creates a block holding the pending transactions.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the hash of the block
+// - error: an error if any
+func (devnet *DevNet) CreateBlock() (*felt.Felt, error) {
+	var resp struct {
+		BlockHash *felt.Felt `json:"block_hash"`
+	}
+	if err := devnet.post("/create_block", struct{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.BlockHash, nil
+}
+
+// DumpState dumps the state of the DevNet to a file. The path is resolved
+// by the DevNet, e.g. inside its container.
+//
+// Parameters:
+// - path: the path of the file
+// Returns:
+// - error: an error if any
+func (devnet *DevNet) DumpState(path string) error {
+	return devnet.post("/dump", struct {
+		Path string `json:"path"`
+	}{Path: path}, nil)
+}
+
+// LoadState loads the state of the DevNet from a file written by DumpState.
+//
+// Parameters:
+// - path: the path of the file
+// Returns:
+// - error: an error if any
+func (devnet *DevNet) LoadState(path string) error {
+	return devnet.post("/load", struct {
+		Path string `json:"path"`
+	}{Path: path}, nil)
+}
+
+// post sends a POST request to the DevNet API and decodes the response.
+//
+// Parameters:
+// - uri: the URI path
+// - data: the request body
+// - result: the value the response is decoded into, the response is ignored if nil
+// Returns:
+// - error: an error if the request fails or the status is not 200
+func (devnet *DevNet) post(uri string, data, result any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, devnet.api(uri), bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("devnet: %s: %s: %s", uri, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
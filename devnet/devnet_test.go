@@ -1,9 +1,16 @@
 package devnet
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/xiang-xx/starknet.go/utils"
 )
@@ -91,3 +98,207 @@ func TestDevnet_Mint(t *testing.T) {
 		t.Fatalf("ETH should be higher than the last mint, instead: %d", resp.NewBalance)
 	}
 }
+
+// TestDevnet_Control tests the time, block and state endpoints, and the
+// provider of the DevNet, against a fake DevNet server.
+//
+// Parameters:
+// - t: is the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDevnet_Control(t *testing.T) {
+	requests := map[string]map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding %s: %v", r.URL.Path, err)
+		}
+		requests[r.URL.Path] = body
+		switch r.URL.Path {
+		case "/set_time":
+			fmt.Fprint(w, `{"block_timestamp": 1700000000, "block_hash": "0xb1"}`)
+		case "/increase_time":
+			fmt.Fprint(w, `{"timestamp_increased_by": 3600, "block_hash": "0xb2"}`)
+		case "/create_block":
+			fmt.Fprint(w, `{"block_hash": "0xb3"}`)
+		case "/dump", "/load":
+		case "/rpc":
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %v, "result": 42}`, body["id"])
+		default:
+			http.Error(w, "unknown endpoint", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	d := NewDevNet(server.URL)
+
+	setTime, err := d.SetTime(time.Unix(1700000000, 0), true)
+	if err != nil || setTime.BlockTimestamp != 1700000000 || setTime.BlockHash.String() != "0xb1" {
+		t.Fatalf("unexpected SetTime result %+v, %v", setTime, err)
+	}
+	if requests["/set_time"]["time"] != float64(1700000000) || requests["/set_time"]["generate_block"] != true {
+		t.Fatalf("unexpected SetTime request %v", requests["/set_time"])
+	}
+	increase, err := d.IncreaseTime(time.Hour)
+	if err != nil || increase.TimestampIncreasedBy != 3600 || requests["/increase_time"]["time"] != float64(3600) {
+		t.Fatalf("unexpected IncreaseTime result %+v, %v", increase, err)
+	}
+	block, err := d.CreateBlock()
+	if err != nil || block.String() != "0xb3" {
+		t.Fatalf("unexpected CreateBlock result %v, %v", block, err)
+	}
+	if err := d.DumpState("/tmp/state.json"); err != nil || requests["/dump"]["path"] != "/tmp/state.json" {
+		t.Fatalf("unexpected DumpState result %v", err)
+	}
+	if err := d.LoadState("/tmp/state.json"); err != nil || requests["/load"]["path"] != "/tmp/state.json" {
+		t.Fatalf("unexpected LoadState result %v", err)
+	}
+	number, err := d.Provider().BlockNumber(context.Background())
+	if err != nil || number != 42 || requests["/rpc"]["method"] != "starknet_blockNumber" {
+		t.Fatalf("unexpected BlockNumber result %d, %v", number, err)
+	}
+	if err := d.post("/restart", struct{}{}, nil); err == nil {
+		t.Fatal("an unknown endpoint should fail")
+	}
+}
+
+// TestStart tests that starting a DevNet from a missing binary fails.
+//
+// Parameters:
+// - t: is the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestStart(t *testing.T) {
+	if _, err := Start(context.Background(), WithBinary("/nonexistent/starknet-devnet")); err == nil {
+		t.Fatal("starting a missing binary should fail")
+	}
+}
+
+// TestDevnet_PredeployedAccounts tests that the predeployed accounts are read
+// with their keys and the Cairo version of their class, against a fake DevNet
+// server with a Cairo 1 and a Cairo 0 account.
+//
+// Parameters:
+// - t: is the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDevnet_PredeployedAccounts(t *testing.T) {
+	classRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/predeployed_accounts" {
+			fmt.Fprint(w, `[
+				{"address": "0xa1", "private_key": "0x1", "public_key": "0x11"},
+				{"address": "0xa2", "private_key": "0x2", "public_key": "0x12"},
+				{"address": "0xa0", "private_key": "0x3", "public_key": "0x13"}
+			]`)
+			return
+		}
+		var body struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+			Params []any  `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding %s: %v", r.URL.Path, err)
+		}
+		result := `null`
+		switch body.Method {
+		case "starknet_chainId":
+			result = `"0x534e5f5345504f4c4941"`
+		case "starknet_getClassHashAt":
+			result = `"0xc1"`
+			if body.Params[1] == "0xa0" {
+				result = `"0xc0"`
+			}
+		case "starknet_getClass":
+			classRequests++
+			result = `{"sierra_program": [], "contract_class_version": "0.1.0", "entry_points_by_type": {"CONSTRUCTOR": [], "EXTERNAL": [], "L1_HANDLER": []}, "abi": "[]"}`
+			if body.Params[1] == "0xc0" {
+				result = `{"program": "", "entry_points_by_type": {"CONSTRUCTOR": [], "EXTERNAL": [], "L1_HANDLER": []}, "abi": []}`
+			}
+		}
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": %s}`, body.ID, result)
+	}))
+	defer server.Close()
+
+	accounts, err := NewDevNet(server.URL).PredeployedAccounts(context.Background())
+	if err != nil {
+		t.Fatalf("reading the predeployed accounts should succeed, instead: %v", err)
+	}
+	if len(accounts) != 3 || classRequests != 2 {
+		t.Fatalf("expected 3 accounts and 2 classes read, got %d and %d", len(accounts), classRequests)
+	}
+	for i, expected := range []struct {
+		address, publicKey, classHash string
+		cairoVersion                  int
+	}{
+		{"0xa1", "0x11", "0xc1", 2},
+		{"0xa2", "0x12", "0xc1", 2},
+		{"0xa0", "0x13", "0xc0", 0},
+	} {
+		acc := accounts[i]
+		if acc.AccountAddress.String() != expected.address || acc.PublicKey.String() != expected.publicKey ||
+			acc.ClassHash.String() != expected.classHash || acc.CairoVersion != expected.cairoVersion {
+			t.Fatalf("unexpected account %d: %s %s %s %d", i, acc.AccountAddress, acc.PublicKey, acc.ClassHash, acc.CairoVersion)
+		}
+		if acc.ChainId.String() != "0x534e5f5345504f4c4941" {
+			t.Fatalf("unexpected chain ID %s", acc.ChainId)
+		}
+	}
+	if accounts[2].PrivateKey.String() != "0x3" {
+		t.Fatalf("unexpected private key %s", accounts[2].PrivateKey)
+	}
+}
+
+// TestDevnet_Snapshot tests that a snapshot is dumped to a file loaded again
+// by Restore, and that unknown snapshots are not restored, against a fake
+// DevNet server.
+//
+// Parameters:
+// - t: is the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDevnet_Snapshot(t *testing.T) {
+	var dumped, loaded []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Path string `json:"path"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding %s: %v", r.URL.Path, err)
+		}
+		switch r.URL.Path {
+		case "/dump":
+			dumped = append(dumped, body.Path)
+		case "/load":
+			loaded = append(loaded, body.Path)
+		default:
+			http.Error(w, "unknown endpoint", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	d := NewDevNet(server.URL)
+
+	first, err := d.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot should succeed, instead: %v", err)
+	}
+	second, err := d.Snapshot()
+	if err != nil || second == first || len(dumped) != 2 || dumped[0] == dumped[1] {
+		t.Fatalf("snapshots should be dumped to distinct files, got %v, %v", dumped, err)
+	}
+	for _, id := range []string{first, first, second} {
+		if err := d.Restore(id); err != nil {
+			t.Fatalf("Restore should succeed, instead: %v", err)
+		}
+	}
+	if len(loaded) != 3 || loaded[0] != dumped[0] || loaded[1] != dumped[0] || loaded[2] != dumped[1] {
+		t.Fatalf("snapshots should be loaded from their files, got %v", loaded)
+	}
+	if err := NewDevNet(server.URL).Restore(first); !errors.Is(err, ErrUnknownSnapshot) || len(loaded) != 3 {
+		t.Fatalf("a snapshot of another DevNet should not be restored, got %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package devnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultBinary is the name of the starknet-devnet binary looked up in PATH.
+	DefaultBinary = "starknet-devnet"
+	// DefaultImage is the docker image used by WithDocker when none is given.
+	DefaultImage = "shardlabs/starknet-devnet-rs:latest"
+	// DefaultStartTimeout bounds the wait for a started DevNet to be alive.
+	DefaultStartTimeout = 30 * time.Second
+)
+
+var ErrNotAlive = errors.New("devnet: not alive before timeout")
+
+// Instance is a DevNet started by Start, stopped by Stop.
+type Instance struct {
+	*DevNet
+	cmd  *exec.Cmd
+	done chan error
+}
+
+type startConfig struct {
+	binary   string
+	image    string
+	port     int
+	seed     *uint64
+	accounts int
+	args     []string
+	timeout  time.Duration
+	output   *os.File
+}
+
+// StartOption configures Start.
+type StartOption func(*startConfig)
+
+// WithBinary runs the starknet-devnet binary at the given path.
+//
+// Parameters:
+// - path: the path of the binary
+// Returns:
+// - StartOption: the option
+func WithBinary(path string) StartOption {
+	return func(c *startConfig) {
+		c.binary = path
+	}
+}
+
+// WithDocker runs the DevNet in a docker container instead of a binary.
+//
+// Parameters:
+// - image: the image, DefaultImage if empty
+// Returns:
+// - StartOption: the option
+func WithDocker(image string) StartOption {
+	return func(c *startConfig) {
+		if image == "" {
+			image = DefaultImage
+		}
+		c.image = image
+	}
+}
+
+// WithPort sets the port of the DevNet, a free port by default.
+//
+// Parameters:
+// - port: the port
+// Returns:
+// - StartOption: the option
+func WithPort(port int) StartOption {
+	return func(c *startConfig) {
+		c.port = port
+	}
+}
+
+// WithSeed sets the seed of the predeployed accounts, making them
+// deterministic.
+//
+// Parameters:
+// - seed: the seed
+// Returns:
+// - StartOption: the option
+func WithSeed(seed uint64) StartOption {
+	return func(c *startConfig) {
+		c.seed = &seed
+	}
+}
+
+// WithAccounts sets the number of predeployed accounts.
+//
+// Parameters:
+// - n: the number of accounts
+// Returns:
+// - StartOption: the option
+func WithAccounts(n int) StartOption {
+	return func(c *startConfig) {
+		c.accounts = n
+	}
+}
+
+// WithArgs appends arguments to the command line of the DevNet.
+//
+// Parameters:
+// - args: the arguments
+// Returns:
+// - StartOption: the option
+func WithArgs(args ...string) StartOption {
+	return func(c *startConfig) {
+		c.args = append(c.args, args...)
+	}
+}
+
+// WithStartTimeout bounds the wait for the DevNet to be alive.
+//
+// Parameters:
+// - timeout: the timeout
+// Returns:
+// - StartOption: the option
+func WithStartTimeout(timeout time.Duration) StartOption {
+	return func(c *startConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithOutput writes the output of the DevNet to a file, e.g. os.Stderr.
+//
+// Parameters:
+// - f: the file
+// Returns:
+// - StartOption: the option
+func WithOutput(f *os.File) StartOption {
+	return func(c *startConfig) {
+		c.output = f
+	}
+}
+
+// Start spawns a DevNet, from the starknet-devnet binary or a docker image,
+// and waits for it to be alive.
+//
+// Parameters:
+// - ctx: the context, cancelling it kills the DevNet
+// - opts: the options
+// Returns:
+// - *Instance: the running DevNet
+// - error: an error if the DevNet fails to start, or ErrNotAlive
+func Start(ctx context.Context, opts ...StartOption) (*Instance, error) {
+	cfg := startConfig{binary: DefaultBinary, timeout: DefaultStartTimeout}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.port == 0 {
+		port, err := freePort()
+		if err != nil {
+			return nil, err
+		}
+		cfg.port = port
+	}
+
+	port := strconv.Itoa(cfg.port)
+	args := []string{"--port", port}
+	if cfg.seed != nil {
+		args = append(args, "--seed", strconv.FormatUint(*cfg.seed, 10))
+	}
+	if cfg.accounts > 0 {
+		args = append(args, "--accounts", strconv.Itoa(cfg.accounts))
+	}
+	args = append(args, cfg.args...)
+
+	var cmd *exec.Cmd
+	if cfg.image != "" {
+		docker := append([]string{"run", "--rm", "-p", port + ":" + port, cfg.image, "--host", "0.0.0.0"}, args...)
+		cmd = exec.CommandContext(ctx, "docker", docker...)
+	} else {
+		cmd = exec.CommandContext(ctx, cfg.binary, args...)
+	}
+	if cfg.output != nil {
+		cmd.Stdout, cmd.Stderr = cfg.output, cfg.output
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("devnet: %w", err)
+	}
+
+	instance := &Instance{
+		DevNet: NewDevNet("http://127.0.0.1:" + port),
+		cmd:    cmd,
+		done:   make(chan error, 1),
+	}
+	go func() {
+		instance.done <- cmd.Wait()
+	}()
+
+	deadline := time.NewTimer(cfg.timeout)
+	defer deadline.Stop()
+	tick := time.NewTicker(100 * time.Millisecond)
+	defer tick.Stop()
+	for !instance.IsAlive() {
+		select {
+		case err := <-instance.done:
+			instance.done <- err
+			return nil, fmt.Errorf("devnet: exited before being alive: %v", err)
+		case <-deadline.C:
+			_ = instance.Stop()
+			return nil, ErrNotAlive
+		case <-tick.C:
+		}
+	}
+	return instance, nil
+}
+
+// Stop interrupts the DevNet and waits for it to exit, killing it if it
+// does not exit within 5 seconds.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: an error if the DevNet can not be signalled
+func (instance *Instance) Stop() error {
+	select {
+	case err := <-instance.done:
+		instance.done <- err
+		return nil
+	default:
+	}
+	if err := instance.cmd.Process.Signal(os.Interrupt); err != nil {
+		return instance.cmd.Process.Kill()
+	}
+	select {
+	case err := <-instance.done:
+		instance.done <- err
+	case <-time.After(5 * time.Second):
+		return instance.cmd.Process.Kill()
+	}
+	return nil
+}
+
+// freePort returns a TCP port free on the loopback interface.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - int: the port
+// - error: an error if no port can be reserved
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
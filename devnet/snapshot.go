@@ -0,0 +1,62 @@
+package devnet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+var ErrUnknownSnapshot = errors.New("devnet: unknown snapshot")
+
+// snapshots numbers the snapshots of the process, so that DevNets sharing a
+// temporary directory never overwrite each other's files.
+var snapshots atomic.Uint64
+
+// Snapshot dumps the state of the DevNet to a file of the temporary directory
+// and returns its id, to reset the DevNet to this state with Restore, e.g.
+// between the subtests of a suite, instead of starting a new DevNet.
+//
+// The file is written by the DevNet, so the temporary directory must exist on
+// its side too, as /tmp does for a local binary or a docker image. The DevNet
+// must be started with dumping enabled, e.g. WithArgs("--dump-on", "request").
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the id of the snapshot
+// - error: an error if the state can not be dumped
+func (devnet *DevNet) Snapshot() (string, error) {
+	id := fmt.Sprintf("%d-%d", os.Getpid(), snapshots.Add(1))
+	path := filepath.Join(os.TempDir(), "starknet-devnet-"+id+".json")
+	if err := devnet.DumpState(path); err != nil {
+		return "", err
+	}
+	devnet.mu.Lock()
+	defer devnet.mu.Unlock()
+	if devnet.snapshots == nil {
+		devnet.snapshots = map[string]string{}
+	}
+	devnet.snapshots[id] = path
+	return id, nil
+}
+
+// Restore resets the state of the DevNet to a snapshot. A snapshot can be
+// restored any number of times.
+//
+// Parameters:
+// - id: the id returned by Snapshot
+// Returns:
+// - error: ErrUnknownSnapshot if the snapshot was not taken by this DevNet, or an error if the state can not be loaded
+func (devnet *DevNet) Restore(id string) error {
+	devnet.mu.Lock()
+	path, ok := devnet.snapshots[id]
+	devnet.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownSnapshot, id)
+	}
+	return devnet.LoadState(path)
+}
@@ -0,0 +1,484 @@
+// Package forkprovider reads the state of an upstream node, e.g. a mainnet
+// node, as an rpc.RpcProvider with local overrides of storage, nonces, class
+// hashes and token balances, to run "what-if" code against production state
+// without touching it.
+//
+// The overrides apply to the reads at the head of the fork: the reads by tag,
+// and by the number of the fork block when it is pinned with WithForkBlock.
+// Reads of other blocks are passed to the upstream node unchanged, as are the
+// methods not reading state.
+//
+// Calls, fee estimations and simulations are executed by the upstream node at
+// the fork block and do not see the overrides, the Starknet specification
+// having no state overrides: a contract reading an overridden slot sees its
+// upstream value. CallWithStateOverrides and
+// SimulateTransactionsWithStateOverrides send the overrides of the fork to
+// upstream nodes supporting them.
+//
+//	fork := forkprovider.New(mainnet, forkprovider.WithForkBlock(650000))
+//	fork.SetBalance(strk, acnt, big.NewInt(1e18))
+//	fork.SetNonce(acnt, new(felt.Felt).SetUint64(7))
+package forkprovider
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// Provider is an rpc.RpcProvider reading the state of an upstream node with
+// local overrides. It is safe for concurrent use by multiple goroutines.
+type Provider struct {
+	rpc.RpcProvider
+
+	// forkBlock is the number of the block the state is read at, nil to read
+	// the blocks requested
+	forkBlock *uint64
+
+	mu          sync.RWMutex
+	storage     map[felt.Felt]map[felt.Felt]*felt.Felt
+	nonces      map[felt.Felt]*felt.Felt
+	classHashes map[felt.Felt]*felt.Felt
+}
+
+var _ rpc.RpcProvider = &Provider{}
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithForkBlock pins the fork to a block: the reads and the executions by
+// tag are sent to the upstream node at this block, so that the state of the
+// fork does not move with the upstream chain.
+//
+// Parameters:
+// - blockNumber: the number of the fork block
+// Returns:
+// - Option: the option
+func WithForkBlock(blockNumber uint64) Option {
+	return func(p *Provider) {
+		p.forkBlock = &blockNumber
+	}
+}
+
+// New returns a fork of the state of an upstream node, without overrides.
+//
+// Parameters:
+// - upstream: the provider of the upstream node
+// - opts: the options
+// Returns:
+// - *Provider: the fork
+func New(upstream rpc.RpcProvider, opts ...Option) *Provider {
+	p := &Provider{
+		RpcProvider: upstream,
+		storage:     map[felt.Felt]map[felt.Felt]*felt.Felt{},
+		nonces:      map[felt.Felt]*felt.Felt{},
+		classHashes: map[felt.Felt]*felt.Felt{},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// SetStorage overrides a storage slot of a contract.
+//
+// Parameters:
+// - contractAddress: the address of the contract
+// - key: the storage address of the slot, e.g. from utils.StorageVarAddress
+// - value: the value
+// Returns:
+//
+//	none
+func (p *Provider) SetStorage(contractAddress, key, value *felt.Felt) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	slots, ok := p.storage[*contractAddress]
+	if !ok {
+		slots = map[felt.Felt]*felt.Felt{}
+		p.storage[*contractAddress] = slots
+	}
+	slots[*key] = value
+}
+
+// SetNonce overrides the nonce of a contract.
+//
+// Parameters:
+// - contractAddress: the address of the contract
+// - nonce: the nonce
+// Returns:
+//
+//	none
+func (p *Provider) SetNonce(contractAddress, nonce *felt.Felt) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nonces[*contractAddress] = nonce
+}
+
+// SetClassHash overrides the class of a contract, e.g. to read an upgraded
+// class at its address. The class itself is read from the upstream node, so
+// it must be declared there.
+//
+// Parameters:
+// - contractAddress: the address of the contract
+// - classHash: the hash of the class
+// Returns:
+//
+//	none
+func (p *Provider) SetClassHash(contractAddress, classHash *felt.Felt) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.classHashes[*contractAddress] = classHash
+}
+
+// SetBalance overrides the balance of an owner in an ERC20 token storing its
+// balances in ERC20_balances, as rpc.BalanceOverride does.
+//
+// Parameters:
+// - token: the address of the token
+// - owner: the address of the owner
+// - amount: the balance, in the base unit of the token
+// Returns:
+// - error: an error if the amount is negative or does not fit in a u256
+func (p *Provider) SetBalance(token, owner *felt.Felt, amount *big.Int) error {
+	override, err := rpc.BalanceOverride(token, owner, amount)
+	if err != nil {
+		return fmt.Errorf("forkprovider: %w", err)
+	}
+	for _, entry := range override.Storage {
+		p.SetStorage(token, entry.Key, entry.Value)
+	}
+	return nil
+}
+
+// Overrides returns the overrides of the fork, by contract, to execute
+// transactions on the state of the fork with nodes supporting them.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []rpc.StateOverride: the overrides
+func (p *Provider) Overrides() []rpc.StateOverride {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	byContract := map[felt.Felt]*rpc.StateOverride{}
+	override := func(contractAddress felt.Felt) *rpc.StateOverride {
+		o, ok := byContract[contractAddress]
+		if !ok {
+			address := contractAddress
+			o = &rpc.StateOverride{ContractAddress: &address}
+			byContract[contractAddress] = o
+		}
+		return o
+	}
+	for contractAddress, slots := range p.storage {
+		o := override(contractAddress)
+		for key, value := range slots {
+			key := key
+			o.Storage = append(o.Storage, rpc.StorageEntry{Key: &key, Value: value})
+		}
+		sort.Slice(o.Storage, func(i, j int) bool { return o.Storage[i].Key.Cmp(o.Storage[j].Key) < 0 })
+	}
+	for contractAddress, nonce := range p.nonces {
+		override(contractAddress).Nonce = nonce
+	}
+	for contractAddress, classHash := range p.classHashes {
+		override(contractAddress).ClassHash = classHash
+	}
+	overrides := make([]rpc.StateOverride, 0, len(byContract))
+	for _, o := range byContract {
+		overrides = append(overrides, *o)
+	}
+	sort.Slice(overrides, func(i, j int) bool { return overrides[i].ContractAddress.Cmp(overrides[j].ContractAddress) < 0 })
+	return overrides
+}
+
+// Reset removes all the overrides.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (p *Provider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.storage = map[felt.Felt]map[felt.Felt]*felt.Felt{}
+	p.nonces = map[felt.Felt]*felt.Felt{}
+	p.classHashes = map[felt.Felt]*felt.Felt{}
+}
+
+// StorageAt reads a storage variable of a contract, the overridden value at
+// the head of the fork.
+//
+// Parameters:
+// - ctx: the context
+// - contractAddress: the address of the contract
+// - key: the name of the storage variable
+// - blockID: the block
+// Returns:
+// - string: the value of the storage
+// - error: an error of the upstream node
+func (p *Provider) StorageAt(ctx context.Context, contractAddress *felt.Felt, key string, blockID rpc.BlockID) (string, error) {
+	blockID, head := p.resolve(blockID)
+	if head {
+		if value, ok := p.storageOverride(contractAddress, utils.GetSelectorFromNameFelt(key)); ok {
+			return value.String(), nil
+		}
+	}
+	return p.RpcProvider.StorageAt(ctx, contractAddress, key, blockID)
+}
+
+// StorageAtKeys reads storage slots of a contract, the overridden values at
+// the head of the fork, the others from the upstream node.
+//
+// Parameters:
+// - ctx: the context
+// - contractAddress: the address of the contract
+// - keys: the storage addresses of the slots
+// - blockID: the block
+// Returns:
+// - map[felt.Felt]*felt.Felt: the values, by storage address
+// - error: an error of the upstream node
+func (p *Provider) StorageAtKeys(ctx context.Context, contractAddress *felt.Felt, keys []*felt.Felt, blockID rpc.BlockID) (map[felt.Felt]*felt.Felt, error) {
+	blockID, head := p.resolve(blockID)
+	values := make(map[felt.Felt]*felt.Felt, len(keys))
+	missing := keys
+	if head {
+		missing = nil
+		for _, key := range keys {
+			if value, ok := p.storageOverride(contractAddress, key); ok {
+				values[*key] = value
+			} else {
+				missing = append(missing, key)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+	upstream, err := p.RpcProvider.StorageAtKeys(ctx, contractAddress, missing, blockID)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range upstream {
+		values[key] = value
+	}
+	return values, nil
+}
+
+// Nonce reads the nonce of a contract, the overridden nonce at the head of
+// the fork.
+//
+// Parameters:
+// - ctx: the context
+// - blockID: the block
+// - contractAddress: the address of the contract
+// Returns:
+// - *felt.Felt: the nonce
+// - error: an error of the upstream node
+func (p *Provider) Nonce(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	blockID, head := p.resolve(blockID)
+	if head {
+		p.mu.RLock()
+		nonce, ok := p.nonces[*contractAddress]
+		p.mu.RUnlock()
+		if ok {
+			return nonce, nil
+		}
+	}
+	return p.RpcProvider.Nonce(ctx, blockID, contractAddress)
+}
+
+// ClassHashAt reads the hash of the class of a contract, the overridden hash
+// at the head of the fork.
+//
+// Parameters:
+// - ctx: the context
+// - blockID: the block
+// - contractAddress: the address of the contract
+// Returns:
+// - *felt.Felt: the hash of the class
+// - error: an error of the upstream node
+func (p *Provider) ClassHashAt(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
+	blockID, head := p.resolve(blockID)
+	if head {
+		if classHash, ok := p.classHashOverride(contractAddress); ok {
+			return classHash, nil
+		}
+	}
+	return p.RpcProvider.ClassHashAt(ctx, blockID, contractAddress)
+}
+
+// ClassAt reads the class of a contract, the class of the overridden hash at
+// the head of the fork.
+//
+// Parameters:
+// - ctx: the context
+// - blockID: the block
+// - contractAddress: the address of the contract
+// Returns:
+// - rpc.ClassOutput: the class
+// - error: an error of the upstream node
+func (p *Provider) ClassAt(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (rpc.ClassOutput, error) {
+	blockID, head := p.resolve(blockID)
+	if head {
+		if classHash, ok := p.classHashOverride(contractAddress); ok {
+			return p.RpcProvider.Class(ctx, blockID, classHash)
+		}
+	}
+	return p.RpcProvider.ClassAt(ctx, blockID, contractAddress)
+}
+
+// Class reads a class from the upstream node, at the fork block if pinned.
+//
+// Parameters:
+// - ctx: the context
+// - blockID: the block
+// - classHash: the hash of the class
+// Returns:
+// - rpc.ClassOutput: the class
+// - error: an error of the upstream node
+func (p *Provider) Class(ctx context.Context, blockID rpc.BlockID, classHash *felt.Felt) (rpc.ClassOutput, error) {
+	blockID, _ = p.resolve(blockID)
+	return p.RpcProvider.Class(ctx, blockID, classHash)
+}
+
+// Call executes a call on the upstream node, at the fork block if pinned,
+// without the overrides.
+//
+// Parameters:
+// - ctx: the context
+// - call: the call
+// - blockID: the block
+// Returns:
+// - []*felt.Felt: the result of the call
+// - error: an error of the upstream node
+func (p *Provider) Call(ctx context.Context, call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error) {
+	blockID, _ = p.resolve(blockID)
+	return p.RpcProvider.Call(ctx, call, blockID)
+}
+
+// EstimateFee estimates transactions on the upstream node, at the fork block
+// if pinned, without the overrides.
+//
+// Parameters:
+// - ctx: the context
+// - requests: the transactions
+// - simulationFlags: the simulation flags
+// - blockID: the block
+// Returns:
+// - []rpc.FeeEstimate: the estimates
+// - error: an error of the upstream node
+func (p *Provider) EstimateFee(ctx context.Context, requests []rpc.BroadcastTxn, simulationFlags []rpc.SimulationFlag, blockID rpc.BlockID) ([]rpc.FeeEstimate, error) {
+	blockID, _ = p.resolve(blockID)
+	return p.RpcProvider.EstimateFee(ctx, requests, simulationFlags, blockID)
+}
+
+// SimulateTransactions simulates transactions on the upstream node, at the
+// fork block if pinned, without the overrides.
+//
+// Parameters:
+// - ctx: the context
+// - blockID: the block
+// - txns: the transactions
+// - simulationFlags: the simulation flags
+// Returns:
+// - []rpc.SimulatedTransaction: the simulations
+// - error: an error of the upstream node
+func (p *Provider) SimulateTransactions(ctx context.Context, blockID rpc.BlockID, txns []rpc.Transaction, simulationFlags []rpc.SimulationFlag) ([]rpc.SimulatedTransaction, error) {
+	blockID, _ = p.resolve(blockID)
+	return p.RpcProvider.SimulateTransactions(ctx, blockID, txns, simulationFlags)
+}
+
+// CallWithStateOverrides executes a call on the upstream node, at the fork
+// block if pinned, with the overrides of the fork followed by the overrides
+// given, on nodes supporting them.
+//
+// Parameters:
+// - ctx: the context
+// - call: the call
+// - blockID: the block
+// - overrides: the overrides added to those of the fork
+// Returns:
+// - []*felt.Felt: the result of the call
+// - error: rpc.ErrStateOverridesNotSupported if the upstream node rejects the overrides, or an error of the upstream node
+func (p *Provider) CallWithStateOverrides(ctx context.Context, call rpc.FunctionCall, blockID rpc.BlockID, overrides []rpc.StateOverride) ([]*felt.Felt, error) {
+	blockID, _ = p.resolve(blockID)
+	return p.RpcProvider.CallWithStateOverrides(ctx, call, blockID, append(p.Overrides(), overrides...))
+}
+
+// SimulateTransactionsWithStateOverrides simulates transactions on the
+// upstream node, at the fork block if pinned, with the overrides of the fork
+// followed by the overrides given, on nodes supporting them.
+//
+// Parameters:
+// - ctx: the context
+// - blockID: the block
+// - txns: the transactions
+// - simulationFlags: the simulation flags
+// - overrides: the overrides added to those of the fork
+// Returns:
+// - []rpc.SimulatedTransaction: the simulations
+// - error: rpc.ErrStateOverridesNotSupported if the upstream node rejects the overrides, or an error of the upstream node
+func (p *Provider) SimulateTransactionsWithStateOverrides(ctx context.Context, blockID rpc.BlockID, txns []rpc.Transaction, simulationFlags []rpc.SimulationFlag, overrides []rpc.StateOverride) ([]rpc.SimulatedTransaction, error) {
+	blockID, _ = p.resolve(blockID)
+	return p.RpcProvider.SimulateTransactionsWithStateOverrides(ctx, blockID, txns, simulationFlags, append(p.Overrides(), overrides...))
+}
+
+// resolve returns the block a read is sent upstream at, and whether it reads
+// the head of the fork, where the overrides apply.
+//
+// Parameters:
+// - blockID: the block requested
+// Returns:
+// - rpc.BlockID: the fork block for a tag if pinned, the block requested otherwise
+// - bool: true for a tag or the fork block
+func (p *Provider) resolve(blockID rpc.BlockID) (rpc.BlockID, bool) {
+	if blockID.Tag != "" {
+		if p.forkBlock != nil {
+			return rpc.WithBlockNumber(*p.forkBlock), true
+		}
+		return blockID, true
+	}
+	head := p.forkBlock != nil && blockID.Number != nil && *blockID.Number == *p.forkBlock
+	return blockID, head
+}
+
+// storageOverride returns the overridden value of a storage slot.
+//
+// Parameters:
+// - contractAddress: the address of the contract
+// - key: the storage address of the slot
+// Returns:
+// - *felt.Felt: the value
+// - bool: true if the slot is overridden
+func (p *Provider) storageOverride(contractAddress, key *felt.Felt) (*felt.Felt, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	value, ok := p.storage[*contractAddress][*key]
+	return value, ok
+}
+
+// classHashOverride returns the overridden class hash of a contract.
+//
+// Parameters:
+// - contractAddress: the address of the contract
+// Returns:
+// - *felt.Felt: the hash of the class
+// - bool: true if the class is overridden
+func (p *Provider) classHashOverride(contractAddress *felt.Felt) (*felt.Felt, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	classHash, ok := p.classHashes[*contractAddress]
+	return classHash, ok
+}
@@ -0,0 +1,104 @@
+package forkprovider
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestProvider tests that the overrides are read at the head of the fork,
+// that the other reads are sent upstream at the fork block, that historical
+// reads are not overridden, and that the overrides are sent with the
+// executions with state overrides.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestProvider(t *testing.T) {
+	upstream := mocks.NewMockRpcProvider(gomock.NewController(t))
+	fork := New(upstream, WithForkBlock(100))
+	ctx := context.Background()
+	contract, token, owner := new(felt.Felt).SetUint64(0xc0), new(felt.Felt).SetUint64(0x70), new(felt.Felt).SetUint64(0xa1)
+	latest, forkBlock, older := rpc.WithBlockTag(rpc.BlockTagLatest), rpc.WithBlockNumber(100), rpc.WithBlockNumber(99)
+
+	fork.SetNonce(contract, new(felt.Felt).SetUint64(7))
+	nonce, err := fork.Nonce(ctx, latest, contract)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), nonce.Uint64())
+	upstream.EXPECT().Nonce(ctx, older, contract).Return(new(felt.Felt).SetUint64(3), nil)
+	nonce, err = fork.Nonce(ctx, older, contract)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), nonce.Uint64())
+	upstream.EXPECT().Nonce(ctx, forkBlock, token).Return(new(felt.Felt).SetUint64(1), nil)
+	nonce, err = fork.Nonce(ctx, latest, token)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), nonce.Uint64())
+
+	admin := utils.GetSelectorFromNameFelt("admin")
+	fork.SetStorage(contract, admin, new(felt.Felt).SetUint64(0xad))
+	value, err := fork.StorageAt(ctx, contract, "admin", forkBlock)
+	require.NoError(t, err)
+	require.Equal(t, "0xad", value)
+	upstream.EXPECT().StorageAt(ctx, contract, "owner", forkBlock).Return("0xee", nil)
+	value, err = fork.StorageAt(ctx, contract, "owner", latest)
+	require.NoError(t, err)
+	require.Equal(t, "0xee", value)
+
+	amount := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(2), 128), big.NewInt(5))
+	require.NoError(t, fork.SetBalance(token, owner, amount))
+	require.Error(t, fork.SetBalance(token, owner, big.NewInt(-1)))
+	balance := utils.StorageVarAddress("ERC20_balances", owner)
+	other := new(felt.Felt).SetUint64(0x5107)
+	keys := []*felt.Felt{balance, utils.StorageAddressOffset(balance, 1), other}
+	upstream.EXPECT().StorageAtKeys(ctx, token, []*felt.Felt{other}, forkBlock).Return(map[felt.Felt]*felt.Felt{*other: new(felt.Felt).SetUint64(9)}, nil)
+	values, err := fork.StorageAtKeys(ctx, token, keys, latest)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), values[*keys[0]].Uint64())
+	require.Equal(t, uint64(2), values[*keys[1]].Uint64())
+	require.Equal(t, uint64(9), values[*other].Uint64())
+
+	upgraded := new(felt.Felt).SetUint64(0xc1a55)
+	fork.SetClassHash(contract, upgraded)
+	classHash, err := fork.ClassHashAt(ctx, latest, contract)
+	require.NoError(t, err)
+	require.Equal(t, upgraded, classHash)
+	class := &rpc.ContractClass{}
+	upstream.EXPECT().Class(ctx, forkBlock, upgraded).Return(class, nil)
+	output, err := fork.ClassAt(ctx, latest, contract)
+	require.NoError(t, err)
+	require.Equal(t, class, output)
+
+	call := rpc.FunctionCall{ContractAddress: contract, EntryPointSelector: admin}
+	upstream.EXPECT().Call(ctx, call, forkBlock).Return([]*felt.Felt{new(felt.Felt).SetUint64(0xee)}, nil)
+	result, err := fork.Call(ctx, call, latest)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0xee), result[0].Uint64())
+
+	extra := rpc.StateOverride{ContractAddress: owner, Nonce: new(felt.Felt).SetUint64(1)}
+	overrides := fork.Overrides()
+	require.Len(t, overrides, 2)
+	require.Equal(t, token, overrides[0].ContractAddress)
+	require.Len(t, overrides[0].Storage, 2)
+	require.Equal(t, contract, overrides[1].ContractAddress)
+	require.Equal(t, uint64(7), overrides[1].Nonce.Uint64())
+	require.Equal(t, upgraded, overrides[1].ClassHash)
+	upstream.EXPECT().CallWithStateOverrides(ctx, call, forkBlock, append(overrides, extra)).Return([]*felt.Felt{new(felt.Felt).SetUint64(0xad)}, nil)
+	result, err = fork.CallWithStateOverrides(ctx, call, latest, []rpc.StateOverride{extra})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0xad), result[0].Uint64())
+
+	fork.Reset()
+	upstream.EXPECT().Nonce(ctx, forkBlock, contract).Return(new(felt.Felt).SetUint64(3), nil)
+	nonce, err = fork.Nonce(ctx, latest, contract)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), nonce.Uint64())
+}
@@ -0,0 +1,258 @@
+// Package forks tracks the Starknet versions activated on each chain by block
+// height, and the features each version brings (new transaction versions),
+// so that code verifying historical transactions applies the rules in force
+// at the height of each block.
+//
+// A Table maps the block heights of a chain to the Starknet versions active
+// at those heights. Tables are filled with Register, or by Discover from the
+// block headers of a node. Default knows the activations of SN_MAIN and
+// SN_SEPOLIA.
+package forks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/xiang-xx/starknet.go/contracts"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+var (
+	ErrUnknownChain = errors.New("forks: no activations registered for the chain")
+	ErrNotActive    = errors.New("forks: feature not active at the block")
+)
+
+// Feature is a protocol feature activated by a Starknet version.
+type Feature string
+
+const (
+	// FeatureDeclareV2 enables V2 declare transactions of Sierra classes
+	FeatureDeclareV2 Feature = "declare_v2"
+	// FeatureV3Transactions enables the V3 transactions of SNIP-8, hashed
+	// with Poseidon
+	FeatureV3Transactions Feature = "v3_transactions"
+)
+
+// FeatureVersions are the Starknet versions activating each feature.
+var FeatureVersions = map[Feature]contracts.Version{
+	FeatureDeclareV2:      {Major: 0, Minor: 11, Patch: 0},
+	FeatureV3Transactions: {Major: 0, Minor: 13, Patch: 0},
+}
+
+// Activation is the first block of a chain running a Starknet version.
+type Activation struct {
+	Version contracts.Version
+	Block   uint64
+}
+
+// Table maps the block heights of chains to the Starknet versions active at
+// those heights. It is safe for concurrent use.
+type Table struct {
+	mu     sync.RWMutex
+	chains map[string][]Activation
+}
+
+// Default is the table used when none is given, holding the activations of
+// the versions of FeatureVersions on SN_MAIN and SN_SEPOLIA. Other chains are
+// registered or discovered.
+var Default = defaultTable()
+
+// NewTable creates an empty table.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *Table: the table
+func NewTable() *Table {
+	return &Table{chains: map[string][]Activation{}}
+}
+
+// defaultTable creates the table of the public chains.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *Table: the table
+func defaultTable() *Table {
+	table := NewTable()
+	table.Register("SN_MAIN",
+		Activation{Version: contracts.Version{Minor: 11}, Block: 28613},
+		Activation{Version: contracts.Version{Minor: 13}, Block: 501514},
+	)
+	// SN_SEPOLIA started after Starknet 0.13.0 was released to the testnets
+	table.Register("SN_SEPOLIA",
+		Activation{Version: contracts.Version{Minor: 13}, Block: 0},
+	)
+	return table
+}
+
+// Register adds activations to a chain, replacing the activation of the same
+// block if any.
+//
+// Parameters:
+// - chainID: the chain ID, e.g. "SN_MAIN"
+// - activations: the activations, in any order
+// Returns:
+//
+//	none
+func (t *Table) Register(chainID string, activations ...Activation) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	byBlock := map[uint64]Activation{}
+	for _, a := range t.chains[chainID] {
+		byBlock[a.Block] = a
+	}
+	for _, a := range activations {
+		byBlock[a.Block] = a
+	}
+	merged := make([]Activation, 0, len(byBlock))
+	for _, a := range byBlock {
+		merged = append(merged, a)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Block < merged[j].Block })
+	t.chains[chainID] = merged
+}
+
+// Activations returns the activations of a chain, by ascending block.
+//
+// Parameters:
+// - chainID: the chain ID
+// Returns:
+// - []Activation: the activations, nil if the chain is unknown
+func (t *Table) Activations(chainID string) []Activation {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return append([]Activation(nil), t.chains[chainID]...)
+}
+
+// Version returns the Starknet version active at a block.
+//
+// Parameters:
+// - chainID: the chain ID
+// - block: the block number
+// Returns:
+// - contracts.Version: the version, zero before the first activation
+// - error: ErrUnknownChain if the chain has no activations
+func (t *Table) Version(chainID string, block uint64) (contracts.Version, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	activations, ok := t.chains[chainID]
+	if !ok {
+		return contracts.Version{}, fmt.Errorf("%w: %s", ErrUnknownChain, chainID)
+	}
+	i := sort.Search(len(activations), func(i int) bool { return activations[i].Block > block })
+	if i == 0 {
+		return contracts.Version{}, nil
+	}
+	return activations[i-1].Version, nil
+}
+
+// Active reports whether a feature is active at a block.
+//
+// Parameters:
+// - chainID: the chain ID
+// - feature: the feature
+// - block: the block number
+// Returns:
+// - bool: true if the version active at the block activates the feature
+// - error: ErrUnknownChain if the chain has no activations, or an error if the feature is unknown
+func (t *Table) Active(chainID string, feature Feature, block uint64) (bool, error) {
+	required, ok := FeatureVersions[feature]
+	if !ok {
+		return false, fmt.Errorf("forks: unknown feature %q", feature)
+	}
+	version, err := t.Version(chainID, block)
+	if err != nil {
+		return false, err
+	}
+	return version.Compare(required) >= 0, nil
+}
+
+// Require returns ErrNotActive if a feature is not active at a block.
+//
+// Parameters:
+// - chainID: the chain ID
+// - feature: the feature
+// - block: the block number
+// Returns:
+// - error: an error wrapping ErrNotActive, ErrUnknownChain if the chain has no activations
+func (t *Table) Require(chainID string, feature Feature, block uint64) error {
+	active, err := t.Active(chainID, feature, block)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return fmt.Errorf("%w: %s requires Starknet %s, block %d of %s runs an older version", ErrNotActive, feature, FeatureVersions[feature], block, chainID)
+	}
+	return nil
+}
+
+// Discover registers the activations of a chain up to its latest block,
+// reading the Starknet versions of the block headers of a node. The first
+// block of each version is found by binary search, reading a few headers per
+// version.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider of the chain
+// - chainID: the chain ID to register the activations for
+// Returns:
+// - []Activation: the activations found
+// - error: an error if a block header can not be read or has an invalid version
+func (t *Table) Discover(ctx context.Context, provider rpc.RpcProvider, chainID string) ([]Activation, error) {
+	latest, err := provider.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version := func(block uint64) (contracts.Version, error) {
+		result, err := provider.BlockWithTxHashes(ctx, rpc.WithBlockNumber(block))
+		if err != nil {
+			return contracts.Version{}, err
+		}
+		starknetVersion := result.Header().StarknetVersion
+		if starknetVersion == "" {
+			return contracts.Version{}, nil
+		}
+		return contracts.ParseVersion(starknetVersion)
+	}
+
+	current, err := version(0)
+	if err != nil {
+		return nil, err
+	}
+	last, err := version(latest)
+	if err != nil {
+		return nil, err
+	}
+	activations := []Activation{{Version: current, Block: 0}}
+	for from := uint64(0); current != last; {
+		// first block after from running another version
+		low, high := from+1, latest
+		for low < high {
+			mid := low + (high-low)/2
+			v, err := version(mid)
+			if err != nil {
+				return nil, err
+			}
+			if v == current {
+				low = mid + 1
+			} else {
+				high = mid
+			}
+		}
+		if current, err = version(low); err != nil {
+			return nil, err
+		}
+		activations = append(activations, Activation{Version: current, Block: low})
+		from = low
+	}
+	t.Register(chainID, activations...)
+	return activations, nil
+}
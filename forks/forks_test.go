@@ -0,0 +1,129 @@
+package forks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/contracts"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// TestTable_Active tests the versions and features active around the
+// activation heights of a chain.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestTable_Active(t *testing.T) {
+	table := NewTable()
+	table.Register("SN_TEST",
+		Activation{Version: contracts.Version{Minor: 13, Patch: 2}, Block: 300},
+		Activation{Version: contracts.Version{Minor: 12, Patch: 3}, Block: 0},
+		Activation{Version: contracts.Version{Minor: 13}, Block: 100},
+	)
+	require.Equal(t, []Activation{
+		{Version: contracts.Version{Minor: 12, Patch: 3}, Block: 0},
+		{Version: contracts.Version{Minor: 13}, Block: 100},
+		{Version: contracts.Version{Minor: 13, Patch: 2}, Block: 300},
+	}, table.Activations("SN_TEST"))
+
+	type testSetType struct {
+		Block           uint64
+		ExpectedVersion string
+		ExpectedV3      bool
+	}
+	testSet := []testSetType{
+		{Block: 0, ExpectedVersion: "0.12.3"},
+		{Block: 99, ExpectedVersion: "0.12.3"},
+		{Block: 100, ExpectedVersion: "0.13.0", ExpectedV3: true},
+		{Block: 299, ExpectedVersion: "0.13.0", ExpectedV3: true},
+		{Block: 300, ExpectedVersion: "0.13.2", ExpectedV3: true},
+	}
+	for _, test := range testSet {
+		version, err := table.Version("SN_TEST", test.Block)
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedVersion, version.String())
+		v3, err := table.Active("SN_TEST", FeatureV3Transactions, test.Block)
+		require.NoError(t, err)
+		require.Equal(t, test.ExpectedV3, v3)
+	}
+
+	err := table.Require("SN_TEST", FeatureV3Transactions, 42)
+	require.True(t, errors.Is(err, ErrNotActive))
+	require.Contains(t, err.Error(), "v3_transactions requires Starknet 0.13.0, block 42 of SN_TEST")
+	_, err = table.Version("SN_OTHER", 42)
+	require.True(t, errors.Is(err, ErrUnknownChain))
+	_, err = table.Active("SN_TEST", Feature("unknown"), 42)
+	require.Error(t, err)
+}
+
+// TestDefault tests the features active on the public chains.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDefault(t *testing.T) {
+	for _, chainID := range []string{"SN_MAIN", "SN_SEPOLIA"} {
+		tip := Default.Activations(chainID)
+		for feature := range FeatureVersions {
+			active, err := Default.Active(chainID, feature, tip[len(tip)-1].Block)
+			require.NoError(t, err)
+			require.True(t, active, "%s on %s", feature, chainID)
+		}
+	}
+	declareV2, err := Default.Active("SN_MAIN", FeatureDeclareV2, 28612)
+	require.NoError(t, err)
+	require.False(t, declareV2)
+	v3, err := Default.Active("SN_MAIN", FeatureV3Transactions, 501513)
+	require.NoError(t, err)
+	require.False(t, v3)
+}
+
+// TestTable_Discover tests the activations found from the Starknet versions
+// of the block headers.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestTable_Discover(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	provider.EXPECT().BlockNumber(gomock.Any()).Return(uint64(1000), nil)
+	reads := 0
+	provider.EXPECT().BlockWithTxHashes(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, blockID rpc.BlockID) (*rpc.BlockTxHashesResult, error) {
+			reads++
+			version := ""
+			switch n := *blockID.Number; {
+			case n >= 731:
+				version = "0.13.1.1"
+			case n >= 250:
+				version = "0.13.0"
+			case n >= 17:
+				version = "0.12.3"
+			}
+			return &rpc.BlockTxHashesResult{Block: &rpc.BlockTxHashes{BlockHeader: rpc.BlockHeader{BlockNumber: *blockID.Number, StarknetVersion: version}}}, nil
+		}).AnyTimes()
+
+	table := NewTable()
+	activations, err := table.Discover(context.Background(), provider, "SN_TEST")
+	require.NoError(t, err)
+	require.Equal(t, []Activation{
+		{Block: 0},
+		{Version: contracts.Version{Minor: 12, Patch: 3}, Block: 17},
+		{Version: contracts.Version{Minor: 13}, Block: 250},
+		{Version: contracts.Version{Minor: 13, Patch: 1}, Block: 731},
+	}, activations)
+	require.Equal(t, activations, table.Activations("SN_TEST"))
+	require.True(t, reads < 50, "%d headers read", reads)
+}
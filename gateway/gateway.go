@@ -0,0 +1,335 @@
+// Package gateway is a client of the feeder gateway of the Starknet
+// sequencer, for the data some JSON-RPC providers do not expose: aborted
+// blocks, block signatures, the public key of the sequencer and the
+// addresses of the core contracts. Blocks, state updates and classes are
+// returned with the types of the rpc package.
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/contracts"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// Base URLs of the public feeder gateways.
+const (
+	MainnetURL = "https://alpha-mainnet.starknet.io"
+	SepoliaURL = "https://alpha-sepolia.starknet.io"
+)
+
+// errorCodes map the codes of the gateway errors to the errors of the rpc
+// package.
+var errorCodes = map[string]error{
+	"StarknetErrorCode.BLOCK_NOT_FOUND":        rpc.ErrBlockNotFound,
+	"StarknetErrorCode.UNDECLARED_CLASS":       rpc.ErrClassHashNotFound,
+	"StarknetErrorCode.UNINITIALIZED_CONTRACT": rpc.ErrContractNotFound,
+	"StarknetErrorCode.TRANSACTION_NOT_FOUND":  rpc.ErrHashNotFound,
+}
+
+// Error is an error returned by the gateway.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error returns the code and the message of the error.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the error message
+func (e *Error) Error() string {
+	return fmt.Sprintf("gateway: %s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the rpc error matching the code of the error, e.g.
+// rpc.ErrBlockNotFound, so that errors.Is works as with a provider.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: the rpc error, nil if none matches
+func (e *Error) Unwrap() error {
+	return errorCodes[e.Code]
+}
+
+// Client is a client of the feeder gateway.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	headers    http.Header
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client, http.DefaultClient by default.
+//
+// Parameters:
+// - httpClient: the HTTP client
+// Returns:
+// - Option: the option
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHeader adds a header to every request, e.g. the API key of a gateway
+// with rate limits.
+//
+// Parameters:
+// - key: the name of the header
+// - value: the value of the header
+// Returns:
+// - Option: the option
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// NewClient creates a client of a feeder gateway.
+//
+// Parameters:
+// - baseURL: the base URL of the gateway, e.g. MainnetURL
+// - opts: the options
+// Returns:
+// - *Client: the client
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		headers:    http.Header{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Block returns a block with its transactions and receipts. Aborted blocks
+// are returned with the status BlockStatusAborted.
+//
+// Parameters:
+// - ctx: the context
+// - blockID: the block, by number, hash, or the "latest" or "pending" tag
+// Returns:
+// - *Block: the block
+// - error: an error wrapping rpc.ErrBlockNotFound if the block does not exist
+func (c *Client) Block(ctx context.Context, blockID rpc.BlockID) (*Block, error) {
+	query, err := blockQuery(blockID)
+	if err != nil {
+		return nil, err
+	}
+	var block Block
+	if err := c.get(ctx, "get_block", query, &block); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// StateUpdate returns the state update of a block, converted to the rpc
+// state update.
+//
+// Parameters:
+// - ctx: the context
+// - blockID: the block, by number, hash, or the "latest" or "pending" tag
+// Returns:
+// - *rpc.StateUpdateOutput: the state update
+// - error: an error wrapping rpc.ErrBlockNotFound if the block does not exist
+func (c *Client) StateUpdate(ctx context.Context, blockID rpc.BlockID) (*rpc.StateUpdateOutput, error) {
+	query, err := blockQuery(blockID)
+	if err != nil {
+		return nil, err
+	}
+	var update stateUpdate
+	if err := c.get(ctx, "get_state_update", query, &update); err != nil {
+		return nil, err
+	}
+	return update.rpc()
+}
+
+// ClassByHash returns a class declared at a block, a *rpc.ContractClass for
+// Sierra classes or a *rpc.DeprecatedContractClass for Cairo 0 classes.
+//
+// Parameters:
+// - ctx: the context
+// - classHash: the class hash
+// - blockID: the block, by number, hash, or the "latest" or "pending" tag
+// Returns:
+// - rpc.ClassOutput: the class
+// - error: an error wrapping rpc.ErrClassHashNotFound if the class is not declared
+func (c *Client) ClassByHash(ctx context.Context, classHash *felt.Felt, blockID rpc.BlockID) (rpc.ClassOutput, error) {
+	query, err := blockQuery(blockID)
+	if err != nil {
+		return nil, err
+	}
+	query.Set("classHash", classHash.String())
+	var raw map[string]json.RawMessage
+	if err := c.get(ctx, "get_class_by_hash", query, &raw); err != nil {
+		return nil, err
+	}
+	content, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := raw["sierra_program"]; ok {
+		var class rpc.ContractClass
+		if err := json.Unmarshal(content, &class); err != nil {
+			return nil, err
+		}
+		return &class, nil
+	}
+	var class rpc.DeprecatedContractClass
+	if err := json.Unmarshal(content, &class); err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+// CompiledClassByHash returns the CASM class compiled from a Sierra class.
+//
+// Parameters:
+// - ctx: the context
+// - classHash: the hash of the Sierra class
+// Returns:
+// - *contracts.CasmClass: the CASM class
+// - error: an error wrapping rpc.ErrClassHashNotFound if the class is not declared
+func (c *Client) CompiledClassByHash(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	var class contracts.CasmClass
+	if err := c.get(ctx, "get_compiled_class_by_class_hash", url.Values{"classHash": {classHash.String()}}, &class); err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+// Signature returns the signature of a block by the sequencer.
+//
+// Parameters:
+// - ctx: the context
+// - blockID: the block, by number or hash, or the "latest" tag
+// Returns:
+// - *BlockSignature: the signature
+// - error: an error wrapping rpc.ErrBlockNotFound if the block does not exist
+func (c *Client) Signature(ctx context.Context, blockID rpc.BlockID) (*BlockSignature, error) {
+	query, err := blockQuery(blockID)
+	if err != nil {
+		return nil, err
+	}
+	var signature BlockSignature
+	if err := c.get(ctx, "get_signature", query, &signature); err != nil {
+		return nil, err
+	}
+	return &signature, nil
+}
+
+// PublicKey returns the public key the sequencer signs blocks with.
+//
+// Parameters:
+// - ctx: the context
+// Returns:
+// - *felt.Felt: the public key
+// - error: an error if the request fails
+func (c *Client) PublicKey(ctx context.Context) (*felt.Felt, error) {
+	var key *felt.Felt
+	if err := c.get(ctx, "get_public_key", nil, &key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ContractAddresses returns the L1 addresses of the core contracts of the chain.
+//
+// Parameters:
+// - ctx: the context
+// Returns:
+// - *ContractAddresses: the addresses
+// - error: an error if the request fails
+func (c *Client) ContractAddresses(ctx context.Context) (*ContractAddresses, error) {
+	var addresses ContractAddresses
+	if err := c.get(ctx, "get_contract_addresses", nil, &addresses); err != nil {
+		return nil, err
+	}
+	return &addresses, nil
+}
+
+// get calls an endpoint of the feeder gateway and decodes its response.
+//
+// Parameters:
+// - ctx: the context
+// - endpoint: the endpoint, e.g. "get_block"
+// - query: the query parameters
+// - result: the value the response is decoded to
+// Returns:
+// - error: an *Error if the gateway answers with an error, or an error if the request fails
+func (c *Client) get(ctx context.Context, endpoint string, query url.Values, result interface{}) error {
+	target := c.baseURL + "/feeder_gateway/" + endpoint
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var gatewayErr Error
+		if json.Unmarshal(body, &gatewayErr) == nil && gatewayErr.Code != "" {
+			return &gatewayErr
+		}
+		return fmt.Errorf("gateway: %s: %s: %s", endpoint, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("gateway: %s: %w", endpoint, err)
+	}
+	return nil
+}
+
+// blockQuery returns the query parameters selecting a block.
+//
+// Parameters:
+// - blockID: the block
+// Returns:
+// - url.Values: the parameters, none for the latest block
+// - error: an error if the tag is neither "latest" nor "pending"
+func blockQuery(blockID rpc.BlockID) (url.Values, error) {
+	query := url.Values{}
+	switch {
+	case blockID.Number != nil:
+		query.Set("blockNumber", strconv.FormatUint(*blockID.Number, 10))
+	case blockID.Hash != nil:
+		query.Set("blockHash", blockID.Hash.String())
+	case blockID.Tag == "pending":
+		query.Set("blockNumber", "pending")
+	case blockID.Tag == "latest", blockID.Tag == "":
+	default:
+		return nil, errors.New("gateway: invalid block tag " + blockID.Tag)
+	}
+	return query, nil
+}
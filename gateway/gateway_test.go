@@ -0,0 +1,179 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// responses are the bodies served by the test gateway, by request URI.
+var responses = map[string]string{
+	"/feeder_gateway/get_block?blockNumber=7": `{
+		"block_hash": "0x10",
+		"parent_block_hash": "0xf",
+		"block_number": 7,
+		"state_root": "0x99",
+		"status": "ABORTED",
+		"timestamp": 1700000000,
+		"sequencer_address": "0x1",
+		"starknet_version": "0.13.1",
+		"l1_gas_price": {"price_in_wei": "0x3b9aca00", "price_in_fri": "0x2540be400"},
+		"transactions": [{
+			"type": "INVOKE_FUNCTION",
+			"version": "0x1",
+			"transaction_hash": "0x123",
+			"sender_address": "0x456",
+			"calldata": ["0x1", "0x2"],
+			"signature": ["0x3", "0x4"],
+			"max_fee": "0x100",
+			"nonce": "0x5"
+		}],
+		"transaction_receipts": [{
+			"transaction_hash": "0x123",
+			"transaction_index": 0,
+			"actual_fee": "0x80",
+			"execution_status": "REVERTED",
+			"revert_error": "out of gas",
+			"events": [{"from_address": "0x456", "keys": ["0x7"], "data": ["0x8"]}],
+			"l2_to_l1_messages": []
+		}]
+	}`,
+	"/feeder_gateway/get_block?blockNumber=8": `{
+		"code": "StarknetErrorCode.BLOCK_NOT_FOUND",
+		"message": "Block number 8 was not found."
+	}`,
+	"/feeder_gateway/get_state_update?blockHash=0x10": `{
+		"block_hash": "0x10",
+		"new_root": "0x99",
+		"old_root": "0x98",
+		"state_diff": {
+			"storage_diffs": {
+				"0x0b": [{"key": "0x1", "value": "0x2"}],
+				"0xa": [{"key": "0x3", "value": "0x4"}]
+			},
+			"nonces": {"0x456": "0x6"},
+			"deployed_contracts": [{"address": "0xc", "class_hash": "0xd"}],
+			"old_declared_contracts": ["0xe"],
+			"declared_classes": [{"class_hash": "0x20", "compiled_class_hash": "0x21"}],
+			"replaced_classes": [{"address": "0xc", "class_hash": "0x22"}]
+		}
+	}`,
+	"/feeder_gateway/get_public_key":         `"0x52934be54ce926b1e715f15dc2542849a97ecfdf829cd0b7384c64eeeb2264e"`,
+	"/feeder_gateway/get_contract_addresses": `{"Starknet": "0xc662c410C0ECf747543f5bA90660f6ABeBD9C8c4", "GpsStatementVerifier": "0x47312450B3Ac8b5b8e247a6bB6d523e7605bDb60"}`,
+}
+
+// newTestClient starts a gateway serving responses and returns a client of it.
+//
+// Parameters:
+// - t: the test
+// Returns:
+// - *Client: the client
+func newTestClient(t *testing.T) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("blockNumber") == "8" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(server.URL+"/", WithHeader("X-Api-Key", "secret"))
+}
+
+// TestClient_Block tests that aborted blocks are decoded with the rpc
+// transaction types, and that gateway errors match the rpc errors.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestClient_Block(t *testing.T) {
+	client := newTestClient(t)
+
+	block, err := client.Block(context.Background(), rpc.WithBlockNumber(7))
+	require.NoError(t, err)
+	require.Equal(t, BlockStatusAborted, block.Status)
+	require.Equal(t, "0.13.1", block.StarknetVersion)
+	require.Equal(t, "0x3b9aca00", block.L1GasPrice.PriceInWei.String())
+	require.Len(t, block.Transactions, 1)
+	txn, ok := block.Transactions[0].(rpc.BlockInvokeTxnV1)
+	require.True(t, ok, "unexpected transaction %T", block.Transactions[0])
+	require.Equal(t, "0x123", txn.TransactionHash.String())
+	require.Equal(t, "0x456", txn.SenderAddress.String())
+	require.Len(t, block.Receipts, 1)
+	require.Equal(t, rpc.TxnExecutionStatusREVERTED, block.Receipts[0].ExecutionStatus)
+	require.Equal(t, "out of gas", block.Receipts[0].RevertError)
+	require.Len(t, block.Receipts[0].Events, 1)
+
+	_, err = client.Block(context.Background(), rpc.WithBlockNumber(8))
+	require.Error(t, err)
+	require.True(t, errors.Is(err, rpc.ErrBlockNotFound), "unexpected error %v", err)
+	var gatewayErr *Error
+	require.True(t, errors.As(err, &gatewayErr))
+	require.Equal(t, "StarknetErrorCode.BLOCK_NOT_FOUND", gatewayErr.Code)
+}
+
+// TestClient_StateUpdate tests the conversion of gateway state updates to the
+// rpc state updates.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestClient_StateUpdate(t *testing.T) {
+	client := newTestClient(t)
+	blockHash, err := new(felt.Felt).SetString("0x10")
+	require.NoError(t, err)
+
+	update, err := client.StateUpdate(context.Background(), rpc.WithBlockHash(blockHash))
+	require.NoError(t, err)
+	require.Equal(t, "0x98", update.OldRoot.String())
+	diff := update.StateDiff
+	require.Len(t, diff.StorageDiffs, 2)
+	require.Equal(t, "0xa", diff.StorageDiffs[0].Address.String())
+	require.Equal(t, "0x4", diff.StorageDiffs[0].StorageEntries[0].Value.String())
+	require.Equal(t, "0xb", diff.StorageDiffs[1].Address.String())
+	require.Len(t, diff.Nonces, 1)
+	require.Equal(t, "0x6", diff.Nonces[0].Nonce.String())
+	require.Equal(t, "0xe", diff.DeprecatedDeclaredClasses[0].String())
+	require.Equal(t, "0x21", diff.DeclaredClasses[0].CompiledClassHash.String())
+	require.Equal(t, "0xd", diff.DeployedContracts[0].ClassHash.String())
+	require.Equal(t, "0xc", diff.ReplacedClasses[0].ContractClass.String())
+	require.Equal(t, "0x22", diff.ReplacedClasses[0].ClassHash.String())
+}
+
+// TestClient_Chain tests reading the public key of the sequencer and the
+// addresses of the core contracts.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestClient_Chain(t *testing.T) {
+	client := newTestClient(t)
+
+	key, err := client.PublicKey(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "0x52934be54ce926b1e715f15dc2542849a97ecfdf829cd0b7384c64eeeb2264e", key.String())
+
+	addresses, err := client.ContractAddresses(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "0xc662c410C0ECf747543f5bA90660f6ABeBD9C8c4", addresses.Starknet)
+}
@@ -0,0 +1,192 @@
+package gateway
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// BlockStatus is the status of a block in the feeder gateway, which unlike
+// rpc.BlockStatus includes aborted blocks.
+type BlockStatus string
+
+const (
+	BlockStatusPending      BlockStatus = "PENDING"
+	BlockStatusAcceptedOnL2 BlockStatus = "ACCEPTED_ON_L2"
+	BlockStatusAcceptedOnL1 BlockStatus = "ACCEPTED_ON_L1"
+	BlockStatusReverted     BlockStatus = "REVERTED"
+	BlockStatusAborted      BlockStatus = "ABORTED"
+)
+
+// GasPrice is the price of a unit of gas, in wei and in fri.
+type GasPrice struct {
+	PriceInWei *felt.Felt `json:"price_in_wei"`
+	PriceInFri *felt.Felt `json:"price_in_fri"`
+}
+
+// Block is a block of the feeder gateway.
+type Block struct {
+	BlockHash        *felt.Felt            `json:"block_hash"`
+	ParentHash       *felt.Felt            `json:"parent_block_hash"`
+	BlockNumber      uint64                `json:"block_number"`
+	NewRoot          *felt.Felt            `json:"state_root"`
+	Status           BlockStatus           `json:"status"`
+	Timestamp        uint64                `json:"timestamp"`
+	SequencerAddress *felt.Felt            `json:"sequencer_address"`
+	StarknetVersion  string                `json:"starknet_version"`
+	L1GasPrice       GasPrice              `json:"l1_gas_price"`
+	Transactions     rpc.BlockTransactions `json:"transactions"`
+	Receipts         []TransactionReceipt  `json:"transaction_receipts"`
+}
+
+// UnmarshalJSON unmarshals a block, renaming the gateway transaction types
+// to the types of the rpc package, e.g. INVOKE_FUNCTION to INVOKE.
+//
+// Parameters:
+// - data: the JSON data
+// Returns:
+// - error: an error if the unmarshaling fails
+func (b *Block) UnmarshalJSON(data []byte) error {
+	type block Block
+	var raw struct {
+		block
+		Transactions []map[string]interface{} `json:"transactions"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for _, txn := range raw.Transactions {
+		if txn["type"] == "INVOKE_FUNCTION" {
+			txn["type"] = string(rpc.TransactionType_Invoke)
+		}
+	}
+	transactions, err := json.Marshal(raw.Transactions)
+	if err != nil {
+		return err
+	}
+	*b = Block(raw.block)
+	return json.Unmarshal(transactions, &b.Transactions)
+}
+
+// TransactionReceipt is the receipt of a transaction in a block of the feeder
+// gateway.
+type TransactionReceipt struct {
+	TransactionHash  *felt.Felt             `json:"transaction_hash"`
+	TransactionIndex uint64                 `json:"transaction_index"`
+	ActualFee        *felt.Felt             `json:"actual_fee"`
+	ExecutionStatus  rpc.TxnExecutionStatus `json:"execution_status"`
+	RevertError      string                 `json:"revert_error,omitempty"`
+	Events           []rpc.Event            `json:"events"`
+	MessagesSent     []rpc.MsgToL1          `json:"l2_to_l1_messages"`
+}
+
+// BlockSignature is the signature of a block by the sequencer.
+type BlockSignature struct {
+	BlockHash *felt.Felt   `json:"block_hash"`
+	Signature []*felt.Felt `json:"signature"`
+}
+
+// ContractAddresses are the L1 addresses of the core contracts of a chain.
+type ContractAddresses struct {
+	Starknet             string `json:"Starknet"`
+	GpsStatementVerifier string `json:"GpsStatementVerifier"`
+}
+
+// stateUpdate is a state update of the feeder gateway, keyed by address.
+type stateUpdate struct {
+	BlockHash *felt.Felt `json:"block_hash"`
+	NewRoot   *felt.Felt `json:"new_root"`
+	OldRoot   *felt.Felt `json:"old_root"`
+	StateDiff struct {
+		StorageDiffs         map[string][]rpc.StorageEntry `json:"storage_diffs"`
+		Nonces               map[string]*felt.Felt         `json:"nonces"`
+		DeployedContracts    []rpc.DeployedContractItem    `json:"deployed_contracts"`
+		OldDeclaredContracts []*felt.Felt                  `json:"old_declared_contracts"`
+		DeclaredClasses      []rpc.DeclaredClassesItem     `json:"declared_classes"`
+		ReplacedClasses      []struct {
+			Address   *felt.Felt `json:"address"`
+			ClassHash *felt.Felt `json:"class_hash"`
+		} `json:"replaced_classes"`
+	} `json:"state_diff"`
+}
+
+// rpc converts the state update to the rpc state update, with the storage
+// diffs and the nonces sorted by address.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *rpc.StateUpdateOutput: the state update
+// - error: an error if an address is not a felt
+func (u *stateUpdate) rpc() (*rpc.StateUpdateOutput, error) {
+	diff := rpc.StateDiff{
+		DeprecatedDeclaredClasses: u.StateDiff.OldDeclaredContracts,
+		DeclaredClasses:           u.StateDiff.DeclaredClasses,
+		DeployedContracts:         u.StateDiff.DeployedContracts,
+	}
+	addresses, entries, err := sortedAddresses(u.StateDiff.StorageDiffs)
+	if err != nil {
+		return nil, err
+	}
+	for i, address := range addresses {
+		diff.StorageDiffs = append(diff.StorageDiffs, rpc.ContractStorageDiffItem{
+			Address:        address,
+			StorageEntries: entries[i],
+		})
+	}
+	addresses, nonces, err := sortedAddresses(u.StateDiff.Nonces)
+	if err != nil {
+		return nil, err
+	}
+	for i, address := range addresses {
+		diff.Nonces = append(diff.Nonces, rpc.ContractNonce{
+			ContractAddress: address,
+			Nonce:           nonces[i],
+		})
+	}
+	for _, replaced := range u.StateDiff.ReplacedClasses {
+		diff.ReplacedClasses = append(diff.ReplacedClasses, rpc.ReplacedClassesItem{
+			ContractClass: replaced.Address,
+			ClassHash:     replaced.ClassHash,
+		})
+	}
+	return &rpc.StateUpdateOutput{
+		BlockHash: u.BlockHash,
+		NewRoot:   u.NewRoot,
+		PendingStateUpdate: rpc.PendingStateUpdate{
+			OldRoot:   u.OldRoot,
+			StateDiff: diff,
+		},
+	}, nil
+}
+
+// sortedAddresses parses the addresses keying a map.
+//
+// Parameters:
+// - m: the map keyed by hexadecimal addresses
+// Returns:
+// - []*felt.Felt: the addresses, in ascending order
+// - []V: the values of the addresses, in the same order
+// - error: an error if a key is not a felt
+func sortedAddresses[V any](m map[string]V) ([]*felt.Felt, []V, error) {
+	addresses := make([]*felt.Felt, 0, len(m))
+	keys := make(map[*felt.Felt]string, len(m))
+	for key := range m {
+		address, err := new(felt.Felt).SetString(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		addresses = append(addresses, address)
+		keys[address] = key
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].Cmp(addresses[j]) < 0 })
+	values := make([]V, len(addresses))
+	for i, address := range addresses {
+		values[i] = m[keys[address]]
+	}
+	return addresses, values, nil
+}
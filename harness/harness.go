@@ -0,0 +1,198 @@
+// Package harness packages the integration scenarios of the SDK (deploy an
+// account, declare, deploy, invoke, read events, estimate an L1 message) as a
+// battery that downstream projects can run against their own infrastructure,
+// to validate a node, a key management setup or an account implementation.
+//
+// The Provider and the Account of the Env are the pluggable parts: the
+// account can sign with any account.Keystore, e.g. a remote signer. The
+// scenarios run in order and share their results through the Env, e.g. the
+// Deploy scenario deploys the class declared by the Declare scenario.
+// Scenarios missing an input of the Env are skipped.
+//
+//	env := &harness.Env{Provider: provider, Account: acc, Class: &class, Casm: &casm}
+//	report := harness.Run(ctx, env, harness.Default()...)
+//	if err := report.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+package harness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/contracts"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// DefaultPollInterval is the interval receipts are polled at when the Env
+// does not set one.
+const DefaultPollInterval = 2 * time.Second
+
+var (
+	ErrSkipped  = errors.New("harness: scenario skipped")
+	ErrReverted = errors.New("harness: transaction reverted")
+)
+
+// Env is the environment of the scenarios: the provider and the account
+// running them, their inputs, and the results they share.
+type Env struct {
+	// Provider is the node under test
+	Provider rpc.RpcProvider
+	// Account is the funded account sending the transactions
+	Account *account.Account
+	// PollInterval is the interval receipts are polled at, DefaultPollInterval if zero
+	PollInterval time.Duration
+
+	// AccountClassHash is the class of the account deployed by DeployAccount,
+	// whose constructor takes the public key, e.g. an OpenZeppelin account
+	AccountClassHash *felt.Felt
+	// Fund sends the fee of its deployment to the account deployed by
+	// DeployAccount
+	Fund func(ctx context.Context, address *felt.Felt) error
+
+	// Class and Casm are the class declared by Declare and deployed by Deploy
+	Class *rpc.ContractClass
+	Casm  *contracts.CasmClass
+	// ConstructorCalldata is the calldata of the constructor of the class
+	ConstructorCalldata []*felt.Felt
+	// Salt is the salt of the contract deployed by Deploy, random if nil
+	Salt *felt.Felt
+
+	// Invoke is the call of the Invoke scenario, to the contract deployed by
+	// Deploy if its contract address is nil. Its execution must emit an event.
+	Invoke *rpc.FunctionCall
+
+	// Message is the L1 message estimated by BridgeMessage
+	Message *rpc.MsgFromL1
+
+	// DeployedAccount is the address of the account deployed by DeployAccount
+	DeployedAccount *felt.Felt
+	// ClassHash is the hash of the class declared by Declare
+	ClassHash *felt.Felt
+	// ContractAddress is the address of the contract deployed by Deploy
+	ContractAddress *felt.Felt
+	// InvokeHash is the hash of the transaction sent by Invoke
+	InvokeHash *felt.Felt
+	// InvokeBlock is the latest block before the transaction sent by Invoke
+	InvokeBlock uint64
+	// MessageFee is the fee estimated by BridgeMessage
+	MessageFee *rpc.FeeEstimate
+}
+
+// pollInterval returns the interval receipts are polled at.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - time.Duration: the poll interval
+func (env *Env) pollInterval() time.Duration {
+	if env.PollInterval > 0 {
+		return env.PollInterval
+	}
+	return DefaultPollInterval
+}
+
+// wait waits for the receipt of a transaction sent by an account and checks
+// that the transaction succeeded.
+//
+// Parameters:
+// - ctx: the context
+// - acc: the account that sent the transaction
+// - txHash: the hash of the transaction
+// Returns:
+// - error: an error wrapping ErrReverted if the transaction reverted, or an error of the provider
+func (env *Env) wait(ctx context.Context, acc *account.Account, txHash *felt.Felt) error {
+	receipt, err := acc.WaitForTransactionReceipt(ctx, txHash, env.pollInterval())
+	if err != nil {
+		return err
+	}
+	if status := receipt.ExecutionStatus(); status != rpc.TxnExecutionStatusSUCCEEDED {
+		return fmt.Errorf("%w: %s is %s", ErrReverted, txHash, status)
+	}
+	return nil
+}
+
+// Scenario is a step of the battery.
+type Scenario struct {
+	Name string
+	Run  func(ctx context.Context, env *Env) error
+}
+
+// Result is the outcome of a scenario.
+type Result struct {
+	Name     string
+	Err      error
+	Duration time.Duration
+}
+
+// Skipped reports whether the scenario was skipped for a missing input.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if the scenario was skipped
+func (r Result) Skipped() bool {
+	return errors.Is(r.Err, ErrSkipped)
+}
+
+// Report is the outcome of a battery.
+type Report []Result
+
+// Err returns the failures of the battery, skipped scenarios excluded.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: an error listing the failed scenarios, nil if none failed
+func (r Report) Err() error {
+	var failures []string
+	for _, result := range r {
+		if result.Err != nil && !result.Skipped() {
+			failures = append(failures, fmt.Sprintf("%s: %v", result.Name, result.Err))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("harness: %d scenarios failed: %s", len(failures), strings.Join(failures, "; "))
+}
+
+// Run runs scenarios in order. A failing scenario does not stop the battery,
+// the scenarios depending on its results are skipped instead.
+//
+// Parameters:
+// - ctx: the context
+// - env: the environment
+// - scenarios: the scenarios, e.g. Default()
+// Returns:
+// - Report: the result of each scenario
+func Run(ctx context.Context, env *Env, scenarios ...Scenario) Report {
+	report := make(Report, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		start := time.Now()
+		err := scenario.Run(ctx, env)
+		report = append(report, Result{Name: scenario.Name, Err: err, Duration: time.Since(start)})
+	}
+	return report
+}
+
+// skip returns an ErrSkipped for a missing input.
+//
+// Parameters:
+// - input: the missing input
+// Returns:
+// - error: an error wrapping ErrSkipped
+func skip(input string) error {
+	return fmt.Errorf("%w: no %s", ErrSkipped, input)
+}
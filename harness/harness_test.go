@@ -0,0 +1,69 @@
+package harness
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestRun tests that the battery runs every scenario, skips the scenarios
+// missing an input and reports the failures.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+
+	contract := utils.TestHexToFelt(t, "0xc0")
+	invokeHash := utils.TestHexToFelt(t, "0x123")
+	message := rpc.MsgFromL1{FromAddress: "0xae0ee0a63a2ce6baeeffe56e7714fb4efe48d419", ToAddress: contract}
+	env := &Env{
+		Provider:        provider,
+		Message:         &message,
+		ContractAddress: contract,
+		InvokeHash:      invokeHash,
+		InvokeBlock:     10,
+	}
+
+	gomock.InOrder(
+		provider.EXPECT().Events(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input rpc.EventsInput) (*rpc.EventChunk, error) {
+			require.Equal(t, contract, input.Address)
+			require.Equal(t, rpc.WithBlockNumber(10), input.FromBlock)
+			return &rpc.EventChunk{
+				Events:            []rpc.EmittedEvent{{TransactionHash: utils.TestHexToFelt(t, "0x1")}},
+				ContinuationToken: "next",
+			}, nil
+		}),
+		provider.EXPECT().Events(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input rpc.EventsInput) (*rpc.EventChunk, error) {
+			require.Equal(t, "next", input.ContinuationToken)
+			return &rpc.EventChunk{Events: []rpc.EmittedEvent{{TransactionHash: invokeHash}}}, nil
+		}),
+	)
+	provider.EXPECT().EstimateMessageFee(gomock.Any(), message, rpc.WithBlockTag("latest")).
+		Return(&rpc.FeeEstimate{OverallFee: utils.Uint64ToFelt(42)}, nil)
+
+	report := Run(context.Background(), env, Default()...)
+	require.Len(t, report, 6)
+	for _, result := range report[:4] {
+		require.True(t, result.Skipped(), "%s: %v", result.Name, result.Err)
+	}
+	require.NoError(t, report[4].Err)
+	require.NoError(t, report[5].Err)
+	require.NoError(t, report.Err())
+	require.Equal(t, "0x2a", env.MessageFee.OverallFee.String())
+
+	provider.EXPECT().EstimateMessageFee(gomock.Any(), message, rpc.WithBlockTag("latest")).Return(nil, errors.New("unavailable"))
+	report = Run(context.Background(), env, Scenario{Name: "bridge_message", Run: BridgeMessage})
+	require.Error(t, report.Err())
+	require.Contains(t, report.Err().Error(), "bridge_message: unavailable")
+}
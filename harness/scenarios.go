@@ -0,0 +1,278 @@
+package harness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/history"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// UniversalDeployer is the address of the Universal Deployer Contract the
+// Deploy scenario deploys with.
+var UniversalDeployer = history.UniversalDeployers[0]
+
+// Default returns the battery of the SDK, in the order the scenarios depend
+// on each other.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []Scenario: the scenarios
+func Default() []Scenario {
+	return []Scenario{
+		{Name: "deploy_account", Run: DeployAccount},
+		{Name: "declare", Run: Declare},
+		{Name: "deploy", Run: Deploy},
+		{Name: "invoke", Run: Invoke},
+		{Name: "events", Run: Events},
+		{Name: "bridge_message", Run: BridgeMessage},
+	}
+}
+
+// DeployAccount deploys an account of class Env.AccountClassHash with new
+// random keys, funded by Env.Fund, and records its address in
+// Env.DeployedAccount.
+//
+// Parameters:
+// - ctx: the context
+// - env: the environment
+// Returns:
+// - error: ErrSkipped without an account class or a funding function, or an error if the deployment fails
+func DeployAccount(ctx context.Context, env *Env) error {
+	if env.AccountClassHash == nil {
+		return skip("account class hash")
+	}
+	if env.Fund == nil {
+		return skip("funding function")
+	}
+	ks, pub, _ := account.GetRandomKeys()
+	calldata := []*felt.Felt{pub}
+	address, err := env.Account.PrecomputeAddress(&felt.Zero, pub, env.AccountClassHash, calldata)
+	if err != nil {
+		return err
+	}
+	acc, err := account.NewAccount(env.Provider, address, pub.String(), ks, env.Account.CairoVersion)
+	if err != nil {
+		return err
+	}
+	if err := env.Fund(ctx, address); err != nil {
+		return fmt.Errorf("fund %s: %w", address, err)
+	}
+
+	tx := rpc.DeployAccountTxn{
+		Type:                rpc.TransactionType_DeployAccount,
+		Version:             rpc.TransactionV1,
+		Nonce:               new(felt.Felt),
+		MaxFee:              new(felt.Felt),
+		ClassHash:           env.AccountClassHash,
+		ContractAddressSalt: pub,
+		ConstructorCalldata: calldata,
+	}
+	if err := acc.SignDeployAccountTransaction(ctx, &tx, address); err != nil {
+		return err
+	}
+	estimates, err := acc.EstimateFee(ctx, []rpc.BroadcastTxn{rpc.BroadcastDeployAccountTxn{DeployAccountTxn: tx}}, []rpc.SimulationFlag{}, rpc.WithBlockTag("pending"))
+	if err != nil {
+		return err
+	}
+	overall := estimates[0].OverallFee.BigInt(new(big.Int))
+	tx.MaxFee = utils.BigIntToFelt(overall.Mul(overall, big.NewInt(2)))
+	if err := acc.SignDeployAccountTransaction(ctx, &tx, address); err != nil {
+		return err
+	}
+	resp, err := acc.AddDeployAccountTransaction(ctx, rpc.BroadcastDeployAccountTxn{DeployAccountTxn: tx})
+	if err != nil {
+		return err
+	}
+	if err := env.wait(ctx, acc, resp.TransactionHash); err != nil {
+		return err
+	}
+	env.DeployedAccount = address
+	return nil
+}
+
+// Declare declares Env.Class with Env.Account and records its hash in
+// Env.ClassHash. A class already declared is not an error.
+//
+// Parameters:
+// - ctx: the context
+// - env: the environment
+// Returns:
+// - error: ErrSkipped without a class, or an error if the declaration fails
+func Declare(ctx context.Context, env *Env) error {
+	if env.Class == nil || env.Casm == nil {
+		return skip("class")
+	}
+	resp, err := env.Account.Declare(ctx, *env.Class, *env.Casm)
+	var rpcErr *rpc.RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code() == rpc.ErrClassAlreadyDeclared.Code() {
+		classHash, err := hash.ClassHash(*env.Class)
+		if err != nil {
+			return err
+		}
+		env.ClassHash = classHash
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := env.wait(ctx, env.Account, resp.TransactionHash); err != nil {
+		return err
+	}
+	env.ClassHash = resp.ClassHash
+	return nil
+}
+
+// Deploy deploys the class declared by Declare through the Universal
+// Deployer, and records the address of the contract in Env.ContractAddress.
+//
+// Parameters:
+// - ctx: the context
+// - env: the environment
+// Returns:
+// - error: ErrSkipped without a declared class, or an error if the deployment fails or the contract has another class
+func Deploy(ctx context.Context, env *Env) error {
+	if env.ClassHash == nil {
+		return skip("declared class")
+	}
+	salt := env.Salt
+	if salt == nil {
+		var err error
+		if salt, err = new(felt.Felt).SetRandom(); err != nil {
+			return err
+		}
+	}
+	calldata := []*felt.Felt{env.ClassHash, salt, new(felt.Felt), new(felt.Felt).SetUint64(uint64(len(env.ConstructorCalldata)))}
+	calldata = append(calldata, env.ConstructorCalldata...)
+	resp, err := env.Account.Execute(ctx, []rpc.FunctionCall{{
+		ContractAddress:    UniversalDeployer,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("deployContract"),
+		Calldata:           calldata,
+	}})
+	if err != nil {
+		return err
+	}
+	if err := env.wait(ctx, env.Account, resp.TransactionHash); err != nil {
+		return err
+	}
+	// not unique: the address does not depend on the deployer
+	address, err := env.Account.PrecomputeAddress(&felt.Zero, salt, env.ClassHash, env.ConstructorCalldata)
+	if err != nil {
+		return err
+	}
+	classHash, err := env.Provider.ClassHashAt(ctx, rpc.WithBlockTag("latest"), address)
+	if err != nil {
+		return err
+	}
+	if !classHash.Equal(env.ClassHash) {
+		return fmt.Errorf("harness: contract %s has class %s, expected %s", address, classHash, env.ClassHash)
+	}
+	env.ContractAddress = address
+	return nil
+}
+
+// Invoke executes Env.Invoke, by default on the contract deployed by Deploy,
+// and records the transaction in Env.InvokeHash.
+//
+// Parameters:
+// - ctx: the context
+// - env: the environment
+// Returns:
+// - error: ErrSkipped without a call or a contract, or an error if the transaction fails
+func Invoke(ctx context.Context, env *Env) error {
+	if env.Invoke == nil {
+		return skip("invoke call")
+	}
+	call := *env.Invoke
+	if call.ContractAddress == nil {
+		call.ContractAddress = env.ContractAddress
+	}
+	if call.ContractAddress == nil {
+		return skip("contract")
+	}
+	block, err := env.Provider.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := env.Account.Execute(ctx, []rpc.FunctionCall{call})
+	if err != nil {
+		return err
+	}
+	if err := env.wait(ctx, env.Account, resp.TransactionHash); err != nil {
+		return err
+	}
+	env.InvokeHash = resp.TransactionHash
+	env.InvokeBlock = block
+	return nil
+}
+
+// Events checks that the events of the transaction sent by Invoke are
+// returned by the event filter of the node.
+//
+// Parameters:
+// - ctx: the context
+// - env: the environment
+// Returns:
+// - error: ErrSkipped without an invoke, or an error if no event of the transaction is found
+func Events(ctx context.Context, env *Env) error {
+	if env.InvokeHash == nil {
+		return skip("invoke transaction")
+	}
+	input := rpc.EventsInput{
+		EventFilter: rpc.EventFilter{
+			FromBlock: rpc.WithBlockNumber(env.InvokeBlock),
+			ToBlock:   rpc.WithBlockTag("latest"),
+		},
+		ResultPageRequest: rpc.ResultPageRequest{ChunkSize: 100},
+	}
+	if env.Invoke == nil || env.Invoke.ContractAddress == nil {
+		input.Address = env.ContractAddress
+	}
+	for {
+		chunk, err := env.Provider.Events(ctx, input)
+		if err != nil {
+			return err
+		}
+		for _, event := range chunk.Events {
+			if event.TransactionHash.Equal(env.InvokeHash) {
+				return nil
+			}
+		}
+		if chunk.ContinuationToken == "" {
+			return fmt.Errorf("harness: no event of %s since block %d", env.InvokeHash, env.InvokeBlock)
+		}
+		input.ContinuationToken = chunk.ContinuationToken
+	}
+}
+
+// BridgeMessage estimates the fee of the l1_handler executing Env.Message,
+// and records it in Env.MessageFee.
+//
+// Parameters:
+// - ctx: the context
+// - env: the environment
+// Returns:
+// - error: ErrSkipped without a message, or an error if the estimation fails or is zero
+func BridgeMessage(ctx context.Context, env *Env) error {
+	if env.Message == nil {
+		return skip("L1 message")
+	}
+	estimate, err := env.Provider.EstimateMessageFee(ctx, *env.Message, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return err
+	}
+	if estimate.OverallFee == nil || estimate.OverallFee.IsZero() {
+		return errors.New("harness: zero message fee")
+	}
+	env.MessageFee = estimate
+	return nil
+}
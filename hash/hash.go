@@ -5,7 +5,6 @@ import (
 	"github.com/xiang-xx/starknet.go/contracts"
 	"github.com/xiang-xx/starknet.go/curve"
 	"github.com/xiang-xx/starknet.go/rpc"
-	"github.com/xiang-xx/starknet.go/utils"
 )
 
 // ComputeHashOnElementsFelt computes the hash on elements of a Felt array.
@@ -16,13 +15,7 @@ import (
 // - *felt.Felt: a pointer to a Felt object
 // - error: an error if any
 func ComputeHashOnElementsFelt(feltArr []*felt.Felt) (*felt.Felt, error) {
-	bigIntArr := utils.FeltArrToBigIntArr(feltArr)
-
-	hash, err := curve.Curve.ComputeHashOnElements(bigIntArr)
-	if err != nil {
-		return nil, err
-	}
-	return utils.BigIntToFelt(hash), nil
+	return curve.Curve.PedersenArray(feltArr...), nil
 }
 
 // CalculateTransactionHashCommon calculates the transaction hash common to be used in the StarkNet network - a unique identifier of the transaction.
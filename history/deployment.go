@@ -0,0 +1,240 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/curve"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var ErrNotDeployed = errors.New("history: contract not deployed")
+
+// UniversalDeployers are the addresses of the Cairo 0 and Cairo 1 Universal
+// Deployer Contracts, whose ContractDeployed events identify deployments.
+var UniversalDeployers = []*felt.Felt{
+	mustFelt("0x41a78e741e5af2fec34b695679bc6891742439f7afb8484ecd7766661ad02bf"),
+	mustFelt("0x2ceed65a4bd731034c01113685c831b01c15d7d432f71afb1cf1634b53a2125"),
+}
+
+var (
+	contractDeployedSelector = utils.GetSelectorFromNameFelt("ContractDeployed")
+	contractAddressPrefix    = new(felt.Felt).SetBytes([]byte("STARKNET_CONTRACT_ADDRESS"))
+)
+
+// Deployment is the deployment of a contract.
+type Deployment struct {
+	BlockNumber uint64
+	BlockHash   *felt.Felt
+	// ClassHash is the class the contract was deployed with
+	ClassHash *felt.Felt
+	// TransactionHash is the deploying transaction, nil when it can not be
+	// identified without tracing the block
+	TransactionHash *felt.Felt
+	// Deployer is the account or contract that deployed the contract, zero
+	// for deploy account transactions, nil when unknown
+	Deployer *felt.Felt
+}
+
+// FindDeployment locates the deployment of a contract. The block is found by
+// binary search on the existence of the contract, then the transaction is
+// identified by, in order, a ContractDeployed event of a Universal Deployer,
+// a deploy or deploy account transaction computing to the address, or the
+// single transaction of the block emitting events from the contract.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - address: the address of the contract
+// Returns:
+// - *Deployment: the deployment
+// - error: ErrNotDeployed if the contract does not exist, or an error of the provider
+func FindDeployment(ctx context.Context, provider rpc.RpcProvider, address *felt.Felt) (*Deployment, error) {
+	latest, err := provider.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exists := func(n uint64) (bool, error) {
+		_, err := provider.ClassHashAt(ctx, rpc.WithBlockNumber(n), address)
+		switch {
+		case err == nil:
+			return true, nil
+		case errors.Is(err, rpc.ErrContractNotFound):
+			return false, nil
+		}
+		return false, err
+	}
+	if ok, err := exists(latest); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrNotDeployed
+	}
+
+	low, high := uint64(0), latest
+	for low < high {
+		mid := low + (high-low)/2
+		ok, err := exists(mid)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	update, err := provider.StateUpdate(ctx, rpc.WithBlockNumber(low))
+	if err != nil {
+		return nil, err
+	}
+	deployment := &Deployment{BlockNumber: low, BlockHash: update.BlockHash}
+	for _, item := range update.StateDiff.DeployedContracts {
+		if item.Address.Equal(address) {
+			deployment.ClassHash = item.ClassHash
+		}
+	}
+	if deployment.ClassHash == nil {
+		return nil, fmt.Errorf("history: block %d does not deploy %s", low, address)
+	}
+
+	if found, err := findUDCDeployment(ctx, provider, deployment, address); err != nil || found {
+		return deployment, err
+	}
+	if found, err := findDeployTransaction(ctx, provider, deployment, address); err != nil || found {
+		return deployment, err
+	}
+	candidates, err := emitters(ctx, provider, address, low)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 1 {
+		deployment.TransactionHash = candidates[0]
+	}
+	return deployment, nil
+}
+
+// findUDCDeployment completes the deployment from the ContractDeployed event
+// of a Universal Deployer, whose data starts with the address and the deployer.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - deployment: the deployment to complete
+// - address: the address of the contract
+// Returns:
+// - bool: true if the event was found
+// - error: an error of the provider
+func findUDCDeployment(ctx context.Context, provider rpc.RpcProvider, deployment *Deployment, address *felt.Felt) (bool, error) {
+	for _, udc := range UniversalDeployers {
+		input := rpc.EventsInput{
+			EventFilter: rpc.EventFilter{
+				FromBlock: rpc.WithBlockNumber(deployment.BlockNumber),
+				ToBlock:   rpc.WithBlockNumber(deployment.BlockNumber),
+				Address:   udc,
+				Keys:      [][]*felt.Felt{{contractDeployedSelector}},
+			},
+			ResultPageRequest: rpc.ResultPageRequest{ChunkSize: eventsChunkSize},
+		}
+		for {
+			chunk, err := provider.Events(ctx, input)
+			if err != nil {
+				return false, err
+			}
+			for _, event := range chunk.Events {
+				if len(event.Data) >= 2 && event.Data[0].Equal(address) {
+					deployment.TransactionHash = event.TransactionHash
+					deployment.Deployer = event.Data[1]
+					return true, nil
+				}
+			}
+			if chunk.ContinuationToken == "" {
+				break
+			}
+			input.ContinuationToken = chunk.ContinuationToken
+		}
+	}
+	return false, nil
+}
+
+// findDeployTransaction completes the deployment from the deploy or deploy
+// account transaction of the block computing to the address.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - deployment: the deployment to complete
+// - address: the address of the contract
+// Returns:
+// - bool: true if the transaction was found
+// - error: an error of the provider
+func findDeployTransaction(ctx context.Context, provider rpc.RpcProvider, deployment *Deployment, address *felt.Felt) (bool, error) {
+	result, err := provider.BlockWithTxs(ctx, rpc.WithBlockNumber(deployment.BlockNumber))
+	if err != nil {
+		return false, err
+	}
+	for _, tx := range result.Transactions() {
+		var salt, classHash *felt.Felt
+		var calldata []*felt.Felt
+		switch tx := tx.(type) {
+		case rpc.BlockDeployAccountTxn:
+			salt, classHash, calldata = tx.ContractAddressSalt, tx.ClassHash, tx.ConstructorCalldata
+		case rpc.BlockDeployAccountTxnV3:
+			salt, classHash, calldata = tx.ContractAddressSalt, tx.ClassHash, tx.ConstructorCalldata
+		case rpc.BlockDeployTxn:
+			salt, classHash, calldata = tx.ContractAddressSalt, tx.ClassHash, tx.ConstructorCalldata
+		default:
+			continue
+		}
+		computed, err := contractAddress(new(felt.Felt), salt, classHash, calldata)
+		if err != nil {
+			return false, err
+		}
+		if computed.Equal(address) {
+			deployment.TransactionHash = tx.Hash()
+			deployment.Deployer = new(felt.Felt)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// contractAddress computes the address of a contract.
+//
+// Parameters:
+// - deployer: the deployer address, zero for deploy account transactions
+// - salt: the salt
+// - classHash: the class hash
+// - calldata: the constructor calldata
+// Returns:
+// - *felt.Felt: the address
+// - error: an error if the hash fails
+func contractAddress(deployer, salt, classHash *felt.Felt, calldata []*felt.Felt) (*felt.Felt, error) {
+	calldataHash, err := curve.Curve.ComputeHashOnElements(utils.FeltArrToBigIntArr(calldata))
+	if err != nil {
+		return nil, err
+	}
+	elements := append(utils.FeltArrToBigIntArr([]*felt.Felt{contractAddressPrefix, deployer, salt, classHash}), calldataHash)
+	address, err := curve.Curve.ComputeHashOnElements(elements)
+	if err != nil {
+		return nil, err
+	}
+	return utils.BigIntToFelt(address), nil
+}
+
+// mustFelt parses a hexadecimal felt, panicking on failure.
+//
+// Parameters:
+// - hex: the hexadecimal string
+// Returns:
+// - *felt.Felt: the felt
+func mustFelt(hex string) *felt.Felt {
+	f, err := new(felt.Felt).SetString(hex)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
@@ -0,0 +1,272 @@
+// Package history reconstructs the history of a storage slot or of the nonce
+// of a contract by replaying the state updates of a block range, without a
+// provider able to trace archived transactions.
+package history
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+var ErrInvalidRange = errors.New("history: invalid block range")
+
+// eventsChunkSize is the page size of the events used to attribute storage changes.
+const eventsChunkSize = 100
+
+// Change is a change of a value in a block.
+type Change struct {
+	BlockNumber uint64
+	BlockHash   *felt.Felt
+	// Previous is the value before the block, nil for the first change of a
+	// range starting at the genesis block
+	Previous *felt.Felt
+	// Value is the value at the end of the block
+	Value *felt.Felt
+	// TransactionHash is the transaction that made the change, nil when it
+	// can not be told apart from the Candidates
+	TransactionHash *felt.Felt
+	// Candidates are the transactions of the block that may have made the
+	// change when TransactionHash is nil
+	Candidates []*felt.Felt
+}
+
+// StorageHistory returns the changes of a storage slot of a contract from
+// block from to block to, included.
+//
+// A change is attributed to a transaction through the events emitted by the
+// contract in the block: when a single transaction emitted events, it is the
+// transaction that made the change, otherwise the emitting transactions are
+// returned as candidates.
+//
+// The value before the range is read at block from-1, a contract not yet
+// deployed having zeroed storage.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - contract: the address of the contract
+// - key: the storage key
+// - from: the first block
+// - to: the last block
+// Returns:
+// - []Change: the changes, in block order
+// - error: an error if the range is invalid or the provider fails
+func StorageHistory(ctx context.Context, provider rpc.RpcProvider, contract, key *felt.Felt, from, to uint64) ([]Change, error) {
+	if from > to {
+		return nil, ErrInvalidRange
+	}
+	var initial *felt.Felt
+	if from > 0 {
+		values, err := provider.StorageAtKeys(ctx, contract, []*felt.Felt{key}, rpc.WithBlockNumber(from-1))
+		switch {
+		case err == nil:
+			initial = values[*key]
+		case errors.Is(err, rpc.ErrContractNotFound):
+			initial = new(felt.Felt)
+		default:
+			return nil, err
+		}
+	}
+	find := func(diff *rpc.StateDiff) *felt.Felt {
+		for _, item := range diff.StorageDiffs {
+			if !item.Address.Equal(contract) {
+				continue
+			}
+			for _, entry := range item.StorageEntries {
+				if entry.Key.Equal(key) {
+					return entry.Value
+				}
+			}
+		}
+		return nil
+	}
+	attribute := func(ctx context.Context, change *Change) error {
+		candidates, err := emitters(ctx, provider, contract, change.BlockNumber)
+		if err != nil {
+			return err
+		}
+		if len(candidates) == 1 {
+			change.TransactionHash = candidates[0]
+		} else {
+			change.Candidates = candidates
+		}
+		return nil
+	}
+	return replay(ctx, provider, from, to, initial, find, attribute)
+}
+
+// NonceHistory returns the changes of the nonce of a contract from block
+// from to block to, included. Each change is attributed to the transaction
+// sent by the contract with the previous nonce.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - contract: the address of the contract
+// - from: the first block
+// - to: the last block
+// Returns:
+// - []Change: the changes, in block order
+// - error: an error if the range is invalid or the provider fails
+func NonceHistory(ctx context.Context, provider rpc.RpcProvider, contract *felt.Felt, from, to uint64) ([]Change, error) {
+	if from > to {
+		return nil, ErrInvalidRange
+	}
+	var initial *felt.Felt
+	if from > 0 {
+		nonce, err := provider.Nonce(ctx, rpc.WithBlockNumber(from-1), contract)
+		switch {
+		case err == nil:
+			initial = nonce
+		case errors.Is(err, rpc.ErrContractNotFound):
+			initial = new(felt.Felt)
+		default:
+			return nil, err
+		}
+	}
+	find := func(diff *rpc.StateDiff) *felt.Felt {
+		for _, item := range diff.Nonces {
+			if item.ContractAddress.Equal(contract) {
+				return item.Nonce
+			}
+		}
+		return nil
+	}
+	attribute := func(ctx context.Context, change *Change) error {
+		result, err := provider.BlockWithTxs(ctx, rpc.WithBlockNumber(change.BlockNumber))
+		if err != nil {
+			return err
+		}
+		sent := new(felt.Felt).Sub(change.Value, new(felt.Felt).SetUint64(1))
+		for _, tx := range result.Transactions() {
+			if sender, nonce := senderNonce(tx); sender != nil && sender.Equal(contract) && nonce.Equal(sent) {
+				change.TransactionHash = tx.Hash()
+				return nil
+			}
+		}
+		return nil
+	}
+	return replay(ctx, provider, from, to, initial, find, attribute)
+}
+
+// replay walks the state updates of a block range and records the blocks
+// changing a value.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - from: the first block
+// - to: the last block
+// - previous: the value before the range, nil if unknown
+// - find: returns the value set by a state diff, nil if it is not set
+// - attribute: completes a change with the transaction that made it
+// Returns:
+// - []Change: the changes, in block order
+// - error: an error if the range is invalid or the provider fails
+func replay(ctx context.Context, provider rpc.RpcProvider, from, to uint64, previous *felt.Felt,
+	find func(*rpc.StateDiff) *felt.Felt, attribute func(context.Context, *Change) error) ([]Change, error) {
+	if from > to {
+		return nil, ErrInvalidRange
+	}
+	changes := []Change{}
+	for n := from; n <= to; n++ {
+		update, err := provider.StateUpdate(ctx, rpc.WithBlockNumber(n))
+		if err != nil {
+			return nil, err
+		}
+		value := find(&update.StateDiff)
+		if value == nil || (previous != nil && value.Equal(previous)) {
+			continue
+		}
+		change := Change{BlockNumber: n, BlockHash: update.BlockHash, Previous: previous, Value: value}
+		if err := attribute(ctx, &change); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+		previous = value
+	}
+	return changes, nil
+}
+
+// emitters returns the transactions of a block that emitted events from the contract.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - contract: the address of the contract
+// - block: the block number
+// Returns:
+// - []*felt.Felt: the transaction hashes, in order
+// - error: an error if the provider fails
+func emitters(ctx context.Context, provider rpc.RpcProvider, contract *felt.Felt, block uint64) ([]*felt.Felt, error) {
+	input := rpc.EventsInput{
+		EventFilter:       rpc.EventFilter{FromBlock: rpc.WithBlockNumber(block), ToBlock: rpc.WithBlockNumber(block), Address: contract},
+		ResultPageRequest: rpc.ResultPageRequest{ChunkSize: eventsChunkSize},
+	}
+	hashes := []*felt.Felt{}
+	seen := map[felt.Felt]bool{}
+	for {
+		chunk, err := provider.Events(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range chunk.Events {
+			if event.TransactionHash != nil && !seen[*event.TransactionHash] {
+				seen[*event.TransactionHash] = true
+				hashes = append(hashes, event.TransactionHash)
+			}
+		}
+		if chunk.ContinuationToken == "" {
+			return hashes, nil
+		}
+		input.ContinuationToken = chunk.ContinuationToken
+	}
+}
+
+// senderNonce returns the sender and the nonce of a transaction. The sender
+// of a deploy account transaction is the deployed account.
+//
+// Parameters:
+// - tx: the transaction
+// Returns:
+// - *felt.Felt: the sender, nil for transactions without one
+// - *felt.Felt: the nonce
+func senderNonce(tx rpc.BlockTransaction) (*felt.Felt, *felt.Felt) {
+	switch tx := tx.(type) {
+	case rpc.BlockInvokeTxnV1:
+		return tx.SenderAddress, tx.Nonce
+	case rpc.BlockInvokeTxnV3:
+		return tx.SenderAddress, tx.Nonce
+	case rpc.BlockDeclareTxnV1:
+		return tx.SenderAddress, tx.Nonce
+	case rpc.BlockDeclareTxnV2:
+		return tx.SenderAddress, tx.Nonce
+	case rpc.BlockDeclareTxnV3:
+		return tx.SenderAddress, tx.Nonce
+	case rpc.BlockDeployAccountTxn:
+		return deployedAccount(tx.ContractAddressSalt, tx.ClassHash, tx.ConstructorCalldata), tx.Nonce
+	case rpc.BlockDeployAccountTxnV3:
+		return deployedAccount(tx.ContractAddressSalt, tx.ClassHash, tx.ConstructorCalldata), tx.Nonce
+	}
+	return nil, nil
+}
+
+// deployedAccount returns the address of the account of a deploy account
+// transaction.
+//
+// Parameters:
+// - salt: the salt of the address
+// - classHash: the class hash of the account
+// - calldata: the constructor calldata
+// Returns:
+// - *felt.Felt: the address, nil if it can not be computed
+func deployedAccount(salt, classHash *felt.Felt, calldata []*felt.Felt) *felt.Felt {
+	address, err := contractAddress(new(felt.Felt), salt, classHash, calldata)
+	if err != nil {
+		return nil
+	}
+	return address
+}
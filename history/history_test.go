@@ -0,0 +1,180 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestHistory tests the storage and nonce histories replayed over four
+// blocks, from the values before the range, with unchanged values skipped and
+// changes attributed to transactions.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+
+	contract := utils.TestHexToFelt(t, "0xc0de")
+	key := utils.TestHexToFelt(t, "0x5107")
+	diffs := []rpc.StateDiff{
+		{StorageDiffs: []rpc.ContractStorageDiffItem{{Address: contract, StorageEntries: []rpc.StorageEntry{{Key: key, Value: utils.Uint64ToFelt(1)}}}},
+			Nonces: []rpc.ContractNonce{{ContractAddress: contract, Nonce: utils.Uint64ToFelt(4)}}},
+		{StorageDiffs: []rpc.ContractStorageDiffItem{{Address: utils.TestHexToFelt(t, "0x1"), StorageEntries: []rpc.StorageEntry{{Key: key, Value: utils.Uint64ToFelt(9)}}}}},
+		{StorageDiffs: []rpc.ContractStorageDiffItem{{Address: contract, StorageEntries: []rpc.StorageEntry{{Key: key, Value: utils.Uint64ToFelt(1)}}}}},
+		{StorageDiffs: []rpc.ContractStorageDiffItem{{Address: contract, StorageEntries: []rpc.StorageEntry{{Key: key, Value: utils.Uint64ToFelt(2)}}}},
+			Nonces: []rpc.ContractNonce{{ContractAddress: contract, Nonce: utils.Uint64ToFelt(5)}}},
+	}
+	for i, diff := range diffs {
+		update := &rpc.StateUpdateOutput{BlockHash: utils.Uint64ToFelt(uint64(100 + i))}
+		update.StateDiff = diff
+		provider.EXPECT().StateUpdate(gomock.Any(), rpc.WithBlockNumber(uint64(10+i))).Return(update, nil).Times(2)
+	}
+
+	txA, txB := utils.TestHexToFelt(t, "0xa"), utils.TestHexToFelt(t, "0xb")
+	provider.EXPECT().Events(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input rpc.EventsInput) (*rpc.EventChunk, error) {
+		switch {
+		case *input.FromBlock.Number == 10:
+			return &rpc.EventChunk{Events: []rpc.EmittedEvent{{TransactionHash: txA}, {TransactionHash: txA}}}, nil
+		case input.ContinuationToken == "":
+			return &rpc.EventChunk{Events: []rpc.EmittedEvent{{TransactionHash: txA}}, ContinuationToken: "next"}, nil
+		default:
+			return &rpc.EventChunk{Events: []rpc.EmittedEvent{{TransactionHash: txB}}}, nil
+		}
+	}).Times(3)
+
+	provider.EXPECT().StorageAtKeys(gomock.Any(), contract, []*felt.Felt{key}, rpc.WithBlockNumber(9)).Return(map[felt.Felt]*felt.Felt{*key: new(felt.Felt)}, nil)
+	changes, err := StorageHistory(context.Background(), provider, contract, key, 10, 13)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, Change{BlockNumber: 10, BlockHash: utils.Uint64ToFelt(100), Previous: new(felt.Felt), Value: utils.Uint64ToFelt(1), TransactionHash: txA}, changes[0])
+	require.Equal(t, Change{BlockNumber: 13, BlockHash: utils.Uint64ToFelt(103), Previous: utils.Uint64ToFelt(1),
+		Value: utils.Uint64ToFelt(2), Candidates: []*felt.Felt{txA, txB}}, changes[1])
+
+	provider.EXPECT().Nonce(gomock.Any(), rpc.WithBlockNumber(9), contract).Return(utils.Uint64ToFelt(3), nil)
+	provider.EXPECT().BlockWithTxs(gomock.Any(), rpc.WithBlockNumber(10)).Return(&rpc.BlockResult{Block: &rpc.Block{Transactions: rpc.BlockTransactions{
+		rpc.BlockInvokeTxnV1{TransactionHash: txB, InvokeTxnV1: rpc.InvokeTxnV1{SenderAddress: utils.TestHexToFelt(t, "0x1"), Nonce: utils.Uint64ToFelt(3)}},
+		rpc.BlockInvokeTxnV1{TransactionHash: txA, InvokeTxnV1: rpc.InvokeTxnV1{SenderAddress: contract, Nonce: utils.Uint64ToFelt(3)}},
+	}}}, nil)
+	provider.EXPECT().BlockWithTxs(gomock.Any(), rpc.WithBlockNumber(13)).Return(&rpc.BlockResult{Block: &rpc.Block{Transactions: rpc.BlockTransactions{
+		rpc.BlockDeclareTxnV2{TransactionHash: txB, DeclareTxnV2: rpc.DeclareTxnV2{SenderAddress: contract, Nonce: utils.Uint64ToFelt(4)}},
+	}}}, nil)
+
+	changes, err = NonceHistory(context.Background(), provider, contract, 10, 13)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, Change{BlockNumber: 10, BlockHash: utils.Uint64ToFelt(100), Previous: utils.Uint64ToFelt(3), Value: utils.Uint64ToFelt(4), TransactionHash: txA}, changes[0])
+	require.Equal(t, txB, changes[1].TransactionHash)
+
+	_, err = NonceHistory(context.Background(), provider, contract, 13, 10)
+	require.Equal(t, ErrInvalidRange, err)
+}
+
+// TestSenderNonce tests that the sender and the nonce of every versioned
+// transaction type are found, the sender of a deploy account transaction
+// being the deployed account.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestSenderNonce(t *testing.T) {
+	sender, nonce := utils.TestHexToFelt(t, "0xacc"), utils.Uint64ToFelt(7)
+	classHash, salt := utils.TestHexToFelt(t, "0xc1a55"), utils.TestHexToFelt(t, "0x5a17")
+	deployed, err := contractAddress(new(felt.Felt), salt, classHash, nil)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		tx     rpc.BlockTransaction
+		sender *felt.Felt
+	}{
+		{rpc.BlockInvokeTxnV1{InvokeTxnV1: rpc.InvokeTxnV1{SenderAddress: sender, Nonce: nonce}}, sender},
+		{rpc.BlockInvokeTxnV3{InvokeTxnV3: rpc.InvokeTxnV3{SenderAddress: sender, Nonce: nonce}}, sender},
+		{rpc.BlockDeclareTxnV1{DeclareTxnV1: rpc.DeclareTxnV1{SenderAddress: sender, Nonce: nonce}}, sender},
+		{rpc.BlockDeclareTxnV2{DeclareTxnV2: rpc.DeclareTxnV2{SenderAddress: sender, Nonce: nonce}}, sender},
+		{rpc.BlockDeclareTxnV3{DeclareTxnV3: rpc.DeclareTxnV3{SenderAddress: sender, Nonce: nonce}}, sender},
+		{rpc.BlockDeployAccountTxn{DeployAccountTxn: rpc.DeployAccountTxn{Nonce: nonce, ClassHash: classHash, ContractAddressSalt: salt}}, deployed},
+		{rpc.BlockDeployAccountTxnV3{DeployAccountTxnV3: rpc.DeployAccountTxnV3{Nonce: nonce, ClassHash: classHash, ContractAddressSalt: salt}}, deployed},
+	} {
+		gotSender, gotNonce := senderNonce(tc.tx)
+		require.Equal(t, tc.sender, gotSender)
+		require.Equal(t, nonce, gotNonce)
+	}
+	gotSender, _ := senderNonce(rpc.BlockL1HandlerTxn{})
+	require.Nil(t, gotSender)
+}
+
+// TestFindDeployment tests the lookup of deployments through a Universal
+// Deployer event and through a deploy account transaction.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestFindDeployment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+
+	classHash, salt := utils.TestHexToFelt(t, "0xc1a55"), utils.TestHexToFelt(t, "0x5a17")
+	calldata := []*felt.Felt{utils.TestHexToFelt(t, "0x1234")}
+	account, err := contractAddress(new(felt.Felt), salt, classHash, calldata)
+	require.NoError(t, err)
+	udcDeployed, deployer := utils.TestHexToFelt(t, "0xc0de"), utils.TestHexToFelt(t, "0xde91")
+	deployedAt := map[felt.Felt]uint64{*udcDeployed: 37, *account: 5}
+
+	provider.EXPECT().BlockNumber(gomock.Any()).Return(uint64(100), nil).AnyTimes()
+	provider.EXPECT().ClassHashAt(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, blockID rpc.BlockID, address *felt.Felt) (*felt.Felt, error) {
+			if at, ok := deployedAt[*address]; ok && *blockID.Number >= at {
+				return classHash, nil
+			}
+			return nil, rpc.ErrContractNotFound
+		}).AnyTimes()
+	provider.EXPECT().StateUpdate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, blockID rpc.BlockID) (*rpc.StateUpdateOutput, error) {
+		update := &rpc.StateUpdateOutput{BlockHash: utils.Uint64ToFelt(1000 + *blockID.Number)}
+		for address, at := range deployedAt {
+			if at == *blockID.Number {
+				address := address
+				update.StateDiff.DeployedContracts = []rpc.DeployedContractItem{{Address: &address, ClassHash: classHash}}
+			}
+		}
+		return update, nil
+	}).Times(2)
+	udcTx, accountTx := utils.TestHexToFelt(t, "0xa"), utils.TestHexToFelt(t, "0xb")
+	provider.EXPECT().Events(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input rpc.EventsInput) (*rpc.EventChunk, error) {
+		if *input.FromBlock.Number == 37 && input.Address.Equal(UniversalDeployers[0]) {
+			return &rpc.EventChunk{Events: []rpc.EmittedEvent{{TransactionHash: udcTx, Event: rpc.Event{Data: []*felt.Felt{udcDeployed, deployer}}}}}, nil
+		}
+		return &rpc.EventChunk{}, nil
+	}).Times(3)
+	provider.EXPECT().BlockWithTxs(gomock.Any(), rpc.WithBlockNumber(5)).Return(&rpc.BlockResult{Block: &rpc.Block{Transactions: rpc.BlockTransactions{
+		rpc.BlockDeployAccountTxn{TransactionHash: accountTx, DeployAccountTxn: rpc.DeployAccountTxn{
+			ClassHash: classHash, ContractAddressSalt: salt, ConstructorCalldata: calldata}},
+	}}}, nil)
+
+	deployment, err := FindDeployment(context.Background(), provider, udcDeployed)
+	require.NoError(t, err)
+	require.Equal(t, &Deployment{BlockNumber: 37, BlockHash: utils.Uint64ToFelt(1037), ClassHash: classHash,
+		TransactionHash: udcTx, Deployer: deployer}, deployment)
+
+	deployment, err = FindDeployment(context.Background(), provider, account)
+	require.NoError(t, err)
+	require.Equal(t, &Deployment{BlockNumber: 5, BlockHash: utils.Uint64ToFelt(1005), ClassHash: classHash,
+		TransactionHash: accountTx, Deployer: new(felt.Felt)}, deployment)
+
+	_, err = FindDeployment(context.Background(), provider, utils.TestHexToFelt(t, "0xdead"))
+	require.True(t, errors.Is(err, ErrNotDeployed))
+}
@@ -0,0 +1,476 @@
+// Package indexer runs handlers on the events of the blocks of a chain, in
+// order, and keeps their position in a Store so that a restarted indexer
+// resumes where it stopped.
+//
+// Blocks are read one at a time with their receipts, the handlers of their
+// events being called in the order of the block. The cursor is saved after
+// each block. When the parent of a block is not the last block processed, the
+// chain was reorganized: the rollback handlers are called with the first
+// block to undo, and the indexer resumes from the last block still on the
+// chain. Failing blocks are retried, so that handlers may be called more than
+// once for an event and must be idempotent. A panicking handler fails its
+// block as an error does, and is reported to the ErrorHandler of the indexer.
+//
+// The cursors are kept by a Store: MemoryStore and FileStore are provided,
+// database-backed stores are implemented by applications, e.g. to save the
+// cursor in the transaction of the data of the handlers.
+//
+//	ix := indexer.New("transfers", provider, store)
+//	ix.HandleEvent(token, "Transfer", func(ctx context.Context, event rpc.EmittedEvent) error {
+//		return db.InsertTransfer(ctx, event)
+//	})
+//	ix.OnRollback(func(ctx context.Context, fromBlock uint64) error {
+//		return db.DeleteTransfersFrom(ctx, fromBlock)
+//	})
+//	err := ix.Run(ctx)
+//
+// An indexer is also a lifecycle.Lifecycle component, run with Start and
+// stopped with Stop, which waits for the block in flight and saves its cursor.
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/lifecycle"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+const (
+	// DefaultPollInterval is the interval new blocks are looked for at.
+	DefaultPollInterval = 2 * time.Second
+	// DefaultRetries is the number of retries of a failing block.
+	DefaultRetries = 5
+	// DefaultRetryDelay is the delay before the first retry, doubled on each retry.
+	DefaultRetryDelay = time.Second
+	// DefaultReorgDepth is the number of blocks kept to find the last block
+	// still on the chain after a reorganization.
+	DefaultReorgDepth = 64
+)
+
+var (
+	ErrReorgTooDeep = errors.New("indexer: reorganization deeper than the blocks kept")
+	ErrPendingBlock = errors.New("indexer: block not accepted yet")
+)
+
+// Handler handles an event of a block.
+type Handler func(ctx context.Context, event rpc.EmittedEvent) error
+
+// RollbackHandler undoes the effects of the blocks from a block on, left
+// out of the chain by a reorganization.
+type RollbackHandler func(ctx context.Context, fromBlock uint64) error
+
+// registration is a handler of the events of a contract and of a selector.
+type registration struct {
+	contract *felt.Felt
+	selector *felt.Felt
+	handler  Handler
+}
+
+// Indexer runs handlers on the events of the blocks of a chain.
+type Indexer struct {
+	lifecycle.Base
+	name         string
+	provider     rpc.RpcProvider
+	store        Store
+	handlers     []registration
+	rollbacks    []RollbackHandler
+	startBlock   uint64
+	pollInterval time.Duration
+	retries      int
+	retryDelay   time.Duration
+	reorgDepth   int
+	onError      lifecycle.ErrorHandler
+
+	// stopping is closed when the indexer must not start another block, nil
+	// when it runs until its context is done
+	stopping <-chan struct{}
+	// err is the error stopping an indexer started with Start
+	err error
+	// recent are the last blocks processed, oldest first
+	recent []Cursor
+}
+
+// Option configures an Indexer.
+type Option func(*Indexer)
+
+// WithStartBlock sets the first block of an indexer without cursor, 0 by default.
+//
+// Parameters:
+// - blockNumber: the first block
+// Returns:
+// - Option: the option
+func WithStartBlock(blockNumber uint64) Option {
+	return func(ix *Indexer) {
+		ix.startBlock = blockNumber
+	}
+}
+
+// WithPollInterval sets the interval new blocks are looked for at,
+// DefaultPollInterval by default.
+//
+// Parameters:
+// - interval: the poll interval, ignored if not positive
+// Returns:
+// - Option: the option
+func WithPollInterval(interval time.Duration) Option {
+	return func(ix *Indexer) {
+		if interval > 0 {
+			ix.pollInterval = interval
+		}
+	}
+}
+
+// WithRetries sets the number of retries of a failing block and the delay
+// before the first one, doubled on each retry, DefaultRetries and
+// DefaultRetryDelay by default.
+//
+// Parameters:
+// - retries: the number of retries, 0 to fail on the first error
+// - delay: the delay before the first retry
+// Returns:
+// - Option: the option
+func WithRetries(retries int, delay time.Duration) Option {
+	return func(ix *Indexer) {
+		ix.retries = retries
+		ix.retryDelay = delay
+	}
+}
+
+// WithReorgDepth sets the number of blocks kept to find the last block still
+// on the chain after a reorganization, DefaultReorgDepth by default.
+//
+// Parameters:
+// - depth: the number of blocks, ignored if not positive
+// Returns:
+// - Option: the option
+func WithReorgDepth(depth int) Option {
+	return func(ix *Indexer) {
+		if depth > 0 {
+			ix.reorgDepth = depth
+		}
+	}
+}
+
+// WithErrorHandler sets the handler notified of the errors and recovered
+// panics of the handlers and the rollback handlers, on every attempt of their
+// block.
+//
+// Parameters:
+// - onError: the error handler
+// Returns:
+// - Option: the option
+func WithErrorHandler(onError lifecycle.ErrorHandler) Option {
+	return func(ix *Indexer) {
+		ix.onError = onError
+	}
+}
+
+// New returns an indexer saving its cursor in a store under its name.
+//
+// Parameters:
+// - name: the name of the indexer in the store
+// - provider: the provider reading the blocks
+// - store: the store of the cursor
+// - opts: the options
+// Returns:
+// - *Indexer: the indexer, without handlers
+func New(name string, provider rpc.RpcProvider, store Store, opts ...Option) *Indexer {
+	ix := &Indexer{
+		name:         name,
+		provider:     provider,
+		store:        store,
+		pollInterval: DefaultPollInterval,
+		retries:      DefaultRetries,
+		retryDelay:   DefaultRetryDelay,
+		reorgDepth:   DefaultReorgDepth,
+	}
+	for _, opt := range opts {
+		opt(ix)
+	}
+	ix.SetErrorHandler(ix.onError)
+	return ix
+}
+
+// Handle registers a handler of the events of a contract whose first key is
+// a selector. Handlers are called in the order of registration for an event.
+// It must not be called while the indexer runs.
+//
+// Parameters:
+// - contract: the contract emitting the events, any contract if nil
+// - selector: the selector of the events, any event if nil
+// - handler: the handler
+// Returns:
+//
+//	none
+func (ix *Indexer) Handle(contract, selector *felt.Felt, handler Handler) {
+	ix.handlers = append(ix.handlers, registration{contract: contract, selector: selector, handler: handler})
+}
+
+// HandleEvent registers a handler of the events of a contract by name, e.g.
+// "Transfer", as Handle does with the selector of the name.
+//
+// Parameters:
+// - contract: the contract emitting the events, any contract if nil
+// - event: the name of the events
+// - handler: the handler
+// Returns:
+//
+//	none
+func (ix *Indexer) HandleEvent(contract *felt.Felt, event string, handler Handler) {
+	ix.Handle(contract, utils.GetSelectorFromNameFelt(event), handler)
+}
+
+// OnRollback registers a handler of the reorganizations. It must not be
+// called while the indexer runs.
+//
+// Parameters:
+// - handler: the handler
+// Returns:
+//
+//	none
+func (ix *Indexer) OnRollback(handler RollbackHandler) {
+	ix.rollbacks = append(ix.rollbacks, handler)
+}
+
+// Run processes the blocks from the cursor of the store, or from the start
+// block, then follows the chain until ctx is done. The cursor of a block
+// whose handlers succeeded is saved even if ctx is done meanwhile, and no
+// block is started after, so that a stopped indexer resumes after the last
+// block it completed. It must not be called on an indexer started with Start.
+//
+// Parameters:
+// - ctx: the context, stopping the indexer
+// Returns:
+// - error: nil when ctx is done, or the error of a block failing after the retries, or of the store
+func (ix *Indexer) Run(ctx context.Context) error {
+	return ix.run(ctx, nil)
+}
+
+// Start runs the indexer in the background until Stop.
+//
+// Parameters:
+// - ctx: the context, unused
+// Returns:
+// - error: lifecycle.ErrAlreadyStarted or lifecycle.ErrStopped
+func (ix *Indexer) Start(ctx context.Context) error {
+	if _, err := ix.Begin(); err != nil {
+		return err
+	}
+	stopping := ix.Stopping()
+	ix.Go(func(ctx context.Context) {
+		ix.err = ix.run(ctx, stopping)
+	})
+	return nil
+}
+
+// Stop stops the indexer once the block in flight is processed and its
+// cursor saved. The handlers of the block are cancelled if ctx is done
+// first.
+//
+// Parameters:
+// - ctx: the context, bounding the wait of the block in flight
+// Returns:
+// - error: the error of a block failing after the retries, or of the store, stopping the indexer before, or the error of the context
+func (ix *Indexer) Stop(ctx context.Context) error {
+	err := ix.Shutdown(ctx, nil)
+	if errors.Is(err, lifecycle.ErrNotStarted) || errors.Is(err, lifecycle.ErrStopped) {
+		return err
+	}
+	return errors.Join(ix.err, err)
+}
+
+// run processes the blocks as Run does, until ctx is done or stopping is
+// closed.
+//
+// Parameters:
+// - ctx: the context, stopping the indexer
+// - stopping: the channel stopping the indexer, nil to run until ctx is done
+// Returns:
+// - error: nil when stopped, or the error of a block failing after the retries, or of the store
+func (ix *Indexer) run(ctx context.Context, stopping <-chan struct{}) error {
+	ix.stopping = stopping
+	cursor, err := ix.store.Load(ctx, ix.name)
+	if err != nil {
+		return err
+	}
+	next := ix.startBlock
+	ix.recent = nil
+	if cursor != nil {
+		next = cursor.BlockNumber + 1
+		ix.recent = []Cursor{*cursor}
+	}
+
+	t := time.NewTicker(ix.pollInterval)
+	defer t.Stop()
+	for {
+		var latest uint64
+		err := ix.retry(ctx, func() (err error) {
+			latest, err = ix.provider.BlockNumber(ctx)
+			return err
+		})
+		for err == nil && next <= latest && !ix.stopped(ctx) {
+			err = ix.retry(ctx, func() (err error) {
+				next, err = ix.process(ctx, next)
+				return err
+			})
+		}
+		if ix.stopped(ctx) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-stopping:
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// stopped reports whether the indexer must not start another block.
+//
+// Parameters:
+// - ctx: the context of the indexer
+// Returns:
+// - bool: true if ctx is done or the indexer is stopping
+func (ix *Indexer) stopped(ctx context.Context) bool {
+	select {
+	case <-ix.stopping:
+		return true
+	default:
+		return ctx.Err() != nil
+	}
+}
+
+// retry calls a function until it succeeds, it fails with ErrReorgTooDeep,
+// the retries are exhausted, ctx is done or the indexer is stopping.
+//
+// Parameters:
+// - ctx: the context
+// - f: the function
+// Returns:
+// - error: the last error of the function, nil if it succeeded
+func (ix *Indexer) retry(ctx context.Context, f func() error) error {
+	delay := ix.retryDelay
+	for attempt := 0; ; attempt++ {
+		err := f()
+		if err == nil || attempt == ix.retries || errors.Is(err, ErrReorgTooDeep) || ix.stopped(ctx) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-ix.stopping:
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// process calls the handlers of the events of a block and saves it as the
+// cursor, or rolls back a reorganization detected from its parent.
+//
+// Parameters:
+// - ctx: the context
+// - number: the number of the block
+// Returns:
+// - uint64: the number of the next block to process
+// - error: an error of the provider, of a handler or of the store
+func (ix *Indexer) process(ctx context.Context, number uint64) (uint64, error) {
+	result, err := ix.provider.BlockWithReceipts(ctx, rpc.WithBlockNumber(number))
+	if err != nil {
+		return number, err
+	}
+	block, ok := result.AsBlock()
+	if !ok {
+		return number, fmt.Errorf("%w: %d", ErrPendingBlock, number)
+	}
+	if len(ix.recent) > 0 {
+		last := ix.recent[len(ix.recent)-1]
+		if last.BlockNumber+1 == number && !last.BlockHash.Equal(block.ParentHash) {
+			return ix.rollback(ctx, number)
+		}
+	}
+
+	for _, tx := range block.Transactions {
+		for _, event := range tx.Receipt.Events() {
+			emitted := rpc.EmittedEvent{Event: event, BlockHash: block.BlockHash, BlockNumber: number, TransactionHash: tx.Receipt.Hash()}
+			for _, r := range ix.handlers {
+				if !r.matches(event) {
+					continue
+				}
+				if err := lifecycle.SafeCall(ix.onError, func() error { return r.handler(ctx, emitted) }); err != nil {
+					return number, fmt.Errorf("indexer: block %d: transaction %s: %w", number, emitted.TransactionHash, err)
+				}
+			}
+		}
+	}
+
+	cursor := Cursor{BlockNumber: number, BlockHash: block.BlockHash}
+	if err := ix.store.Save(context.WithoutCancel(ctx), ix.name, cursor); err != nil {
+		return number, err
+	}
+	ix.recent = append(ix.recent, cursor)
+	if len(ix.recent) > ix.reorgDepth {
+		ix.recent = ix.recent[len(ix.recent)-ix.reorgDepth:]
+	}
+	return number + 1, nil
+}
+
+// rollback finds the last block processed still on the chain, calls the
+// rollback handlers with the block after it, and saves it as the cursor.
+//
+// Parameters:
+// - ctx: the context
+// - number: the number of the block whose parent is not the last block processed
+// Returns:
+// - uint64: the number of the next block to process, number on errors
+// - error: ErrReorgTooDeep if no block kept is on the chain, or an error of the provider, of a handler or of the store
+func (ix *Indexer) rollback(ctx context.Context, number uint64) (uint64, error) {
+	for i := len(ix.recent) - 1; i >= 0; i-- {
+		kept := ix.recent[i]
+		result, err := ix.provider.BlockWithTxHashes(ctx, rpc.WithBlockNumber(kept.BlockNumber))
+		if err != nil {
+			return number, err
+		}
+		if block, ok := result.AsBlock(); !ok || !block.BlockHash.Equal(kept.BlockHash) {
+			continue
+		}
+		for _, handler := range ix.rollbacks {
+			if err := lifecycle.SafeCall(ix.onError, func() error { return handler(ctx, kept.BlockNumber+1) }); err != nil {
+				return number, fmt.Errorf("indexer: rollback from block %d: %w", kept.BlockNumber+1, err)
+			}
+		}
+		if err := ix.store.Save(context.WithoutCancel(ctx), ix.name, kept); err != nil {
+			return number, err
+		}
+		ix.recent = ix.recent[:i+1]
+		return kept.BlockNumber + 1, nil
+	}
+	return number, ErrReorgTooDeep
+}
+
+// matches reports whether an event is handled by a registration.
+//
+// Parameters:
+// - event: the event
+// Returns:
+// - bool: true if the contract and the selector of the event match
+func (r registration) matches(event rpc.Event) bool {
+	if r.contract != nil && !r.contract.Equal(event.FromAddress) {
+		return false
+	}
+	if r.selector != nil && (len(event.Keys) == 0 || !r.selector.Equal(event.Keys[0])) {
+		return false
+	}
+	return true
+}
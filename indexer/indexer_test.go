@@ -0,0 +1,277 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/lifecycle"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// chain is a fake chain answering the block reads of a mock provider.
+type chain struct {
+	mu     sync.Mutex
+	blocks []*rpc.BlockWithReceipts
+}
+
+// block returns a block of events, child of a block.
+//
+// Parameters:
+// - parent: the parent block, nil for the genesis block
+// - hash: the hash of the block
+// - events: the events, one transaction each
+// Returns:
+// - *rpc.BlockWithReceipts: the block
+func block(parent *rpc.BlockWithReceipts, hash uint64, events ...rpc.Event) *rpc.BlockWithReceipts {
+	b := &rpc.BlockWithReceipts{BlockHeader: rpc.BlockHeader{BlockHash: new(felt.Felt).SetUint64(hash), ParentHash: &felt.Zero}}
+	if parent != nil {
+		b.BlockNumber, b.ParentHash = parent.BlockNumber+1, parent.BlockHash
+	}
+	for i, event := range events {
+		b.Transactions = append(b.Transactions, rpc.TransactionWithReceipt{Receipt: rpc.Receipt{TransactionReceipt: rpc.InvokeTransactionReceipt{
+			TransactionHash: new(felt.Felt).SetUint64(hash<<8 + uint64(i)),
+			Events:          []rpc.Event{event},
+		}}})
+	}
+	return b
+}
+
+// set replaces the blocks of the chain.
+//
+// Parameters:
+// - blocks: the blocks
+// Returns:
+//
+//	none
+func (c *chain) set(blocks ...*rpc.BlockWithReceipts) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blocks = blocks
+}
+
+// provider returns a mock provider reading the blocks of the chain.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+// - *mocks.MockRpcProvider: the provider
+func (c *chain) provider(t *testing.T) *mocks.MockRpcProvider {
+	provider := mocks.NewMockRpcProvider(gomock.NewController(t))
+	provider.EXPECT().BlockNumber(gomock.Any()).AnyTimes().DoAndReturn(func(context.Context) (uint64, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return uint64(len(c.blocks) - 1), nil
+	})
+	provider.EXPECT().BlockWithReceipts(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(_ context.Context, id rpc.BlockID) (*rpc.BlockWithReceiptsResult, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if *id.Number >= uint64(len(c.blocks)) {
+			return nil, rpc.ErrBlockNotFound
+		}
+		return &rpc.BlockWithReceiptsResult{Block: c.blocks[*id.Number]}, nil
+	})
+	provider.EXPECT().BlockWithTxHashes(gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(_ context.Context, id rpc.BlockID) (*rpc.BlockTxHashesResult, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if *id.Number >= uint64(len(c.blocks)) {
+			return nil, rpc.ErrBlockNotFound
+		}
+		return &rpc.BlockTxHashesResult{Block: &rpc.BlockTxHashes{BlockHeader: c.blocks[*id.Number].BlockHeader}}, nil
+	})
+	return provider
+}
+
+// waitCursor waits for the cursor of an indexer to be a block.
+//
+// Parameters:
+// - t: the testing object
+// - store: the store of the cursor
+// - hash: the hash of the block
+// Returns:
+//
+//	none
+func waitCursor(t *testing.T, store Store, hash uint64) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		cursor, err := store.Load(context.Background(), "test")
+		if err == nil && cursor != nil && cursor.BlockHash.Equal(new(felt.Felt).SetUint64(hash)) {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("the cursor did not reach block %#x", hash)
+}
+
+// TestIndexer tests that the handlers are called in order for the events they
+// registered, with retries, that a reorganization is rolled back, and that a
+// restarted indexer resumes from its cursor.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestIndexer(t *testing.T) {
+	token, other := new(felt.Felt).SetUint64(0x70), new(felt.Felt).SetUint64(0x71)
+	transfer := func(from *felt.Felt, amount uint64) rpc.Event {
+		return rpc.Event{FromAddress: from, Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Transfer")}, Data: []*felt.Felt{new(felt.Felt).SetUint64(amount)}}
+	}
+	approval := rpc.Event{FromAddress: token, Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Approval")}}
+	b0 := block(nil, 0x100)
+	b1 := block(b0, 0x101, transfer(token, 1), transfer(other, 99), transfer(token, 2))
+	b2 := block(b1, 0x102, approval)
+	b3 := block(b2, 0x103, transfer(token, 3))
+	c := &chain{}
+	c.set(b0, b1, b2, b3)
+	store := NewMemoryStore()
+
+	var mu sync.Mutex
+	var amounts []uint64
+	var rollbacks []uint64
+	failures := 1
+	newIndexer := func() *Indexer {
+		ix := New("test", c.provider(t), store, WithStartBlock(1), WithPollInterval(time.Millisecond), WithRetries(2, time.Millisecond), WithReorgDepth(2))
+		ix.HandleEvent(token, "Transfer", func(ctx context.Context, event rpc.EmittedEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if failures > 0 {
+				failures--
+				return errors.New("database busy")
+			}
+			amounts = append(amounts, event.Data[0].Uint64())
+			return nil
+		})
+		ix.OnRollback(func(ctx context.Context, fromBlock uint64) error {
+			mu.Lock()
+			defer mu.Unlock()
+			rollbacks = append(rollbacks, fromBlock)
+			amounts = amounts[:len(amounts)-1]
+			return nil
+		})
+		return ix
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- newIndexer().Run(ctx) }()
+	waitCursor(t, store, 0x103)
+	b3fork := block(b2, 0x203, transfer(token, 4))
+	b4fork := block(b3fork, 0x204, transfer(token, 5))
+	c.set(b0, b1, b2, b3fork, b4fork)
+	waitCursor(t, store, 0x204)
+	cancel()
+	require.NoError(t, <-done)
+
+	mu.Lock()
+	require.Equal(t, []uint64{1, 2, 4, 5}, amounts)
+	require.Equal(t, []uint64{3}, rollbacks)
+	mu.Unlock()
+
+	// restarted, the indexer resumes after its cursor
+	c.set(b0, b1, b2, b3fork, b4fork, block(b4fork, 0x205, transfer(token, 6)))
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() { done <- newIndexer().Run(ctx) }()
+	waitCursor(t, store, 0x205)
+	cancel()
+	require.NoError(t, <-done)
+	mu.Lock()
+	require.Equal(t, []uint64{1, 2, 4, 5, 6}, amounts)
+	mu.Unlock()
+
+	// a fork below the blocks kept after a restart can not be rolled back
+	b4 := block(b3, 0x304)
+	b5 := block(b4, 0x305)
+	c.set(b0, b1, b2, b3, b4, b5, block(b5, 0x306))
+	err := newIndexer().Run(context.Background())
+	require.True(t, errors.Is(err, ErrReorgTooDeep))
+}
+
+// TestIndexer_Lifecycle tests that an indexer started with Start recovers
+// the panics of its handlers, reported to its error handler, and retries
+// their block, and that Stop stops it.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestIndexer_Lifecycle(t *testing.T) {
+	token := new(felt.Felt).SetUint64(0x70)
+	transfer := rpc.Event{FromAddress: token, Keys: []*felt.Felt{utils.GetSelectorFromNameFelt("Transfer")}, Data: []*felt.Felt{new(felt.Felt).SetUint64(1)}}
+	b0 := block(nil, 0x100)
+	c := &chain{}
+	c.set(b0, block(b0, 0x101, transfer))
+	store := NewMemoryStore()
+
+	var mu sync.Mutex
+	var reported []error
+	calls := 0
+	ix := New("test", c.provider(t), store, WithStartBlock(1), WithPollInterval(time.Millisecond), WithRetries(2, time.Millisecond),
+		WithErrorHandler(func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, err)
+		}))
+	ix.HandleEvent(token, "Transfer", func(ctx context.Context, event rpc.EmittedEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			panic("bad handler")
+		}
+		return nil
+	})
+
+	require.True(t, errors.Is(ix.Stop(context.Background()), lifecycle.ErrNotStarted))
+	require.NoError(t, ix.Start(context.Background()))
+	waitCursor(t, store, 0x101)
+	require.NoError(t, ix.Stop(context.Background()))
+	require.True(t, errors.Is(ix.Start(context.Background()), lifecycle.ErrStopped))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, 2, calls)
+	require.Len(t, reported, 1)
+	var panicErr *lifecycle.PanicError
+	require.True(t, errors.As(reported[0], &panicErr))
+	require.Equal(t, "bad handler", panicErr.Value)
+}
+
+// TestFileStore tests that the cursors are saved to and loaded from files.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	cursor, err := store.Load(ctx, "transfers")
+	require.NoError(t, err)
+	require.Nil(t, cursor)
+
+	for _, number := range []uint64{7, 8} {
+		saved := Cursor{BlockNumber: number, BlockHash: new(felt.Felt).SetUint64(number + 0x100)}
+		require.NoError(t, store.Save(ctx, "transfers", saved))
+		cursor, err = store.Load(ctx, "transfers")
+		require.NoError(t, err)
+		require.Equal(t, saved, *cursor)
+	}
+
+	for _, name := range []string{"", "..", "a/b"} {
+		require.True(t, errors.Is(store.Save(ctx, name, Cursor{}), ErrInvalidName))
+		_, err = store.Load(ctx, name)
+		require.True(t, errors.Is(err, ErrInvalidName))
+	}
+}
@@ -0,0 +1,183 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var ErrInvalidName = errors.New("indexer: invalid name")
+
+// Cursor is the position of an indexer: the last block it processed.
+type Cursor struct {
+	BlockNumber uint64     `json:"block_number"`
+	BlockHash   *felt.Felt `json:"block_hash"`
+}
+
+// Store persists the cursors of indexers, by name. The package provides
+// MemoryStore and FileStore; a store implemented by an application on its
+// database saves the cursor in the transaction of the data of the handlers
+// when they share it, so that the data and the cursor are never out of step.
+type Store interface {
+	// Load returns the cursor of an indexer, nil if it never saved one.
+	Load(ctx context.Context, name string) (*Cursor, error)
+	// Save replaces the cursor of an indexer.
+	Save(ctx context.Context, name string, cursor Cursor) error
+}
+
+// MemoryStore is a Store keeping the cursors in memory, for tests and
+// indexers rebuilding their data on every start.
+type MemoryStore struct {
+	mu      sync.Mutex
+	cursors map[string]Cursor
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *MemoryStore: the store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{cursors: map[string]Cursor{}}
+}
+
+// Load returns the cursor of an indexer.
+//
+// Parameters:
+// - ctx: the context
+// - name: the name of the indexer
+// Returns:
+// - *Cursor: the cursor, nil if none was saved
+// - error: always nil
+func (s *MemoryStore) Load(ctx context.Context, name string) (*Cursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cursor, ok := s.cursors[name]
+	if !ok {
+		return nil, nil
+	}
+	return &cursor, nil
+}
+
+// Save replaces the cursor of an indexer.
+//
+// Parameters:
+// - ctx: the context
+// - name: the name of the indexer
+// - cursor: the cursor
+// Returns:
+// - error: always nil
+func (s *MemoryStore) Save(ctx context.Context, name string, cursor Cursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursors[name] = cursor
+	return nil
+}
+
+// FileStore is a Store keeping each cursor in a JSON file of a directory,
+// named after the indexer. Files are replaced atomically.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore in a directory, created if missing.
+//
+// Parameters:
+// - dir: the directory
+// Returns:
+// - *FileStore: the store
+// - error: an error if the directory can not be created
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Load returns the cursor of an indexer.
+//
+// Parameters:
+// - ctx: the context
+// - name: the name of the indexer
+// Returns:
+// - *Cursor: the cursor, nil if none was saved
+// - error: ErrInvalidName, or an error if the file can not be read
+func (s *FileStore) Load(ctx context.Context, name string) (*Cursor, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("indexer: cursor %s: %w", path, err)
+	}
+	return &cursor, nil
+}
+
+// Save replaces the cursor of an indexer, writing a temporary file renamed
+// over the previous one.
+//
+// Parameters:
+// - ctx: the context
+// - name: the name of the indexer
+// - cursor: the cursor
+// Returns:
+// - error: ErrInvalidName, or an error if the file can not be written
+func (s *FileStore) Save(ctx context.Context, name string, cursor Cursor) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// path returns the file of a cursor.
+//
+// Parameters:
+// - name: the name of the indexer
+// Returns:
+// - string: the path of the file
+// - error: ErrInvalidName if the name is empty or not a file name
+func (s *FileStore) path(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return filepath.Join(s.dir, name+".json"), nil
+}
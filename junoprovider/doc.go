@@ -0,0 +1,15 @@
+// Package junoprovider reads the database of a Juno node embedded in the
+// process as an rpc.Provider, for analytics jobs colocated with a node: the
+// reads of blocks, nonces, class hashes and storage are served by the
+// blockchain.Reader of the node, without JSON-RPC, and the other methods by
+// an optional fallback client, e.g. the JSON-RPC endpoint of the node.
+//
+// Juno links its Rust libraries, so the package is built with the juno build
+// tag, once the libraries of the Juno module are built:
+//
+//	go build -tags juno ./...
+//
+//	database, _ := pebble.New(path, 1024, 128, nil)
+//	reader := blockchain.New(database, &utils.Mainnet)
+//	provider := junoprovider.New(reader, junoprovider.WithFallback(rpc.NewHTTPClient(url)))
+package junoprovider
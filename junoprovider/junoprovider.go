@@ -0,0 +1,295 @@
+//go:build juno
+
+package junoprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var ErrMethodNotServed = errors.New("junoprovider: method not served by the database")
+
+// Backend is an rpc.Backend serving the reads of blocks and state with the
+// blockchain.Reader of an embedded Juno node.
+type Backend struct {
+	reader   blockchain.Reader
+	fallback rpc.CallCloser
+}
+
+var _ rpc.Backend = &Backend{}
+
+// Option configures a Backend.
+type Option func(*Backend)
+
+// WithFallback sends the requests of the methods not served by the database,
+// e.g. calls and transactions, with client.
+//
+// Parameters:
+// - client: the fallback client
+// Returns:
+// - Option: the option
+func WithFallback(client rpc.CallCloser) Option {
+	return func(b *Backend) {
+		b.fallback = client
+	}
+}
+
+// NewBackend creates a backend reading the database of reader.
+//
+// Parameters:
+// - reader: the reader of the blockchain of the node
+// - opts: the options
+// Returns:
+// - *Backend: the backend
+func NewBackend(reader blockchain.Reader, opts ...Option) *Backend {
+	b := &Backend{reader: reader}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// New creates a provider reading the database of reader.
+//
+// Parameters:
+// - reader: the reader of the blockchain of the node
+// - opts: the options
+// Returns:
+// - *rpc.Provider: the provider
+func New(reader blockchain.Reader, opts ...Option) *rpc.Provider {
+	b := NewBackend(reader, opts...)
+	return rpc.NewBackendProvider(b, rpc.WithChainID(reader.Network().L2ChainID))
+}
+
+// Serve serves starknet_blockNumber, starknet_blockHashAndNumber,
+// starknet_chainId, starknet_getBlockWithTxHashes,
+// starknet_getBlockTransactionCount, starknet_getNonce,
+// starknet_getClassHashAt and starknet_getStorageAt with the database, and
+// the other methods with the fallback client.
+//
+// Parameters:
+// - ctx: the context
+// - method: the method
+// - params: the parameters
+// Returns:
+// - interface{}: the result
+// - error: an *rpc.RPCError such as rpc.ErrBlockNotFound, ErrMethodNotServed
+// without fallback, or an error of the database
+func (b *Backend) Serve(ctx context.Context, method string, params []interface{}) (interface{}, error) {
+	switch method {
+	case "starknet_blockNumber":
+		height, err := b.reader.Height()
+		return height, blockErr(err, rpc.ErrNoBlocks)
+	case "starknet_blockHashAndNumber":
+		header, err := b.reader.HeadsHeader()
+		if err != nil {
+			return nil, blockErr(err, rpc.ErrNoBlocks)
+		}
+		return &rpc.BlockHashAndNumberOutput{BlockNumber: header.Number, BlockHash: header.Hash}, nil
+	case "starknet_chainId":
+		return b.reader.Network().L2ChainIDFelt().String(), nil
+	case "starknet_getBlockWithTxHashes", "starknet_getBlockTransactionCount":
+		blockID, ok := param[rpc.BlockID](params, 0)
+		if !ok || blockID.Tag == "pending" {
+			break
+		}
+		block, err := b.block(blockID)
+		if err != nil {
+			return nil, err
+		}
+		if method == "starknet_getBlockTransactionCount" {
+			return uint64(len(block.Transactions)), nil
+		}
+		return b.blockTxHashes(block), nil
+	case "starknet_getNonce", "starknet_getClassHashAt":
+		blockID, okBlock := param[rpc.BlockID](params, 0)
+		address, okAddress := param[*felt.Felt](params, 1)
+		if !okBlock || !okAddress || blockID.Tag == "pending" {
+			break
+		}
+		return b.read(blockID, func(state core.StateReader) (*felt.Felt, error) {
+			if method == "starknet_getNonce" {
+				return state.ContractNonce(address)
+			}
+			return state.ContractClassHash(address)
+		})
+	case "starknet_getStorageAt":
+		address, okAddress := param[*felt.Felt](params, 0)
+		key, okKey := storageKey(params)
+		blockID, okBlock := param[rpc.BlockID](params, 2)
+		if !okAddress || !okKey || !okBlock || blockID.Tag == "pending" {
+			break
+		}
+		return b.read(blockID, func(state core.StateReader) (*felt.Felt, error) {
+			return state.ContractStorage(address, key)
+		})
+	}
+
+	if b.fallback == nil {
+		return nil, fmt.Errorf("%w: %s", ErrMethodNotServed, method)
+	}
+	var result interface{}
+	if err := b.fallback.CallContext(ctx, &result, method, params...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Close closes the fallback client.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (b *Backend) Close() {
+	if b.fallback != nil {
+		b.fallback.Close()
+	}
+}
+
+// block reads the block of a block ID.
+//
+// Parameters:
+// - blockID: the block ID, by number, hash or the latest tag
+// Returns:
+// - *core.Block: the block
+// - error: rpc.ErrBlockNotFound, or an error of the database
+func (b *Backend) block(blockID rpc.BlockID) (*core.Block, error) {
+	var block *core.Block
+	var err error
+	switch {
+	case blockID.Hash != nil:
+		block, err = b.reader.BlockByHash(blockID.Hash)
+	case blockID.Number != nil:
+		block, err = b.reader.BlockByNumber(*blockID.Number)
+	default:
+		block, err = b.reader.Head()
+	}
+	return block, blockErr(err, rpc.ErrBlockNotFound)
+}
+
+// blockTxHashes returns a block with the hashes of its transactions, its
+// status being ACCEPTED_ON_L1 once the L1 head of the node reaches it.
+//
+// Parameters:
+// - block: the block
+// Returns:
+// - *rpc.BlockTxHashes: the block
+func (b *Backend) blockTxHashes(block *core.Block) *rpc.BlockTxHashes {
+	status := rpc.BlockStatus_AcceptedOnL2
+	if l1Head, err := b.reader.L1Head(); err == nil && l1Head.BlockNumber >= block.Number {
+		status = rpc.BlockStatus_AcceptedOnL1
+	}
+	hashes := make([]*felt.Felt, len(block.Transactions))
+	for i, txn := range block.Transactions {
+		hashes[i] = txn.Hash()
+	}
+	return &rpc.BlockTxHashes{
+		BlockHeader: rpc.BlockHeader{
+			BlockHash:        block.Hash,
+			ParentHash:       block.ParentHash,
+			BlockNumber:      block.Number,
+			NewRoot:          block.GlobalStateRoot,
+			Timestamp:        block.Timestamp,
+			SequencerAddress: block.SequencerAddress,
+			L1GasPrice:       rpc.ResourcePrice{PriceInWei: block.GasPrice, PriceInFRI: block.GasPriceSTRK},
+			StarknetVersion:  block.ProtocolVersion,
+		},
+		Status:       status,
+		Transactions: hashes,
+	}
+}
+
+// read reads the state of a block ID.
+//
+// Parameters:
+// - blockID: the block ID, by number, hash or the latest tag
+// - read: the read of the state
+// Returns:
+// - *felt.Felt: the value read
+// - error: rpc.ErrBlockNotFound, rpc.ErrContractNotFound, or an error of the database
+func (b *Backend) read(blockID rpc.BlockID, read func(core.StateReader) (*felt.Felt, error)) (*felt.Felt, error) {
+	var state core.StateReader
+	var closer blockchain.StateCloser
+	var err error
+	switch {
+	case blockID.Hash != nil:
+		state, closer, err = b.reader.StateAtBlockHash(blockID.Hash)
+	case blockID.Number != nil:
+		state, closer, err = b.reader.StateAtBlockNumber(*blockID.Number)
+	default:
+		state, closer, err = b.reader.HeadState()
+	}
+	if err != nil {
+		return nil, blockErr(err, rpc.ErrBlockNotFound)
+	}
+	defer closer()
+
+	value, err := read(state)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, rpc.ErrContractNotFound
+	}
+	return value, err
+}
+
+// blockErr returns notFound for the not found errors of the database.
+//
+// Parameters:
+// - err: the error of the database
+// - notFound: the error of missing keys
+// Returns:
+// - error: notFound, err, or nil
+func blockErr(err error, notFound *rpc.RPCError) error {
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return notFound
+	}
+	return err
+}
+
+// param returns a parameter of a type.
+//
+// Parameters:
+// - params: the parameters
+// - i: the index of the parameter
+// Returns:
+// - T: the parameter
+// - bool: whether the parameter is of the type
+func param[T any](params []interface{}, i int) (T, bool) {
+	var zero T
+	if i >= len(params) {
+		return zero, false
+	}
+	value, ok := params[i].(T)
+	return value, ok
+}
+
+// storageKey returns the key of a starknet_getStorageAt request, a felt or
+// the hex string of Provider.StorageAt.
+//
+// Parameters:
+// - params: the parameters
+// Returns:
+// - *felt.Felt: the key
+// - bool: whether the key is valid
+func storageKey(params []interface{}) (*felt.Felt, bool) {
+	if key, ok := param[*felt.Felt](params, 1); ok {
+		return key, true
+	}
+	hex, ok := param[string](params, 1)
+	if !ok {
+		return nil, false
+	}
+	key, err := utils.HexToFelt(hex)
+	return key, err == nil
+}
@@ -0,0 +1,131 @@
+//go:build juno
+
+package junoprovider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// fakeReader is a blockchain.Reader of a chain of one block, the contract 1
+// having the nonce 3 and the value 99 at the key 7.
+type fakeReader struct {
+	blockchain.Reader
+	block *core.Block
+}
+
+func (r *fakeReader) Network() *utils.Network { return &utils.Sepolia }
+
+func (r *fakeReader) Height() (uint64, error) { return r.block.Number, nil }
+
+func (r *fakeReader) HeadsHeader() (*core.Header, error) { return r.block.Header, nil }
+
+func (r *fakeReader) Head() (*core.Block, error) { return r.block, nil }
+
+func (r *fakeReader) L1Head() (*core.L1Head, error) { return nil, db.ErrKeyNotFound }
+
+func (r *fakeReader) BlockByNumber(number uint64) (*core.Block, error) {
+	if number != r.block.Number {
+		return nil, db.ErrKeyNotFound
+	}
+	return r.block, nil
+}
+
+func (r *fakeReader) HeadState() (core.StateReader, blockchain.StateCloser, error) {
+	return fakeState{}, func() error { return nil }, nil
+}
+
+func (r *fakeReader) StateAtBlockNumber(number uint64) (core.StateReader, blockchain.StateCloser, error) {
+	if number != r.block.Number {
+		return nil, nil, db.ErrKeyNotFound
+	}
+	return r.HeadState()
+}
+
+// fakeState is the state of the fakeReader.
+type fakeState struct {
+	core.StateReader
+}
+
+func (fakeState) ContractNonce(addr *felt.Felt) (*felt.Felt, error) {
+	if !addr.Equal(new(felt.Felt).SetUint64(1)) {
+		return nil, db.ErrKeyNotFound
+	}
+	return new(felt.Felt).SetUint64(3), nil
+}
+
+func (fakeState) ContractStorage(addr, key *felt.Felt) (*felt.Felt, error) {
+	if key.Equal(new(felt.Felt).SetUint64(7)) {
+		return new(felt.Felt).SetUint64(99), nil
+	}
+	return new(felt.Felt), nil
+}
+
+// fallbackClient is a CallCloser answering starknet_specVersion.
+type fallbackClient struct{}
+
+func (fallbackClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return json.Unmarshal([]byte(`"0.7.1"`), result)
+}
+
+func (fallbackClient) Close() {}
+
+// TestProvider tests the reads served by the database, the errors of missing
+// blocks and contracts, and the methods sent to the fallback client.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestProvider(t *testing.T) {
+	block := &core.Block{
+		Header:       &core.Header{Hash: new(felt.Felt).SetUint64(0xb), ParentHash: new(felt.Felt), Number: 5},
+		Transactions: []core.Transaction{&core.InvokeTransaction{TransactionHash: new(felt.Felt).SetUint64(0xa)}},
+	}
+	ctx := context.Background()
+	contract := new(felt.Felt).SetUint64(1)
+
+	provider := New(&fakeReader{block: block})
+	chainID, err := provider.ChainID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "SN_SEPOLIA", chainID)
+	number, err := provider.BlockNumber(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), number)
+
+	result, err := provider.BlockWithTxHashes(ctx, rpc.WithBlockNumber(5))
+	require.NoError(t, err)
+	hashes, ok := result.AsBlock()
+	require.True(t, ok)
+	require.Equal(t, rpc.BlockStatus_AcceptedOnL2, hashes.Status)
+	require.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(0xa)}, hashes.Transactions)
+	_, err = provider.BlockWithTxHashes(ctx, rpc.WithBlockNumber(6))
+	require.True(t, errors.Is(err, rpc.ErrBlockNotFound))
+
+	nonce, err := provider.Nonce(ctx, rpc.WithBlockTag("latest"), contract)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), nonce.Uint64())
+	_, err = provider.Nonce(ctx, rpc.WithBlockTag("latest"), new(felt.Felt))
+	require.True(t, errors.Is(err, rpc.ErrContractNotFound))
+	values, err := provider.StorageAtKeys(ctx, contract, []*felt.Felt{new(felt.Felt).SetUint64(7)}, rpc.WithBlockNumber(5))
+	require.NoError(t, err)
+	require.Equal(t, uint64(99), values[*new(felt.Felt).SetUint64(7)].Uint64())
+
+	_, err = provider.SpecVersion(ctx)
+	require.True(t, errors.Is(err, ErrMethodNotServed))
+	provider = New(&fakeReader{block: block}, WithFallback(fallbackClient{}))
+	version, err := provider.SpecVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "0.7.1", version)
+}
@@ -0,0 +1,81 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Group starts a set of components in order and stops them in reverse order,
+// so that a component is stopped before the components it depends on.
+type Group struct {
+	mu         sync.Mutex
+	components []Lifecycle
+	started    int
+}
+
+var _ Lifecycle = &Group{}
+
+// NewGroup creates a Group. A component must be listed after the components it uses.
+//
+// Parameters:
+// - components: the components, in start order
+// Returns:
+// - *Group: the group
+func NewGroup(components ...Lifecycle) *Group {
+	return &Group{components: components}
+}
+
+// Start starts every component in order. When a component fails to start,
+// the components already started are stopped and the error is returned.
+//
+// Parameters:
+// - ctx: bounds the startup and, on failure, the rollback
+// Returns:
+// - error: the error of the failing component, joined with the rollback errors
+func (g *Group) Start(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.started > 0 {
+		return ErrAlreadyStarted
+	}
+	for _, c := range g.components {
+		if err := c.Start(ctx); err != nil {
+			return errors.Join(err, g.stopStarted(ctx))
+		}
+		g.started++
+	}
+	return nil
+}
+
+// Stop stops the started components in reverse order. Every component is
+// stopped even if another one fails.
+//
+// Parameters:
+// - ctx: bounds the shutdown of every component
+// Returns:
+// - error: the errors of the components, joined
+func (g *Group) Stop(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.started == 0 {
+		return ErrNotStarted
+	}
+	return g.stopStarted(ctx)
+}
+
+// stopStarted stops the started components in reverse order. The caller must hold the lock.
+//
+// Parameters:
+// - ctx: bounds the shutdown of every component
+// Returns:
+// - error: the errors of the components, joined
+func (g *Group) stopStarted(ctx context.Context) error {
+	var errs []error
+	for ; g.started > 0; g.started-- {
+		if err := g.components[g.started-1].Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,238 @@
+// Package lifecycle defines how the long-running components of the SDK
+// (providers holding connections, watchers, relayers and indexers) are
+// started and stopped, so that an embedding service can shut them down
+// cleanly: no new work is accepted, in-flight work is drained and the final
+// checkpoint is flushed before Stop returns.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrAlreadyStarted = errors.New("lifecycle: component already started")
+	ErrNotStarted     = errors.New("lifecycle: component not started")
+	ErrStopped        = errors.New("lifecycle: component stopped")
+)
+
+// Lifecycle is implemented by every background component.
+type Lifecycle interface {
+	// Start launches the component and returns once it is running. The
+	// context only bounds the startup, the component keeps running until Stop.
+	Start(ctx context.Context) error
+	// Stop stops accepting new work, waits for the in-flight work and flushes
+	// the component state. When ctx is done before the drain completes,
+	// in-flight work is cancelled and ctx.Err() is returned.
+	Stop(ctx context.Context) error
+}
+
+// State is the state of a component.
+type State int
+
+const (
+	StateIdle State = iota
+	StateRunning
+	StateStopping
+	StateStopped
+)
+
+// String returns the name of the state.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the state name
+func (s State) String() string {
+	switch s {
+	case StateIdle:
+		return "idle"
+	case StateRunning:
+		return "running"
+	case StateStopping:
+		return "stopping"
+	case StateStopped:
+		return "stopped"
+	}
+	return "unknown"
+}
+
+// Base implements the bookkeeping of Lifecycle and is embedded by components.
+//
+// A component calls Begin from its Start method, launches its work with Go,
+// watches Stopping in its loops to stop taking new work, and calls Shutdown
+// from its Stop method. A Base can not be restarted once stopped.
+type Base struct {
+	mu       sync.Mutex
+	state    State
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopping chan struct{}
+	wg       sync.WaitGroup
+	onError  ErrorHandler
+}
+
+// SetErrorHandler sets the handler notified when work started with Go
+// panics. It must be called before Begin.
+//
+// Parameters:
+// - onError: the handler
+// Returns:
+//
+//	none
+func (b *Base) SetErrorHandler(onError ErrorHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onError = onError
+}
+
+// ErrorHandler returns the handler set with SetErrorHandler.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - ErrorHandler: the handler, nil if none was set
+func (b *Base) ErrorHandler() ErrorHandler {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.onError
+}
+
+// Begin moves the component to the running state.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - context.Context: the work context, cancelled once the component is stopped
+// - error: ErrAlreadyStarted or ErrStopped if the component is not idle
+func (b *Base) Begin() (context.Context, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case StateRunning, StateStopping:
+		return nil, ErrAlreadyStarted
+	case StateStopped:
+		return nil, ErrStopped
+	}
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	b.stopping = make(chan struct{})
+	b.state = StateRunning
+	return b.ctx, nil
+}
+
+// State returns the current state of the component.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - State: the state
+func (b *Base) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Stopping returns a channel closed when Stop is called. Loops select on it
+// to stop taking new work while in-flight work is drained.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - <-chan struct{}: the stopping channel, nil before Begin
+func (b *Base) Stopping() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stopping
+}
+
+// Go runs f in a goroutine tracked by the drain. The context passed to f is
+// only cancelled when the drain times out or after it completes, and f must
+// return promptly once it is cancelled. A panic in f is recovered and
+// reported to the error handler instead of crashing the process.
+//
+// Parameters:
+// - f: the work to run
+// Returns:
+// - bool: false if the component is not running and f was not started
+func (b *Base) Go(f func(ctx context.Context)) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state != StateRunning {
+		return false
+	}
+	b.wg.Add(1)
+	ctx, onError := b.ctx, b.onError
+	go func() {
+		defer b.wg.Done()
+		_ = SafeCall(onError, func() error {
+			f(ctx)
+			return nil
+		})
+	}()
+	return true
+}
+
+// Shutdown signals Stopping, waits for the work started with Go and then
+// calls flush, if not nil, to persist the final state of the component.
+//
+// flush is called even when the drain times out, with the same expired
+// context, so that it can decide what it is able to persist.
+//
+// Parameters:
+// - ctx: bounds the drain and the flush
+// - flush: the final flush of the component, may be nil
+// Returns:
+// - error: ErrNotStarted if the component never started, ctx.Err() if the
+// drain timed out, or the error of flush
+func (b *Base) Shutdown(ctx context.Context, flush func(ctx context.Context) error) error {
+	b.mu.Lock()
+	switch b.state {
+	case StateIdle:
+		b.mu.Unlock()
+		return ErrNotStarted
+	case StateStopping, StateStopped:
+		b.mu.Unlock()
+		return ErrStopped
+	}
+	b.state = StateStopping
+	close(b.stopping)
+	b.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+		b.cancel()
+		<-drained
+	}
+	b.cancel()
+
+	if flush != nil {
+		if flushErr := flush(ctx); flushErr != nil {
+			err = errors.Join(err, flushErr)
+		}
+	}
+
+	b.mu.Lock()
+	b.state = StateStopped
+	b.mu.Unlock()
+	return err
+}
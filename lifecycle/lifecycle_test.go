@@ -0,0 +1,126 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/require"
+)
+
+// worker is a component recording its start and stop events.
+type worker struct {
+	Base
+	name    string
+	events  *[]string
+	flushed bool
+}
+
+// Start implements Lifecycle.
+func (w *worker) Start(ctx context.Context) error {
+	if _, err := w.Begin(); err != nil {
+		return err
+	}
+	*w.events = append(*w.events, "start "+w.name)
+	return nil
+}
+
+// Stop implements Lifecycle and records the final flush.
+func (w *worker) Stop(ctx context.Context) error {
+	return w.Shutdown(ctx, func(ctx context.Context) error {
+		w.flushed = true
+		*w.events = append(*w.events, "stop "+w.name)
+		return nil
+	})
+}
+
+// TestBase_Drain tests that Shutdown waits for in-flight work, refuses new
+// work while stopping, and cancels the work when the drain times out.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestBase_Drain(t *testing.T) {
+	var events []string
+	w := &worker{name: "w", events: &events}
+	require.NoError(t, w.Start(context.Background()))
+	require.Equal(t, ErrAlreadyStarted, w.Start(context.Background()))
+
+	done := make(chan struct{})
+	require.True(t, w.Go(func(ctx context.Context) {
+		<-w.Stopping()
+		time.Sleep(10 * time.Millisecond)
+		close(done)
+	}))
+	require.NoError(t, w.Stop(context.Background()))
+	<-done
+	require.True(t, w.flushed)
+	require.Equal(t, StateStopped, w.State())
+	require.False(t, w.Go(func(context.Context) {}))
+	require.Equal(t, ErrStopped, w.Stop(context.Background()))
+
+	w = &worker{name: "slow", events: &events}
+	require.NoError(t, w.Start(context.Background()))
+	w.Go(func(ctx context.Context) { <-ctx.Done() })
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.True(t, errors.Is(w.Stop(ctx), context.DeadlineExceeded))
+	require.True(t, w.flushed)
+}
+
+// TestGroup tests that a Group stops its components in reverse start order.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestGroup(t *testing.T) {
+	var events []string
+	g := NewGroup(&worker{name: "a", events: &events}, &worker{name: "b", events: &events})
+	require.Equal(t, ErrNotStarted, g.Stop(context.Background()))
+	require.NoError(t, g.Start(context.Background()))
+	require.NoError(t, g.Stop(context.Background()))
+	require.Equal(t, []string{"start a", "start b", "stop b", "stop a"}, events)
+}
+
+// TestSafeCall tests that panics in callbacks and in work started with Go are
+// recovered and reported to the error handler.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestSafeCall(t *testing.T) {
+	var reported []error
+	var mu sync.Mutex
+	onError := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err)
+	}
+
+	failure := errors.New("failure")
+	require.Equal(t, failure, SafeCall(onError, func() error { return failure }))
+	err := SafeCall(onError, func() error { panic(failure) })
+	var panicErr *PanicError
+	require.True(t, errors.As(err, &panicErr))
+	require.True(t, errors.Is(err, failure))
+	require.NotEmpty(t, panicErr.Stack)
+	require.NoError(t, SafeCall(nil, func() error { return nil }))
+
+	var events []string
+	w := &worker{name: "w", events: &events}
+	w.SetErrorHandler(onError)
+	require.NoError(t, w.Start(context.Background()))
+	w.Go(func(context.Context) { panic("handler bug") })
+	require.NoError(t, w.Stop(context.Background()))
+
+	require.Len(t, reported, 3)
+	require.Equal(t, "callback panicked: handler bug", reported[2].Error())
+}
@@ -0,0 +1,68 @@
+package lifecycle
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrorHandler receives the errors of user callbacks, including recovered
+// panics, that a component can not return to its caller. It must be safe
+// for concurrent use.
+type ErrorHandler func(err error)
+
+// PanicError is the error reported when a callback panics.
+type PanicError struct {
+	// Value is the value passed to panic
+	Value any
+	// Stack is the stack trace of the panicking goroutine
+	Stack []byte
+}
+
+// Error returns the panic value.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the error message
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("callback panicked: %v", e.Value)
+}
+
+// Unwrap returns the panic value when it is an error.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: the panic value, or nil if it is not an error
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// SafeCall invokes a user callback and converts a panic into a *PanicError,
+// so that a faulty handler can not take down the component invoking it. The
+// error of the callback, or the recovered panic, is also passed to onError
+// when it is not nil.
+//
+// Parameters:
+// - onError: the handler notified of the error, may be nil
+// - f: the callback
+// Returns:
+// - error: the error returned by f, or a *PanicError if f panicked
+func SafeCall(onError ErrorHandler, f func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}()
+	return f()
+}
@@ -0,0 +1,416 @@
+// Package localvm executes view calls locally, against classes and storage
+// fetched from a node and checked with storage proofs, so that the result of
+// a call does not depend on trusting the node. It is experimental.
+//
+// The package has no Cairo VM of its own: a VM is plugged in through the VM
+// interface, e.g. a binding to the Rust or Go implementations, and reads the
+// state through State. Every read of ProvenState is proven against the state
+// root of the block with the merkle package, the classes included: a node
+// returning another storage value or another class than the ones committed
+// in the state root is detected.
+//
+// The state root is by default the new root of the block header returned by
+// the same node, which proves that the state read is the state of the header
+// but not that the header is canonical. WithRootSource sets a trusted source
+// of roots, e.g. the state roots posted on L1.
+//
+//	local := localvm.New(provider, vm)
+//	result, err := local.Call(ctx, call, rpc.WithBlockTag(rpc.BlockTagLatest))
+package localvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/contracts"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/merkle"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+var (
+	ErrPendingBlock = errors.New("localvm: the pending block has no state proofs")
+	ErrRootMismatch = errors.New("localvm: proof rooted at another state")
+)
+
+// Call is the execution of an entry point by a VM.
+type Call struct {
+	ContractAddress *felt.Felt
+	ClassHash       *felt.Felt
+	// Class is the compiled class of the contract, proven against the state
+	Class              *contracts.CasmClass
+	EntryPointSelector *felt.Felt
+	Calldata           []*felt.Felt
+	// BlockNumber is the block of the state
+	BlockNumber uint64
+}
+
+// VM executes the entry points of compiled classes, e.g. a binding to a Cairo
+// VM. The VM reads the state, for its storage reads and the calls to other
+// contracts, through the state it is given.
+type VM interface {
+	Execute(ctx context.Context, call Call, state State) ([]*felt.Felt, error)
+}
+
+// State is the state of a block read by a VM. The values of contracts not
+// deployed are zero.
+type State interface {
+	StorageAt(ctx context.Context, address, key *felt.Felt) (*felt.Felt, error)
+	ClassHashAt(ctx context.Context, address *felt.Felt) (*felt.Felt, error)
+	NonceAt(ctx context.Context, address *felt.Felt) (*felt.Felt, error)
+	CompiledClass(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error)
+}
+
+// RootSource returns the trusted state root of a block.
+type RootSource func(ctx context.Context, blockNumber uint64) (*felt.Felt, error)
+
+// ProvenState is the state of a block read from a node, every value being
+// checked with a storage proof against the state root of the block. The
+// values read are cached. It is safe for concurrent use by multiple
+// goroutines.
+type ProvenState struct {
+	provider    rpc.RpcProvider
+	blockNumber uint64
+	roots       rpc.GlobalRoots
+
+	mu        sync.Mutex
+	contracts map[felt.Felt]rpc.ContractLeafData
+	storage   map[felt.Felt]map[felt.Felt]*felt.Felt
+	classes   map[felt.Felt]*contracts.CasmClass
+}
+
+var _ State = &ProvenState{}
+
+// NewProvenState returns the state of a block, checking the roots of the
+// proofs of the node against a trusted state root.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider of the node, which must support storage proofs
+// - blockNumber: the number of the block
+// - stateRoot: the trusted state root of the block
+// Returns:
+// - *ProvenState: the state
+// - error: merkle.ErrRootMismatch if the roots of the node do not add up to the state root, or an error of the provider
+func NewProvenState(ctx context.Context, provider rpc.RpcProvider, blockNumber uint64, stateRoot *felt.Felt) (*ProvenState, error) {
+	result, err := provider.StorageProof(ctx, rpc.StorageProofInput{BlockID: rpc.WithBlockNumber(blockNumber)})
+	if err != nil {
+		return nil, err
+	}
+	if err := merkle.VerifyGlobalRoots(stateRoot, result.GlobalRoots); err != nil {
+		return nil, err
+	}
+	return &ProvenState{
+		provider:    provider,
+		blockNumber: blockNumber,
+		roots:       result.GlobalRoots,
+		contracts:   map[felt.Felt]rpc.ContractLeafData{},
+		storage:     map[felt.Felt]map[felt.Felt]*felt.Felt{},
+		classes:     map[felt.Felt]*contracts.CasmClass{},
+	}, nil
+}
+
+// BlockNumber returns the number of the block of the state.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - uint64: the block number
+func (s *ProvenState) BlockNumber() uint64 {
+	return s.blockNumber
+}
+
+// StorageAt returns the proven value of a storage key of a contract.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address of the contract
+// - key: the storage key
+// Returns:
+// - *felt.Felt: the value, zero if the key is not set
+// - error: an error if the proof is invalid or the provider fails
+func (s *ProvenState) StorageAt(ctx context.Context, address, key *felt.Felt) (*felt.Felt, error) {
+	s.mu.Lock()
+	value, ok := s.storage[*address][*key]
+	s.mu.Unlock()
+	if ok {
+		return value, nil
+	}
+
+	result, err := s.proof(ctx, rpc.StorageProofInput{
+		ContractAddresses:    []*felt.Felt{address},
+		ContractsStorageKeys: []rpc.ContractStorageKeys{{ContractAddress: address, StorageKeys: []*felt.Felt{key}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := s.verifyContract(address, result)
+	if err != nil {
+		return nil, err
+	}
+	value = new(felt.Felt)
+	if !leaf.ClassHash.IsZero() {
+		if len(result.ContractsStorageProofs) != 1 {
+			return nil, fmt.Errorf("%w: no storage proof of %s", merkle.ErrMissingNode, address)
+		}
+		if value, err = merkle.VerifyStorage(leaf.StorageRoot, key, result.ContractsStorageProofs[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.storage[*address] == nil {
+		s.storage[*address] = map[felt.Felt]*felt.Felt{}
+	}
+	s.storage[*address][*key] = value
+	return value, nil
+}
+
+// ClassHashAt returns the proven class hash of a contract.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address of the contract
+// Returns:
+// - *felt.Felt: the class hash, zero if the contract is not deployed
+// - error: an error if the proof is invalid or the provider fails
+func (s *ProvenState) ClassHashAt(ctx context.Context, address *felt.Felt) (*felt.Felt, error) {
+	leaf, err := s.contract(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return leaf.ClassHash, nil
+}
+
+// NonceAt returns the proven nonce of a contract.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address of the contract
+// Returns:
+// - *felt.Felt: the nonce, zero if the contract is not deployed
+// - error: an error if the proof is invalid or the provider fails
+func (s *ProvenState) NonceAt(ctx context.Context, address *felt.Felt) (*felt.Felt, error) {
+	leaf, err := s.contract(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return leaf.Nonce, nil
+}
+
+// CompiledClass returns the compiled class of a class, whose compiled class
+// hash is proven against the classes tree.
+//
+// Parameters:
+// - ctx: the context
+// - classHash: the hash of the class
+// Returns:
+// - *contracts.CasmClass: the compiled class
+// - error: merkle.ErrLeafMismatch if the class is not the declared one, or an error if the proof is invalid or the provider fails
+func (s *ProvenState) CompiledClass(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	s.mu.Lock()
+	class, ok := s.classes[*classHash]
+	s.mu.Unlock()
+	if ok {
+		return class, nil
+	}
+
+	class, err := s.provider.CompiledCasm(ctx, classHash)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.proof(ctx, rpc.StorageProofInput{ClassHashes: []*felt.Felt{classHash}})
+	if err != nil {
+		return nil, err
+	}
+	if err := merkle.VerifyClass(s.roots, result.ClassesProof, classHash, hash.CompiledClassHash(*class)); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.classes[*classHash] = class
+	return class, nil
+}
+
+// contract returns the proven state of a contract.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address of the contract
+// Returns:
+// - rpc.ContractLeafData: the state of the contract
+// - error: an error if the proof is invalid or the provider fails
+func (s *ProvenState) contract(ctx context.Context, address *felt.Felt) (rpc.ContractLeafData, error) {
+	s.mu.Lock()
+	leaf, ok := s.contracts[*address]
+	s.mu.Unlock()
+	if ok {
+		return leaf, nil
+	}
+	result, err := s.proof(ctx, rpc.StorageProofInput{ContractAddresses: []*felt.Felt{address}})
+	if err != nil {
+		return rpc.ContractLeafData{}, err
+	}
+	return s.verifyContract(address, result)
+}
+
+// verifyContract checks the state of a contract in a proof, and caches it.
+//
+// Parameters:
+// - address: the address of the contract
+// - result: the proof of the contract
+// Returns:
+// - rpc.ContractLeafData: the state of the contract
+// - error: an error if the proof is invalid
+func (s *ProvenState) verifyContract(address *felt.Felt, result *rpc.StorageProofResult) (rpc.ContractLeafData, error) {
+	if len(result.ContractsProof.ContractLeavesData) != 1 {
+		return rpc.ContractLeafData{}, fmt.Errorf("%w: no contract leaf of %s", merkle.ErrMissingNode, address)
+	}
+	leaf := result.ContractsProof.ContractLeavesData[0]
+	if err := merkle.VerifyContract(s.roots, result.ContractsProof, address, leaf); err != nil {
+		return rpc.ContractLeafData{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contracts[*address] = leaf
+	return leaf, nil
+}
+
+// proof fetches a storage proof of the block of the state, checking that it
+// is rooted at the state.
+//
+// Parameters:
+// - ctx: the context
+// - input: the classes, contracts and storage keys to prove
+// Returns:
+// - *rpc.StorageProofResult: the proof
+// - error: ErrRootMismatch if the proof is rooted at another state, or an error of the provider
+func (s *ProvenState) proof(ctx context.Context, input rpc.StorageProofInput) (*rpc.StorageProofResult, error) {
+	input.BlockID = rpc.WithBlockNumber(s.blockNumber)
+	result, err := s.provider.StorageProof(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	roots := result.GlobalRoots
+	if !roots.ContractsTreeRoot.Equal(s.roots.ContractsTreeRoot) || !roots.ClassesTreeRoot.Equal(s.roots.ClassesTreeRoot) {
+		return nil, fmt.Errorf("%w: block %d", ErrRootMismatch, s.blockNumber)
+	}
+	return result, nil
+}
+
+// Provider is an rpc.RpcProvider executing calls locally against the proven
+// state of the block of the call. The other methods are passed to the
+// upstream node.
+type Provider struct {
+	rpc.RpcProvider
+
+	vm   VM
+	root RootSource
+}
+
+var _ rpc.RpcProvider = &Provider{}
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithRootSource sets the source of the trusted state roots, instead of the
+// block headers of the upstream node.
+//
+// Parameters:
+// - source: the source of the state roots
+// Returns:
+// - Option: the option
+func WithRootSource(source RootSource) Option {
+	return func(p *Provider) {
+		p.root = source
+	}
+}
+
+// New returns a provider executing calls with a VM.
+//
+// Parameters:
+// - upstream: the provider of the upstream node, which must support storage proofs
+// - vm: the VM
+// - opts: the options
+// Returns:
+// - *Provider: the provider
+func New(upstream rpc.RpcProvider, vm VM, opts ...Option) *Provider {
+	p := &Provider{RpcProvider: upstream, vm: vm}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// State returns the proven state of a block.
+//
+// Parameters:
+// - ctx: the context
+// - blockID: the block, which cannot be the pending block
+// Returns:
+// - *ProvenState: the state
+// - error: ErrPendingBlock, or an error if the roots are not trusted or the provider fails
+func (p *Provider) State(ctx context.Context, blockID rpc.BlockID) (*ProvenState, error) {
+	if blockID.Tag == rpc.BlockTagPending {
+		return nil, ErrPendingBlock
+	}
+	block, err := p.RpcProvider.BlockWithTxHashes(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	header, ok := block.AsBlock()
+	if !ok {
+		return nil, ErrPendingBlock
+	}
+	root := header.NewRoot
+	if p.root != nil {
+		if root, err = p.root(ctx, header.BlockNumber); err != nil {
+			return nil, err
+		}
+	}
+	return NewProvenState(ctx, p.RpcProvider, header.BlockNumber, root)
+}
+
+// Call executes a view call with the VM, against the proven state of the
+// block.
+//
+// Parameters:
+// - ctx: the context
+// - call: the call
+// - blockID: the block, which cannot be the pending block
+// Returns:
+// - []*felt.Felt: the result of the call
+// - error: rpc.ErrContractNotFound if the contract is not deployed, an error of the VM, or an error of State
+func (p *Provider) Call(ctx context.Context, call rpc.FunctionCall, blockID rpc.BlockID) ([]*felt.Felt, error) {
+	state, err := p.State(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+	classHash, err := state.ClassHashAt(ctx, call.ContractAddress)
+	if err != nil {
+		return nil, err
+	}
+	if classHash.IsZero() {
+		return nil, rpc.ErrContractNotFound
+	}
+	class, err := state.CompiledClass(ctx, classHash)
+	if err != nil {
+		return nil, err
+	}
+	return p.vm.Execute(ctx, Call{
+		ContractAddress:    call.ContractAddress,
+		ClassHash:          classHash,
+		Class:              class,
+		EntryPointSelector: call.EntryPointSelector,
+		Calldata:           call.Calldata,
+		BlockNumber:        state.BlockNumber(),
+	}, state)
+}
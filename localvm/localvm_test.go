@@ -0,0 +1,132 @@
+package localvm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/contracts"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/merkle"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// storageVM is a VM returning the value of the storage key of the calldata.
+type storageVM struct {
+	calls []Call
+}
+
+// Execute returns the value of the storage key of the calldata.
+//
+// Parameters:
+// - ctx: the context
+// - call: the call
+// - state: the state
+// Returns:
+// - []*felt.Felt: the value
+// - error: an error of the state
+func (vm *storageVM) Execute(ctx context.Context, call Call, state State) ([]*felt.Felt, error) {
+	vm.calls = append(vm.calls, call)
+	value, err := state.StorageAt(ctx, call.ContractAddress, call.Calldata[0])
+	if err != nil {
+		return nil, err
+	}
+	return []*felt.Felt{value}, nil
+}
+
+// leafProof returns the proof of the key of a tree holding a single leaf,
+// made of the edge node from the root to the leaf.
+//
+// Parameters:
+// - key: the key of the leaf
+// - leaf: the leaf
+// - hash: the hash of the tree
+// Returns:
+// - *felt.Felt: the root of the tree
+// - rpc.NodeHashToNodeMapping: the proof
+func leafProof(key, leaf *felt.Felt, hash merkle.HashFunc) (*felt.Felt, rpc.NodeHashToNodeMapping) {
+	node := rpc.MerkleNode{Path: key, Length: merkle.TreeHeight, Child: leaf}
+	root := merkle.NodeHash(node, hash)
+	return root, rpc.NodeHashToNodeMapping{{NodeHash: root, Node: node}}
+}
+
+// TestCall tests a call executed locally against a proven state, reading a
+// storage value and the compiled class, and the rejection of tampered
+// values, of a mismatched state root and of the pending block.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestCall(t *testing.T) {
+	class, err := contracts.UnmarshalCasmClass("../contracts/tests/hello_starknet_compiled.casm.json")
+	require.NoError(t, err)
+	address := new(felt.Felt).SetUint64(0x123)
+	classHash := new(felt.Felt).SetUint64(0xc1a55)
+	key := new(felt.Felt).SetUint64(0x5)
+	value := new(felt.Felt).SetUint64(42)
+
+	storageRoot, storageProof := leafProof(key, value, crypto.Pedersen)
+	leaf := rpc.ContractLeafData{Nonce: new(felt.Felt).SetUint64(1), ClassHash: classHash, StorageRoot: storageRoot}
+	contractLeaf, err := merkle.ContractLeaf(leaf)
+	require.NoError(t, err)
+	contractsRoot, contractsProof := leafProof(address, contractLeaf, crypto.Pedersen)
+	classesRoot, classesProof := leafProof(classHash, merkle.ClassLeaf(hash.CompiledClassHash(*class)), crypto.Poseidon)
+	roots := rpc.GlobalRoots{ContractsTreeRoot: contractsRoot, ClassesTreeRoot: classesRoot, BlockHash: new(felt.Felt).SetUint64(0xb)}
+	stateRoot := merkle.StateRoot(contractsRoot, classesRoot)
+
+	mockCtrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(mockCtrl)
+	header := rpc.BlockHeader{BlockNumber: 7, NewRoot: stateRoot}
+	provider.EXPECT().BlockWithTxHashes(gomock.Any(), gomock.Any()).Return(&rpc.BlockTxHashesResult{Block: &rpc.BlockTxHashes{BlockHeader: header}}, nil).AnyTimes()
+	provider.EXPECT().CompiledCasm(gomock.Any(), classHash).Return(class, nil).AnyTimes()
+	tampered := false
+	provider.EXPECT().StorageProof(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, input rpc.StorageProofInput) (*rpc.StorageProofResult, error) {
+		require.Equal(t, uint64(7), *input.BlockID.Number)
+		result := &rpc.StorageProofResult{GlobalRoots: roots, ClassesProof: rpc.NodeHashToNodeMapping{}}
+		if len(input.ClassHashes) > 0 {
+			result.ClassesProof = classesProof
+		}
+		if len(input.ContractAddresses) > 0 {
+			result.ContractsProof = rpc.ContractsProof{Nodes: contractsProof, ContractLeavesData: []rpc.ContractLeafData{leaf}}
+		}
+		for range input.ContractsStorageKeys {
+			proof := storageProof
+			if tampered {
+				_, proof = leafProof(key, new(felt.Felt).SetUint64(43), crypto.Pedersen)
+			}
+			result.ContractsStorageProofs = append(result.ContractsStorageProofs, proof)
+		}
+		return result, nil
+	}).AnyTimes()
+
+	vm := &storageVM{}
+	local := New(provider, vm)
+	call := rpc.FunctionCall{ContractAddress: address, EntryPointSelector: new(felt.Felt).SetUint64(1), Calldata: []*felt.Felt{key}}
+	result, err := local.Call(context.Background(), call, rpc.WithBlockTag(rpc.BlockTagLatest))
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{value}, result)
+	require.Len(t, vm.calls, 1)
+	require.Equal(t, class, vm.calls[0].Class)
+	require.Equal(t, classHash, vm.calls[0].ClassHash)
+	require.Equal(t, uint64(7), vm.calls[0].BlockNumber)
+
+	tampered = true
+	_, err = local.Call(context.Background(), call, rpc.WithBlockNumber(7))
+	require.True(t, errors.Is(err, merkle.ErrMissingNode), err)
+
+	untrusted := New(provider, vm, WithRootSource(func(context.Context, uint64) (*felt.Felt, error) {
+		return new(felt.Felt).SetUint64(0xbad), nil
+	}))
+	_, err = untrusted.Call(context.Background(), call, rpc.WithBlockNumber(7))
+	require.True(t, errors.Is(err, merkle.ErrRootMismatch))
+
+	_, err = local.Call(context.Background(), call, rpc.WithBlockTag(rpc.BlockTagPending))
+	require.True(t, errors.Is(err, ErrPendingBlock))
+}
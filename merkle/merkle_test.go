@@ -1,8 +1,13 @@
 package merkle
 
 import (
+	"errors"
 	"math/big"
 	"testing"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
 )
 
 // debugProof is a function used for debugging purposes. It logs the proofs to the testing logger.
@@ -19,6 +24,43 @@ func debugProof(t *testing.T, proofs []*big.Int) {
 	}
 }
 
+// TestFixedSizeMerkleTree_OddBranch tests the proofs of the last leaf of a
+// branch of odd length, whose sibling is zero.
+//
+// Parameters:
+// - t: A testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestFixedSizeMerkleTree_OddBranch(t *testing.T) {
+	leaves := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4), big.NewInt(5), big.NewInt(6), big.NewInt(7)}
+	merkleTree, err := NewFixedSizeMerkleTree(leaves...)
+	if err != nil {
+		t.Fatal("should generate merkle tree, error", err)
+	}
+	proof, err := merkleTree.Proof(big.NewInt(7))
+	if err != nil {
+		t.Fatal("should generate merkle proof, error", err)
+	}
+	proof_5_6, _ := MerkleHash(big.NewInt(5), big.NewInt(6))
+	proof_1_2, _ := MerkleHash(big.NewInt(1), big.NewInt(2))
+	proof_3_4, _ := MerkleHash(big.NewInt(3), big.NewInt(4))
+	proof_1_2_3_4, _ := MerkleHash(proof_1_2, proof_3_4)
+	manualProof := []*big.Int{big.NewInt(0), proof_5_6, proof_1_2_3_4}
+	if len(manualProof) != len(proof) {
+		debugProof(t, proof)
+		t.Fatalf("tree length should match, expected: %d, got: %d", len(manualProof), len(proof))
+	}
+	for i, p := range manualProof {
+		if p.Cmp(proof[i]) != 0 {
+			t.Fatalf("proof should match, expected: 0x%s, got: 0x%s", p.Text(16), proof[i].Text(16))
+		}
+	}
+	if ok := ProofMerklePath(merkleTree.Root, big.NewInt(7), proof); !ok {
+		t.Fatal("root should match proof. it does not")
+	}
+}
+
 // TestGeneral_FixedSizeMerkleTree_Check1 is a Go function that tests the functionality of the FixedSizeMerkleTree.Check1 method.
 //
 // It creates a fixed-size Merkle tree with the given leaves and calculates the Merkle proof for a specific leaf. It then compares the manual proof generated with the expected proof and checks if the Merkle tree root matches the proof.
@@ -61,3 +103,169 @@ func TestGeneral_FixedSizeMerkleTree_Check1(t *testing.T) {
 		t.Fatal("root should match proof. it does not")
 	}
 }
+
+// patriciaProof builds the proof of a storage trie holding valueA at key 0x1
+// and valueB at key 0x2: an edge of 249 zero bits to a binary node, whose
+// children are edges of one bit to the values.
+//
+// Parameters:
+// - valueA: the value at key 0x1
+// - valueB: the value at key 0x2
+// Returns:
+// - *felt.Felt: the root of the trie
+// - rpc.NodeHashToNodeMapping: the nodes of the trie
+func patriciaProof(valueA, valueB *felt.Felt) (*felt.Felt, rpc.NodeHashToNodeMapping) {
+	edgeA := rpc.MerkleNode{Path: new(felt.Felt).SetUint64(1), Length: 1, Child: valueA}
+	edgeB := rpc.MerkleNode{Path: new(felt.Felt), Length: 1, Child: valueB}
+	binary := rpc.MerkleNode{Left: NodeHash(edgeA, crypto.Pedersen), Right: NodeHash(edgeB, crypto.Pedersen)}
+	root := rpc.MerkleNode{Path: new(felt.Felt), Length: TreeHeight - 2, Child: NodeHash(binary, crypto.Pedersen)}
+	var nodes rpc.NodeHashToNodeMapping
+	for _, node := range []rpc.MerkleNode{root, binary, edgeA, edgeB} {
+		nodes = append(nodes, rpc.NodeHashToNode{NodeHash: NodeHash(node, crypto.Pedersen), Node: node})
+	}
+	return nodes[0].NodeHash, nodes
+}
+
+// TestVerifyStorage tests the membership and the non-membership proofs of
+// storage keys, and the rejection of incomplete and tampered proofs.
+//
+// Parameters:
+// - t: A testing.T object used for reporting test failures and logging.
+// Returns:
+//   none
+func TestVerifyStorage(t *testing.T) {
+	valueA, valueB := new(felt.Felt).SetUint64(0xa), new(felt.Felt).SetUint64(0xb)
+	root, nodes := patriciaProof(valueA, valueB)
+
+	for key, expected := range map[uint64]*felt.Felt{1: valueA, 2: valueB, 3: new(felt.Felt), 4: new(felt.Felt)} {
+		value, err := VerifyStorage(root, new(felt.Felt).SetUint64(key), nodes)
+		if err != nil {
+			t.Fatalf("key %d: %v", key, err)
+		}
+		if !value.Equal(expected) {
+			t.Fatalf("key %d: expected %s, got %s", key, expected, value)
+		}
+	}
+
+	if _, err := VerifyStorage(root, new(felt.Felt).SetUint64(1), nodes[:2]); !errors.Is(err, ErrMissingNode) {
+		t.Fatalf("expected a missing node, got %v", err)
+	}
+	tampered := append(rpc.NodeHashToNodeMapping{}, nodes...)
+	tampered[2].Node.Child = new(felt.Felt).SetUint64(0xc)
+	if _, err := VerifyStorage(root, new(felt.Felt).SetUint64(1), tampered); !errors.Is(err, ErrInvalidNode) {
+		t.Fatalf("expected an invalid node, got %v", err)
+	}
+}
+
+// TestVerifyContract tests the proofs of deployed and undeployed contracts,
+// and the global roots.
+//
+// Parameters:
+// - t: A testing.T object used for reporting test failures and logging.
+// Returns:
+//   none
+func TestVerifyContract(t *testing.T) {
+	leaf := rpc.ContractLeafData{
+		Nonce:       new(felt.Felt).SetUint64(3),
+		ClassHash:   new(felt.Felt).SetUint64(0x123),
+		StorageRoot: new(felt.Felt).SetUint64(0x456),
+	}
+	leafHash, err := ContractLeaf(leaf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contractsRoot, nodes := patriciaProof(leafHash, new(felt.Felt).SetUint64(0xb))
+	roots := rpc.GlobalRoots{ContractsTreeRoot: contractsRoot, ClassesTreeRoot: new(felt.Felt).SetUint64(0x789)}
+	proof := rpc.ContractsProof{Nodes: nodes}
+
+	if err := VerifyGlobalRoots(crypto.PoseidonArray(stateVersion, roots.ContractsTreeRoot, roots.ClassesTreeRoot), roots); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyGlobalRoots(contractsRoot, roots); !errors.Is(err, ErrRootMismatch) {
+		t.Fatalf("expected a root mismatch, got %v", err)
+	}
+	if err := VerifyContract(roots, proof, new(felt.Felt).SetUint64(1), leaf); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyContract(roots, proof, new(felt.Felt).SetUint64(4), rpc.ContractLeafData{Nonce: new(felt.Felt), ClassHash: new(felt.Felt)}); err != nil {
+		t.Fatal(err)
+	}
+	leaf.Nonce = new(felt.Felt).SetUint64(4)
+	if err := VerifyContract(roots, proof, new(felt.Felt).SetUint64(1), leaf); !errors.Is(err, ErrLeafMismatch) {
+		t.Fatalf("expected a leaf mismatch, got %v", err)
+	}
+}
+
+// TestTree tests the roots of the Pedersen and Poseidon trees, and that their
+// proofs verify for their leaves only.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestTree(t *testing.T) {
+	if _, err := NewPedersenTree(); !errors.Is(err, ErrNoLeaves) {
+		t.Fatalf("expected ErrNoLeaves, got %v", err)
+	}
+	leaves := make([]*felt.Felt, 7)
+	for i := range leaves {
+		leaves[i] = new(felt.Felt).SetUint64(uint64(7 - i))
+	}
+	pedersen, err := NewPedersenTree(leaves...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// leaves 7, 6, 5, 4, 3, 2, 1 pair sorted as (6, 7), (4, 5), (2, 3), (0, 1)
+	h := func(a, b *felt.Felt) *felt.Felt { return PairHash(crypto.Pedersen, a, b) }
+	root := h(h(h(leaves[1], leaves[0]), h(leaves[3], leaves[2])), h(h(leaves[5], leaves[4]), h(leaves[6], &felt.Zero)))
+	if !pedersen.Root.Equal(root) {
+		t.Fatalf("root %s, expected %s", pedersen.Root, root)
+	}
+
+	poseidon, err := NewPoseidonTree(leaves...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tree := range []struct {
+		tree *Tree
+		hash HashFunc
+	}{{pedersen, crypto.Pedersen}, {poseidon, crypto.Poseidon}} {
+		for i, leaf := range leaves {
+			proof, err := tree.tree.Proof(i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(proof) != 3 {
+				t.Fatalf("proof of leaf %d has %d nodes, expected 3", i, len(proof))
+			}
+			if !VerifyProof(tree.hash, tree.tree.Root, leaf, proof) {
+				t.Fatalf("proof of leaf %d does not verify", i)
+			}
+			if VerifyProof(tree.hash, tree.tree.Root, new(felt.Felt).SetUint64(8), proof) {
+				t.Fatalf("proof of leaf %d verifies another leaf", i)
+			}
+		}
+		if _, err := tree.tree.ProofOf(new(felt.Felt).SetUint64(8)); !errors.Is(err, ErrLeafNotFound) {
+			t.Fatalf("expected ErrLeafNotFound, got %v", err)
+		}
+		if _, err := tree.tree.Proof(7); !errors.Is(err, ErrLeafNotFound) {
+			t.Fatalf("expected ErrLeafNotFound, got %v", err)
+		}
+	}
+	proof, err := pedersen.ProofOf(leaves[6])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proof[0].IsZero() || !proof[1].Equal(h(leaves[5], leaves[4])) {
+		t.Fatalf("unexpected proof of the last leaf %v", proof)
+	}
+
+	single, err := NewPoseidonTree(leaves[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !single.Root.Equal(leaves[0]) {
+		t.Fatalf("root of a single leaf %s, expected the leaf", single.Root)
+	}
+}
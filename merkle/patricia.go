@@ -0,0 +1,225 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// TreeHeight is the height of the Merkle-Patricia trees of the Starknet
+// state, in bits of their keys.
+const TreeHeight = 251
+
+var (
+	ErrMissingNode   = errors.New("merkle: node missing from the proof")
+	ErrInvalidNode   = errors.New("merkle: node does not match its hash")
+	ErrRootMismatch  = errors.New("merkle: root mismatch")
+	ErrLeafMismatch  = errors.New("merkle: leaf mismatch")
+	ErrNoStorageRoot = errors.New("merkle: no storage root in the contract leaf")
+)
+
+var (
+	stateVersion     = new(felt.Felt).SetBytes([]byte("STARKNET_STATE_V0"))
+	classLeafVersion = new(felt.Felt).SetBytes([]byte("CONTRACT_CLASS_LEAF_V0"))
+)
+
+// HashFunc is the hash of the nodes of a tree: Pedersen for the contracts
+// tree and the storage tries, Poseidon for the classes tree.
+type HashFunc func(a, b *felt.Felt) *felt.Felt
+
+// NodeHash calculates the hash of a node: H(left, right) for a binary node,
+// H(child, path) + length for an edge node.
+//
+// Parameters:
+// - node: the node
+// - hash: the hash of the tree
+// Returns:
+// - *felt.Felt: the hash of the node
+func NodeHash(node rpc.MerkleNode, hash HashFunc) *felt.Felt {
+	if !node.IsEdge() {
+		return hash(node.Left, node.Right)
+	}
+	h := hash(node.Child, node.Path)
+	return h.Add(h, new(felt.Felt).SetUint64(uint64(node.Length)))
+}
+
+// VerifyPath walks the proof of a key from the root of a tree, checking the
+// hash of every node on the way, and returns the leaf of the key. A proof
+// ending on an edge diverging from the key proves that the key is not in the
+// tree, and its leaf is zero.
+//
+// Parameters:
+// - root: the root of the tree
+// - key: the key
+// - nodes: the nodes of the proof
+// - hash: the hash of the tree
+// Returns:
+// - *felt.Felt: the leaf of the key, zero if the key is not in the tree
+// - error: ErrMissingNode or ErrInvalidNode if the proof is incomplete or invalid
+func VerifyPath(root, key *felt.Felt, nodes rpc.NodeHashToNodeMapping, hash HashFunc) (*felt.Felt, error) {
+	byHash := make(map[felt.Felt]rpc.MerkleNode, len(nodes))
+	for _, node := range nodes {
+		byHash[*node.NodeHash] = node.Node
+	}
+	k := key.BigInt(new(big.Int))
+	current := root
+	for depth := 0; depth < TreeHeight; {
+		if current.IsZero() {
+			return new(felt.Felt), nil
+		}
+		node, ok := byHash[*current]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingNode, current)
+		}
+		if !NodeHash(node, hash).Equal(current) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNode, current)
+		}
+		if !node.IsEdge() {
+			if k.Bit(TreeHeight-1-depth) == 0 {
+				current = node.Left
+			} else {
+				current = node.Right
+			}
+			depth++
+			continue
+		}
+		length := int(node.Length)
+		if length == 0 || depth+length > TreeHeight {
+			return nil, fmt.Errorf("%w: %s has length %d at depth %d", ErrInvalidNode, current, length, depth)
+		}
+		path := new(big.Int).Rsh(k, uint(TreeHeight-depth-length))
+		path.And(path, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(length)), big.NewInt(1)))
+		if path.Cmp(node.Path.BigInt(new(big.Int))) != 0 {
+			return new(felt.Felt), nil
+		}
+		current = node.Child
+		depth += length
+	}
+	return current, nil
+}
+
+// StateRoot calculates the state root of a block from the roots of its trees.
+//
+// Parameters:
+// - contractsRoot: the root of the contracts tree
+// - classesRoot: the root of the classes tree
+// Returns:
+// - *felt.Felt: the state root
+func StateRoot(contractsRoot, classesRoot *felt.Felt) *felt.Felt {
+	if classesRoot.IsZero() {
+		return contractsRoot
+	}
+	return crypto.PoseidonArray(stateVersion, contractsRoot, classesRoot)
+}
+
+// ContractLeaf calculates the leaf of a contract in the contracts tree,
+// H(H(H(class_hash, storage_root), nonce), 0).
+//
+// Parameters:
+// - leaf: the state of the contract
+// Returns:
+// - *felt.Felt: the leaf of the contract
+// - error: ErrNoStorageRoot if the state has no storage root
+func ContractLeaf(leaf rpc.ContractLeafData) (*felt.Felt, error) {
+	if leaf.StorageRoot == nil {
+		return nil, ErrNoStorageRoot
+	}
+	h := crypto.Pedersen(leaf.ClassHash, leaf.StorageRoot)
+	h = crypto.Pedersen(h, leaf.Nonce)
+	return crypto.Pedersen(h, &felt.Zero), nil
+}
+
+// ClassLeaf calculates the leaf of a class in the classes tree.
+//
+// Parameters:
+// - compiledClassHash: the compiled class hash of the class
+// Returns:
+// - *felt.Felt: the leaf of the class
+func ClassLeaf(compiledClassHash *felt.Felt) *felt.Felt {
+	return crypto.Poseidon(classLeafVersion, compiledClassHash)
+}
+
+// VerifyGlobalRoots checks the roots of a storage proof against a trusted
+// state root, e.g. the new root of a block accepted on L1.
+//
+// Parameters:
+// - stateRoot: the trusted state root
+// - roots: the roots of the proof
+// Returns:
+// - error: ErrRootMismatch if the roots do not add up to the state root
+func VerifyGlobalRoots(stateRoot *felt.Felt, roots rpc.GlobalRoots) error {
+	if root := StateRoot(roots.ContractsTreeRoot, roots.ClassesTreeRoot); !root.Equal(stateRoot) {
+		return fmt.Errorf("%w: roots add up to %s, expected %s", ErrRootMismatch, root, stateRoot)
+	}
+	return nil
+}
+
+// VerifyContract checks the state of a contract against the contracts tree.
+// A contract with a zero class hash and nonce is proven not deployed.
+//
+// Parameters:
+// - roots: the roots of the proof, checked with VerifyGlobalRoots
+// - proof: the proof of the contracts
+// - address: the address of the contract
+// - leaf: the state of the contract
+// Returns:
+// - error: ErrLeafMismatch if the state is not the state of the contract in the tree, or an error if the proof is invalid
+func VerifyContract(roots rpc.GlobalRoots, proof rpc.ContractsProof, address *felt.Felt, leaf rpc.ContractLeafData) error {
+	value, err := VerifyPath(roots.ContractsTreeRoot, address, proof.Nodes, crypto.Pedersen)
+	if err != nil {
+		return err
+	}
+	if value.IsZero() && leaf.ClassHash.IsZero() && leaf.Nonce.IsZero() {
+		return nil
+	}
+	expected, err := ContractLeaf(leaf)
+	if err != nil {
+		return err
+	}
+	if !value.Equal(expected) {
+		return fmt.Errorf("%w: contract %s", ErrLeafMismatch, address)
+	}
+	return nil
+}
+
+// VerifyStorage returns the value of a storage key proven against the storage
+// root of a contract, checked with VerifyContract.
+//
+// Parameters:
+// - storageRoot: the storage root of the contract
+// - key: the storage key
+// - proof: the proof of the storage key
+// Returns:
+// - *felt.Felt: the value of the key, zero if the key is not set
+// - error: an error if the proof is invalid
+func VerifyStorage(storageRoot, key *felt.Felt, proof rpc.NodeHashToNodeMapping) (*felt.Felt, error) {
+	return VerifyPath(storageRoot, key, proof, crypto.Pedersen)
+}
+
+// VerifyClass checks the compiled class hash of a class against the classes
+// tree. A zero compiled class hash is proven not declared.
+//
+// Parameters:
+// - roots: the roots of the proof, checked with VerifyGlobalRoots
+// - proof: the proof of the classes
+// - classHash: the hash of the class
+// - compiledClassHash: the compiled class hash of the class
+// Returns:
+// - error: ErrLeafMismatch if the compiled class hash is not the one in the tree, or an error if the proof is invalid
+func VerifyClass(roots rpc.GlobalRoots, proof rpc.NodeHashToNodeMapping, classHash, compiledClassHash *felt.Felt) error {
+	value, err := VerifyPath(roots.ClassesTreeRoot, classHash, proof, crypto.Poseidon)
+	if err != nil {
+		return err
+	}
+	if value.IsZero() && compiledClassHash.IsZero() {
+		return nil
+	}
+	if !value.Equal(ClassLeaf(compiledClassHash)) {
+		return fmt.Errorf("%w: class %s", ErrLeafMismatch, classHash)
+	}
+	return nil
+}
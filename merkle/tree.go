@@ -0,0 +1,153 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var (
+	ErrNoLeaves     = errors.New("merkle: tree without leaves")
+	ErrLeafNotFound = errors.New("merkle: leaf not in the tree")
+)
+
+// Tree is a binary Merkle tree of felts, as verified by the merkle_proof of
+// the OpenZeppelin Cairo contracts and by the airdrop contracts built on it:
+// the pairs of nodes are hashed sorted, so that proofs carry no direction,
+// with a single hash of the two nodes, unlike the MerkleHash of
+// FixedSizeMerkleTree, and the last node of an odd layer is paired with zero.
+type Tree struct {
+	// Layers are the nodes of the tree, from the leaves to the root
+	Layers [][]*felt.Felt
+	Root   *felt.Felt
+	hash   HashFunc
+}
+
+// NewTree builds the tree of leaves, e.g. the hashes of the address and the
+// amount of the recipients of an airdrop, with a hash: crypto.Pedersen, or
+// crypto.Poseidon for the Poseidon hasher of OpenZeppelin, a single
+// permutation of the two nodes.
+//
+// Parameters:
+// - hash: the hash of the pairs of nodes
+// - leaves: the leaves, in order
+// Returns:
+// - *Tree: the tree
+// - error: ErrNoLeaves if there are no leaves
+func NewTree(hash HashFunc, leaves ...*felt.Felt) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrNoLeaves
+	}
+	t := &Tree{Layers: [][]*felt.Felt{leaves}, hash: hash}
+	for layer := leaves; len(layer) > 1; {
+		next := make([]*felt.Felt, 0, (len(layer)+1)/2)
+		for i := 0; i < len(layer); i += 2 {
+			sibling := &felt.Zero
+			if i+1 < len(layer) {
+				sibling = layer[i+1]
+			}
+			next = append(next, PairHash(hash, layer[i], sibling))
+		}
+		t.Layers = append(t.Layers, next)
+		layer = next
+	}
+	t.Root = t.Layers[len(t.Layers)-1][0]
+	return t, nil
+}
+
+// NewPedersenTree builds the tree of leaves with the Pedersen hash.
+//
+// Parameters:
+// - leaves: the leaves, in order
+// Returns:
+// - *Tree: the tree
+// - error: ErrNoLeaves if there are no leaves
+func NewPedersenTree(leaves ...*felt.Felt) (*Tree, error) {
+	return NewTree(crypto.Pedersen, leaves...)
+}
+
+// NewPoseidonTree builds the tree of leaves with the Poseidon hash.
+//
+// Parameters:
+// - leaves: the leaves, in order
+// Returns:
+// - *Tree: the tree
+// - error: ErrNoLeaves if there are no leaves
+func NewPoseidonTree(leaves ...*felt.Felt) (*Tree, error) {
+	return NewTree(crypto.Poseidon, leaves...)
+}
+
+// PairHash hashes two nodes of a tree, the lower first.
+//
+// Parameters:
+// - hash: the hash of the tree
+// - a: a node
+// - b: the other node
+// Returns:
+// - *felt.Felt: the hash of the pair
+func PairHash(hash HashFunc, a, b *felt.Felt) *felt.Felt {
+	if a.Cmp(b) <= 0 {
+		return hash(a, b)
+	}
+	return hash(b, a)
+}
+
+// Proof returns the proof of the leaf at an index: the siblings of the nodes
+// from the leaf to the root.
+//
+// Parameters:
+// - index: the index of the leaf
+// Returns:
+// - []*felt.Felt: the proof
+// - error: ErrLeafNotFound if the index is out of the leaves
+func (t *Tree) Proof(index int) ([]*felt.Felt, error) {
+	if index < 0 || index >= len(t.Layers[0]) {
+		return nil, fmt.Errorf("%w: index %d of %d leaves", ErrLeafNotFound, index, len(t.Layers[0]))
+	}
+	proof := make([]*felt.Felt, 0, len(t.Layers)-1)
+	for _, layer := range t.Layers[:len(t.Layers)-1] {
+		sibling := &felt.Zero
+		if index^1 < len(layer) {
+			sibling = layer[index^1]
+		}
+		proof = append(proof, sibling)
+		index /= 2
+	}
+	return proof, nil
+}
+
+// ProofOf returns the proof of the first leaf equal to a value.
+//
+// Parameters:
+// - leaf: the leaf
+// Returns:
+// - []*felt.Felt: the proof
+// - error: ErrLeafNotFound if the leaf is not in the tree
+func (t *Tree) ProofOf(leaf *felt.Felt) ([]*felt.Felt, error) {
+	for i, l := range t.Layers[0] {
+		if l.Equal(leaf) {
+			return t.Proof(i)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrLeafNotFound, leaf)
+}
+
+// VerifyProof checks that a leaf belongs to the tree of a root, as the
+// verify of merkle_proof does on chain.
+//
+// Parameters:
+// - hash: the hash of the tree
+// - root: the root of the tree
+// - leaf: the leaf
+// - proof: the proof of the leaf
+// Returns:
+// - bool: true if the proof leads from the leaf to the root
+func VerifyProof(hash HashFunc, root, leaf *felt.Felt, proof []*felt.Felt) bool {
+	node := leaf
+	for _, sibling := range proof {
+		node = PairHash(hash, node, sibling)
+	}
+	return node.Equal(root)
+}
@@ -0,0 +1,170 @@
+// Package messaging prepares the L1 side of L2 to L1 withdrawals. A withdrawal
+// is an L2 transaction sending a message to an L1 contract, e.g. a StarkGate
+// bridge, which consumes it with consumeMessageFromL2 of the Starknet core
+// contract once the block of the transaction is proven on L1.
+//
+// The package reads the messages of a withdrawal from its receipt, computes
+// their hashes, encodes the calldata of consumeMessageFromL2, and checks
+// whether a message can be consumed with a Caller of the core contract, a
+// small interface that an adapter of go-ethereum's ethclient implements:
+//
+//	type caller struct{ client *ethclient.Client }
+//
+//	func (c caller) CallContract(ctx context.Context, to string, data []byte) ([]byte, error) {
+//		address := common.HexToAddress(to)
+//		return c.client.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+//	}
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/bridge"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// Addresses of the Starknet core contract on Ethereum.
+const (
+	MainnetCoreContract = "0xc662c410C0ECf747543f5bA90660f6ABeBD9C8c4"
+	SepoliaCoreContract = "0xE2Bb56ee936fd6433DC0F6e7e3b8365C906AA057"
+)
+
+var (
+	ErrNoMessage = errors.New("messaging: no message to L1 in the transaction")
+	ErrReverted  = errors.New("messaging: transaction reverted, its messages were not sent")
+)
+
+var (
+	consumeMessageFromL2Selector = utils.Keccak256([]byte("consumeMessageFromL2(uint256,uint256[])"))[:4]
+	l2ToL1MessagesSelector       = utils.Keccak256([]byte("l2ToL1Messages(bytes32)"))[:4]
+)
+
+// Caller calls the view functions of L1 contracts.
+type Caller interface {
+	// CallContract calls the contract at the hexadecimal address to with the
+	// ABI encoded data, and returns the ABI encoded result
+	CallContract(ctx context.Context, to string, data []byte) ([]byte, error)
+}
+
+// Withdrawal is a message sent to L1 by an L2 transaction.
+type Withdrawal struct {
+	// TransactionHash is the hash of the L2 transaction
+	TransactionHash *felt.Felt
+	// BlockNumber is the block of the L2 transaction, zero while pending
+	BlockNumber uint64
+	// Message is the message sent to L1
+	Message rpc.MsgToL1
+	// Hash is the hash of the message in the core contract
+	Hash []byte
+}
+
+// Withdrawals returns the messages sent to L1 by an L2 transaction.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider of the receipt
+// - txHash: the hash of the L2 transaction
+// Returns:
+// - []Withdrawal: the messages, in the order they were sent
+// - error: ErrReverted or ErrNoMessage, or an error of the provider
+func Withdrawals(ctx context.Context, provider rpc.RpcProvider, txHash *felt.Felt) ([]Withdrawal, error) {
+	receipt, err := provider.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.ExecutionStatus() == rpc.TxnExecutionStatusREVERTED {
+		return nil, fmt.Errorf("%w: %s", ErrReverted, receipt.RevertReason())
+	}
+	messages := receipt.MessagesSent()
+	if len(messages) == 0 {
+		return nil, ErrNoMessage
+	}
+	_, blockNumber := receipt.Block()
+	withdrawals := make([]Withdrawal, len(messages))
+	for i, msg := range messages {
+		withdrawals[i] = Withdrawal{
+			TransactionHash: txHash,
+			BlockNumber:     blockNumber,
+			Message:         msg,
+			Hash:            bridge.L2MessageHash(msg),
+		}
+	}
+	return withdrawals, nil
+}
+
+// Recipient returns the L1 contract the message is sent to, the only contract
+// able to consume it.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the hexadecimal address of the L1 contract
+func (w Withdrawal) Recipient() string {
+	return fmt.Sprintf("0x%040x", utils.FeltToBigInt(w.Message.ToAddress))
+}
+
+// ConsumeCalldata encodes the call of consumeMessageFromL2(fromAddress,
+// payload) consuming the message on the core contract. The call must be sent
+// by the recipient of the message, e.g. by the withdraw function of a bridge.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the ABI encoded calldata
+func (w Withdrawal) ConsumeCalldata() []byte {
+	words := []*big.Int{
+		utils.FeltToBigInt(w.Message.FromAddress),
+		big.NewInt(64),
+		big.NewInt(int64(len(w.Message.Payload))),
+	}
+	for _, element := range w.Message.Payload {
+		words = append(words, utils.FeltToBigInt(element))
+	}
+	return append(append([]byte{}, consumeMessageFromL2Selector...), encodeWords(words)...)
+}
+
+// Consumable returns how many times the message can be consumed on L1, with
+// l2ToL1Messages of the core contract. It is zero until the block of the
+// withdrawal is proven on L1, and after the message is consumed.
+//
+// Parameters:
+// - ctx: the context
+// - caller: the caller of the core contract
+// - coreContract: the address of the core contract, e.g. MainnetCoreContract
+// Returns:
+// - *big.Int: the number of times the message can be consumed
+// - error: an error of the caller, or if its result is not a uint256
+func (w Withdrawal) Consumable(ctx context.Context, caller Caller, coreContract string) (*big.Int, error) {
+	data := append(append([]byte{}, l2ToL1MessagesSelector...), w.Hash...)
+	result, err := caller.CallContract(ctx, coreContract, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) != 32 {
+		return nil, fmt.Errorf("messaging: l2ToL1Messages returned %d bytes", len(result))
+	}
+	return new(big.Int).SetBytes(result), nil
+}
+
+// encodeWords encodes words as 32-byte big-endian integers.
+//
+// Parameters:
+// - words: the words
+// Returns:
+// - []byte: the encoding
+func encodeWords(words []*big.Int) []byte {
+	data := make([]byte, 32*len(words))
+	for i, w := range words {
+		w.FillBytes(data[i*32 : (i+1)*32])
+	}
+	return data
+}
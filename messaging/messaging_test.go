@@ -0,0 +1,93 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/bridge"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// coreContract answers l2ToL1Messages with a count per message hash.
+type coreContract map[string]int64
+
+// CallContract answers l2ToL1Messages.
+//
+// Parameters:
+// - ctx: the context
+// - to: the address of the core contract
+// - data: the calldata
+// Returns:
+// - []byte: the count of the message
+// - error: an error for another call
+func (c coreContract) CallContract(ctx context.Context, to string, data []byte) ([]byte, error) {
+	if to != SepoliaCoreContract || !bytes.Equal(data[:4], l2ToL1MessagesSelector) {
+		return nil, errors.New("unexpected call")
+	}
+	return big.NewInt(c[hex.EncodeToString(data[4:])]).FillBytes(make([]byte, 32)), nil
+}
+
+// TestWithdrawals tests reading the messages of a withdrawal, the calldata
+// consuming them and their status in the core contract.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestWithdrawals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	txHash := utils.TestHexToFelt(t, "0x123")
+	msg := rpc.MsgToL1{
+		FromAddress: utils.TestHexToFelt(t, "0x73314940630fd6dcda0d772d4c972c4e0a9946bef9dabf4ef84eda8ef542b82"),
+		ToAddress:   utils.TestHexToFelt(t, "0xae0ee0a63a2ce6baeeffe56e7714fb4efe48d419"),
+		Payload:     []*felt.Felt{new(felt.Felt), utils.Uint64ToFelt(7)},
+	}
+	provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(&rpc.Receipt{TransactionReceipt: rpc.InvokeTransactionReceipt{
+		TransactionHash: txHash,
+		ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+		BlockNumber:     9,
+		MessagesSent:    []rpc.MsgToL1{msg},
+	}}, nil)
+
+	withdrawals, err := Withdrawals(context.Background(), provider, txHash)
+	require.NoError(t, err)
+	require.Len(t, withdrawals, 1)
+	w := withdrawals[0]
+	require.Equal(t, uint64(9), w.BlockNumber)
+	require.Equal(t, bridge.L2MessageHash(msg), w.Hash)
+	require.Equal(t, "0xae0ee0a63a2ce6baeeffe56e7714fb4efe48d419", w.Recipient())
+	require.Equal(t, "2c9dd5c0"+
+		"073314940630fd6dcda0d772d4c972c4e0a9946bef9dabf4ef84eda8ef542b82"+
+		"0000000000000000000000000000000000000000000000000000000000000040"+
+		"0000000000000000000000000000000000000000000000000000000000000002"+
+		"0000000000000000000000000000000000000000000000000000000000000000"+
+		"0000000000000000000000000000000000000000000000000000000000000007", hex.EncodeToString(w.ConsumeCalldata()))
+
+	count, err := w.Consumable(context.Background(), coreContract{hex.EncodeToString(w.Hash): 1}, SepoliaCoreContract)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1), count)
+
+	provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(&rpc.Receipt{TransactionReceipt: rpc.InvokeTransactionReceipt{
+		ExecutionStatus: rpc.TxnExecutionStatusREVERTED,
+		RevertReason:    "insufficient balance",
+	}}, nil)
+	_, err = Withdrawals(context.Background(), provider, txHash)
+	require.True(t, errors.Is(err, ErrReverted), "unexpected error %v", err)
+
+	provider.EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(&rpc.Receipt{TransactionReceipt: rpc.InvokeTransactionReceipt{
+		ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED,
+	}}, nil)
+	_, err = Withdrawals(context.Background(), provider, txHash)
+	require.Equal(t, ErrNoMessage, err)
+}
@@ -0,0 +1,223 @@
+// Package metrics instruments the RPC clients with Prometheus metrics:
+// requests and errors by method, latency histograms by method and the number
+// of inflight requests. The metrics are collected by an interceptor and
+// exposed as a prometheus.Collector.
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// DefaultNamespace prefixes the names of the metrics.
+const DefaultNamespace = "starknet_rpc"
+
+// Metrics are the metrics of the requests of one or more clients.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	inflight prometheus.Gauge
+}
+
+var _ prometheus.Collector = &Metrics{}
+
+type config struct {
+	namespace string
+	buckets   []float64
+	labels    prometheus.Labels
+}
+
+// Option configures Metrics.
+type Option func(*config)
+
+// WithNamespace sets the prefix of the names of the metrics, DefaultNamespace
+// by default.
+//
+// Parameters:
+// - namespace: the prefix
+// Returns:
+// - Option: the option
+func WithNamespace(namespace string) Option {
+	return func(c *config) {
+		c.namespace = namespace
+	}
+}
+
+// WithBuckets sets the buckets of the latency histograms, in seconds.
+//
+// Parameters:
+// - buckets: the upper bounds of the buckets
+// Returns:
+// - Option: the option
+func WithBuckets(buckets ...float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// WithConstLabels adds labels to every metric, e.g. the name of the node
+// when several providers share a registry.
+//
+// Parameters:
+// - labels: the labels
+// Returns:
+// - Option: the option
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *config) {
+		c.labels = labels
+	}
+}
+
+// New creates the metrics and registers them. When metrics with the same
+// names are already registered, the registered metrics are reused.
+//
+// Parameters:
+// - registerer: the registry, the metrics are not registered if nil
+// - opts: the options
+// Returns:
+// - *Metrics: the metrics
+// - error: an error if the metrics can not be registered
+func New(registerer prometheus.Registerer, opts ...Option) (*Metrics, error) {
+	cfg := config{namespace: DefaultNamespace, buckets: prometheus.DefBuckets}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Name:        "requests_total",
+			Help:        "Number of JSON-RPC requests, by method.",
+			ConstLabels: cfg.labels,
+		}, []string{"method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   cfg.namespace,
+			Name:        "errors_total",
+			Help:        "Number of failed JSON-RPC requests, by method and error code.",
+			ConstLabels: cfg.labels,
+		}, []string{"method", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   cfg.namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Latency of JSON-RPC requests, by method.",
+			Buckets:     cfg.buckets,
+			ConstLabels: cfg.labels,
+		}, []string{"method"}),
+		inflight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   cfg.namespace,
+			Name:        "requests_inflight",
+			Help:        "Number of JSON-RPC requests waiting for a response.",
+			ConstLabels: cfg.labels,
+		}),
+	}
+	if registerer == nil {
+		return m, nil
+	}
+	if err := registerer.Register(m); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			if existing, ok := registered.ExistingCollector.(*Metrics); ok {
+				return existing, nil
+			}
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// WithMetrics instruments an HTTP client with metrics registered in a
+// registry, reusing the metrics already registered in it by another client,
+// as New does.
+//
+// Parameters:
+// - registerer: the registry
+// - opts: the options of the metrics
+// Returns:
+// - rpc.HTTPOption: the option
+// - error: the error of the registration, e.g. metrics of the same names
+// registered with other labels
+func WithMetrics(registerer prometheus.Registerer, opts ...Option) (rpc.HTTPOption, error) {
+	m, err := New(registerer, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return rpc.WithInterceptor(m.Interceptor()), nil
+}
+
+// Interceptor returns the interceptor collecting the metrics, to instrument
+// a client with rpc.WithInterceptor or rpc.Intercept.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - rpc.Interceptor: the interceptor
+func (m *Metrics) Interceptor() rpc.Interceptor {
+	return func(ctx context.Context, method string, params []interface{}, next rpc.Invoker) (json.RawMessage, error) {
+		m.inflight.Inc()
+		start := time.Now()
+		raw, err := next(ctx, method, params)
+		m.latency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		m.inflight.Dec()
+		m.requests.WithLabelValues(method).Inc()
+		if err != nil {
+			m.errors.WithLabelValues(method, errorCode(err)).Inc()
+		}
+		return raw, err
+	}
+}
+
+// Describe sends the descriptors of the metrics.
+//
+// Parameters:
+// - ch: the channel of the descriptors
+// Returns:
+//
+//	none
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.errors.Describe(ch)
+	m.latency.Describe(ch)
+	m.inflight.Describe(ch)
+}
+
+// Collect sends the metrics.
+//
+// Parameters:
+// - ch: the channel of the metrics
+// Returns:
+//
+//	none
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.errors.Collect(ch)
+	m.latency.Collect(ch)
+	m.inflight.Collect(ch)
+}
+
+// errorCode returns the label of an error: the JSON-RPC code of node errors,
+// "timeout" or "canceled" for context errors, and "transport" otherwise.
+//
+// Parameters:
+// - err: the error
+// Returns:
+// - string: the label
+func errorCode(err error) string {
+	var rpcErr *rpc.RPCError
+	switch {
+	case errors.As(err, &rpcErr):
+		return strconv.Itoa(rpcErr.Code())
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	}
+	return "transport"
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// TestMetrics tests the request, error and latency metrics collected from a
+// provider, the reuse of metrics registered twice, and the error of metrics
+// conflicting with the metrics of a registry.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Method == "starknet_blockNumber" {
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": 42}`, req.ID)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "error": {"code": 24, "message": "Block not found"}}`, req.ID)
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	option, err := WithMetrics(registry)
+	require.NoError(t, err)
+	provider, err := rpc.NewHTTPProvider(server.URL, option)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err := provider.BlockNumber(context.Background())
+		require.NoError(t, err)
+	}
+	_, err = provider.BlockTransactionCount(context.Background(), rpc.WithBlockNumber(1))
+	require.Error(t, err)
+
+	m, err := New(registry)
+	require.NoError(t, err)
+	require.Equal(t, 3.0, testutil.ToFloat64(m.requests.WithLabelValues("starknet_blockNumber")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues("starknet_getBlockTransactionCount")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.errors.WithLabelValues("starknet_getBlockTransactionCount", "24")))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.inflight))
+	require.Equal(t, 2, testutil.CollectAndCount(m.latency))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	names := []string{}
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	require.Equal(t, []string{"starknet_rpc_errors_total", "starknet_rpc_request_duration_seconds",
+		"starknet_rpc_requests_inflight", "starknet_rpc_requests_total"}, names)
+	require.Equal(t, "canceled", errorCode(context.Canceled))
+
+	_, err = WithMetrics(registry)
+	require.NoError(t, err)
+	_, err = WithMetrics(registry, WithConstLabels(prometheus.Labels{"node": "backup"}))
+	require.Error(t, err)
+}
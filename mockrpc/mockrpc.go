@@ -0,0 +1,411 @@
+// Package mockrpc provides a JSON-RPC server answering with canned responses,
+// for deterministic tests of code built on a Provider. Responses are scripted
+// in Go, loaded from fixture files, or replayed from exchanges captured from
+// a real node with a Recorder. The server records the requests it receives
+// for assertions.
+package mockrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// Error is the error of a JSON-RPC response.
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error returns the message of the error.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the message
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Exchange is a request and its response. An exchange without Params
+// answers any request to its method.
+type Exchange struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Params json.RawMessage
+}
+
+// HandlerFunc answers a request with a result encoded to JSON, or an error.
+// Errors other than *Error are answered as internal errors.
+type HandlerFunc func(params json.RawMessage) (any, error)
+
+// script is an exchange and whether it was answered.
+type script struct {
+	Exchange
+	params any
+	used   bool
+}
+
+// Server is a JSON-RPC server answering with canned responses. Exchanges of
+// a method are answered in order, the last matching exchange answering all
+// following requests.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	scripts  map[string][]*script
+	handlers map[string]HandlerFunc
+	requests []Request
+}
+
+// NewServer starts a server without responses. It is stopped by Close.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *Server: the server
+func NewServer() *Server {
+	s := &Server{
+		scripts:  map[string][]*script{},
+		handlers: map[string]HandlerFunc{},
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Provider returns a provider connected to the server.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *rpc.Provider: the provider
+func (s *Server) Provider() *rpc.Provider {
+	return rpc.NewProvider(rpc.NewHTTPClient(s.URL, rpc.WithHTTPClient(s.Client())))
+}
+
+// Add appends exchanges to the responses of the server.
+//
+// Parameters:
+// - exchanges: the exchanges
+// Returns:
+// - error: an error if the params of an exchange are not valid JSON
+func (s *Server) Add(exchanges ...Exchange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, exchange := range exchanges {
+		sc := &script{Exchange: exchange}
+		if len(exchange.Params) > 0 {
+			if err := json.Unmarshal(exchange.Params, &sc.params); err != nil {
+				return fmt.Errorf("mockrpc: params of %s: %w", exchange.Method, err)
+			}
+		}
+		s.scripts[exchange.Method] = append(s.scripts[exchange.Method], sc)
+	}
+	return nil
+}
+
+// Handle answers requests to a method with a result.
+//
+// Parameters:
+// - method: the method
+// - result: the result, encoded to JSON
+// Returns:
+// - error: an error if the result can not be encoded
+func (s *Server) Handle(method string, result any) error {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return s.Add(Exchange{Method: method, Result: raw})
+}
+
+// HandleError answers requests to a method with an error.
+//
+// Parameters:
+// - method: the method
+// - code: the code of the error, e.g. 20 for a contract not found
+// - message: the message of the error
+// - data: the data of the error, encoded to JSON, omitted if nil
+// Returns:
+// - error: an error if the data can not be encoded
+func (s *Server) HandleError(method string, code int, message string, data any) error {
+	e := &Error{Code: code, Message: message}
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		e.Data = raw
+	}
+	return s.Add(Exchange{Method: method, Error: e})
+}
+
+// HandleFunc answers requests to a method with a function. Functions take
+// precedence over exchanges.
+//
+// Parameters:
+// - method: the method
+// - fn: the function
+// Returns:
+//
+//	none
+func (s *Server) HandleFunc(method string, fn HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = fn
+}
+
+// Load adds the exchanges of a fixture file, holding an exchange or an array
+// of exchanges as saved by Recorder.Save.
+//
+// Parameters:
+// - path: the path of the file
+// Returns:
+// - error: an error if the file can not be read or decoded
+func (s *Server) Load(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var exchanges []Exchange
+	if err := json.Unmarshal(content, &exchanges); err != nil {
+		var exchange Exchange
+		if err := json.Unmarshal(content, &exchange); err != nil {
+			return fmt.Errorf("mockrpc: %s: %w", path, err)
+		}
+		exchanges = []Exchange{exchange}
+	}
+	return s.Add(exchanges...)
+}
+
+// LoadDir adds the exchanges of the .json fixture files of a directory, in
+// the order of their names.
+//
+// Parameters:
+// - dir: the directory
+// Returns:
+// - error: an error if a file can not be read or decoded
+func (s *Server) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if err := s.Load(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Requests returns the requests received by the server, in order.
+//
+// Parameters:
+// - methods: the methods to return, all if none
+// Returns:
+// - []Request: the requests
+func (s *Server) Requests(methods ...string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var requests []Request
+	for _, req := range s.requests {
+		if len(methods) == 0 || contains(methods, req.Method) {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+// Reset forgets the received requests and rewinds the exchanges.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	for _, scripts := range s.scripts {
+		for _, sc := range scripts {
+			sc.used = false
+		}
+	}
+}
+
+// jsonrpcMessage is a JSON-RPC request or response.
+type jsonrpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// ServeHTTP answers a JSON-RPC request or batch.
+//
+// Parameters:
+// - w: the response writer
+// - r: the request
+// Returns:
+//
+//	none
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	var batch []jsonrpcMessage
+	if err := json.Unmarshal(body, &batch); err == nil {
+		responses := make([]jsonrpcMessage, len(batch))
+		for i, req := range batch {
+			responses[i] = s.answer(req)
+		}
+		_ = json.NewEncoder(w).Encode(responses)
+		return
+	}
+	var req jsonrpcMessage
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(s.answer(req))
+}
+
+// answer records a request and builds its response.
+//
+// Parameters:
+// - req: the request
+// Returns:
+// - jsonrpcMessage: the response
+func (s *Server) answer(req jsonrpcMessage) jsonrpcMessage {
+	resp := jsonrpcMessage{JSONRPC: "2.0", ID: req.ID}
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: req.Method, Params: req.Params})
+	fn := s.handlers[req.Method]
+	var exchange *Exchange
+	if fn == nil {
+		exchange = s.next(req)
+	}
+	s.mu.Unlock()
+
+	switch {
+	case fn != nil:
+		result, err := fn(req.Params)
+		if err != nil {
+			resp.Error = toError(err)
+			return resp
+		}
+		raw, err := json.Marshal(result)
+		if err != nil {
+			resp.Error = toError(err)
+			return resp
+		}
+		resp.Result = raw
+	case exchange != nil:
+		resp.Result, resp.Error = exchange.Result, exchange.Error
+		if resp.Result == nil && resp.Error == nil {
+			resp.Result = json.RawMessage("null")
+		}
+	default:
+		resp.Error = &Error{Code: rpc.MethodNotFound, Message: fmt.Sprintf("mockrpc: no response for %s %s", req.Method, req.Params)}
+	}
+	return resp
+}
+
+// next returns the exchange answering a request. s.mu must be held.
+//
+// Parameters:
+// - req: the request
+// Returns:
+// - *Exchange: the exchange, nil if none matches
+func (s *Server) next(req jsonrpcMessage) *Exchange {
+	var params any
+	if len(req.Params) > 0 {
+		_ = json.Unmarshal(req.Params, &params)
+	}
+	var last *script
+	for _, sc := range s.scripts[req.Method] {
+		if sc.params != nil && !reflect.DeepEqual(sc.params, params) {
+			continue
+		}
+		if !sc.used {
+			sc.used = true
+			return &sc.Exchange
+		}
+		last = sc
+	}
+	if last == nil {
+		return nil
+	}
+	return &last.Exchange
+}
+
+// toError converts an error to the error of a response.
+//
+// Parameters:
+// - err: the error
+// Returns:
+// - *Error: the error of the response
+func toError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	var rpcErr *rpc.RPCError
+	if errors.As(err, &rpcErr) {
+		e = &Error{Code: rpcErr.Code(), Message: rpcErr.Error()}
+		if rpcErr.Data() != nil {
+			e.Data, _ = json.Marshal(rpcErr.Data())
+		}
+		return e
+	}
+	return &Error{Code: rpc.InternalError, Message: err.Error()}
+}
+
+// contains reports whether a method is in a list.
+//
+// Parameters:
+// - methods: the list
+// - method: the method
+// Returns:
+// - bool: true if the method is in the list
+func contains(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package mockrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestServer tests scripted responses, matching on params, errors and the
+// recording of requests, through an account.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	address := utils.TestHexToFelt(t, "0xacc")
+	require.NoError(t, server.Handle("starknet_chainId", "0x534e5f5345504f4c4941"))
+	require.NoError(t, server.Add(
+		Exchange{Method: "starknet_getNonce", Params: json.RawMessage(`["latest", "0xacc"]`), Result: json.RawMessage(`"0x7"`)},
+		Exchange{Method: "starknet_getNonce", Params: json.RawMessage(`["latest", "0xacc"]`), Result: json.RawMessage(`"0x8"`)},
+	))
+	require.NoError(t, server.HandleError("starknet_getClassHashAt", 20, "Contract not found", nil))
+	blocks := uint64(0)
+	server.HandleFunc("starknet_blockNumber", func(json.RawMessage) (any, error) {
+		blocks++
+		return blocks, nil
+	})
+
+	_, pub, _ := account.GetRandomKeys()
+	acc, err := account.NewAccount(server.Provider(), address, pub.String(), account.NewMemKeystore(), 0)
+	require.NoError(t, err)
+	require.Equal(t, "0x534e5f5345504f4c4941", acc.ChainId.String())
+	for _, expected := range []uint64{7, 8, 8} {
+		nonce, err := acc.Nonce(context.Background(), rpc.WithBlockTag("latest"), address)
+		require.NoError(t, err)
+		require.Equal(t, utils.Uint64ToFelt(expected), nonce)
+	}
+	_, err = acc.Nonce(context.Background(), rpc.WithBlockTag("latest"), utils.TestHexToFelt(t, "0xb0b"))
+	require.Error(t, err)
+	_, err = acc.ClassHashAt(context.Background(), rpc.WithBlockTag("latest"), address)
+	require.True(t, errors.Is(err, rpc.ErrContractNotFound))
+	for _, expected := range []uint64{1, 2} {
+		number, err := acc.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, expected, number)
+	}
+
+	nonces := server.Requests("starknet_getNonce")
+	require.Len(t, nonces, 4)
+	require.JSONEq(t, `["latest", "0xb0b"]`, string(nonces[3].Params))
+	// the failed nonce request looks up the class for the Cairo 0 fallback
+	require.Len(t, server.Requests("starknet_getClassAt"), 1)
+	require.Len(t, server.Requests(), 9)
+	server.Reset()
+	require.Empty(t, server.Requests())
+}
+
+// TestRecorder tests the replay of exchanges captured by a recorder.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestRecorder(t *testing.T) {
+	upstream := NewServer()
+	defer upstream.Close()
+	require.NoError(t, upstream.Handle("starknet_blockNumber", 1234))
+	require.NoError(t, upstream.Handle("starknet_getClassHashAt", "0xc1a55"))
+	require.NoError(t, upstream.HandleError("starknet_getNonce", 20, "Contract not found", nil))
+
+	recorder := NewRecorder(rpc.NewHTTPClient(upstream.URL))
+	calls := func(provider *rpc.Provider) (uint64, string, error) {
+		number, err := provider.BlockNumber(context.Background())
+		require.NoError(t, err)
+		classHash, err := provider.ClassHashAt(context.Background(), rpc.WithBlockNumber(number), utils.TestHexToFelt(t, "0xc0de"))
+		require.NoError(t, err)
+		_, err = provider.Nonce(context.Background(), rpc.WithBlockNumber(number), utils.TestHexToFelt(t, "0xc0de"))
+		return number, classHash.String(), err
+	}
+	number, classHash, nonceErr := calls(rpc.NewProvider(recorder))
+	require.Len(t, recorder.Exchanges(), 3)
+
+	path := filepath.Join(t.TempDir(), "recording.json")
+	require.NoError(t, recorder.Save(path))
+	replay := NewServer()
+	defer replay.Close()
+	require.NoError(t, replay.LoadDir(filepath.Dir(path)))
+	replayedNumber, replayedClassHash, replayedErr := calls(replay.Provider())
+	require.Equal(t, number, replayedNumber)
+	require.Equal(t, classHash, replayedClassHash)
+	require.True(t, errors.Is(nonceErr, rpc.ErrContractNotFound))
+	require.True(t, errors.Is(replayedErr, rpc.ErrContractNotFound))
+
+	_, err := replay.Provider().ClassHashAt(context.Background(), rpc.WithBlockNumber(1), utils.TestHexToFelt(t, "0xc0de"))
+	require.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package mockrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// Recorder is a CallCloser forwarding requests to a client, e.g. connected to
+// mainnet, and capturing the exchanges to be replayed by a Server.
+type Recorder struct {
+	c rpc.CallCloser
+
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+var _ rpc.CallCloser = &Recorder{}
+
+// NewRecorder creates a recorder forwarding requests to a client.
+//
+// Parameters:
+// - c: the client
+// Returns:
+// - *Recorder: the recorder
+func NewRecorder(c rpc.CallCloser) *Recorder {
+	return &Recorder{c: c}
+}
+
+// CallContext forwards a request and captures its response.
+//
+// Parameters:
+// - ctx: the context
+// - result: the value the result is decoded into
+// - method: the method
+// - args: the parameters
+// Returns:
+// - error: the error of the client
+func (r *Recorder) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+	params, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	var raw json.RawMessage
+	callErr := r.c.CallContext(ctx, &raw, method, args...)
+	exchange := Exchange{Method: method, Params: params, Result: raw}
+	if callErr != nil {
+		var rpcErr *rpc.RPCError
+		var e *Error
+		if !errors.As(callErr, &rpcErr) && !errors.As(callErr, &e) {
+			// transport errors are not part of the conversation with the node
+			return callErr
+		}
+		exchange.Result, exchange.Error = nil, toError(callErr)
+	}
+	r.mu.Lock()
+	r.exchanges = append(r.exchanges, exchange)
+	r.mu.Unlock()
+	if callErr != nil {
+		return callErr
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// Close closes the client.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (r *Recorder) Close() {
+	r.c.Close()
+}
+
+// Exchanges returns the captured exchanges, in order.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []Exchange: the exchanges
+func (r *Recorder) Exchanges() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Exchange(nil), r.exchanges...)
+}
+
+// Save writes the captured exchanges to a fixture file loadable by
+// Server.Load.
+//
+// Parameters:
+// - path: the path of the file
+// Returns:
+// - error: an error if the file can not be written
+func (r *Recorder) Save(path string) error {
+	content, err := json.MarshalIndent(r.Exchanges(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
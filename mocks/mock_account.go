@@ -159,10 +159,10 @@ func (mr *MockAccountInterfaceMockRecorder) TransactionHashInvoke(invokeTxn any)
 }
 
 // WaitForTransactionReceipt mocks base method.
-func (m *MockAccountInterface) WaitForTransactionReceipt(ctx context.Context, transactionHash *felt.Felt, pollInterval time.Duration) (*rpc.TransactionReceipt, error) {
+func (m *MockAccountInterface) WaitForTransactionReceipt(ctx context.Context, transactionHash *felt.Felt, pollInterval time.Duration) (*rpc.Receipt, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "WaitForTransactionReceipt", ctx, transactionHash, pollInterval)
-	ret0, _ := ret[0].(*rpc.TransactionReceipt)
+	ret0, _ := ret[0].(*rpc.Receipt)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 
 	felt "github.com/NethermindEth/juno/core/felt"
+	contracts "github.com/xiang-xx/starknet.go/contracts"
 	rpc "github.com/xiang-xx/starknet.go/rpc"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -131,10 +132,10 @@ func (mr *MockRpcProviderMockRecorder) BlockTransactionCount(ctx, blockID any) *
 }
 
 // BlockWithTxHashes mocks base method.
-func (m *MockRpcProvider) BlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (any, error) {
+func (m *MockRpcProvider) BlockWithTxHashes(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockTxHashesResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "BlockWithTxHashes", ctx, blockID)
-	ret0, _ := ret[0].(any)
+	ret0, _ := ret[0].(*rpc.BlockTxHashesResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -145,11 +146,26 @@ func (mr *MockRpcProviderMockRecorder) BlockWithTxHashes(ctx, blockID any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockWithTxHashes", reflect.TypeOf((*MockRpcProvider)(nil).BlockWithTxHashes), ctx, blockID)
 }
 
+// BlockWithReceipts mocks base method.
+func (m *MockRpcProvider) BlockWithReceipts(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockWithReceiptsResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockWithReceipts", ctx, blockID)
+	ret0, _ := ret[0].(*rpc.BlockWithReceiptsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockWithReceipts indicates an expected call of BlockWithReceipts.
+func (mr *MockRpcProviderMockRecorder) BlockWithReceipts(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockWithReceipts", reflect.TypeOf((*MockRpcProvider)(nil).BlockWithReceipts), ctx, blockID)
+}
+
 // BlockWithTxs mocks base method.
-func (m *MockRpcProvider) BlockWithTxs(ctx context.Context, blockID rpc.BlockID) (any, error) {
+func (m *MockRpcProvider) BlockWithTxs(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "BlockWithTxs", ctx, blockID)
-	ret0, _ := ret[0].(any)
+	ret0, _ := ret[0].(*rpc.BlockResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -175,6 +191,21 @@ func (mr *MockRpcProviderMockRecorder) Call(ctx, call, block any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockRpcProvider)(nil).Call), ctx, call, block)
 }
 
+// CallWithStateOverrides mocks base method.
+func (m *MockRpcProvider) CallWithStateOverrides(ctx context.Context, call rpc.FunctionCall, block rpc.BlockID, overrides []rpc.StateOverride) ([]*felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CallWithStateOverrides", ctx, call, block, overrides)
+	ret0, _ := ret[0].([]*felt.Felt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CallWithStateOverrides indicates an expected call of CallWithStateOverrides.
+func (mr *MockRpcProviderMockRecorder) CallWithStateOverrides(ctx, call, block, overrides any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallWithStateOverrides", reflect.TypeOf((*MockRpcProvider)(nil).CallWithStateOverrides), ctx, call, block, overrides)
+}
+
 // ChainID mocks base method.
 func (m *MockRpcProvider) ChainID(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -235,6 +266,21 @@ func (mr *MockRpcProviderMockRecorder) ClassHashAt(ctx, blockID, contractAddress
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClassHashAt", reflect.TypeOf((*MockRpcProvider)(nil).ClassHashAt), ctx, blockID, contractAddress)
 }
 
+// CompiledCasm mocks base method.
+func (m *MockRpcProvider) CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompiledCasm", ctx, classHash)
+	ret0, _ := ret[0].(*contracts.CasmClass)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompiledCasm indicates an expected call of CompiledCasm.
+func (mr *MockRpcProviderMockRecorder) CompiledCasm(ctx, classHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompiledCasm", reflect.TypeOf((*MockRpcProvider)(nil).CompiledCasm), ctx, classHash)
+}
+
 // EstimateFee mocks base method.
 func (m *MockRpcProvider) EstimateFee(ctx context.Context, requests []rpc.BroadcastTxn, simulationFlags []rpc.SimulationFlag, blockID rpc.BlockID) ([]rpc.FeeEstimate, error) {
 	m.ctrl.T.Helper()
@@ -280,6 +326,36 @@ func (mr *MockRpcProviderMockRecorder) Events(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockRpcProvider)(nil).Events), ctx, input)
 }
 
+// FeeHistory mocks base method.
+func (m *MockRpcProvider) FeeHistory(ctx context.Context, n int) (rpc.FeeHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FeeHistory", ctx, n)
+	ret0, _ := ret[0].(rpc.FeeHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FeeHistory indicates an expected call of FeeHistory.
+func (mr *MockRpcProviderMockRecorder) FeeHistory(ctx, n any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeeHistory", reflect.TypeOf((*MockRpcProvider)(nil).FeeHistory), ctx, n)
+}
+
+// GasPrices mocks base method.
+func (m *MockRpcProvider) GasPrices(ctx context.Context, blockID rpc.BlockID) (*rpc.BlockGasPrices, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GasPrices", ctx, blockID)
+	ret0, _ := ret[0].(*rpc.BlockGasPrices)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GasPrices indicates an expected call of GasPrices.
+func (mr *MockRpcProviderMockRecorder) GasPrices(ctx, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GasPrices", reflect.TypeOf((*MockRpcProvider)(nil).GasPrices), ctx, blockID)
+}
+
 // GetTransactionStatus mocks base method.
 func (m *MockRpcProvider) GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*rpc.TxnStatusResp, error) {
 	m.ctrl.T.Helper()
@@ -295,6 +371,21 @@ func (mr *MockRpcProviderMockRecorder) GetTransactionStatus(ctx, transactionHash
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionStatus", reflect.TypeOf((*MockRpcProvider)(nil).GetTransactionStatus), ctx, transactionHash)
 }
 
+// MessagesStatus mocks base method.
+func (m *MockRpcProvider) MessagesStatus(ctx context.Context, l1TransactionHash rpc.NumAsHex) ([]rpc.MessageStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MessagesStatus", ctx, l1TransactionHash)
+	ret0, _ := ret[0].([]rpc.MessageStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MessagesStatus indicates an expected call of MessagesStatus.
+func (mr *MockRpcProviderMockRecorder) MessagesStatus(ctx, l1TransactionHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MessagesStatus", reflect.TypeOf((*MockRpcProvider)(nil).MessagesStatus), ctx, l1TransactionHash)
+}
+
 // Nonce mocks base method.
 func (m *MockRpcProvider) Nonce(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error) {
 	m.ctrl.T.Helper()
@@ -325,6 +416,21 @@ func (mr *MockRpcProviderMockRecorder) SimulateTransactions(ctx, blockID, txns,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateTransactions", reflect.TypeOf((*MockRpcProvider)(nil).SimulateTransactions), ctx, blockID, txns, simulationFlags)
 }
 
+// SimulateTransactionsWithStateOverrides mocks base method.
+func (m *MockRpcProvider) SimulateTransactionsWithStateOverrides(ctx context.Context, blockID rpc.BlockID, txns []rpc.Transaction, simulationFlags []rpc.SimulationFlag, overrides []rpc.StateOverride) ([]rpc.SimulatedTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimulateTransactionsWithStateOverrides", ctx, blockID, txns, simulationFlags, overrides)
+	ret0, _ := ret[0].([]rpc.SimulatedTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimulateTransactionsWithStateOverrides indicates an expected call of SimulateTransactionsWithStateOverrides.
+func (mr *MockRpcProviderMockRecorder) SimulateTransactionsWithStateOverrides(ctx, blockID, txns, simulationFlags, overrides any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimulateTransactionsWithStateOverrides", reflect.TypeOf((*MockRpcProvider)(nil).SimulateTransactionsWithStateOverrides), ctx, blockID, txns, simulationFlags, overrides)
+}
+
 // SpecVersion mocks base method.
 func (m *MockRpcProvider) SpecVersion(ctx context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -370,6 +476,66 @@ func (mr *MockRpcProviderMockRecorder) StorageAt(ctx, contractAddress, key, bloc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageAt", reflect.TypeOf((*MockRpcProvider)(nil).StorageAt), ctx, contractAddress, key, blockID)
 }
 
+// StorageAtKeys mocks base method.
+func (m *MockRpcProvider) StorageAtKeys(ctx context.Context, contractAddress *felt.Felt, keys []*felt.Felt, blockID rpc.BlockID) (map[felt.Felt]*felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorageAtKeys", ctx, contractAddress, keys, blockID)
+	ret0, _ := ret[0].(map[felt.Felt]*felt.Felt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorageAtKeys indicates an expected call of StorageAtKeys.
+func (mr *MockRpcProviderMockRecorder) StorageAtKeys(ctx, contractAddress, keys, blockID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageAtKeys", reflect.TypeOf((*MockRpcProvider)(nil).StorageAtKeys), ctx, contractAddress, keys, blockID)
+}
+
+// StorageProof mocks base method.
+func (m *MockRpcProvider) StorageProof(ctx context.Context, input rpc.StorageProofInput) (*rpc.StorageProofResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorageProof", ctx, input)
+	ret0, _ := ret[0].(*rpc.StorageProofResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorageProof indicates an expected call of StorageProof.
+func (mr *MockRpcProviderMockRecorder) StorageProof(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageProof", reflect.TypeOf((*MockRpcProvider)(nil).StorageProof), ctx, input)
+}
+
+// StreamEvents mocks base method.
+func (m *MockRpcProvider) StreamEvents(ctx context.Context, contracts []*felt.Felt, keys [][]*felt.Felt, fromBlock uint64) (<-chan rpc.StreamedEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamEvents", ctx, contracts, keys, fromBlock)
+	ret0, _ := ret[0].(<-chan rpc.StreamedEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamEvents indicates an expected call of StreamEvents.
+func (mr *MockRpcProviderMockRecorder) StreamEvents(ctx, contracts, keys, fromBlock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamEvents", reflect.TypeOf((*MockRpcProvider)(nil).StreamEvents), ctx, contracts, keys, fromBlock)
+}
+
+// SubscribeTransactionStatus mocks base method.
+func (m *MockRpcProvider) SubscribeTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (<-chan rpc.TxnStatusUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeTransactionStatus", ctx, transactionHash)
+	ret0, _ := ret[0].(<-chan rpc.TxnStatusUpdate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeTransactionStatus indicates an expected call of SubscribeTransactionStatus.
+func (mr *MockRpcProviderMockRecorder) SubscribeTransactionStatus(ctx, transactionHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeTransactionStatus", reflect.TypeOf((*MockRpcProvider)(nil).SubscribeTransactionStatus), ctx, transactionHash)
+}
+
 // Syncing mocks base method.
 func (m *MockRpcProvider) Syncing(ctx context.Context) (*rpc.SyncStatus, error) {
 	m.ctrl.T.Helper()
@@ -446,10 +612,10 @@ func (mr *MockRpcProviderMockRecorder) TransactionByHash(ctx, hash any) *gomock.
 }
 
 // TransactionReceipt mocks base method.
-func (m *MockRpcProvider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (rpc.TransactionReceipt, error) {
+func (m *MockRpcProvider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*rpc.Receipt, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "TransactionReceipt", ctx, transactionHash)
-	ret0, _ := ret[0].(rpc.TransactionReceipt)
+	ret0, _ := ret[0].(*rpc.Receipt)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -0,0 +1,383 @@
+// Package multisig coordinates the signing of a transaction by the owners of
+// a multisig account: a Collector holds the transaction being built, collects
+// the signatures of the owners over its hash, and once the threshold is
+// reached assembles them in the signature format of the account class and
+// submits the transaction.
+//
+//	ms, _ := signers.ReadArgentMultisig(ctx, provider, account, rpc.WithBlockTag("latest"))
+//	c, err := multisig.NewCollector(builder, ms.Signers, ms.Threshold, multisig.FormatArgent)
+//	err = c.Collect(ctx, owner1, owner2)
+//	txHash, err := c.Submit(ctx, provider)
+//
+// Signatures made elsewhere, e.g. by owners on other machines signing c.Hash(),
+// are added with Add.
+package multisig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/curve"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/txbuilder"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var (
+	ErrInvalidThreshold       = errors.New("multisig: invalid threshold")
+	ErrUnknownSigner          = errors.New("multisig: not a signer of the account")
+	ErrInvalidSignature       = errors.New("multisig: invalid signature")
+	ErrThresholdNotReached    = errors.New("multisig: threshold not reached")
+	ErrUnsupportedFormat      = errors.New("multisig: unsupported signature format")
+	ErrUnsupportedTransaction = errors.New("multisig: unsupported transaction")
+)
+
+// Format is the signature format of a multisig account class.
+type Format int
+
+const (
+	// FormatArgent is the format of the Argent multisig v0.2 and later, the
+	// serialized Array<SignerSignature>: the number of signatures, then for
+	// each one the Starknet signer variant 0, the public key, r and s, in
+	// ascending order of signer GUID
+	FormatArgent Format = iota
+	// FormatArgentLegacy is the format of the Argent multisig v0.1, the
+	// public key, r and s of each signature, in ascending order of public key
+	FormatArgentLegacy
+)
+
+// starknetSignerGUID is the prefix of the GUID of the Starknet signers of
+// Argent accounts, the short string "Starknet Signer"
+var starknetSignerGUID = new(felt.Felt).SetBytes([]byte("Starknet Signer"))
+
+// Signer signs transaction hashes for one owner of a multisig account.
+type Signer interface {
+	// PublicKey returns the public key of the owner, as stored by the account
+	PublicKey() *felt.Felt
+	// SignHash signs a transaction hash
+	SignHash(ctx context.Context, hash *felt.Felt) (r, s *felt.Felt, err error)
+}
+
+// Signature is the signature of a transaction hash by one owner.
+type Signature struct {
+	Signer *felt.Felt
+	R      *felt.Felt
+	S      *felt.Felt
+}
+
+// keystoreSigner is a Signer with a key of a keystore.
+type keystoreSigner struct {
+	ks        account.Keystore
+	publicKey *felt.Felt
+}
+
+// KeystoreSigner returns a Signer signing with a key of a keystore.
+//
+// Parameters:
+// - ks: the keystore
+// - publicKey: the public key of the owner, the ID of the key in the keystore
+// Returns:
+// - Signer: the signer
+func KeystoreSigner(ks account.Keystore, publicKey *felt.Felt) Signer {
+	return &keystoreSigner{ks: ks, publicKey: publicKey}
+}
+
+// PublicKey returns the public key of the owner.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the public key
+func (k *keystoreSigner) PublicKey() *felt.Felt {
+	return k.publicKey
+}
+
+// SignHash signs a transaction hash with the keystore.
+//
+// Parameters:
+// - ctx: the context
+// - hash: the transaction hash
+// Returns:
+// - r, s: the signature
+// - error: an error of the keystore
+func (k *keystoreSigner) SignHash(ctx context.Context, hash *felt.Felt) (*felt.Felt, *felt.Felt, error) {
+	r, s, err := k.ks.Sign(ctx, k.publicKey.String(), utils.FeltToBigInt(hash))
+	if err != nil {
+		return nil, nil, err
+	}
+	return utils.BigIntToFelt(r), utils.BigIntToFelt(s), nil
+}
+
+// Collector collects the signatures of the owners of a multisig account over
+// the hash of a transaction. It is safe for concurrent use.
+type Collector struct {
+	builder   *txbuilder.Builder
+	hash      *felt.Felt
+	signers   map[[32]byte]bool
+	threshold uint64
+	format    Format
+
+	mu         sync.Mutex
+	signatures map[[32]byte]Signature
+}
+
+// NewCollector starts collecting signatures for a transaction. The
+// transaction, its nonce and resource bounds included, must be complete: the
+// owners sign its hash.
+//
+// Parameters:
+// - builder: the transaction, sent by the multisig account
+// - signers: the public keys of the owners, e.g. from signers.ReadArgentMultisig
+// - threshold: the number of signatures the account requires
+// - format: the signature format of the account class
+// Returns:
+// - *Collector: the collector
+// - error: an error of the builder, ErrInvalidThreshold or ErrUnsupportedFormat
+func NewCollector(builder *txbuilder.Builder, signers []*felt.Felt, threshold uint64, format Format) (*Collector, error) {
+	if format != FormatArgent && format != FormatArgentLegacy {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedFormat, format)
+	}
+	if threshold == 0 || threshold > uint64(len(signers)) {
+		return nil, fmt.Errorf("%w: %d of %d signers", ErrInvalidThreshold, threshold, len(signers))
+	}
+	hash, err := builder.Hash()
+	if err != nil {
+		return nil, err
+	}
+	c := &Collector{
+		builder:    builder,
+		hash:       hash,
+		signers:    make(map[[32]byte]bool, len(signers)),
+		threshold:  threshold,
+		format:     format,
+		signatures: make(map[[32]byte]Signature),
+	}
+	for _, signer := range signers {
+		c.signers[signer.Bytes()] = true
+	}
+	return c, nil
+}
+
+// Hash returns the transaction hash the owners sign.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the hash
+func (c *Collector) Hash() *felt.Felt {
+	return new(felt.Felt).Set(c.hash)
+}
+
+// Add adds the signature of an owner, after checking it. A second signature of
+// the same owner replaces the first.
+//
+// Parameters:
+// - sig: the signature
+// Returns:
+// - error: ErrUnknownSigner or ErrInvalidSignature
+func (c *Collector) Add(sig Signature) error {
+	if sig.Signer == nil || !c.signers[sig.Signer.Bytes()] {
+		return fmt.Errorf("%w: %s", ErrUnknownSigner, sig.Signer)
+	}
+	if sig.R == nil || sig.S == nil {
+		return fmt.Errorf("%w: signer %s", ErrInvalidSignature, sig.Signer)
+	}
+	valid, err := curve.Curve.VerifyBatch(
+		[]*big.Int{utils.FeltToBigInt(c.hash)},
+		[]*big.Int{utils.FeltToBigInt(sig.R)},
+		[]*big.Int{utils.FeltToBigInt(sig.S)},
+		[]*big.Int{utils.FeltToBigInt(sig.Signer)},
+	)
+	if err != nil || !valid[0] {
+		return fmt.Errorf("%w: signer %s", ErrInvalidSignature, sig.Signer)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.signatures[sig.Signer.Bytes()] = sig
+	return nil
+}
+
+// Collect asks signers for their signature of the transaction hash, one after
+// the other, and adds them.
+//
+// Parameters:
+// - ctx: the context
+// - signers: the signers
+// Returns:
+// - error: the first error of a signer or of Add
+func (c *Collector) Collect(ctx context.Context, signers ...Signer) error {
+	for _, signer := range signers {
+		r, s, err := signer.SignHash(ctx, c.Hash())
+		if err != nil {
+			return fmt.Errorf("multisig: signer %s: %w", signer.PublicKey(), err)
+		}
+		if err := c.Add(Signature{Signer: signer.PublicKey(), R: r, S: s}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ready reports whether the threshold is reached.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if enough owners signed
+func (c *Collector) Ready() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return uint64(len(c.signatures)) >= c.threshold
+}
+
+// Signature assembles the signature of the account from the first signatures
+// in the order of the format, exactly threshold of them.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*felt.Felt: the signature of the transaction
+// - error: ErrThresholdNotReached if too few owners signed
+func (c *Collector) Signature() ([]*felt.Felt, error) {
+	c.mu.Lock()
+	sigs := make([]Signature, 0, len(c.signatures))
+	for _, sig := range c.signatures {
+		sigs = append(sigs, sig)
+	}
+	c.mu.Unlock()
+	if uint64(len(sigs)) < c.threshold {
+		return nil, fmt.Errorf("%w: %d of %d signatures", ErrThresholdNotReached, len(sigs), c.threshold)
+	}
+	return Encode(c.format, ordered(c.format, sigs)[:c.threshold]), nil
+}
+
+// Submit assembles the signature and broadcasts the transaction.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// Returns:
+// - *felt.Felt: the hash of the transaction
+// - error: ErrThresholdNotReached, or an error of the builder or the provider
+func (c *Collector) Submit(ctx context.Context, provider rpc.RpcProvider) (*felt.Felt, error) {
+	signature, err := c.Signature()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := c.builder.SetSignature(signature...).Transaction()
+	if err != nil {
+		return nil, err
+	}
+	switch tx := tx.(type) {
+	case rpc.BroadcastInvokev3Txn:
+		resp, err := provider.AddInvokeTransaction(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		return resp.TransactionHash, nil
+	case rpc.BroadcastDeclareTxnV3:
+		resp, err := provider.AddDeclareTransaction(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		return resp.TransactionHash, nil
+	case rpc.BroadcastDeployAccountTxnV3:
+		resp, err := provider.AddDeployAccountTransaction(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		return resp.TransactionHash, nil
+	}
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedTransaction, tx)
+}
+
+// Encode assembles signatures in the format of an account class, in the order
+// the class requires.
+//
+// Parameters:
+// - format: the signature format
+// - sigs: the signatures, in any order
+// Returns:
+// - []*felt.Felt: the signature of the transaction, nil for an unsupported format
+func Encode(format Format, sigs []Signature) []*felt.Felt {
+	sorted := ordered(format, sigs)
+	switch format {
+	case FormatArgent:
+		out := make([]*felt.Felt, 0, 1+4*len(sorted))
+		out = append(out, new(felt.Felt).SetUint64(uint64(len(sorted))))
+		for _, sig := range sorted {
+			out = append(out, new(felt.Felt), sig.Signer, sig.R, sig.S)
+		}
+		return out
+	case FormatArgentLegacy:
+		out := make([]*felt.Felt, 0, 3*len(sorted))
+		for _, sig := range sorted {
+			out = append(out, sig.Signer, sig.R, sig.S)
+		}
+		return out
+	}
+	return nil
+}
+
+// ordered returns a copy of signatures in the order of a format.
+//
+// Parameters:
+// - format: the signature format
+// - sigs: the signatures
+// Returns:
+// - []Signature: the sorted signatures
+func ordered(format Format, sigs []Signature) []Signature {
+	sorted := append([]Signature(nil), sigs...)
+	if format == FormatArgent {
+		sortBy(sorted, func(sig Signature) *felt.Felt {
+			return curve.Curve.PoseidonArray(starknetSignerGUID, sig.Signer)
+		})
+	} else {
+		sortBy(sorted, func(sig Signature) *felt.Felt { return sig.Signer })
+	}
+	return sorted
+}
+
+// sortBy sorts signatures in ascending order of a key.
+//
+// Parameters:
+// - sigs: the signatures
+// - key: the key of a signature
+// Returns:
+//
+//	none
+func sortBy(sigs []Signature, key func(Signature) *felt.Felt) {
+	keys := make([][32]byte, len(sigs))
+	for i, sig := range sigs {
+		keys[i] = key(sig).Bytes()
+	}
+	sort.Sort(bySortKey{sigs: sigs, keys: keys})
+}
+
+// bySortKey sorts signatures with their precomputed keys.
+type bySortKey struct {
+	sigs []Signature
+	keys [][32]byte
+}
+
+func (b bySortKey) Len() int           { return len(b.sigs) }
+func (b bySortKey) Less(i, j int) bool { return bytes.Compare(b.keys[i][:], b.keys[j][:]) < 0 }
+func (b bySortKey) Swap(i, j int) {
+	b.sigs[i], b.sigs[j] = b.sigs[j], b.sigs[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
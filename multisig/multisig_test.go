@@ -0,0 +1,103 @@
+package multisig
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/curve"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/txbuilder"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestCollector tests that a collector checks the signatures of the owners,
+// assembles the threshold of them in the format of the account class, and
+// submits the transaction once the threshold is reached.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestCollector(t *testing.T) {
+	ctx := context.Background()
+	owners := make([]Signer, 3)
+	keys := make([]*felt.Felt, 3)
+	for i := range owners {
+		ks, pub, _ := account.GetRandomKeys()
+		owners[i] = KeystoreSigner(ks, pub)
+		keys[i] = pub
+	}
+	builder := txbuilder.NewInvoke("SN_SEPOLIA", utils.Uint64ToFelt(0xacc)).
+		SetCalls([]rpc.FunctionCall{{ContractAddress: utils.Uint64ToFelt(0x10), EntryPointSelector: utils.SelectorApprove}}, 2).
+		SetNonce(utils.Uint64ToFelt(1)).
+		SetResourceBounds(rpc.ResourceBoundsMapping{
+			L1Gas: rpc.ResourceBounds{MaxAmount: "0x100", MaxPricePerUnit: "0x10"},
+			L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+		})
+
+	_, err := NewCollector(builder, keys, 4, FormatArgent)
+	require.True(t, errors.Is(err, ErrInvalidThreshold))
+	c, err := NewCollector(builder, keys, 2, FormatArgent)
+	require.NoError(t, err)
+	txHash, err := builder.Hash()
+	require.NoError(t, err)
+	require.Equal(t, txHash, c.Hash())
+
+	// signatures of strangers or over another hash are rejected
+	ks, stranger, _ := account.GetRandomKeys()
+	require.True(t, errors.Is(c.Collect(ctx, KeystoreSigner(ks, stranger)), ErrUnknownSigner))
+	r, s, err := owners[0].SignHash(ctx, utils.Uint64ToFelt(1))
+	require.NoError(t, err)
+	require.True(t, errors.Is(c.Add(Signature{Signer: keys[0], R: r, S: s}), ErrInvalidSignature))
+
+	require.NoError(t, c.Collect(ctx, owners[0]))
+	require.False(t, c.Ready())
+	_, err = c.Signature()
+	require.True(t, errors.Is(err, ErrThresholdNotReached))
+
+	require.NoError(t, c.Collect(ctx, owners[1], owners[2]))
+	require.True(t, c.Ready())
+	signature, err := c.Signature()
+	require.NoError(t, err)
+	require.Len(t, signature, 1+2*4)
+	require.Equal(t, utils.Uint64ToFelt(2), signature[0])
+	guids := make([]*felt.Felt, 2)
+	for i := 0; i < 2; i++ {
+		require.Equal(t, new(felt.Felt), signature[1+4*i])
+		guids[i] = curve.Curve.PoseidonArray(starknetSignerGUID, signature[2+4*i])
+	}
+	require.True(t, guids[0].Cmp(guids[1]) < 0)
+
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, tx rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+			require.Equal(t, signature, tx.(rpc.BroadcastInvokev3Txn).Signature)
+			return &rpc.AddInvokeTransactionResponse{TransactionHash: txHash}, nil
+		})
+	submitted, err := c.Submit(ctx, provider)
+	require.NoError(t, err)
+	require.Equal(t, txHash, submitted)
+}
+
+// TestEncode tests the legacy Argent multisig format, signatures sorted by
+// public key.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestEncode(t *testing.T) {
+	a := Signature{Signer: utils.Uint64ToFelt(2), R: utils.Uint64ToFelt(20), S: utils.Uint64ToFelt(21)}
+	b := Signature{Signer: utils.Uint64ToFelt(1), R: utils.Uint64ToFelt(10), S: utils.Uint64ToFelt(11)}
+	require.Equal(t, []*felt.Felt{b.Signer, b.R, b.S, a.Signer, a.R, a.S}, Encode(FormatArgentLegacy, []Signature{a, b}))
+	require.Nil(t, Encode(Format(9), []Signature{a}))
+}
@@ -0,0 +1,167 @@
+package nonce
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type leaseState string
+
+const (
+	stateLeased    leaseState = "leased"
+	stateCommitted leaseState = "committed"
+	stateReleased  leaseState = "released"
+)
+
+type memoryLease struct {
+	owner     string
+	state     leaseState
+	expiresAt time.Time
+}
+
+// MemoryStore is a Store keeping the leases in memory. It coordinates the
+// goroutines of a single process and is meant for tests and single-replica
+// deployments.
+type MemoryStore struct {
+	mu       sync.Mutex
+	accounts map[string]map[uint64]*memoryLease
+	now      func() time.Time
+}
+
+var _ Store = &MemoryStore{}
+
+// NewMemoryStore creates an empty MemoryStore.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *MemoryStore: the store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		accounts: map[string]map[uint64]*memoryLease{},
+		now:      time.Now,
+	}
+}
+
+// Reserve leases count nonces of the account to owner for ttl.
+//
+// Parameters:
+// - ctx: the context
+// - account: the account address
+// - owner: the replica identifier
+// - floor: the on-chain nonce of the account
+// - count: the number of nonces to reserve
+// - ttl: the lease duration
+// Returns:
+// - []uint64: the reserved nonces, in increasing order
+// - error: an error if count is not positive
+func (s *MemoryStore) Reserve(ctx context.Context, account, owner string, floor uint64, count int, ttl time.Duration) ([]uint64, error) {
+	if count <= 0 {
+		return nil, ErrInvalidCount
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	leases, ok := s.accounts[account]
+	if !ok {
+		leases = map[uint64]*memoryLease{}
+		s.accounts[account] = leases
+	}
+	now := s.now()
+	next := floor
+	var reclaimable []uint64
+	for n, l := range leases {
+		if n < floor {
+			delete(leases, n)
+			continue
+		}
+		if n >= next {
+			next = n + 1
+		}
+		if l.state == stateReleased || now.After(l.expiresAt) {
+			reclaimable = append(reclaimable, n)
+		}
+	}
+	sort.Slice(reclaimable, func(i, j int) bool { return reclaimable[i] < reclaimable[j] })
+
+	nonces := make([]uint64, 0, count)
+	for _, n := range reclaimable {
+		if len(nonces) == count {
+			break
+		}
+		nonces = append(nonces, n)
+	}
+	for len(nonces) < count {
+		nonces = append(nonces, next)
+		next++
+	}
+	for _, n := range nonces {
+		leases[n] = &memoryLease{owner: owner, state: stateLeased, expiresAt: now.Add(ttl)}
+	}
+	return nonces, nil
+}
+
+// Commit marks a nonce leased by owner as used and keeps it for ttl.
+//
+// Parameters:
+// - ctx: the context
+// - account: the account address
+// - owner: the replica identifier
+// - nonce: the committed nonce
+// - ttl: how long the nonce is kept
+// Returns:
+// - error: ErrLeaseNotHeld if owner does not hold a live lease on the nonce
+func (s *MemoryStore) Commit(ctx context.Context, account, owner string, nonce uint64, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.heldLease(account, owner, nonce)
+	if err != nil {
+		return err
+	}
+	l.state = stateCommitted
+	l.expiresAt = s.now().Add(ttl)
+	return nil
+}
+
+// Release hands a nonce leased by owner back to the other replicas.
+//
+// Parameters:
+// - ctx: the context
+// - account: the account address
+// - owner: the replica identifier
+// - nonce: the released nonce
+// Returns:
+// - error: ErrLeaseNotHeld if owner does not hold a live lease on the nonce
+func (s *MemoryStore) Release(ctx context.Context, account, owner string, nonce uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, err := s.heldLease(account, owner, nonce)
+	if err != nil {
+		return err
+	}
+	l.state = stateReleased
+	return nil
+}
+
+// heldLease returns the live lease of owner on a nonce. The caller must hold the lock.
+//
+// Parameters:
+// - account: the account address
+// - owner: the replica identifier
+// - nonce: the nonce
+// Returns:
+// - *memoryLease: the lease
+// - error: ErrLeaseNotHeld if owner does not hold a live lease on the nonce
+func (s *MemoryStore) heldLease(account, owner string, nonce uint64) (*memoryLease, error) {
+	l, ok := s.accounts[account][nonce]
+	if !ok || l.owner != owner || l.state != stateLeased || s.now().After(l.expiresAt) {
+		return nil, ErrLeaseNotHeld
+	}
+	return l, nil
+}
@@ -0,0 +1,239 @@
+// Package nonce coordinates the nonces of an account shared by several
+// processes, such as the replicas of a service sending transactions from the
+// same sender account.
+//
+// Each replica reserves nonces from a Store shared by every replica. A
+// reservation is a lease: it must be committed once the transaction carrying
+// the nonce is sent, or released if it is not, and it is reclaimed by other
+// replicas when it expires. Committed nonces are kept until the on-chain
+// nonce of the account moves past them, so that the nonce of a transaction
+// that never lands is eventually reused instead of blocking the account.
+//
+// The package provides a MemoryStore, for the goroutines of a single process,
+// and a PostgresStore, for replicas sharing a PostgreSQL database. Other
+// coordination backends, such as Redis, implement Store.
+package nonce
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+var (
+	ErrLeaseNotHeld = errors.New("nonce: lease is not held by this owner")
+	ErrInvalidCount = errors.New("nonce: the number of nonces to reserve must be positive")
+)
+
+const (
+	DefaultLeaseTTL  = time.Minute
+	DefaultCommitTTL = 10 * time.Minute
+)
+
+// Store is the coordination backend shared by every replica.
+//
+// Implementations must be safe for concurrent use by several processes: the
+// reservation of the nonces of an account must be atomic.
+type Store interface {
+	// Reserve leases count nonces of the account to owner for ttl. Nonces lower
+	// than floor are used on chain and must be forgotten. Released and expired
+	// nonces are handed out first, then nonces above every known nonce.
+	Reserve(ctx context.Context, account, owner string, floor uint64, count int, ttl time.Duration) ([]uint64, error)
+	// Commit marks a nonce leased by owner as used by a sent transaction and
+	// keeps it for ttl, after which it is reclaimed unless the on-chain nonce
+	// moved past it.
+	Commit(ctx context.Context, account, owner string, nonce uint64, ttl time.Duration) error
+	// Release hands a nonce leased by owner back to the other replicas.
+	Release(ctx context.Context, account, owner string, nonce uint64) error
+}
+
+// Reserver reserves the nonces of one account from a Store.
+type Reserver struct {
+	provider  rpc.RpcProvider
+	store     Store
+	account   *felt.Felt
+	owner     string
+	leaseTTL  time.Duration
+	commitTTL time.Duration
+}
+
+// Option configures a Reserver.
+type Option func(*Reserver)
+
+// WithOwner sets the identifier of the replica holding the leases. It
+// defaults to a random identifier.
+//
+// Parameters:
+// - owner: the replica identifier
+// Returns:
+// - Option: the option
+func WithOwner(owner string) Option {
+	return func(r *Reserver) {
+		r.owner = owner
+	}
+}
+
+// WithLeaseTTL sets how long a reserved nonce stays leased before other
+// replicas reclaim it. It defaults to DefaultLeaseTTL.
+//
+// Parameters:
+// - ttl: the lease duration
+// Returns:
+// - Option: the option
+func WithLeaseTTL(ttl time.Duration) Option {
+	return func(r *Reserver) {
+		r.leaseTTL = ttl
+	}
+}
+
+// WithCommitTTL sets how long a committed nonce waits for the on-chain nonce
+// to move past it before being reclaimed. It defaults to DefaultCommitTTL.
+//
+// Parameters:
+// - ttl: the commit duration
+// Returns:
+// - Option: the option
+func WithCommitTTL(ttl time.Duration) Option {
+	return func(r *Reserver) {
+		r.commitTTL = ttl
+	}
+}
+
+// NewReserver creates a Reserver for the nonces of an account.
+//
+// Parameters:
+// - provider: the provider used to read the on-chain nonce
+// - store: the coordination store shared by the replicas
+// - account: the address of the sender account
+// - opts: the options of the Reserver
+// Returns:
+// - *Reserver: the Reserver
+func NewReserver(provider rpc.RpcProvider, store Store, account *felt.Felt, opts ...Option) *Reserver {
+	r := &Reserver{
+		provider:  provider,
+		store:     store,
+		account:   account,
+		leaseTTL:  DefaultLeaseTTL,
+		commitTTL: DefaultCommitTTL,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.owner == "" {
+		r.owner = randomOwner()
+	}
+	return r
+}
+
+// Owner returns the identifier of the replica holding the leases.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the owner identifier
+func (r *Reserver) Owner() string {
+	return r.owner
+}
+
+// Reserve reserves the next available nonce of the account.
+//
+// Parameters:
+// - ctx: the context
+// Returns:
+// - *Reservation: the reserved nonce
+// - error: an error if the on-chain nonce can not be read or the store fails
+func (r *Reserver) Reserve(ctx context.Context) (*Reservation, error) {
+	res, err := r.ReserveBatch(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	return res[0], nil
+}
+
+// ReserveBatch reserves count nonces of the account at once, in increasing order.
+//
+// The nonces are not necessarily contiguous: nonces released or abandoned by
+// other replicas are handed out first.
+//
+// Parameters:
+// - ctx: the context
+// - count: the number of nonces to reserve
+// Returns:
+// - []*Reservation: the reserved nonces
+// - error: an error if the on-chain nonce can not be read or the store fails
+func (r *Reserver) ReserveBatch(ctx context.Context, count int) ([]*Reservation, error) {
+	if count <= 0 {
+		return nil, ErrInvalidCount
+	}
+	onChain, err := r.provider.Nonce(ctx, rpc.WithBlockTag("pending"), r.account)
+	if err != nil {
+		return nil, err
+	}
+	floor := onChain.Uint64()
+	if !onChain.Equal(new(felt.Felt).SetUint64(floor)) {
+		return nil, fmt.Errorf("nonce: on-chain nonce %s does not fit in 64 bits", onChain)
+	}
+
+	nonces, err := r.store.Reserve(ctx, r.account.String(), r.owner, floor, count, r.leaseTTL)
+	if err != nil {
+		return nil, err
+	}
+	reservations := make([]*Reservation, len(nonces))
+	for i, n := range nonces {
+		reservations[i] = &Reservation{Nonce: new(felt.Felt).SetUint64(n), reserver: r, value: n}
+	}
+	return reservations, nil
+}
+
+// Reservation is a nonce leased to a Reserver.
+type Reservation struct {
+	Nonce    *felt.Felt
+	reserver *Reserver
+	value    uint64
+}
+
+// Commit marks the nonce as used by a sent transaction.
+//
+// Parameters:
+// - ctx: the context
+// Returns:
+// - error: ErrLeaseNotHeld if the lease expired and was reclaimed
+func (res *Reservation) Commit(ctx context.Context) error {
+	r := res.reserver
+	return r.store.Commit(ctx, r.account.String(), r.owner, res.value, r.commitTTL)
+}
+
+// Release hands the nonce back when no transaction was sent with it.
+//
+// Parameters:
+// - ctx: the context
+// Returns:
+// - error: ErrLeaseNotHeld if the lease expired and was reclaimed
+func (res *Reservation) Release(ctx context.Context) error {
+	r := res.reserver
+	return r.store.Release(ctx, r.account.String(), r.owner, res.value)
+}
+
+// randomOwner returns a random replica identifier.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the identifier
+func randomOwner() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("replica-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
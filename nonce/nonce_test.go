@@ -0,0 +1,334 @@
+package nonce
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestReserver_Replicas tests two replicas sharing a MemoryStore: they never
+// receive the same nonce, released and expired nonces are reclaimed, and
+// nonces below the on-chain nonce are forgotten.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestReserver_Replicas(t *testing.T) {
+	ctx := context.Background()
+	account := utils.TestHexToFelt(t, "0xacc")
+	onChain := new(felt.Felt).SetUint64(5)
+
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	provider.EXPECT().Nonce(gomock.Any(), rpc.WithBlockTag("pending"), account).DoAndReturn(
+		func(context.Context, rpc.BlockID, *felt.Felt) (*felt.Felt, error) { return onChain, nil },
+	).AnyTimes()
+
+	now := time.Unix(0, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	a := NewReserver(provider, store, account, WithOwner("a"))
+	b := NewReserver(provider, store, account, WithOwner("b"))
+
+	batch, err := a.ReserveBatch(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{5, 6, 7}, reservedValues(batch))
+
+	res, err := b.Reserve(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(8), res.value)
+	require.NoError(t, res.Commit(ctx))
+
+	// a sends 5, gives 6 back and forgets about 7
+	require.NoError(t, batch[0].Commit(ctx))
+	require.NoError(t, batch[1].Release(ctx))
+	require.Equal(t, ErrLeaseNotHeld, batch[1].Commit(ctx))
+
+	res, err = b.Reserve(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), res.value)
+	require.NoError(t, res.Commit(ctx))
+
+	now = now.Add(DefaultLeaseTTL + time.Second)
+	res, err = b.Reserve(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), res.value)
+	require.Equal(t, ErrLeaseNotHeld, batch[2].Commit(ctx))
+
+	// the transaction with nonce 5 landed
+	onChain = new(felt.Felt).SetUint64(6)
+	now = now.Add(DefaultCommitTTL)
+	batch, err = a.ReserveBatch(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{6, 7}, reservedValues(batch))
+}
+
+// reservedValues returns the nonces of the reservations.
+//
+// Parameters:
+// - reservations: the reservations
+// Returns:
+// - []uint64: the nonces
+func reservedValues(reservations []*Reservation) []uint64 {
+	values := make([]uint64, len(reservations))
+	for i, r := range reservations {
+		values[i] = r.value
+	}
+	return values
+}
+
+// TestPostgresStore tests the statements of a PostgresStore on a fake
+// database: reserved nonces are leased under the advisory lock of the
+// account, released and expired nonces are reclaimed, leases not held are
+// refused, nonces below the floor are deleted and failed reservations are
+// rolled back.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestPostgresStore(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewPostgresStore(nil, "leases; DROP TABLE users")
+	require.Error(t, err)
+
+	db := newFakePostgres("nonces")
+	store, err := NewPostgresStore(sql.OpenDB(db), "nonces")
+	require.NoError(t, err)
+	require.NoError(t, store.Migrate(ctx))
+	require.True(t, db.migrated)
+
+	nonces, err := store.Reserve(ctx, "0xacc", "a", 5, 3, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{5, 6, 7}, nonces)
+	require.Equal(t, []string{"0xacc"}, db.locks)
+	nonces, err = store.Reserve(ctx, "0xacc", "b", 5, 1, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{8}, nonces)
+	_, err = store.Reserve(ctx, "0xacc", "b", 5, 0, time.Minute)
+	require.Equal(t, ErrInvalidCount, err)
+
+	// a sends 5, gives 6 back and forgets about 7
+	require.NoError(t, store.Commit(ctx, "0xacc", "a", 5, time.Hour))
+	require.NoError(t, store.Release(ctx, "0xacc", "a", 6))
+	require.Equal(t, ErrLeaseNotHeld, store.Commit(ctx, "0xacc", "a", 6, time.Hour))
+	require.Equal(t, ErrLeaseNotHeld, store.Release(ctx, "0xacc", "b", 7))
+	nonces, err = store.Reserve(ctx, "0xacc", "b", 5, 1, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{6}, nonces)
+
+	db.now = db.now.Add(2 * time.Minute)
+	require.Equal(t, ErrLeaseNotHeld, store.Commit(ctx, "0xacc", "a", 7, time.Hour))
+	nonces, err = store.Reserve(ctx, "0xacc", "b", 5, 3, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{6, 7, 8}, nonces)
+
+	db.fail = errors.New("connection reset")
+	_, err = store.Reserve(ctx, "0xacc", "a", 6, 1, time.Minute)
+	require.Equal(t, db.fail, err)
+	db.fail = nil
+	require.Contains(t, db.leases, uint64(5))
+
+	// the transaction with nonce 5 landed
+	nonces, err = store.Reserve(ctx, "0xacc", "a", 6, 1, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{9}, nonces)
+	require.NotContains(t, db.leases, uint64(5))
+}
+
+// fakePostgres is a driver.Connector running the statements of a
+// PostgresStore for a single account on an in-memory table, with now() its
+// clock. The inserts fail with fail when it is set.
+type fakePostgres struct {
+	mu       sync.Mutex
+	table    string
+	now      time.Time
+	fail     error
+	migrated bool
+	locks    []string
+	leases   map[uint64]*memoryLease
+}
+
+// newFakePostgres creates a fake database with an empty table.
+//
+// Parameters:
+// - table: the name of the table
+// Returns:
+// - *fakePostgres: the database
+func newFakePostgres(table string) *fakePostgres {
+	return &fakePostgres{table: table, now: time.Unix(0, 0), leases: map[uint64]*memoryLease{}}
+}
+
+// Connect implements driver.Connector.
+func (d *fakePostgres) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: d}, nil }
+
+// Driver implements driver.Connector.
+func (d *fakePostgres) Driver() driver.Driver { return nil }
+
+// fakeConn is a connection to a fakePostgres. A transaction restores the
+// table it snapshots on rollback.
+type fakeConn struct {
+	db       *fakePostgres
+	snapshot map[uint64]*memoryLease
+}
+
+// Prepare implements driver.Conn.
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+
+// Close implements driver.Conn.
+func (c *fakeConn) Close() error { return nil }
+
+// Begin implements driver.Conn.
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.snapshot = map[uint64]*memoryLease{}
+	for n, l := range c.db.leases {
+		lease := *l
+		c.snapshot[n] = &lease
+	}
+	return c, nil
+}
+
+// Commit implements driver.Tx.
+func (c *fakeConn) Commit() error {
+	c.snapshot = nil
+	return nil
+}
+
+// Rollback implements driver.Tx.
+func (c *fakeConn) Rollback() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.leases, c.snapshot = c.snapshot, nil
+	return nil
+}
+
+// ExecContext implements driver.ExecerContext.
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	d := c.db
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	query = strings.Join(strings.Fields(strings.ReplaceAll(query, d.table, "t")), " ")
+	var affected int64
+	switch {
+	case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS t "):
+		d.migrated = true
+	case query == "SELECT pg_advisory_xact_lock(hashtext($1))":
+		d.locks = append(d.locks, args[0].Value.(string))
+	case query == "DELETE FROM t WHERE account = $1 AND nonce < $2":
+		for n := range d.leases {
+			if n < uint64(args[1].Value.(int64)) {
+				delete(d.leases, n)
+				affected++
+			}
+		}
+	case strings.HasPrefix(query, "UPDATE t SET owner = $3, state = $4, expires_at = now() + $5 * interval '1 second' WHERE account = $1 AND nonce = $2"):
+		l := d.leases[uint64(args[1].Value.(int64))]
+		l.owner, l.state, l.expiresAt = args[2].Value.(string), leaseState(args[3].Value.(string)), d.expiry(args[4])
+		affected = 1
+	case strings.HasPrefix(query, "INSERT INTO t (account, nonce, owner, state, expires_at) VALUES"):
+		if d.fail != nil {
+			return nil, d.fail
+		}
+		d.leases[uint64(args[1].Value.(int64))] = &memoryLease{owner: args[2].Value.(string), state: leaseState(args[3].Value.(string)), expiresAt: d.expiry(args[4])}
+		affected = 1
+	case query == "UPDATE t SET state = $4, expires_at = now() + $6 * interval '1 second' WHERE account = $1 AND nonce = $2 AND owner = $3 AND state = $5 AND expires_at >= now()",
+		query == "UPDATE t SET state = $4 WHERE account = $1 AND nonce = $2 AND owner = $3 AND state = $5 AND expires_at >= now()":
+		l, ok := d.leases[uint64(args[1].Value.(int64))]
+		if ok && l.owner == args[2].Value.(string) && l.state == leaseState(args[4].Value.(string)) && !d.now.After(l.expiresAt) {
+			l.state = leaseState(args[3].Value.(string))
+			if len(args) == 6 {
+				l.expiresAt = d.expiry(args[5])
+			}
+			affected = 1
+		}
+	default:
+		return nil, fmt.Errorf("unexpected statement %q", query)
+	}
+	return driver.RowsAffected(affected), nil
+}
+
+// QueryContext implements driver.QueryerContext.
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	d := c.db
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	query = strings.Join(strings.Fields(strings.ReplaceAll(query, d.table, "t")), " ")
+	var values []int64
+	switch query {
+	case "SELECT nonce FROM t WHERE account = $1 AND (state = $2 OR expires_at < now()) ORDER BY nonce LIMIT $3":
+		for n, l := range d.leases {
+			if l.state == leaseState(args[1].Value.(string)) || l.expiresAt.Before(d.now) {
+				values = append(values, int64(n))
+			}
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		if limit := int(args[2].Value.(int64)); len(values) > limit {
+			values = values[:limit]
+		}
+	case "SELECT COALESCE(MAX(nonce) + 1, $2) FROM t WHERE account = $1":
+		next := args[1].Value.(int64)
+		if len(d.leases) > 0 {
+			next = 0
+			for n := range d.leases {
+				if int64(n) >= next {
+					next = int64(n) + 1
+				}
+			}
+		}
+		values = []int64{next}
+	default:
+		return nil, fmt.Errorf("unexpected query %q", query)
+	}
+	return &fakeRows{values: values}, nil
+}
+
+// expiry returns the expiry of a lease of a duration in seconds.
+//
+// Parameters:
+// - seconds: the duration argument
+// Returns:
+// - time.Time: the expiry
+func (d *fakePostgres) expiry(seconds driver.NamedValue) time.Time {
+	return d.now.Add(time.Duration(seconds.Value.(float64) * float64(time.Second)))
+}
+
+// fakeRows are the rows of a single int64 column.
+type fakeRows struct {
+	values []int64
+}
+
+// Columns implements driver.Rows.
+func (r *fakeRows) Columns() []string { return []string{"nonce"} }
+
+// Close implements driver.Rows.
+func (r *fakeRows) Close() error { return nil }
+
+// Next implements driver.Rows.
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
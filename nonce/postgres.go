@@ -0,0 +1,204 @@
+package nonce
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// DefaultPostgresTable is the table used by a PostgresStore when none is given.
+const DefaultPostgresTable = "starknet_nonce_leases"
+
+var tableNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// PostgresStore is a Store backed by a PostgreSQL table. The reservations of
+// an account are serialized with a transaction-level advisory lock, and every
+// expiry is evaluated with the clock of the database so that the replicas do
+// not depend on their own clocks.
+//
+// The store only uses database/sql: the caller opens the *sql.DB with the
+// PostgreSQL driver of their choice.
+type PostgresStore struct {
+	db    *sql.DB
+	table string
+}
+
+var _ Store = &PostgresStore{}
+
+// NewPostgresStore creates a PostgresStore using the given table, or
+// DefaultPostgresTable when table is empty. Call Migrate to create the table.
+//
+// Parameters:
+// - db: the database handle
+// - table: the table name, optionally schema-qualified
+// Returns:
+// - *PostgresStore: the store
+// - error: an error if the table name is not a valid identifier
+func NewPostgresStore(db *sql.DB, table string) (*PostgresStore, error) {
+	if table == "" {
+		table = DefaultPostgresTable
+	}
+	if !tableNameRegexp.MatchString(table) {
+		return nil, fmt.Errorf("nonce: invalid table name %q", table)
+	}
+	return &PostgresStore{db: db, table: table}, nil
+}
+
+// Migrate creates the lease table if it does not exist.
+//
+// Parameters:
+// - ctx: the context
+// Returns:
+// - error: an error if the table can not be created
+func (s *PostgresStore) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	account TEXT NOT NULL,
+	nonce BIGINT NOT NULL,
+	owner TEXT NOT NULL,
+	state TEXT NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (account, nonce)
+)`, s.table))
+	return err
+}
+
+// Reserve leases count nonces of the account to owner for ttl.
+//
+// Parameters:
+// - ctx: the context
+// - account: the account address
+// - owner: the replica identifier
+// - floor: the on-chain nonce of the account
+// - count: the number of nonces to reserve
+// - ttl: the lease duration
+// Returns:
+// - []uint64: the reserved nonces, in increasing order
+// - error: an error if the transaction fails
+func (s *PostgresStore) Reserve(ctx context.Context, account, owner string, floor uint64, count int, ttl time.Duration) (nonces []uint64, err error) {
+	if count <= 0 {
+		return nil, ErrInvalidCount
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, account); err != nil {
+		return nil, err
+	}
+	if _, err = tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE account = $1 AND nonce < $2`, s.table), account, int64(floor)); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(
+		`SELECT nonce FROM %s WHERE account = $1 AND (state = $2 OR expires_at < now()) ORDER BY nonce LIMIT $3`, s.table),
+		account, string(stateReleased), count)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var n int64
+		if err = rows.Scan(&n); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		nonces = append(nonces, uint64(n))
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	seconds := ttl.Seconds()
+	for _, n := range nonces {
+		if _, err = tx.ExecContext(ctx, fmt.Sprintf(
+			`UPDATE %s SET owner = $3, state = $4, expires_at = now() + $5 * interval '1 second' WHERE account = $1 AND nonce = $2`, s.table),
+			account, int64(n), owner, string(stateLeased), seconds); err != nil {
+			return nil, err
+		}
+	}
+
+	var next int64
+	if err = tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT COALESCE(MAX(nonce) + 1, $2) FROM %s WHERE account = $1`, s.table), account, int64(floor)).Scan(&next); err != nil {
+		return nil, err
+	}
+	if next < int64(floor) {
+		next = int64(floor)
+	}
+	for len(nonces) < count {
+		if _, err = tx.ExecContext(ctx, fmt.Sprintf(
+			`INSERT INTO %s (account, nonce, owner, state, expires_at) VALUES ($1, $2, $3, $4, now() + $5 * interval '1 second')`, s.table),
+			account, next, owner, string(stateLeased), seconds); err != nil {
+			return nil, err
+		}
+		nonces = append(nonces, uint64(next))
+		next++
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return nonces, nil
+}
+
+// Commit marks a nonce leased by owner as used and keeps it for ttl.
+//
+// Parameters:
+// - ctx: the context
+// - account: the account address
+// - owner: the replica identifier
+// - nonce: the committed nonce
+// - ttl: how long the nonce is kept
+// Returns:
+// - error: ErrLeaseNotHeld if owner does not hold a live lease on the nonce
+func (s *PostgresStore) Commit(ctx context.Context, account, owner string, nonce uint64, ttl time.Duration) error {
+	return s.transition(ctx, fmt.Sprintf(
+		`UPDATE %s SET state = $4, expires_at = now() + $6 * interval '1 second'
+		WHERE account = $1 AND nonce = $2 AND owner = $3 AND state = $5 AND expires_at >= now()`, s.table),
+		account, int64(nonce), owner, string(stateCommitted), string(stateLeased), ttl.Seconds())
+}
+
+// Release hands a nonce leased by owner back to the other replicas.
+//
+// Parameters:
+// - ctx: the context
+// - account: the account address
+// - owner: the replica identifier
+// - nonce: the released nonce
+// Returns:
+// - error: ErrLeaseNotHeld if owner does not hold a live lease on the nonce
+func (s *PostgresStore) Release(ctx context.Context, account, owner string, nonce uint64) error {
+	return s.transition(ctx, fmt.Sprintf(
+		`UPDATE %s SET state = $4 WHERE account = $1 AND nonce = $2 AND owner = $3 AND state = $5 AND expires_at >= now()`, s.table),
+		account, int64(nonce), owner, string(stateReleased), string(stateLeased))
+}
+
+// transition runs an update changing the state of a single live lease.
+//
+// Parameters:
+// - ctx: the context
+// - query: the update statement
+// - args: the arguments of the statement
+// Returns:
+// - error: ErrLeaseNotHeld if no lease was updated
+func (s *PostgresStore) transition(ctx context.Context, query string, args ...any) error {
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLeaseNotHeld
+	}
+	return nil
+}
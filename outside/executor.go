@@ -0,0 +1,73 @@
+package outside
+
+import (
+	"context"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// Executor relays signed outside executions from a sponsor account, which
+// pays the fee of the transaction.
+type Executor struct {
+	relayer *account.Account
+	maxFee  *felt.Felt
+}
+
+// NewExecutor creates an Executor sending the transactions from relayer.
+//
+// Parameters:
+// - relayer: the account submitting and paying for the executions
+// - maxFee: the max fee of the relaying transactions
+// Returns:
+// - *Executor: the executor
+func NewExecutor(relayer *account.Account, maxFee *felt.Felt) *Executor {
+	return &Executor{relayer: relayer, maxFee: maxFee}
+}
+
+// Execute checks that the relayer may submit the signed executions and sends
+// them in a single invoke transaction.
+//
+// Parameters:
+// - ctx: the context
+// - executions: the signed executions to relay
+// Returns:
+// - *rpc.AddInvokeTransactionResponse: the response of the relaying transaction
+// - error: an error if an execution is not valid for the relayer or the transaction is rejected
+func (e *Executor) Execute(ctx context.Context, executions ...*SignedOutsideExecution) (*rpc.AddInvokeTransactionResponse, error) {
+	calls := make([]rpc.FunctionCall, len(executions))
+	now := time.Now()
+	for i, s := range executions {
+		if err := s.CheckValid(e.relayer.AccountAddress, now); err != nil {
+			return nil, err
+		}
+		call, err := s.Call()
+		if err != nil {
+			return nil, err
+		}
+		calls[i] = call
+	}
+
+	nonce, err := e.relayer.Nonce(ctx, rpc.WithBlockTag("latest"), e.relayer.AccountAddress)
+	if err != nil {
+		return nil, err
+	}
+	calldata, err := e.relayer.FmtCalldata(calls)
+	if err != nil {
+		return nil, err
+	}
+	tx := rpc.InvokeTxnV1{
+		MaxFee:        e.maxFee,
+		Version:       rpc.TransactionV1,
+		Nonce:         nonce,
+		Type:          rpc.TransactionType_Invoke,
+		SenderAddress: e.relayer.AccountAddress,
+		Calldata:      calldata,
+	}
+	if err := e.relayer.SignInvokeTransaction(ctx, &tx); err != nil {
+		return nil, err
+	}
+	return e.relayer.AddInvokeTransaction(ctx, rpc.BroadcastInvokev1Txn{InvokeTxnV1: tx})
+}
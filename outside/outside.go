@@ -0,0 +1,252 @@
+// Package outside implements SNIP-9 outside execution: an account signs an
+// OutsideExecution authorizing a set of calls, and another account (a
+// relayer) submits it to the signer through `execute_from_outside`, paying
+// the fee on its behalf.
+package outside
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/abi/codec"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/curve"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// Version selects the SNIP-9 interface implemented by the signer account.
+type Version int
+
+const (
+	// V1 is hashed with SNIP-12 revision 0 and executed with execute_from_outside
+	V1 Version = 1
+	// V2 is hashed with SNIP-12 revision 1 and executed with execute_from_outside_v2
+	V2 Version = 2
+)
+
+var (
+	// AnyCaller allows any account to submit the outside execution
+	AnyCaller = new(felt.Felt).SetBytes([]byte("ANY_CALLER"))
+
+	ErrUnsupportedVersion = errors.New("outside: unsupported SNIP-9 version")
+
+	domainName    = new(felt.Felt).SetBytes([]byte("Account.execute_from_outside"))
+	messagePrefix = new(felt.Felt).SetBytes([]byte("StarkNet Message"))
+
+	// SNIP-12 revision 0 types used by V1
+	domainTypeHashV1           = utils.GetSelectorFromNameFelt("StarkNetDomain(name:felt,version:felt,chainId:felt)")
+	outsideExecutionTypeHashV1 = utils.GetSelectorFromNameFelt("OutsideExecution(caller:felt,nonce:felt,execute_after:felt,execute_before:felt,calls_len:felt,calls:OutsideCall*)OutsideCall(to:felt,selector:felt,calldata_len:felt,calldata:felt*)")
+	callTypeHashV1             = utils.GetSelectorFromNameFelt("OutsideCall(to:felt,selector:felt,calldata_len:felt,calldata:felt*)")
+
+	// SNIP-12 revision 1 types used by V2
+	domainTypeHashV2           = utils.GetSelectorFromNameFelt(`"StarknetDomain"("name":"shortstring","version":"shortstring","chainId":"shortstring","revision":"shortstring")`)
+	outsideExecutionTypeHashV2 = utils.GetSelectorFromNameFelt(`"OutsideExecution"("Caller":"ContractAddress","Nonce":"felt","Execute After":"u128","Execute Before":"u128","Calls":"Call*")"Call"("To":"ContractAddress","Selector":"selector","Calldata":"felt*")`)
+	callTypeHashV2             = utils.GetSelectorFromNameFelt(`"Call"("To":"ContractAddress","Selector":"selector","Calldata":"felt*")`)
+)
+
+// OutsideExecution is the payload signed by the account whose calls are executed.
+type OutsideExecution struct {
+	// Caller is the only account allowed to submit the execution, or AnyCaller
+	Caller *felt.Felt
+	// Nonce is a unique value preventing replays, it is not the account nonce
+	Nonce *felt.Felt
+	// ExecuteAfter and ExecuteBefore bound the execution time, in seconds since the epoch
+	ExecuteAfter  uint64
+	ExecuteBefore uint64
+	Calls         []rpc.FunctionCall
+}
+
+// NewOutsideExecution creates an OutsideExecution with a random nonce, valid
+// from one minute ago, to absorb clock skew, until validFor from now.
+//
+// Parameters:
+// - caller: the account allowed to submit the execution, or AnyCaller
+// - calls: the calls to execute
+// - validFor: how long the execution stays valid
+// Returns:
+// - *OutsideExecution: the execution
+// - error: an error if the nonce can not be generated
+func NewOutsideExecution(caller *felt.Felt, calls []rpc.FunctionCall, validFor time.Duration) (*OutsideExecution, error) {
+	b := make([]byte, 31)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &OutsideExecution{
+		Caller:        caller,
+		Nonce:         new(felt.Felt).SetBytes(b),
+		ExecuteAfter:  uint64(now.Add(-time.Minute).Unix()),
+		ExecuteBefore: uint64(now.Add(validFor).Unix()),
+		Calls:         calls,
+	}, nil
+}
+
+// MessageHash returns the SNIP-12 message hash signed by the signer account.
+//
+// Parameters:
+// - version: the SNIP-9 version implemented by the signer
+// - chainID: the chain ID, as the felt of its short string
+// - signer: the address of the account whose calls are executed
+// Returns:
+// - *felt.Felt: the message hash
+// - error: an error if the version is unsupported
+func (oe *OutsideExecution) MessageHash(version Version, chainID, signer *felt.Felt) (*felt.Felt, error) {
+	switch version {
+	case V1:
+		return oe.messageHashV1(chainID, signer)
+	case V2:
+		return oe.messageHashV2(chainID, signer), nil
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+}
+
+// messageHashV1 hashes the execution with Pedersen, following SNIP-12 revision 0.
+//
+// Parameters:
+// - chainID: the chain ID
+// - signer: the address of the signer
+// Returns:
+// - *felt.Felt: the message hash
+// - error: an error if hashing fails
+func (oe *OutsideExecution) messageHashV1(chainID, signer *felt.Felt) (*felt.Felt, error) {
+	domainHash, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{domainTypeHashV1, domainName, new(felt.Felt).SetUint64(1), chainID})
+	if err != nil {
+		return nil, err
+	}
+	callHashes := make([]*felt.Felt, len(oe.Calls))
+	for i, call := range oe.Calls {
+		calldataHash, err := hash.ComputeHashOnElementsFelt(call.Calldata)
+		if err != nil {
+			return nil, err
+		}
+		callHashes[i], err = hash.ComputeHashOnElementsFelt([]*felt.Felt{
+			callTypeHashV1, call.ContractAddress, call.EntryPointSelector,
+			new(felt.Felt).SetUint64(uint64(len(call.Calldata))), calldataHash,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	callsHash, err := hash.ComputeHashOnElementsFelt(callHashes)
+	if err != nil {
+		return nil, err
+	}
+	executionHash, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{
+		outsideExecutionTypeHashV1, oe.Caller, oe.Nonce,
+		new(felt.Felt).SetUint64(oe.ExecuteAfter), new(felt.Felt).SetUint64(oe.ExecuteBefore),
+		new(felt.Felt).SetUint64(uint64(len(oe.Calls))), callsHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hash.ComputeHashOnElementsFelt([]*felt.Felt{messagePrefix, domainHash, signer, executionHash})
+}
+
+// messageHashV2 hashes the execution with Poseidon, following SNIP-12 revision 1.
+//
+// Parameters:
+// - chainID: the chain ID
+// - signer: the address of the signer
+// Returns:
+// - *felt.Felt: the message hash
+func (oe *OutsideExecution) messageHashV2(chainID, signer *felt.Felt) *felt.Felt {
+	domainHash := curve.Curve.PoseidonArray(domainTypeHashV2, domainName, new(felt.Felt).SetUint64(2), chainID, new(felt.Felt).SetUint64(1))
+	callHashes := make([]*felt.Felt, len(oe.Calls))
+	for i, call := range oe.Calls {
+		callHashes[i] = curve.Curve.PoseidonArray(callTypeHashV2, call.ContractAddress, call.EntryPointSelector, curve.Curve.PoseidonArray(call.Calldata...))
+	}
+	executionHash := curve.Curve.PoseidonArray(
+		outsideExecutionTypeHashV2, oe.Caller, oe.Nonce,
+		new(felt.Felt).SetUint64(oe.ExecuteAfter), new(felt.Felt).SetUint64(oe.ExecuteBefore),
+		curve.Curve.PoseidonArray(callHashes...),
+	)
+	return curve.Curve.PoseidonArray(messagePrefix, domainHash, signer, executionHash)
+}
+
+// SignedOutsideExecution is an OutsideExecution signed by the account whose
+// calls are executed, ready to be relayed.
+type SignedOutsideExecution struct {
+	Execution OutsideExecution
+	Version   Version
+	Signer    *felt.Felt
+	Signature []*felt.Felt
+}
+
+// Sign signs an OutsideExecution with the signer account.
+//
+// Parameters:
+// - ctx: the context
+// - signer: the account whose calls are executed
+// - oe: the execution to sign
+// - version: the SNIP-9 version implemented by the signer
+// Returns:
+// - *SignedOutsideExecution: the signed execution
+// - error: an error if hashing or signing fails
+func Sign(ctx context.Context, signer *account.Account, oe *OutsideExecution, version Version) (*SignedOutsideExecution, error) {
+	msgHash, err := oe.MessageHash(version, signer.ChainId, signer.AccountAddress)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.Sign(ctx, msgHash)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedOutsideExecution{Execution: *oe, Version: version, Signer: signer.AccountAddress, Signature: signature}, nil
+}
+
+// Call returns the call to the signer account executing the outside
+// execution, to be sent by the caller.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - rpc.FunctionCall: the execute_from_outside call
+// - error: an error if the version is unsupported or a call can not be serialized
+func (s *SignedOutsideExecution) Call() (rpc.FunctionCall, error) {
+	var entrypoint string
+	switch s.Version {
+	case V1:
+		entrypoint = "execute_from_outside"
+	case V2:
+		entrypoint = "execute_from_outside_v2"
+	default:
+		return rpc.FunctionCall{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, s.Version)
+	}
+	calldata, err := codec.MarshalAll(s.Execution, s.Signature)
+	if err != nil {
+		return rpc.FunctionCall{}, err
+	}
+	return rpc.FunctionCall{
+		ContractAddress:    s.Signer,
+		EntryPointSelector: utils.GetSelectorFromNameCached(entrypoint),
+		Calldata:           calldata,
+	}, nil
+}
+
+// CheckValid checks that the execution can be submitted by caller at the given time.
+//
+// Parameters:
+// - caller: the address of the account submitting the execution
+// - at: the submission time
+// Returns:
+// - error: an error if the caller is not allowed or the time is outside the window
+func (s *SignedOutsideExecution) CheckValid(caller *felt.Felt, at time.Time) error {
+	oe := s.Execution
+	if !oe.Caller.Equal(AnyCaller) && !oe.Caller.Equal(caller) {
+		return fmt.Errorf("outside: execution can only be submitted by %s", oe.Caller)
+	}
+	now := uint64(at.Unix())
+	if now <= oe.ExecuteAfter || now >= oe.ExecuteBefore {
+		return fmt.Errorf("outside: execution is only valid between %s and %s",
+			time.Unix(int64(oe.ExecuteAfter), 0).UTC(), time.Unix(int64(oe.ExecuteBefore), 0).UTC())
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package outside
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/curve"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestTypeHashes tests the SNIP-12 type hashes against the constants used by
+// the OpenZeppelin and Argent account implementations.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestTypeHashes(t *testing.T) {
+	require.Equal(t, "0x11ff76fe3f640fa6f3d60bbd94a3b9d47141a2c96f87fdcfbeb2af1d03f7050", outsideExecutionTypeHashV1.String())
+	require.Equal(t, "0x312b56c05a7965066ddbda31c016d8d05afc305071c0ca3cdc2192c3c2f1f0f", outsideExecutionTypeHashV2.String())
+	require.Equal(t, "0x3635c7f2a7ba93844c0d064e18e487f35ab90f7c39d00f186a781fc3f0c2ca9", callTypeHashV2.String())
+	require.Equal(t, "0x1ff2f602e42168014d405a94f75e8a93d640751d71d16311266e140d8b0a210", domainTypeHashV2.String())
+}
+
+// TestSign tests that the signature of an outside execution verifies against
+// its message hash and that the relayed call has the expected calldata.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestSign(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	provider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+
+	ks, pub, _ := account.GetRandomKeys()
+	signerAddress := utils.TestHexToFelt(t, "0x5167e7")
+	signer, err := account.NewAccount(provider, signerAddress, pub.String(), ks, 2)
+	require.NoError(t, err)
+
+	relayer := utils.TestHexToFelt(t, "0x7e1a7e7")
+	call := rpc.FunctionCall{
+		ContractAddress:    utils.TestHexToFelt(t, "0xc0ffee"),
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{utils.Uint64ToFelt(1), utils.Uint64ToFelt(2)},
+	}
+	oe, err := NewOutsideExecution(AnyCaller, []rpc.FunctionCall{call}, time.Hour)
+	require.NoError(t, err)
+
+	for _, version := range []Version{V1, V2} {
+		signed, err := Sign(context.Background(), signer, oe, version)
+		require.NoError(t, err)
+		require.NoError(t, signed.CheckValid(relayer, time.Now()))
+		require.Error(t, signed.CheckValid(relayer, time.Now().Add(2*time.Hour)))
+
+		msgHash, err := oe.MessageHash(version, signer.ChainId, signerAddress)
+		require.NoError(t, err)
+		pubY := curve.Curve.GetYCoordinate(utils.FeltToBigInt(pub))
+		require.True(t, curve.Curve.Verify(utils.FeltToBigInt(msgHash), utils.FeltToBigInt(signed.Signature[0]),
+			utils.FeltToBigInt(signed.Signature[1]), utils.FeltToBigInt(pub), pubY))
+
+		relayed, err := signed.Call()
+		require.NoError(t, err)
+		require.Equal(t, signerAddress, relayed.ContractAddress)
+		require.Equal(t, []*felt.Felt{
+			AnyCaller, oe.Nonce, utils.Uint64ToFelt(oe.ExecuteAfter), utils.Uint64ToFelt(oe.ExecuteBefore),
+			utils.Uint64ToFelt(1), call.ContractAddress, call.EntryPointSelector, utils.Uint64ToFelt(2), call.Calldata[0], call.Calldata[1],
+			utils.Uint64ToFelt(2), signed.Signature[0], signed.Signature[1],
+		}, relayed.Calldata)
+	}
+}
@@ -0,0 +1,225 @@
+// Package paymaster is a client for paymaster services implementing the
+// SNIP-29 API, e.g. AVNU. A paymaster relays the calls of an account as a
+// SNIP-9 outside execution and pays the fee of the transaction, either for
+// free (sponsored mode) or against a payment in a gas token (default mode).
+package paymaster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/outside"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// TransactionTypeInvoke is the type of the transactions built by the paymaster
+// for the calls of an account.
+const TransactionTypeInvoke = "invoke"
+
+// ExecutionParametersVersion is the version of the SNIP-29 execution parameters.
+const ExecutionParametersVersion = "0x1"
+
+// Client calls a paymaster service.
+type Client struct {
+	c rpc.CallCloser
+}
+
+// NewClient creates a client for the paymaster service behind c.
+//
+// Parameters:
+// - c: the JSON-RPC client of the paymaster service
+// Returns:
+// - *Client: the client
+func NewClient(c rpc.CallCloser) *Client {
+	return &Client{c: c}
+}
+
+// Close closes the underlying JSON-RPC client.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (c *Client) Close() {
+	c.c.Close()
+}
+
+// IsAvailable returns whether the paymaster service accepts transactions.
+//
+// Parameters:
+// - ctx: the context
+// Returns:
+// - bool: true if the service is available
+// - error: an error if the call fails
+func (c *Client) IsAvailable(ctx context.Context) (bool, error) {
+	var available bool
+	if err := c.c.CallContext(ctx, &available, "paymaster_isAvailable"); err != nil {
+		return false, err
+	}
+	return available, nil
+}
+
+// SupportedTokens returns the gas tokens the fee can be paid in.
+//
+// Parameters:
+// - ctx: the context
+// Returns:
+// - []TokenData: the gas tokens
+// - error: an error if the call fails
+func (c *Client) SupportedTokens(ctx context.Context) ([]TokenData, error) {
+	var tokens []TokenData
+	if err := c.c.CallContext(ctx, &tokens, "paymaster_getSupportedTokens"); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// BuildTransaction asks the paymaster to prepare a transaction. For an invoke,
+// the response holds the typed data of the outside execution to sign.
+//
+// Parameters:
+// - ctx: the context
+// - tx: the transaction of the user
+// - params: the execution parameters
+// Returns:
+// - *BuildTransactionResponse: the prepared transaction and its fee
+// - error: an error if the call fails
+func (c *Client) BuildTransaction(ctx context.Context, tx UserTransaction, params ExecutionParameters) (*BuildTransactionResponse, error) {
+	var resp BuildTransactionResponse
+	if err := c.c.CallContext(ctx, &resp, "paymaster_buildTransaction", tx, params); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ExecuteTransaction sends a transaction signed by the user to the paymaster.
+//
+// Parameters:
+// - ctx: the context
+// - tx: the signed transaction
+// - params: the execution parameters, as returned by BuildTransaction
+// Returns:
+// - *ExecuteTransactionResponse: the hash of the transaction sent by the paymaster
+// - error: an error if the call fails
+func (c *Client) ExecuteTransaction(ctx context.Context, tx ExecutableUserTransaction, params ExecutionParameters) (*ExecuteTransactionResponse, error) {
+	var resp ExecuteTransactionResponse
+	if err := c.c.CallContext(ctx, &resp, "paymaster_executeTransaction", tx, params); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Execute has the paymaster execute the calls of an account. The typed data
+// built by the paymaster is checked to be bound to the chain of the account
+// and to run the requested calls, plus the fee payment in the default mode,
+// before it is signed.
+//
+// Parameters:
+// - ctx: the context
+// - acc: the account executing the calls
+// - calls: the calls
+// - params: the execution parameters
+// Returns:
+// - *ExecuteTransactionResponse: the hash of the transaction sent by the paymaster
+// - error: an error if the paymaster fails or builds an unexpected transaction
+func (c *Client) Execute(ctx context.Context, acc *account.Account, calls []rpc.FunctionCall, params ExecutionParameters) (*ExecuteTransactionResponse, error) {
+	if params.Version == "" {
+		params.Version = ExecutionParametersVersion
+	}
+	invoke := &UserInvoke{UserAddress: acc.AccountAddress, Calls: make([]Call, len(calls))}
+	for i, call := range calls {
+		invoke.Calls[i] = Call{To: call.ContractAddress, Selector: call.EntryPointSelector, Calldata: call.Calldata}
+	}
+	built, err := c.BuildTransaction(ctx, UserTransaction{Type: TransactionTypeInvoke, Invoke: invoke}, params)
+	if err != nil {
+		return nil, err
+	}
+	if built.Type != TransactionTypeInvoke {
+		return nil, fmt.Errorf("paymaster: built a %s transaction for an invoke", built.Type)
+	}
+
+	oe, err := parseOutsideExecution(built.TypedData, acc.ChainId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCalls(oe, calls, params.FeeMode); err != nil {
+		return nil, err
+	}
+	msgHash, err := oe.MessageHash(outside.V2, acc.ChainId, acc.AccountAddress)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := acc.Sign(ctx, msgHash)
+	if err != nil {
+		return nil, err
+	}
+
+	executable := ExecutableUserTransaction{
+		Type:   TransactionTypeInvoke,
+		Invoke: &ExecutableUserInvoke{UserAddress: acc.AccountAddress, TypedData: built.TypedData, Signature: signature},
+	}
+	return c.ExecuteTransaction(ctx, executable, built.Parameters)
+}
+
+// Sponsor executes the calls of Account.Execute through a paymaster.
+type Sponsor struct {
+	client *Client
+	params ExecutionParameters
+}
+
+// NewSponsor creates a Sponsor sending the calls to the paymaster with the
+// given parameters.
+//
+// Parameters:
+// - client: the paymaster client
+// - params: the execution parameters
+// Returns:
+// - *Sponsor: the sponsor
+func NewSponsor(client *Client, params ExecutionParameters) *Sponsor {
+	return &Sponsor{client: client, params: params}
+}
+
+// ExecuteSponsored implements account.Sponsor.
+//
+// Parameters:
+// - ctx: the context
+// - acc: the account executing the calls
+// - calls: the calls
+// Returns:
+// - *rpc.AddInvokeTransactionResponse: the hash of the transaction sent by the paymaster
+// - error: an error if the paymaster fails or builds an unexpected transaction
+func (s *Sponsor) ExecuteSponsored(ctx context.Context, acc *account.Account, calls []rpc.FunctionCall) (*rpc.AddInvokeTransactionResponse, error) {
+	resp, err := s.client.Execute(ctx, acc, calls, s.params)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.AddInvokeTransactionResponse{TransactionHash: resp.TransactionHash}, nil
+}
+
+// WithPaymaster makes Account.Execute send the calls through the paymaster,
+// which pays the fee in the sponsored mode.
+//
+// Parameters:
+// - client: the paymaster client
+// Returns:
+// - account.ExecuteOption: the option
+func WithPaymaster(client *Client) account.ExecuteOption {
+	return account.WithSponsor(NewSponsor(client, ExecutionParameters{FeeMode: FeeMode{Mode: FeeModeSponsored}}))
+}
+
+// WithGasToken makes Account.Execute send the calls through the paymaster,
+// paying the fee to the paymaster in the gas token.
+//
+// Parameters:
+// - client: the paymaster client
+// - gasToken: the address of the gas token, one of SupportedTokens
+// Returns:
+// - account.ExecuteOption: the option
+func WithGasToken(client *Client, gasToken *felt.Felt) account.ExecuteOption {
+	return account.WithSponsor(NewSponsor(client, ExecutionParameters{FeeMode: FeeMode{Mode: FeeModeDefault, GasToken: gasToken}}))
+}
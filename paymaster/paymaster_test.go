@@ -0,0 +1,170 @@
+package paymaster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/curve"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/outside"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// fakeService is a paymaster service answering with canned typed data.
+type fakeService struct {
+	typedData string
+	executed  *ExecutableUserTransaction
+}
+
+// CallContext answers the paymaster methods.
+//
+// Parameters:
+// - ctx: the context
+// - result: the value the response is decoded into
+// - method: the method
+// - args: the arguments
+// Returns:
+// - error: an error if the method is unknown
+func (s *fakeService) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var resp string
+	switch method {
+	case "paymaster_isAvailable":
+		resp = "true"
+	case "paymaster_getSupportedTokens":
+		resp = `[{"token_address":"0x4718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d","decimals":18,"price_in_strk":"0x1"}]`
+	case "paymaster_buildTransaction":
+		resp = fmt.Sprintf(`{"type":"invoke","typed_data":%s,"parameters":{"version":"0x1","fee_mode":{"mode":"sponsored"}},"fee":{}}`, s.typedData)
+	case "paymaster_executeTransaction":
+		tx := args[0].(ExecutableUserTransaction)
+		s.executed = &tx
+		resp = `{"tracking_id":"0x1","transaction_hash":"0xabc"}`
+	default:
+		return fmt.Errorf("unknown method %s", method)
+	}
+	return json.Unmarshal([]byte(resp), result)
+}
+
+// Close does nothing.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (s *fakeService) Close() {}
+
+// outsideTypedDataJSON returns the typed data of a V2 outside execution of the calls.
+//
+// Parameters:
+// - chainID: the chain ID in the domain
+// - calls: the calls, with selectors given by name
+// Returns:
+// - string: the typed data
+func outsideTypedDataJSON(chainID string, calls ...string) string {
+	callsJSON := "["
+	for i, call := range calls {
+		if i > 0 {
+			callsJSON += ","
+		}
+		callsJSON += call
+	}
+	callsJSON += "]"
+	return fmt.Sprintf(`{
+		"types": {},
+		"primaryType": "OutsideExecution",
+		"domain": {"name": "Account.execute_from_outside", "version": "2", "chainId": %q, "revision": "1"},
+		"message": {"Caller": "0x414e595f43414c4c4552", "Nonce": "0x7", "Execute After": "1", "Execute Before": 1700000000, "Calls": %s}
+	}`, chainID, callsJSON)
+}
+
+// TestExecute tests that Account.Execute signs the outside execution built by
+// the paymaster and that unexpected typed data is rejected.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestExecute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	provider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil)
+
+	ks, pub, _ := account.GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0x5167e7")
+	acc, err := account.NewAccount(provider, address, pub.String(), ks, 2)
+	require.NoError(t, err)
+
+	call := rpc.FunctionCall{
+		ContractAddress:    utils.TestHexToFelt(t, "0xc0ffee"),
+		EntryPointSelector: utils.GetSelectorFromNameFelt("transfer"),
+		Calldata:           []*felt.Felt{utils.Uint64ToFelt(1), utils.Uint64ToFelt(2)},
+	}
+	callJSON := `{"To": "0xc0ffee", "Selector": "transfer", "Calldata": ["1", "0x2"]}`
+	service := &fakeService{typedData: outsideTypedDataJSON("SN_SEPOLIA", callJSON)}
+	client := NewClient(service)
+
+	available, err := client.IsAvailable(context.Background())
+	require.NoError(t, err)
+	require.True(t, available)
+	tokens, err := client.SupportedTokens(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, uint8(18), tokens[0].Decimals)
+
+	resp, err := acc.Execute(context.Background(), []rpc.FunctionCall{call}, WithPaymaster(client))
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0xabc"), resp.TransactionHash)
+
+	oe := &outside.OutsideExecution{
+		Caller:        outside.AnyCaller,
+		Nonce:         utils.Uint64ToFelt(7),
+		ExecuteAfter:  1,
+		ExecuteBefore: 1700000000,
+		Calls:         []rpc.FunctionCall{call},
+	}
+	msgHash, err := oe.MessageHash(outside.V2, acc.ChainId, address)
+	require.NoError(t, err)
+	signature := service.executed.Invoke.Signature
+	pubY := curve.Curve.GetYCoordinate(utils.FeltToBigInt(pub))
+	require.True(t, curve.Curve.Verify(utils.FeltToBigInt(msgHash), utils.FeltToBigInt(signature[0]),
+		utils.FeltToBigInt(signature[1]), utils.FeltToBigInt(pub), pubY))
+
+	service.executed = nil
+	service.typedData = outsideTypedDataJSON("SN_MAIN", callJSON)
+	_, err = acc.Execute(context.Background(), []rpc.FunctionCall{call}, WithPaymaster(client))
+	require.Error(t, err)
+
+	service.typedData = outsideTypedDataJSON("SN_SEPOLIA", callJSON, `{"To": "0xbad", "Selector": "transfer", "Calldata": []}`)
+	_, err = acc.Execute(context.Background(), []rpc.FunctionCall{call}, WithPaymaster(client))
+	require.Error(t, err)
+	require.Nil(t, service.executed)
+}
+
+// TestCheckCalls tests that the fee payment is only accepted in the default
+// mode, to the gas token.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestCheckCalls(t *testing.T) {
+	gasToken := utils.TestHexToFelt(t, "0x5757")
+	call := rpc.FunctionCall{ContractAddress: utils.TestHexToFelt(t, "0xc0ffee"), EntryPointSelector: utils.GetSelectorFromNameFelt("transfer")}
+	payment := rpc.FunctionCall{ContractAddress: gasToken, EntryPointSelector: utils.GetSelectorFromNameFelt("transfer")}
+	oe := &outside.OutsideExecution{Calls: []rpc.FunctionCall{call, payment}}
+
+	require.NoError(t, checkCalls(oe, []rpc.FunctionCall{call}, FeeMode{Mode: FeeModeDefault, GasToken: gasToken}))
+	require.Error(t, checkCalls(oe, []rpc.FunctionCall{call}, FeeMode{Mode: FeeModeSponsored}))
+	require.Error(t, checkCalls(oe, []rpc.FunctionCall{call, call}, FeeMode{Mode: FeeModeDefault, GasToken: gasToken}))
+}
@@ -0,0 +1,212 @@
+package paymaster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/outside"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var ErrUnsupportedTypedData = errors.New("paymaster: unsupported typed data")
+
+// typedValue is a SNIP-12 value, given either as a JSON number or as a
+// decimal, hexadecimal or short string.
+type typedValue string
+
+// UnmarshalJSON accepts JSON strings and numbers.
+//
+// Parameters:
+// - data: the JSON value
+// Returns:
+// - error: an error if data is neither a string nor a number
+func (v *typedValue) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*v = typedValue(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("%w: %s is not a value", ErrUnsupportedTypedData, data)
+	}
+	*v = typedValue(n.String())
+	return nil
+}
+
+// felt returns the value as a felt. Strings that are not numbers are short strings.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the value
+func (v typedValue) felt() *felt.Felt {
+	if b, ok := new(big.Int).SetString(string(v), 0); ok {
+		return utils.BigIntToFelt(b)
+	}
+	return new(felt.Felt).SetBytes([]byte(v))
+}
+
+// selector returns the value as an entrypoint selector. Strings that are not
+// numbers are entrypoint names.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the selector
+func (v typedValue) selector() *felt.Felt {
+	if b, ok := new(big.Int).SetString(string(v), 0); ok {
+		return utils.BigIntToFelt(b)
+	}
+	return utils.GetSelectorFromNameFelt(string(v))
+}
+
+// uint64 returns the value as a uint64.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - uint64: the value
+// - error: an error if the value does not fit in 64 bits
+func (v typedValue) uint64() (uint64, error) {
+	f := v.felt()
+	n := f.Uint64()
+	if !f.Equal(new(felt.Felt).SetUint64(n)) {
+		return 0, fmt.Errorf("%w: %s does not fit in 64 bits", ErrUnsupportedTypedData, v)
+	}
+	return n, nil
+}
+
+// outsideTypedData is the SNIP-12 revision 1 typed data of a SNIP-9 V2 outside execution.
+type outsideTypedData struct {
+	PrimaryType string `json:"primaryType"`
+	Domain      struct {
+		Name     typedValue `json:"name"`
+		Version  typedValue `json:"version"`
+		ChainID  typedValue `json:"chainId"`
+		Revision typedValue `json:"revision"`
+	} `json:"domain"`
+	Message struct {
+		Caller        typedValue `json:"Caller"`
+		Nonce         typedValue `json:"Nonce"`
+		ExecuteAfter  typedValue `json:"Execute After"`
+		ExecuteBefore typedValue `json:"Execute Before"`
+		Calls         []struct {
+			To       typedValue   `json:"To"`
+			Selector typedValue   `json:"Selector"`
+			Calldata []typedValue `json:"Calldata"`
+		} `json:"Calls"`
+	} `json:"message"`
+}
+
+// parseOutsideExecution extracts the outside execution from the typed data
+// built by the paymaster and checks it is bound to the chain.
+//
+// Parameters:
+// - raw: the typed data
+// - chainID: the chain ID of the account
+// Returns:
+// - *outside.OutsideExecution: the outside execution
+// - error: an error if the typed data is not a SNIP-9 V2 outside execution for the chain
+func parseOutsideExecution(raw json.RawMessage, chainID *felt.Felt) (*outside.OutsideExecution, error) {
+	var td outsideTypedData
+	if err := json.Unmarshal(raw, &td); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedTypedData, err)
+	}
+	if td.PrimaryType != "OutsideExecution" || td.Domain.Revision.felt().Uint64() != 1 || td.Domain.Version.felt().Uint64() != 2 {
+		return nil, fmt.Errorf("%w: expected a SNIP-9 V2 outside execution, got %s revision %s version %s",
+			ErrUnsupportedTypedData, td.PrimaryType, td.Domain.Revision, td.Domain.Version)
+	}
+	if !strings.EqualFold(string(td.Domain.Name), "Account.execute_from_outside") {
+		return nil, fmt.Errorf("%w: unexpected domain %s", ErrUnsupportedTypedData, td.Domain.Name)
+	}
+	if !td.Domain.ChainID.felt().Equal(chainID) {
+		return nil, fmt.Errorf("%w: typed data is for chain %s", ErrUnsupportedTypedData, td.Domain.ChainID)
+	}
+
+	executeAfter, err := td.Message.ExecuteAfter.uint64()
+	if err != nil {
+		return nil, err
+	}
+	executeBefore, err := td.Message.ExecuteBefore.uint64()
+	if err != nil {
+		return nil, err
+	}
+	oe := &outside.OutsideExecution{
+		Caller:        td.Message.Caller.felt(),
+		Nonce:         td.Message.Nonce.felt(),
+		ExecuteAfter:  executeAfter,
+		ExecuteBefore: executeBefore,
+		Calls:         make([]rpc.FunctionCall, len(td.Message.Calls)),
+	}
+	for i, call := range td.Message.Calls {
+		calldata := make([]*felt.Felt, len(call.Calldata))
+		for j, v := range call.Calldata {
+			calldata[j] = v.felt()
+		}
+		oe.Calls[i] = rpc.FunctionCall{
+			ContractAddress:    call.To.felt(),
+			EntryPointSelector: call.Selector.selector(),
+			Calldata:           calldata,
+		}
+	}
+	return oe, nil
+}
+
+// checkCalls checks that the outside execution runs the requested calls, in
+// order. In the default fee mode, calls to the gas token paying the
+// paymaster may be interleaved.
+//
+// Parameters:
+// - oe: the outside execution built by the paymaster
+// - requested: the calls requested by the user
+// - feeMode: the fee mode of the transaction
+// Returns:
+// - error: an error if the outside execution runs other calls
+func checkCalls(oe *outside.OutsideExecution, requested []rpc.FunctionCall, feeMode FeeMode) error {
+	next := 0
+	for _, call := range oe.Calls {
+		if next < len(requested) && sameCall(call, requested[next]) {
+			next++
+			continue
+		}
+		if feeMode.Mode == FeeModeDefault && feeMode.GasToken != nil && call.ContractAddress.Equal(feeMode.GasToken) {
+			continue
+		}
+		return fmt.Errorf("paymaster: typed data contains an unexpected call to %s", call.ContractAddress)
+	}
+	if next != len(requested) {
+		return fmt.Errorf("paymaster: typed data is missing %d requested calls", len(requested)-next)
+	}
+	return nil
+}
+
+// sameCall returns whether two calls are identical.
+//
+// Parameters:
+// - a: the first call
+// - b: the second call
+// Returns:
+// - bool: true if the calls are identical
+func sameCall(a, b rpc.FunctionCall) bool {
+	if !a.ContractAddress.Equal(b.ContractAddress) || !a.EntryPointSelector.Equal(b.EntryPointSelector) || len(a.Calldata) != len(b.Calldata) {
+		return false
+	}
+	for i := range a.Calldata {
+		if !a.Calldata[i].Equal(b.Calldata[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package paymaster
+
+import (
+	"encoding/json"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// Fee modes of a sponsored transaction.
+const (
+	// FeeModeSponsored makes the paymaster pay the fee
+	FeeModeSponsored = "sponsored"
+	// FeeModeDefault makes the user pay the fee to the paymaster in a gas token
+	FeeModeDefault = "default"
+)
+
+// TokenData describes a gas token accepted by the paymaster.
+type TokenData struct {
+	TokenAddress *felt.Felt `json:"token_address"`
+	Decimals     uint8      `json:"decimals"`
+	PriceInStrk  *felt.Felt `json:"price_in_strk"`
+}
+
+// FeeMode selects who pays the fee of a sponsored transaction.
+type FeeMode struct {
+	Mode string `json:"mode"`
+	// GasToken is the token the fee is paid in, in the default mode
+	GasToken *felt.Felt `json:"gas_token,omitempty"`
+}
+
+// TimeBounds restricts when the paymaster may execute the transaction.
+type TimeBounds struct {
+	ExecuteAfter  uint64 `json:"execute_after"`
+	ExecuteBefore uint64 `json:"execute_before"`
+}
+
+// ExecutionParameters are the parameters of a sponsored transaction.
+type ExecutionParameters struct {
+	Version    string      `json:"version"`
+	FeeMode    FeeMode     `json:"fee_mode"`
+	TimeBounds *TimeBounds `json:"time_bounds,omitempty"`
+}
+
+// Call is a call of a sponsored transaction.
+type Call struct {
+	To       *felt.Felt   `json:"to"`
+	Selector *felt.Felt   `json:"selector"`
+	Calldata []*felt.Felt `json:"calldata"`
+}
+
+// UserInvoke is the invoke the user asks the paymaster to sponsor.
+type UserInvoke struct {
+	UserAddress *felt.Felt `json:"user_address"`
+	Calls       []Call     `json:"calls"`
+}
+
+// UserTransaction is the transaction passed to paymaster_buildTransaction.
+type UserTransaction struct {
+	Type   string      `json:"type"`
+	Invoke *UserInvoke `json:"invoke,omitempty"`
+}
+
+// FeeEstimate is the fee quoted by the paymaster.
+type FeeEstimate struct {
+	GasTokenPriceInStrk       *felt.Felt `json:"gas_token_price_in_strk"`
+	EstimatedFeeInStrk        *felt.Felt `json:"estimated_fee_in_strk"`
+	EstimatedFeeInGasToken    *felt.Felt `json:"estimated_fee_in_gas_token"`
+	SuggestedMaxFeeInStrk     *felt.Felt `json:"suggested_max_fee_in_strk"`
+	SuggestedMaxFeeInGasToken *felt.Felt `json:"suggested_max_fee_in_gas_token"`
+}
+
+// BuildTransactionResponse is the transaction prepared by the paymaster. The
+// typed data is signed by the user and sent back with ExecuteTransaction.
+type BuildTransactionResponse struct {
+	Type       string              `json:"type"`
+	TypedData  json.RawMessage     `json:"typed_data"`
+	Parameters ExecutionParameters `json:"parameters"`
+	Fee        FeeEstimate         `json:"fee"`
+}
+
+// ExecutableUserInvoke is the signed invoke passed to paymaster_executeTransaction.
+type ExecutableUserInvoke struct {
+	UserAddress *felt.Felt      `json:"user_address"`
+	TypedData   json.RawMessage `json:"typed_data"`
+	Signature   []*felt.Felt    `json:"signature"`
+}
+
+// ExecutableUserTransaction is the transaction passed to paymaster_executeTransaction.
+type ExecutableUserTransaction struct {
+	Type   string                `json:"type"`
+	Invoke *ExecutableUserInvoke `json:"invoke,omitempty"`
+}
+
+// ExecuteTransactionResponse identifies the transaction sent by the paymaster.
+type ExecuteTransactionResponse struct {
+	TrackingID      *felt.Felt `json:"tracking_id"`
+	TransactionHash *felt.Felt `json:"transaction_hash"`
+}
@@ -0,0 +1,149 @@
+// Package presets provides call builders for the admin entrypoints of the
+// OpenZeppelin Cairo contract presets (Ownable, Upgradeable, Pausable and
+// AccessControl components).
+//
+// Each builder returns an rpc.FunctionCall that can be passed to
+// Account.FmtCalldata and sent from the account holding the admin rights.
+package presets
+
+import (
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// Entrypoint names of the OpenZeppelin Cairo components.
+const (
+	TransferOwnershipEntrypoint = "transfer_ownership"
+	RenounceOwnershipEntrypoint = "renounce_ownership"
+	UpgradeEntrypoint           = "upgrade"
+	PauseEntrypoint             = "pause"
+	UnpauseEntrypoint           = "unpause"
+	GrantRoleEntrypoint         = "grant_role"
+	RevokeRoleEntrypoint        = "revoke_role"
+	RenounceRoleEntrypoint      = "renounce_role"
+)
+
+// DefaultAdminRole is the AccessControl role allowed to grant and revoke every role.
+var DefaultAdminRole = new(felt.Felt)
+
+// RoleID returns the identifier of an AccessControl role, computed by the
+// presets as selector!("<name>"), e.g. RoleID("MINTER_ROLE").
+//
+// Parameters:
+// - name: the role name
+// Returns:
+// - *felt.Felt: the role identifier
+func RoleID(name string) *felt.Felt {
+	return utils.GetSelectorFromNameFelt(name)
+}
+
+// newCall builds the call to an entrypoint of a contract.
+//
+// Parameters:
+// - contract: the address of the contract
+// - entrypoint: the name of the entrypoint
+// - calldata: the arguments of the entrypoint
+// Returns:
+// - rpc.FunctionCall: the call
+func newCall(contract *felt.Felt, entrypoint string, calldata ...*felt.Felt) rpc.FunctionCall {
+	if calldata == nil {
+		calldata = []*felt.Felt{}
+	}
+	return rpc.FunctionCall{
+		ContractAddress:    contract,
+		EntryPointSelector: utils.GetSelectorFromNameCached(entrypoint),
+		Calldata:           calldata,
+	}
+}
+
+// TransferOwnership builds the Ownable call transferring the ownership of a contract.
+//
+// Parameters:
+// - contract: the address of the contract
+// - newOwner: the address of the new owner
+// Returns:
+// - rpc.FunctionCall: the transfer_ownership call
+func TransferOwnership(contract, newOwner *felt.Felt) rpc.FunctionCall {
+	return newCall(contract, TransferOwnershipEntrypoint, newOwner)
+}
+
+// RenounceOwnership builds the Ownable call leaving a contract without owner.
+//
+// Parameters:
+// - contract: the address of the contract
+// Returns:
+// - rpc.FunctionCall: the renounce_ownership call
+func RenounceOwnership(contract *felt.Felt) rpc.FunctionCall {
+	return newCall(contract, RenounceOwnershipEntrypoint)
+}
+
+// Upgrade builds the Upgradeable call replacing the class of a contract, whose
+// upgrade takes the new class hash like the OpenZeppelin account.
+//
+// Parameters:
+// - contract: the address of the contract
+// - newClassHash: the hash of the declared class to upgrade to
+// Returns:
+// - rpc.FunctionCall: the upgrade call
+func Upgrade(contract, newClassHash *felt.Felt) rpc.FunctionCall {
+	return account.UpgradeCall(contract, newClassHash, account.FlavorOpenZeppelin)
+}
+
+// Pause builds the call pausing a Pausable contract.
+//
+// Parameters:
+// - contract: the address of the contract
+// Returns:
+// - rpc.FunctionCall: the pause call
+func Pause(contract *felt.Felt) rpc.FunctionCall {
+	return newCall(contract, PauseEntrypoint)
+}
+
+// Unpause builds the call unpausing a Pausable contract.
+//
+// Parameters:
+// - contract: the address of the contract
+// Returns:
+// - rpc.FunctionCall: the unpause call
+func Unpause(contract *felt.Felt) rpc.FunctionCall {
+	return newCall(contract, UnpauseEntrypoint)
+}
+
+// GrantRole builds the AccessControl call granting a role to an account.
+//
+// Parameters:
+// - contract: the address of the contract
+// - role: the role identifier, see RoleID
+// - account: the address receiving the role
+// Returns:
+// - rpc.FunctionCall: the grant_role call
+func GrantRole(contract, role, account *felt.Felt) rpc.FunctionCall {
+	return newCall(contract, GrantRoleEntrypoint, role, account)
+}
+
+// RevokeRole builds the AccessControl call revoking a role from an account.
+//
+// Parameters:
+// - contract: the address of the contract
+// - role: the role identifier, see RoleID
+// - account: the address losing the role
+// Returns:
+// - rpc.FunctionCall: the revoke_role call
+func RevokeRole(contract, role, account *felt.Felt) rpc.FunctionCall {
+	return newCall(contract, RevokeRoleEntrypoint, role, account)
+}
+
+// RenounceRole builds the AccessControl call by which the caller gives up one
+// of its roles. The account must be the address of the caller.
+//
+// Parameters:
+// - contract: the address of the contract
+// - role: the role identifier, see RoleID
+// - account: the address of the caller
+// Returns:
+// - rpc.FunctionCall: the renounce_role call
+func RenounceRole(contract, role, account *felt.Felt) rpc.FunctionCall {
+	return newCall(contract, RenounceRoleEntrypoint, role, account)
+}
@@ -0,0 +1,63 @@
+package presets
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestAdminCalls tests the selectors and calldata shapes of the admin call builders.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestAdminCalls(t *testing.T) {
+	contract := utils.TestHexToFelt(t, "0xc0ffee")
+	owner := utils.TestHexToFelt(t, "0xa11ce")
+	classHash := utils.TestHexToFelt(t, "0xc1a55")
+	minter := RoleID("MINTER_ROLE")
+
+	type testSetType struct {
+		Call             rpc.FunctionCall
+		ExpectedSelector string
+		ExpectedCalldata []*felt.Felt
+	}
+	testSet := []testSetType{
+		{
+			Call:             TransferOwnership(contract, owner),
+			ExpectedSelector: "0x2a3bb1eaa05b77c4b0eeee0116a3177c6d62319dd7149ae148185d9e09de74a",
+			ExpectedCalldata: []*felt.Felt{owner},
+		},
+		{
+			Call:             Upgrade(contract, classHash),
+			ExpectedSelector: "0xf2f7c15cbe06c8d94597cd91fd7f3369eae842359235712def5584f8d270cd",
+			ExpectedCalldata: []*felt.Felt{classHash},
+		},
+		{
+			Call:             Pause(contract),
+			ExpectedSelector: "0x3f618718f1cde37d9c527a9237b04e6ac0489a8647d0517bb15827758ece720",
+			ExpectedCalldata: []*felt.Felt{},
+		},
+		{
+			Call:             GrantRole(contract, minter, owner),
+			ExpectedSelector: "0x18a2f881894a5eb15a2a00f598839abaa75bd7f1fea1a37e42779d7fbcd9cf8",
+			ExpectedCalldata: []*felt.Felt{minter, owner},
+		},
+		{
+			Call:             RevokeRole(contract, DefaultAdminRole, owner),
+			ExpectedSelector: utils.GetSelectorFromNameFelt("revoke_role").String(),
+			ExpectedCalldata: []*felt.Felt{DefaultAdminRole, owner},
+		},
+	}
+
+	for _, test := range testSet {
+		require.Equal(t, contract, test.Call.ContractAddress)
+		require.Equal(t, test.ExpectedSelector, test.Call.EntryPointSelector.String())
+		require.Equal(t, test.ExpectedCalldata, test.Call.Calldata)
+	}
+}
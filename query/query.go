@@ -0,0 +1,532 @@
+// Package query bundles the reads an explorer or a wallet frontend needs
+// behind one typed API: account overviews with balances and Starknet ID
+// domains, token transfers and contract information. The results carry JSON
+// tags so that a Service can be mounted behind GraphQL or REST handlers.
+//
+// Each source can be replaced: TokenRegistry and TransferSource default to
+// reads through the provider, and may be backed by an indexer instead.
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/starknetid"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// DefaultChunkSize is the number of events read per page of transfers.
+const DefaultChunkSize = 100
+
+var (
+	ErrNotAToken     = errors.New("query: contract is not a token")
+	ErrMissingToken  = errors.New("query: a token is required")
+	transferSelector = utils.SelectorTransferEvent
+)
+
+// Token describes an ERC20 token.
+type Token struct {
+	Address  *felt.Felt `json:"address"`
+	Name     string     `json:"name"`
+	Symbol   string     `json:"symbol"`
+	Decimals uint8      `json:"decimals"`
+}
+
+// Balance is the balance of an account in a token.
+type Balance struct {
+	Token  *Token   `json:"token"`
+	Amount *big.Int `json:"amount"`
+}
+
+// AccountOverview is the state of an account.
+type AccountOverview struct {
+	Address   *felt.Felt `json:"address"`
+	ClassHash *felt.Felt `json:"class_hash"`
+	Nonce     *felt.Felt `json:"nonce"`
+	// Domain is the main Starknet ID domain of the account, empty if none
+	Domain   string    `json:"domain,omitempty"`
+	Balances []Balance `json:"balances"`
+}
+
+// Transfer is a transfer of tokens.
+type Transfer struct {
+	Token           *felt.Felt `json:"token"`
+	From            *felt.Felt `json:"from"`
+	To              *felt.Felt `json:"to"`
+	Amount          *big.Int   `json:"amount"`
+	BlockNumber     uint64     `json:"block_number"`
+	TransactionHash *felt.Felt `json:"transaction_hash"`
+}
+
+// TransferFilter selects token transfers.
+type TransferFilter struct {
+	// Token is the token contract, required
+	Token *felt.Felt
+	// Account keeps the transfers from or to an account, all if nil
+	Account *felt.Felt
+	// FromBlock and ToBlock bound the blocks, from the genesis to the latest
+	// block if nil
+	FromBlock *rpc.BlockID
+	ToBlock   *rpc.BlockID
+	// ChunkSize is the number of events read for the page, DefaultChunkSize if 0
+	ChunkSize int
+	// ContinuationToken fetches the page following a previous one
+	ContinuationToken string
+}
+
+// TransferPage is a page of transfers. Filtering by account happens on the
+// events of the page, so a page may hold fewer transfers than its chunk
+// size while more pages follow.
+type TransferPage struct {
+	Transfers         []Transfer `json:"transfers"`
+	ContinuationToken string     `json:"continuation_token,omitempty"`
+}
+
+// ContractInfo describes a deployed contract.
+type ContractInfo struct {
+	Address   *felt.Felt `json:"address"`
+	ClassHash *felt.Felt `json:"class_hash"`
+	Nonce     *felt.Felt `json:"nonce"`
+	// Sierra is true for Cairo 1 classes, false for Cairo 0 classes
+	Sierra bool `json:"sierra"`
+	// Token is set if the contract is a token
+	Token *Token `json:"token,omitempty"`
+	// Domain is the main Starknet ID domain of the contract, empty if none
+	Domain string `json:"domain,omitempty"`
+}
+
+// TokenRegistry describes tokens.
+type TokenRegistry interface {
+	// Token returns the description of a token, or an error wrapping
+	// ErrNotAToken if the contract is not a token
+	Token(ctx context.Context, address *felt.Felt) (*Token, error)
+}
+
+// TransferSource lists token transfers, e.g. from an indexer projection.
+type TransferSource interface {
+	Transfers(ctx context.Context, filter TransferFilter) (*TransferPage, error)
+}
+
+// NameResolver resolves addresses to their main domain, as starknetid.Client does.
+type NameResolver interface {
+	ReverseResolve(ctx context.Context, address *felt.Felt) (string, error)
+}
+
+var (
+	_ NameResolver   = &starknetid.Client{}
+	_ TokenRegistry  = &ProviderTokens{}
+	_ TransferSource = &EventTransfers{}
+)
+
+// Service answers the queries of frontends.
+type Service struct {
+	provider  rpc.RpcProvider
+	tokens    TokenRegistry
+	transfers TransferSource
+	names     NameResolver
+	balances  []*felt.Felt
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithTokenRegistry sets the registry describing tokens, a ProviderTokens by default.
+//
+// Parameters:
+// - tokens: the registry
+// Returns:
+// - Option: the option
+func WithTokenRegistry(tokens TokenRegistry) Option {
+	return func(s *Service) {
+		s.tokens = tokens
+	}
+}
+
+// WithTransferSource sets the source of the transfers, an EventTransfers by default.
+//
+// Parameters:
+// - transfers: the source
+// Returns:
+// - Option: the option
+func WithTransferSource(transfers TransferSource) Option {
+	return func(s *Service) {
+		s.transfers = transfers
+	}
+}
+
+// WithNameResolver sets the resolver of the domains of accounts and
+// contracts. Domains are not resolved without one.
+//
+// Parameters:
+// - names: the resolver, e.g. a starknetid.Client
+// Returns:
+// - Option: the option
+func WithNameResolver(names NameResolver) Option {
+	return func(s *Service) {
+		s.names = names
+	}
+}
+
+// WithBalances sets the tokens whose balances are part of account overviews.
+//
+// Parameters:
+// - tokens: the addresses of the tokens
+// Returns:
+// - Option: the option
+func WithBalances(tokens ...*felt.Felt) Option {
+	return func(s *Service) {
+		s.balances = tokens
+	}
+}
+
+// NewService creates a Service reading through a provider.
+//
+// Parameters:
+// - provider: the provider
+// - opts: the options
+// Returns:
+// - *Service: the service
+func NewService(provider rpc.RpcProvider, opts ...Option) *Service {
+	s := &Service{provider: provider}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.tokens == nil {
+		s.tokens = NewProviderTokens(provider)
+	}
+	if s.transfers == nil {
+		s.transfers = &EventTransfers{Provider: provider}
+	}
+	return s
+}
+
+// GetAccountOverview returns the class, nonce, domain and balances of an
+// account at the latest block.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address of the account
+// Returns:
+// - *AccountOverview: the overview
+// - error: rpc.ErrContractNotFound if the account is not deployed, or an error if a read fails
+func (s *Service) GetAccountOverview(ctx context.Context, address *felt.Felt) (*AccountOverview, error) {
+	classHash, nonce, err := s.state(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := s.domain(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	overview := &AccountOverview{Address: address, ClassHash: classHash, Nonce: nonce, Domain: domain, Balances: []Balance{}}
+	for _, tokenAddress := range s.balances {
+		token, err := s.tokens.Token(ctx, tokenAddress)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := balanceOf(ctx, s.provider, tokenAddress, address)
+		if err != nil {
+			return nil, fmt.Errorf("query: balance of %s in %s: %w", address, token.Symbol, err)
+		}
+		overview.Balances = append(overview.Balances, Balance{Token: token, Amount: amount})
+	}
+	return overview, nil
+}
+
+// GetTokenTransfers returns a page of the transfers of a token.
+//
+// Parameters:
+// - ctx: the context
+// - filter: the transfers to return
+// Returns:
+// - *TransferPage: the page
+// - error: ErrMissingToken if the filter has no token, or an error if the source fails
+func (s *Service) GetTokenTransfers(ctx context.Context, filter TransferFilter) (*TransferPage, error) {
+	if filter.Token == nil {
+		return nil, ErrMissingToken
+	}
+	return s.transfers.Transfers(ctx, filter)
+}
+
+// GetContractInfo returns the class, nonce and domain of a contract at the
+// latest block, and its description if it is a token.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address of the contract
+// Returns:
+// - *ContractInfo: the information
+// - error: rpc.ErrContractNotFound if the contract is not deployed, or an error if a read fails
+func (s *Service) GetContractInfo(ctx context.Context, address *felt.Felt) (*ContractInfo, error) {
+	classHash, nonce, err := s.state(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	class, err := s.provider.Class(ctx, rpc.WithBlockTag("latest"), classHash)
+	if err != nil {
+		return nil, err
+	}
+	_, sierra := class.(*rpc.ContractClass)
+	domain, err := s.domain(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	token, err := s.tokens.Token(ctx, address)
+	if err != nil && !errors.Is(err, ErrNotAToken) {
+		return nil, err
+	}
+	return &ContractInfo{Address: address, ClassHash: classHash, Nonce: nonce, Sierra: sierra, Token: token, Domain: domain}, nil
+}
+
+// state reads the class hash and the nonce of a contract.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address of the contract
+// Returns:
+// - *felt.Felt: the class hash
+// - *felt.Felt: the nonce
+// - error: an error if a read fails
+func (s *Service) state(ctx context.Context, address *felt.Felt) (*felt.Felt, *felt.Felt, error) {
+	classHash, err := s.provider.ClassHashAt(ctx, rpc.WithBlockTag("latest"), address)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := s.provider.Nonce(ctx, rpc.WithBlockTag("latest"), address)
+	if err != nil {
+		return nil, nil, err
+	}
+	return classHash, nonce, nil
+}
+
+// domain resolves the main domain of an address.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address
+// Returns:
+// - string: the domain, empty if there is no resolver or no domain
+// - error: an error if the resolution fails
+func (s *Service) domain(ctx context.Context, address *felt.Felt) (string, error) {
+	if s.names == nil {
+		return "", nil
+	}
+	domain, err := s.names.ReverseResolve(ctx, address)
+	if errors.Is(err, starknetid.ErrAddressNotFound) {
+		return "", nil
+	}
+	return domain, err
+}
+
+// ProviderTokens describes tokens by calling their name, symbol and decimals
+// entrypoints. Descriptions are cached, tokens being immutable.
+type ProviderTokens struct {
+	provider rpc.RpcProvider
+
+	mu     sync.Mutex
+	tokens map[felt.Felt]*Token
+}
+
+// NewProviderTokens creates a registry calling tokens through a provider.
+//
+// Parameters:
+// - provider: the provider
+// Returns:
+// - *ProviderTokens: the registry
+func NewProviderTokens(provider rpc.RpcProvider) *ProviderTokens {
+	return &ProviderTokens{provider: provider, tokens: map[felt.Felt]*Token{}}
+}
+
+// Token returns the description of a token. Names and symbols are decoded
+// from short strings or from byte arrays.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address of the token
+// Returns:
+// - *Token: the description
+// - error: an error wrapping ErrNotAToken if an entrypoint fails or returns an unexpected value
+func (r *ProviderTokens) Token(ctx context.Context, address *felt.Felt) (*Token, error) {
+	r.mu.Lock()
+	token, ok := r.tokens[*address]
+	r.mu.Unlock()
+	if ok {
+		return token, nil
+	}
+
+	token = &Token{Address: address}
+	for _, field := range []struct {
+		entrypoint string
+		value      *string
+	}{{"name", &token.Name}, {"symbol", &token.Symbol}} {
+		result, err := call(ctx, r.provider, address, field.entrypoint)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s: %v", ErrNotAToken, address, field.entrypoint, err)
+		}
+		if *field.value, err = decodeString(result); err != nil {
+			return nil, fmt.Errorf("%w: %s: %s: %v", ErrNotAToken, address, field.entrypoint, err)
+		}
+	}
+	result, err := call(ctx, r.provider, address, "decimals")
+	if err != nil || len(result) != 1 || utils.FeltToBigInt(result[0]).Cmp(big.NewInt(255)) > 0 {
+		return nil, fmt.Errorf("%w: %s: unexpected decimals %v, %v", ErrNotAToken, address, result, err)
+	}
+	token.Decimals = uint8(result[0].Uint64())
+
+	r.mu.Lock()
+	r.tokens[*address] = token
+	r.mu.Unlock()
+	return token, nil
+}
+
+// EventTransfers lists the transfers of a token from its Transfer events,
+// emitted with the Cairo 0 layout (from, to and amount in the data) or the
+// Cairo 1 layout (from and to in the keys).
+type EventTransfers struct {
+	Provider rpc.RpcProvider
+}
+
+// Transfers returns a page of the transfers of a token.
+//
+// Parameters:
+// - ctx: the context
+// - filter: the transfers to return
+// Returns:
+// - *TransferPage: the page
+// - error: an error if the events can not be read
+func (e *EventTransfers) Transfers(ctx context.Context, filter TransferFilter) (*TransferPage, error) {
+	input := rpc.EventsInput{
+		EventFilter:       rpc.EventFilter{FromBlock: rpc.WithBlockNumber(0), ToBlock: rpc.WithBlockTag("latest"), Address: filter.Token, Keys: [][]*felt.Felt{{transferSelector}}},
+		ResultPageRequest: rpc.ResultPageRequest{ChunkSize: filter.ChunkSize, ContinuationToken: filter.ContinuationToken},
+	}
+	if filter.FromBlock != nil {
+		input.FromBlock = *filter.FromBlock
+	}
+	if filter.ToBlock != nil {
+		input.ToBlock = *filter.ToBlock
+	}
+	if input.ChunkSize == 0 {
+		input.ChunkSize = DefaultChunkSize
+	}
+	chunk, err := e.Provider.Events(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	page := &TransferPage{Transfers: []Transfer{}, ContinuationToken: chunk.ContinuationToken}
+	for _, event := range chunk.Events {
+		transfer, ok := decodeTransfer(event)
+		if !ok {
+			continue
+		}
+		if filter.Account != nil && !transfer.From.Equal(filter.Account) && !transfer.To.Equal(filter.Account) {
+			continue
+		}
+		page.Transfers = append(page.Transfers, transfer)
+	}
+	return page, nil
+}
+
+// decodeTransfer decodes a Transfer event.
+//
+// Parameters:
+// - event: the event
+// Returns:
+// - Transfer: the transfer
+// - bool: false if the event does not have one of the Transfer layouts
+func decodeTransfer(event rpc.EmittedEvent) (Transfer, bool) {
+	var from, to, low, high *felt.Felt
+	switch {
+	case len(event.Keys) == 1 && len(event.Data) == 4:
+		from, to, low, high = event.Data[0], event.Data[1], event.Data[2], event.Data[3]
+	case len(event.Keys) == 3 && len(event.Data) == 2:
+		from, to, low, high = event.Keys[1], event.Keys[2], event.Data[0], event.Data[1]
+	default:
+		return Transfer{}, false
+	}
+	amount, err := utils.FeltsToUint256(low, high)
+	if err != nil {
+		return Transfer{}, false
+	}
+	return Transfer{
+		Token:           event.FromAddress,
+		From:            from,
+		To:              to,
+		Amount:          amount.BigInt(),
+		BlockNumber:     event.BlockNumber,
+		TransactionHash: event.TransactionHash,
+	}, true
+}
+
+// balanceOf reads the balance of an account in a token, with the balanceOf
+// entrypoint or, if it fails, the balance_of entrypoint.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - token: the address of the token
+// - account: the address of the account
+// Returns:
+// - *big.Int: the balance
+// - error: an error if both entrypoints fail
+func balanceOf(ctx context.Context, provider rpc.RpcProvider, token, account *felt.Felt) (*big.Int, error) {
+	result, err := call(ctx, provider, token, "balanceOf", account)
+	if err != nil {
+		result, err = call(ctx, provider, token, "balance_of", account)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(result) != 2 {
+		return nil, fmt.Errorf("unexpected balance %v", result)
+	}
+	amount, err := utils.FeltsToUint256(result[0], result[1])
+	if err != nil {
+		return nil, err
+	}
+	return amount.BigInt(), nil
+}
+
+// call calls an entrypoint of a contract at the latest block.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - contract: the address of the contract
+// - entrypoint: the name of the entrypoint
+// - calldata: the arguments
+// Returns:
+// - []*felt.Felt: the result
+// - error: an error if the call fails
+func call(ctx context.Context, provider rpc.RpcProvider, contract *felt.Felt, entrypoint string, calldata ...*felt.Felt) ([]*felt.Felt, error) {
+	if calldata == nil {
+		calldata = []*felt.Felt{}
+	}
+	return provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    contract,
+		EntryPointSelector: utils.GetSelectorFromNameCached(entrypoint),
+		Calldata:           calldata,
+	}, rpc.WithBlockTag("latest"))
+}
+
+// decodeString decodes a string returned by a contract, a short string or a
+// byte array.
+//
+// Parameters:
+// - result: the result of the call
+// Returns:
+// - string: the string
+// - error: an error if the result is empty or not a valid byte array
+func decodeString(result []*felt.Felt) (string, error) {
+	switch len(result) {
+	case 0:
+		return "", errors.New("empty result")
+	case 1:
+		return utils.HexToShortStr(result[0].String()), nil
+	}
+	return utils.ByteArrayFeltsToString(result)
+}
@@ -0,0 +1,130 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/starknetid"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// names is a NameResolver with fixed domains.
+type names map[felt.Felt]string
+
+// ReverseResolve returns the domain of an address.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address
+// Returns:
+// - string: the domain
+// - error: starknetid.ErrAddressNotFound if the address has no domain
+func (n names) ReverseResolve(ctx context.Context, address *felt.Felt) (string, error) {
+	if domain, ok := n[*address]; ok {
+		return domain, nil
+	}
+	return "", starknetid.ErrAddressNotFound
+}
+
+// TestService tests the account overview, the contract information and the
+// transfers of a token.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	account := utils.TestHexToFelt(t, "0xacc")
+	other := utils.TestHexToFelt(t, "0xb0b")
+	token := utils.TestHexToFelt(t, "0x70c")
+	classHash := utils.TestHexToFelt(t, "0xc1a55")
+
+	calls := map[string][]*felt.Felt{
+		"0x70c/name":             utils.StringToByteArrayFelts("Starknet Token"),
+		"0x70c/symbol":           {new(felt.Felt).SetBytes([]byte("STRK"))},
+		"0x70c/decimals":         {utils.Uint64ToFelt(18)},
+		"0x70c/balance_of/0xacc": {utils.Uint64ToFelt(1500), new(felt.Felt)},
+	}
+	selectors := map[string]string{}
+	for _, entrypoint := range []string{"name", "symbol", "decimals", "balanceOf", "balance_of"} {
+		selectors[utils.GetSelectorFromNameFelt(entrypoint).String()] = entrypoint
+	}
+	callCount := 0
+	provider.EXPECT().Call(gomock.Any(), gomock.Any(), rpc.WithBlockTag("latest")).DoAndReturn(
+		func(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+			callCount++
+			key := call.ContractAddress.String() + "/" + selectors[call.EntryPointSelector.String()]
+			for _, arg := range call.Calldata {
+				key += "/" + arg.String()
+			}
+			if result, ok := calls[key]; ok {
+				return result, nil
+			}
+			return nil, rpc.ErrContractError
+		}).AnyTimes()
+	provider.EXPECT().ClassHashAt(gomock.Any(), rpc.WithBlockTag("latest"), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ rpc.BlockID, address *felt.Felt) (*felt.Felt, error) {
+			if address.Equal(other) {
+				return nil, rpc.ErrContractNotFound
+			}
+			return classHash, nil
+		}).AnyTimes()
+	provider.EXPECT().Nonce(gomock.Any(), rpc.WithBlockTag("latest"), gomock.Any()).Return(utils.Uint64ToFelt(3), nil).AnyTimes()
+	provider.EXPECT().Class(gomock.Any(), rpc.WithBlockTag("latest"), classHash).Return(&rpc.ContractClass{}, nil).AnyTimes()
+
+	service := NewService(provider, WithBalances(token), WithNameResolver(names{*account: "alice.stark"}))
+
+	overview, err := service.GetAccountOverview(context.Background(), account)
+	require.NoError(t, err)
+	require.Equal(t, "alice.stark", overview.Domain)
+	require.Equal(t, "0x3", overview.Nonce.String())
+	require.Len(t, overview.Balances, 1)
+	require.Equal(t, &Token{Address: token, Name: "Starknet Token", Symbol: "STRK", Decimals: 18}, overview.Balances[0].Token)
+	require.Equal(t, big.NewInt(1500), overview.Balances[0].Amount)
+	_, err = service.GetAccountOverview(context.Background(), other)
+	require.True(t, errors.Is(err, rpc.ErrContractNotFound))
+
+	info, err := service.GetContractInfo(context.Background(), token)
+	require.NoError(t, err)
+	require.True(t, info.Sierra)
+	require.Equal(t, "", info.Domain)
+	require.Equal(t, "STRK", info.Token.Symbol)
+	info, err = service.GetContractInfo(context.Background(), account)
+	require.NoError(t, err)
+	require.Nil(t, info.Token)
+	require.Equal(t, 6, callCount, "the token description is cached")
+
+	provider.EXPECT().Events(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, input rpc.EventsInput) (*rpc.EventChunk, error) {
+			require.Equal(t, token, input.Address)
+			require.Equal(t, [][]*felt.Felt{{transferSelector}}, input.Keys)
+			require.Equal(t, DefaultChunkSize, input.ChunkSize)
+			require.Equal(t, rpc.WithBlockNumber(10), input.FromBlock)
+			return &rpc.EventChunk{ContinuationToken: "next", Events: []rpc.EmittedEvent{
+				{Event: rpc.Event{FromAddress: token, Keys: []*felt.Felt{transferSelector}, Data: []*felt.Felt{other, account, utils.Uint64ToFelt(5), new(felt.Felt)}}, BlockNumber: 11},
+				{Event: rpc.Event{FromAddress: token, Keys: []*felt.Felt{transferSelector, account, other}, Data: []*felt.Felt{utils.Uint64ToFelt(7), new(felt.Felt)}}, BlockNumber: 12},
+				{Event: rpc.Event{FromAddress: token, Keys: []*felt.Felt{transferSelector, other, other}, Data: []*felt.Felt{utils.Uint64ToFelt(9), new(felt.Felt)}}, BlockNumber: 13},
+				{Event: rpc.Event{FromAddress: token, Keys: []*felt.Felt{transferSelector}, Data: []*felt.Felt{other}}, BlockNumber: 14},
+			}}, nil
+		})
+	from := rpc.WithBlockNumber(10)
+	page, err := service.GetTokenTransfers(context.Background(), TransferFilter{Token: token, Account: account, FromBlock: &from})
+	require.NoError(t, err)
+	require.Equal(t, "next", page.ContinuationToken)
+	require.Equal(t, []Transfer{
+		{Token: token, From: other, To: account, Amount: big.NewInt(5), BlockNumber: 11},
+		{Token: token, From: account, To: other, Amount: big.NewInt(7), BlockNumber: 12},
+	}, page.Transfers)
+	_, err = service.GetTokenTransfers(context.Background(), TransferFilter{})
+	require.Equal(t, ErrMissingToken, err)
+}
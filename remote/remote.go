@@ -0,0 +1,265 @@
+// Package remote orchestrates transactions signed in a remote wallet. The
+// backend proposes a transaction or a typed data signature to the wallet of
+// the user over a Transport, e.g. a relay server or a push channel, and waits
+// for the wallet to answer with the transaction hash or the signature.
+package remote
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+)
+
+// DefaultTimeout is the time the wallet has to answer a proposal.
+const DefaultTimeout = 5 * time.Minute
+
+var (
+	ErrTimeout         = errors.New("remote: proposal timed out")
+	ErrRejected        = errors.New("remote: proposal rejected by the wallet")
+	ErrUnknownProposal = errors.New("remote: unknown or expired proposal")
+)
+
+// Method is the request a proposal makes to the wallet.
+type Method string
+
+const (
+	// MethodInvoke asks the wallet to sign and send an invoke transaction
+	MethodInvoke Method = "starknet_addInvokeTransaction"
+	// MethodSignTypedData asks the wallet to sign SNIP-12 typed data
+	MethodSignTypedData Method = "starknet_signTypedData"
+)
+
+// Proposal is a request to the wallet.
+type Proposal struct {
+	ID     string `json:"id"`
+	Method Method `json:"method"`
+	// Account is the address of the account expected to answer
+	Account *felt.Felt `json:"account"`
+	// Calls are the calls of an invoke proposal
+	Calls []rpc.FunctionCall `json:"calls,omitempty"`
+	// TypedData is the typed data of a signature proposal
+	TypedData json.RawMessage `json:"typed_data,omitempty"`
+	// ExpiresAt is the time the proposal is abandoned at
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Response is the answer of the wallet to a proposal.
+type Response struct {
+	ID string `json:"id"`
+	// Rejected is set when the user declined the proposal
+	Rejected bool `json:"rejected,omitempty"`
+	// Error is the error of the wallet, if any
+	Error string `json:"error,omitempty"`
+	// TransactionHash is the hash of the transaction sent for an invoke proposal
+	TransactionHash *felt.Felt `json:"transaction_hash,omitempty"`
+	// Signature is the signature of a typed data proposal
+	Signature []*felt.Felt `json:"signature,omitempty"`
+}
+
+// Transport delivers proposals to the wallet. The responses of the wallet are
+// handed back with Client.Deliver.
+type Transport interface {
+	Send(ctx context.Context, proposal *Proposal) error
+}
+
+// Client proposes transactions to remote wallets and waits for their answers.
+type Client struct {
+	transport Transport
+	timeout   time.Duration
+
+	mu      sync.Mutex
+	pending map[string]chan *Response
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithTimeout sets the time the wallet has to answer a proposal. It defaults
+// to DefaultTimeout.
+//
+// Parameters:
+// - timeout: the timeout
+// Returns:
+// - Option: the option
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// NewClient creates a Client sending proposals over the transport.
+//
+// Parameters:
+// - transport: the transport to the wallets
+// - opts: the client options
+// Returns:
+// - *Client: the client
+func NewClient(transport Transport, opts ...Option) *Client {
+	c := &Client{
+		transport: transport,
+		timeout:   DefaultTimeout,
+		pending:   map[string]chan *Response{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ProposeInvoke asks the wallet of the account to send the calls.
+//
+// Parameters:
+// - ctx: the context
+// - account: the address of the account
+// - calls: the calls
+// Returns:
+// - *felt.Felt: the hash of the transaction sent by the wallet
+// - error: ErrRejected, ErrTimeout, or an error of the transport or the wallet
+func (c *Client) ProposeInvoke(ctx context.Context, account *felt.Felt, calls []rpc.FunctionCall) (*felt.Felt, error) {
+	resp, err := c.Propose(ctx, &Proposal{Method: MethodInvoke, Account: account, Calls: calls})
+	if err != nil {
+		return nil, err
+	}
+	if resp.TransactionHash == nil {
+		return nil, fmt.Errorf("remote: response to proposal %s has no transaction hash", resp.ID)
+	}
+	return resp.TransactionHash, nil
+}
+
+// ProposeSignTypedData asks the wallet of the account to sign typed data.
+//
+// Parameters:
+// - ctx: the context
+// - account: the address of the account
+// - typedData: the SNIP-12 typed data
+// Returns:
+// - []*felt.Felt: the signature
+// - error: ErrRejected, ErrTimeout, or an error of the transport or the wallet
+func (c *Client) ProposeSignTypedData(ctx context.Context, account *felt.Felt, typedData json.RawMessage) ([]*felt.Felt, error) {
+	resp, err := c.Propose(ctx, &Proposal{Method: MethodSignTypedData, Account: account, TypedData: typedData})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Signature) == 0 {
+		return nil, fmt.Errorf("remote: response to proposal %s has no signature", resp.ID)
+	}
+	return resp.Signature, nil
+}
+
+// Propose sends a proposal and waits for the answer of the wallet, until the
+// proposal expires or ctx is done. The ID and the expiry of the proposal are
+// set when empty.
+//
+// Parameters:
+// - ctx: the context
+// - proposal: the proposal
+// Returns:
+// - *Response: the response of the wallet
+// - error: ErrRejected, ErrTimeout, or an error of the transport or the wallet
+func (c *Client) Propose(ctx context.Context, proposal *Proposal) (*Response, error) {
+	if proposal.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return nil, err
+		}
+		proposal.ID = id
+	}
+	if proposal.ExpiresAt.IsZero() {
+		proposal.ExpiresAt = time.Now().Add(c.timeout)
+	}
+
+	ch := make(chan *Response, 1)
+	c.mu.Lock()
+	if _, ok := c.pending[proposal.ID]; ok {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("remote: proposal %s is already pending", proposal.ID)
+	}
+	c.pending[proposal.ID] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, proposal.ID)
+		c.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithDeadline(ctx, proposal.ExpiresAt)
+	defer cancel()
+	if err := c.transport.Send(ctx, proposal); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-ch:
+		switch {
+		case resp.Rejected:
+			return nil, ErrRejected
+		case resp.Error != "":
+			return nil, fmt.Errorf("remote: wallet error: %s", resp.Error)
+		}
+		return resp, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrTimeout
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// Deliver hands the response of a wallet to the pending proposal.
+//
+// Parameters:
+// - resp: the response
+// Returns:
+// - error: ErrUnknownProposal if no proposal is waiting for the response
+func (c *Client) Deliver(resp *Response) error {
+	c.mu.Lock()
+	ch, ok := c.pending[resp.ID]
+	if ok {
+		delete(c.pending, resp.ID)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return ErrUnknownProposal
+	}
+	ch <- resp
+	return nil
+}
+
+// Pending returns the number of proposals waiting for an answer.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - int: the number of pending proposals
+func (c *Client) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending)
+}
+
+// newID returns a random proposal ID.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the ID
+// - error: an error if the random source fails
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,73 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// wallet is a Transport answering proposals with a canned response.
+type wallet struct {
+	client  *Client
+	respond func(proposal *Proposal) *Response
+}
+
+// Send answers the proposal asynchronously, as a remote wallet would.
+//
+// Parameters:
+// - ctx: the context
+// - proposal: the proposal
+// Returns:
+// - error: always nil
+func (w *wallet) Send(ctx context.Context, proposal *Proposal) error {
+	if resp := w.respond(proposal); resp != nil {
+		go func() {
+			_ = w.client.Deliver(resp)
+		}()
+	}
+	return nil
+}
+
+// TestClient tests approved, rejected and unanswered proposals.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestClient(t *testing.T) {
+	account := utils.TestHexToFelt(t, "0xacc")
+	txHash := utils.TestHexToFelt(t, "0xabc")
+	w := &wallet{}
+	client := NewClient(w, WithTimeout(50*time.Millisecond))
+	w.client = client
+
+	w.respond = func(p *Proposal) *Response {
+		if p.Method == MethodInvoke {
+			return &Response{ID: p.ID, TransactionHash: txHash}
+		}
+		return &Response{ID: p.ID, Signature: []*felt.Felt{utils.Uint64ToFelt(1), utils.Uint64ToFelt(2)}}
+	}
+	hash, err := client.ProposeInvoke(context.Background(), account, []rpc.FunctionCall{{ContractAddress: account}})
+	require.NoError(t, err)
+	require.Equal(t, txHash, hash)
+	signature, err := client.ProposeSignTypedData(context.Background(), account, json.RawMessage(`{}`))
+	require.NoError(t, err)
+	require.Len(t, signature, 2)
+
+	w.respond = func(p *Proposal) *Response { return &Response{ID: p.ID, Rejected: true} }
+	_, err = client.ProposeInvoke(context.Background(), account, nil)
+	require.Equal(t, ErrRejected, err)
+
+	w.respond = func(p *Proposal) *Response { return nil }
+	_, err = client.ProposeInvoke(context.Background(), account, nil)
+	require.Equal(t, ErrTimeout, err)
+	require.Equal(t, 0, client.Pending())
+	require.Equal(t, ErrUnknownProposal, client.Deliver(&Response{ID: "late"}))
+}
@@ -0,0 +1,290 @@
+// Package revert turns the revert reasons of Starknet transactions, as found
+// in execution errors, simulation traces and REVERTED receipts, into readable
+// errors. Cairo panics carry felts holding short strings or ByteArrays; the
+// decoder extracts the messages they encode and maps well-known messages to
+// sentinel errors usable with errors.Is.
+package revert
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var (
+	ErrInsufficientBalance   = errors.New("insufficient balance")
+	ErrInsufficientAllowance = errors.New("insufficient allowance")
+	ErrInvalidSignature      = errors.New("invalid signature")
+	ErrInvalidNonce          = errors.New("invalid nonce")
+	ErrEntrypointNotFound    = errors.New("entrypoint not found")
+	ErrOutOfGas              = errors.New("out of gas")
+)
+
+// known maps well-known panic messages to sentinel errors.
+var known = map[string]error{
+	"ERC20: insufficient balance":            ErrInsufficientBalance,
+	"ERC20: transfer amount exceeds balance": ErrInsufficientBalance,
+	"ERC20: insufficient allowance":          ErrInsufficientAllowance,
+	"u256_sub Overflow":                      ErrInsufficientBalance,
+	"argent/invalid-signature":               ErrInvalidSignature,
+	"Account: invalid signature":             ErrInvalidSignature,
+	"argent/invalid-tx-version":              ErrInvalidNonce,
+	"Invalid transaction nonce":              ErrInvalidNonce,
+	"ENTRYPOINT_NOT_FOUND":                   ErrEntrypointNotFound,
+	"Out of gas":                             ErrOutOfGas,
+}
+
+// byteArrayMagic is the first felt of the panic data of a ByteArray panic,
+// e.g. panic!("...") in Cairo.
+var byteArrayMagic, _ = new(felt.Felt).SetString("0x46a6158a16a947e5916b2a2ca68501a45e93d7110e81aa2d6438b1c57c879a3")
+
+var (
+	hexPattern    = regexp.MustCompile(`0x[0-9a-fA-F]{1,64}`)
+	quotedPattern = regexp.MustCompile(`\('((?:[^'\\]|\\.)*)'\)`)
+)
+
+// Error is a decoded revert reason.
+type Error struct {
+	// Reason is the raw revert reason
+	Reason string
+	// Messages are the messages decoded from the reason, in order of appearance
+	Messages []string
+}
+
+// Error returns the decoded messages, or the raw reason if none were found.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the error message
+func (e *Error) Error() string {
+	if len(e.Messages) == 0 {
+		return e.Reason
+	}
+	return strings.Join(e.Messages, ": ")
+}
+
+// Unwrap returns the sentinel errors of the well-known messages.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []error: the sentinel errors
+func (e *Error) Unwrap() []error {
+	var errs []error
+	for _, msg := range e.Messages {
+		if err := known[msg]; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// DecodePanicData decodes the felts of a Cairo panic into messages. A
+// ByteArray panic yields a single message, other felts are decoded as short
+// strings, or kept in hexadecimal when they are not printable.
+//
+// Parameters:
+// - data: the panic data
+// Returns:
+// - []string: the messages
+func DecodePanicData(data []*felt.Felt) []string {
+	if len(data) > 0 && data[0].Equal(byteArrayMagic) {
+		if msg, ok := decodeByteArray(data[1:]); ok {
+			return []string{msg}
+		}
+	}
+	messages := make([]string, len(data))
+	for i, f := range data {
+		if msg, ok := shortString(f); ok {
+			messages[i] = msg
+		} else {
+			messages[i] = f.String()
+		}
+	}
+	return messages
+}
+
+// Decode extracts the messages of a revert reason. Messages already decoded
+// by the node, quoted as in 0x...('message'), are kept; felts holding
+// printable short strings or ByteArrays are decoded.
+//
+// Parameters:
+// - reason: the revert reason
+// Returns:
+// - *Error: the decoded reason
+func Decode(reason string) *Error {
+	e := &Error{Reason: reason}
+	seen := map[string]bool{}
+	add := func(msg string) {
+		if msg != "" && !seen[msg] {
+			seen[msg] = true
+			e.Messages = append(e.Messages, msg)
+		}
+	}
+
+	quoted := quotedPattern.FindAllStringSubmatchIndex(reason, -1)
+	inQuote := func(i int) bool {
+		for _, q := range quoted {
+			if i >= q[0] && i < q[1] {
+				return true
+			}
+		}
+		return false
+	}
+	hexes := hexPattern.FindAllStringIndex(reason, -1)
+	felts := make([]*felt.Felt, 0, len(hexes))
+	for _, loc := range hexes {
+		if inQuote(loc[0]) {
+			continue
+		}
+		f, err := utils.HexToFelt(reason[loc[0]:loc[1]])
+		if err != nil {
+			continue
+		}
+		felts = append(felts, f)
+	}
+	for i := 0; i < len(felts); i++ {
+		if felts[i].Equal(byteArrayMagic) {
+			if n, ok := byteArrayLen(felts[i+1:]); ok {
+				if msg, ok := decodeByteArray(felts[i+1 : i+1+n]); ok {
+					add(msg)
+					i += n
+					continue
+				}
+			}
+		}
+		if msg, ok := shortString(felts[i]); ok {
+			add(msg)
+		}
+	}
+	for _, q := range quoted {
+		add(reason[q[2]:q[3]])
+	}
+	return e
+}
+
+// FromReceipt returns the decoded revert reason of a REVERTED receipt.
+//
+// Parameters:
+// - receipt: the receipt
+// Returns:
+// - error: the decoded reason, or nil if the transaction succeeded
+func FromReceipt(receipt rpc.TransactionReceipt) error {
+	r := rpc.Receipt{TransactionReceipt: receipt}
+	if r.ExecutionStatus() != rpc.TxnExecutionStatusREVERTED {
+		return nil
+	}
+	return Decode(r.RevertReason())
+}
+
+// FromError decodes the revert reason held by an execution error of the
+// provider, e.g. a contract error returned by Call or EstimateFee.
+//
+// Parameters:
+// - err: the error
+// Returns:
+// - error: the decoded reason, or err if it holds none
+func FromError(err error) error {
+	var rpcErr *rpc.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Data() == nil {
+		return err
+	}
+	reason, ok := reasonOf(rpcErr.Data())
+	if !ok {
+		return err
+	}
+	return Decode(reason)
+}
+
+// reasonOf returns the revert reason of the data of an error.
+//
+// Parameters:
+// - data: the data of the error
+// Returns:
+// - string: the reason
+// - bool: true if the data holds a reason
+func reasonOf(data any) (string, bool) {
+	switch data := data.(type) {
+	case string:
+		return data, data != ""
+	case map[string]any:
+		for _, key := range []string{"revert_error", "execution_error", "revert_reason"} {
+			if reason, ok := reasonOf(data[key]); ok {
+				return reason, true
+			}
+		}
+	case json.RawMessage:
+		var decoded any
+		if json.Unmarshal(data, &decoded) == nil {
+			return reasonOf(decoded)
+		}
+	}
+	return "", false
+}
+
+// byteArrayLen returns the number of felts of the ByteArray serialization at
+// the start of data.
+//
+// Parameters:
+// - data: the felts
+// Returns:
+// - int: the number of felts
+// - bool: false if data is too short
+func byteArrayLen(data []*felt.Felt) (int, bool) {
+	if len(data) == 0 {
+		return 0, false
+	}
+	words := data[0].Uint64()
+	if !data[0].Equal(new(felt.Felt).SetUint64(words)) || words > uint64(len(data)) {
+		return 0, false
+	}
+	n := int(words) + 3
+	return n, n > 0 && n <= len(data)
+}
+
+// decodeByteArray decodes the ByteArray serialization at the start of data.
+//
+// Parameters:
+// - data: the felts
+// Returns:
+// - string: the string
+// - bool: false if data does not start with a ByteArray
+func decodeByteArray(data []*felt.Felt) (string, bool) {
+	n, ok := byteArrayLen(data)
+	if !ok {
+		return "", false
+	}
+	msg, err := utils.ByteArrayFeltsToString(data[:n])
+	return msg, err == nil
+}
+
+// shortString decodes a felt holding a printable short string.
+//
+// Parameters:
+// - f: the felt
+// Returns:
+// - string: the string
+// - bool: false if the felt is zero or not printable
+func shortString(f *felt.Felt) (string, bool) {
+	b := f.Bytes()
+	s := strings.TrimLeft(string(b[:]), "\x00")
+	if len(s) < 2 {
+		return "", false
+	}
+	for _, c := range s {
+		if c < 0x20 || c > 0x7e {
+			return "", false
+		}
+	}
+	return s, true
+}
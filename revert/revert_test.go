@@ -0,0 +1,65 @@
+package revert
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestDecode tests the decoding of revert reasons in the formats returned by
+// nodes: messages quoted by the node, raw short strings and ByteArray panics.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDecode(t *testing.T) {
+	quoted := Decode("Error in the called contract (0x4d1085d194e2f228ea8bfd3cf341217784dc6cf28dba2319d71264b4e7c94c7):\n" +
+		"Error at pc=0:4835:\nExecution failed. Failure reason: 0x45524332303a20696e73756666696369656e742062616c616e6365 ('ERC20: insufficient balance').")
+	require.Equal(t, []string{"ERC20: insufficient balance"}, quoted.Messages)
+	require.True(t, errors.Is(quoted, ErrInsufficientBalance))
+
+	raw := Decode("Execution failed. Failure reason: (0x753235365f737562204f766572666c6f77, 0x454e545259504f494e545f4641494c4544).")
+	require.Equal(t, "u256_sub Overflow: ENTRYPOINT_FAILED", raw.Error())
+	require.True(t, errors.Is(raw, ErrInsufficientBalance))
+	require.False(t, errors.Is(raw, ErrInvalidSignature))
+
+	msg := "Ownable: caller is not the owner of this contract"
+	panicData := append([]*felt.Felt{byteArrayMagic}, utils.StringToByteArrayFelts(msg)...)
+	hexes := make([]string, len(panicData))
+	for i, f := range panicData {
+		hexes[i] = f.String()
+	}
+	byteArray := Decode("Failure reason: (" + strings.Join(hexes, ", ") + ").")
+	require.Equal(t, []string{msg}, byteArray.Messages)
+	require.Equal(t, []string{msg}, DecodePanicData(panicData))
+	require.Equal(t, []string{"argent/invalid-signature", "0x1"},
+		DecodePanicData([]*felt.Felt{new(felt.Felt).SetBytes([]byte("argent/invalid-signature")), utils.Uint64ToFelt(1)}))
+
+	require.Equal(t, "no felts here", Decode("no felts here").Error())
+}
+
+// TestFrom tests the extraction of revert reasons from receipts and errors.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestFrom(t *testing.T) {
+	reason := "Failure reason: 0x617267656e742f696e76616c69642d7369676e6174757265."
+	reverted := rpc.InvokeTransactionReceipt{ExecutionStatus: rpc.TxnExecutionStatusREVERTED, RevertReason: reason}
+	require.True(t, errors.Is(FromReceipt(reverted), ErrInvalidSignature))
+	require.NoError(t, FromReceipt(rpc.InvokeTransactionReceipt{ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED}))
+
+	err := rpc.Err(rpc.InternalError, map[string]any{"revert_error": reason})
+	require.True(t, errors.Is(FromError(err), ErrInvalidSignature))
+	other := errors.New("connection refused")
+	require.Equal(t, other, FromError(other))
+}
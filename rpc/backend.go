@@ -0,0 +1,158 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Backend serves the methods of the provider with Go values instead of
+// JSON-RPC, e.g. a gRPC client of a node or a reader of the database of a
+// local node, so that the requests and results are not encoded as JSON.
+type Backend interface {
+	// Serve serves a method with the params the provider passes, e.g. a
+	// BlockID and a *felt.Felt for starknet_getNonce, and returns its
+	// result, e.g. a *felt.Felt, or an *RPCError such as ErrContractNotFound.
+	// A nil result is returned by the provider as not found.
+	Serve(ctx context.Context, method string, params []interface{}) (interface{}, error)
+}
+
+// BackendClient is a CallCloser serving the requests with a Backend. The
+// results of the backend are set in the values of the provider when they are
+// of their types, and converted through JSON otherwise, so that a backend
+// returns the types of the package for the methods on its hot path and any
+// JSON-compatible value for the others. It implements BatchCallCloser.
+//
+// Interceptors work on JSON: a BackendClient wrapped with Intercept has its
+// results converted through JSON.
+type BackendClient struct {
+	backend Backend
+}
+
+var _ BatchCallCloser = &BackendClient{}
+
+// NewBackendClient creates a client serving the requests with backend.
+//
+// Parameters:
+// - backend: the backend
+// Returns:
+// - *BackendClient: the client
+func NewBackendClient(backend Backend) *BackendClient {
+	return &BackendClient{backend: backend}
+}
+
+// NewBackendProvider creates a provider serving the requests with backend.
+//
+// Parameters:
+// - backend: the backend
+// - opts: the options of the provider
+// Returns:
+// - *Provider: the provider
+func NewBackendProvider(backend Backend, opts ...ProviderOption) *Provider {
+	return NewProvider(NewBackendClient(backend), opts...)
+}
+
+// CallContext serves a request and sets its result.
+//
+// Parameters:
+// - ctx: the context
+// - result: a pointer to the value the result is set in
+// - method: the method
+// - args: the parameters
+// Returns:
+// - error: the error of the backend, or an error of the conversion of the result
+func (c *BackendClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	value, err := c.backend.Serve(ctx, method, args)
+	if err != nil {
+		return err
+	}
+	return setResult(result, value)
+}
+
+// BatchCallContext serves the requests of a batch in order. The error of
+// each request is set in its BatchElem.
+//
+// Parameters:
+// - ctx: the context
+// - b: the batch
+// Returns:
+// - error: the error of the context
+func (c *BackendClient) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	for i := range b {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b[i].Error = c.CallContext(ctx, b[i].Result, b[i].Method, b[i].Args...)
+	}
+	return nil
+}
+
+// Close closes the backend if it implements Close.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (c *BackendClient) Close() {
+	if closer, ok := c.backend.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
+
+// call serves a request for do, returning errNotFound for a nil result as
+// do does for an empty JSON result.
+//
+// Parameters:
+// - ctx: the context
+// - result: a pointer to the value the result is set in
+// - method: the method
+// - args: the parameters
+// Returns:
+// - error: the error of the backend, errNotFound, or an error of the conversion of the result
+func (c *BackendClient) call(ctx context.Context, result interface{}, method string, args []interface{}) error {
+	value, err := c.backend.Serve(ctx, method, args)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return errNotFound
+	}
+	return setResult(result, value)
+}
+
+// setResult sets a value in the value result points to, directly when it is
+// of its type or a pointer to it, and through JSON otherwise.
+//
+// Parameters:
+// - result: a non-nil pointer
+// - value: the value, ignored if nil
+// Returns:
+// - error: an error if result is not a pointer or the conversion fails
+func setResult(result, value interface{}) error {
+	target := reflect.ValueOf(result)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("rpc: result %T is not a non-nil pointer", result)
+	}
+	if value == nil {
+		return nil
+	}
+	elem := target.Elem()
+	v := reflect.ValueOf(value)
+	switch {
+	case v.Type().AssignableTo(elem.Type()):
+		elem.Set(v)
+	case v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Type().AssignableTo(elem.Type()):
+		elem.Set(v.Elem())
+	default:
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(raw, result)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// mapBackend is a Backend serving the storage and the nonces of a contract,
+// and the block number.
+type mapBackend struct {
+	nonce   *felt.Felt
+	storage map[felt.Felt]*felt.Felt
+	closed  bool
+}
+
+// Serve serves a method of the backend.
+//
+// Parameters:
+// - ctx: the context
+// - method: the method
+// - params: the parameters
+// Returns:
+// - interface{}: the result
+// - error: ErrContractNotFound for unknown contracts, or ErrUnexpectedError for unknown methods
+func (b *mapBackend) Serve(ctx context.Context, method string, params []interface{}) (interface{}, error) {
+	switch method {
+	case "starknet_blockNumber":
+		return uint64(42), nil
+	case "starknet_getNonce":
+		if !params[1].(*felt.Felt).Equal(new(felt.Felt).SetUint64(1)) {
+			return nil, ErrContractNotFound
+		}
+		if params[0].(BlockID).Tag != "latest" {
+			return nil, ErrBlockNotFound
+		}
+		return b.nonce, nil
+	case "starknet_getStorageAt":
+		if key, ok := params[1].(*felt.Felt); ok {
+			return b.storage[*key], nil
+		}
+		// the key of StorageAt is a string, converted through JSON
+		return b.storage[*new(felt.Felt).SetUint64(7)], nil
+	case "starknet_call":
+		return nil, nil
+	default:
+		return nil, ErrUnexpectedError
+	}
+}
+
+// Close marks the backend closed.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (b *mapBackend) Close() {
+	b.closed = true
+}
+
+// TestBackendClient tests a provider backed by a Backend: results of the
+// types of the provider set without JSON, other results converted through
+// JSON, the errors of the backend, batches and nil results.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestBackendClient(t *testing.T) {
+	backend := &mapBackend{
+		nonce:   new(felt.Felt).SetUint64(3),
+		storage: map[felt.Felt]*felt.Felt{*new(felt.Felt).SetUint64(7): new(felt.Felt).SetUint64(99)},
+	}
+	provider := NewBackendProvider(backend)
+	ctx := context.Background()
+	contract := new(felt.Felt).SetUint64(1)
+
+	number, err := provider.BlockNumber(ctx)
+	if err != nil || number != 42 {
+		t.Fatalf("expected block 42, got %d, %v", number, err)
+	}
+	nonce, err := provider.Nonce(ctx, WithBlockTag("latest"), contract)
+	if err != nil || nonce != backend.nonce {
+		t.Fatalf("expected the nonce of the backend, got %v, %v", nonce, err)
+	}
+	if _, err := provider.Nonce(ctx, WithBlockTag("latest"), new(felt.Felt)); !errors.Is(err, ErrContractNotFound) {
+		t.Fatalf("expected ErrContractNotFound, got %v", err)
+	}
+	if _, err := provider.Nonce(ctx, WithBlockNumber(1), contract); !errors.Is(err, ErrBlockNotFound) {
+		t.Fatalf("expected ErrBlockNotFound, got %v", err)
+	}
+
+	value, err := provider.StorageAt(ctx, contract, "balance", WithBlockTag("latest"))
+	if err != nil || value != "0x63" {
+		t.Fatalf("expected 0x63, got %s, %v", value, err)
+	}
+	values, err := provider.StorageAtKeys(ctx, contract, []*felt.Felt{new(felt.Felt).SetUint64(7)}, WithBlockTag("latest"))
+	if err != nil || values[*new(felt.Felt).SetUint64(7)].Uint64() != 99 {
+		t.Fatalf("expected 99, got %v, %v", values, err)
+	}
+
+	if _, err := provider.Call(ctx, FunctionCall{ContractAddress: contract}, WithBlockTag("latest")); !errors.Is(err, errNotFound) {
+		t.Fatalf("expected a nil result to be not found, got %v", err)
+	}
+	if err := setResult(number, uint64(1)); err == nil {
+		t.Fatal("expected a result that is not a pointer to be rejected")
+	}
+
+	provider.c.Close()
+	if !backend.closed {
+		t.Fatal("expected the backend to be closed")
+	}
+}
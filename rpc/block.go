@@ -3,6 +3,7 @@ package rpc
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/NethermindEth/juno/core/felt"
 )
@@ -76,15 +77,67 @@ func WithBlockTag(tag string) BlockID {
 	}
 }
 
+// BlockIDFromNumber returns the BlockID of the block with the given number.
+//
+// Parameters:
+// - n: The block number
+// Returns:
+// - BlockID: The BlockID
+func BlockIDFromNumber(n uint64) BlockID {
+	return WithBlockNumber(n)
+}
+
+// BlockIDFromHash returns the BlockID of the block with the given hash.
+//
+// Parameters:
+// - h: The block hash
+// Returns:
+// - BlockID: The BlockID
+// - error: an error wrapping ErrInvalidBlockID if the hash is nil or zero
+func BlockIDFromHash(h *felt.Felt) (BlockID, error) {
+	id := WithBlockHash(h)
+	if h == nil {
+		return BlockID{}, fmt.Errorf("%w: nil hash", ErrInvalidBlockID)
+	}
+	if err := id.Validate(); err != nil {
+		return BlockID{}, err
+	}
+	return id, nil
+}
+
+// BlockIDLatest returns the BlockID of the latest accepted block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - BlockID: The BlockID
+func BlockIDLatest() BlockID {
+	return WithBlockTag(BlockTagLatest)
+}
+
+// BlockIDPending returns the BlockID of the pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - BlockID: The BlockID
+func BlockIDPending() BlockID {
+	return WithBlockTag(BlockTagPending)
+}
+
 // BlockWithTxHashes retrieves the block with transaction hashes for the given block ID.
 //
 // Parameters:
 // - ctx: The context.Context object for controlling the function call
 // - blockID: The ID of the block to retrieve the transactions from
 // Returns:
-// - interface{}: The retrieved block
+// - *BlockTxHashesResult: The retrieved block, accepted or pending
 // - error: An error, if any
-func (provider *Provider) BlockWithTxHashes(ctx context.Context, blockID BlockID) (interface{}, error) {
+func (provider *Provider) BlockWithTxHashes(ctx context.Context, blockID BlockID) (*BlockTxHashesResult, error) {
 	var result BlockTxHashes
 	if err := do(ctx, provider.c, "starknet_getBlockWithTxHashes", &result, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
@@ -92,20 +145,19 @@ func (provider *Provider) BlockWithTxHashes(ctx context.Context, blockID BlockID
 
 	// if header.Hash == nil it's a pending block
 	if result.BlockHeader.BlockHash == nil {
-		return &PendingBlockTxHashes{
-			PendingBlockHeader{
-				ParentHash:       result.ParentHash,
-				Timestamp:        result.Timestamp,
-				SequencerAddress: result.SequencerAddress},
+		return &BlockTxHashesResult{Pending: &PendingBlockTxHashes{
+			pendingHeader(result.BlockHeader),
 			result.Transactions,
-		}, nil
+		}}, nil
 	}
 
-	return &result, nil
+	return &BlockTxHashesResult{Block: &result}, nil
 }
 
 // StateUpdate is a function that performs a state update operation
 // (gets the information about the result of executing the requested block).
+// The state update of the pending block has no block hash nor new root, see
+// StateUpdateOutput.AsPending.
 //
 // Parameters:
 // - ctx: The context.Context object for controlling the function call
@@ -146,22 +198,45 @@ func (provider *Provider) BlockTransactionCount(ctx context.Context, blockID Blo
 // - ctx: The context.Context object for the request
 // - blockID: The ID of the block to retrieve
 // Returns:
-// - interface{}: The retrieved block
+// - *BlockResult: The retrieved block, accepted or pending
 // - error: An error, if any
-func (provider *Provider) BlockWithTxs(ctx context.Context, blockID BlockID) (interface{}, error) {
+func (provider *Provider) BlockWithTxs(ctx context.Context, blockID BlockID) (*BlockResult, error) {
 	var result Block
 	if err := do(ctx, provider.c, "starknet_getBlockWithTxs", &result, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err,ErrBlockNotFound )
 	}
 	// if header.Hash == nil it's a pending block
 	if result.BlockHeader.BlockHash == nil {
-		return &PendingBlock{
-			PendingBlockHeader{
-				ParentHash:       result.ParentHash,
-				Timestamp:        result.Timestamp,
-				SequencerAddress: result.SequencerAddress},
+		return &BlockResult{Pending: &PendingBlock{
+			pendingHeader(result.BlockHeader),
+			result.Transactions,
+		}}, nil
+	}
+	return &BlockResult{Block: &result}, nil
+}
+
+// BlockWithReceipts retrieves a block with its transactions and their
+// receipts given the block id, in a single request instead of one receipt
+// request per transaction. It requires a node implementing the RPC v0.7
+// method starknet_getBlockWithReceipts.
+//
+// Parameters:
+// - ctx: The context.Context object for the request
+// - blockID: The ID of the block to retrieve
+// Returns:
+// - *BlockWithReceiptsResult: The retrieved block, accepted or pending
+// - error: An error, if any
+func (provider *Provider) BlockWithReceipts(ctx context.Context, blockID BlockID) (*BlockWithReceiptsResult, error) {
+	var result BlockWithReceipts
+	if err := do(ctx, provider.c, "starknet_getBlockWithReceipts", &result, blockID); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
+	}
+	// if header.Hash == nil it's a pending block
+	if result.BlockHeader.BlockHash == nil {
+		return &BlockWithReceiptsResult{Pending: &PendingBlockWithReceipts{
+			pendingHeader(result.BlockHeader),
 			result.Transactions,
-		}, nil
+		}}, nil
 	}
-	return &result, nil
+	return &BlockWithReceiptsResult{Block: &result}, nil
 }
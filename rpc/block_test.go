@@ -0,0 +1,136 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestBlockWithReceipts tests that the transactions of accepted and pending
+// blocks are decoded with their receipts, the receipts of accepted blocks
+// carrying the block of the header.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestBlockWithReceipts(t *testing.T) {
+	const transactions = `[{
+		"transaction": {"type": "INVOKE", "version": "0x1", "sender_address": "0x1", "calldata": [], "max_fee": "0x10", "signature": [], "nonce": "0x2"},
+		"receipt": {"type": "INVOKE", "transaction_hash": "0x3", "actual_fee": {"amount": "0x8", "unit": "WEI"}, "execution_status": "SUCCEEDED", "finality_status": "ACCEPTED_ON_L2", "messages_sent": [], "events": [{"from_address": "0x1", "keys": [], "data": []}]}
+	}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		if req.Method != "starknet_getBlockWithReceipts" {
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		raw, _ := json.Marshal(req.Params)
+		if string(raw) == `[{"block_number":7}]` {
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": {"status": "ACCEPTED_ON_L2", "block_hash": "0xb", "block_number": 7, "parent_hash": "0xa", "starknet_version": "0.13.1", "transactions": %s}}`, req.ID, transactions)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": {"parent_hash": "0xb", "starknet_version": "0.13.1", "transactions": %s}}`, req.ID, transactions)
+	}))
+	defer server.Close()
+	provider := NewProvider(NewHTTPClient(server.URL))
+
+	result, err := provider.BlockWithReceipts(context.Background(), WithBlockNumber(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, ok := result.AsBlock()
+	if !ok || block.Status != BlockStatus_AcceptedOnL2 || block.BlockNumber != 7 {
+		t.Fatalf("unexpected block %+v", result)
+	}
+	if len(block.Transactions) != 1 {
+		t.Fatalf("expected 1 transaction, got %d", len(block.Transactions))
+	}
+	if _, ok := block.Transactions[0].Transaction.(InvokeTxnV1); !ok {
+		t.Fatalf("unexpected transaction %T", block.Transactions[0].Transaction)
+	}
+	receipt := block.Transactions[0].Receipt
+	if _, ok := receipt.AsInvoke(); !ok {
+		t.Fatalf("unexpected receipt %T", receipt.TransactionReceipt)
+	}
+	if hash, number := receipt.Block(); hash.String() != "0xb" || number != 7 {
+		t.Fatalf("unexpected receipt block %s %d", hash, number)
+	}
+
+	result, err = provider.BlockWithReceipts(context.Background(), WithBlockTag("pending"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsPending() || result.Header().ParentHash.String() != "0xb" || result.Header().StarknetVersion != "0.13.1" {
+		t.Fatalf("unexpected pending block %+v", result.Header())
+	}
+	if receipt := result.Transactions()[0].Receipt; !receipt.IsPending() || len(receipt.Events()) != 1 {
+		t.Fatalf("unexpected pending receipt %+v", receipt)
+	}
+}
+
+// TestPendingBlock tests that the block-returning methods decode the pending
+// block, which has neither a hash nor a number, into the pending types.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestPendingBlock(t *testing.T) {
+	results := map[string]string{
+		"starknet_getBlockWithTxHashes":   `{"parent_hash": "0xb", "timestamp": 10, "sequencer_address": "0x5", "l1_gas_price": {"price_in_wei": "0x1"}, "starknet_version": "0.13.1", "transactions": ["0x3"]}`,
+		"starknet_getBlockWithTxs":        `{"parent_hash": "0xb", "timestamp": 10, "sequencer_address": "0x5", "l1_gas_price": {"price_in_wei": "0x1"}, "starknet_version": "0.13.1", "transactions": [{"transaction_hash": "0x3", "type": "INVOKE", "version": "0x1", "sender_address": "0x1", "calldata": [], "max_fee": "0x10", "signature": [], "nonce": "0x2"}]}`,
+		"starknet_getStateUpdate":         `{"old_root": "0xa", "state_diff": {"storage_diffs": [], "deprecated_declared_classes": [], "declared_classes": [], "deployed_contracts": [], "replaced_classes": [], "nonces": [{"contract_address": "0x1", "nonce": "0x3"}]}}`,
+		"starknet_traceBlockTransactions": `[{"transaction_hash": "0x3", "trace_root": {"type": "INVOKE", "execute_invocation": {"revert_reason": "failed"}}}]`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": %s}`, req.ID, results[req.Method])
+	}))
+	defer server.Close()
+	provider := NewProvider(NewHTTPClient(server.URL))
+	pending := WithBlockTag("pending")
+
+	hashes, err := provider.BlockWithTxHashes(context.Background(), pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hashes.Pending == nil || hashes.Pending.ParentHash.String() != "0xb" || len(hashes.Pending.Transactions) != 1 {
+		t.Fatalf("unexpected pending block %+v", hashes)
+	}
+
+	block, err := provider.BlockWithTxs(context.Background(), pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Pending == nil || block.Pending.Timestamp != 10 || len(block.Pending.BlockTransactions) != 1 {
+		t.Fatalf("unexpected pending block %+v", block)
+	}
+
+	update, err := provider.StateUpdate(context.Background(), pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pendingUpdate, ok := update.AsPending()
+	if !ok || pendingUpdate.OldRoot.String() != "0xa" || len(pendingUpdate.StateDiff.Nonces) != 1 {
+		t.Fatalf("unexpected pending state update %+v", update)
+	}
+
+	traces, err := provider.TraceBlockTransactions(context.Background(), pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(traces) != 1 || traces[0].TxnHash.String() != "0x3" {
+		t.Fatalf("unexpected pending traces %+v", traces)
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"github.com/xiang-xx/starknet.go/utils"
 )
 
-// ChainID returns the chain ID for transaction replay protection.
+// ChainID returns the chain ID for transaction replay protection. The chain
+// ID is fetched once and cached; concurrent first calls wait for the same
+// fetch, each bounded by its own context, and the lock of the provider is not
+// held during the fetch.
 //
 // Parameters:
 // - ctx: The context.Context object for the function
@@ -15,16 +18,40 @@ import (
 // - string: The chain ID
 // - error: An error if any occurred during the execution
 func (provider *Provider) ChainID(ctx context.Context) (string, error) {
+	provider.mu.Lock()
 	if provider.chainID != "" {
+		defer provider.mu.Unlock()
 		return provider.chainID, nil
 	}
+	call := provider.chainIDCall
+	if call != nil {
+		provider.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.chainID, call.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	call = &chainIDCall{done: make(chan struct{})}
+	provider.chainIDCall = call
+	provider.mu.Unlock()
+
 	var result string
 	// Note: []interface{}{}...force an empty `params[]` in the jsonrpc request
-	if err := provider.c.CallContext(ctx, &result, "starknet_chainId", []interface{}{}...); err != nil {
-		return "", err
+	call.err = provider.c.CallContext(ctx, &result, "starknet_chainId", []interface{}{}...)
+	if call.err == nil {
+		call.chainID = utils.HexToShortStr(result)
+	}
+
+	provider.mu.Lock()
+	if call.err == nil {
+		provider.chainID = call.chainID
 	}
-	provider.chainID = utils.HexToShortStr(result)
-	return provider.chainID, nil
+	provider.chainIDCall = nil
+	provider.mu.Unlock()
+	close(call.done)
+	return call.chainID, call.err
 }
 
 // Syncing retrieves the synchronization status of the provider.
@@ -10,7 +10,43 @@ type CallCloser interface {
 	Close()
 }
 
+// BatchElem is a request of a JSON-RPC batch. Result and Error are set by
+// BatchCallContext.
+type BatchElem struct {
+	Method string
+	Args   []interface{}
+	Result interface{}
+	Error  error
+}
+
+// BatchCallCloser is a CallCloser able to send several requests in a single
+// JSON-RPC batch. Providers use batches when their client implements it.
+type BatchCallCloser interface {
+	CallCloser
+	BatchCallContext(ctx context.Context, b []BatchElem) error
+}
+
+// Subscription is a subscription of a SubscriptionCallCloser.
+type Subscription interface {
+	// Err returns a channel receiving the error ending the subscription, e.g.
+	// a closed connection
+	Err() <-chan error
+	// Unsubscribe ends the subscription
+	Unsubscribe()
+}
+
+// SubscriptionCallCloser is a CallCloser able to subscribe to notifications,
+// e.g. over a websocket. Providers use subscriptions when their client
+// implements it, and poll otherwise.
+type SubscriptionCallCloser interface {
+	CallCloser
+	// Subscribe calls a subscription method and sends the results of its
+	// notifications to ch until the subscription ends
+	Subscribe(ctx context.Context, ch chan<- json.RawMessage, method string, args ...interface{}) (Subscription, error)
+}
+
 // do is a function that performs a remote procedure call (RPC) using the provided callCloser.
+// The results of a BackendClient are set without JSON.
 //
 // Parameters:
 // - ctx: represents the current execution context
@@ -21,6 +57,9 @@ type CallCloser interface {
 // Returns:
 // - error: an error if any occurred during the function call
 func do(ctx context.Context, call CallCloser, method string, data interface{}, args ...interface{}) error {
+	if client, ok := call.(*BackendClient); ok {
+		return client.call(ctx, data, method, args)
+	}
 	var raw json.RawMessage
 	err := call.CallContext(ctx, &raw, method, args...)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/contracts"
 	"github.com/xiang-xx/starknet.go/utils"
 )
 
@@ -30,6 +31,24 @@ func (provider *Provider) Class(ctx context.Context, blockID BlockID, classHash
 
 }
 
+// CompiledCasm returns the CASM class compiled from a Sierra class, with the
+// bytecode and the hints needed to execute it or to check its compiled class
+// hash.
+//
+// Parameters:
+// - ctx: The context.Context object
+// - classHash: The hash of the Sierra class
+// Returns:
+// - *contracts.CasmClass: The CASM class
+// - error: ErrCompilationError with the error of the compiler as data, or an error if any occurred during the execution
+func (provider *Provider) CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error) {
+	var casm contracts.CasmClass
+	if err := do(ctx, provider.c, "starknet_getCompiledCasm", &casm, classHash); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrClassHashNotFound, ErrCompilationError)
+	}
+	return &casm, nil
+}
+
 // ClassAt returns the class at the specified blockID and contractAddress.
 //
 // Parameters:
@@ -137,7 +156,12 @@ func (provider *Provider) Nonce(ctx context.Context, blockID BlockID, contractAd
 // Estimates the resources required by a given sequence of transactions when applied on a given state.
 // If one of the transactions reverts or fails due to any reason (e.g. validation failure or an internal error),
 // a TRANSACTION_EXECUTION_ERROR is returned. For v0-2 transactions the estimate is given in wei, and for v3 transactions it is given in fri.
+// SKIP_VALIDATE, the only flag accepted, estimates transactions of accounts not deployed yet or with placeholder signatures.
+// Nil flags are sent as no flags.
 func (provider *Provider) EstimateFee(ctx context.Context, requests []BroadcastTxn, simulationFlags []SimulationFlag, blockID BlockID) ([]FeeEstimate, error) {
+	if simulationFlags == nil {
+		simulationFlags = []SimulationFlag{}
+	}
 	var raw []FeeEstimate
 	if err := do(ctx, provider.c, "starknet_estimateFee", &raw, requests, simulationFlags, blockID); err != nil {
 		return nil, tryUnwrapToRPCErr(err, ErrTxnExec, ErrBlockNotFound)
@@ -0,0 +1,73 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// TestCompiledCasm tests that CompiledCasm decodes the bytecode and the hints
+// of the CASM class, and keeps the data of compilation errors.
+//
+// Parameters:
+// - t: The testing.T object for testing purposes
+// Returns:
+//
+//	none
+func TestCompiledCasm(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		if req.Method != "starknet_getCompiledCasm" {
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		raw, _ := json.Marshal(req.Params)
+		if string(raw) != `["0x1"]` {
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "error": {"code": 100, "message": "Failed to compile the contract", "data": {"compilation_error": "unsupported libfunc"}}}`, req.ID)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": {
+			"prime": "0x800000000000011000000000000000000000000000000000000000000000001",
+			"compiler_version": "2.6.0",
+			"bytecode": ["0xa0680017fff8000", "0x7"],
+			"bytecode_segment_lengths": [2],
+			"hints": [[0, [{"AllocSegment": {"dst": {"register": "AP", "offset": 0}}}]]],
+			"entry_points_by_type": {"CONSTRUCTOR": [], "EXTERNAL": [{"selector": "0x2", "offset": 0, "builtins": ["range_check"]}], "L1_HANDLER": []}
+		}}`, req.ID)
+	}))
+	defer server.Close()
+	provider := NewProvider(NewHTTPClient(server.URL))
+
+	casm, err := provider.CompiledCasm(context.Background(), new(felt.Felt).SetUint64(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if casm.Version != "2.6.0" || len(casm.ByteCode) != 2 || len(casm.BytecodeSegmentLengths) != 1 {
+		t.Fatalf("unexpected class %+v", casm)
+	}
+	if len(casm.Hints) != 1 || casm.Hints[0].Offset != 0 || len(casm.Hints[0].Hints) != 1 {
+		t.Fatalf("unexpected hints %+v", casm.Hints)
+	}
+	if len(casm.EntryPointByType.External) != 1 || casm.EntryPointByType.External[0].Builtins[0] != "range_check" {
+		t.Fatalf("unexpected entry points %+v", casm.EntryPointByType)
+	}
+
+	_, err = provider.CompiledCasm(context.Background(), new(felt.Felt).SetUint64(2))
+	rpcErr, ok := err.(*RPCError)
+	if !ok || rpcErr.Code() != ErrCompilationError.Code() {
+		t.Fatalf("expected ErrCompilationError, got %v", err)
+	}
+	var data struct {
+		CompilationError string `json:"compilation_error"`
+	}
+	if raw, ok := rpcErr.Data().(json.RawMessage); !ok || json.Unmarshal(raw, &data) != nil || data.CompilationError != "unsupported libfunc" {
+		t.Fatalf("unexpected data %v", rpcErr.Data())
+	}
+}
@@ -1,7 +1,6 @@
 package rpc
 
 import (
-	"encoding/json"
 	"errors"
 )
 
@@ -39,16 +38,16 @@ func Err(code int, data any) *RPCError {
 
 // tryUnwrapToRPCErr unwraps the error and checks if it matches any of the given RPC errors.
 // If a match is found, the corresponding RPC error is returned.
-// If no match is found, the function returns an InternalError with the original error.
+// If no match is found, the function returns the original error.
 //
 // Parameters:
 // - err: The error to be unwrapped
 // - rpcErrors: variadic list of *RPCError objects to be checked
 // Returns:
-// - error: the original error
+// - error: the matching RPC error, or the original error
 func tryUnwrapToRPCErr(err error, rpcErrors ...*RPCError) error {
 	var nodeErr *RPCError
-	if json.Unmarshal([]byte(err.Error()), nodeErr) != nil {
+	if !errors.As(err, &nodeErr) {
 		return err
 	}
 
@@ -58,11 +57,11 @@ func tryUnwrapToRPCErr(err error, rpcErrors ...*RPCError) error {
 	}
 
 	for _, rpcErr := range rpcErrors {
-		if errors.Is(nodeErr, rpcErr) {
+		if nodeErr.code == rpcErr.code {
 			return rpcErr
 		}
 	}
-	return Err(InternalError, err)
+	return err
 }
 
 // isErrorWithData checks if the error is the type of error that might contain information in the data field.
@@ -86,6 +85,10 @@ func isErrorWithData(nodeErr *RPCError) *RPCError {
 		contractError := *ErrContractError
 		contractError.data = nodeErr.data
 		return &contractError
+	case ErrCompilationError.code:
+		compilationError := *ErrCompilationError
+		compilationError.data = nodeErr.data
+		return &compilationError
 	}
 	return nil
 }
@@ -147,6 +150,10 @@ var (
 		code:    24,
 		message: "Block not found",
 	}
+	ErrStorageProofNotSupported = &RPCError{
+		code:    42,
+		message: "The node doesn't support storage proofs for blocks that are too far in the past",
+	}
 	ErrInvalidTxnHash = &RPCError{
 		code:    25,
 		message: "Invalid transaction hash",
@@ -247,4 +254,8 @@ var (
 		code:    63,
 		message: "An unexpected error occurred",
 	}
+	ErrCompilationError = &RPCError{
+		code:    100,
+		message: "Failed to compile the contract",
+	}
 )
@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestTryUnwrapToRPCErr tests that the errors of the node are mapped to the
+// RPC errors of their code, wrapped or not, with their data when they carry
+// some, and that the other errors, including the node errors of a code not
+// expected by the caller, are returned unchanged.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestTryUnwrapToRPCErr(t *testing.T) {
+	hashNotFound := &RPCError{code: 29, message: "Transaction hash not found"}
+	unknown := &RPCError{code: 12345, message: "Unknown error"}
+	plain := errors.New("connection refused")
+	wrappedPlain := fmt.Errorf("call: %w", plain)
+	wrappedUnknown := fmt.Errorf("call: %w", unknown)
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected []*RPCError
+		want     error
+	}{
+		{"node error", hashNotFound, []*RPCError{ErrHashNotFound}, ErrHashNotFound},
+		{"wrapped node error", fmt.Errorf("call: %w", hashNotFound), []*RPCError{ErrBlockNotFound, ErrHashNotFound}, ErrHashNotFound},
+		{"ErrHashNotFound", ErrHashNotFound, []*RPCError{ErrHashNotFound}, ErrHashNotFound},
+		{"wrapped ErrHashNotFound", fmt.Errorf("receipt: %w", ErrHashNotFound), []*RPCError{ErrHashNotFound}, ErrHashNotFound},
+		{"code not expected", hashNotFound, []*RPCError{ErrBlockNotFound}, hashNotFound},
+		{"unknown code", unknown, []*RPCError{ErrBlockNotFound, ErrHashNotFound}, unknown},
+		{"wrapped unknown code", wrappedUnknown, []*RPCError{ErrHashNotFound}, wrappedUnknown},
+		{"non-RPC error", plain, []*RPCError{ErrHashNotFound}, plain},
+		{"wrapped non-RPC error", wrappedPlain, []*RPCError{ErrHashNotFound}, wrappedPlain},
+	} {
+		if err := tryUnwrapToRPCErr(tc.err, tc.expected...); err != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	dataErr := &RPCError{code: 40, message: "Contract error", data: "revert reason"}
+	err := tryUnwrapToRPCErr(dataErr, ErrBlockNotFound)
+	var contractErr *RPCError
+	if !errors.As(err, &contractErr) || contractErr.Code() != ErrContractError.Code() || contractErr.Data() != "revert reason" {
+		t.Fatalf("expected a contract error carrying the data, got %v", err)
+	}
+	if ErrContractError.Data() != nil {
+		t.Fatal("the data must not be set on ErrContractError")
+	}
+}
@@ -0,0 +1,415 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/lifecycle"
+)
+
+// streamChunkSize is the page size of the starknet_getEvents calls of StreamEvents.
+const streamChunkSize = 100
+
+// pendingEventsRetention is the number of blocks a pending event is waited
+// for in a block before it is forgotten, e.g. when its transaction is dropped.
+const pendingEventsRetention = 10
+
+// StreamedEvent is an event delivered by StreamEvents.
+type StreamedEvent struct {
+	EmittedEvent
+	// Pending is true for an event of the pending block, whose BlockHash and
+	// BlockNumber are not set
+	Pending bool
+	// Err is the error ending the stream, set on the last event only
+	Err error
+}
+
+// eventKey identifies an event: the transaction emitting it, and its index
+// among the events of the transaction matching the filter.
+type eventKey struct {
+	transactionHash felt.Felt
+	index           int
+}
+
+// eventStream is the state of a StreamEvents stream.
+type eventStream struct {
+	provider  *Provider
+	contracts map[felt.Felt]struct{}
+	filter    EventFilter
+	events    chan<- StreamedEvent
+	// pending are the events delivered from the pending block, by the latest
+	// block number when they were delivered
+	pending map[eventKey]uint64
+}
+
+// StreamEvents delivers the events of a set of contracts from a block on, in
+// the order of the chain, then follows the chain: new blocks are looked for,
+// and the pending block polled, every poll interval. Events are delivered
+// once from the pending block, and not again when their block is accepted.
+//
+// The stream is closed on an error, delivered as the last event, or when ctx
+// is done. Delivery is at least once: restarting the stream from the block
+// number of the last event received delivers the events of that block again,
+// so that no event is lost.
+//
+// For more than one contract, the events of all contracts matching the keys
+// are read and those of the set kept, to deliver them in order.
+//
+// Parameters:
+// - ctx: The context.Context object, cancelling the stream
+// - contracts: The contracts emitting the events, all contracts if empty
+// - keys: The accepted values of the keys, as in EventFilter
+// - fromBlock: The number of the first block
+// Returns:
+// - <-chan StreamedEvent: The events, the last one carrying the error ending the stream if any
+// - error: an error if the latest block can not be read
+func (provider *Provider) StreamEvents(ctx context.Context, contracts []*felt.Felt, keys [][]*felt.Felt, fromBlock uint64) (<-chan StreamedEvent, error) {
+	latest, err := provider.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan StreamedEvent)
+	go provider.newEventStream(contracts, keys, events).run(ctx, fromBlock, latest)
+	return events, nil
+}
+
+// newEventStream creates the state of a stream of the events of a set of
+// contracts.
+//
+// Parameters:
+// - contracts: The contracts emitting the events, all contracts if empty
+// - keys: The accepted values of the keys, as in EventFilter
+// - events: The channel the events are delivered on
+// Returns:
+// - *eventStream: the state of the stream
+func (provider *Provider) newEventStream(contracts []*felt.Felt, keys [][]*felt.Felt, events chan<- StreamedEvent) *eventStream {
+	stream := &eventStream{
+		provider: provider,
+		filter:   EventFilter{Keys: keys},
+		events:   events,
+		pending:  map[eventKey]uint64{},
+	}
+	if len(contracts) == 1 {
+		stream.filter.Address = contracts[0]
+	} else if len(contracts) > 1 {
+		stream.contracts = map[felt.Felt]struct{}{}
+		for _, contract := range contracts {
+			stream.contracts[*contract] = struct{}{}
+		}
+	}
+	return stream
+}
+
+// run scans the blocks, then follows the chain, until an error or ctx is
+// done, then closes the events.
+//
+// Parameters:
+// - ctx: The context.Context object, cancelling the stream
+// - next: The number of the next block to scan
+// - latest: The number of the latest block
+// Returns:
+//
+//	none
+func (s *eventStream) run(ctx context.Context, next, latest uint64) {
+	defer close(s.events)
+	t := time.NewTicker(s.provider.pollInterval)
+	defer t.Stop()
+	for {
+		if next <= latest {
+			if !s.scan(ctx, WithBlockNumber(next), WithBlockNumber(latest), latest) {
+				return
+			}
+			next = latest + 1
+		}
+		pending := WithBlockTag(BlockTagPending)
+		if !s.scan(ctx, pending, pending, latest) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		var err error
+		if latest, err = s.provider.BlockNumber(ctx); err != nil {
+			s.fail(ctx, err)
+			return
+		}
+	}
+}
+
+// scan delivers the events of a block range, or of the pending block, not
+// delivered yet from the pending block.
+//
+// Parameters:
+// - ctx: The context.Context object, cancelling the stream
+// - from: The first block
+// - to: The last block
+// - latest: The number of the latest block
+// Returns:
+// - bool: false if the stream is over, on an error or when ctx is done
+func (s *eventStream) scan(ctx context.Context, from, to BlockID, latest uint64) bool {
+	pending := from.Tag == BlockTagPending
+	filter := s.filter
+	filter.FromBlock, filter.ToBlock = from, to
+	indexes := map[felt.Felt]int{}
+	token := ""
+	for {
+		chunk, err := s.provider.Events(ctx, EventsInput{
+			EventFilter:       filter,
+			ResultPageRequest: ResultPageRequest{ContinuationToken: token, ChunkSize: streamChunkSize},
+		})
+		if err != nil {
+			s.fail(ctx, err)
+			return false
+		}
+		for _, event := range chunk.Events {
+			if s.contracts != nil {
+				if _, ok := s.contracts[*event.FromAddress]; !ok {
+					continue
+				}
+			}
+			key := eventKey{transactionHash: *event.TransactionHash, index: indexes[*event.TransactionHash]}
+			indexes[*event.TransactionHash]++
+			if _, delivered := s.pending[key]; delivered {
+				if !pending {
+					delete(s.pending, key)
+				}
+				continue
+			}
+			if pending {
+				s.pending[key] = latest
+			}
+			select {
+			case s.events <- StreamedEvent{EmittedEvent: event, Pending: pending}:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		if chunk.ContinuationToken == "" {
+			break
+		}
+		token = chunk.ContinuationToken
+	}
+	if !pending {
+		for key, seen := range s.pending {
+			if latest > seen+pendingEventsRetention {
+				delete(s.pending, key)
+			}
+		}
+	}
+	return true
+}
+
+// fail delivers the error ending the stream, unless ctx is done.
+//
+// Parameters:
+// - ctx: The context.Context object, cancelling the delivery
+// - err: The error
+// Returns:
+//
+//	none
+func (s *eventStream) fail(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	select {
+	case s.events <- StreamedEvent{Err: err}:
+	case <-ctx.Done():
+	}
+}
+
+// EventHandler handles an event delivered to an EventListener.
+type EventHandler func(ctx context.Context, event StreamedEvent) error
+
+// CheckpointFunc persists the number of the block an EventListener resumes
+// from, the block of the last accepted event handled.
+type CheckpointFunc func(ctx context.Context, blockNumber uint64) error
+
+// listenerOptions are the options of an EventListener or a TxnStatusWatcher.
+type listenerOptions struct {
+	checkpoint CheckpointFunc
+	onError    lifecycle.ErrorHandler
+}
+
+// ListenerOption configures an EventListener or a TxnStatusWatcher.
+type ListenerOption func(*listenerOptions)
+
+// WithCheckpoint sets the function persisting the block an EventListener
+// resumes from, called once the handled events are drained by Stop. It is
+// ignored by a TxnStatusWatcher.
+//
+// Parameters:
+// - checkpoint: the function
+// Returns:
+// - ListenerOption: the option
+func WithCheckpoint(checkpoint CheckpointFunc) ListenerOption {
+	return func(o *listenerOptions) {
+		o.checkpoint = checkpoint
+	}
+}
+
+// WithErrorHandler sets the handler notified of the errors of the handler of
+// an EventListener or a TxnStatusWatcher, including recovered panics, the
+// listener going on with the next event or update.
+//
+// Parameters:
+// - onError: the error handler
+// Returns:
+// - ListenerOption: the option
+func WithErrorHandler(onError lifecycle.ErrorHandler) ListenerOption {
+	return func(o *listenerOptions) {
+		o.onError = onError
+	}
+}
+
+// EventListener runs a handler on the events of StreamEvents, as a
+// lifecycle.Lifecycle component: Stop stops taking events, waits for the
+// handler of the event in flight, then flushes the checkpoint. An event whose
+// handler fails or panics is reported to the error handler and skipped; the
+// listener ends with the error of the stream.
+type EventListener struct {
+	lifecycle.Base
+	provider  *Provider
+	contracts []*felt.Felt
+	keys      [][]*felt.Felt
+	fromBlock uint64
+	handler   EventHandler
+	options   listenerOptions
+
+	mu sync.Mutex
+	// checkpoint is the block of the last accepted event handled, valid if handled
+	checkpoint uint64
+	handled    bool
+	// err is the error ending the listener
+	err error
+}
+
+var _ lifecycle.Lifecycle = &EventListener{}
+
+// NewEventListener creates a listener running a handler on the events of a
+// set of contracts from a block on, as StreamEvents delivers them, once
+// started.
+//
+// Parameters:
+// - contracts: The contracts emitting the events, all contracts if empty
+// - keys: The accepted values of the keys, as in EventFilter
+// - fromBlock: The number of the first block
+// - handler: The handler of the events
+// - opts: The options
+// Returns:
+// - *EventListener: the listener
+func (provider *Provider) NewEventListener(contracts []*felt.Felt, keys [][]*felt.Felt, fromBlock uint64, handler EventHandler, opts ...ListenerOption) *EventListener {
+	l := &EventListener{
+		provider:  provider,
+		contracts: contracts,
+		keys:      keys,
+		fromBlock: fromBlock,
+		handler:   handler,
+	}
+	for _, opt := range opts {
+		opt(&l.options)
+	}
+	l.SetErrorHandler(l.options.onError)
+	return l
+}
+
+// Start starts the stream of the events and their handling.
+//
+// Parameters:
+// - ctx: The context.Context object, bounding the read of the latest block
+// Returns:
+// - error: lifecycle.ErrAlreadyStarted, lifecycle.ErrStopped, or the error of StreamEvents
+func (l *EventListener) Start(ctx context.Context) error {
+	work, err := l.Begin()
+	if err != nil {
+		return err
+	}
+	streamCtx, cancel := context.WithCancel(work)
+	latest, err := l.provider.BlockNumber(ctx)
+	if err != nil {
+		cancel()
+		return errors.Join(err, l.Shutdown(ctx, nil))
+	}
+	events := make(chan StreamedEvent)
+	go l.provider.newEventStream(l.contracts, l.keys, events).run(streamCtx, l.fromBlock, latest)
+	l.Go(func(ctx context.Context) {
+		defer cancel()
+		l.consume(ctx, events)
+	})
+	return nil
+}
+
+// Stop stops taking events, waits for the handler of the event in flight and
+// flushes the checkpoint.
+//
+// Parameters:
+// - ctx: The context.Context object, bounding the drain and the flush
+// Returns:
+// - error: the error ending the listener, of the drain or of the checkpoint
+func (l *EventListener) Stop(ctx context.Context) error {
+	err := l.Shutdown(ctx, l.flush)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return errors.Join(l.err, err)
+}
+
+// consume handles the events until the stream ends or the listener stops.
+//
+// Parameters:
+// - ctx: The context.Context object of the handlers
+// - events: The events of the stream
+// Returns:
+//
+//	none
+func (l *EventListener) consume(ctx context.Context, events <-chan StreamedEvent) {
+	stopping := l.Stopping()
+	for {
+		select {
+		case <-stopping:
+			return
+		default:
+		}
+		select {
+		case <-stopping:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Err != nil {
+				l.mu.Lock()
+				l.err = event.Err
+				l.mu.Unlock()
+				return
+			}
+			_ = lifecycle.SafeCall(l.options.onError, func() error {
+				return l.handler(ctx, event)
+			})
+			if !event.Pending {
+				l.mu.Lock()
+				l.checkpoint, l.handled = event.BlockNumber, true
+				l.mu.Unlock()
+			}
+		}
+	}
+}
+
+// flush persists the checkpoint, if an event was handled.
+//
+// Parameters:
+// - ctx: The context.Context object of the checkpoint
+// Returns:
+// - error: the error of the checkpoint
+func (l *EventListener) flush(ctx context.Context) error {
+	l.mu.Lock()
+	checkpoint, handled := l.checkpoint, l.handled
+	l.mu.Unlock()
+	if l.options.checkpoint == nil || !handled {
+		return nil
+	}
+	return l.options.checkpoint(ctx, checkpoint)
+}
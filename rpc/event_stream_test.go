@@ -0,0 +1,280 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/lifecycle"
+)
+
+// chainClient answers starknet_blockNumber with a sequence of block numbers,
+// failing after the last one, and starknet_getEvents with the events of its
+// blocks, in pages of two events.
+type chainClient struct {
+	latests []uint64
+	blocks  map[uint64][]EmittedEvent
+	// pending are the events of the pending block, by latest block
+	pending map[uint64][]EmittedEvent
+	latest  uint64
+	calls   int
+}
+
+// CallContext answers a block number or an events read.
+//
+// Parameters:
+// - ctx: the context
+// - result: the value the response is decoded into
+// - method: the method
+// - args: the arguments
+// Returns:
+// - error: an error after the last block number
+func (c *chainClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var response any
+	switch method {
+	case "starknet_blockNumber":
+		if c.calls == len(c.latests) {
+			return errors.New("node down")
+		}
+		c.latest = c.latests[c.calls]
+		c.calls++
+		response = c.latest
+	case "starknet_getEvents":
+		input := args[0].(EventsInput)
+		var events []EmittedEvent
+		if input.FromBlock.Tag == BlockTagPending {
+			events = c.pending[c.latest]
+		} else {
+			for n := *input.FromBlock.Number; n <= *input.ToBlock.Number; n++ {
+				events = append(events, c.blocks[n]...)
+			}
+		}
+		var matching []EmittedEvent
+		for _, event := range events {
+			if input.Address == nil || input.Address.Equal(event.FromAddress) {
+				matching = append(matching, event)
+			}
+		}
+		start, _ := strconv.Atoi(input.ContinuationToken)
+		end := min(start+2, len(matching), start+input.ChunkSize)
+		chunk := EventChunk{Events: matching[start:end]}
+		if end < len(matching) {
+			chunk.ContinuationToken = strconv.Itoa(end)
+		}
+		response = chunk
+	}
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// Close does nothing.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (c *chainClient) Close() {}
+
+// TestStreamEvents tests that the events of a set of contracts are delivered
+// in order from the blocks and the pending block, once, until an error.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestStreamEvents(t *testing.T) {
+	a, b, other := new(felt.Felt).SetUint64(0xa), new(felt.Felt).SetUint64(0xb), new(felt.Felt).SetUint64(0xc)
+	event := func(from *felt.Felt, tx uint64, data uint64) EmittedEvent {
+		return EmittedEvent{
+			Event:           Event{FromAddress: from, Keys: []*felt.Felt{}, Data: []*felt.Felt{new(felt.Felt).SetUint64(data)}},
+			TransactionHash: new(felt.Felt).SetUint64(tx),
+		}
+	}
+	client := &chainClient{
+		latests: []uint64{2, 2, 3},
+		blocks: map[uint64][]EmittedEvent{
+			0: {event(a, 0x1, 0)},
+			1: {event(a, 0x10, 1), event(other, 0x10, 99), event(b, 0x11, 2), event(a, 0x11, 3)},
+			2: {event(b, 0x20, 4)},
+			3: {event(a, 0x30, 5), event(a, 0x30, 6), event(b, 0x31, 7)},
+		},
+		pending: map[uint64][]EmittedEvent{
+			2: {event(a, 0x30, 5), event(other, 0x30, 99)},
+		},
+	}
+	provider := NewProvider(client, WithPollInterval(time.Millisecond))
+	events, err := provider.StreamEvents(context.Background(), []*felt.Felt{a, b}, nil, 1)
+	if err != nil {
+		t.Fatalf("starting the stream should succeed, instead: %v", err)
+	}
+
+	var data []uint64
+	var last StreamedEvent
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				done = true
+				break
+			}
+			last = e
+			if e.Err == nil {
+				data = append(data, e.Data[0].Uint64())
+			}
+			if e.Pending != (e.Data != nil && e.Data[0].Uint64() == 5) {
+				t.Fatalf("unexpected pending status of event %v", e)
+			}
+		case <-timeout:
+			t.Fatal("the stream was not closed")
+		}
+	}
+	expected := []uint64{1, 2, 3, 4, 5, 6, 7}
+	if len(data) != len(expected) {
+		t.Fatalf("expected the events %v, got %v", expected, data)
+	}
+	for i := range expected {
+		if data[i] != expected[i] {
+			t.Fatalf("expected the events %v, got %v", expected, data)
+		}
+	}
+	if last.Err == nil || last.Err.Error() != "node down" {
+		t.Fatalf("expected the stream to end with the error of the node, got %v", last.Err)
+	}
+}
+
+// TestEventListener tests that an EventListener runs its handler on the
+// events of the stream, flushes the block of the last accepted event handled
+// when stopped, reports the errors and panics of its handler and goes on, and
+// ends with the error of the stream.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestEventListener(t *testing.T) {
+	a := new(felt.Felt).SetUint64(0xa)
+	event := func(block, tx, data uint64) EmittedEvent {
+		return EmittedEvent{
+			Event:           Event{FromAddress: a, Keys: []*felt.Felt{}, Data: []*felt.Felt{new(felt.Felt).SetUint64(data)}},
+			BlockNumber:     block,
+			TransactionHash: new(felt.Felt).SetUint64(tx),
+		}
+	}
+	latests := make([]uint64, 10000)
+	for i := range latests {
+		latests[i] = 2
+	}
+	newClient := func() *chainClient {
+		return &chainClient{
+			latests: latests,
+			blocks: map[uint64][]EmittedEvent{
+				1: {event(1, 0x10, 1)},
+				2: {event(2, 0x20, 2)},
+			},
+			pending: map[uint64][]EmittedEvent{
+				2: {event(0, 0x30, 3)},
+			},
+		}
+	}
+
+	handled := make(chan uint64, 3)
+	var checkpoints []uint64
+	provider := NewProvider(newClient(), WithPollInterval(time.Millisecond))
+	listener := provider.NewEventListener([]*felt.Felt{a}, nil, 1, func(ctx context.Context, e StreamedEvent) error {
+		handled <- e.Data[0].Uint64()
+		return nil
+	}, WithCheckpoint(func(ctx context.Context, blockNumber uint64) error {
+		checkpoints = append(checkpoints, blockNumber)
+		return nil
+	}))
+	if err := listener.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for expected := uint64(1); expected <= 3; expected++ {
+		select {
+		case data := <-handled:
+			if data != expected {
+				t.Fatalf("expected the event %d, got %d", expected, data)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("the event %d was not handled", expected)
+		}
+	}
+	if err := listener.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(checkpoints) != 1 || checkpoints[0] != 2 {
+		t.Fatalf("expected the checkpoint 2, got %v", checkpoints)
+	}
+	if err := listener.Start(context.Background()); !errors.Is(err, lifecycle.ErrStopped) {
+		t.Fatalf("expected ErrStopped, got %v", err)
+	}
+
+	failure := errors.New("database down")
+	errs := make(chan error, 2)
+	handled = make(chan uint64, 3)
+	listener = NewProvider(newClient(), WithPollInterval(time.Millisecond)).NewEventListener(nil, nil, 1, func(ctx context.Context, e StreamedEvent) error {
+		switch data := e.Data[0].Uint64(); data {
+		case 1:
+			panic("bad handler")
+		case 2:
+			return failure
+		default:
+			handled <- data
+		}
+		return nil
+	}, WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+	if err := listener.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case data := <-handled:
+		if data != 3 {
+			t.Fatalf("expected the event 3, got %d", data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the listener did not go on after its handler failed")
+	}
+	var panicErr *lifecycle.PanicError
+	if err := <-errs; !errors.As(err, &panicErr) || panicErr.Value != "bad handler" {
+		t.Fatalf("expected the panic of the handler, got %v", err)
+	}
+	if err := <-errs; !errors.Is(err, failure) {
+		t.Fatalf("expected the error of the handler, got %v", err)
+	}
+	if err := listener.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	client := newClient()
+	client.latests = []uint64{2}
+	listener = NewProvider(client, WithPollInterval(time.Millisecond)).NewEventListener(nil, nil, 1, func(ctx context.Context, e StreamedEvent) error {
+		return nil
+	})
+	if err := listener.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for ended := false; !ended; time.Sleep(time.Millisecond) {
+		listener.mu.Lock()
+		ended = listener.err != nil
+		listener.mu.Unlock()
+	}
+	if err := listener.Stop(context.Background()); err == nil || err.Error() != "node down" {
+		t.Fatalf("expected the error of the stream, got %v", err)
+	}
+}
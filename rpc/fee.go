@@ -0,0 +1,171 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var (
+	// ErrFeeOverflow is returned by the FeeEstimate arithmetic for results out of their range.
+	ErrFeeOverflow = errors.New("fee overflow")
+	// ErrInvalidMultiplier is returned by the FeeEstimate arithmetic for negative, infinite or NaN multipliers.
+	ErrInvalidMultiplier = errors.New("invalid fee multiplier")
+
+	maxFeeBound    = new(big.Int).Lsh(big.NewInt(1), 128)
+	maxAmountBound = new(big.Int).Lsh(big.NewInt(1), 64)
+)
+
+// feeDecimals is the number of decimals of ETH and STRK, whose base units are
+// WEI and FRI.
+const feeDecimals = 18
+
+// Token returns the token the fees of a unit are paid in.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: "ETH" for WEI, "STRK" for FRI, the unit otherwise
+func (unit FeePaymentUnit) Token() string {
+	switch unit {
+	case UnitWei:
+		return "ETH"
+	case UnitStrk:
+		return "STRK"
+	}
+	return string(unit)
+}
+
+// Overall returns the overall fee of the estimate, in the base unit of its
+// FeeUnit, or the product of the gas consumed and the gas price when the node
+// did not return it.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *big.Int: the overall fee
+func (fee FeeEstimate) Overall() *big.Int {
+	if fee.OverallFee != nil {
+		return fee.OverallFee.BigInt(new(big.Int))
+	}
+	return fee.GasFee()
+}
+
+// GasFee returns the product of the gas consumed and the gas price of the
+// estimate, missing values being zero. It is lower than the overall fee of
+// estimates paying data gas too.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *big.Int: the fee of the gas
+func (fee FeeEstimate) GasFee() *big.Int {
+	if fee.GasConsumed == nil || fee.GasPrice == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Mul(fee.GasConsumed.BigInt(new(big.Int)), fee.GasPrice.BigInt(new(big.Int)))
+}
+
+// MaxFee returns the overall fee multiplied by a factor, rounded up, as the
+// max fee of a V1 or V2 transaction, e.g. MaxFee(1.5) for a margin of 50%.
+//
+// Parameters:
+// - multiplier: the factor
+// Returns:
+// - *felt.Felt: the max fee
+// - error: ErrInvalidMultiplier, or ErrFeeOverflow if the max fee does not fit in the u128 of the fees
+func (fee FeeEstimate) MaxFee(multiplier float64) (*felt.Felt, error) {
+	maxFee, err := multiplyFee(fee.Overall(), multiplier)
+	if err != nil {
+		return nil, err
+	}
+	if maxFee.Cmp(maxFeeBound) >= 0 {
+		return nil, fmt.Errorf("%w: max fee %s above the u128 range", ErrFeeOverflow, maxFee)
+	}
+	return new(felt.Felt).SetBigInt(maxFee), nil
+}
+
+// ResourceBounds returns the L1 gas bounds of a V3 transaction from the
+// estimate: the gas consumed and the gas price multiplied by factors,
+// rounded up, e.g. ResourceBounds(1.5, 2) to absorb the variations of the
+// execution and of the price before inclusion.
+//
+// Parameters:
+// - amountMultiplier: the factor of the gas consumed
+// - priceMultiplier: the factor of the gas price
+// Returns:
+// - ResourceBounds: the max amount and max price per unit of L1 gas
+// - error: ErrInvalidMultiplier, or ErrFeeOverflow if the amount does not fit in a u64 or the price in a u128
+func (fee FeeEstimate) ResourceBounds(amountMultiplier, priceMultiplier float64) (ResourceBounds, error) {
+	consumed, price := new(big.Int), new(big.Int)
+	if fee.GasConsumed != nil {
+		fee.GasConsumed.BigInt(consumed)
+	}
+	if fee.GasPrice != nil {
+		fee.GasPrice.BigInt(price)
+	}
+	maxAmount, err := multiplyFee(consumed, amountMultiplier)
+	if err != nil {
+		return ResourceBounds{}, err
+	}
+	if maxAmount.Cmp(maxAmountBound) >= 0 {
+		return ResourceBounds{}, fmt.Errorf("%w: max amount %s above the u64 range", ErrFeeOverflow, maxAmount)
+	}
+	maxPrice, err := multiplyFee(price, priceMultiplier)
+	if err != nil {
+		return ResourceBounds{}, err
+	}
+	if maxPrice.Cmp(maxFeeBound) >= 0 {
+		return ResourceBounds{}, fmt.Errorf("%w: max price per unit %s above the u128 range", ErrFeeOverflow, maxPrice)
+	}
+	return ResourceBounds{
+		MaxAmount:       U64(fmt.Sprintf("0x%x", maxAmount)),
+		MaxPricePerUnit: U128(fmt.Sprintf("0x%x", maxPrice)),
+	}, nil
+}
+
+// Format returns the overall fee in the token of the estimate, e.g.
+// "0.0015 STRK", for display.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the formatted fee
+func (fee FeeEstimate) Format() string {
+	if fee.FeeUnit != UnitWei && fee.FeeUnit != UnitStrk {
+		return fmt.Sprintf("%s %s", fee.Overall(), fee.FeeUnit)
+	}
+	return fmt.Sprintf("%s %s", utils.FormatUnits(fee.Overall(), feeDecimals), fee.FeeUnit.Token())
+}
+
+// multiplyFee multiplies an amount by a factor, rounding up.
+//
+// Parameters:
+// - amount: the amount
+// - multiplier: the factor
+// Returns:
+// - *big.Int: the product
+// - error: ErrInvalidMultiplier if the factor is negative, infinite or NaN
+func multiplyFee(amount *big.Int, multiplier float64) (*big.Int, error) {
+	if multiplier < 0 || math.IsInf(multiplier, 0) || math.IsNaN(multiplier) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMultiplier, multiplier)
+	}
+	product := new(big.Float).Mul(new(big.Float).SetInt(amount), big.NewFloat(multiplier))
+	result, accuracy := product.Int(nil)
+	if accuracy == big.Below {
+		result.Add(result, big.NewInt(1))
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// TestFeeEstimate tests the overall fee, the max fee and the resource bounds
+// of estimates, their overflow checks, and their formatting.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestFeeEstimate(t *testing.T) {
+	fee := FeeEstimate{
+		GasConsumed: new(felt.Felt).SetUint64(1000),
+		GasPrice:    new(felt.Felt).SetUint64(1500000000000),
+		OverallFee:  new(felt.Felt).SetUint64(1600000000000000),
+		FeeUnit:     UnitStrk,
+	}
+	if fee.Overall().Uint64() != 1600000000000000 || fee.GasFee().Uint64() != 1500000000000000 {
+		t.Fatalf("unexpected overall fee %s or gas fee %s", fee.Overall(), fee.GasFee())
+	}
+	if withoutOverall := (FeeEstimate{GasConsumed: fee.GasConsumed, GasPrice: fee.GasPrice}); withoutOverall.Overall().Uint64() != 1500000000000000 {
+		t.Fatalf("the overall fee should default to the gas fee, got %s", withoutOverall.Overall())
+	}
+	if got := fee.Format(); got != "0.0016 STRK" {
+		t.Fatalf("unexpected formatted fee %q", got)
+	}
+	if got := (FeeEstimate{OverallFee: new(felt.Felt).SetUint64(5), FeeUnit: UnitWei}).Format(); got != "0.000000000000000005 ETH" {
+		t.Fatalf("unexpected formatted fee %q", got)
+	}
+
+	maxFee, err := fee.MaxFee(1.5)
+	if err != nil || maxFee.Uint64() != 2400000000000000 {
+		t.Fatalf("unexpected max fee %v, %v", maxFee, err)
+	}
+	if maxFee, _ := (FeeEstimate{OverallFee: new(felt.Felt).SetUint64(3)}).MaxFee(1.5); maxFee.Uint64() != 5 {
+		t.Fatalf("the max fee should be rounded up, got %v", maxFee)
+	}
+	huge := FeeEstimate{OverallFee: new(felt.Felt).SetBigInt(new(big.Int).Lsh(big.NewInt(1), 127))}
+	if _, err := huge.MaxFee(2); !errors.Is(err, ErrFeeOverflow) {
+		t.Fatalf("expected ErrFeeOverflow, got %v", err)
+	}
+	for _, multiplier := range []float64{-1, math.NaN(), math.Inf(1)} {
+		if _, err := fee.MaxFee(multiplier); !errors.Is(err, ErrInvalidMultiplier) {
+			t.Fatalf("expected ErrInvalidMultiplier for %v, got %v", multiplier, err)
+		}
+	}
+
+	bounds, err := fee.ResourceBounds(1.5, 2)
+	if err != nil || bounds.MaxAmount != "0x5dc" || bounds.MaxPricePerUnit != "0x2ba7def3000" {
+		t.Fatalf("unexpected resource bounds %+v, %v", bounds, err)
+	}
+	if _, err := bounds.Bytes(ResourceL1Gas); err != nil {
+		t.Fatalf("the resource bounds should be hashable, instead: %v", err)
+	}
+	if _, err := (FeeEstimate{GasConsumed: new(felt.Felt).SetUint64(math.MaxUint64)}).ResourceBounds(2, 1); !errors.Is(err, ErrFeeOverflow) {
+		t.Fatalf("expected ErrFeeOverflow for the amount, got %v", err)
+	}
+	if _, err := huge.ResourceBounds(1, 1); err != nil {
+		t.Fatalf("an estimate without gas should have zero bounds, got %v", err)
+	}
+}
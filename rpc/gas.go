@@ -0,0 +1,165 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// ErrInvalidHistoryLength is returned by FeeHistory for a history of less than one block.
+var ErrInvalidHistoryLength = errors.New("fee history needs at least one block")
+
+// GasPrices are the prices of the resources paid by the transactions of a block.
+type GasPrices struct {
+	// L1GasPrice is the price of l1 gas
+	L1GasPrice ResourcePrice `json:"l1_gas_price"`
+	// L1DataGasPrice is the price of l1 data gas, for the state diffs posted as blobs
+	L1DataGasPrice ResourcePrice `json:"l1_data_gas_price"`
+	// L2GasPrice is the price of l2 gas
+	L2GasPrice ResourcePrice `json:"l2_gas_price"`
+}
+
+// BlockGasPrices are the gas prices of a block.
+type BlockGasPrices struct {
+	GasPrices
+	// BlockNumber is the number of the block, zero for the pending block
+	BlockNumber uint64 `json:"block_number"`
+	// Pending is true for the pending block
+	Pending bool `json:"pending"`
+}
+
+// FeeHistory are the gas prices of consecutive blocks, oldest first.
+type FeeHistory []BlockGasPrices
+
+// GasPrices returns the gas prices of a block, from its header.
+//
+// Parameters:
+// - ctx: The context.Context object for controlling the function call
+// - blockID: The ID of the block, e.g. WithBlockTag("pending") for the prices of the next transactions
+// Returns:
+// - *BlockGasPrices: The gas prices of the block
+// - error: An error, if any
+func (provider *Provider) GasPrices(ctx context.Context, blockID BlockID) (*BlockGasPrices, error) {
+	var header BlockHeader
+	if err := do(ctx, provider.c, "starknet_getBlockWithTxHashes", &header, blockID); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
+	}
+	return headerGasPrices(header), nil
+}
+
+// FeeHistory returns the gas prices of the last n accepted blocks, in a single
+// JSON-RPC batch when the client implements BatchCallCloser. The history is
+// shorter than n blocks when the chain is.
+//
+// Parameters:
+// - ctx: The context.Context object for controlling the function call
+// - n: The number of blocks
+// Returns:
+// - FeeHistory: The gas prices of the blocks, the latest block last
+// - error: ErrInvalidHistoryLength if n is not positive, or an error of the calls
+func (provider *Provider) FeeHistory(ctx context.Context, n int) (FeeHistory, error) {
+	if n < 1 {
+		return nil, ErrInvalidHistoryLength
+	}
+	latest, err := provider.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(n) > latest+1 {
+		n = int(latest + 1)
+	}
+	first := latest + 1 - uint64(n)
+	headers := make([]BlockHeader, n)
+
+	if batcher, ok := provider.c.(BatchCallCloser); ok {
+		batch := make([]BatchElem, n)
+		for i := range batch {
+			batch[i] = BatchElem{Method: "starknet_getBlockWithTxHashes", Args: []interface{}{WithBlockNumber(first + uint64(i))}, Result: &headers[i]}
+		}
+		if err := batcher.BatchCallContext(ctx, batch); err != nil {
+			return nil, err
+		}
+		for _, elem := range batch {
+			if elem.Error != nil {
+				return nil, tryUnwrapToRPCErr(elem.Error, ErrBlockNotFound)
+			}
+		}
+	} else {
+		for i := range headers {
+			if err := do(ctx, provider.c, "starknet_getBlockWithTxHashes", &headers[i], WithBlockNumber(first+uint64(i))); err != nil {
+				return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound)
+			}
+		}
+	}
+
+	history := make(FeeHistory, n)
+	for i, header := range headers {
+		history[i] = *headerGasPrices(header)
+	}
+	return history, nil
+}
+
+// Percentile returns, for each price of the history, the price that p percent
+// of the blocks did not exceed, e.g. Percentile(50) for the median prices and
+// Percentile(100) for the highest ones, to choose the max price per unit of
+// the resource bounds of V3 transactions. Prices missing from a block are
+// ignored.
+//
+// Parameters:
+// - p: The percentile, between 0 and 100
+// Returns:
+// - GasPrices: The prices, nil where no block has the price
+func (h FeeHistory) Percentile(p float64) GasPrices {
+	pick := func(price func(BlockGasPrices) *felt.Felt) *felt.Felt {
+		prices := make([]*felt.Felt, 0, len(h))
+		for _, block := range h {
+			if v := price(block); v != nil {
+				prices = append(prices, v)
+			}
+		}
+		if len(prices) == 0 {
+			return nil
+		}
+		sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+		rank := int(math.Ceil(math.Max(0, math.Min(p, 100)) / 100 * float64(len(prices))))
+		if rank > 0 {
+			rank--
+		}
+		return new(felt.Felt).Set(prices[rank])
+	}
+	return GasPrices{
+		L1GasPrice: ResourcePrice{
+			PriceInFRI: pick(func(b BlockGasPrices) *felt.Felt { return b.L1GasPrice.PriceInFRI }),
+			PriceInWei: pick(func(b BlockGasPrices) *felt.Felt { return b.L1GasPrice.PriceInWei }),
+		},
+		L1DataGasPrice: ResourcePrice{
+			PriceInFRI: pick(func(b BlockGasPrices) *felt.Felt { return b.L1DataGasPrice.PriceInFRI }),
+			PriceInWei: pick(func(b BlockGasPrices) *felt.Felt { return b.L1DataGasPrice.PriceInWei }),
+		},
+		L2GasPrice: ResourcePrice{
+			PriceInFRI: pick(func(b BlockGasPrices) *felt.Felt { return b.L2GasPrice.PriceInFRI }),
+			PriceInWei: pick(func(b BlockGasPrices) *felt.Felt { return b.L2GasPrice.PriceInWei }),
+		},
+	}
+}
+
+// headerGasPrices returns the gas prices of a block header.
+//
+// Parameters:
+// - header: The header, pending if it has no block hash
+// Returns:
+// - *BlockGasPrices: The gas prices of the block
+func headerGasPrices(header BlockHeader) *BlockGasPrices {
+	return &BlockGasPrices{
+		GasPrices: GasPrices{
+			L1GasPrice:     header.L1GasPrice,
+			L1DataGasPrice: header.L1DataGasPrice,
+			L2GasPrice:     header.L2GasPrice,
+		},
+		BlockNumber: header.BlockNumber,
+		Pending:     header.BlockHash == nil,
+	}
+}
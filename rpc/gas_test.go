@@ -0,0 +1,143 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// gasClient answers starknet_blockNumber with latest, and the headers of
+// blocks with l1 gas prices of 100 plus their number.
+type gasClient struct {
+	latest  uint64
+	batches int
+}
+
+// CallContext answers a block number or a block header request.
+//
+// Parameters:
+// - ctx: the context
+// - result: the value the response is decoded into
+// - method: the method
+// - args: the block ID for headers
+// Returns:
+// - error: an error for unknown methods or blocks
+func (c *gasClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var raw string
+	switch method {
+	case "starknet_blockNumber":
+		raw = fmt.Sprint(c.latest)
+	case "starknet_getBlockWithTxHashes":
+		id := args[0].(BlockID)
+		if id.Tag == "pending" {
+			raw = `{"parent_hash": "0x1", "l1_gas_price": {"price_in_wei": "0x1", "price_in_fri": "0x2"}, "l1_data_gas_price": {"price_in_wei": "0x3", "price_in_fri": "0x4"}, "l2_gas_price": {"price_in_wei": "0x5", "price_in_fri": "0x6"}, "transactions": []}`
+			break
+		}
+		if id.Number == nil || *id.Number > c.latest {
+			return &RPCError{code: 24, message: "Block not found"}
+		}
+		raw = fmt.Sprintf(`{"block_hash": "0x1", "block_number": %d, "l1_gas_price": {"price_in_wei": "0x%x", "price_in_fri": "0x%x"}, "l1_data_gas_price": {"price_in_wei": "0x1"}, "transactions": []}`, *id.Number, 100+*id.Number, 200+*id.Number)
+	default:
+		return fmt.Errorf("unexpected method %s", method)
+	}
+	return json.Unmarshal([]byte(raw), result)
+}
+
+// Close does nothing.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (c *gasClient) Close() {}
+
+// batchGasClient answers header requests in batches.
+type batchGasClient struct {
+	gasClient
+}
+
+// BatchCallContext answers each request of the batch.
+//
+// Parameters:
+// - ctx: the context
+// - b: the batch
+// Returns:
+// - error: always nil
+func (c *batchGasClient) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	c.batches++
+	for i := range b {
+		b[i].Error = c.gasClient.CallContext(ctx, b[i].Result, b[i].Method, b[i].Args...)
+	}
+	return nil
+}
+
+// TestGasPrices tests the gas prices of accepted and pending blocks, the fee
+// history with and without batches, and its percentiles.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestGasPrices(t *testing.T) {
+	ctx := context.Background()
+	provider := NewProvider(&gasClient{latest: 10})
+
+	prices, err := provider.GasPrices(ctx, WithBlockTag("pending"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !prices.Pending || prices.L1GasPrice.PriceInFRI.String() != "0x2" || prices.L1DataGasPrice.PriceInWei.String() != "0x3" || prices.L2GasPrice.PriceInFRI.String() != "0x6" {
+		t.Errorf("unexpected pending prices %+v", prices)
+	}
+	prices, err = provider.GasPrices(ctx, WithBlockNumber(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prices.Pending || prices.BlockNumber != 7 || prices.L1GasPrice.PriceInWei.Uint64() != 107 || prices.L2GasPrice.PriceInWei != nil {
+		t.Errorf("unexpected prices of block 7 %+v", prices)
+	}
+	if _, err := provider.GasPrices(ctx, WithBlockNumber(11)); !errors.Is(err, ErrBlockNotFound) {
+		t.Errorf("expected ErrBlockNotFound, got %v", err)
+	}
+
+	batcher := &batchGasClient{gasClient{latest: 10}}
+	for _, p := range []*Provider{provider, NewProvider(batcher)} {
+		history, err := p.FeeHistory(ctx, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(history) != 5 || history[0].BlockNumber != 6 || history[4].BlockNumber != 10 {
+			t.Fatalf("unexpected history %+v", history)
+		}
+		for p, expected := range map[float64]uint64{0: 106, 50: 108, 80: 109, 100: 110} {
+			if got := history.Percentile(p); got.L1GasPrice.PriceInWei.Uint64() != expected || got.L1GasPrice.PriceInFRI.Uint64() != 100+expected {
+				t.Errorf("percentile %v: expected %d, got %+v", p, expected, got.L1GasPrice)
+			}
+		}
+		if got := history.Percentile(50); got.L2GasPrice.PriceInWei != nil || !got.L1DataGasPrice.PriceInWei.Equal(new(felt.Felt).SetUint64(1)) {
+			t.Errorf("unexpected median prices %+v", got)
+		}
+	}
+	if batcher.batches != 1 {
+		t.Errorf("expected a single batch, got %d", batcher.batches)
+	}
+
+	history, err := NewProvider(&gasClient{latest: 2}).FeeHistory(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 || history[0].BlockNumber != 0 {
+		t.Errorf("expected the 3 blocks of the chain, got %+v", history)
+	}
+	if _, err := provider.FeeHistory(ctx, 0); !errors.Is(err, ErrInvalidHistoryLength) {
+		t.Errorf("expected ErrInvalidHistoryLength, got %v", err)
+	}
+}
@@ -0,0 +1,485 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// HTTPClient is a CallCloser sending JSON-RPC requests over HTTP, e.g. to a
+// node or a devnet. It implements BatchCallCloser.
+type HTTPClient struct {
+	url     string
+	client  *http.Client
+	header  http.Header
+	timeout time.Duration
+	invoke  Invoker
+	// interceptors are the interceptors of invoke
+	interceptors []Interceptor
+	id           uint64
+}
+
+var _ BatchCallCloser = &HTTPClient{}
+
+// jsonrpcRequest is a JSON-RPC 2.0 request.
+type jsonrpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// jsonrpcResponse is a JSON-RPC 2.0 response.
+type jsonrpcResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	} `json:"error"`
+}
+
+// err returns the error of the response as an RPCError.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: the error, or nil if the request succeeded
+func (r *jsonrpcResponse) err() error {
+	if r.Error == nil {
+		return nil
+	}
+	var data any
+	if len(r.Error.Data) > 0 {
+		data = r.Error.Data
+	}
+	return &RPCError{code: r.Error.Code, message: r.Error.Message, data: data}
+}
+
+// decode decodes the result of the response.
+//
+// Parameters:
+// - result: the value the result is decoded into, nil to discard it
+// Returns:
+// - error: the error of the response, or an error of the decoding
+func (r *jsonrpcResponse) decode(result interface{}) error {
+	if err := r.err(); err != nil {
+		return err
+	}
+	if len(r.Result) == 0 || result == nil {
+		return nil
+	}
+	return json.Unmarshal(r.Result, result)
+}
+
+// HTTPOption configures an HTTPClient.
+type HTTPOption func(*httpConfig)
+
+type httpConfig struct {
+	client       *http.Client
+	header       http.Header
+	timeout      time.Duration
+	transport    *TransportConfig
+	proxy        *url.URL
+	dial         DialFunc
+	noProxy      bool
+	interceptors []Interceptor
+}
+
+// WithHTTPClient sends the requests with the given HTTP client instead of a
+// client with a transport configured by DefaultTransportConfig.
+//
+// Parameters:
+// - client: the HTTP client
+// Returns:
+// - HTTPOption: the option
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(c *httpConfig) {
+		c.client = client
+	}
+}
+
+// WithHeader adds a header to every request, e.g. for gateways requiring
+// custom authentication.
+//
+// Parameters:
+// - key: the name of the header
+// - value: the value of the header
+// Returns:
+// - HTTPOption: the option
+func WithHeader(key, value string) HTTPOption {
+	return func(c *httpConfig) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithAPIKey authenticates the requests with the x-api-key header.
+//
+// Parameters:
+// - key: the API key
+// Returns:
+// - HTTPOption: the option
+func WithAPIKey(key string) HTTPOption {
+	return func(c *httpConfig) {
+		c.header.Set("x-api-key", key)
+	}
+}
+
+// WithBasicAuth authenticates the requests with HTTP basic authentication.
+//
+// Parameters:
+// - username: the username
+// - password: the password
+// Returns:
+// - HTTPOption: the option
+func WithBasicAuth(username, password string) HTTPOption {
+	return func(c *httpConfig) {
+		req := http.Request{Header: http.Header{}}
+		req.SetBasicAuth(username, password)
+		c.header.Set("Authorization", req.Header.Get("Authorization"))
+	}
+}
+
+// WithTimeout bounds the duration of each request, in addition to the
+// deadline of its context.
+//
+// Parameters:
+// - timeout: the timeout
+// Returns:
+// - HTTPOption: the option
+func WithTimeout(timeout time.Duration) HTTPOption {
+	return func(c *httpConfig) {
+		c.timeout = timeout
+	}
+}
+
+// WithTransport sends the requests through a transport with its own
+// connection pool configured by cfg, e.g. to bound the connections to a node
+// with MaxConnsPerHost. It replaces the transport of the client of
+// WithHTTPClient.
+//
+// Parameters:
+// - cfg: the configuration of the transport
+// Returns:
+// - HTTPOption: the option
+func WithTransport(cfg TransportConfig) HTTPOption {
+	return func(c *httpConfig) {
+		c.transport = &cfg
+	}
+}
+
+// WithProxy sends the requests through an HTTP proxy. Without it, the
+// proxy of the environment (HTTPS_PROXY, NO_PROXY) is used by the default
+// transport.
+//
+// Parameters:
+// - proxy: the URL of the proxy
+// Returns:
+// - HTTPOption: the option
+func WithProxy(proxy *url.URL) HTTPOption {
+	return func(c *httpConfig) {
+		c.proxy = proxy
+	}
+}
+
+// WithDialer dials the connections of the requests with dial instead of
+// the dialer of the transport, e.g. through a SOCKS proxy such as TOR or to
+// an in-memory server of a test harness.
+//
+// Parameters:
+// - dial: the dial function
+// Returns:
+// - HTTPOption: the option
+func WithDialer(dial DialFunc) HTTPOption {
+	return func(c *httpConfig) {
+		c.dial = dial
+	}
+}
+
+// WithUnixSocket sends the requests to the HTTP server listening on the unix
+// socket at path, whatever the host of the URL. The proxy of the environment
+// is not used.
+//
+// Parameters:
+// - path: the path of the socket
+// Returns:
+// - HTTPOption: the option
+func WithUnixSocket(path string) HTTPOption {
+	return func(c *httpConfig) {
+		var dialer net.Dialer
+		c.dial = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", path)
+		}
+		c.noProxy = true
+	}
+}
+
+// WithInterceptor adds an interceptor around the requests, the first added
+// being the outermost. Each request of a batch goes through the
+// interceptors, the requests reaching the end of the chain being sent in a
+// single batch.
+//
+// Parameters:
+// - interceptor: the interceptor
+// Returns:
+// - HTTPOption: the option
+func WithInterceptor(interceptor Interceptor) HTTPOption {
+	return func(c *httpConfig) {
+		c.interceptors = append(c.interceptors, interceptor)
+	}
+}
+
+// NewHTTPClient creates a JSON-RPC client for the given URL. The client is
+// safe for concurrent use; without WithHTTPClient and WithTransport, the
+// clients of a process share a connection pool configured by
+// DefaultTransportConfig.
+//
+// Parameters:
+// - url: the URL of the JSON-RPC endpoint
+// - opts: the options
+// Returns:
+// - *HTTPClient: the client
+func NewHTTPClient(url string, opts ...HTTPOption) *HTTPClient {
+	cfg := httpConfig{client: defaultHTTPClient, header: http.Header{}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	client := cfg.client
+	if cfg.transport != nil {
+		withTransport := *client
+		withTransport.Transport = NewTransport(*cfg.transport)
+		client = &withTransport
+	}
+	if cfg.proxy != nil || cfg.dial != nil {
+		transport, ok := client.Transport.(*http.Transport)
+		if !ok || transport == nil {
+			transport = http.DefaultTransport.(*http.Transport)
+		}
+		transport = transport.Clone()
+		if cfg.proxy != nil {
+			transport.Proxy = http.ProxyURL(cfg.proxy)
+		}
+		if cfg.dial != nil {
+			transport.DialContext = cfg.dial
+		}
+		if cfg.noProxy {
+			transport.Proxy = nil
+		}
+		withTransport := *client
+		withTransport.Transport = transport
+		client = &withTransport
+	}
+	c := &HTTPClient{url: url, client: client, header: cfg.header, timeout: cfg.timeout}
+	if len(cfg.interceptors) > 0 {
+		c.invoke = chain(c.send, cfg.interceptors)
+		c.interceptors = cfg.interceptors
+	}
+	return c
+}
+
+// NewHTTPProvider creates a provider sending JSON-RPC requests over HTTP.
+//
+// Parameters:
+// - rawURL: the URL of the JSON-RPC endpoint
+// - opts: the options of the HTTP client
+// Returns:
+// - *Provider: the provider
+// - error: an error if the URL is not a valid HTTP(S) URL
+func NewHTTPProvider(rawURL string, opts ...HTTPOption) (*Provider, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %q in %s", u.Scheme, rawURL)
+	}
+	return NewProvider(NewHTTPClient(rawURL, opts...)), nil
+}
+
+// CallContext sends a JSON-RPC request and decodes its result.
+//
+// Parameters:
+// - ctx: the context
+// - result: the value the result is decoded into
+// - method: the method
+// - args: the parameters
+// Returns:
+// - error: an *RPCError if the node returns an error, or an error of the transport
+func (c *HTTPClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	invoke := c.invoke
+	if invoke == nil {
+		invoke = c.send
+	}
+	return decodeRaw(ctx, invoke, result, method, args)
+}
+
+// send sends a request and returns its raw result.
+//
+// Parameters:
+// - ctx: the context
+// - method: the method
+// - params: the parameters
+// Returns:
+// - json.RawMessage: the result
+// - error: an *RPCError if the node returns an error, or an error of the transport
+func (c *HTTPClient) send(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	var resp jsonrpcResponse
+	if err := c.post(ctx, c.request(method, params), &resp); err != nil {
+		return nil, err
+	}
+	if err := resp.err(); err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+// BatchCallContext sends the requests in a single JSON-RPC batch. The error
+// of each request is set in its BatchElem. With interceptors, each request
+// goes through the interceptors, the requests reaching the end of the chain
+// being sent in a single batch.
+//
+// Parameters:
+// - ctx: the context
+// - b: the batch
+// Returns:
+// - error: an error of the transport
+func (c *HTTPClient) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if c.invoke != nil {
+		return batchThrough(ctx, b, c.interceptors, c.send, c.sendBatch)
+	}
+	return c.sendBatch(ctx, b)
+}
+
+// sendBatch sends the requests in a single JSON-RPC batch, without the
+// interceptors. A node rejecting the whole batch answers with a single
+// error, which is set in every BatchElem.
+//
+// Parameters:
+// - ctx: the context
+// - b: the batch
+// Returns:
+// - error: an error of the transport, or of the decoding of the response
+func (c *HTTPClient) sendBatch(ctx context.Context, b []BatchElem) error {
+	requests := make([]jsonrpcRequest, len(b))
+	byID := make(map[uint64]int, len(b))
+	for i, elem := range b {
+		requests[i] = c.request(elem.Method, elem.Args)
+		byID[requests[i].ID] = i
+	}
+	var raw json.RawMessage
+	if err := c.post(ctx, requests, &raw); err != nil {
+		return err
+	}
+	if trimmed := bytes.TrimLeft(raw, " \t\r\n"); len(trimmed) == 0 || trimmed[0] != '[' {
+		var resp jsonrpcResponse
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			return fmt.Errorf("%s: invalid batch response: %w", c.url, err)
+		}
+		err := resp.err()
+		if err == nil {
+			return fmt.Errorf("%s: batch answered with a single response", c.url)
+		}
+		for i := range b {
+			b[i].Error = err
+		}
+		return nil
+	}
+	var responses []jsonrpcResponse
+	if err := json.Unmarshal(raw, &responses); err != nil {
+		return err
+	}
+	answered := make([]bool, len(b))
+	for _, resp := range responses {
+		i, ok := byID[resp.ID]
+		if !ok {
+			continue
+		}
+		answered[i] = true
+		b[i].Error = resp.decode(b[i].Result)
+	}
+	for i := range b {
+		if !answered[i] {
+			b[i].Error = fmt.Errorf("no response to %s", b[i].Method)
+		}
+	}
+	return nil
+}
+
+// Close does nothing, HTTP connections are managed by the HTTP client.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (c *HTTPClient) Close() {}
+
+// request builds a request with a new ID.
+//
+// Parameters:
+// - method: the method
+// - args: the parameters
+// Returns:
+// - jsonrpcRequest: the request
+func (c *HTTPClient) request(method string, args []interface{}) jsonrpcRequest {
+	if args == nil {
+		args = []interface{}{}
+	}
+	return jsonrpcRequest{JSONRPC: "2.0", ID: atomic.AddUint64(&c.id, 1), Method: method, Params: args}
+}
+
+// post sends a request body and decodes the response body.
+//
+// Parameters:
+// - ctx: the context
+// - body: the request body
+// - out: the value the response body is decoded into
+// Returns:
+// - error: an error of the transport or of the decoding
+func (c *HTTPClient) post(ctx context.Context, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// the connection is reused only if the body is read to the end
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %s", c.url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,252 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// TestHTTPClient tests the headers, authentication, timeout and error
+// decoding of the HTTP client.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestHTTPClient(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		switch req.Method {
+		case "starknet_blockNumber":
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": 42}`, req.ID)
+		case "starknet_getClassHashAt":
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "error": {"code": 20, "message": "Contract not found"}}`, req.ID)
+		default:
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewHTTPProvider(server.URL,
+		WithHTTPClient(server.Client()),
+		WithHeader("X-Tenant", "acme"),
+		WithAPIKey("secret"),
+		WithBasicAuth("user", "pass"),
+		WithTimeout(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	number, err := provider.BlockNumber(context.Background())
+	if err != nil || number != 42 {
+		t.Fatalf("expected block 42, got %d, %v", number, err)
+	}
+	if headers.Get("X-Tenant") != "acme" || headers.Get("X-Api-Key") != "secret" || headers.Get("Authorization") != "Basic dXNlcjpwYXNz" {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+
+	if _, err := provider.ClassHashAt(context.Background(), WithBlockTag("latest"), new(felt.Felt)); !errors.Is(err, ErrContractNotFound) {
+		t.Fatalf("expected ErrContractNotFound, got %v", err)
+	}
+	if _, err := provider.ChainID(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the request to time out, got %v", err)
+	}
+	if _, err := NewHTTPProvider("ws://localhost:9545"); err == nil {
+		t.Fatal("expected a websocket URL to be rejected")
+	}
+}
+
+// TestProvider_ChainID tests that the chain ID is fetched once, even by
+// concurrent callers, and never when set with WithChainID.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestProvider_ChainID(t *testing.T) {
+	var sent int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		atomic.AddInt32(&sent, 1)
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": "0x534e5f5345504f4c4941"}`, req.ID)
+	}))
+	defer server.Close()
+
+	provider := NewProvider(NewHTTPClient(server.URL))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if chainID, err := provider.ChainID(context.Background()); err != nil || chainID != "SN_SEPOLIA" {
+				t.Errorf("unexpected chain id %s, %v", chainID, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if sent != 1 {
+		t.Fatalf("expected the chain id to be fetched once, got %d requests", sent)
+	}
+
+	provider = NewProvider(NewHTTPClient(server.URL), WithChainID("SN_MAIN"))
+	if chainID, err := provider.ChainID(context.Background()); err != nil || chainID != "SN_MAIN" {
+		t.Fatalf("unexpected chain id %s, %v", chainID, err)
+	}
+	if sent != 1 {
+		t.Fatalf("expected no request with WithChainID, got %d", sent-1)
+	}
+
+	release := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer hung.Close()
+	defer close(release)
+	provider = NewProvider(NewHTTPClient(hung.URL))
+	go provider.ChainID(context.Background())
+	time.Sleep(20 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := provider.ChainID(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait for a hung node to be bounded by the context, got %v", err)
+	}
+}
+
+// TestHTTPClient_Concurrency tests a provider under concurrent requests: each
+// request gets its own response and the connections to the node are reused
+// within the limit of the transport.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestHTTPClient_Concurrency(t *testing.T) {
+	var connections int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		// the block number is the ID of the request, to match responses
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": %d}`+"\n", req.ID, req.ID)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&connections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	const maxConns = 8
+	cfg := DefaultTransportConfig
+	cfg.MaxConnsPerHost = maxConns
+	client := NewHTTPClient(server.URL, WithTransport(cfg))
+	provider := NewProvider(client)
+
+	var wg sync.WaitGroup
+	seen := make([]int32, 2001)
+	for i := 0; i < 2000; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := provider.BlockNumber(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			atomic.AddInt32(&seen[n], 1)
+		}()
+	}
+	wg.Wait()
+	for n := 1; n <= 2000; n++ {
+		if seen[n] != 1 {
+			t.Fatalf("response %d received %d times", n, seen[n])
+		}
+	}
+	if got := atomic.LoadInt64(&connections); got > maxConns {
+		t.Fatalf("%d connections opened, expected at most %d", got, maxConns)
+	}
+}
+
+// TestHTTPClient_Batch tests that a batch is answered by ID, that the results
+// of the requests without a result value are discarded, and that a batch
+// rejected with a single error sets the error in every request.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestHTTPClient_Batch(t *testing.T) {
+	reject := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			t.Errorf("decoding batch: %v", err)
+		}
+		if reject {
+			fmt.Fprint(w, `{"jsonrpc": "2.0", "id": null, "error": {"code": -32600, "message": "Invalid Request", "data": "batch too large"}}`)
+			return
+		}
+		responses := make([]string, len(requests))
+		for i, req := range requests {
+			responses[len(requests)-1-i] = fmt.Sprintf(`{"jsonrpc": "2.0", "id": %d, "result": "0x%x"}`, req.ID, req.ID)
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(responses, ","))
+	}))
+	defer server.Close()
+	client := NewHTTPClient(server.URL)
+
+	var first, third string
+	batch := []BatchElem{
+		{Method: "starknet_blockNumber", Result: &first},
+		{Method: "starknet_blockNumber"},
+		{Method: "starknet_blockNumber", Result: &third},
+	}
+	if err := client.BatchCallContext(context.Background(), batch); err != nil {
+		t.Fatal(err)
+	}
+	for i, elem := range batch {
+		if elem.Error != nil {
+			t.Fatalf("request %d failed: %v", i, elem.Error)
+		}
+	}
+	if first != "0x1" || third != "0x3" {
+		t.Fatalf("expected the results of their requests, got %s and %s", first, third)
+	}
+
+	reject = true
+	batch = []BatchElem{{Method: "starknet_blockNumber", Result: &first}, {Method: "starknet_chainId", Result: &third}}
+	if err := client.BatchCallContext(context.Background(), batch); err != nil {
+		t.Fatal(err)
+	}
+	for i, elem := range batch {
+		var rpcErr *RPCError
+		if !errors.As(elem.Error, &rpcErr) || rpcErr.Code() != InvalidRequest {
+			t.Fatalf("expected request %d to fail with the error of the batch, got %v", i, elem.Error)
+		}
+	}
+}
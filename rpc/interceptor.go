@@ -0,0 +1,325 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// Invoker sends a request and returns its raw result.
+type Invoker func(ctx context.Context, method string, params []interface{}) (json.RawMessage, error)
+
+// Interceptor is a hook around the requests of a client, e.g. for logging,
+// metrics, request signing or caching. It calls next to send the request, or
+// returns a result without sending it.
+type Interceptor func(ctx context.Context, method string, params []interface{}, next Invoker) (json.RawMessage, error)
+
+// interceptedClient is a CallCloser sending its requests through interceptors.
+type interceptedClient struct {
+	c            CallCloser
+	invoke       Invoker
+	interceptors []Interceptor
+}
+
+// interceptedBatchClient is an interceptedClient of a BatchCallCloser.
+type interceptedBatchClient struct {
+	*interceptedClient
+}
+
+// interceptedSubscriptionClient is an interceptedClient of a
+// SubscriptionCallCloser.
+type interceptedSubscriptionClient struct {
+	*interceptedClient
+}
+
+// interceptedBatchSubscriptionClient is an interceptedClient of a client
+// sending batches and subscribing to notifications.
+type interceptedBatchSubscriptionClient struct {
+	*interceptedClient
+}
+
+// Intercept wraps a client so that its requests go through the interceptors,
+// the first interceptor being the outermost. The wrapped client implements
+// BatchCallCloser and SubscriptionCallCloser when the client does: each
+// request of a batch goes through the interceptors, the requests reaching
+// the client being sent in a single batch, and the subscriptions are passed
+// to the client, their notifications not going through the interceptors.
+//
+// Parameters:
+// - c: the client
+// - interceptors: the interceptors
+// Returns:
+// - CallCloser: the wrapped client
+func Intercept(c CallCloser, interceptors ...Interceptor) CallCloser {
+	invoke := func(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+		var raw json.RawMessage
+		err := c.CallContext(ctx, &raw, method, params...)
+		return raw, err
+	}
+	ic := &interceptedClient{c: c, invoke: chain(invoke, interceptors), interceptors: interceptors}
+	_, batches := c.(BatchCallCloser)
+	_, subscribes := c.(SubscriptionCallCloser)
+	switch {
+	case batches && subscribes:
+		return interceptedBatchSubscriptionClient{ic}
+	case batches:
+		return interceptedBatchClient{ic}
+	case subscribes:
+		return interceptedSubscriptionClient{ic}
+	}
+	return ic
+}
+
+// CallContext sends a request through the interceptors and decodes its result.
+//
+// Parameters:
+// - ctx: the context
+// - result: the value the result is decoded into
+// - method: the method
+// - args: the parameters
+// Returns:
+// - error: the error of the interceptors or of the client
+func (ic *interceptedClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return decodeRaw(ctx, ic.invoke, result, method, args)
+}
+
+// Close closes the client.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (ic *interceptedClient) Close() {
+	ic.c.Close()
+}
+
+// batch sends a batch through the interceptors with the batches of the
+// client.
+//
+// Parameters:
+// - ctx: the context
+// - b: the batch
+// Returns:
+// - error: an error of the transport
+func (ic *interceptedClient) batch(ctx context.Context, b []BatchElem) error {
+	send := func(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+		var raw json.RawMessage
+		err := ic.c.CallContext(ctx, &raw, method, params...)
+		return raw, err
+	}
+	return batchThrough(ctx, b, ic.interceptors, send, ic.c.(BatchCallCloser).BatchCallContext)
+}
+
+// subscribe subscribes with the client, without the interceptors.
+//
+// Parameters:
+// - ctx: the context
+// - ch: the channel of the notifications
+// - method: the subscription method
+// - args: the parameters
+// Returns:
+// - Subscription: the subscription
+// - error: the error of the client
+func (ic *interceptedClient) subscribe(ctx context.Context, ch chan<- json.RawMessage, method string, args ...interface{}) (Subscription, error) {
+	return ic.c.(SubscriptionCallCloser).Subscribe(ctx, ch, method, args...)
+}
+
+// BatchCallContext sends a batch through the interceptors, the requests
+// reaching the client being sent in a single batch.
+//
+// Parameters:
+// - ctx: the context
+// - b: the batch
+// Returns:
+// - error: an error of the transport
+func (ic interceptedBatchClient) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	return ic.batch(ctx, b)
+}
+
+// Subscribe subscribes with the client, the notifications not going through
+// the interceptors.
+//
+// Parameters:
+// - ctx: the context
+// - ch: the channel of the notifications
+// - method: the subscription method
+// - args: the parameters
+// Returns:
+// - Subscription: the subscription
+// - error: the error of the client
+func (ic interceptedSubscriptionClient) Subscribe(ctx context.Context, ch chan<- json.RawMessage, method string, args ...interface{}) (Subscription, error) {
+	return ic.subscribe(ctx, ch, method, args...)
+}
+
+// BatchCallContext sends a batch through the interceptors, the requests
+// reaching the client being sent in a single batch.
+//
+// Parameters:
+// - ctx: the context
+// - b: the batch
+// Returns:
+// - error: an error of the transport
+func (ic interceptedBatchSubscriptionClient) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	return ic.batch(ctx, b)
+}
+
+// Subscribe subscribes with the client, the notifications not going through
+// the interceptors.
+//
+// Parameters:
+// - ctx: the context
+// - ch: the channel of the notifications
+// - method: the subscription method
+// - args: the parameters
+// Returns:
+// - Subscription: the subscription
+// - error: the error of the client
+func (ic interceptedBatchSubscriptionClient) Subscribe(ctx context.Context, ch chan<- json.RawMessage, method string, args ...interface{}) (Subscription, error) {
+	return ic.subscribe(ctx, ch, method, args...)
+}
+
+// batchKey is the context key of the index of a request of a batch sent by
+// batchThrough.
+type batchKey struct{}
+
+// queuedCall is a request of a batch that went through the interceptors.
+type queuedCall struct {
+	method string
+	params []interface{}
+	raw    json.RawMessage
+	err    error
+	done   chan struct{}
+}
+
+// batchThrough sends the requests of a batch through interceptors
+// concurrently, then sends the requests reaching the end of the chain in a
+// single batch once every request reached it or returned, e.g. from a cache.
+// Requests reaching it again after the batch is sent, e.g. retries, are sent
+// alone.
+//
+// Parameters:
+// - ctx: the context
+// - b: the batch
+// - interceptors: the interceptors
+// - send: the invoker sending a request alone
+// - sendBatch: the function sending a batch of raw results
+// Returns:
+// - error: the error of sendBatch
+func batchThrough(ctx context.Context, b []BatchElem, interceptors []Interceptor, send Invoker, sendBatch func(context.Context, []BatchElem) error) error {
+	if len(b) == 0 {
+		return nil
+	}
+	var (
+		mu       sync.Mutex
+		flushed  bool
+		queued   []*queuedCall
+		settled  = make([]bool, len(b))
+		progress = make(chan struct{}, len(b))
+	)
+	// settle signals once that a request reached the end of the chain or
+	// returned
+	settle := func(i int) {
+		if !settled[i] {
+			settled[i] = true
+			progress <- struct{}{}
+		}
+	}
+	join := func(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+		i, ok := ctx.Value(batchKey{}).(int)
+		mu.Lock()
+		if flushed || !ok {
+			mu.Unlock()
+			return send(ctx, method, params)
+		}
+		call := &queuedCall{method: method, params: params, done: make(chan struct{})}
+		queued = append(queued, call)
+		settle(i)
+		mu.Unlock()
+		<-call.done
+		return call.raw, call.err
+	}
+	invoke := chain(join, interceptors)
+
+	var wg sync.WaitGroup
+	for i := range b {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := decodeRaw(context.WithValue(ctx, batchKey{}, i), invoke, b[i].Result, b[i].Method, b[i].Args)
+			mu.Lock()
+			b[i].Error = err
+			settle(i)
+			mu.Unlock()
+		}(i)
+	}
+	for n := 0; n < len(b); n++ {
+		select {
+		case <-progress:
+		case <-ctx.Done():
+			n = len(b)
+		}
+	}
+
+	mu.Lock()
+	flushed = true
+	calls := queued
+	mu.Unlock()
+	var err error
+	if len(calls) > 0 {
+		elems := make([]BatchElem, len(calls))
+		for i, call := range calls {
+			elems[i] = BatchElem{Method: call.method, Args: call.params, Result: &calls[i].raw}
+		}
+		err = sendBatch(ctx, elems)
+		for i, call := range calls {
+			call.err = elems[i].Error
+			if err != nil {
+				call.err = err
+			}
+			close(call.done)
+		}
+	}
+	wg.Wait()
+	return err
+}
+
+// chain wraps an invoker with interceptors, the first being the outermost.
+//
+// Parameters:
+// - invoke: the invoker sending the requests
+// - interceptors: the interceptors
+// Returns:
+// - Invoker: the invoker running the interceptors
+func chain(invoke Invoker, interceptors []Interceptor) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoke
+		invoke = func(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+			return interceptor(ctx, method, params, next)
+		}
+	}
+	return invoke
+}
+
+// decodeRaw sends a request with an invoker and decodes its result.
+//
+// Parameters:
+// - ctx: the context
+// - invoke: the invoker
+// - result: the value the result is decoded into
+// - method: the method
+// - args: the parameters
+// Returns:
+// - error: the error of the invoker or of the decoding
+func decodeRaw(ctx context.Context, invoke Invoker, result interface{}, method string, args []interface{}) error {
+	raw, err := invoke(ctx, method, args)
+	if err != nil {
+		return err
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, result)
+}
@@ -0,0 +1,226 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestInterceptors tests the order of interceptors and a caching interceptor
+// answering without sending the request, on a wrapped client and on the HTTP
+// client.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestInterceptors(t *testing.T) {
+	sent := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		sent++
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": "0x534e5f5345504f4c4941"}`, req.ID)
+	}))
+	defer server.Close()
+
+	var log []string
+	logging := func(ctx context.Context, method string, params []interface{}, next Invoker) (json.RawMessage, error) {
+		log = append(log, "start "+method)
+		raw, err := next(ctx, method, params)
+		log = append(log, "end "+method)
+		return raw, err
+	}
+	cache := map[string]json.RawMessage{}
+	caching := func(ctx context.Context, method string, params []interface{}, next Invoker) (json.RawMessage, error) {
+		if raw, ok := cache[method]; ok {
+			log = append(log, "cached "+method)
+			return raw, nil
+		}
+		raw, err := next(ctx, method, params)
+		if err == nil {
+			cache[method] = raw
+		}
+		return raw, err
+	}
+	denied := errors.New("denied")
+	deny := func(ctx context.Context, method string, params []interface{}, next Invoker) (json.RawMessage, error) {
+		if method == "starknet_addInvokeTransaction" {
+			return nil, denied
+		}
+		return next(ctx, method, params)
+	}
+
+	for _, client := range []CallCloser{
+		Intercept(NewHTTPClient(server.URL), logging, caching, deny),
+		NewHTTPClient(server.URL, WithInterceptor(logging), WithInterceptor(caching), WithInterceptor(deny)),
+	} {
+		sent, log = 0, nil
+		cache = map[string]json.RawMessage{}
+		provider := NewProvider(client)
+		for i := 0; i < 2; i++ {
+			chainID, err := provider.ChainID(context.Background())
+			if err != nil || chainID != "SN_SEPOLIA" {
+				t.Fatalf("unexpected chain id %s, %v", chainID, err)
+			}
+			provider.chainID = ""
+		}
+		var raw json.RawMessage
+		if err := client.CallContext(context.Background(), &raw, "starknet_addInvokeTransaction"); !errors.Is(err, denied) {
+			t.Fatalf("expected the request to be denied, got %v", err)
+		}
+		expected := "start starknet_chainId,end starknet_chainId,start starknet_chainId,cached starknet_chainId,end starknet_chainId," +
+			"start starknet_addInvokeTransaction,end starknet_addInvokeTransaction"
+		if sent != 1 || strings.Join(log, ",") != expected {
+			t.Fatalf("expected a single request and log %s, got %d and %s", expected, sent, strings.Join(log, ","))
+		}
+	}
+}
+
+// TestInterceptors_Batch tests that the requests of a batch go through the
+// interceptors and that the requests reaching the client are sent in a
+// single batch, on a wrapped client and on the HTTP client, and that a
+// wrapped client keeps the subscriptions of its client.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestInterceptors_Batch(t *testing.T) {
+	var mu sync.Mutex
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			t.Errorf("expected a batch: %v", err)
+		}
+		mu.Lock()
+		posts++
+		mu.Unlock()
+		responses := make([]string, len(requests))
+		for i, req := range requests {
+			responses[i] = fmt.Sprintf(`{"jsonrpc": "2.0", "id": %d, "result": %d}`, req.ID, int(req.Params[0].(float64))*10)
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(responses, ","))
+	}))
+	defer server.Close()
+
+	var seen int32
+	counting := func(ctx context.Context, method string, params []interface{}, next Invoker) (json.RawMessage, error) {
+		atomic.AddInt32(&seen, 1)
+		if params[0] == 2 {
+			return json.RawMessage(`"cached"`), nil
+		}
+		return next(ctx, method, params)
+	}
+
+	for _, client := range []CallCloser{
+		Intercept(NewHTTPClient(server.URL), counting),
+		NewHTTPClient(server.URL, WithInterceptor(counting)),
+	} {
+		posts, seen = 0, 0
+		batcher, ok := client.(BatchCallCloser)
+		if !ok {
+			t.Fatal("expected the intercepted client to send batches")
+		}
+		results := make([]json.RawMessage, 3)
+		batch := make([]BatchElem, 3)
+		for i := range batch {
+			batch[i] = BatchElem{Method: "test_echo", Args: []interface{}{i + 1}, Result: &results[i]}
+		}
+		if err := batcher.BatchCallContext(context.Background(), batch); err != nil {
+			t.Fatal(err)
+		}
+		for i, elem := range batch {
+			if elem.Error != nil {
+				t.Fatalf("request %d: %v", i, elem.Error)
+			}
+		}
+		if string(results[0]) != "10" || string(results[1]) != `"cached"` || string(results[2]) != "30" {
+			t.Fatalf("unexpected results %s", results)
+		}
+		if posts != 1 || seen != 3 {
+			t.Fatalf("expected 3 intercepted requests in 1 batch, got %d in %d posts", seen, posts)
+		}
+	}
+
+	if _, ok := Intercept(&subscriptionClient{}, counting).(SubscriptionCallCloser); !ok {
+		t.Fatal("expected the intercepted client to keep the subscriptions")
+	}
+	if _, ok := Intercept(&statusClient{}, counting).(BatchCallCloser); ok {
+		t.Fatal("expected a client without batches to stay without batches")
+	}
+}
+
+// TestTracingInterceptor tests the attributes and status of the spans
+// recorded for successful and failed requests.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestTracingInterceptor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		switch req.Method {
+		case "starknet_addInvokeTransaction":
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": {"transaction_hash": "0xabc"}}`, req.ID)
+		default:
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "error": {"code": 24, "message": "Block not found"}}`, req.ID)
+		}
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	client := NewHTTPClient(server.URL, WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	var raw json.RawMessage
+	if err := client.CallContext(context.Background(), &raw, "starknet_addInvokeTransaction", BroadcastInvokev1Txn{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CallContext(context.Background(), &raw, "starknet_getBlockWithTxHashes", WithBlockNumber(7)); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	attributes := func(span sdktrace.ReadOnlySpan) map[attribute.Key]string {
+		m := map[attribute.Key]string{}
+		for _, kv := range span.Attributes() {
+			m[kv.Key] = kv.Value.Emit()
+		}
+		return m
+	}
+	add, get := attributes(spans[0]), attributes(spans[1])
+	if spans[0].Name() != "starknet_addInvokeTransaction" || add[AttributeRPCMethod] != "starknet_addInvokeTransaction" || add[AttributeTransactionHash] != "0xabc" {
+		t.Fatalf("unexpected span %s %v", spans[0].Name(), add)
+	}
+	if spans[0].SpanKind() != trace.SpanKindClient || spans[0].Status().Code != codes.Unset {
+		t.Fatalf("unexpected kind %v or status %v", spans[0].SpanKind(), spans[0].Status())
+	}
+	if get[AttributeBlockID] != "7" || get[AttributeRPCErrorCode] != "24" || spans[1].Status().Code != codes.Error {
+		t.Fatalf("unexpected span %v, status %v", get, spans[1].Status())
+	}
+}
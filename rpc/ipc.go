@@ -0,0 +1,296 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrClientClosed = errors.New("rpc: client closed")
+
+// DialFunc dials a connection, as net.Dialer.DialContext does, e.g. through
+// a SOCKS proxy such as TOR or to an in-memory server of a test harness.
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// IPCClient is a CallCloser sending JSON-RPC requests over a stream
+// connection, e.g. the unix socket of a node running on the same host. The
+// requests and responses are JSON values written back to back, matched by
+// ID, so that concurrent requests share the connection. It implements
+// BatchCallCloser.
+type IPCClient struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+	id      uint64
+
+	mu      sync.Mutex
+	pending map[uint64]chan jsonrpcResponse
+	err     error
+	done    chan struct{}
+}
+
+var _ BatchCallCloser = &IPCClient{}
+
+// NewIPCClient creates a JSON-RPC client over an established connection,
+// e.g. one dialed with a custom DialFunc. The client owns the connection and
+// closes it on Close.
+//
+// Parameters:
+// - conn: the connection
+// Returns:
+// - *IPCClient: the client
+func NewIPCClient(conn net.Conn) *IPCClient {
+	c := &IPCClient{conn: conn, pending: map[uint64]chan jsonrpcResponse{}, done: make(chan struct{})}
+	go c.read()
+	return c
+}
+
+// DialIPC connects a JSON-RPC client to the unix socket at path.
+//
+// Parameters:
+// - ctx: the context of the connection establishment
+// - path: the path of the socket
+// Returns:
+// - *IPCClient: the client
+// - error: an error if the socket cannot be dialed
+func DialIPC(ctx context.Context, path string) (*IPCClient, error) {
+	var dialer net.Dialer
+	return DialStream(ctx, dialer.DialContext, "unix", path)
+}
+
+// DialStream connects a JSON-RPC client to address with dial, e.g. to a TCP
+// endpoint streaming JSON-RPC through a proxy.
+//
+// Parameters:
+// - ctx: the context of the connection establishment
+// - dial: the dial function
+// - network: the network, e.g. "unix" or "tcp"
+// - address: the address
+// Returns:
+// - *IPCClient: the client
+// - error: an error if the address cannot be dialed
+func DialStream(ctx context.Context, dial DialFunc, network, address string) (*IPCClient, error) {
+	conn, err := dial(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	return NewIPCClient(conn), nil
+}
+
+// NewIPCProvider creates a provider sending JSON-RPC requests over the unix
+// socket at path.
+//
+// Parameters:
+// - ctx: the context of the connection establishment
+// - path: the path of the socket
+// - opts: the options of the provider
+// Returns:
+// - *Provider: the provider
+// - error: an error if the socket cannot be dialed
+func NewIPCProvider(ctx context.Context, path string, opts ...ProviderOption) (*Provider, error) {
+	client, err := DialIPC(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return NewProvider(client, opts...), nil
+}
+
+// CallContext sends a JSON-RPC request and decodes its result.
+//
+// Parameters:
+// - ctx: the context
+// - result: the value the result is decoded into
+// - method: the method
+// - args: the parameters
+// Returns:
+// - error: an *RPCError if the node returns an error, ErrClientClosed if the
+// connection is closed, or an error of the transport
+func (c *IPCClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	req := c.request(method, args)
+	responses, err := c.roundTrip(ctx, req, []uint64{req.ID})
+	if err != nil {
+		return err
+	}
+	return responses[0].decode(result)
+}
+
+// BatchCallContext sends the requests in a single JSON-RPC batch. The error
+// of each request is set in its BatchElem.
+//
+// Parameters:
+// - ctx: the context
+// - b: the batch
+// Returns:
+// - error: ErrClientClosed if the connection is closed, or an error of the
+// transport
+func (c *IPCClient) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	if len(b) == 0 {
+		return nil
+	}
+	requests := make([]jsonrpcRequest, len(b))
+	ids := make([]uint64, len(b))
+	for i, elem := range b {
+		requests[i] = c.request(elem.Method, elem.Args)
+		ids[i] = requests[i].ID
+	}
+	responses, err := c.roundTrip(ctx, requests, ids)
+	if err != nil {
+		return err
+	}
+	for i := range b {
+		b[i].Error = responses[i].decode(b[i].Result)
+	}
+	return nil
+}
+
+// Close closes the connection, failing the pending requests with
+// ErrClientClosed.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (c *IPCClient) Close() {
+	c.conn.Close()
+	<-c.done
+}
+
+// request builds a request with a new ID.
+//
+// Parameters:
+// - method: the method
+// - args: the parameters
+// Returns:
+// - jsonrpcRequest: the request
+func (c *IPCClient) request(method string, args []interface{}) jsonrpcRequest {
+	if args == nil {
+		args = []interface{}{}
+	}
+	return jsonrpcRequest{JSONRPC: "2.0", ID: atomic.AddUint64(&c.id, 1), Method: method, Params: args}
+}
+
+// roundTrip writes a request or a batch and waits for the responses of its
+// IDs.
+//
+// Parameters:
+// - ctx: the context
+// - body: the request or the batch
+// - ids: the IDs of the requests
+// Returns:
+// - []jsonrpcResponse: the responses, in the order of ids
+// - error: the error of the context, of the transport, or ErrClientClosed
+func (c *IPCClient) roundTrip(ctx context.Context, body interface{}, ids []uint64) ([]jsonrpcResponse, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make([]chan jsonrpcResponse, len(ids))
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return nil, c.err
+	}
+	for i, id := range ids {
+		channels[i] = make(chan jsonrpcResponse, 1)
+		c.pending[id] = channels[i]
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		for _, id := range ids {
+			delete(c.pending, id)
+		}
+		c.mu.Unlock()
+	}()
+
+	if err := c.write(ctx, payload); err != nil {
+		return nil, err
+	}
+	responses := make([]jsonrpcResponse, len(ids))
+	for i, ch := range channels {
+		select {
+		case responses[i] = <-ch:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.done:
+			return nil, c.err
+		}
+	}
+	return responses, nil
+}
+
+// write writes a payload to the connection, bounded by the deadline of the
+// context.
+//
+// Parameters:
+// - ctx: the context
+// - payload: the payload
+// Returns:
+// - error: an error of the transport
+func (c *IPCClient) write(ctx context.Context, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	deadline, _ := ctx.Deadline()
+	if err := c.conn.SetWriteDeadline(deadline); err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	defer c.conn.SetWriteDeadline(time.Time{})
+	_, err := c.conn.Write(append(payload, '\n'))
+	return err
+}
+
+// read decodes the responses of the connection until it is closed, and
+// dispatches them to the pending requests. Notifications and responses of
+// abandoned requests are dropped.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (c *IPCClient) read() {
+	decoder := json.NewDecoder(c.conn)
+	var err error
+	for {
+		var raw json.RawMessage
+		if err = decoder.Decode(&raw); err != nil {
+			break
+		}
+		var responses []jsonrpcResponse
+		if trimmed := bytes.TrimLeft(raw, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '[' {
+			err = json.Unmarshal(raw, &responses)
+		} else {
+			var resp jsonrpcResponse
+			err = json.Unmarshal(raw, &resp)
+			responses = append(responses, resp)
+		}
+		if err != nil {
+			break
+		}
+		c.mu.Lock()
+		for _, resp := range responses {
+			if ch, ok := c.pending[resp.ID]; ok {
+				ch <- resp
+				delete(c.pending, resp.ID)
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	c.err = fmt.Errorf("%w: %v", ErrClientClosed, err)
+	c.mu.Unlock()
+	c.conn.Close()
+	close(c.done)
+}
@@ -0,0 +1,187 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// serveIPC serves the JSON-RPC requests of a connection, answering
+// starknet_blockNumber with 42 and starknet_chainId with an error, the
+// batches as batches.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// - conn: the connection
+// Returns:
+//
+//	none
+func serveIPC(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	answer := func(req jsonrpcRequest) json.RawMessage {
+		if req.Method == "starknet_blockNumber" {
+			return json.RawMessage(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":42}`, req.ID))
+		}
+		return json.RawMessage(fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"error":{"code":-32601,"message":"Method not found"}}`, req.ID))
+	}
+	decoder := json.NewDecoder(conn)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return
+		}
+		var out []byte
+		if raw[0] == '[' {
+			var requests []jsonrpcRequest
+			if err := json.Unmarshal(raw, &requests); err != nil {
+				t.Errorf("decoding batch: %v", err)
+				return
+			}
+			// answered in reverse order, matched by ID
+			responses := make([]json.RawMessage, 0, len(requests))
+			for i := len(requests) - 1; i >= 0; i-- {
+				responses = append(responses, answer(requests[i]))
+			}
+			out, _ = json.Marshal(responses)
+		} else {
+			var req jsonrpcRequest
+			if err := json.Unmarshal(raw, &req); err != nil {
+				t.Errorf("decoding request: %v", err)
+				return
+			}
+			// a notification written before the response is dropped
+			out = append([]byte(`{"jsonrpc":"2.0","method":"starknet_subscriptionNewHeads","params":{}}`), answer(req)...)
+		}
+		if _, err := conn.Write(out); err != nil {
+			return
+		}
+	}
+}
+
+// TestIPCClient tests calls, concurrent calls and batches over a unix
+// socket, and the failure of the calls once the client is closed.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestIPCClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.ipc")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveIPC(t, conn)
+		}
+	}()
+
+	provider, err := NewIPCProvider(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			number, err := provider.BlockNumber(context.Background())
+			if err != nil || number != 42 {
+				t.Errorf("expected block 42, got %d, %v", number, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, err := provider.ChainID(context.Background()); err == nil {
+		t.Fatal("expected the error of the node")
+	}
+
+	client := provider.c.(*IPCClient)
+	var number uint64
+	var chainID string
+	batch := []BatchElem{
+		{Method: "starknet_blockNumber", Result: &number},
+		{Method: "starknet_chainId", Result: &chainID},
+	}
+	if err := client.BatchCallContext(context.Background(), batch); err != nil {
+		t.Fatal(err)
+	}
+	if batch[0].Error != nil || number != 42 || batch[1].Error == nil {
+		t.Fatalf("unexpected batch results %d, %v, %v", number, batch[0].Error, batch[1].Error)
+	}
+
+	client.Close()
+	if _, err := provider.BlockNumber(context.Background()); !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("expected ErrClientClosed, got %v", err)
+	}
+	if _, err := NewIPCProvider(context.Background(), filepath.Join(t.TempDir(), "missing.ipc")); err == nil {
+		t.Fatal("expected a missing socket to be rejected")
+	}
+}
+
+// TestHTTPClient_Dialer tests HTTP requests sent to a server listening on a
+// unix socket, and through a custom dialer.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestHTTPClient_Dialer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": 42}`, req.ID)
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	provider, err := NewHTTPProvider("http://node/rpc", WithUnixSocket(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if number, err := provider.BlockNumber(context.Background()); err != nil || number != 42 {
+		t.Fatalf("expected block 42, got %d, %v", number, err)
+	}
+
+	var dials int32
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+		if address != "node:80" {
+			t.Errorf("unexpected address %s", address)
+		}
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", path)
+	}
+	provider, err = NewHTTPProvider("http://node/rpc", WithDialer(dial), WithTransport(DefaultTransportConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if number, err := provider.BlockNumber(context.Background()); err != nil || number != 42 {
+		t.Fatalf("expected block 42, got %d, %v", number, err)
+	}
+	if atomic.LoadInt32(&dials) != 1 {
+		t.Fatalf("expected 1 dial, got %d", dials)
+	}
+}
@@ -0,0 +1,36 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// StorageProof returns the Merkle proofs of classes, contracts and storage
+// keys in the state of a block, as specified by the RPC v0.8 method
+// starknet_getStorageProof. The proofs can be checked against the state root
+// of the block with the proof package.
+//
+// Parameters:
+// - ctx: The context.Context object for the function call
+// - input: The block and the classes, contracts and storage keys to prove
+// Returns:
+// - *StorageProofResult: The proofs
+// - error: ErrStorageProofNotSupported if the node does not keep proofs of the block, or an error if any
+func (provider *Provider) StorageProof(ctx context.Context, input StorageProofInput) (*StorageProofResult, error) {
+	classHashes, contractAddresses, storageKeys := input.ClassHashes, input.ContractAddresses, input.ContractsStorageKeys
+	if classHashes == nil {
+		classHashes = []*felt.Felt{}
+	}
+	if contractAddresses == nil {
+		contractAddresses = []*felt.Felt{}
+	}
+	if storageKeys == nil {
+		storageKeys = []ContractStorageKeys{}
+	}
+	var result StorageProofResult
+	if err := do(ctx, provider.c, "starknet_getStorageProof", &result, input.BlockID, classHashes, contractAddresses, storageKeys); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrBlockNotFound, ErrStorageProofNotSupported)
+	}
+	return &result, nil
+}
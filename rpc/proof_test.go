@@ -0,0 +1,75 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// TestStorageProof tests that StorageProof sends empty lists for the missing
+// inputs, decodes the proof nodes, and maps the error of unsupported blocks.
+//
+// Parameters:
+// - t: The testing.T object for testing purposes
+// Returns:
+//
+//	none
+func TestStorageProof(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		if req.Method != "starknet_getStorageProof" {
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		raw, _ := json.Marshal(req.Params)
+		if string(raw) != `[{"block_number":7},[],["0x1"],[]]` {
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "error": {"code": 42, "message": "too far"}}`, req.ID)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": {
+			"classes_proof": [],
+			"contracts_proof": {
+				"nodes": [
+					{"node_hash": "0xa", "node": {"left": "0x1", "right": "0x2"}},
+					{"node_hash": "0xb", "node": {"path": "0x3", "length": 2, "child": "0x4"}}
+				],
+				"contract_leaves_data": [{"nonce": "0x1", "class_hash": "0x5", "storage_root": "0x6"}]
+			},
+			"contracts_storage_proofs": [],
+			"global_roots": {"contracts_tree_root": "0xa", "classes_tree_root": "0x0", "block_hash": "0xc"}
+		}}`, req.ID)
+	}))
+	defer server.Close()
+	provider := NewProvider(NewHTTPClient(server.URL))
+	address := new(felt.Felt).SetUint64(1)
+
+	result, err := provider.StorageProof(context.Background(), StorageProofInput{
+		BlockID:           WithBlockNumber(7),
+		ContractAddresses: []*felt.Felt{address},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := result.ContractsProof.Nodes
+	if len(nodes) != 2 || nodes[0].Node.IsEdge() || !nodes[1].Node.IsEdge() || nodes[1].Node.Length != 2 {
+		t.Fatalf("unexpected nodes %+v", nodes)
+	}
+	if leaves := result.ContractsProof.ContractLeavesData; len(leaves) != 1 || leaves[0].StorageRoot.String() != "0x6" {
+		t.Fatalf("unexpected leaves %+v", leaves)
+	}
+	if result.GlobalRoots.BlockHash.String() != "0xc" {
+		t.Fatalf("unexpected roots %+v", result.GlobalRoots)
+	}
+
+	_, err = provider.StorageProof(context.Background(), StorageProofInput{BlockID: WithBlockNumber(1)})
+	if err != ErrStorageProofNotSupported {
+		t.Fatalf("expected ErrStorageProofNotSupported, got %v", err)
+	}
+}
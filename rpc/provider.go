@@ -3,8 +3,11 @@ package rpc
 import (
 	"context"
 	"errors"
+	"sync"
+	"time"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/contracts"
 )
 
 // ErrNotFound is returned by API methods if the requested item does not exist.
@@ -12,17 +15,68 @@ var (
 	errNotFound = errors.New("not found")
 )
 
-// Provider provides the provider for starknet.go/rpc implementation.
+// Provider provides the provider for starknet.go/rpc implementation. It is
+// safe for concurrent use by multiple goroutines, as are the clients of this
+// package.
 type Provider struct {
-	c       CallCloser
+	c CallCloser
+
+	mu          sync.Mutex
+	chainID     string
+	chainIDCall *chainIDCall
+
+	pollInterval time.Duration
+}
+
+// chainIDCall is a fetch of the chain ID, shared by the concurrent callers of
+// ChainID. Its fields are set before done is closed.
+type chainIDCall struct {
+	done    chan struct{}
 	chainID string
+	err     error
+}
+
+// ProviderOption configures a Provider.
+type ProviderOption func(*Provider)
+
+// WithChainID sets the chain ID of the provider, e.g. "SN_MAIN", so that
+// ChainID never calls the node.
+//
+// Parameters:
+// - chainID: the chain ID, as a short string
+// Returns:
+// - ProviderOption: the option
+func WithChainID(chainID string) ProviderOption {
+	return func(provider *Provider) {
+		provider.chainID = chainID
+	}
+}
+
+// WithPollInterval sets the interval the provider polls the node at when its
+// client does not support subscriptions, DefaultPollInterval by default.
+//
+// Parameters:
+// - interval: the poll interval, ignored if not positive
+// Returns:
+// - ProviderOption: the option
+func WithPollInterval(interval time.Duration) ProviderOption {
+	return func(provider *Provider) {
+		if interval > 0 {
+			provider.pollInterval = interval
+		}
+	}
 }
 
 // NewProvider creates a new Provider instance with the given RPC (`go-ethereum/rpc`) client.
 //
 // It takes a *rpc.Client as a parameter and returns a pointer to a Provider struct.
-func NewProvider(c CallCloser) *Provider {
-	return &Provider{c: c}
+// The chain ID is fetched on the first call to ChainID and cached, unless set with WithChainID.
+func NewProvider(c CallCloser, opts ...ProviderOption) *Provider {
+	provider := &Provider{c: c, pollInterval: DefaultPollInterval}
+	for _, opt := range opts {
+		opt(provider)
+	}
+	return provider
 }
 
 //go:generate mockgen -destination=../mocks/mock_rpc_provider.go -package=mocks -source=provider.go api
@@ -33,27 +87,38 @@ type RpcProvider interface {
 	BlockHashAndNumber(ctx context.Context) (*BlockHashAndNumberOutput, error)
 	BlockNumber(ctx context.Context) (uint64, error)
 	BlockTransactionCount(ctx context.Context, blockID BlockID) (uint64, error)
-	BlockWithTxHashes(ctx context.Context, blockID BlockID) (interface{}, error)
-	BlockWithTxs(ctx context.Context, blockID BlockID) (interface{}, error)
+	BlockWithTxHashes(ctx context.Context, blockID BlockID) (*BlockTxHashesResult, error)
+	BlockWithTxs(ctx context.Context, blockID BlockID) (*BlockResult, error)
+	BlockWithReceipts(ctx context.Context, blockID BlockID) (*BlockWithReceiptsResult, error)
 	Call(ctx context.Context, call FunctionCall, block BlockID) ([]*felt.Felt, error)
+	CallWithStateOverrides(ctx context.Context, call FunctionCall, block BlockID, overrides []StateOverride) ([]*felt.Felt, error)
 	ChainID(ctx context.Context) (string, error)
 	Class(ctx context.Context, blockID BlockID, classHash *felt.Felt) (ClassOutput, error)
 	ClassAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (ClassOutput, error)
 	ClassHashAt(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error)
+	CompiledCasm(ctx context.Context, classHash *felt.Felt) (*contracts.CasmClass, error)
 	EstimateFee(ctx context.Context, requests []BroadcastTxn, simulationFlags []SimulationFlag, blockID BlockID) ([]FeeEstimate, error)
 	EstimateMessageFee(ctx context.Context, msg MsgFromL1, blockID BlockID) (*FeeEstimate, error)
 	Events(ctx context.Context, input EventsInput) (*EventChunk, error)
+	FeeHistory(ctx context.Context, n int) (FeeHistory, error)
+	GasPrices(ctx context.Context, blockID BlockID) (*BlockGasPrices, error)
 	GetTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*TxnStatusResp, error)
+	MessagesStatus(ctx context.Context, l1TransactionHash NumAsHex) ([]MessageStatus, error)
+	SubscribeTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (<-chan TxnStatusUpdate, error)
 	Nonce(ctx context.Context, blockID BlockID, contractAddress *felt.Felt) (*felt.Felt, error)
 	SimulateTransactions(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag) ([]SimulatedTransaction, error)
+	SimulateTransactionsWithStateOverrides(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag, overrides []StateOverride) ([]SimulatedTransaction, error)
 	StateUpdate(ctx context.Context, blockID BlockID) (*StateUpdateOutput, error)
 	StorageAt(ctx context.Context, contractAddress *felt.Felt, key string, blockID BlockID) (string, error)
+	StorageAtKeys(ctx context.Context, contractAddress *felt.Felt, keys []*felt.Felt, blockID BlockID) (map[felt.Felt]*felt.Felt, error)
+	StorageProof(ctx context.Context, input StorageProofInput) (*StorageProofResult, error)
+	StreamEvents(ctx context.Context, contracts []*felt.Felt, keys [][]*felt.Felt, fromBlock uint64) (<-chan StreamedEvent, error)
 	SpecVersion(ctx context.Context) (string, error)
 	Syncing(ctx context.Context) (*SyncStatus, error)
 	TraceBlockTransactions(ctx context.Context, blockID BlockID) ([]Trace, error)
 	TransactionByBlockIdAndIndex(ctx context.Context, blockID BlockID, index uint64) (Transaction, error)
 	TransactionByHash(ctx context.Context, hash *felt.Felt) (Transaction, error)
-	TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (TransactionReceipt, error)
+	TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*Receipt, error)
 	TraceTransaction(ctx context.Context, transactionHash *felt.Felt) (TxnTrace, error)
 }
 
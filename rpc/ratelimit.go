@@ -0,0 +1,94 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket. Requests beyond the burst take a token in
+// advance, the bucket going negative, and wait until it is refilled, so that
+// waiting requests are served in order.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rps    float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// RateLimit returns an interceptor sending at most rps requests per second on
+// average, and bursts of at most burst requests. Requests beyond the limit
+// wait for their turn, unless their context ends first, e.g. to stay under
+// the quotas of public endpoints instead of getting 429 responses.
+//
+// Parameters:
+// - rps: the number of requests per second
+// - burst: the number of requests sent at once after an idle period, at least 1
+// Returns:
+// - Interceptor: the interceptor, not limiting the requests if rps is not positive
+func RateLimit(rps float64, burst int) Interceptor {
+	if burst < 1 {
+		burst = 1
+	}
+	limiter := &rateLimiter{rps: rps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+	return func(ctx context.Context, method string, params []interface{}, next Invoker) (json.RawMessage, error) {
+		if rps > 0 {
+			if err := limiter.wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return next(ctx, method, params)
+	}
+}
+
+// WithRateLimit limits the requests of the client as RateLimit does. The
+// limiter is the outermost interceptor if it is the first option adding one.
+//
+// Parameters:
+// - rps: the number of requests per second
+// - burst: the number of requests sent at once after an idle period
+// Returns:
+// - HTTPOption: the option
+func WithRateLimit(rps float64, burst int) HTTPOption {
+	return WithInterceptor(RateLimit(rps, burst))
+}
+
+// wait takes a token, waiting for the bucket to be refilled if it is empty.
+//
+// Parameters:
+// - ctx: the context
+// Returns:
+// - error: the error of the context if it ends before a token is available
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rps
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rps * float64(time.Second))
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// give the token back to the requests waiting after this one
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
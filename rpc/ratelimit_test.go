@@ -0,0 +1,75 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRateLimit tests that the rate limiter lets a burst through, spaces the
+// following requests, and stops waiting when the context ends.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestRateLimit(t *testing.T) {
+	next := func(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+		return json.RawMessage(`"0x1"`), nil
+	}
+	limit := RateLimit(50, 5)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 15; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := limit(context.Background(), "starknet_chainId", nil, next); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	// the 10 requests after the burst are spaced by 20ms
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond || elapsed > 2*time.Second {
+		t.Fatalf("15 requests at 50 rps with a burst of 5 took %s", elapsed)
+	}
+
+	slow := RateLimit(1, 1)
+	if _, err := slow(context.Background(), "starknet_chainId", nil, next); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := slow(ctx, "starknet_chainId", nil, next); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": 7}`, req.ID)
+	}))
+	defer server.Close()
+	provider := NewProvider(NewHTTPClient(server.URL, WithRateLimit(1, 1)))
+	if _, err := provider.BlockNumber(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := provider.BlockNumber(ctx); !errors.Is(err, context.DeadlineExceeded) || requests != 1 {
+		t.Fatalf("expected the second request to wait and not be sent, got %v after %d requests", err, requests)
+	}
+}
@@ -0,0 +1,231 @@
+package rpc
+
+import (
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// DeclaredClass is a class declared in a state diff.
+type DeclaredClass struct {
+	ClassHash *felt.Felt
+	// CompiledClassHash is the hash of the CASM class, nil for Cairo 0 classes
+	CompiledClassHash *felt.Felt
+	// Deprecated is true for Cairo 0 classes
+	Deprecated bool
+}
+
+// ClassChange is a contract deployed or whose class was replaced in a state
+// diff.
+type ClassChange struct {
+	ContractAddress *felt.Felt
+	ClassHash       *felt.Felt
+	// Deployed is true for deployed contracts, false for replaced classes
+	Deployed bool
+}
+
+// StorageChanges returns the storage keys of a contract changed in the state
+// diff, with their new values.
+//
+//	update, err := provider.StateUpdate(ctx, WithBlockNumber(n))
+//	changes := update.StateDiff.StorageChanges(tokenAddress)
+//
+// Parameters:
+// - contractAddress: the address of the contract
+// Returns:
+// - []StorageEntry: the changed keys and their values, empty if the contract storage did not change
+func (d *StateDiff) StorageChanges(contractAddress *felt.Felt) []StorageEntry {
+	var entries []StorageEntry
+	for _, diff := range d.StorageDiffs {
+		if diff.Address.Equal(contractAddress) {
+			entries = append(entries, diff.StorageEntries...)
+		}
+	}
+	return entries
+}
+
+// StorageChange returns the new value of a storage key of a contract.
+//
+// Parameters:
+// - contractAddress: the address of the contract
+// - key: the storage key, e.g. as returned by utils.StorageVarAddress
+// Returns:
+// - *felt.Felt: the new value
+// - bool: false if the key did not change
+func (d *StateDiff) StorageChange(contractAddress, key *felt.Felt) (*felt.Felt, bool) {
+	var value *felt.Felt
+	for _, entry := range d.StorageChanges(contractAddress) {
+		if entry.Key.Equal(key) {
+			value = entry.Value
+		}
+	}
+	return value, value != nil
+}
+
+// ChangedContracts returns the addresses of the contracts whose storage
+// changed in the state diff, in the order of the diff.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*felt.Felt: the addresses
+func (d *StateDiff) ChangedContracts() []*felt.Felt {
+	var addresses []*felt.Felt
+	seen := map[felt.Felt]bool{}
+	for _, diff := range d.StorageDiffs {
+		if !seen[*diff.Address] {
+			seen[*diff.Address] = true
+			addresses = append(addresses, diff.Address)
+		}
+	}
+	return addresses
+}
+
+// Declared returns the classes declared in the state diff, the Sierra classes
+// followed by the Cairo 0 classes.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []DeclaredClass: the declared classes
+func (d *StateDiff) Declared() []DeclaredClass {
+	var classes []DeclaredClass
+	for _, class := range d.DeclaredClasses {
+		classes = append(classes, DeclaredClass{ClassHash: class.ClassHash, CompiledClassHash: class.CompiledClassHash})
+	}
+	for _, classHash := range d.DeprecatedDeclaredClasses {
+		classes = append(classes, DeclaredClass{ClassHash: classHash, Deprecated: true})
+	}
+	return classes
+}
+
+// IsDeclared reports whether a class was declared in the state diff.
+//
+// Parameters:
+// - classHash: the hash of the class
+// Returns:
+// - bool: true if the class was declared
+func (d *StateDiff) IsDeclared(classHash *felt.Felt) bool {
+	for _, class := range d.Declared() {
+		if class.ClassHash.Equal(classHash) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassChanges returns the contracts deployed and the contracts whose class
+// was replaced in the state diff.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []ClassChange: the deployed contracts followed by the replaced classes
+func (d *StateDiff) ClassChanges() []ClassChange {
+	var changes []ClassChange
+	for _, deployed := range d.DeployedContracts {
+		changes = append(changes, ClassChange{ContractAddress: deployed.Address, ClassHash: deployed.ClassHash, Deployed: true})
+	}
+	for _, replaced := range d.ReplacedClasses {
+		changes = append(changes, ClassChange{ContractAddress: replaced.ContractClass, ClassHash: replaced.ClassHash})
+	}
+	return changes
+}
+
+// NonceChanges returns the new nonces of the contracts whose nonce advanced
+// in the state diff.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - map[felt.Felt]*felt.Felt: the new nonces by contract address
+func (d *StateDiff) NonceChanges() map[felt.Felt]*felt.Felt {
+	nonces := make(map[felt.Felt]*felt.Felt, len(d.Nonces))
+	for _, nonce := range d.Nonces {
+		nonces[*nonce.ContractAddress] = nonce.Nonce
+	}
+	return nonces
+}
+
+// NonceChange returns the new nonce of a contract.
+//
+// Parameters:
+// - contractAddress: the address of the contract
+// Returns:
+// - *felt.Felt: the new nonce
+// - bool: false if the nonce of the contract did not advance
+func (d *StateDiff) NonceChange(contractAddress *felt.Felt) (*felt.Felt, bool) {
+	nonce, ok := d.NonceChanges()[*contractAddress]
+	return nonce, ok
+}
+
+// MergeStateDiffs merges the state diffs of consecutive blocks, oldest first,
+// into the net state diff of the range: the last value of each storage key,
+// nonce and contract class, and every declared class.
+//
+// Parameters:
+// - diffs: the state diffs, oldest first
+// Returns:
+// - StateDiff: the merged state diff
+func MergeStateDiffs(diffs ...StateDiff) StateDiff {
+	var merged StateDiff
+	storage := map[felt.Felt]int{}
+	keys := map[[2]felt.Felt]int{}
+	deployed := map[felt.Felt]int{}
+	replaced := map[felt.Felt]int{}
+	nonces := map[felt.Felt]int{}
+	for _, diff := range diffs {
+		for _, item := range diff.StorageDiffs {
+			i, ok := storage[*item.Address]
+			if !ok {
+				i = len(merged.StorageDiffs)
+				storage[*item.Address] = i
+				merged.StorageDiffs = append(merged.StorageDiffs, ContractStorageDiffItem{Address: item.Address})
+			}
+			for _, entry := range item.StorageEntries {
+				id := [2]felt.Felt{*item.Address, *entry.Key}
+				if j, ok := keys[id]; ok {
+					merged.StorageDiffs[i].StorageEntries[j].Value = entry.Value
+					continue
+				}
+				keys[id] = len(merged.StorageDiffs[i].StorageEntries)
+				merged.StorageDiffs[i].StorageEntries = append(merged.StorageDiffs[i].StorageEntries, entry)
+			}
+		}
+		merged.DeclaredClasses = append(merged.DeclaredClasses, diff.DeclaredClasses...)
+		merged.DeprecatedDeclaredClasses = append(merged.DeprecatedDeclaredClasses, diff.DeprecatedDeclaredClasses...)
+		for _, item := range diff.DeployedContracts {
+			deployed[*item.Address] = len(merged.DeployedContracts)
+			merged.DeployedContracts = append(merged.DeployedContracts, item)
+		}
+		for _, item := range diff.ReplacedClasses {
+			if i, ok := deployed[*item.ContractClass]; ok {
+				// a contract deployed then replaced in the range is deployed
+				// with its last class
+				merged.DeployedContracts[i].ClassHash = item.ClassHash
+				continue
+			}
+			if i, ok := replaced[*item.ContractClass]; ok {
+				merged.ReplacedClasses[i].ClassHash = item.ClassHash
+				continue
+			}
+			replaced[*item.ContractClass] = len(merged.ReplacedClasses)
+			merged.ReplacedClasses = append(merged.ReplacedClasses, item)
+		}
+		for _, item := range diff.Nonces {
+			if i, ok := nonces[*item.ContractAddress]; ok {
+				merged.Nonces[i].Nonce = item.Nonce
+				continue
+			}
+			nonces[*item.ContractAddress] = len(merged.Nonces)
+			merged.Nonces = append(merged.Nonces, item)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,88 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// TestStateDiff tests the queries of the storage changes, declared classes,
+// class changes and nonces of a state diff, and the merge of the diffs of
+// consecutive blocks.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestStateDiff(t *testing.T) {
+	var older, newer StateUpdateOutput
+	if err := json.Unmarshal([]byte(`{"block_hash": "0x1", "new_root": "0x2", "old_root": "0x3", "state_diff": {
+		"storage_diffs": [{"address": "0xa", "storage_entries": [{"key": "0x1", "value": "0x10"}, {"key": "0x2", "value": "0x20"}]}, {"address": "0xb", "storage_entries": [{"key": "0x1", "value": "0x11"}]}],
+		"deprecated_declared_classes": ["0xc0"],
+		"declared_classes": [{"class_hash": "0xc1", "compiled_class_hash": "0xcc1"}],
+		"deployed_contracts": [{"address": "0xb", "class_hash": "0xc1"}],
+		"replaced_classes": [],
+		"nonces": [{"contract_address": "0xa", "nonce": "0x5"}]}}`), &older); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"block_hash": "0x4", "new_root": "0x5", "old_root": "0x2", "state_diff": {
+		"storage_diffs": [{"address": "0xa", "storage_entries": [{"key": "0x2", "value": "0x21"}, {"key": "0x3", "value": "0x30"}]}],
+		"deprecated_declared_classes": [],
+		"declared_classes": [{"class_hash": "0xc2", "compiled_class_hash": "0xcc2"}],
+		"deployed_contracts": [],
+		"replaced_classes": [{"contract_address": "0xb", "class_hash": "0xc2"}, {"contract_address": "0xa", "class_hash": "0xc2"}],
+		"nonces": [{"contract_address": "0xa", "nonce": "0x6"}]}}`), &newer); err != nil {
+		t.Fatal(err)
+	}
+	a, b := new(felt.Felt).SetUint64(0xa), new(felt.Felt).SetUint64(0xb)
+	diff := older.StateDiff
+
+	if changes := diff.StorageChanges(a); len(changes) != 2 || changes[1].Value.String() != "0x20" {
+		t.Fatalf("unexpected storage changes %v", changes)
+	}
+	if value, ok := diff.StorageChange(b, new(felt.Felt).SetUint64(1)); !ok || value.String() != "0x11" {
+		t.Fatalf("unexpected storage change %v %v", value, ok)
+	}
+	if _, ok := diff.StorageChange(b, new(felt.Felt).SetUint64(2)); ok {
+		t.Fatal("unchanged key reported as changed")
+	}
+	if contracts := diff.ChangedContracts(); len(contracts) != 2 || !contracts[1].Equal(b) {
+		t.Fatalf("unexpected changed contracts %v", contracts)
+	}
+	declared := diff.Declared()
+	if len(declared) != 2 || declared[0].CompiledClassHash.String() != "0xcc1" || !declared[1].Deprecated {
+		t.Fatalf("unexpected declared classes %+v", declared)
+	}
+	if !diff.IsDeclared(new(felt.Felt).SetUint64(0xc0)) || diff.IsDeclared(new(felt.Felt).SetUint64(0xc2)) {
+		t.Fatal("unexpected IsDeclared")
+	}
+	if changes := diff.ClassChanges(); len(changes) != 1 || !changes[0].Deployed || !changes[0].ContractAddress.Equal(b) {
+		t.Fatalf("unexpected class changes %+v", changes)
+	}
+	if nonce, ok := diff.NonceChange(a); !ok || nonce.String() != "0x5" {
+		t.Fatalf("unexpected nonce %v %v", nonce, ok)
+	}
+	if _, ok := diff.NonceChange(b); ok {
+		t.Fatal("unchanged nonce reported as changed")
+	}
+
+	merged := MergeStateDiffs(older.StateDiff, newer.StateDiff)
+	if changes := merged.StorageChanges(a); len(changes) != 3 || changes[1].Value.String() != "0x21" || changes[2].Value.String() != "0x30" {
+		t.Fatalf("unexpected merged storage changes %v", changes)
+	}
+	if len(merged.Declared()) != 3 {
+		t.Fatalf("unexpected merged declared classes %+v", merged.Declared())
+	}
+	changes := merged.ClassChanges()
+	if len(changes) != 2 || !changes[0].Deployed || changes[0].ClassHash.String() != "0xc2" || changes[1].Deployed || !changes[1].ContractAddress.Equal(a) {
+		t.Fatalf("unexpected merged class changes %+v", changes)
+	}
+	if nonce, _ := merged.NonceChange(a); len(merged.Nonces) != 1 || nonce.String() != "0x6" {
+		t.Fatalf("unexpected merged nonces %+v", merged.Nonces)
+	}
+	if older.StateDiff.StorageDiffs[0].StorageEntries[1].Value.String() != "0x20" {
+		t.Fatal("merge modified its inputs")
+	}
+}
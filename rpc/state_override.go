@@ -0,0 +1,128 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var ErrStateOverridesNotSupported = errors.New("state overrides not supported by the node")
+
+// erc20BalancesVariable is the storage variable of the balances of the ERC20
+// tokens of OpenZeppelin, e.g. ETH and STRK.
+const erc20BalancesVariable = "ERC20_balances"
+
+// StateOverride replaces the state of a contract for a call or a simulation,
+// without changing the state of the node.
+type StateOverride struct {
+	ContractAddress *felt.Felt `json:"contract_address"`
+	// Storage are the slots written before the execution
+	Storage []StorageEntry `json:"storage,omitempty"`
+	// Nonce replaces the nonce of the contract, if set
+	Nonce *felt.Felt `json:"nonce,omitempty"`
+	// ClassHash replaces the class of the contract, if set, e.g. to test an
+	// upgrade with a declared class before upgrading
+	ClassHash *felt.Felt `json:"class_hash,omitempty"`
+}
+
+// BalanceOverride returns the override of the balance of an owner in an
+// ERC20 token storing its balances in ERC20_balances, as ETH and STRK do, as
+// the u256 low and high limbs of the two slots of the balance.
+//
+// Parameters:
+// - token: The address of the token
+// - owner: The address of the owner
+// - amount: The balance, in the base unit of the token
+// Returns:
+// - StateOverride: The override of the storage of the token
+// - error: An error if the amount is negative or does not fit in a u256
+func BalanceOverride(token, owner *felt.Felt, amount *big.Int) (StateOverride, error) {
+	if amount.Sign() < 0 || amount.BitLen() > 256 {
+		return StateOverride{}, fmt.Errorf("balance %s out of the u256 range", amount)
+	}
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	base := utils.StorageVarAddress(erc20BalancesVariable, owner)
+	return StateOverride{
+		ContractAddress: token,
+		Storage: []StorageEntry{
+			{Key: base, Value: new(felt.Felt).SetBigInt(new(big.Int).And(amount, mask))},
+			{Key: utils.StorageAddressOffset(base, 1), Value: new(felt.Felt).SetBigInt(new(big.Int).Rsh(amount, 128))},
+		},
+	}, nil
+}
+
+// CallWithStateOverrides calls a function as Call does, on the state of the
+// block with overrides, sent as an extra parameter of starknet_call. The
+// method is not part of the Starknet specification: nodes without support of
+// the overrides reject the parameter, and the overrides are never ignored.
+//
+// Parameters:
+// - ctx: The context.Context object for the function call
+// - request: The FunctionCall object representing the request
+// - blockID: The ID of the block whose state is overridden
+// - overrides: The overrides, a plain Call if empty
+// Returns:
+// - []*felt.Felt: The result of the function call
+// - error: ErrStateOverridesNotSupported if the node rejects the overrides, or an error if any
+func (provider *Provider) CallWithStateOverrides(ctx context.Context, request FunctionCall, blockID BlockID, overrides []StateOverride) ([]*felt.Felt, error) {
+	if len(overrides) == 0 {
+		return provider.Call(ctx, request, blockID)
+	}
+	if len(request.Calldata) == 0 {
+		request.Calldata = make([]*felt.Felt, 0)
+	}
+	var result []*felt.Felt
+	if err := do(ctx, provider.c, "starknet_call", &result, request, blockID, overrides); err != nil {
+		return nil, overridesErr(err, ErrContractNotFound, ErrBlockNotFound)
+	}
+	return result, nil
+}
+
+// SimulateTransactionsWithStateOverrides simulates transactions as
+// SimulateTransactions does, on the state of the block with overrides, sent
+// as an extra parameter of starknet_simulateTransactions. The method is not
+// part of the Starknet specification: nodes without support of the overrides
+// reject the parameter, and the overrides are never ignored.
+//
+// Parameters:
+// - ctx: The context.Context object for the function call
+// - blockID: The ID of the block whose state is overridden
+// - txns: The transactions
+// - simulationFlags: The simulation flags, nil for none
+// - overrides: The overrides, a plain SimulateTransactions if empty
+// Returns:
+// - []SimulatedTransaction: The simulations
+// - error: ErrStateOverridesNotSupported if the node rejects the overrides, or an error if any
+func (provider *Provider) SimulateTransactionsWithStateOverrides(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag, overrides []StateOverride) ([]SimulatedTransaction, error) {
+	if len(overrides) == 0 {
+		return provider.SimulateTransactions(ctx, blockID, txns, simulationFlags)
+	}
+	if simulationFlags == nil {
+		simulationFlags = []SimulationFlag{}
+	}
+	var output []SimulatedTransaction
+	if err := do(ctx, provider.c, "starknet_simulateTransactions", &output, blockID, txns, simulationFlags, overrides); err != nil {
+		return nil, overridesErr(err, ErrTxnExec, ErrBlockNotFound)
+	}
+	return output, nil
+}
+
+// overridesErr maps the rejection of the overrides parameter by the node to
+// ErrStateOverridesNotSupported, and the other errors as tryUnwrapToRPCErr.
+//
+// Parameters:
+// - err: The error of the node
+// - rpcErrors: The errors of the method
+// Returns:
+// - error: The mapped error
+func overridesErr(err error, rpcErrors ...*RPCError) error {
+	var nodeErr *RPCError
+	if errors.As(err, &nodeErr) && (nodeErr.code == InvalidParams || nodeErr.code == MethodNotFound) {
+		return fmt.Errorf("%w: %v", ErrStateOverridesNotSupported, err)
+	}
+	return tryUnwrapToRPCErr(err, rpcErrors...)
+}
@@ -0,0 +1,109 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// overridesClient answers the calls and the simulations with the number of
+// their parameters, rejecting the overrides when it does not support them.
+type overridesClient struct {
+	supported bool
+	args      []interface{}
+}
+
+// CallContext answers a call or a simulation.
+//
+// Parameters:
+// - ctx: the context
+// - result: the value the response is decoded into
+// - method: the method
+// - args: the arguments
+// Returns:
+// - error: an invalid params error for the overrides if they are not supported
+func (c *overridesClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	c.args = args
+	var response any = []*felt.Felt{new(felt.Felt).SetUint64(uint64(len(args)))}
+	if method == "starknet_simulateTransactions" {
+		response = []SimulatedTransaction{{FeeEstimate: FeeEstimate{OverallFee: new(felt.Felt).SetUint64(uint64(len(args)))}}}
+	}
+	if _, overridden := args[len(args)-1].([]StateOverride); overridden && !c.supported {
+		return Err(InvalidParams, "too many parameters")
+	}
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// Close does nothing.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (c *overridesClient) Close() {}
+
+// TestStateOverrides tests that the overrides are sent as an extra parameter,
+// that no overrides fall back to the plain methods, and that nodes rejecting
+// the overrides fail with ErrStateOverridesNotSupported.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestStateOverrides(t *testing.T) {
+	token, owner := new(felt.Felt).SetUint64(0x70), new(felt.Felt).SetUint64(0xa1)
+	override, err := BalanceOverride(token, owner, new(big.Int).Add(new(big.Int).Lsh(big.NewInt(3), 128), big.NewInt(4)))
+	if err != nil {
+		t.Fatalf("BalanceOverride should succeed, instead: %v", err)
+	}
+	base := utils.StorageVarAddress("ERC20_balances", owner)
+	if len(override.Storage) != 2 || !override.Storage[0].Key.Equal(base) || override.Storage[0].Value.Uint64() != 4 ||
+		!override.Storage[1].Key.Equal(utils.StorageAddressOffset(base, 1)) || override.Storage[1].Value.Uint64() != 3 {
+		t.Fatalf("unexpected balance override %v", override.Storage)
+	}
+	if _, err := BalanceOverride(token, owner, new(big.Int).Lsh(big.NewInt(1), 256)); err == nil {
+		t.Fatal("a balance above the u256 range should fail")
+	}
+	overrides := []StateOverride{override, {ContractAddress: owner, Nonce: new(felt.Felt).SetUint64(7)}}
+
+	client := &overridesClient{supported: true}
+	provider := NewProvider(client)
+	call := FunctionCall{ContractAddress: token, EntryPointSelector: utils.GetSelectorFromNameFelt("balanceOf")}
+	latest := WithBlockTag(BlockTagLatest)
+	result, err := provider.CallWithStateOverrides(context.Background(), call, latest, overrides)
+	if err != nil || result[0].Uint64() != 3 {
+		t.Fatalf("the overrides should be sent as a third parameter, got %v, %v", result, err)
+	}
+	if sent, ok := client.args[2].([]StateOverride); !ok || len(sent) != 2 {
+		t.Fatalf("unexpected overrides sent %v", client.args[2])
+	}
+	simulations, err := provider.SimulateTransactionsWithStateOverrides(context.Background(), latest, nil, nil, overrides)
+	if err != nil || simulations[0].FeeEstimate.OverallFee.Uint64() != 4 {
+		t.Fatalf("the overrides should be sent as a fourth parameter, got %v, %v", simulations, err)
+	}
+
+	client.supported = false
+	result, err = provider.CallWithStateOverrides(context.Background(), call, latest, nil)
+	if err != nil || result[0].Uint64() != 2 {
+		t.Fatalf("no overrides should be a plain call, got %v, %v", result, err)
+	}
+	if _, err := provider.CallWithStateOverrides(context.Background(), call, latest, overrides); !errors.Is(err, ErrStateOverridesNotSupported) {
+		t.Fatalf("expected ErrStateOverridesNotSupported, got %v", err)
+	}
+	if _, err := provider.SimulateTransactionsWithStateOverrides(context.Background(), latest, nil, nil, overrides); !errors.Is(err, ErrStateOverridesNotSupported) {
+		t.Fatalf("expected ErrStateOverridesNotSupported, got %v", err)
+	}
+}
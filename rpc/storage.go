@@ -0,0 +1,106 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// storageAtKeysConcurrency bounds the concurrent requests of StorageAtKeys
+// when the client does not support batches.
+const storageAtKeysConcurrency = 16
+
+// StorageAtKeys reads several storage keys of a contract. The reads are sent
+// in a single JSON-RPC batch when the client implements BatchCallCloser, and
+// as concurrent requests otherwise.
+//
+// Unlike StorageAt, the keys are storage addresses and are not hashed, e.g.
+// the address of a balance in a mapping.
+//
+// Parameters:
+// - ctx: The context.Context object for the function call
+// - contractAddress: The address of the contract
+// - keys: The storage addresses to read
+// - blockID: The ID of the block
+// Returns:
+// - map[felt.Felt]*felt.Felt: The values, by storage address
+// - error: An error if any of the reads fails
+func (provider *Provider) StorageAtKeys(ctx context.Context, contractAddress *felt.Felt, keys []*felt.Felt, blockID BlockID) (map[felt.Felt]*felt.Felt, error) {
+	values := make([]*felt.Felt, len(keys))
+	for i := range values {
+		values[i] = new(felt.Felt)
+	}
+
+	if batcher, ok := provider.c.(BatchCallCloser); ok {
+		batch := make([]BatchElem, len(keys))
+		for i, key := range keys {
+			batch[i] = BatchElem{Method: "starknet_getStorageAt", Args: []interface{}{contractAddress, key, blockID}, Result: values[i]}
+		}
+		if err := batcher.BatchCallContext(ctx, batch); err != nil {
+			return nil, err
+		}
+		for _, elem := range batch {
+			if elem.Error != nil {
+				return nil, tryUnwrapToRPCErr(elem.Error, ErrContractNotFound, ErrBlockNotFound)
+			}
+		}
+	} else if err := provider.storageAtKeysConcurrently(ctx, contractAddress, keys, blockID, values); err != nil {
+		return nil, err
+	}
+
+	result := make(map[felt.Felt]*felt.Felt, len(keys))
+	for i, key := range keys {
+		result[*key] = values[i]
+	}
+	return result, nil
+}
+
+// storageAtKeysConcurrently reads storage keys with concurrent requests.
+//
+// Parameters:
+// - ctx: The context.Context object for the function call
+// - contractAddress: The address of the contract
+// - keys: The storage addresses to read
+// - blockID: The ID of the block
+// - values: The values to decode the reads into, in the order of the keys
+// Returns:
+// - error: The first error of the reads
+func (provider *Provider) storageAtKeysConcurrently(ctx context.Context, contractAddress *felt.Felt, keys []*felt.Felt, blockID BlockID, values []*felt.Felt) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		sem      = make(chan struct{}, storageAtKeysConcurrency)
+	)
+	for i, key := range keys {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(key, value *felt.Felt) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := do(ctx, provider.c, "starknet_getStorageAt", value, contractAddress, key, blockID); err != nil {
+				once.Do(func() {
+					firstErr = tryUnwrapToRPCErr(err, ErrContractNotFound, ErrBlockNotFound)
+					cancel()
+				})
+			}
+		}(key, values[i])
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
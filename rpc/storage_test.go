@@ -0,0 +1,116 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// storageClient answers starknet_getStorageAt with the key plus one.
+type storageClient struct {
+	calls int32
+}
+
+// CallContext answers a storage read.
+//
+// Parameters:
+// - ctx: the context
+// - result: the value the response is decoded into
+// - method: the method
+// - args: the contract, the key and the block
+// Returns:
+// - error: an error if the key is zero
+func (c *storageClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	atomic.AddInt32(&c.calls, 1)
+	key := args[1].(*felt.Felt)
+	if key.IsZero() {
+		return fmt.Errorf("no storage at %s", key)
+	}
+	value := new(felt.Felt).Add(key, new(felt.Felt).SetUint64(1))
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, result)
+}
+
+// Close does nothing.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (c *storageClient) Close() {}
+
+// batchStorageClient answers storage reads in batches.
+type batchStorageClient struct {
+	storageClient
+	batches int
+}
+
+// BatchCallContext answers each request of the batch.
+//
+// Parameters:
+// - ctx: the context
+// - b: the batch
+// Returns:
+// - error: always nil
+func (c *batchStorageClient) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	c.batches++
+	for i := range b {
+		var raw json.RawMessage
+		if b[i].Error = c.storageClient.CallContext(ctx, &raw, b[i].Method, b[i].Args...); b[i].Error == nil {
+			b[i].Error = json.Unmarshal(raw, b[i].Result)
+		}
+	}
+	return nil
+}
+
+// TestStorageAtKeys tests batched and concurrent storage reads.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestStorageAtKeys(t *testing.T) {
+	contract := new(felt.Felt).SetUint64(0xc0de)
+	keys := make([]*felt.Felt, 40)
+	for i := range keys {
+		keys[i] = new(felt.Felt).SetUint64(uint64(i + 1))
+	}
+
+	batched := &batchStorageClient{}
+	concurrent := &storageClient{}
+	for _, client := range []CallCloser{batched, concurrent} {
+		values, err := NewProvider(client).StorageAtKeys(context.Background(), contract, keys, WithBlockTag("latest"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != len(keys) {
+			t.Fatalf("expected %d values, got %d", len(keys), len(values))
+		}
+		for i, key := range keys {
+			if values[*key].Uint64() != uint64(i+2) {
+				t.Fatalf("unexpected value %s at key %s", values[*key], key)
+			}
+		}
+	}
+	if batched.batches != 1 || concurrent.calls != int32(len(keys)) {
+		t.Fatalf("expected a single batch and %d calls, got %d and %d", len(keys), batched.batches, concurrent.calls)
+	}
+
+	keys[7] = new(felt.Felt)
+	for _, client := range []CallCloser{&batchStorageClient{}, &storageClient{}} {
+		if _, err := NewProvider(client).StorageAtKeys(context.Background(), contract, keys, WithBlockTag("latest")); err == nil {
+			t.Fatal("expected the failed read to fail the call")
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/lifecycle"
+)
+
+// DefaultPollInterval is the interval providers poll the node at when their
+// client does not support subscriptions.
+const DefaultPollInterval = 2 * time.Second
+
+// TxnStatusUpdate is a status of a transaction delivered by
+// SubscribeTransactionStatus.
+type TxnStatusUpdate struct {
+	TxnStatusResp
+	// Err is the error ending the watch, set on the last update only
+	Err error
+}
+
+// Terminal reports whether the status is final: accepted on L1 or rejected.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if the status will not change anymore
+func (s TxnStatusResp) Terminal() bool {
+	return s.FinalityStatus == TxnStatus_Accepted_On_L1 || s.FinalityStatus == TxnStatus_Rejected
+}
+
+// SubscribeTransactionStatus watches the status of a transaction and delivers
+// its transitions on a channel, closed after a terminal status, an error, or
+// when ctx is done. The watch subscribes to starknet_subscribeTransactionStatus
+// when the client of the provider is a SubscriptionCallCloser, and polls
+// GetTransactionStatus every poll interval otherwise. A transaction unknown
+// to the node is waited for.
+//
+// Parameters:
+// - ctx: The context.Context object, cancelling the watch
+// - transactionHash: The hash of the transaction
+// Returns:
+// - <-chan TxnStatusUpdate: The status transitions, the last one carrying the error ending the watch if any
+// - error: an error if the subscription fails
+func (provider *Provider) SubscribeTransactionStatus(ctx context.Context, transactionHash *felt.Felt) (<-chan TxnStatusUpdate, error) {
+	updates := make(chan TxnStatusUpdate)
+	client, ok := provider.c.(SubscriptionCallCloser)
+	if !ok {
+		go provider.pollTransactionStatus(ctx, transactionHash, updates)
+		return updates, nil
+	}
+
+	notifications := make(chan json.RawMessage)
+	sub, err := client.Subscribe(ctx, notifications, "starknet_subscribeTransactionStatus", transactionHash)
+	if err != nil {
+		return nil, tryUnwrapToRPCErr(err)
+	}
+	go func() {
+		defer close(updates)
+		defer sub.Unsubscribe()
+		var last TxnStatusResp
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-sub.Err():
+				if err != nil {
+					send(ctx, updates, TxnStatusUpdate{Err: err})
+				}
+				return
+			case raw := <-notifications:
+				var notification struct {
+					Status TxnStatusResp `json:"status"`
+				}
+				if err := json.Unmarshal(raw, &notification); err != nil {
+					send(ctx, updates, TxnStatusUpdate{Err: err})
+					return
+				}
+				if transition(ctx, updates, &last, notification.Status) {
+					return
+				}
+			}
+		}
+	}()
+	return updates, nil
+}
+
+// pollTransactionStatus polls the status of a transaction and delivers its
+// transitions, then closes updates.
+//
+// Parameters:
+// - ctx: The context.Context object, cancelling the watch
+// - transactionHash: The hash of the transaction
+// - updates: The channel the transitions are delivered on
+// Returns:
+//
+//	none
+func (provider *Provider) pollTransactionStatus(ctx context.Context, transactionHash *felt.Felt, updates chan<- TxnStatusUpdate) {
+	defer close(updates)
+	t := time.NewTicker(provider.pollInterval)
+	defer t.Stop()
+	var last TxnStatusResp
+	for {
+		status, err := provider.GetTransactionStatus(ctx, transactionHash)
+		switch {
+		case err == nil:
+			if transition(ctx, updates, &last, *status) {
+				return
+			}
+		case errors.Is(err, ErrHashNotFound):
+			// not received by the node yet
+		default:
+			if ctx.Err() == nil {
+				send(ctx, updates, TxnStatusUpdate{Err: err})
+			}
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// transition delivers a status if it differs from the last one delivered.
+//
+// Parameters:
+// - ctx: The context.Context object, cancelling the delivery
+// - updates: The channel the status is delivered on
+// - last: The last status delivered, updated with the status
+// - status: The status
+// Returns:
+// - bool: true if the watch is over, on a terminal status or when ctx is done
+func transition(ctx context.Context, updates chan<- TxnStatusUpdate, last *TxnStatusResp, status TxnStatusResp) bool {
+	if status == *last {
+		return false
+	}
+	*last = status
+	return !send(ctx, updates, TxnStatusUpdate{TxnStatusResp: status}) || status.Terminal()
+}
+
+// send delivers an update unless ctx is done first.
+//
+// Parameters:
+// - ctx: The context.Context object, cancelling the delivery
+// - updates: The channel the update is delivered on
+// - update: The update
+// Returns:
+// - bool: true if the update was delivered
+func send(ctx context.Context, updates chan<- TxnStatusUpdate, update TxnStatusUpdate) bool {
+	select {
+	case updates <- update:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// TxnStatusHandler handles a status of a transaction watched by a
+// TxnStatusWatcher.
+type TxnStatusHandler func(ctx context.Context, transactionHash *felt.Felt, update TxnStatusUpdate) error
+
+// TxnStatusWatcher runs a handler on the status transitions of the
+// transactions it watches, as SubscribeTransactionStatus delivers them, as a
+// lifecycle.Lifecycle component: Stop stops the watches and waits for the
+// handlers of the updates in flight.
+type TxnStatusWatcher struct {
+	lifecycle.Base
+	provider *Provider
+	handler  TxnStatusHandler
+	options  listenerOptions
+}
+
+var _ lifecycle.Lifecycle = &TxnStatusWatcher{}
+
+// NewTxnStatusWatcher creates a watcher running a handler on the status
+// transitions of the transactions it watches.
+//
+// Parameters:
+// - handler: The handler of the statuses
+// - opts: The options
+// Returns:
+// - *TxnStatusWatcher: the watcher
+func (provider *Provider) NewTxnStatusWatcher(handler TxnStatusHandler, opts ...ListenerOption) *TxnStatusWatcher {
+	w := &TxnStatusWatcher{provider: provider, handler: handler}
+	for _, opt := range opts {
+		opt(&w.options)
+	}
+	w.SetErrorHandler(w.options.onError)
+	return w
+}
+
+// Start starts the watcher.
+//
+// Parameters:
+// - ctx: The context.Context object, unused
+// Returns:
+// - error: lifecycle.ErrAlreadyStarted or lifecycle.ErrStopped
+func (w *TxnStatusWatcher) Start(ctx context.Context) error {
+	_, err := w.Begin()
+	return err
+}
+
+// Stop stops the watches and waits for the handlers of the updates in flight.
+//
+// Parameters:
+// - ctx: The context.Context object, bounding the drain
+// Returns:
+// - error: the error of the context if the drain timed out
+func (w *TxnStatusWatcher) Stop(ctx context.Context) error {
+	return w.Shutdown(ctx, nil)
+}
+
+// Watch watches the status of a transaction until a terminal status or an
+// error, passed to the handler as the Err of the last update. An update whose
+// handler fails or panics is reported to the error handler.
+//
+// Parameters:
+// - transactionHash: The hash of the transaction
+// Returns:
+// - error: lifecycle.ErrNotStarted or lifecycle.ErrStopped if the watcher is not running
+func (w *TxnStatusWatcher) Watch(transactionHash *felt.Felt) error {
+	started := w.Go(func(ctx context.Context) {
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		handle := func(update TxnStatusUpdate) {
+			_ = lifecycle.SafeCall(w.options.onError, func() error {
+				return w.handler(ctx, transactionHash, update)
+			})
+		}
+		updates, err := w.provider.SubscribeTransactionStatus(watchCtx, transactionHash)
+		if err != nil {
+			handle(TxnStatusUpdate{Err: err})
+			return
+		}
+		stopping := w.Stopping()
+		for {
+			select {
+			case <-stopping:
+				return
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+				handle(update)
+			}
+		}
+	})
+	if started {
+		return nil
+	}
+	if w.State() == lifecycle.StateIdle {
+		return lifecycle.ErrNotStarted
+	}
+	return lifecycle.ErrStopped
+}
@@ -0,0 +1,258 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/lifecycle"
+)
+
+// statusClient answers starknet_getTransactionStatus with a sequence of
+// statuses, repeating the last one.
+type statusClient struct {
+	statuses []string
+	calls    int
+}
+
+// CallContext answers a status read.
+//
+// Parameters:
+// - ctx: the context
+// - result: the value the response is decoded into
+// - method: the method
+// - args: the transaction hash
+// Returns:
+// - error: ErrHashNotFound for an empty status
+func (c *statusClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	status := c.statuses[len(c.statuses)-1]
+	if c.calls < len(c.statuses) {
+		status = c.statuses[c.calls]
+	}
+	c.calls++
+	if status == "" {
+		return ErrHashNotFound
+	}
+	return json.Unmarshal([]byte(status), result)
+}
+
+// Close does nothing.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (c *statusClient) Close() {}
+
+// subscription is a Subscription ended by errs.
+type subscription struct {
+	errs         chan error
+	unsubscribed bool
+}
+
+// Err returns the channel ending the subscription.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - <-chan error: the channel
+func (s *subscription) Err() <-chan error { return s.errs }
+
+// Unsubscribe records the end of the subscription.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+//
+//	none
+func (s *subscription) Unsubscribe() { s.unsubscribed = true }
+
+// subscriptionClient is a SubscriptionCallCloser sending notifications.
+type subscriptionClient struct {
+	statusClient
+	notifications []string
+	sub           *subscription
+	method        string
+}
+
+// Subscribe sends the notifications, then ends the subscription with an error.
+//
+// Parameters:
+// - ctx: the context
+// - ch: the channel receiving the notifications
+// - method: the subscription method
+// - args: the transaction hash
+// Returns:
+// - Subscription: the subscription
+// - error: always nil
+func (c *subscriptionClient) Subscribe(ctx context.Context, ch chan<- json.RawMessage, method string, args ...interface{}) (Subscription, error) {
+	c.method = method
+	c.sub = &subscription{errs: make(chan error, 1)}
+	go func() {
+		for _, notification := range c.notifications {
+			ch <- json.RawMessage(notification)
+		}
+		c.sub.errs <- errors.New("connection closed")
+	}()
+	return c.sub, nil
+}
+
+// collect reads the updates until the channel is closed.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures
+// - updates: The channel of updates
+// Returns:
+// - []TxnStatusUpdate: the updates
+func collect(t *testing.T, updates <-chan TxnStatusUpdate) []TxnStatusUpdate {
+	var all []TxnStatusUpdate
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return all
+			}
+			all = append(all, update)
+		case <-timeout:
+			t.Fatal("the updates were not closed")
+		}
+	}
+}
+
+// TestSubscribeTransactionStatus tests the status transitions delivered by
+// polling and by subscription.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestSubscribeTransactionStatus(t *testing.T) {
+	hash := new(felt.Felt).SetUint64(1)
+	client := &statusClient{statuses: []string{
+		"",
+		`{"finality_status": "RECEIVED"}`,
+		`{"finality_status": "RECEIVED"}`,
+		`{"finality_status": "ACCEPTED_ON_L2", "execution_status": "SUCCEEDED"}`,
+		`{"finality_status": "ACCEPTED_ON_L1", "execution_status": "SUCCEEDED"}`,
+	}}
+	provider := NewProvider(client, WithPollInterval(time.Millisecond))
+	updates, err := provider.SubscribeTransactionStatus(context.Background(), hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := collect(t, updates)
+	if len(all) != 3 || all[0].FinalityStatus != TxnStatus_Received || all[2].FinalityStatus != TxnStatus_Accepted_On_L1 || all[2].Err != nil {
+		t.Fatalf("unexpected updates %+v", all)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider = NewProvider(&statusClient{statuses: []string{""}}, WithPollInterval(time.Millisecond))
+	updates, err = provider.SubscribeTransactionStatus(ctx, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if all := collect(t, updates); len(all) != 0 {
+		t.Fatalf("unexpected updates %+v", all)
+	}
+
+	sc := &subscriptionClient{notifications: []string{
+		`{"transaction_hash": "0x1", "status": {"finality_status": "RECEIVED"}}`,
+		`{"transaction_hash": "0x1", "status": {"finality_status": "REJECTED", "failure_reason": "invalid nonce"}}`,
+	}}
+	updates, err = NewProvider(sc).SubscribeTransactionStatus(context.Background(), hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all = collect(t, updates)
+	if len(all) != 2 || !all[1].Terminal() || all[1].FailureReason != "invalid nonce" {
+		t.Fatalf("unexpected updates %+v", all)
+	}
+	if sc.method != "starknet_subscribeTransactionStatus" || !sc.sub.unsubscribed {
+		t.Fatalf("unexpected subscription %s", sc.method)
+	}
+
+	sc = &subscriptionClient{notifications: []string{`{"transaction_hash": "0x1", "status": {"finality_status": "RECEIVED"}}`}}
+	updates, err = NewProvider(sc).SubscribeTransactionStatus(context.Background(), hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all = collect(t, updates)
+	if len(all) != 2 || all[1].Err == nil {
+		t.Fatalf("expected the error ending the subscription, got %+v", all)
+	}
+}
+
+// TestTxnStatusWatcher tests that a TxnStatusWatcher runs its handler on the
+// transitions of the transactions it watches once started, going on after a
+// panic of the handler, and stops them when stopped.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestTxnStatusWatcher(t *testing.T) {
+	client := &statusClient{statuses: []string{
+		"",
+		`{"finality_status": "RECEIVED"}`,
+		`{"finality_status": "ACCEPTED_ON_L1", "execution_status": "SUCCEEDED"}`,
+	}}
+	provider := NewProvider(client, WithPollInterval(time.Millisecond))
+	hash := new(felt.Felt).SetUint64(1)
+	updates := make(chan TxnStatusUpdate, 2)
+	errs := make(chan error, 1)
+	watcher := provider.NewTxnStatusWatcher(func(ctx context.Context, transactionHash *felt.Felt, update TxnStatusUpdate) error {
+		if !transactionHash.Equal(hash) {
+			t.Errorf("unexpected transaction %s", transactionHash)
+		}
+		updates <- update
+		if update.FinalityStatus == TxnStatus_Received {
+			panic("bad handler")
+		}
+		return nil
+	}, WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+	if err := watcher.Watch(hash); !errors.Is(err, lifecycle.ErrNotStarted) {
+		t.Fatalf("expected ErrNotStarted, got %v", err)
+	}
+	if err := watcher.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := watcher.Watch(hash); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []TxnStatus{TxnStatus_Received, TxnStatus_Accepted_On_L1} {
+		select {
+		case update := <-updates:
+			if update.FinalityStatus != expected || update.Err != nil {
+				t.Fatalf("expected the status %s, got %+v", expected, update)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("the status %s was not handled", expected)
+		}
+	}
+	var panicErr *lifecycle.PanicError
+	if err := <-errs; !errors.As(err, &panicErr) {
+		t.Fatalf("expected the panic of the handler, got %v", err)
+	}
+	if err := watcher.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := watcher.Watch(hash); !errors.Is(err, lifecycle.ErrStopped) {
+		t.Fatalf("expected ErrStopped, got %v", err)
+	}
+}
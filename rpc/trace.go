@@ -27,39 +27,56 @@ func (provider *Provider) TraceTransaction(ctx context.Context, transactionHash
 	if err != nil {
 		return nil, err
 	}
+	return unmarshalTxnTrace(rawTraceByte)
+}
+
+// unmarshalTxnTrace decodes a transaction trace into the trace type of its transaction type.
+//
+// Parameters:
+//   - data: the JSON trace
+//
+// Returns:
+//   - TxnTrace: the transaction trace
+//   - error: an error if the trace can not be decoded
+func unmarshalTxnTrace(data []byte) (TxnTrace, error) {
+	var header struct {
+		Type TransactionType `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
 
-	switch rawTxnTrace["type"] {
-	case string(TransactionType_Invoke):
+	switch header.Type {
+	case TransactionType_Invoke:
 		var trace InvokeTxnTrace
-		err = json.Unmarshal(rawTraceByte, &trace)
+		err := json.Unmarshal(data, &trace)
 		if err != nil {
 			return nil, err
 		}
 		return trace, nil
-	case string(TransactionType_Declare):
+	case TransactionType_Declare:
 		var trace DeclareTxnTrace
-		err = json.Unmarshal(rawTraceByte, &trace)
+		err := json.Unmarshal(data, &trace)
 		if err != nil {
 			return nil, err
 		}
 		return trace, nil
-	case string(TransactionType_DeployAccount):
+	case TransactionType_DeployAccount:
 		var trace DeployAccountTxnTrace
-		err = json.Unmarshal(rawTraceByte, &trace)
+		err := json.Unmarshal(data, &trace)
 		if err != nil {
 			return nil, err
 		}
 		return trace, nil
-	case string(TransactionType_L1Handler):
+	case TransactionType_L1Handler:
 		var trace L1HandlerTxnTrace
-		err = json.Unmarshal(rawTraceByte, &trace)
+		err := json.Unmarshal(data, &trace)
 		if err != nil {
 			return nil, err
 		}
 		return trace, nil
 	}
 	return nil, errors.New("Unknown transaction type")
-
 }
 
 // TraceBlockTransactions retrieves the traces of transactions in a given block.
@@ -83,7 +100,12 @@ func (provider *Provider) TraceBlockTransactions(ctx context.Context, blockID Bl
 // Simulate a given sequence of transactions on the requested state, and generate the execution traces.
 // Note that some of the transactions may revert, in which case no error is thrown, but revert details can be seen on the returned trace object.
 // Note that some of the transactions may revert, this will be reflected by the revert_error property in the trace. Other types of failures (e.g. unexpected error or failure in the validation phase) will result in TRANSACTION_EXECUTION_ERROR.
+// SKIP_VALIDATE simulates transactions of accounts not deployed yet or with placeholder signatures, SKIP_FEE_CHARGE
+// transactions of accounts without funds. Nil flags are sent as no flags.
 func (provider *Provider) SimulateTransactions(ctx context.Context, blockID BlockID, txns []Transaction, simulationFlags []SimulationFlag) ([]SimulatedTransaction, error) {
+	if simulationFlags == nil {
+		simulationFlags = []SimulationFlag{}
+	}
 
 	var output []SimulatedTransaction
 	if err := do(ctx, provider.c, "starknet_simulateTransactions", &output, blockID, txns, simulationFlags); err != nil {
@@ -0,0 +1,191 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// TracePhase is the part of a transaction an invocation runs in.
+type TracePhase string
+
+const (
+	TracePhaseValidate    TracePhase = "validate"
+	TracePhaseExecute     TracePhase = "execute"
+	TracePhaseConstructor TracePhase = "constructor"
+	TracePhaseFeeTransfer TracePhase = "fee_transfer"
+	TracePhaseL1Handler   TracePhase = "l1_handler"
+)
+
+// TraceCall is an invocation of a transaction trace, without its nested calls.
+type TraceCall struct {
+	Phase TracePhase
+	// Depth is 0 for the root invocation of the phase
+	Depth          int
+	Caller         *felt.Felt
+	Contract       *felt.Felt
+	Selector       *felt.Felt
+	ClassHash      *felt.Felt
+	CallType       CallType
+	EntryPointType EntryPointType
+	Calldata       []*felt.Felt
+	Result         []*felt.Felt
+	// RevertReason is set on the root execute invocation of a reverted transaction
+	RevertReason string
+}
+
+// FlattenTrace lists the invocations of a transaction trace, phase by phase,
+// each invocation followed by its nested calls.
+//
+// Parameters:
+// - trace: the trace, as returned by TraceTransaction, TraceBlockTransactions or SimulateTransactions
+// Returns:
+// - []TraceCall: the invocations, in execution order
+// - error: an error if the trace type is unknown
+func FlattenTrace(trace TxnTrace) ([]TraceCall, error) {
+	trace, err := normalizeTxnTrace(trace)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := []TraceCall{}
+	add := func(phase TracePhase, invocation *FnInvocation) {
+		if invocation != nil && invocation.ContractAddress != nil {
+			calls = flattenInvocation(calls, phase, 0, invocation)
+		}
+	}
+	switch trace := trace.(type) {
+	case InvokeTxnTrace:
+		add(TracePhaseValidate, &trace.ValidateInvocation)
+		if trace.ExecuteInvocation.RevertReason != "" {
+			calls = append(calls, TraceCall{Phase: TracePhaseExecute, RevertReason: trace.ExecuteInvocation.RevertReason})
+		} else {
+			add(TracePhaseExecute, &trace.ExecuteInvocation.FunctionInvocation)
+		}
+		add(TracePhaseFeeTransfer, &trace.FeeTransferInvocation)
+	case DeclareTxnTrace:
+		add(TracePhaseValidate, &trace.ValidateInvocation)
+		add(TracePhaseFeeTransfer, &trace.FeeTransferInvocation)
+	case DeployAccountTxnTrace:
+		add(TracePhaseValidate, &trace.ValidateInvocation)
+		add(TracePhaseConstructor, &trace.ConstructorInvocation)
+		add(TracePhaseFeeTransfer, &trace.FeeTransferInvocation)
+	case L1HandlerTxnTrace:
+		add(TracePhaseL1Handler, &trace.FunctionInvocation)
+	}
+	return calls, nil
+}
+
+// flattenInvocation appends an invocation and its nested calls.
+//
+// Parameters:
+// - calls: the invocations listed so far
+// - phase: the phase of the invocation
+// - depth: the depth of the invocation
+// - invocation: the invocation
+// Returns:
+// - []TraceCall: the invocations with the invocation and its nested calls appended
+func flattenInvocation(calls []TraceCall, phase TracePhase, depth int, invocation *FnInvocation) []TraceCall {
+	calls = append(calls, TraceCall{
+		Phase:          phase,
+		Depth:          depth,
+		Caller:         invocation.CallerAddress,
+		Contract:       invocation.ContractAddress,
+		Selector:       invocation.EntryPointSelector,
+		ClassHash:      invocation.ClassHash,
+		CallType:       invocation.CallType,
+		EntryPointType: invocation.EntryPointType,
+		Calldata:       invocation.Calldata,
+		Result:         invocation.Result,
+	})
+	for i := range invocation.NestedCalls {
+		calls = flattenInvocation(calls, phase, depth+1, &invocation.NestedCalls[i])
+	}
+	return calls
+}
+
+// RenderTrace writes the call tree of a transaction trace, one invocation per
+// line, indented by depth. Selectors found in names, keyed by selector, are
+// printed by name.
+//
+// Parameters:
+// - w: the writer
+// - trace: the trace
+// - names: the entrypoint names by selector, may be nil
+// Returns:
+// - error: an error if the trace type is unknown or the writer fails
+func RenderTrace(w io.Writer, trace TxnTrace, names map[felt.Felt]string) error {
+	calls, err := FlattenTrace(trace)
+	if err != nil {
+		return err
+	}
+	var phase TracePhase
+	for _, call := range calls {
+		if call.Phase != phase {
+			phase = call.Phase
+			if _, err := fmt.Fprintf(w, "%s:\n", phase); err != nil {
+				return err
+			}
+		}
+		indent := strings.Repeat("  ", call.Depth+1)
+		if call.Contract == nil {
+			if _, err := fmt.Fprintf(w, "%sREVERTED: %s\n", indent, call.RevertReason); err != nil {
+				return err
+			}
+			continue
+		}
+		selector := call.Selector.String()
+		if name, ok := names[*call.Selector]; ok {
+			selector = name
+		}
+		if _, err := fmt.Fprintf(w, "%s%s.%s(%s) -> [%s]\n", indent, call.Contract, selector, joinFelts(call.Calldata), joinFelts(call.Result)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinFelts formats felts as a comma separated list.
+//
+// Parameters:
+// - felts: the felts
+// Returns:
+// - string: the list
+func joinFelts(felts []*felt.Felt) string {
+	strs := make([]string, len(felts))
+	for i, f := range felts {
+		strs[i] = f.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+// normalizeTxnTrace converts a trace left undecoded, e.g. by SimulateTransactions,
+// or given by pointer into the trace type of its transaction type.
+//
+// Parameters:
+// - trace: the trace
+// Returns:
+// - TxnTrace: the trace type
+// - error: an error if the trace type is unknown
+func normalizeTxnTrace(trace TxnTrace) (TxnTrace, error) {
+	switch trace := trace.(type) {
+	case InvokeTxnTrace, DeclareTxnTrace, DeployAccountTxnTrace, L1HandlerTxnTrace:
+		return trace, nil
+	case *InvokeTxnTrace:
+		return *trace, nil
+	case *DeclareTxnTrace:
+		return *trace, nil
+	case *DeployAccountTxnTrace:
+		return *trace, nil
+	case *L1HandlerTxnTrace:
+		return *trace, nil
+	}
+	data, err := json.Marshal(trace)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalTxnTrace(data)
+}
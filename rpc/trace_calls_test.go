@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// TestFlattenTrace tests that the call tree of a block trace is flattened in
+// execution order and rendered with entrypoint names.
+//
+// Parameters:
+// - t: The testing.T object used for reporting test failures and logging.
+// Returns:
+//
+//	none
+func TestFlattenTrace(t *testing.T) {
+	content, err := os.ReadFile("./tests/trace/0x3ddc3a8aaac071ecdc5d8d0cfbb1dc4fc6a88272bc6c67523c9baaee52a5ea2.json")
+	if err != nil {
+		t.Fatal("should be able to read file", err)
+	}
+	var response struct {
+		Result []struct {
+			TraceRoot InvokeTxnTrace `json:"trace_root"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(content, &response); err != nil {
+		t.Fatal("should be able to unmarshal trace", err)
+	}
+
+	calls, err := FlattenTrace(response.Result[0].TraceRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	phases := map[TracePhase]int{}
+	for _, call := range calls {
+		phases[call.Phase]++
+	}
+	if phases[TracePhaseValidate] != 2 || phases[TracePhaseExecute] != 8 || phases[TracePhaseFeeTransfer] != 2 {
+		t.Fatalf("unexpected calls per phase %v", phases)
+	}
+	execute := calls[phases[TracePhaseValidate]]
+	if execute.Depth != 0 || execute.Contract.String() != "0x4d1085d194e2f228ea8bfd3cf341217784dc6cf28dba2319d71264b4e7c94c7" || len(execute.Result) != 2 {
+		t.Fatalf("unexpected root execute invocation %+v", execute)
+	}
+	if calls[phases[TracePhaseValidate]+1].Depth != 1 {
+		t.Fatalf("expected the nested call at depth 1, got %d", calls[phases[TracePhaseValidate]+1].Depth)
+	}
+
+	var out bytes.Buffer
+	names := map[felt.Felt]string{*execute.Selector: "__execute__"}
+	if err := RenderTrace(&out, response.Result[0].TraceRoot, names); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "execute:\n  0x4d1085d194e2f228ea8bfd3cf341217784dc6cf28dba2319d71264b4e7c94c7.__execute__(") {
+		t.Fatalf("unexpected rendering\n%s", out.String())
+	}
+
+	reverted := map[string]any{"type": "INVOKE", "execute_invocation": map[string]any{"revert_reason": "out of gas"}}
+	calls, err = FlattenTrace(reverted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 || calls[0].RevertReason != "out of gas" {
+		t.Fatalf("unexpected calls of reverted trace %+v", calls)
+	}
+}
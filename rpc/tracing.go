@@ -0,0 +1,120 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the OpenTelemetry tracer of the package.
+const TracerName = "github.com/xiang-xx/starknet.go/rpc"
+
+// Attributes of the spans of the requests.
+const (
+	AttributeRPCSystem       = attribute.Key("rpc.system")
+	AttributeRPCMethod       = attribute.Key("rpc.method")
+	AttributeRPCErrorCode    = attribute.Key("rpc.jsonrpc.error_code")
+	AttributeBlockID         = attribute.Key("starknet.block_id")
+	AttributeTransactionHash = attribute.Key("starknet.transaction_hash")
+)
+
+// transactionHashMethods are the methods whose first parameter is a transaction hash.
+var transactionHashMethods = map[string]bool{
+	"starknet_getTransactionByHash":  true,
+	"starknet_getTransactionReceipt": true,
+	"starknet_getTransactionStatus":  true,
+	"starknet_traceTransaction":      true,
+}
+
+// WithTracerProvider records an OpenTelemetry span for each request of the
+// HTTP client, carrying the method, the block ID and the transaction hash.
+//
+// Parameters:
+// - tp: the tracer provider
+// Returns:
+// - HTTPOption: the option
+func WithTracerProvider(tp trace.TracerProvider) HTTPOption {
+	return WithInterceptor(TracingInterceptor(tp))
+}
+
+// TracingInterceptor returns an interceptor recording an OpenTelemetry span
+// for each request, to instrument any client with Intercept.
+//
+// Parameters:
+// - tp: the tracer provider
+// Returns:
+// - Interceptor: the interceptor
+func TracingInterceptor(tp trace.TracerProvider) Interceptor {
+	tracer := tp.Tracer(TracerName)
+	return func(ctx context.Context, method string, params []interface{}, next Invoker) (json.RawMessage, error) {
+		ctx, span := tracer.Start(ctx, method, trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(AttributeRPCSystem.String("jsonrpc"), AttributeRPCMethod.String(method)))
+		defer span.End()
+		for _, param := range params {
+			if blockID, ok := param.(BlockID); ok {
+				span.SetAttributes(AttributeBlockID.String(blockIDString(blockID)))
+			}
+		}
+		if hash, ok := firstFelt(params); ok && transactionHashMethods[method] {
+			span.SetAttributes(AttributeTransactionHash.String(hash.String()))
+		}
+
+		raw, err := next(ctx, method, params)
+		if err != nil {
+			var rpcErr *RPCError
+			if errors.As(err, &rpcErr) {
+				span.SetAttributes(AttributeRPCErrorCode.Int(rpcErr.Code()))
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return raw, err
+		}
+		if strings.HasPrefix(method, "starknet_add") {
+			var resp struct {
+				TransactionHash *felt.Felt `json:"transaction_hash"`
+			}
+			if json.Unmarshal(raw, &resp) == nil && resp.TransactionHash != nil {
+				span.SetAttributes(AttributeTransactionHash.String(resp.TransactionHash.String()))
+			}
+		}
+		return raw, nil
+	}
+}
+
+// blockIDString returns the block number, hash or tag of a block ID.
+//
+// Parameters:
+// - blockID: the block ID
+// Returns:
+// - string: the number, hash or tag
+func blockIDString(blockID BlockID) string {
+	switch {
+	case blockID.Number != nil:
+		return strconv.FormatUint(*blockID.Number, 10)
+	case blockID.Hash != nil:
+		return blockID.Hash.String()
+	}
+	return blockID.Tag
+}
+
+// firstFelt returns the first parameter if it is a felt.
+//
+// Parameters:
+// - params: the parameters
+// Returns:
+// - *felt.Felt: the felt
+// - bool: true if the first parameter is a felt
+func firstFelt(params []interface{}) (*felt.Felt, bool) {
+	if len(params) == 0 {
+		return nil, false
+	}
+	f, ok := params[0].(*felt.Felt)
+	return f, ok && f != nil
+}
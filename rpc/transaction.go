@@ -106,15 +106,34 @@ func (provider *Provider) TransactionByBlockIdAndIndex(ctx context.Context, bloc
 // - ctx: the context.Context object for the request
 // - transactionHash: the hash of the transaction as a Felt
 // Returns:
-// - TransactionReceipt: the transaction receipt
+// - *Receipt: the transaction receipt
 // - error: an error if any
-func (provider *Provider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (TransactionReceipt, error) {
-	var receipt UnknownTransactionReceipt
+func (provider *Provider) TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*Receipt, error) {
+	var receipt Receipt
 	err := do(ctx, provider.c, "starknet_getTransactionReceipt", &receipt, transactionHash)
 	if err != nil {
 		return nil, tryUnwrapToRPCErr(err,ErrHashNotFound)
 	}
-	return receipt.TransactionReceipt, nil
+	return &receipt, nil
+}
+
+// MessagesStatus gets the status of the l1_handler transactions executing the
+// L1 to L2 messages sent by an L1 transaction, in the order of the messages,
+// so that bridges can follow a deposit from the L1 transaction to its
+// consumption on L2.
+//
+// Parameters:
+// - ctx: the context.Context object for cancellation and timeouts.
+// - l1TransactionHash: the hash of the L1 transaction sending the messages
+// Returns:
+// - []MessageStatus: the status of the l1_handler transaction of each message
+// - error: ErrHashNotFound if the L1 transaction is unknown, or another error
+func (provider *Provider) MessagesStatus(ctx context.Context, l1TransactionHash NumAsHex) ([]MessageStatus, error) {
+	var statuses []MessageStatus
+	if err := do(ctx, provider.c, "starknet_getMessagesStatus", &statuses, l1TransactionHash); err != nil {
+		return nil, tryUnwrapToRPCErr(err, ErrHashNotFound)
+	}
+	return statuses, nil
 }
 
 // GetTransactionStatus gets the transaction status (possibly reflecting that the tx is still in the mempool, or dropped from it)
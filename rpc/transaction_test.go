@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMessagesStatus tests that the l1_handler transactions of the messages
+// of an L1 transaction are followed from their status to the message hash of
+// their receipt.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestMessagesStatus(t *testing.T) {
+	const l1Hash = "0x4a0e5a3d0c5d5c2d6f1e1b3e9d0b2f7a1c8e6d3b2a190807060504030201aabb"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonrpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		raw, _ := json.Marshal(req.Params)
+		switch {
+		case req.Method == "starknet_getMessagesStatus" && string(raw) == fmt.Sprintf(`[%q]`, l1Hash):
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": [{"transaction_hash": "0x3", "finality_status": "ACCEPTED_ON_L2", "execution_status": "SUCCEEDED"}, {"transaction_hash": "0x4", "finality_status": "REJECTED", "failure_reason": "invalid payload"}]}`, req.ID)
+		case req.Method == "starknet_getMessagesStatus":
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "error": {"code": 29, "message": "Transaction hash not found"}}`, req.ID)
+		case req.Method == "starknet_getTransactionReceipt":
+			fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": %d, "result": {"type": "L1_HANDLER", "transaction_hash": "0x3", "message_hash": "0x%s", "actual_fee": {"amount": "0x0", "unit": "WEI"}, "execution_status": "SUCCEEDED", "finality_status": "ACCEPTED_ON_L2", "block_hash": "0xb", "block_number": 7, "messages_sent": [], "events": []}}`, req.ID, l1Hash[2:])
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+	}))
+	defer server.Close()
+	provider := NewProvider(NewHTTPClient(server.URL))
+
+	statuses, err := provider.MessagesStatus(context.Background(), NumAsHex(l1Hash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0].TransactionHash.String() != "0x3" || statuses[0].ExecutionStatus != TxnExecutionStatusSUCCEEDED {
+		t.Fatalf("unexpected statuses %+v", statuses)
+	}
+	if statuses[1].FinalityStatus != TxnStatus_Rejected || statuses[1].FailureReason != "invalid payload" {
+		t.Fatalf("unexpected rejected status %+v", statuses[1])
+	}
+	if _, err := provider.MessagesStatus(context.Background(), "0x1"); !errors.Is(err, ErrHashNotFound) {
+		t.Fatalf("expected ErrHashNotFound, got %v", err)
+	}
+
+	receipt, err := provider.TransactionReceipt(context.Background(), statuses[0].TransactionHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l1Handler, ok := receipt.AsL1Handler()
+	if !ok || string(l1Handler.MsgHash) != l1Hash || l1Handler.BlockNumber != 7 {
+		t.Fatalf("unexpected l1_handler receipt %+v", receipt.TransactionReceipt)
+	}
+	if hash, number := receipt.Block(); hash.String() != "0xb" || number != 7 {
+		t.Fatalf("unexpected receipt block %s %d", hash, number)
+	}
+}
@@ -0,0 +1,80 @@
+package rpc
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportConfig configures the connection pool of the HTTP transport of an
+// HTTPClient. The pool of http.DefaultTransport keeps 2 idle connections per
+// host, so that clients sending hundreds of concurrent requests to a node
+// open a connection for most requests and exhaust the ephemeral ports with
+// connections in TIME_WAIT.
+type TransportConfig struct {
+	// MaxIdleConns is the maximum number of idle connections, to all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum number of idle connections to a host,
+	// the number of connections reused by concurrent requests
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost is the maximum number of connections to a host, requests
+	// waiting for a connection beyond it, 0 for no limit
+	MaxConnsPerHost int
+	// IdleConnTimeout is the duration an idle connection is kept
+	IdleConnTimeout time.Duration
+	// DialTimeout is the timeout of the establishment of a connection
+	DialTimeout time.Duration
+	// KeepAlive is the interval of the TCP keep-alive probes
+	KeepAlive time.Duration
+	// TLSHandshakeTimeout is the timeout of the TLS handshake
+	TLSHandshakeTimeout time.Duration
+	// DisableHTTP2 disables HTTP/2, which is negotiated over TLS otherwise and
+	// multiplexes the requests over a few connections
+	DisableHTTP2 bool
+}
+
+// DefaultTransportConfig is the configuration of the transport of the
+// HTTPClients created without WithHTTPClient, sized for indexers sending
+// hundreds of requests per second to a node.
+var DefaultTransportConfig = TransportConfig{
+	MaxIdleConns:        512,
+	MaxIdleConnsPerHost: 256,
+	IdleConnTimeout:     90 * time.Second,
+	DialTimeout:         30 * time.Second,
+	KeepAlive:           30 * time.Second,
+	TLSHandshakeTimeout: 10 * time.Second,
+}
+
+// defaultHTTPClient is the HTTP client of the HTTPClients created without
+// WithHTTPClient, sharing its connection pool between them.
+var defaultHTTPClient = &http.Client{Transport: NewTransport(DefaultTransportConfig)}
+
+// NewTransport returns an HTTP transport with a connection pool configured by
+// cfg, using the proxy of the environment as http.DefaultTransport does. It
+// is safe for concurrent use and meant to be shared by the clients of a
+// process.
+//
+// Parameters:
+// - cfg: the configuration of the transport
+// Returns:
+// - *http.Transport: the transport
+func NewTransport(cfg TransportConfig) *http.Transport {
+	dialer := &net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: cfg.KeepAlive}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ExpectContinueTimeout: time.Second,
+		ForceAttemptHTTP2:     !cfg.DisableHTTP2,
+	}
+	if cfg.DisableHTTP2 {
+		// a non-nil empty map disables the HTTP/2 upgrade of TLS connections
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
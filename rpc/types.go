@@ -97,6 +97,35 @@ type PendingStateUpdate struct {
 	StateDiff StateDiff `json:"state_diff"`
 }
 
+// IsPending reports whether the state update is the one of the pending block,
+// which has neither a block hash nor a new root.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true for the pending state update
+func (s *StateUpdateOutput) IsPending() bool {
+	return s.BlockHash == nil
+}
+
+// AsPending returns the state update of the pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *PendingStateUpdate: the pending state update
+// - bool: false if the state update is the one of an accepted block
+func (s *StateUpdateOutput) AsPending() (*PendingStateUpdate, bool) {
+	if !s.IsPending() {
+		return nil, false
+	}
+	return &s.PendingStateUpdate, true
+}
+
 // SyncStatus is An object describing the node synchronization status
 type SyncStatus struct {
 	SyncStatus        bool       // todo(remove? not in spec)
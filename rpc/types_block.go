@@ -1,9 +1,9 @@
 package rpc
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
-	"math/big"
 	"strconv"
 
 	"github.com/NethermindEth/juno/core/felt"
@@ -25,6 +25,47 @@ type BlockID struct {
 	Tag    string     `json:"block_tag,omitempty"`
 }
 
+const (
+	// BlockTagLatest is the tag of the latest accepted block
+	BlockTagLatest = "latest"
+	// BlockTagPending is the tag of the block being built by the sequencer
+	BlockTagPending = "pending"
+)
+
+// Validate checks that the BlockID is one of the block identifiers of the
+// spec: exactly one of a block number, a non-zero block hash, or the latest
+// or pending tag.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: an error wrapping ErrInvalidBlockID describing the problem
+func (b BlockID) Validate() error {
+	set := 0
+	if b.Number != nil {
+		set++
+	}
+	if b.Hash != nil {
+		set++
+	}
+	if b.Tag != "" {
+		set++
+	}
+	switch {
+	case set == 0:
+		return fmt.Errorf("%w: empty", ErrInvalidBlockID)
+	case set > 1:
+		return fmt.Errorf("%w: more than one of number, hash and tag", ErrInvalidBlockID)
+	case b.Tag != "" && b.Tag != BlockTagLatest && b.Tag != BlockTagPending:
+		return fmt.Errorf("%w: unknown tag %q", ErrInvalidBlockID, b.Tag)
+	case b.Hash != nil && b.Hash.IsZero():
+		return fmt.Errorf("%w: zero hash", ErrInvalidBlockID)
+	}
+	return nil
+}
+
 // MarshalJSON marshals the BlockID to JSON format.
 //
 // It returns a byte slice and an error. The byte slice contains the JSON representation of the BlockID,
@@ -36,26 +77,60 @@ type BlockID struct {
 //
 // Returns:
 // - []byte: the JSON representation of the BlockID
-// - error: any error that occurred during the marshaling process
+// - error: an error wrapping ErrInvalidBlockID if the BlockID is not valid
 func (b BlockID) MarshalJSON() ([]byte, error) {
-	if b.Tag == "pending" || b.Tag == "latest" {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	switch {
+	case b.Tag != "":
 		return []byte(strconv.Quote(b.Tag)), nil
+	case b.Number != nil:
+		return []byte(fmt.Sprintf(`{"block_number":%d}`, *b.Number)), nil
+	default:
+		return []byte(fmt.Sprintf(`{"block_hash":"%s"}`, b.Hash.String())), nil
 	}
+}
 
-	if b.Tag != "" {
-		return nil, ErrInvalidBlockID
+// UnmarshalJSON unmarshals a BlockID from the JSON forms of the spec, a tag
+// string, {"block_number": n} or {"block_hash": h}.
+//
+// Parameters:
+// - data: the JSON data
+// Returns:
+// - error: an error wrapping ErrInvalidBlockID if the data is not a block identifier of the spec
+func (b *BlockID) UnmarshalJSON(data []byte) error {
+	var tag string
+	if err := json.Unmarshal(data, &tag); err == nil {
+		id := BlockID{Tag: tag}
+		if err := id.Validate(); err != nil {
+			return err
+		}
+		*b = id
+		return nil
 	}
-
-	if b.Number != nil {
-		return []byte(fmt.Sprintf(`{"block_number":%d}`, *b.Number)), nil
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || len(fields) != 1 {
+		return fmt.Errorf("%w: %s", ErrInvalidBlockID, data)
 	}
-
-	if b.Hash.BigInt(big.NewInt(0)).BitLen() != 0 {
-		return []byte(fmt.Sprintf(`{"block_hash":"%s"}`, b.Hash.String())), nil
+	var id BlockID
+	if raw, ok := fields["block_number"]; ok {
+		var n uint64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return fmt.Errorf("%w: block number %s", ErrInvalidBlockID, raw)
+		}
+		id.Number = &n
+	} else if raw, ok := fields["block_hash"]; ok {
+		id.Hash = new(felt.Felt)
+		if err := json.Unmarshal(raw, id.Hash); err != nil {
+			return fmt.Errorf("%w: block hash %s", ErrInvalidBlockID, raw)
+		}
 	}
-
-	return nil, ErrInvalidBlockID
-
+	if err := id.Validate(); err != nil {
+		return err
+	}
+	*b = id
+	return nil
 }
 
 type BlockStatus string
@@ -150,6 +225,10 @@ type BlockHeader struct {
 	SequencerAddress *felt.Felt `json:"sequencer_address"`
 	// The price of l1 gas in the block
 	L1GasPrice ResourcePrice `json:"l1_gas_price"`
+	// The price of l1 data gas in the block
+	L1DataGasPrice ResourcePrice `json:"l1_data_gas_price"`
+	// The price of l2 gas in the block
+	L2GasPrice ResourcePrice `json:"l2_gas_price"`
 	// Semver of the current Starknet protocol
 	StarknetVersion string `json:"starknet_version"`
 }
@@ -163,13 +242,82 @@ type PendingBlockHeader struct {
 	SequencerAddress *felt.Felt `json:"sequencer_address"`
 	// The price of l1 gas in the block
 	L1GasPrice ResourcePrice `json:"l1_gas_price"`
+	// The price of l1 data gas in the block
+	L1DataGasPrice ResourcePrice `json:"l1_data_gas_price"`
+	// The price of l2 gas in the block
+	L2GasPrice ResourcePrice `json:"l2_gas_price"`
 	// Semver of the current Starknet protocol
 	StarknetVersion string `json:"starknet_version"`
 }
 
 type ResourcePrice struct {
 	// the price of one unit of the given resource, denominated in fri (10^-18 strk)
-	PriceInFRI *felt.Felt `json:"price_in_strk,omitempty"`
+	PriceInFRI *felt.Felt `json:"price_in_fri,omitempty"`
 	// The price of one unit of the given resource, denominated in wei
 	PriceInWei *felt.Felt `json:"price_in_wei"`
 }
+
+// TransactionWithReceipt is a transaction of a block paired with its receipt.
+type TransactionWithReceipt struct {
+	Transaction Transaction `json:"transaction"`
+	Receipt     Receipt     `json:"receipt"`
+}
+
+type BlockWithReceipts struct {
+	BlockHeader
+	Status BlockStatus `json:"status"`
+	// Transactions The transactions in this block, with their receipts
+	Transactions []TransactionWithReceipt `json:"transactions"`
+}
+
+type PendingBlockWithReceipts struct {
+	PendingBlockHeader
+	// Transactions The transactions in the pending block, with their receipts
+	Transactions []TransactionWithReceipt `json:"transactions"`
+}
+
+// UnmarshalJSON unmarshals a block with receipts. The receipts of
+// starknet_getBlockWithReceipts do not repeat the block: the block hash and
+// number of the header are added to them, so that the receipts of an
+// accepted block are not decoded as pending receipts.
+//
+// Parameters:
+// - data: It takes a byte slice as a parameter, which represents the JSON data to be unmarshaled
+// Returns:
+// - error: an error if the unmarshaling fails
+func (b *BlockWithReceipts) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		BlockHeader
+		Status       *BlockStatus `json:"status,omitempty"`
+		Transactions []struct {
+			Transaction map[string]interface{} `json:"transaction"`
+			Receipt     map[string]interface{} `json:"receipt"`
+		} `json:"transactions"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	transactions := make([]TransactionWithReceipt, len(raw.Transactions))
+	for i, t := range raw.Transactions {
+		txn, err := unmarshalTxn(t.Transaction)
+		if err != nil {
+			return err
+		}
+		if raw.BlockHash != nil {
+			t.Receipt["block_hash"] = raw.BlockHash.String()
+			t.Receipt["block_number"] = raw.BlockNumber
+		}
+		receipt, err := unmarshalTransactionReceipt(t.Receipt)
+		if err != nil {
+			return err
+		}
+		transactions[i] = TransactionWithReceipt{Transaction: txn, Receipt: Receipt{receipt}}
+	}
+
+	*b = BlockWithReceipts{BlockHeader: raw.BlockHeader, Transactions: transactions}
+	if raw.Status != nil {
+		b.Status = *raw.Status
+	}
+	return nil
+}
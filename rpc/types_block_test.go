@@ -71,6 +71,56 @@ func TestBlockID_Marshal(t *testing.T) {
 	}
 }
 
+// TestBlockID_Validate tests that the BlockID constructors build valid block
+// identifiers, that invalid combinations are rejected before being sent, and
+// that the JSON forms of the spec round-trip.
+//
+// Parameters:
+// - t: the testing object for running the test cases
+// Returns:
+//
+//	none
+func TestBlockID_Validate(t *testing.T) {
+	hash := new(felt.Felt).SetUint64(0xdead)
+	fromHash, err := BlockIDFromHash(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []BlockID{BlockIDLatest(), BlockIDPending(), BlockIDFromNumber(0), fromHash} {
+		data, err := json.Marshal(id)
+		if err != nil {
+			t.Fatalf("marshalling %+v: %v", id, err)
+		}
+		var decoded BlockID
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("unmarshalling %s: %v", data, err)
+		}
+		again, _ := json.Marshal(decoded)
+		if string(again) != string(data) {
+			t.Errorf("round trip mismatch, want: %s, got: %s", data, again)
+		}
+	}
+
+	if _, err := BlockIDFromHash(nil); !errors.Is(err, ErrInvalidBlockID) {
+		t.Errorf("expected ErrInvalidBlockID for a nil hash, got %v", err)
+	}
+	if _, err := BlockIDFromHash(new(felt.Felt)); !errors.Is(err, ErrInvalidBlockID) {
+		t.Errorf("expected ErrInvalidBlockID for a zero hash, got %v", err)
+	}
+	number := uint64(1)
+	for _, id := range []BlockID{{}, {Tag: "latest", Number: &number}, {Number: &number, Hash: hash}, {Tag: "earliest"}} {
+		if err := id.Validate(); !errors.Is(err, ErrInvalidBlockID) {
+			t.Errorf("expected ErrInvalidBlockID for %+v, got %v", id, err)
+		}
+	}
+	for _, data := range []string{`"earliest"`, `{}`, `{"block_number":1,"block_hash":"0x1"}`, `{"block_number":"0x1"}`, `{"block_hash":"0x0"}`} {
+		var id BlockID
+		if err := json.Unmarshal([]byte(data), &id); !errors.Is(err, ErrInvalidBlockID) {
+			t.Errorf("expected ErrInvalidBlockID for %s, got %v", data, err)
+		}
+	}
+}
+
 // TestBlockStatus is a unit test for the BlockStatus function.
 //
 // The test checks the behavior of the BlockStatus function by iterating through a list of test cases.
@@ -122,3 +172,36 @@ func TestBlock_Unmarshal(t *testing.T) {
 		t.Fatalf("Unmarshalling block: %v", err)
 	}
 }
+
+// TestBlockTransactions_UnmarshalV3 tests that the V3 invoke, declare and
+// deploy account transactions of a block are unmarshalled to their V3 types.
+//
+// Parameters:
+// - t: the testing object for running the test
+// Returns:
+//
+//	none
+func TestBlockTransactions_UnmarshalV3(t *testing.T) {
+	raw := `[
+		{"type": "INVOKE", "version": "0x3", "transaction_hash": "0x1", "sender_address": "0xacc", "nonce": "0x5"},
+		{"type": "DECLARE", "version": "0x3", "transaction_hash": "0x2", "sender_address": "0xacc", "nonce": "0x6"},
+		{"type": "DEPLOY_ACCOUNT", "version": "0x3", "transaction_hash": "0x3", "class_hash": "0xc1a55", "nonce": "0x0"},
+		{"type": "DEPLOY_ACCOUNT", "version": "0x1", "transaction_hash": "0x4", "class_hash": "0xc1a55", "nonce": "0x0"}
+	]`
+	var txns BlockTransactions
+	if err := json.Unmarshal([]byte(raw), &txns); err != nil {
+		t.Fatalf("Unmarshalling transactions: %v", err)
+	}
+	if invoke, ok := txns[0].(BlockInvokeTxnV3); !ok || invoke.Nonce.Uint64() != 5 || invoke.Hash().Uint64() != 1 {
+		t.Fatalf("expected a V3 invoke transaction, got %#v", txns[0])
+	}
+	if declare, ok := txns[1].(BlockDeclareTxnV3); !ok || declare.Nonce.Uint64() != 6 || declare.Hash().Uint64() != 2 {
+		t.Fatalf("expected a V3 declare transaction, got %#v", txns[1])
+	}
+	if _, ok := txns[2].(BlockDeployAccountTxnV3); !ok {
+		t.Fatalf("expected a V3 deploy account transaction, got %#v", txns[2])
+	}
+	if _, ok := txns[3].(BlockDeployAccountTxn); !ok {
+		t.Fatalf("expected a V1 deploy account transaction, got %#v", txns[3])
+	}
+}
@@ -16,11 +16,14 @@ type BlockTransaction interface {
 
 var _ BlockTransaction = BlockInvokeTxnV0{}
 var _ BlockTransaction = BlockInvokeTxnV1{}
+var _ BlockTransaction = BlockInvokeTxnV3{}
 var _ BlockTransaction = BlockDeclareTxnV0{}
 var _ BlockTransaction = BlockDeclareTxnV1{}
 var _ BlockTransaction = BlockDeclareTxnV2{}
+var _ BlockTransaction = BlockDeclareTxnV3{}
 var _ BlockTransaction = BlockDeployTxn{}
 var _ BlockTransaction = BlockDeployAccountTxn{}
+var _ BlockTransaction = BlockDeployAccountTxnV3{}
 var _ BlockTransaction = BlockL1HandlerTxn{}
 
 // Hash returns the transaction hash of the BlockInvokeTxnV0.
@@ -47,6 +50,18 @@ func (tx BlockInvokeTxnV1) Hash() *felt.Felt {
 	return tx.TransactionHash
 }
 
+// Hash returns the hash of the BlockInvokeTxnV3 transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the transaction hash
+func (tx BlockInvokeTxnV3) Hash() *felt.Felt {
+	return tx.TransactionHash
+}
+
 // Hash returns the transaction hash of the BlockDeclareTxnV0.
 //
 // Parameters:
@@ -83,6 +98,18 @@ func (tx BlockDeclareTxnV2) Hash() *felt.Felt {
 	return tx.TransactionHash
 }
 
+// Hash returns the transaction hash of the BlockDeclareTxnV3.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the transaction hash
+func (tx BlockDeclareTxnV3) Hash() *felt.Felt {
+	return tx.TransactionHash
+}
+
 // Hash returns the hash of the BlockDeployTxn.
 //
 // Parameters:
@@ -107,6 +134,18 @@ func (tx BlockDeployAccountTxn) Hash() *felt.Felt {
 	return tx.TransactionHash
 }
 
+// Hash returns the Felt hash of the BlockDeployAccountTxnV3.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the transaction hash
+func (tx BlockDeployAccountTxnV3) Hash() *felt.Felt {
+	return tx.TransactionHash
+}
+
 // Hash returns the hash of the BlockL1HandlerTxn.
 //
 // Parameters:
@@ -129,6 +168,11 @@ type BlockInvokeTxnV1 struct {
 	InvokeTxnV1
 }
 
+type BlockInvokeTxnV3 struct {
+	TransactionHash *felt.Felt `json:"transaction_hash"`
+	InvokeTxnV3
+}
+
 type BlockL1HandlerTxn struct {
 	TransactionHash *felt.Felt `json:"transaction_hash"`
 	L1HandlerTxn
@@ -164,6 +208,11 @@ type BlockDeployAccountTxn struct {
 	DeployAccountTxn
 }
 
+type BlockDeployAccountTxnV3 struct {
+	TransactionHash *felt.Felt `json:"transaction_hash"`
+	DeployAccountTxnV3
+}
+
 // UnmarshalJSON unmarshals the data into a BlockTransactions object.
 //
 // It takes a byte slice as the parameter, representing the JSON data to be unmarshalled.
@@ -219,7 +268,7 @@ func unmarshalBlockTxn(t interface{}) (BlockTransaction, error) {
 				remarshal(casted, &txn)
 				return txn, nil
 			case "0x3":
-				var txn BlockDeclareTxnV2
+				var txn BlockDeclareTxnV3
 				remarshal(casted, &txn)
 				return txn, nil
 			default:
@@ -230,15 +279,25 @@ func unmarshalBlockTxn(t interface{}) (BlockTransaction, error) {
 			remarshal(casted, &txn)
 			return txn, nil
 		case TransactionType_DeployAccount:
+			if casted["version"] == "0x3" {
+				var txn BlockDeployAccountTxnV3
+				remarshal(casted, &txn)
+				return txn, nil
+			}
 			var txn BlockDeployAccountTxn
 			remarshal(casted, &txn)
 			return txn, nil
 		case TransactionType_Invoke:
-			if casted["version"].(string) == "0x0" {
+			switch casted["version"].(string) {
+			case "0x0":
 				var txn BlockInvokeTxnV0
 				remarshal(casted, &txn)
 				return txn, nil
-			} else {
+			case "0x3":
+				var txn BlockInvokeTxnV3
+				remarshal(casted, &txn)
+				return txn, nil
+			default:
 				var txn BlockInvokeTxnV1
 				remarshal(casted, &txn)
 				return txn, nil
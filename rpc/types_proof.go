@@ -0,0 +1,87 @@
+package rpc
+
+import "github.com/NethermindEth/juno/core/felt"
+
+// ContractStorageKeys are the storage keys of a contract to prove.
+type ContractStorageKeys struct {
+	ContractAddress *felt.Felt   `json:"contract_address"`
+	StorageKeys     []*felt.Felt `json:"storage_keys"`
+}
+
+// StorageProofInput is the input of StorageProof.
+type StorageProofInput struct {
+	// BlockID is the block of the state to prove
+	BlockID BlockID `json:"block_id"`
+	// ClassHashes are the classes to prove in the classes tree
+	ClassHashes []*felt.Felt `json:"class_hashes,omitempty"`
+	// ContractAddresses are the contracts to prove in the contracts tree
+	ContractAddresses []*felt.Felt `json:"contract_addresses,omitempty"`
+	// ContractsStorageKeys are the storage keys to prove in the storage tries
+	// of the contracts
+	ContractsStorageKeys []ContractStorageKeys `json:"contracts_storage_keys,omitempty"`
+}
+
+// MerkleNode is a node of a Merkle-Patricia tree: a binary node with Left and
+// Right children, or an edge node with a Path of Length bits to its Child.
+type MerkleNode struct {
+	Left   *felt.Felt `json:"left,omitempty"`
+	Right  *felt.Felt `json:"right,omitempty"`
+	Path   *felt.Felt `json:"path,omitempty"`
+	Length uint8      `json:"length,omitempty"`
+	Child  *felt.Felt `json:"child,omitempty"`
+}
+
+// IsEdge reports whether the node is an edge node.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true for an edge node, false for a binary node
+func (n MerkleNode) IsEdge() bool {
+	return n.Child != nil
+}
+
+// NodeHashToNode is a node of a proof with its hash.
+type NodeHashToNode struct {
+	NodeHash *felt.Felt `json:"node_hash"`
+	Node     MerkleNode `json:"node"`
+}
+
+// NodeHashToNodeMapping are the nodes of a proof, in any order.
+type NodeHashToNodeMapping []NodeHashToNode
+
+// ContractLeafData is the state of a proven contract.
+type ContractLeafData struct {
+	Nonce     *felt.Felt `json:"nonce"`
+	ClassHash *felt.Felt `json:"class_hash"`
+	// StorageRoot is the root of the storage trie of the contract, if returned
+	// by the node
+	StorageRoot *felt.Felt `json:"storage_root,omitempty"`
+}
+
+// ContractsProof is the proof of the contracts in the contracts tree.
+type ContractsProof struct {
+	Nodes NodeHashToNodeMapping `json:"nodes"`
+	// ContractLeavesData are the states of the contracts, in the order of the
+	// contract addresses of the input
+	ContractLeavesData []ContractLeafData `json:"contract_leaves_data"`
+}
+
+// GlobalRoots are the roots the proofs are rooted at.
+type GlobalRoots struct {
+	ContractsTreeRoot *felt.Felt `json:"contracts_tree_root"`
+	ClassesTreeRoot   *felt.Felt `json:"classes_tree_root"`
+	BlockHash         *felt.Felt `json:"block_hash"`
+}
+
+// StorageProofResult is the result of StorageProof.
+type StorageProofResult struct {
+	ClassesProof   NodeHashToNodeMapping `json:"classes_proof"`
+	ContractsProof ContractsProof        `json:"contracts_proof"`
+	// ContractsStorageProofs are the proofs of the storage keys, in the order
+	// of the contracts storage keys of the input
+	ContractsStorageProofs []NodeHashToNodeMapping `json:"contracts_storage_proofs"`
+	GlobalRoots            GlobalRoots             `json:"global_roots"`
+}
@@ -1,6 +1,10 @@
 package rpc
 
-import "github.com/NethermindEth/juno/core/felt"
+import (
+	"encoding/json"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
 
 type SimulateTransactionInput struct {
 	//a sequence of transactions to simulate, running each transaction on the state resulting from applying all the previous ones
@@ -126,3 +130,29 @@ type ExecInvocation struct {
 	FunctionInvocation FnInvocation `json:"function_invocation,omitempty"`
 	RevertReason       string       `json:"revert_reason,omitempty"`
 }
+
+// UnmarshalJSON decodes an execute invocation, which nodes return either as
+// the function invocation itself or as an object holding the revert reason.
+//
+// Parameters:
+// - data: the JSON execute invocation
+// Returns:
+// - error: an error if the invocation can not be decoded
+func (e *ExecInvocation) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if reason, ok := fields["revert_reason"]; ok {
+		if err := json.Unmarshal(reason, &e.RevertReason); err != nil {
+			return err
+		}
+	}
+	if nested, ok := fields["function_invocation"]; ok {
+		return json.Unmarshal(nested, &e.FunctionInvocation)
+	}
+	if _, ok := fields["contract_address"]; ok {
+		return json.Unmarshal(data, &e.FunctionInvocation)
+	}
+	return nil
+}
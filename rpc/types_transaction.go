@@ -66,11 +66,13 @@ type InvokeTxnV3 struct {
 	FeeMode DataAvailabilityMode `json:"fee_data_availability_mode"`
 }
 
+// L1HandlerTxn is the transaction executing an L1 to L2 message, calling the
+// l1_handler entrypoint of the recipient with the L1 sender and the payload.
 type L1HandlerTxn struct {
 	Type TransactionType `json:"type,omitempty"`
 	// Version of the transaction scheme
 	Version *felt.Felt `json:"version"`
-	// Nonce
+	// Nonce is the nonce of the message in the L1 core contract
 	Nonce string `json:"nonce,omitempty"`
 	FunctionCall
 }
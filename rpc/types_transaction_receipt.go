@@ -245,7 +245,12 @@ func (tr DeployAccountTransactionReceipt) GetExecutionStatus() TxnExecutionStatu
 }
 
 // L1HandlerTransactionReceipt L1 Handler Transaction Receipt
-type L1HandlerTransactionReceipt CommonTransactionReceipt
+type L1HandlerTransactionReceipt struct {
+	CommonTransactionReceipt
+	// MsgHash is the hash of the L1 message executed by the transaction, as it
+	// appears on the L1 core contract
+	MsgHash NumAsHex `json:"message_hash"`
+}
 
 // Hash returns the transaction hash.
 //
@@ -561,7 +566,20 @@ const (
 	TxnStatus_Accepted_On_L1 TxnStatus = "ACCEPTED_ON_L1"
 )
 
+// MessageStatus is the status of the l1_handler transaction executing an L1
+// to L2 message, as returned by starknet_getMessagesStatus.
+type MessageStatus struct {
+	// TransactionHash is the hash of the l1_handler transaction
+	TransactionHash *felt.Felt         `json:"transaction_hash"`
+	FinalityStatus  TxnStatus          `json:"finality_status"`
+	ExecutionStatus TxnExecutionStatus `json:"execution_status,omitempty"`
+	// FailureReason is the reason of a rejection or a revert, if returned by the node
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
 type TxnStatusResp struct {
 	ExecutionStatus TxnExecutionStatus `json:"execution_status,omitempty"`
 	FinalityStatus  TxnStatus          `json:"finality_status"`
+	// FailureReason is the reason of a rejection or a revert, if returned by the node
+	FailureReason string `json:"failure_reason,omitempty"`
 }
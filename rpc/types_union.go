@@ -0,0 +1,588 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// Receipt is the receipt returned by TransactionReceipt. It holds one of the
+// receipt types of the package, by value: InvokeTransactionReceipt,
+// DeclareTransactionReceipt, DeployTransactionReceipt,
+// DeployAccountTransactionReceipt, L1HandlerTransactionReceipt, or their
+// Pending counterparts for the receipts of the pending block.
+//
+// The As methods return the receipt of a given type, and the other methods
+// return the fields common to every type, so that callers do not need to
+// switch on the type of the receipt.
+type Receipt struct {
+	TransactionReceipt
+}
+
+// UnmarshalJSON unmarshals a receipt into the receipt type matching its type
+// and its block.
+//
+// Parameters:
+// - data: the JSON data
+// Returns:
+// - error: an error if the unmarshaling fails
+func (r *Receipt) UnmarshalJSON(data []byte) error {
+	var receipt UnknownTransactionReceipt
+	if err := json.Unmarshal(data, &receipt); err != nil {
+		return err
+	}
+	r.TransactionReceipt = receipt.TransactionReceipt
+	return nil
+}
+
+// MarshalJSON marshals the receipt held.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the JSON data
+// - error: an error if the marshaling fails
+func (r Receipt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.TransactionReceipt)
+}
+
+// AsInvoke returns the receipt of an invoke transaction of an accepted block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - InvokeTransactionReceipt: the receipt
+// - bool: true if the receipt is of this type
+func (r Receipt) AsInvoke() (InvokeTransactionReceipt, bool) {
+	tr, ok := r.value().(InvokeTransactionReceipt)
+	return tr, ok
+}
+
+// AsDeclare returns the receipt of a declare transaction of an accepted block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - DeclareTransactionReceipt: the receipt
+// - bool: true if the receipt is of this type
+func (r Receipt) AsDeclare() (DeclareTransactionReceipt, bool) {
+	tr, ok := r.value().(DeclareTransactionReceipt)
+	return tr, ok
+}
+
+// AsDeploy returns the receipt of a deploy transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - DeployTransactionReceipt: the receipt
+// - bool: true if the receipt is of this type
+func (r Receipt) AsDeploy() (DeployTransactionReceipt, bool) {
+	tr, ok := r.value().(DeployTransactionReceipt)
+	return tr, ok
+}
+
+// AsDeployAccount returns the receipt of a deploy account transaction of an
+// accepted block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - DeployAccountTransactionReceipt: the receipt
+// - bool: true if the receipt is of this type
+func (r Receipt) AsDeployAccount() (DeployAccountTransactionReceipt, bool) {
+	tr, ok := r.value().(DeployAccountTransactionReceipt)
+	return tr, ok
+}
+
+// AsL1Handler returns the receipt of an l1_handler transaction of an accepted
+// block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - L1HandlerTransactionReceipt: the receipt
+// - bool: true if the receipt is of this type
+func (r Receipt) AsL1Handler() (L1HandlerTransactionReceipt, bool) {
+	tr, ok := r.value().(L1HandlerTransactionReceipt)
+	return tr, ok
+}
+
+// IsPending reports whether the receipt is of a transaction of the pending
+// block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true for the Pending receipt types
+func (r Receipt) IsPending() bool {
+	switch r.value().(type) {
+	case PendingInvokeTransactionReceipt, PendingDeclareTransactionReceipt, PendingDeployAccountTransactionReceipt, PendingL1HandlerTransactionReceipt:
+		return true
+	}
+	return false
+}
+
+// Type returns the type of the transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - TransactionType: the type, empty if the receipt is nil
+func (r Receipt) Type() TransactionType {
+	switch r.value().(type) {
+	case InvokeTransactionReceipt, PendingInvokeTransactionReceipt:
+		return TransactionType_Invoke
+	case DeclareTransactionReceipt, PendingDeclareTransactionReceipt:
+		return TransactionType_Declare
+	case DeployTransactionReceipt:
+		return TransactionType_Deploy
+	case DeployAccountTransactionReceipt, PendingDeployAccountTransactionReceipt:
+		return TransactionType_DeployAccount
+	case L1HandlerTransactionReceipt, PendingL1HandlerTransactionReceipt:
+		return TransactionType_L1Handler
+	}
+	return ""
+}
+
+// ActualFee returns the fee charged for the transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - FeePayment: the fee and its unit
+func (r Receipt) ActualFee() FeePayment {
+	return r.common().ActualFee
+}
+
+// ExecutionStatus returns the execution status of the transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - TxnExecutionStatus: the execution status
+func (r Receipt) ExecutionStatus() TxnExecutionStatus {
+	return r.common().ExecutionStatus
+}
+
+// FinalityStatus returns the finality status of the transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - TxnFinalityStatus: the finality status
+func (r Receipt) FinalityStatus() TxnFinalityStatus {
+	return r.common().FinalityStatus
+}
+
+// RevertReason returns the revert reason of a reverted transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the revert reason, empty if the transaction succeeded
+func (r Receipt) RevertReason() string {
+	return r.common().RevertReason
+}
+
+// Events returns the events emitted by the transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []Event: the events
+func (r Receipt) Events() []Event {
+	return r.common().Events
+}
+
+// MessagesSent returns the L2 to L1 messages sent by the transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []MsgToL1: the messages
+func (r Receipt) MessagesSent() []MsgToL1 {
+	return r.common().MessagesSent
+}
+
+// Block returns the block including the transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the block hash, nil for the pending block
+// - uint64: the block number, 0 for the pending block
+func (r Receipt) Block() (*felt.Felt, uint64) {
+	c := r.common()
+	return c.BlockHash, c.BlockNumber
+}
+
+// value returns the receipt held, unwrapping nested receipts.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - TransactionReceipt: the receipt
+func (r Receipt) value() TransactionReceipt {
+	switch tr := r.TransactionReceipt.(type) {
+	case Receipt:
+		return tr.value()
+	case *Receipt:
+		if tr == nil {
+			return nil
+		}
+		return tr.value()
+	}
+	return r.TransactionReceipt
+}
+
+// common returns the fields of the receipt common to every receipt type.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - CommonTransactionReceipt: the common fields, zero if the type is unknown
+func (r Receipt) common() CommonTransactionReceipt {
+	switch tr := r.value().(type) {
+	case InvokeTransactionReceipt:
+		return CommonTransactionReceipt(tr)
+	case DeclareTransactionReceipt:
+		return CommonTransactionReceipt(tr)
+	case L1HandlerTransactionReceipt:
+		return tr.CommonTransactionReceipt
+	case DeployTransactionReceipt:
+		return tr.CommonTransactionReceipt
+	case DeployAccountTransactionReceipt:
+		return tr.CommonTransactionReceipt
+	case PendingInvokeTransactionReceipt:
+		return pendingCommon(tr.Type, tr.PendingCommonTransactionReceiptProperties)
+	case PendingDeclareTransactionReceipt:
+		return pendingCommon(tr.Type, tr.PendingCommonTransactionReceiptProperties)
+	case PendingDeployAccountTransactionReceipt:
+		return pendingCommon(tr.Type, tr.PendingCommonTransactionReceiptProperties)
+	case PendingL1HandlerTransactionReceipt:
+		return pendingCommon(tr.Type, tr.PendingCommonTransactionReceiptProperties)
+	}
+	return CommonTransactionReceipt{}
+}
+
+// pendingCommon converts the properties of a pending receipt to the common
+// receipt fields.
+//
+// Parameters:
+// - typ: the type of the transaction
+// - p: the properties of the pending receipt
+// Returns:
+// - CommonTransactionReceipt: the common fields, without block
+func pendingCommon(typ TransactionType, p PendingCommonTransactionReceiptProperties) CommonTransactionReceipt {
+	return CommonTransactionReceipt{
+		TransactionHash:    p.TransactionHash,
+		ActualFee:          p.ActualFee,
+		ExecutionStatus:    p.ExecutionStatus,
+		FinalityStatus:     p.FinalityStatus,
+		Type:               typ,
+		MessagesSent:       p.MessagesSent,
+		RevertReason:       p.RevertReason,
+		Events:             p.Events,
+		ExecutionResources: p.ExecutionResources,
+	}
+}
+
+// BlockTxHashesResult is the block returned by BlockWithTxHashes: Block for
+// an accepted block, or Pending for the pending block.
+type BlockTxHashesResult struct {
+	Block   *BlockTxHashes
+	Pending *PendingBlockTxHashes
+}
+
+// AsBlock returns the accepted block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *BlockTxHashes: the block
+// - bool: true if the block is not pending
+func (r *BlockTxHashesResult) AsBlock() (*BlockTxHashes, bool) {
+	return r.Block, r.Block != nil
+}
+
+// AsPending returns the pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *PendingBlockTxHashes: the block
+// - bool: true if the block is pending
+func (r *BlockTxHashesResult) AsPending() (*PendingBlockTxHashes, bool) {
+	return r.Pending, r.Pending != nil
+}
+
+// IsPending reports whether the block is the pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if the block is pending
+func (r *BlockTxHashesResult) IsPending() bool {
+	return r.Pending != nil
+}
+
+// Header returns the header fields of the block common to accepted and
+// pending blocks.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - PendingBlockHeader: the header
+func (r *BlockTxHashesResult) Header() PendingBlockHeader {
+	if r.Pending != nil {
+		return r.Pending.PendingBlockHeader
+	}
+	if r.Block != nil {
+		return pendingHeader(r.Block.BlockHeader)
+	}
+	return PendingBlockHeader{}
+}
+
+// Transactions returns the hashes of the transactions of the block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*felt.Felt: the transaction hashes
+func (r *BlockTxHashesResult) Transactions() []*felt.Felt {
+	if r.Pending != nil {
+		return r.Pending.Transactions
+	}
+	if r.Block != nil {
+		return r.Block.Transactions
+	}
+	return nil
+}
+
+// BlockResult is the block returned by BlockWithTxs: Block for an accepted
+// block, or Pending for the pending block.
+type BlockResult struct {
+	Block   *Block
+	Pending *PendingBlock
+}
+
+// AsBlock returns the accepted block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *Block: the block
+// - bool: true if the block is not pending
+func (r *BlockResult) AsBlock() (*Block, bool) {
+	return r.Block, r.Block != nil
+}
+
+// AsPending returns the pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *PendingBlock: the block
+// - bool: true if the block is pending
+func (r *BlockResult) AsPending() (*PendingBlock, bool) {
+	return r.Pending, r.Pending != nil
+}
+
+// IsPending reports whether the block is the pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if the block is pending
+func (r *BlockResult) IsPending() bool {
+	return r.Pending != nil
+}
+
+// Header returns the header fields of the block common to accepted and
+// pending blocks.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - PendingBlockHeader: the header
+func (r *BlockResult) Header() PendingBlockHeader {
+	if r.Pending != nil {
+		return r.Pending.PendingBlockHeader
+	}
+	if r.Block != nil {
+		return pendingHeader(r.Block.BlockHeader)
+	}
+	return PendingBlockHeader{}
+}
+
+// Transactions returns the transactions of the block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - BlockTransactions: the transactions
+func (r *BlockResult) Transactions() BlockTransactions {
+	if r.Pending != nil {
+		return r.Pending.BlockTransactions
+	}
+	if r.Block != nil {
+		return r.Block.Transactions
+	}
+	return nil
+}
+
+// BlockWithReceiptsResult is the block returned by BlockWithReceipts: Block
+// for an accepted block, or Pending for the pending block.
+type BlockWithReceiptsResult struct {
+	Block   *BlockWithReceipts
+	Pending *PendingBlockWithReceipts
+}
+
+// AsBlock returns the accepted block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *BlockWithReceipts: the block
+// - bool: true if the block is not pending
+func (r *BlockWithReceiptsResult) AsBlock() (*BlockWithReceipts, bool) {
+	return r.Block, r.Block != nil
+}
+
+// AsPending returns the pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *PendingBlockWithReceipts: the block
+// - bool: true if the block is pending
+func (r *BlockWithReceiptsResult) AsPending() (*PendingBlockWithReceipts, bool) {
+	return r.Pending, r.Pending != nil
+}
+
+// IsPending reports whether the block is the pending block.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if the block is pending
+func (r *BlockWithReceiptsResult) IsPending() bool {
+	return r.Pending != nil
+}
+
+// Header returns the header fields of the block common to accepted and
+// pending blocks.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - PendingBlockHeader: the header
+func (r *BlockWithReceiptsResult) Header() PendingBlockHeader {
+	if r.Pending != nil {
+		return r.Pending.PendingBlockHeader
+	}
+	if r.Block != nil {
+		return pendingHeader(r.Block.BlockHeader)
+	}
+	return PendingBlockHeader{}
+}
+
+// Transactions returns the transactions of the block with their receipts.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []TransactionWithReceipt: the transactions and their receipts
+func (r *BlockWithReceiptsResult) Transactions() []TransactionWithReceipt {
+	if r.Pending != nil {
+		return r.Pending.Transactions
+	}
+	if r.Block != nil {
+		return r.Block.Transactions
+	}
+	return nil
+}
+
+// pendingHeader returns the fields of a block header shared with the header
+// of the pending block.
+//
+// Parameters:
+// - h: the header
+// Returns:
+// - PendingBlockHeader: the shared fields
+func pendingHeader(h BlockHeader) PendingBlockHeader {
+	return PendingBlockHeader{
+		ParentHash:       h.ParentHash,
+		Timestamp:        h.Timestamp,
+		SequencerAddress: h.SequencerAddress,
+		L1GasPrice:       h.L1GasPrice,
+		L1DataGasPrice:   h.L1DataGasPrice,
+		L2GasPrice:       h.L2GasPrice,
+		StarknetVersion:  h.StarknetVersion,
+	}
+}
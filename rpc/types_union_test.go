@@ -0,0 +1,102 @@
+package rpc
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestReceipt tests the accessors of Receipt on accepted and pending
+// receipts decoded from JSON, and that nested receipts are unwrapped.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestReceipt(t *testing.T) {
+	var accepted Receipt
+	if err := json.Unmarshal([]byte(`{
+		"type": "INVOKE",
+		"transaction_hash": "0x1",
+		"actual_fee": {"amount": "0x10", "unit": "WEI"},
+		"execution_status": "REVERTED",
+		"finality_status": "ACCEPTED_ON_L2",
+		"block_hash": "0x2",
+		"block_number": 3,
+		"messages_sent": [],
+		"revert_reason": "insufficient balance",
+		"events": [{"from_address": "0x4", "keys": ["0x5"], "data": []}]
+	}`), &accepted); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := accepted.AsInvoke(); !ok {
+		t.Fatalf("expected an invoke receipt, got %T", accepted.TransactionReceipt)
+	}
+	if _, ok := accepted.AsDeploy(); ok {
+		t.Fatal("invoke receipt returned as deploy receipt")
+	}
+	if accepted.IsPending() || accepted.Type() != TransactionType_Invoke {
+		t.Fatalf("unexpected pending %v or type %s", accepted.IsPending(), accepted.Type())
+	}
+	if accepted.ActualFee().Amount.String() != "0x10" || accepted.ExecutionStatus() != TxnExecutionStatusREVERTED {
+		t.Fatalf("unexpected fee %s or status %s", accepted.ActualFee().Amount, accepted.ExecutionStatus())
+	}
+	if accepted.RevertReason() != "insufficient balance" || len(accepted.Events()) != 1 {
+		t.Fatalf("unexpected revert reason %q or events %v", accepted.RevertReason(), accepted.Events())
+	}
+	if hash, number := accepted.Block(); hash.String() != "0x2" || number != 3 {
+		t.Fatalf("unexpected block %s %d", hash, number)
+	}
+
+	var pending Receipt
+	if err := json.Unmarshal([]byte(`{
+		"type": "DEPLOY_ACCOUNT",
+		"transaction_hash": "0x6",
+		"contract_address": "0x7",
+		"actual_fee": {"amount": "0x20", "unit": "FRI"},
+		"execution_status": "SUCCEEDED",
+		"finality_status": "ACCEPTED_ON_L2",
+		"messages_sent": [],
+		"events": []
+	}`), &pending); err != nil {
+		t.Fatal(err)
+	}
+	if !pending.IsPending() || pending.Type() != TransactionType_DeployAccount {
+		t.Fatalf("unexpected pending %v or type %s", pending.IsPending(), pending.Type())
+	}
+	if _, ok := pending.AsDeployAccount(); ok {
+		t.Fatal("pending receipt returned as accepted receipt")
+	}
+	nested := Receipt{TransactionReceipt: &pending}
+	if nested.ActualFee().Unit != UnitStrk || nested.ExecutionStatus() != TxnExecutionStatusSUCCEEDED {
+		t.Fatalf("unexpected fee %v or status %s", nested.ActualFee(), nested.ExecutionStatus())
+	}
+}
+
+// TestBlockResult tests the header and the transactions of accepted and
+// pending block results.
+//
+// Parameters:
+// - t: the testing object
+// Returns:
+//
+//	none
+func TestBlockResult(t *testing.T) {
+	accepted := &BlockTxHashesResult{Block: &BlockTxHashes{BlockHeader: BlockHeader{StarknetVersion: "0.13.1"}}}
+	if block, ok := accepted.AsBlock(); !ok || block != accepted.Block || accepted.IsPending() {
+		t.Fatal("unexpected accepted block")
+	}
+	if accepted.Header().StarknetVersion != "0.13.1" {
+		t.Fatalf("unexpected version %q", accepted.Header().StarknetVersion)
+	}
+	pending := &BlockResult{Pending: &PendingBlock{
+		PendingBlockHeader: PendingBlockHeader{StarknetVersion: "0.13.2"},
+		BlockTransactions:  BlockTransactions{BlockL1HandlerTxn{}},
+	}}
+	if _, ok := pending.AsBlock(); ok || !pending.IsPending() {
+		t.Fatal("unexpected pending block")
+	}
+	if pending.Header().StarknetVersion != "0.13.2" || len(pending.Transactions()) != 1 {
+		t.Fatalf("unexpected header %v or transactions %v", pending.Header(), pending.Transactions())
+	}
+}
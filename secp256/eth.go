@@ -0,0 +1,199 @@
+package secp256
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// personalSignPrefix is the EIP-191 prefix of the personal_sign message of a
+// 32 byte hash.
+const personalSignPrefix = "\x19Ethereum Signed Message:\n32"
+
+// PersonalSignMessage returns the message an Ethereum wallet signs with
+// personal_sign to sign a Starknet hash as an EIP-191 signer: the hash as 32
+// bytes. Wallets prefix it with "\x19Ethereum Signed Message:\n32".
+//
+// Parameters:
+// - hash: the hash, e.g. a transaction hash
+// Returns:
+// - []byte: the message to pass to personal_sign
+func PersonalSignMessage(hash *felt.Felt) []byte {
+	bytes := hash.Bytes()
+	return bytes[:]
+}
+
+// PersonalSignHash returns the digest signed by an Ethereum wallet for the
+// personal_sign message of a hash, keccak256 of the prefixed message.
+//
+// Parameters:
+// - hash: the hash
+// Returns:
+// - [32]byte: the digest
+func PersonalSignHash(hash *felt.Felt) [32]byte {
+	var digest [32]byte
+	copy(digest[:], utils.Keccak256([]byte(personalSignPrefix), PersonalSignMessage(hash)))
+	return digest
+}
+
+// EIP712Domain is the EIP-712 domain of the typed data of a Starknet hash.
+type EIP712Domain struct {
+	Name    string
+	Version string
+	// ChainID is the Ethereum chain ID of the wallet
+	ChainID *big.Int
+}
+
+// EIP712 is the typed data signed with eth_signTypedData_v4 by the Ethereum
+// wallets of account classes verifying EIP-712 signatures of Starknet hashes,
+// a struct of one bytes32 member holding the hash, e.g.
+// StarknetTransaction(bytes32 hash). The type and member names are the ones
+// of the account class.
+type EIP712 struct {
+	Domain      EIP712Domain
+	PrimaryType string
+	Member      string
+}
+
+// TypedData returns the JSON typed data of a hash to pass to
+// eth_signTypedData_v4.
+//
+// Parameters:
+// - hash: the hash
+// Returns:
+// - []byte: the typed data
+// - error: an error if the typed data cannot be marshaled
+func (e EIP712) TypedData(hash *felt.Felt) ([]byte, error) {
+	type member struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	return json.Marshal(map[string]any{
+		"types": map[string][]member{
+			"EIP712Domain": {{"name", "string"}, {"version", "string"}, {"chainId", "uint256"}},
+			e.PrimaryType:  {{e.Member, "bytes32"}},
+		},
+		"primaryType": e.PrimaryType,
+		"domain":      map[string]any{"name": e.Domain.Name, "version": e.Domain.Version, "chainId": e.Domain.ChainID},
+		"message":     map[string]string{e.Member: "0x" + hex.EncodeToString(PersonalSignMessage(hash))},
+	})
+}
+
+// Hash returns the EIP-712 digest of the typed data of a hash,
+// keccak256("\x19\x01" || domainSeparator || hashStruct(message)), the digest
+// the wallet signs.
+//
+// Parameters:
+// - hash: the hash
+// Returns:
+// - [32]byte: the digest
+func (e EIP712) Hash(hash *felt.Felt) [32]byte {
+	var chainID [32]byte
+	e.Domain.ChainID.FillBytes(chainID[:])
+	domainSeparator := utils.Keccak256(
+		utils.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId)")),
+		utils.Keccak256([]byte(e.Domain.Name)),
+		utils.Keccak256([]byte(e.Domain.Version)),
+		chainID[:],
+	)
+	message := PersonalSignMessage(hash)
+	structHash := utils.Keccak256(utils.Keccak256([]byte(fmt.Sprintf("%s(bytes32 %s)", e.PrimaryType, e.Member))), message)
+
+	var digest [32]byte
+	copy(digest[:], utils.Keccak256([]byte{0x19, 0x01}, domainSeparator, structHash))
+	return digest
+}
+
+// ParseEthSignature parses the 65 byte signature r || s || v returned by
+// Ethereum wallets, v being 27 or 28, or 0 or 1. The signature is normalized
+// to s <= N/2, as checked by Starknet account classes.
+//
+// Parameters:
+// - sig: the signature
+// Returns:
+// - *Signature: the signature
+// - error: ErrInvalidSignature
+func ParseEthSignature(sig []byte) (*Signature, error) {
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("%w: %d bytes, expected 65", ErrInvalidSignature, len(sig))
+	}
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return nil, fmt.Errorf("%w: recovery id %d", ErrInvalidSignature, sig[64])
+	}
+	n := curves[Secp256k1].n
+	parsed := &Signature{R: new(big.Int).SetBytes(sig[:32]), S: new(big.Int).SetBytes(sig[32:64]), YParity: v == 1}
+	if parsed.R.Sign() == 0 || parsed.R.Cmp(n) >= 0 || parsed.S.Sign() == 0 || parsed.S.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("%w: component out of range", ErrInvalidSignature)
+	}
+	if parsed.S.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		parsed.S.Sub(n, parsed.S)
+		parsed.YParity = !parsed.YParity
+	}
+	return parsed, nil
+}
+
+// RecoverEthAddress returns the Ethereum address of the secp256k1 key of a
+// signature of a digest, to check an Ethereum wallet signature before
+// relaying it.
+//
+// Parameters:
+// - digest: the signed digest, e.g. PersonalSignHash or EIP712.Hash
+// - sig: the signature, with its parity
+// Returns:
+// - *felt.Felt: the address
+// - error: ErrInvalidSignature if no key is recovered
+func RecoverEthAddress(digest [32]byte, sig *Signature) (*felt.Felt, error) {
+	c := curves[Secp256k1]
+	if sig == nil || sig.R == nil || sig.S == nil || sig.R.Sign() <= 0 || sig.R.Cmp(c.n) >= 0 || sig.S.Sign() <= 0 || sig.S.Cmp(c.n) >= 0 {
+		return nil, fmt.Errorf("%w: component out of range", ErrInvalidSignature)
+	}
+	// y^2 = x^3 + 7
+	var x, y fp.Element
+	x.SetBigInt(sig.R)
+	y.Square(&x).Mul(&y, &x).Add(&y, new(fp.Element).SetUint64(7))
+	if y.Sqrt(&y) == nil {
+		return nil, fmt.Errorf("%w: r is not on the curve", ErrInvalidSignature)
+	}
+	ry := y.BigInt(new(big.Int))
+	if (ry.Bit(0) == 1) != sig.YParity {
+		y.Neg(&y)
+		ry = y.BigInt(new(big.Int))
+	}
+
+	// Q = r^-1 (s R - z G)
+	rInv := new(big.Int).ModInverse(sig.R, c.n)
+	u1 := new(big.Int).Neg(new(big.Int).SetBytes(digest[:]))
+	u1.Mul(u1, rInv).Mod(u1, c.n)
+	u2 := new(big.Int).Mul(sig.S, rInv)
+	u2.Mod(u2, c.n)
+	qx, qy := c.mult(sig.R, ry, u2)
+	if u1.Sign() != 0 {
+		x1, y1 := c.baseMult(u1)
+		qx, qy = c.add(x1, y1, qx, qy)
+	}
+	if qx.Sign() == 0 && qy.Sign() == 0 {
+		return nil, fmt.Errorf("%w: no key recovered", ErrInvalidSignature)
+	}
+	return EthAddress(qx, qy), nil
+}
+
+// Eip191Signature returns the signature of an Ethereum wallet as the
+// signature of an EIP-191 signer of an Argent account, in LayoutArgentEip191.
+//
+// Parameters:
+// - address: the Ethereum address of the wallet
+// - sig: the signature of the personal_sign message of the hash
+// Returns:
+// - []*felt.Felt: the signature
+func Eip191Signature(address *felt.Felt, sig *Signature) []*felt.Felt {
+	return argentSignature([]*felt.Felt{new(felt.Felt).SetUint64(argentEip191), address}, sig)
+}
@@ -0,0 +1,468 @@
+// Package secp256 signs the transactions of accounts whose __validate__
+// checks secp256k1 signatures, e.g. the OpenZeppelin EthAccount and the
+// Ethereum signers of Argent accounts, or secp256r1 signatures, e.g. the
+// hardware and passkey keys of Argent accounts, in the multi-felt layouts
+// these classes expect. Like the secp256 syscalls, the signed message is the
+// hash itself, as a u256, without hashing it again.
+//
+//	key, _ := secp256.GenerateKey(secp256.Secp256k1)
+//	signer := secp256.NewSigner(key, secp256.LayoutArgent)
+//	acnt, _ := account.NewAccount(provider, address, "", nil, 2, account.WithSigner(signer))
+//
+// Signatures are normalized to s <= N/2, as checked by the account classes.
+// WebauthnSigner assembles the signatures of passkeys, which sign in the
+// browser, from their assertions.
+// The secp256k1 arithmetic is not constant time: keys of high value should be
+// held by a hardware or remote signer implementing account.Signer.
+package secp256
+
+import (
+	"context"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var (
+	ErrUnknownCurve     = errors.New("secp256: unknown curve")
+	ErrInvalidKey       = errors.New("secp256: private key out of range")
+	ErrUnknownLayout    = errors.New("secp256: unknown signature layout")
+	ErrInvalidSignature = errors.New("secp256: invalid signature")
+)
+
+// Curve is a secp256 curve.
+type Curve int
+
+const (
+	Secp256k1 Curve = iota
+	Secp256r1
+)
+
+// Layout is the layout of the signature expected by an account class.
+type Layout int
+
+const (
+	// LayoutRaw is r and s as u256, [r.low, r.high, s.low, s.high], the
+	// EthSignature of the OpenZeppelin EthAccount
+	LayoutRaw Layout = iota
+	// LayoutArgent is the serialized Array<SignerSignature> of Argent accounts
+	// holding the signature of one signer: [1, variant, signer..., r.low,
+	// r.high, s.low, s.high, y_parity], the signer being the Ethereum address
+	// of a secp256k1 key or the u256 x coordinate of a secp256r1 key
+	LayoutArgent
+	// LayoutArgentEip191 is LayoutArgent for the EIP-191 signer of Argent
+	// accounts, a secp256k1 key signing the personal_sign message of the
+	// hash: [1, 3, eth_address, r.low, r.high, s.low, s.high, y_parity]
+	LayoutArgentEip191
+)
+
+// Variants of the SignerSignature enum of Argent accounts.
+const (
+	argentSecp256k1 = 1
+	argentSecp256r1 = 2
+	argentEip191    = 3
+)
+
+// ops is the arithmetic of a curve.
+type ops struct {
+	n        *big.Int
+	baseMult func(k *big.Int) (x, y *big.Int)
+	mult     func(x, y, k *big.Int) (rx, ry *big.Int)
+	add      func(x1, y1, x2, y2 *big.Int) (x, y *big.Int)
+	onCurve  func(x, y *big.Int) bool
+}
+
+var curves = map[Curve]ops{
+	Secp256k1: {
+		n: fr.Modulus(),
+		baseMult: func(k *big.Int) (*big.Int, *big.Int) {
+			var p secp256k1.G1Affine
+			p.ScalarMultiplicationBase(k)
+			return k1Coordinates(&p)
+		},
+		mult: func(x, y, k *big.Int) (*big.Int, *big.Int) {
+			var p secp256k1.G1Affine
+			p.ScalarMultiplication(k1Point(x, y), k)
+			return k1Coordinates(&p)
+		},
+		add: func(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+			var p secp256k1.G1Affine
+			p.Add(k1Point(x1, y1), k1Point(x2, y2))
+			return k1Coordinates(&p)
+		},
+		onCurve: func(x, y *big.Int) bool {
+			p := k1Point(x, y)
+			return !p.IsInfinity() && p.IsOnCurve()
+		},
+	},
+	Secp256r1: {
+		n:        elliptic.P256().Params().N,
+		baseMult: func(k *big.Int) (*big.Int, *big.Int) { return elliptic.P256().ScalarBaseMult(k.Bytes()) },
+		mult:     func(x, y, k *big.Int) (*big.Int, *big.Int) { return elliptic.P256().ScalarMult(x, y, k.Bytes()) },
+		add:      elliptic.P256().Add,
+		onCurve:  elliptic.P256().IsOnCurve,
+	},
+}
+
+// k1Point converts coordinates to a secp256k1 point, (0, 0) being the point
+// at infinity.
+//
+// Parameters:
+// - x, y: the coordinates
+// Returns:
+// - *secp256k1.G1Affine: the point
+func k1Point(x, y *big.Int) *secp256k1.G1Affine {
+	var p secp256k1.G1Affine
+	p.X.SetBigInt(x)
+	p.Y.SetBigInt(y)
+	return &p
+}
+
+// k1Coordinates returns the coordinates of a secp256k1 point.
+//
+// Parameters:
+// - p: the point
+// Returns:
+// - x, y: the coordinates, (0, 0) for the point at infinity
+func k1Coordinates(p *secp256k1.G1Affine) (*big.Int, *big.Int) {
+	return p.X.BigInt(new(big.Int)), p.Y.BigInt(new(big.Int))
+}
+
+// Signature is a secp256 signature.
+type Signature struct {
+	R *big.Int
+	S *big.Int
+	// YParity is the parity of the y coordinate of the point R, which
+	// recovers the public key
+	YParity bool
+}
+
+// PrivateKey is a secp256 private key.
+type PrivateKey struct {
+	curve Curve
+	d     *big.Int
+	// X and Y are the coordinates of the public key
+	X *big.Int
+	Y *big.Int
+}
+
+// NewPrivateKey returns the private key of a scalar.
+//
+// Parameters:
+// - curve: the curve of the key
+// - d: the scalar, 0 < d < N
+// Returns:
+// - *PrivateKey: the key
+// - error: ErrUnknownCurve or ErrInvalidKey
+func NewPrivateKey(curve Curve, d *big.Int) (*PrivateKey, error) {
+	c, ok := curves[curve]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCurve, curve)
+	}
+	if d.Sign() <= 0 || d.Cmp(c.n) >= 0 {
+		return nil, ErrInvalidKey
+	}
+	x, y := c.baseMult(d)
+	return &PrivateKey{curve: curve, d: new(big.Int).Set(d), X: x, Y: y}, nil
+}
+
+// GenerateKey generates a random private key.
+//
+// Parameters:
+// - curve: the curve of the key
+// Returns:
+// - *PrivateKey: the key
+// - error: ErrUnknownCurve, or an error of the random source
+func GenerateKey(curve Curve) (*PrivateKey, error) {
+	c, ok := curves[curve]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCurve, curve)
+	}
+	d, err := randomScalar(c.n)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKey(curve, d)
+}
+
+// Curve returns the curve of the key.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - Curve: the curve
+func (k *PrivateKey) Curve() Curve {
+	return k.curve
+}
+
+// Sign signs a hash, e.g. a transaction hash, with a random nonce. The
+// signature is normalized to s <= N/2.
+//
+// Parameters:
+// - hash: the hash
+// Returns:
+// - *Signature: the signature
+// - error: an error of the random source
+func (k *PrivateKey) Sign(hash *felt.Felt) (*Signature, error) {
+	return k.signDigest(hash.BigInt(new(big.Int)))
+}
+
+// signDigest signs a message of up to 256 bits, e.g. a hash computed off
+// chain like the EIP-191 hash of a transaction hash.
+//
+// Parameters:
+// - z: the message
+// Returns:
+// - *Signature: the normalized signature
+// - error: an error of the random source
+func (k *PrivateKey) signDigest(z *big.Int) (*Signature, error) {
+	c := curves[k.curve]
+	for {
+		nonce, err := randomScalar(c.n)
+		if err != nil {
+			return nil, err
+		}
+		rx, ry := c.baseMult(nonce)
+		r := new(big.Int).Mod(rx, c.n)
+		if r.Sign() == 0 {
+			continue
+		}
+		// s = k^-1 (z + r d)
+		s := new(big.Int).Mul(r, k.d)
+		s.Add(s, z)
+		s.Mul(s, new(big.Int).ModInverse(nonce, c.n))
+		s.Mod(s, c.n)
+		if s.Sign() == 0 {
+			continue
+		}
+		sig := &Signature{R: r, S: s, YParity: ry.Bit(0) == 1}
+		if s.Cmp(new(big.Int).Rsh(c.n, 1)) > 0 {
+			sig.S.Sub(c.n, s)
+			sig.YParity = !sig.YParity
+		}
+		return sig, nil
+	}
+}
+
+// Verify verifies a signature of a hash. The parity of the signature is not
+// checked.
+//
+// Parameters:
+// - curve: the curve of the key
+// - x, y: the coordinates of the public key
+// - hash: the hash
+// - sig: the signature
+// Returns:
+// - bool: true if the signature is valid
+func Verify(curve Curve, x, y *big.Int, hash *felt.Felt, sig *Signature) bool {
+	c, ok := curves[curve]
+	if !ok || sig == nil || sig.R == nil || sig.S == nil || !c.onCurve(x, y) {
+		return false
+	}
+	if sig.R.Sign() <= 0 || sig.R.Cmp(c.n) >= 0 || sig.S.Sign() <= 0 || sig.S.Cmp(c.n) >= 0 {
+		return false
+	}
+	rx, _ := pointR(c, x, y, hash.BigInt(new(big.Int)), sig.R, sig.S)
+	return rx != nil && new(big.Int).Mod(rx, c.n).Cmp(sig.R) == 0
+}
+
+// pointR computes the point R of a signature, s^-1 (z G + r Q), whose x
+// coordinate is r modulo N for a valid signature.
+//
+// Parameters:
+// - c: the curve
+// - x, y: the coordinates of the public key Q
+// - z: the signed message
+// - r, s: the signature, in [1, N)
+// Returns:
+// - rx, ry: the coordinates of R, nil for the point at infinity
+func pointR(c ops, x, y, z, r, s *big.Int) (rx, ry *big.Int) {
+	w := new(big.Int).ModInverse(s, c.n)
+	u1 := new(big.Int).Mul(z, w)
+	u1.Mod(u1, c.n)
+	u2 := new(big.Int).Mul(r, w)
+	u2.Mod(u2, c.n)
+	rx, ry = c.mult(x, y, u2)
+	if u1.Sign() != 0 {
+		x1, y1 := c.baseMult(u1)
+		rx, ry = c.add(x1, y1, rx, ry)
+	}
+	if rx.Sign() == 0 && ry.Sign() == 0 {
+		return nil, nil
+	}
+	return rx, ry
+}
+
+// EthAddress returns the Ethereum address of a secp256k1 public key, the last
+// 20 bytes of the keccak256 of its coordinates.
+//
+// Parameters:
+// - x, y: the coordinates of the public key
+// Returns:
+// - *felt.Felt: the address
+func EthAddress(x, y *big.Int) *felt.Felt {
+	var coordinates [64]byte
+	x.FillBytes(coordinates[:32])
+	y.FillBytes(coordinates[32:])
+	return new(felt.Felt).SetBytes(utils.Keccak256(coordinates[:])[12:])
+}
+
+// Felts returns the signature in the layout of an account class.
+//
+// Parameters:
+// - layout: the layout
+// - key: the public key of the signer, for LayoutArgent
+// Returns:
+// - []*felt.Felt: the signature
+// - error: ErrUnknownLayout
+func (sig *Signature) Felts(layout Layout, key *PrivateKey) ([]*felt.Felt, error) {
+	switch layout {
+	case LayoutRaw:
+		return append(u256(sig.R), u256(sig.S)...), nil
+	case LayoutArgent, LayoutArgentEip191:
+		signer, err := argentSigner(key, layout)
+		if err != nil {
+			return nil, err
+		}
+		return argentSignature(signer, sig), nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownLayout, layout)
+	}
+}
+
+// Signer is an account.Signer signing with a secp256 key.
+type Signer struct {
+	key    *PrivateKey
+	layout Layout
+}
+
+var _ account.Signer = &Signer{}
+
+// NewSigner returns a signer of an account.
+//
+// Parameters:
+// - key: the key of the account
+// - layout: the signature layout of the account class
+// Returns:
+// - *Signer: the signer
+func NewSigner(key *PrivateKey, layout Layout) *Signer {
+	return &Signer{key: key, layout: layout}
+}
+
+// SignHash signs a hash.
+//
+// Parameters:
+// - ctx: the context, unused
+// - hash: the hash
+// Returns:
+// - []*felt.Felt: the signature in the layout of the signer
+// - error: an error of the random source, or ErrUnknownLayout
+func (s *Signer) SignHash(_ context.Context, hash *felt.Felt) ([]*felt.Felt, error) {
+	digest := hash.BigInt(new(big.Int))
+	if s.layout == LayoutArgentEip191 {
+		personal := PersonalSignHash(hash)
+		digest.SetBytes(personal[:])
+	}
+	sig, err := s.key.signDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Felts(s.layout, s.key)
+}
+
+// Owner returns the owner of the account in the layout of the signer, as
+// passed to the constructor of the account class: the public key as a
+// Secp256k1Point [x.low, x.high, y.low, y.high] for LayoutRaw, the Signer
+// enum [variant, signer...] for LayoutArgent and LayoutArgentEip191.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*felt.Felt: the owner
+// - error: ErrUnknownLayout
+func (s *Signer) Owner() ([]*felt.Felt, error) {
+	switch s.layout {
+	case LayoutRaw:
+		return append(u256(s.key.X), u256(s.key.Y)...), nil
+	case LayoutArgent, LayoutArgentEip191:
+		return argentSigner(s.key, s.layout)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownLayout, s.layout)
+	}
+}
+
+// argentSigner returns the Signer enum of a key for Argent accounts.
+//
+// Parameters:
+// - key: the key
+// - layout: LayoutArgent or LayoutArgentEip191
+// Returns:
+// - []*felt.Felt: the variant and the signer
+// - error: ErrUnknownCurve, e.g. for an EIP-191 signer of a secp256r1 key
+func argentSigner(key *PrivateKey, layout Layout) ([]*felt.Felt, error) {
+	switch {
+	case key.curve == Secp256k1 && layout == LayoutArgentEip191:
+		return []*felt.Felt{new(felt.Felt).SetUint64(argentEip191), EthAddress(key.X, key.Y)}, nil
+	case layout == LayoutArgentEip191:
+		return nil, fmt.Errorf("%w: %d for an EIP-191 signer", ErrUnknownCurve, key.curve)
+	case key.curve == Secp256k1:
+		return []*felt.Felt{new(felt.Felt).SetUint64(argentSecp256k1), EthAddress(key.X, key.Y)}, nil
+	case key.curve == Secp256r1:
+		return append([]*felt.Felt{new(felt.Felt).SetUint64(argentSecp256r1)}, u256(key.X)...), nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCurve, key.curve)
+	}
+}
+
+// argentSignature returns the serialized Array<SignerSignature> of Argent
+// accounts holding the signature of one secp256 signer.
+//
+// Parameters:
+// - signer: the Signer enum of the signer
+// - sig: the signature
+// Returns:
+// - []*felt.Felt: the signature
+func argentSignature(signer []*felt.Felt, sig *Signature) []*felt.Felt {
+	signature := append([]*felt.Felt{new(felt.Felt).SetUint64(1)}, signer...)
+	signature = append(signature, u256(sig.R)...)
+	signature = append(signature, u256(sig.S)...)
+	return append(signature, boolFelt(sig.YParity))
+}
+
+// u256 returns an integer of at most 256 bits as a u256, [low, high].
+//
+// Parameters:
+// - v: the integer
+// Returns:
+// - []*felt.Felt: the low and high limbs
+func u256(v *big.Int) []*felt.Felt {
+	low := new(big.Int).And(v, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)))
+	return []*felt.Felt{new(felt.Felt).SetBigInt(low), new(felt.Felt).SetBigInt(new(big.Int).Rsh(v, 128))}
+}
+
+// randomScalar returns a random integer in [1, n).
+//
+// Parameters:
+// - n: the order of the curve
+// Returns:
+// - *big.Int: the integer
+// - error: an error of the random source
+func randomScalar(n *big.Int) (*big.Int, error) {
+	k, err := rand.Int(rand.Reader, new(big.Int).Sub(n, big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(k, big.NewInt(1)), nil
+}
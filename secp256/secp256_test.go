@@ -0,0 +1,243 @@
+package secp256
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestSign tests the signatures of both curves: their verification, their
+// normalization, the parity of their point R, and their rejection for another
+// hash or key.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestSign(t *testing.T) {
+	hash := utils.TestHexToFelt(t, "0x2dd76e7ad84dbed81c314ffe5e7a7cacfb8f4836f01af4e913f275f89a3de1a")
+	for _, curve := range []Curve{Secp256k1, Secp256r1} {
+		key, err := GenerateKey(curve)
+		require.NoError(t, err)
+		other, err := GenerateKey(curve)
+		require.NoError(t, err)
+		c := curves[curve]
+		for i := 0; i < 8; i++ {
+			sig, err := key.Sign(hash)
+			require.NoError(t, err)
+			require.True(t, Verify(curve, key.X, key.Y, hash, sig))
+			require.True(t, sig.S.Cmp(new(big.Int).Rsh(c.n, 1)) <= 0)
+			require.False(t, Verify(curve, key.X, key.Y, new(felt.Felt).SetUint64(1), sig))
+			require.False(t, Verify(curve, other.X, other.Y, hash, sig))
+
+			w := new(big.Int).ModInverse(sig.S, c.n)
+			x1, y1 := c.baseMult(new(big.Int).Mod(new(big.Int).Mul(hash.BigInt(new(big.Int)), w), c.n))
+			x2, y2 := c.mult(key.X, key.Y, new(big.Int).Mod(new(big.Int).Mul(sig.R, w), c.n))
+			_, ry := c.add(x1, y1, x2, y2)
+			require.Equal(t, sig.YParity, ry.Bit(0) == 1)
+		}
+	}
+
+	_, err := NewPrivateKey(Secp256k1, new(big.Int))
+	require.True(t, errors.Is(err, ErrInvalidKey))
+	_, err = GenerateKey(Curve(7))
+	require.True(t, errors.Is(err, ErrUnknownCurve))
+}
+
+// TestLayouts tests the Ethereum address of a key, the signature and owner
+// layouts of the account classes, and the signature of an account with a
+// secp256 signer.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestLayouts(t *testing.T) {
+	one, err := NewPrivateKey(Secp256k1, big.NewInt(1))
+	require.NoError(t, err)
+	require.Equal(t, utils.TestHexToFelt(t, "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf"), EthAddress(one.X, one.Y))
+
+	sig := &Signature{R: new(big.Int).Lsh(big.NewInt(3), 128), S: big.NewInt(5), YParity: true}
+	raw, err := sig.Felts(LayoutRaw, one)
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{utils.Uint64ToFelt(0), utils.Uint64ToFelt(3), utils.Uint64ToFelt(5), utils.Uint64ToFelt(0)}, raw)
+	argent, err := sig.Felts(LayoutArgent, one)
+	require.NoError(t, err)
+	require.Equal(t, append([]*felt.Felt{utils.Uint64ToFelt(1), utils.Uint64ToFelt(1), EthAddress(one.X, one.Y)}, append(raw, utils.Uint64ToFelt(1))...), argent)
+	_, err = sig.Felts(Layout(9), one)
+	require.True(t, errors.Is(err, ErrUnknownLayout))
+
+	r1, err := GenerateKey(Secp256r1)
+	require.NoError(t, err)
+	owner, err := NewSigner(r1, LayoutArgent).Owner()
+	require.NoError(t, err)
+	require.Equal(t, append([]*felt.Felt{utils.Uint64ToFelt(2)}, u256(r1.X)...), owner)
+	point, err := NewSigner(one, LayoutRaw).Owner()
+	require.NoError(t, err)
+	require.Len(t, point, 4)
+	require.Equal(t, one.X, new(big.Int).Add(point[0].BigInt(new(big.Int)), new(big.Int).Lsh(point[1].BigInt(new(big.Int)), 128)))
+
+	acnt, err := account.NewAccount(nil, utils.TestHexToFelt(t, "0xacc"), "", nil, 2, account.WithChainID("SN_SEPOLIA"), account.WithSigner(NewSigner(r1, LayoutArgent)))
+	require.NoError(t, err)
+	hash := utils.TestHexToFelt(t, "0x1234")
+	signature, err := acnt.Sign(context.Background(), hash)
+	require.NoError(t, err)
+	require.Len(t, signature, 9)
+	require.Equal(t, owner, signature[1:4])
+	r := new(big.Int).Add(signature[4].BigInt(new(big.Int)), new(big.Int).Lsh(signature[5].BigInt(new(big.Int)), 128))
+	s := new(big.Int).Add(signature[6].BigInt(new(big.Int)), new(big.Int).Lsh(signature[7].BigInt(new(big.Int)), 128))
+	require.True(t, Verify(Secp256r1, r1.X, r1.Y, hash, &Signature{R: r, S: s}))
+}
+
+// TestWebauthn tests the assembly of the signature of an Argent passkey
+// signer from an assertion signed like a browser does, and the rejection of
+// assertions of another hash, origin or relying party, or with an invalid
+// signature.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestWebauthn(t *testing.T) {
+	passkey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer := NewWebauthnSigner("https://app.example.com", "example.com", passkey.X, passkey.Y)
+	hash := utils.TestHexToFelt(t, "0x2dd76e7ad84dbed81c314ffe5e7a7cacfb8f4836f01af4e913f275f89a3de1a")
+
+	assert := func(challenge, origin string, rpID string) Assertion {
+		rpIDHash := sha256.Sum256([]byte(rpID))
+		auth := append(rpIDHash[:], 0x05, 0, 0, 0, 9)
+		clientData := []byte(`{"type":"webauthn.get","challenge":"` + challenge + `","origin":"` + origin + `","crossOrigin":false,"other_keys_can_be_added_here":"x"}`)
+		clientDataHash := sha256.Sum256(clientData)
+		digest := sha256.Sum256(append(append([]byte{}, auth...), clientDataHash[:]...))
+		der, err := ecdsa.SignASN1(rand.Reader, passkey, digest[:])
+		require.NoError(t, err)
+		r, s, err := ParseDERSignature(der)
+		require.NoError(t, err)
+		return Assertion{AuthenticatorData: auth, ClientDataJSON: clientData, R: r, S: s}
+	}
+
+	assertion := assert(Challenge(hash, Sha256Cairo1), "https://app.example.com", "example.com")
+	signature, err := signer.Signature(hash, assertion)
+	require.NoError(t, err)
+	owner := signer.Owner()
+	require.Equal(t, utils.Uint64ToFelt(4), owner[0])
+	require.Equal(t, utils.Uint64ToFelt(uint64(len("https://app.example.com"))), owner[1])
+	require.Len(t, owner, 2+len("https://app.example.com")+4)
+	require.Equal(t, owner, signature[1:1+len(owner)])
+	outro := `,"other_keys_can_be_added_here":"x"`
+	rest := signature[1+len(owner):]
+	require.Equal(t, utils.Uint64ToFelt(0), rest[0])
+	require.Equal(t, utils.Uint64ToFelt(uint64(len(outro))), rest[1])
+	rest = rest[2+len(outro):]
+	require.Equal(t, []*felt.Felt{utils.Uint64ToFelt(5), utils.Uint64ToFelt(9)}, rest[:2])
+	require.Len(t, rest, 2+4+2)
+	require.Equal(t, utils.Uint64ToFelt(uint64(Sha256Cairo1)), rest[7])
+	s := new(big.Int).Add(rest[4].BigInt(new(big.Int)), new(big.Int).Lsh(rest[5].BigInt(new(big.Int)), 128))
+	require.True(t, s.Cmp(new(big.Int).Rsh(elliptic.P256().Params().N, 1)) <= 0)
+
+	for name, bad := range map[string]Assertion{
+		"hash":   assert(Challenge(new(felt.Felt).SetUint64(1), Sha256Cairo0), "https://app.example.com", "example.com"),
+		"origin": assert(Challenge(hash, Sha256Cairo0), "https://evil.example.com", "example.com"),
+		"rp":     assert(Challenge(hash, Sha256Cairo0), "https://app.example.com", "evil.com"),
+	} {
+		_, err = signer.Signature(hash, bad)
+		require.True(t, errors.Is(err, ErrInvalidAssertion), name)
+	}
+	tampered := assertion
+	tampered.S = new(big.Int).Add(assertion.S, big.NewInt(1))
+	_, err = signer.Signature(hash, tampered)
+	require.True(t, errors.Is(err, ErrInvalidAssertion))
+	_, _, err = ParseDERSignature([]byte{0x30, 0x01})
+	require.True(t, errors.Is(err, ErrInvalidAssertion))
+}
+
+// TestEth tests the EIP-191 and EIP-712 payloads of a Starknet hash, the
+// parsing of a wallet signature and the recovery of its address, and the
+// signature of an Argent EIP-191 signer.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestEth(t *testing.T) {
+	key, err := GenerateKey(Secp256k1)
+	require.NoError(t, err)
+	address := EthAddress(key.X, key.Y)
+	hash := utils.TestHexToFelt(t, "0x2dd76e7ad84dbed81c314ffe5e7a7cacfb8f4836f01af4e913f275f89a3de1a")
+	require.Equal(t, utils.TestHexToFelt(t, "0x"+fmt.Sprintf("%x", PersonalSignMessage(hash))), hash)
+	require.Len(t, PersonalSignMessage(hash), 32)
+
+	typed := EIP712{Domain: EIP712Domain{Name: "Starknet", Version: "1", ChainID: big.NewInt(1)}, PrimaryType: "StarknetTransaction", Member: "hash"}
+	data, err := typed.TypedData(hash)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"primaryType":"StarknetTransaction"`)
+	require.Contains(t, string(data), `"message":{"hash":"0x02dd76e7ad84dbed81c314ffe5e7a7cacfb8f4836f01af4e913f275f89a3de1a"}`)
+	require.Contains(t, string(data), `"StarknetTransaction":[{"name":"hash","type":"bytes32"}]`)
+	other := typed
+	other.Domain.ChainID = big.NewInt(5)
+	require.NotEqual(t, typed.Hash(hash), other.Hash(hash))
+
+	for _, digest := range [][32]byte{PersonalSignHash(hash), typed.Hash(hash)} {
+		sig, err := key.signDigest(new(big.Int).SetBytes(digest[:]))
+		require.NoError(t, err)
+		wallet := make([]byte, 65)
+		sig.R.FillBytes(wallet[:32])
+		sig.S.FillBytes(wallet[32:64])
+		wallet[64] = 27
+		if sig.YParity {
+			wallet[64] = 28
+		}
+		parsed, err := ParseEthSignature(wallet)
+		require.NoError(t, err)
+		require.Equal(t, sig, parsed)
+		recovered, err := RecoverEthAddress(digest, parsed)
+		require.NoError(t, err)
+		require.Equal(t, address, recovered)
+		parsed.YParity = !parsed.YParity
+		recovered, err = RecoverEthAddress(digest, parsed)
+		require.NoError(t, err)
+		require.NotEqual(t, address, recovered)
+	}
+	_, err = ParseEthSignature(make([]byte, 64))
+	require.True(t, errors.Is(err, ErrInvalidSignature))
+	_, err = ParseEthSignature(append(make([]byte, 64), 29))
+	require.True(t, errors.Is(err, ErrInvalidSignature))
+
+	signer := NewSigner(key, LayoutArgentEip191)
+	owner, err := signer.Owner()
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{utils.Uint64ToFelt(3), address}, owner)
+	signature, err := signer.SignHash(context.Background(), hash)
+	require.NoError(t, err)
+	require.Len(t, signature, 8)
+	sig := &Signature{
+		R:       new(big.Int).Add(signature[3].BigInt(new(big.Int)), new(big.Int).Lsh(signature[4].BigInt(new(big.Int)), 128)),
+		S:       new(big.Int).Add(signature[5].BigInt(new(big.Int)), new(big.Int).Lsh(signature[6].BigInt(new(big.Int)), 128)),
+		YParity: signature[7].IsOne(),
+	}
+	require.Equal(t, Eip191Signature(address, sig), signature)
+	recovered, err := RecoverEthAddress(PersonalSignHash(hash), sig)
+	require.NoError(t, err)
+	require.Equal(t, address, recovered)
+
+	r1, err := GenerateKey(Secp256r1)
+	require.NoError(t, err)
+	_, err = NewSigner(r1, LayoutArgentEip191).Owner()
+	require.True(t, errors.Is(err, ErrUnknownCurve))
+}
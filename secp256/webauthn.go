@@ -0,0 +1,251 @@
+package secp256
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var ErrInvalidAssertion = errors.New("secp256: invalid webauthn assertion")
+
+// Sha256Implementation is the SHA-256 implementation an Argent account uses
+// to hash the client data of a WebAuthn assertion, chosen by the signer and
+// encoded in the challenge.
+type Sha256Implementation int
+
+const (
+	Sha256Cairo0 Sha256Implementation = iota
+	Sha256Cairo1
+)
+
+// argentWebauthn is the variant of the WebAuthn signer of the SignerSignature
+// enum of Argent accounts.
+const argentWebauthn = 4
+
+// authenticatorDataLength is the length of authenticator data without
+// attested credential data nor extensions: the hash of the relying party ID,
+// the flags and the signature counter.
+const authenticatorDataLength = 37
+
+// Assertion is a WebAuthn assertion, as returned by
+// navigator.credentials.get in the browser.
+type Assertion struct {
+	AuthenticatorData []byte
+	ClientDataJSON    []byte
+	// R and S are the signature, e.g. parsed with ParseDERSignature
+	R *big.Int
+	S *big.Int
+}
+
+// WebauthnSigner is the passkey signer of an Argent account, a secp256r1 key
+// bound to an origin and a relying party. The signatures are assembled from
+// assertions made by the browser; Braavos accounts use another layout.
+type WebauthnSigner struct {
+	Origin   string
+	RpIDHash [32]byte
+	// X and Y are the coordinates of the public key of the passkey
+	X *big.Int
+	Y *big.Int
+}
+
+// NewWebauthnSigner returns the signer of a passkey.
+//
+// Parameters:
+// - origin: the origin of the page requesting the assertions, e.g. "https://app.example.com"
+// - rpID: the relying party ID of the passkey, e.g. "example.com"
+// - x, y: the coordinates of the public key of the passkey
+// Returns:
+// - *WebauthnSigner: the signer
+func NewWebauthnSigner(origin, rpID string, x, y *big.Int) *WebauthnSigner {
+	return &WebauthnSigner{Origin: origin, RpIDHash: sha256.Sum256([]byte(rpID)), X: x, Y: y}
+}
+
+// Challenge returns the challenge of the assertion signing a hash, to pass to
+// navigator.credentials.get: the base64url of the hash followed by the byte of
+// the SHA-256 implementation.
+//
+// Parameters:
+// - hash: the hash, e.g. a transaction hash
+// - impl: the SHA-256 implementation
+// Returns:
+// - string: the challenge
+func Challenge(hash *felt.Felt, impl Sha256Implementation) string {
+	bytes := hash.Bytes()
+	return base64.RawURLEncoding.EncodeToString(append(bytes[:], byte(impl)))
+}
+
+// ParseDERSignature parses the ASN.1 DER signature of an assertion.
+//
+// Parameters:
+// - der: the signature
+// Returns:
+// - r, s: the components of the signature
+// - error: ErrInvalidAssertion if the signature is not DER
+func ParseDERSignature(der []byte) (r, s *big.Int, err error) {
+	var sig struct{ R, S *big.Int }
+	rest, err := asn1.Unmarshal(der, &sig)
+	if err != nil || len(rest) != 0 {
+		return nil, nil, fmt.Errorf("%w: signature is not DER", ErrInvalidAssertion)
+	}
+	return sig.R, sig.S, nil
+}
+
+// Owner returns the Signer enum of the passkey, as passed to the constructor
+// of Argent accounts or to change_owner: [4, origin, rp_id_hash, pubkey],
+// the origin as a Span<u8> and the hash and the x coordinate as u256.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*felt.Felt: the owner
+func (w *WebauthnSigner) Owner() []*felt.Felt {
+	owner := []*felt.Felt{new(felt.Felt).SetUint64(argentWebauthn)}
+	owner = append(owner, byteSpan([]byte(w.Origin))...)
+	owner = append(owner, u256(new(big.Int).SetBytes(w.RpIDHash[:]))...)
+	return append(owner, u256(w.X)...)
+}
+
+// Signature assembles the signature of a hash from an assertion of the
+// passkey, in the layout of Argent accounts: the serialized
+// Array<SignerSignature> holding the Owner and the WebauthnSignature
+// [cross_origin, client_data_json_outro, flags, sign_count, r, s, y_parity,
+// sha256_implementation]. The assertion is checked as the account checks it:
+// the client data must be the JSON of the challenge of the hash and of the
+// origin, the authenticator data must be of the relying party, and the
+// signature must verify with the public key.
+//
+// Parameters:
+// - hash: the signed hash
+// - assertion: the assertion
+// Returns:
+// - []*felt.Felt: the signature
+// - error: ErrInvalidAssertion
+func (w *WebauthnSigner) Signature(hash *felt.Felt, assertion Assertion) ([]*felt.Felt, error) {
+	auth := assertion.AuthenticatorData
+	if len(auth) != authenticatorDataLength {
+		return nil, fmt.Errorf("%w: authenticator data of %d bytes, expected %d", ErrInvalidAssertion, len(auth), authenticatorDataLength)
+	}
+	if !bytes.Equal(auth[:32], w.RpIDHash[:]) {
+		return nil, fmt.Errorf("%w: authenticator data of another relying party", ErrInvalidAssertion)
+	}
+	flags := auth[32]
+	signCount := binary.BigEndian.Uint32(auth[33:])
+
+	impl, crossOrigin, outro, err := w.parseClientData(hash, assertion.ClientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(assertion.ClientDataJSON)
+	digest := sha256.Sum256(append(append([]byte{}, auth...), clientDataHash[:]...))
+	sig, err := signatureWithParity(w.X, w.Y, new(big.Int).SetBytes(digest[:]), assertion.R, assertion.S)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := append([]*felt.Felt{new(felt.Felt).SetUint64(1)}, w.Owner()...)
+	signature = append(signature, boolFelt(crossOrigin))
+	signature = append(signature, byteSpan(outro)...)
+	signature = append(signature, new(felt.Felt).SetUint64(uint64(flags)), new(felt.Felt).SetUint64(uint64(signCount)))
+	signature = append(signature, u256(sig.R)...)
+	signature = append(signature, u256(sig.S)...)
+	return append(signature, boolFelt(sig.YParity), new(felt.Felt).SetUint64(uint64(impl))), nil
+}
+
+// parseClientData checks the client data of an assertion, which Argent
+// accounts rebuild as {"type":"webauthn.get","challenge":"...","origin":"...",
+// "crossOrigin":...} followed by an outro of other members.
+//
+// Parameters:
+// - hash: the signed hash
+// - clientData: the client data JSON
+// Returns:
+// - Sha256Implementation: the SHA-256 implementation of the challenge
+// - bool: the cross origin flag
+// - []byte: the outro, without the closing brace
+// - error: ErrInvalidAssertion
+func (w *WebauthnSigner) parseClientData(hash *felt.Felt, clientData []byte) (Sha256Implementation, bool, []byte, error) {
+	for _, impl := range []Sha256Implementation{Sha256Cairo0, Sha256Cairo1} {
+		for _, crossOrigin := range []bool{false, true} {
+			prefix := fmt.Sprintf(`{"type":"webauthn.get","challenge":"%s","origin":"%s","crossOrigin":%t`, Challenge(hash, impl), w.Origin, crossOrigin)
+			if !bytes.HasPrefix(clientData, []byte(prefix)) {
+				continue
+			}
+			outro := clientData[len(prefix):]
+			if len(outro) == 0 || outro[len(outro)-1] != '}' {
+				return 0, false, nil, fmt.Errorf("%w: client data not closed", ErrInvalidAssertion)
+			}
+			outro = outro[:len(outro)-1]
+			if len(outro) > 0 && outro[0] != ',' {
+				return 0, false, nil, fmt.Errorf("%w: invalid client data outro", ErrInvalidAssertion)
+			}
+			return impl, crossOrigin, outro, nil
+		}
+	}
+	return 0, false, nil, fmt.Errorf("%w: client data not of the hash and origin", ErrInvalidAssertion)
+}
+
+// signatureWithParity verifies a secp256r1 signature of a digest, and returns
+// its normalized form with the parity of its point R.
+//
+// Parameters:
+// - x, y: the coordinates of the public key
+// - z: the digest
+// - r, s: the signature
+// Returns:
+// - *Signature: the normalized signature
+// - error: ErrInvalidAssertion if the signature does not verify
+func signatureWithParity(x, y, z, r, s *big.Int) (*Signature, error) {
+	c := curves[Secp256r1]
+	if r == nil || s == nil || r.Sign() <= 0 || r.Cmp(c.n) >= 0 || s.Sign() <= 0 || s.Cmp(c.n) >= 0 || !c.onCurve(x, y) {
+		return nil, fmt.Errorf("%w: signature out of range", ErrInvalidAssertion)
+	}
+	rx, ry := pointR(c, x, y, z, r, s)
+	if rx == nil || new(big.Int).Mod(rx, c.n).Cmp(r) != 0 {
+		return nil, fmt.Errorf("%w: signature does not verify", ErrInvalidAssertion)
+	}
+	sig := &Signature{R: new(big.Int).Set(r), S: new(big.Int).Set(s), YParity: ry.Bit(0) == 1}
+	if s.Cmp(new(big.Int).Rsh(c.n, 1)) > 0 {
+		sig.S.Sub(c.n, s)
+		sig.YParity = !sig.YParity
+	}
+	return sig, nil
+}
+
+// boolFelt returns a bool as a felt.
+//
+// Parameters:
+// - b: the bool
+// Returns:
+// - *felt.Felt: 1 for true, 0 for false
+func boolFelt(b bool) *felt.Felt {
+	if b {
+		return new(felt.Felt).SetUint64(1)
+	}
+	return new(felt.Felt)
+}
+
+// byteSpan returns bytes as a serialized Span<u8>, the length then a felt
+// per byte.
+//
+// Parameters:
+// - b: the bytes
+// Returns:
+// - []*felt.Felt: the span
+func byteSpan(b []byte) []*felt.Felt {
+	span := make([]*felt.Felt, 0, len(b)+1)
+	span = append(span, new(felt.Felt).SetUint64(uint64(len(b))))
+	for _, c := range b {
+		span = append(span, new(felt.Felt).SetUint64(uint64(c)))
+	}
+	return span
+}
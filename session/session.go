@@ -0,0 +1,441 @@
+// Package session issues and uses session keys. A session key is a keypair
+// held by a dapp that the owner of an account authorizes, through a signed
+// session, to call a fixed set of contract entrypoints until an expiry time.
+// Transactions signed with the session key carry the session and a merkle
+// proof for each call, which the account checks against the session root.
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/merkle"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var (
+	ErrNoPolicies     = errors.New("session: no policies")
+	ErrSessionExpired = errors.New("session: expired")
+	ErrNotAllowed     = errors.New("session: call not allowed by the session policies")
+)
+
+// SNIP-12 revision 0 type hashes of the session typed data.
+var (
+	domainTypeHash  = utils.GetSelectorFromNameFelt("StarkNetDomain(chainId:felt)")
+	sessionTypeHash = utils.GetSelectorFromNameFelt("Session(key:felt,expires:felt,root:merkletree)")
+	policyTypeHash  = utils.GetSelectorFromNameFelt("Policy(contractAddress:felt,selector:selector)")
+	messagePrefix   = new(felt.Felt).SetBytes([]byte("StarkNet Message"))
+)
+
+// Policy allows the session key to call an entrypoint of a contract.
+type Policy struct {
+	ContractAddress *felt.Felt
+	Selector        *felt.Felt
+}
+
+// NewPolicy creates the policy allowing calls to an entrypoint.
+//
+// Parameters:
+// - contractAddress: the address of the contract
+// - entrypoint: the name of the entrypoint
+// Returns:
+// - Policy: the policy
+func NewPolicy(contractAddress *felt.Felt, entrypoint string) Policy {
+	return Policy{ContractAddress: contractAddress, Selector: utils.GetSelectorFromNameFelt(entrypoint)}
+}
+
+// leaf returns the hash of the policy, a leaf of the session merkle tree.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *big.Int: the leaf
+// - error: an error if the hash fails
+func (p Policy) leaf() (*big.Int, error) {
+	leaf, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{policyTypeHash, p.ContractAddress, p.Selector})
+	if err != nil {
+		return nil, err
+	}
+	return utils.FeltToBigInt(leaf), nil
+}
+
+// Session is the authorization of a session key.
+type Session struct {
+	// Key is the public key of the session keypair
+	Key *felt.Felt
+	// Expires is the unix time the session expires at
+	Expires uint64
+	// Policies are the calls the session key may make
+	Policies []Policy
+}
+
+// tree builds the merkle tree of the session policies.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *merkle.FixedSizeMerkleTree: the tree
+// - error: an error if there are no policies or the hash fails
+func (s *Session) tree() (*merkle.FixedSizeMerkleTree, error) {
+	if len(s.Policies) == 0 {
+		return nil, ErrNoPolicies
+	}
+	leaves := make([]*big.Int, len(s.Policies))
+	for i, policy := range s.Policies {
+		leaf, err := policy.leaf()
+		if err != nil {
+			return nil, err
+		}
+		leaves[i] = leaf
+	}
+	return merkle.NewFixedSizeMerkleTree(leaves...)
+}
+
+// Root returns the root of the merkle tree of the session policies.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the root
+// - error: an error if there are no policies or the hash fails
+func (s *Session) Root() (*felt.Felt, error) {
+	tree, err := s.tree()
+	if err != nil {
+		return nil, err
+	}
+	return utils.BigIntToFelt(tree.Root), nil
+}
+
+// MessageHash returns the SNIP-12 revision 0 hash of the session, the message
+// the owner of the account signs.
+//
+// Parameters:
+// - chainID: the chain ID
+// - accountAddress: the address of the account
+// Returns:
+// - *felt.Felt: the message hash
+// - error: an error if there are no policies or the hash fails
+func (s *Session) MessageHash(chainID, accountAddress *felt.Felt) (*felt.Felt, error) {
+	root, err := s.Root()
+	if err != nil {
+		return nil, err
+	}
+	domainHash, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{domainTypeHash, chainID})
+	if err != nil {
+		return nil, err
+	}
+	sessionHash, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{sessionTypeHash, s.Key, utils.Uint64ToFelt(s.Expires), root})
+	if err != nil {
+		return nil, err
+	}
+	return hash.ComputeHashOnElementsFelt([]*felt.Felt{messagePrefix, domainHash, accountAddress, sessionHash})
+}
+
+// SignedSession is a session signed by the owner of the account.
+type SignedSession struct {
+	Session
+	// Account is the address of the account the session is issued for
+	Account *felt.Felt
+	// Signature is the signature of the owner over the session message hash
+	Signature []*felt.Felt
+}
+
+// Issue has the owner of an account authorize a session key.
+//
+// Parameters:
+// - ctx: the context
+// - owner: the account, signing with its owner key
+// - key: the public key of the session keypair
+// - expires: the time the session expires at
+// - policies: the calls the session key may make
+// Returns:
+// - *SignedSession: the signed session, to hand to the dapp
+// - error: an error if there are no policies or the signature fails
+func Issue(ctx context.Context, owner *account.Account, key *felt.Felt, expires time.Time, policies []Policy) (*SignedSession, error) {
+	session := Session{Key: key, Expires: uint64(expires.Unix()), Policies: policies}
+	msgHash, err := session.MessageHash(owner.ChainId, owner.AccountAddress)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := owner.Sign(ctx, msgHash)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedSession{Session: session, Account: owner.AccountAddress, Signature: signature}, nil
+}
+
+// Format lays out the transactions of a session key for an account
+// implementation.
+type Format interface {
+	// Calls returns the calls of the transaction executing the calls
+	Calls(signed *SignedSession, calls []rpc.FunctionCall) []rpc.FunctionCall
+	// Signature returns the transaction signature from the signature of the
+	// session key and the proofs of the calls
+	Signature(signed *SignedSession, root *felt.Felt, txSignature []*felt.Felt, proofs [][]*felt.Felt) []*felt.Felt
+}
+
+// ArgentFormat is the layout of the Argent session key plugin. The calls are
+// preceded by a use_plugin call to the account and the signature is
+//
+//	[session signature..., key, expires, root, proof length, proofs..., owner signature...]
+//
+// where every proof has the same length, the depth of the merkle tree.
+type ArgentFormat struct {
+	// PluginClassHash is the class hash of the session key plugin
+	PluginClassHash *felt.Felt
+}
+
+// Calls implements Format.
+//
+// Parameters:
+// - signed: the session
+// - calls: the calls
+// Returns:
+// - []rpc.FunctionCall: the use_plugin call followed by the calls
+func (f ArgentFormat) Calls(signed *SignedSession, calls []rpc.FunctionCall) []rpc.FunctionCall {
+	usePlugin := rpc.FunctionCall{
+		ContractAddress:    signed.Account,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("use_plugin"),
+		Calldata:           []*felt.Felt{f.PluginClassHash},
+	}
+	return append([]rpc.FunctionCall{usePlugin}, calls...)
+}
+
+// Signature implements Format.
+//
+// Parameters:
+// - signed: the session
+// - root: the root of the policies
+// - txSignature: the signature of the session key over the transaction hash
+// - proofs: the proofs of the calls
+// Returns:
+// - []*felt.Felt: the transaction signature
+func (f ArgentFormat) Signature(signed *SignedSession, root *felt.Felt, txSignature []*felt.Felt, proofs [][]*felt.Felt) []*felt.Felt {
+	signature := append([]*felt.Felt{}, txSignature...)
+	proofLen := 0
+	if len(proofs) > 0 {
+		proofLen = len(proofs[0])
+	}
+	signature = append(signature, signed.Key, utils.Uint64ToFelt(signed.Expires), root, utils.Uint64ToFelt(uint64(proofLen)))
+	for _, proof := range proofs {
+		signature = append(signature, proof...)
+	}
+	return append(signature, signed.Signature...)
+}
+
+// BraavosFormat is the layout of the session keys of the Braavos account,
+// which validates them in the account itself. The calls are sent unchanged
+// and the signature is
+//
+//	[key, expires, root, owner signature length, owner signature..., proof count, (proof length, proof...)..., session signature...]
+//
+// so that the owner signature may be a multi-signer signature and the proofs
+// may have different lengths.
+type BraavosFormat struct{}
+
+// Calls implements Format.
+//
+// Parameters:
+// - signed: the session
+// - calls: the calls
+// Returns:
+// - []rpc.FunctionCall: the calls
+func (BraavosFormat) Calls(signed *SignedSession, calls []rpc.FunctionCall) []rpc.FunctionCall {
+	return calls
+}
+
+// Signature implements Format.
+//
+// Parameters:
+// - signed: the session
+// - root: the root of the policies
+// - txSignature: the signature of the session key over the transaction hash
+// - proofs: the proofs of the calls
+// Returns:
+// - []*felt.Felt: the transaction signature
+func (BraavosFormat) Signature(signed *SignedSession, root *felt.Felt, txSignature []*felt.Felt, proofs [][]*felt.Felt) []*felt.Felt {
+	signature := []*felt.Felt{signed.Key, utils.Uint64ToFelt(signed.Expires), root, utils.Uint64ToFelt(uint64(len(signed.Signature)))}
+	signature = append(signature, signed.Signature...)
+	signature = append(signature, utils.Uint64ToFelt(uint64(len(proofs))))
+	for _, proof := range proofs {
+		signature = append(signature, utils.Uint64ToFelt(uint64(len(proof))))
+		signature = append(signature, proof...)
+	}
+	return append(signature, txSignature...)
+}
+
+// proofsKey is the context key of the proofs of the calls of the
+// transaction being signed.
+type proofsKey struct{}
+
+// signer is the account.Signer of a session account, laying out the
+// signatures of its transactions with the session and the proofs of their
+// calls.
+type signer struct {
+	keys    *account.Account
+	session *SignedSession
+	root    *felt.Felt
+	format  Format
+}
+
+// SignHash signs a hash with the session key. The signature of a transaction
+// hash, whose proofs are in ctx, is laid out by the format.
+//
+// Parameters:
+// - ctx: the context, carrying the proofs of the calls of a transaction
+// - hash: the hash
+// Returns:
+// - []*felt.Felt: the signature
+// - error: an error if the signature fails
+func (s *signer) SignHash(ctx context.Context, hash *felt.Felt) ([]*felt.Felt, error) {
+	signature, err := s.keys.Sign(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	proofs, ok := ctx.Value(proofsKey{}).([][]*felt.Felt)
+	if !ok {
+		return signature, nil
+	}
+	return s.format.Signature(s.session, s.root, signature, proofs), nil
+}
+
+// Account is an account signing with a session key. Its transactions are
+// built, estimated and sent by account.Account, with the calls and the
+// signature laid out by the format.
+type Account struct {
+	*account.Account
+	session *SignedSession
+	tree    *merkle.FixedSizeMerkleTree
+	format  Format
+	now     func() time.Time
+}
+
+// NewAccount creates an account signing with the session key. The keystore
+// holds the private key of the session keypair.
+//
+// Parameters:
+// - provider: the provider
+// - signed: the session issued by the owner of the account
+// - ks: the keystore of the session key
+// - format: the layout of the account implementation
+// - cairoVersion: the Cairo version of the account
+// Returns:
+// - *Account: the session account
+// - error: an error if there are no policies or the provider fails
+func NewAccount(provider rpc.RpcProvider, signed *SignedSession, ks account.Keystore, format Format, cairoVersion int) (*Account, error) {
+	tree, err := signed.tree()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := account.NewAccount(provider, signed.Account, signed.Key.String(), ks, cairoVersion)
+	if err != nil {
+		return nil, err
+	}
+	acc := *keys
+	account.WithSigner(&signer{keys: keys, session: signed, root: utils.BigIntToFelt(tree.Root), format: format})(&acc)
+	return &Account{Account: &acc, session: signed, tree: tree, format: format, now: time.Now}, nil
+}
+
+// proofs returns the merkle proofs of the policies allowing the calls.
+//
+// Parameters:
+// - calls: the calls
+// Returns:
+// - [][]*felt.Felt: the proofs
+// - error: ErrNotAllowed if a call is not allowed by the policies
+func (a *Account) proofs(calls []rpc.FunctionCall) ([][]*felt.Felt, error) {
+	proofs := make([][]*felt.Felt, len(calls))
+	for i, call := range calls {
+		leaf, err := Policy{ContractAddress: call.ContractAddress, Selector: call.EntryPointSelector}.leaf()
+		if err != nil {
+			return nil, err
+		}
+		path, err := a.tree.Proof(leaf)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s on %s", ErrNotAllowed, call.EntryPointSelector, call.ContractAddress)
+		}
+		proof := make([]*felt.Felt, len(path))
+		for j, node := range path {
+			proof[j] = utils.BigIntToFelt(node)
+		}
+		proofs[i] = proof
+	}
+	return proofs, nil
+}
+
+// authorize checks that the session allows the calls, and returns the
+// context signing the transactions of the calls and the calls laid out by
+// the format.
+//
+// Parameters:
+// - ctx: the context
+// - calls: the calls
+// Returns:
+// - context.Context: the context, carrying the proofs of the calls
+// - []rpc.FunctionCall: the calls of the transaction
+// - error: ErrSessionExpired, or ErrNotAllowed if a call is not allowed by the policies
+func (a *Account) authorize(ctx context.Context, calls []rpc.FunctionCall) (context.Context, []rpc.FunctionCall, error) {
+	if uint64(a.now().Unix()) >= a.session.Expires {
+		return nil, nil, ErrSessionExpired
+	}
+	proofs, err := a.proofs(calls)
+	if err != nil {
+		return nil, nil, err
+	}
+	return context.WithValue(ctx, proofsKey{}, proofs), a.format.Calls(a.session, calls), nil
+}
+
+// BuildInvokeTxn builds the V1 invoke transaction executing the calls and
+// signs it with the session key.
+//
+// Parameters:
+// - ctx: the context
+// - calls: the calls, which must be allowed by the session policies
+// - nonce: the nonce, read from the pending block when nil
+// - maxFee: the max fee, zero when nil
+// Returns:
+// - *rpc.InvokeTxnV1: the signed transaction
+// - error: an error if the session expired, a call is not allowed or the signature fails
+func (a *Account) BuildInvokeTxn(ctx context.Context, calls []rpc.FunctionCall, nonce, maxFee *felt.Felt) (*rpc.InvokeTxnV1, error) {
+	ctx, txCalls, err := a.authorize(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+	return a.Account.BuildInvokeTxn(ctx, txCalls, nonce, maxFee)
+}
+
+// Execute sends the calls in an invoke transaction signed with the session
+// key, as account.Account.Execute does with its options: the fee is
+// estimated with the query version unless set, and a tip or resource bounds
+// send a V3 transaction.
+//
+// Parameters:
+// - ctx: the context
+// - calls: the calls, which must be allowed by the session policies
+// - opts: the execution options
+// Returns:
+// - *rpc.AddInvokeTransactionResponse: the response of the provider
+// - error: an error if the session expired, a call is not allowed, or an error of account.Account.Execute
+func (a *Account) Execute(ctx context.Context, calls []rpc.FunctionCall, opts ...account.ExecuteOption) (*rpc.AddInvokeTransactionResponse, error) {
+	if len(calls) == 0 {
+		return nil, account.ErrNoCalls
+	}
+	ctx, txCalls, err := a.authorize(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+	return a.Account.Execute(ctx, txCalls, opts...)
+}
@@ -0,0 +1,155 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/curve"
+	"github.com/xiang-xx/starknet.go/merkle"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// verify checks a signature with the public key.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// - msgHash: the signed message hash
+// - pub: the public key
+// - signature: the signature
+// Returns:
+//
+//	none
+func verify(t *testing.T, msgHash, pub *felt.Felt, signature []*felt.Felt) {
+	pubY := curve.Curve.GetYCoordinate(utils.FeltToBigInt(pub))
+	require.True(t, curve.Curve.Verify(utils.FeltToBigInt(msgHash), utils.FeltToBigInt(signature[0]),
+		utils.FeltToBigInt(signature[1]), utils.FeltToBigInt(pub), pubY))
+}
+
+// TestSessionAccount tests that a session issued by the owner lets the session
+// key sign allowed calls, with proofs verifying against the session root, and
+// that other calls and expired sessions are refused.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestSessionAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	provider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil).Times(2)
+
+	address := utils.TestHexToFelt(t, "0xacc")
+	ownerKs, ownerPub, _ := account.GetRandomKeys()
+	owner, err := account.NewAccount(provider, address, ownerPub.String(), ownerKs, 0)
+	require.NoError(t, err)
+
+	game := utils.TestHexToFelt(t, "0x9a3e")
+	policies := []Policy{NewPolicy(game, "move"), NewPolicy(game, "attack"), NewPolicy(game, "flee")}
+	sessionKs, sessionPub, _ := account.GetRandomKeys()
+	_, err = Issue(context.Background(), owner, sessionPub, time.Now().Add(time.Hour), nil)
+	require.Equal(t, ErrNoPolicies, err)
+	signed, err := Issue(context.Background(), owner, sessionPub, time.Now().Add(time.Hour), policies)
+	require.NoError(t, err)
+	msgHash, err := signed.MessageHash(owner.ChainId, address)
+	require.NoError(t, err)
+	verify(t, msgHash, ownerPub, signed.Signature)
+
+	format := ArgentFormat{PluginClassHash: utils.TestHexToFelt(t, "0x31c70ed28f4b0faf39b12f3e1af23a4d8ba4c8a4bd6bfbc4ef2b1c2c3a2ebc3")}
+	sessionAccount, err := NewAccount(provider, signed, sessionKs, format, 0)
+	require.NoError(t, err)
+
+	attack := rpc.FunctionCall{ContractAddress: game, EntryPointSelector: utils.GetSelectorFromNameFelt("attack"), Calldata: []*felt.Felt{utils.Uint64ToFelt(3)}}
+	tx, err := sessionAccount.BuildInvokeTxn(context.Background(), []rpc.FunctionCall{attack}, utils.Uint64ToFelt(5), utils.Uint64ToFelt(100))
+	require.NoError(t, err)
+	calldata, err := owner.FmtCalldata(format.Calls(signed, []rpc.FunctionCall{attack}))
+	require.NoError(t, err)
+	require.Equal(t, calldata, tx.Calldata)
+
+	txHash, err := sessionAccount.TransactionHashInvoke(*tx)
+	require.NoError(t, err)
+	verify(t, txHash, sessionPub, tx.Signature[:2])
+	root, err := signed.Root()
+	require.NoError(t, err)
+	require.Equal(t, []*felt.Felt{sessionPub, utils.Uint64ToFelt(signed.Expires), root, utils.Uint64ToFelt(2)}, tx.Signature[2:6])
+	leaf, err := policies[1].leaf()
+	require.NoError(t, err)
+	require.True(t, merkle.ProofMerklePath(utils.FeltToBigInt(root), leaf, utils.FeltArrToBigIntArr(tx.Signature[6:8])))
+	require.Equal(t, signed.Signature, tx.Signature[8:])
+
+	transfer := rpc.FunctionCall{ContractAddress: game, EntryPointSelector: utils.GetSelectorFromNameFelt("transfer")}
+	_, err = sessionAccount.BuildInvokeTxn(context.Background(), []rpc.FunctionCall{attack, transfer}, utils.Uint64ToFelt(5), nil)
+	require.True(t, errors.Is(err, ErrNotAllowed))
+
+	sessionAccount.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = sessionAccount.BuildInvokeTxn(context.Background(), []rpc.FunctionCall{attack}, utils.Uint64ToFelt(5), nil)
+	require.Equal(t, ErrSessionExpired, err)
+}
+
+// TestSessionAccount_Execute tests that Execute sends the calls laid out by
+// the format in a transaction whose fee is estimated with the query version,
+// both signed with the session key in the layout of the format.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestSessionAccount_Execute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	provider.EXPECT().ChainID(gomock.Any()).Return("SN_SEPOLIA", nil).Times(2)
+
+	address := utils.TestHexToFelt(t, "0xacc")
+	ownerKs, ownerPub, _ := account.GetRandomKeys()
+	owner, err := account.NewAccount(provider, address, ownerPub.String(), ownerKs, 2)
+	require.NoError(t, err)
+	game := utils.TestHexToFelt(t, "0x9a3e")
+	sessionKs, sessionPub, _ := account.GetRandomKeys()
+	signed, err := Issue(context.Background(), owner, sessionPub, time.Now().Add(time.Hour), []Policy{NewPolicy(game, "move"), NewPolicy(game, "attack")})
+	require.NoError(t, err)
+	root, err := signed.Root()
+	require.NoError(t, err)
+	sessionAccount, err := NewAccount(provider, signed, sessionKs, BraavosFormat{}, 2)
+	require.NoError(t, err)
+
+	move := rpc.FunctionCall{ContractAddress: game, EntryPointSelector: utils.GetSelectorFromNameFelt("move")}
+	header := []*felt.Felt{sessionPub, utils.Uint64ToFelt(signed.Expires), root, utils.Uint64ToFelt(uint64(len(signed.Signature)))}
+	header = append(header, signed.Signature...)
+	header = append(header, utils.Uint64ToFelt(1), utils.Uint64ToFelt(1))
+
+	provider.EXPECT().EstimateFee(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, txs []rpc.BroadcastTxn, _ []rpc.SimulationFlag, _ rpc.BlockID) ([]rpc.FeeEstimate, error) {
+			query := txs[0].(rpc.BroadcastInvokev1Txn).InvokeTxnV1
+			require.Equal(t, rpc.TransactionV1WithQueryBit, query.Version)
+			require.Equal(t, header, query.Signature[:len(header)])
+			return []rpc.FeeEstimate{{OverallFee: utils.Uint64ToFelt(100)}}, nil
+		})
+	provider.EXPECT().AddInvokeTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, txn rpc.BroadcastInvokeTxnType) (*rpc.AddInvokeTransactionResponse, error) {
+			tx := txn.(rpc.BroadcastInvokev1Txn).InvokeTxnV1
+			require.Equal(t, rpc.TransactionV1, tx.Version)
+			require.Equal(t, utils.Uint64ToFelt(150), tx.MaxFee)
+			require.Equal(t, header, tx.Signature[:len(header)])
+			txHash, err := sessionAccount.TransactionHashInvoke(tx)
+			require.NoError(t, err)
+			verify(t, txHash, sessionPub, tx.Signature[len(header)+1:])
+			return &rpc.AddInvokeTransactionResponse{TransactionHash: txHash}, nil
+		})
+	_, err = sessionAccount.Execute(context.Background(), []rpc.FunctionCall{move}, account.WithNonce(utils.Uint64ToFelt(5)))
+	require.NoError(t, err)
+
+	_, err = sessionAccount.Execute(context.Background(), nil)
+	require.Equal(t, account.ErrNoCalls, err)
+	flee := rpc.FunctionCall{ContractAddress: game, EntryPointSelector: utils.GetSelectorFromNameFelt("flee")}
+	_, err = sessionAccount.Execute(context.Background(), []rpc.FunctionCall{flee}, account.WithNonce(utils.Uint64ToFelt(5)))
+	require.True(t, errors.Is(err, ErrNotAllowed))
+}
@@ -0,0 +1,207 @@
+// Package signers reads the signers, thresholds and pending signer changes of
+// the multi-owner account classes, Argent multisig and Braavos, so that
+// monitoring tools can detect unexpected changes by comparing snapshots.
+package signers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var ErrUnexpectedResponse = errors.New("signers: unexpected response")
+
+// ArgentMultisig is a snapshot of the signers of an Argent multisig account.
+type ArgentMultisig struct {
+	// Signers are the public keys of the signers, in the order of the account
+	Signers   []*felt.Felt
+	Threshold uint64
+}
+
+// Braavos is a snapshot of the signers of a Braavos account.
+type Braavos struct {
+	// Stark are the public keys of the Stark signers
+	Stark []*felt.Felt
+	// Secp256r1 are the GUIDs of the hardware signers
+	Secp256r1 []*felt.Felt
+	// Webauthn are the GUIDs of the webauthn signers
+	Webauthn []*felt.Felt
+	// Threshold is the multisig threshold, 0 or 1 when multisig is disabled
+	Threshold uint64
+	// DeferredRemovalExpiry is the timestamp after which the pending removal
+	// of the hardware signers can be executed, 0 if none is pending
+	DeferredRemovalExpiry uint64
+}
+
+// ReadArgentMultisig reads the signers and threshold of an Argent multisig account.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - account: the address of the account
+// - blockID: the block to read at
+// Returns:
+// - *ArgentMultisig: the snapshot
+// - error: an error of the provider, or ErrUnexpectedResponse
+func ReadArgentMultisig(ctx context.Context, provider rpc.RpcProvider, account *felt.Felt, blockID rpc.BlockID) (*ArgentMultisig, error) {
+	signers, err := call(ctx, provider, account, "get_signers", blockID)
+	if err != nil {
+		return nil, err
+	}
+	list, rest, err := readArray(signers)
+	if err != nil || len(rest) != 0 {
+		return nil, fmt.Errorf("%w: get_signers", ErrUnexpectedResponse)
+	}
+	threshold, err := callUint64(ctx, provider, account, "get_threshold", blockID)
+	if err != nil {
+		return nil, err
+	}
+	return &ArgentMultisig{Signers: list, Threshold: threshold}, nil
+}
+
+// ReadBraavos reads the signers, multisig threshold and pending signer
+// removal of a Braavos account.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - account: the address of the account
+// - blockID: the block to read at
+// Returns:
+// - *Braavos: the snapshot
+// - error: an error of the provider, or ErrUnexpectedResponse
+func ReadBraavos(ctx context.Context, provider rpc.RpcProvider, account *felt.Felt, blockID rpc.BlockID) (*Braavos, error) {
+	signers, err := call(ctx, provider, account, "get_signers", blockID)
+	if err != nil {
+		return nil, err
+	}
+	var lists [3][]*felt.Felt
+	for i := range lists {
+		if lists[i], signers, err = readArray(signers); err != nil {
+			return nil, fmt.Errorf("%w: get_signers", ErrUnexpectedResponse)
+		}
+	}
+	if len(signers) != 0 {
+		return nil, fmt.Errorf("%w: get_signers", ErrUnexpectedResponse)
+	}
+	threshold, err := callUint64(ctx, provider, account, "get_multisig_threshold", blockID)
+	if err != nil {
+		return nil, err
+	}
+	expiry, err := callUint64(ctx, provider, account, "get_deferred_remove_signers", blockID)
+	if err != nil {
+		return nil, err
+	}
+	return &Braavos{
+		Stark:                 lists[0],
+		Secp256r1:             lists[1],
+		Webauthn:              lists[2],
+		Threshold:             threshold,
+		DeferredRemovalExpiry: expiry,
+	}, nil
+}
+
+// Diff returns the signers added and removed between two snapshots of a
+// list of signers.
+//
+// Parameters:
+// - before: the signers of the older snapshot
+// - after: the signers of the newer snapshot
+// Returns:
+// - []*felt.Felt: the signers of after missing from before
+// - []*felt.Felt: the signers of before missing from after
+func Diff(before, after []*felt.Felt) (added, removed []*felt.Felt) {
+	return missing(after, before), missing(before, after)
+}
+
+// missing returns the felts of a missing from b.
+//
+// Parameters:
+// - a: the felts to look up
+// - b: the felts to look into
+// Returns:
+// - []*felt.Felt: the felts of a missing from b
+func missing(a, b []*felt.Felt) []*felt.Felt {
+	set := make(map[felt.Felt]bool, len(b))
+	for _, f := range b {
+		set[*f] = true
+	}
+	var result []*felt.Felt
+	for _, f := range a {
+		if !set[*f] {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// call calls a view entrypoint of an account without arguments.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - account: the address of the account
+// - entrypoint: the name of the entrypoint
+// - blockID: the block to call at
+// Returns:
+// - []*felt.Felt: the result
+// - error: an error of the provider
+func call(ctx context.Context, provider rpc.RpcProvider, account *felt.Felt, entrypoint string, blockID rpc.BlockID) ([]*felt.Felt, error) {
+	return provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    account,
+		EntryPointSelector: utils.GetSelectorFromNameCached(entrypoint),
+		Calldata:           []*felt.Felt{},
+	}, blockID)
+}
+
+// callUint64 calls a view entrypoint returning a single integer.
+//
+// Parameters:
+// - ctx: the context
+// - provider: the provider
+// - account: the address of the account
+// - entrypoint: the name of the entrypoint
+// - blockID: the block to call at
+// Returns:
+// - uint64: the result
+// - error: an error of the provider, or ErrUnexpectedResponse
+func callUint64(ctx context.Context, provider rpc.RpcProvider, account *felt.Felt, entrypoint string, blockID rpc.BlockID) (uint64, error) {
+	result, err := call(ctx, provider, account, entrypoint, blockID)
+	if err != nil {
+		return 0, err
+	}
+	if len(result) != 1 || !isUint64(result[0]) {
+		return 0, fmt.Errorf("%w: %s", ErrUnexpectedResponse, entrypoint)
+	}
+	return result[0].Uint64(), nil
+}
+
+// readArray reads a serialized array at the start of data.
+//
+// Parameters:
+// - data: the felts
+// Returns:
+// - []*felt.Felt: the elements of the array
+// - []*felt.Felt: the felts following the array
+// - error: ErrUnexpectedResponse if data does not start with an array
+func readArray(data []*felt.Felt) ([]*felt.Felt, []*felt.Felt, error) {
+	if len(data) == 0 || !isUint64(data[0]) || data[0].Uint64() > uint64(len(data)-1) {
+		return nil, nil, ErrUnexpectedResponse
+	}
+	n := int(data[0].Uint64())
+	return data[1 : 1+n], data[1+n:], nil
+}
+
+// isUint64 reports whether a felt fits in a uint64.
+//
+// Parameters:
+// - f: the felt
+// Returns:
+// - bool: true if the felt fits in a uint64
+func isUint64(f *felt.Felt) bool {
+	return f.Equal(new(felt.Felt).SetUint64(f.Uint64()))
+}
@@ -0,0 +1,66 @@
+package signers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestRead tests the decoding of the signers of Argent multisig and Braavos
+// accounts, and the diff of snapshots.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	account := utils.TestHexToFelt(t, "0xacc")
+	a, b, c := utils.TestHexToFelt(t, "0xa"), utils.TestHexToFelt(t, "0xb"), utils.TestHexToFelt(t, "0xc")
+
+	responses := map[string][]*felt.Felt{}
+	provider.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, call rpc.FunctionCall, _ rpc.BlockID) ([]*felt.Felt, error) {
+			require.Equal(t, account, call.ContractAddress)
+			for name, result := range responses {
+				if utils.GetSelectorFromNameFelt(name).Equal(call.EntryPointSelector) {
+					return result, nil
+				}
+			}
+			return nil, rpc.ErrContractError
+		}).AnyTimes()
+
+	responses["get_signers"] = []*felt.Felt{utils.Uint64ToFelt(2), a, b}
+	responses["get_threshold"] = []*felt.Felt{utils.Uint64ToFelt(2)}
+	multisig, err := ReadArgentMultisig(context.Background(), provider, account, rpc.WithBlockTag("latest"))
+	require.NoError(t, err)
+	require.Equal(t, &ArgentMultisig{Signers: []*felt.Felt{a, b}, Threshold: 2}, multisig)
+
+	responses["get_signers"] = []*felt.Felt{utils.Uint64ToFelt(1), b, utils.Uint64ToFelt(1), c, utils.Uint64ToFelt(0)}
+	responses["get_multisig_threshold"] = []*felt.Felt{utils.Uint64ToFelt(2)}
+	responses["get_deferred_remove_signers"] = []*felt.Felt{utils.Uint64ToFelt(1700000000)}
+	braavos, err := ReadBraavos(context.Background(), provider, account, rpc.WithBlockTag("latest"))
+	require.NoError(t, err)
+	require.Equal(t, &Braavos{Stark: []*felt.Felt{b}, Secp256r1: []*felt.Felt{c}, Webauthn: []*felt.Felt{},
+		Threshold: 2, DeferredRemovalExpiry: 1700000000}, braavos)
+
+	added, removed := Diff(multisig.Signers, []*felt.Felt{b, c})
+	require.Equal(t, []*felt.Felt{c}, added)
+	require.Equal(t, []*felt.Felt{a}, removed)
+
+	responses["get_signers"] = []*felt.Felt{utils.Uint64ToFelt(3), a}
+	_, err = ReadArgentMultisig(context.Background(), provider, account, rpc.WithBlockTag("latest"))
+	require.True(t, errors.Is(err, ErrUnexpectedResponse))
+	delete(responses, "get_signers")
+	_, err = ReadBraavos(context.Background(), provider, account, rpc.WithBlockTag("latest"))
+	require.True(t, errors.Is(err, rpc.ErrContractError))
+}
@@ -0,0 +1,406 @@
+// Package siws implements Sign-In-With-Starknet: a web backend hands a
+// Message to the wallet of a user, which signs it as SNIP-12 typed data, and
+// verifies the signature against the account of the user on chain before
+// opening a session. The text form of the messages follows Sign-In with
+// Ethereum (EIP-4361), with the Starknet address and chain ID.
+//
+//	msg, err := siws.NewMessage("example.com", address, "SN_MAIN", "https://example.com/login", time.Hour)
+//	// the wallet signs msg.String() as typed data, then
+//	err = siws.Verify(ctx, siws.NewVerifier(provider), msg, signature, siws.WithDomain("example.com"), siws.WithNonce(nonce))
+package siws
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var (
+	ErrInvalidMessage   = errors.New("siws: invalid message")
+	ErrDomainMismatch   = errors.New("siws: domain mismatch")
+	ErrNonceMismatch    = errors.New("siws: nonce mismatch")
+	ErrExpired          = errors.New("siws: message expired")
+	ErrNotYetValid      = errors.New("siws: message not yet valid")
+	ErrInvalidSignature = errors.New("siws: invalid signature")
+
+	messagePrefix = new(felt.Felt).SetBytes([]byte("StarkNet Message"))
+
+	// SNIP-12 revision 0 types of the message
+	domainTypeHash  = utils.GetSelectorFromNameFelt("StarkNetDomain(name:felt,version:felt,chainId:felt)")
+	messageTypeHash = utils.GetSelectorFromNameFelt("Message(address:felt,statement:felt,uri:felt,nonce:felt,issuedAt:felt,expirationTime:felt)")
+)
+
+// Version is the version of the messages built by NewMessage.
+const Version = "1"
+
+const header = " wants you to sign in with your Starknet account:"
+
+// Message is a Sign-In-With-Starknet message.
+type Message struct {
+	// Domain is the host requesting the sign-in, e.g. "example.com"
+	Domain string
+	// Address is the account signing in
+	Address *felt.Felt
+	// Statement is a text shown to the user, may be empty
+	Statement string
+	// URI is the resource the user signs in to
+	URI     string
+	Version string
+	// ChainID is the chain of the account, e.g. "SN_MAIN"
+	ChainID string
+	// Nonce is a random value, issued by the backend, preventing replays
+	Nonce string
+	// IssuedAt and ExpirationTime bound the validity of the message, the
+	// expiration time being optional
+	IssuedAt       time.Time
+	ExpirationTime time.Time
+}
+
+// NewMessage creates a message issued now, with a random nonce, valid for a
+// duration.
+//
+// Parameters:
+// - domain: the host requesting the sign-in
+// - address: the account signing in
+// - chainID: the chain of the account, e.g. "SN_MAIN"
+// - uri: the resource the user signs in to
+// - validFor: how long the message stays valid, no expiration if not positive
+// Returns:
+// - *Message: the message
+// - error: an error if the nonce can not be generated
+func NewMessage(domain string, address *felt.Felt, chainID, uri string, validFor time.Duration) (*Message, error) {
+	nonce, err := NewNonce()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	m := &Message{
+		Domain:   domain,
+		Address:  address,
+		URI:      uri,
+		Version:  Version,
+		ChainID:  chainID,
+		Nonce:    nonce,
+		IssuedAt: now,
+	}
+	if validFor > 0 {
+		m.ExpirationTime = now.Add(validFor)
+	}
+	return m, nil
+}
+
+// NewNonce returns a random nonce of 16 hexadecimal characters, for the
+// backend to store until the sign-in.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the nonce
+// - error: an error if the random source fails
+func NewNonce() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// String returns the text of the message shown to the user:
+//
+//	example.com wants you to sign in with your Starknet account:
+//	0x...
+//
+//	Statement
+//
+//	URI: https://example.com/login
+//	Version: 1
+//	Chain ID: SN_MAIN
+//	Nonce: 32891756a1b2c3d4
+//	Issued At: 2024-01-01T00:00:00Z
+//	Expiration Time: 2024-01-01T01:00:00Z
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the text of the message
+func (m *Message) String() string {
+	var b strings.Builder
+	b.WriteString(m.Domain + header + "\n")
+	if m.Address != nil {
+		b.WriteString(m.Address.String())
+	}
+	b.WriteString("\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n\n")
+	}
+	fmt.Fprintf(&b, "URI: %s\nVersion: %s\nChain ID: %s\nNonce: %s\nIssued At: %s",
+		m.URI, m.Version, m.ChainID, m.Nonce, m.IssuedAt.UTC().Format(time.RFC3339))
+	if !m.ExpirationTime.IsZero() {
+		fmt.Fprintf(&b, "\nExpiration Time: %s", m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+// ParseMessage parses the text of a message, as returned by String.
+//
+// Parameters:
+// - text: the text of the message
+// Returns:
+// - *Message: the message
+// - error: an error wrapping ErrInvalidMessage
+func ParseMessage(text string) (*Message, error) {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) < 3 || !strings.HasSuffix(lines[0], header) || lines[2] != "" {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidMessage)
+	}
+	m := &Message{Domain: strings.TrimSuffix(lines[0], header)}
+	address, err := new(felt.Felt).SetString(lines[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: address: %v", ErrInvalidMessage, err)
+	}
+	m.Address = address
+
+	fields := lines[3:]
+	if len(fields) > 1 && !strings.HasPrefix(fields[0], "URI: ") && fields[1] == "" {
+		m.Statement, fields = fields[0], fields[2:]
+	}
+	values := map[string]string{}
+	for _, line := range fields {
+		name, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("%w: line %q", ErrInvalidMessage, line)
+		}
+		values[name] = value
+	}
+	for name, field := range map[string]*string{"URI": &m.URI, "Version": &m.Version, "Chain ID": &m.ChainID, "Nonce": &m.Nonce} {
+		value, ok := values[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidMessage, name)
+		}
+		*field = value
+	}
+	if m.IssuedAt, err = time.Parse(time.RFC3339, values["Issued At"]); err != nil {
+		return nil, fmt.Errorf("%w: issued at: %v", ErrInvalidMessage, err)
+	}
+	if expiration, ok := values["Expiration Time"]; ok {
+		if m.ExpirationTime, err = time.Parse(time.RFC3339, expiration); err != nil {
+			return nil, fmt.Errorf("%w: expiration time: %v", ErrInvalidMessage, err)
+		}
+	}
+	return m, nil
+}
+
+// Hash returns the SNIP-12 revision 0 hash of the message signed by the
+// account, whose domain is named after the domain of the message. Strings
+// are encoded as short strings, or as their Starknet keccak when longer than
+// the 31 bytes of a short string, e.g. URIs, and times as seconds since the
+// epoch, 0 for no expiration.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the message hash
+// - error: an error if the message has no address or hashing fails
+func (m *Message) Hash() (*felt.Felt, error) {
+	if m.Address == nil {
+		return nil, fmt.Errorf("%w: missing address", ErrInvalidMessage)
+	}
+	domainHash, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{domainTypeHash, encodeString(m.Domain), encodeString(m.Version), encodeString(m.ChainID)})
+	if err != nil {
+		return nil, err
+	}
+	var expiration uint64
+	if !m.ExpirationTime.IsZero() {
+		expiration = uint64(m.ExpirationTime.Unix())
+	}
+	messageHash, err := hash.ComputeHashOnElementsFelt([]*felt.Felt{
+		messageTypeHash, m.Address, encodeString(m.Statement), encodeString(m.URI), encodeString(m.Nonce),
+		new(felt.Felt).SetUint64(uint64(m.IssuedAt.Unix())), new(felt.Felt).SetUint64(expiration),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hash.ComputeHashOnElementsFelt([]*felt.Felt{messagePrefix, domainHash, m.Address, messageHash})
+}
+
+// Sign signs a message with the account signing in, as the wallet of the
+// user does, e.g. for tests and Go clients.
+//
+// Parameters:
+// - ctx: the context
+// - signer: the account of the address of the message
+// - m: the message
+// Returns:
+// - []*felt.Felt: the signature
+// - error: an error if the account is not the address of the message, or hashing or signing fails
+func Sign(ctx context.Context, signer *account.Account, m *Message) ([]*felt.Felt, error) {
+	if m.Address == nil || !m.Address.Equal(signer.AccountAddress) {
+		return nil, fmt.Errorf("%w: signed by %s for %s", ErrInvalidMessage, signer.AccountAddress, m.Address)
+	}
+	msgHash, err := m.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return signer.Sign(ctx, msgHash)
+}
+
+// Verifier checks signatures against the accounts on chain, as
+// account.VerifySignature does.
+type Verifier interface {
+	VerifySignature(ctx context.Context, address, msgHash *felt.Felt, signature []*felt.Felt) (bool, error)
+}
+
+// providerVerifier is the Verifier calling the accounts with a provider.
+type providerVerifier struct {
+	provider rpc.RpcProvider
+}
+
+// NewVerifier creates a Verifier checking the signatures with
+// account.VerifySignature, calling the accounts with provider.
+//
+// Parameters:
+// - provider: the provider
+// Returns:
+// - Verifier: the verifier
+func NewVerifier(provider rpc.RpcProvider) Verifier {
+	return providerVerifier{provider: provider}
+}
+
+// VerifySignature implements Verifier.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address of the account having signed
+// - msgHash: the signed hash
+// - signature: the signature
+// Returns:
+// - bool: true if the account accepts the signature
+// - error: the error of account.VerifySignature
+func (v providerVerifier) VerifySignature(ctx context.Context, address, msgHash *felt.Felt, signature []*felt.Felt) (bool, error) {
+	return account.VerifySignature(ctx, v.provider, address, msgHash, signature)
+}
+
+type verifyOptions struct {
+	domain string
+	nonce  string
+	now    time.Time
+}
+
+// VerifyOption configures Verify.
+type VerifyOption func(*verifyOptions)
+
+// WithDomain requires the message to be for a domain, the host of the
+// backend.
+//
+// Parameters:
+// - domain: the domain
+// Returns:
+// - VerifyOption: the option
+func WithDomain(domain string) VerifyOption {
+	return func(o *verifyOptions) {
+		o.domain = domain
+	}
+}
+
+// WithNonce requires the message to carry the nonce the backend issued.
+//
+// Parameters:
+// - nonce: the nonce
+// Returns:
+// - VerifyOption: the option
+func WithNonce(nonce string) VerifyOption {
+	return func(o *verifyOptions) {
+		o.nonce = nonce
+	}
+}
+
+// WithTime sets the time the validity of the message is checked at, now by
+// default.
+//
+// Parameters:
+// - at: the time
+// Returns:
+// - VerifyOption: the option
+func WithTime(at time.Time) VerifyOption {
+	return func(o *verifyOptions) {
+		o.now = at
+	}
+}
+
+// Verify checks a signed message: its domain and nonce when required by the
+// options, its validity at the current time, and its signature against the
+// account on chain.
+//
+// Parameters:
+// - ctx: the context
+// - verifier: the verifier of the signatures, e.g. NewVerifier
+// - m: the message
+// - signature: the signature of the message
+// - opts: the options
+// Returns:
+// - error: ErrDomainMismatch, ErrNonceMismatch, ErrExpired, ErrNotYetValid, ErrInvalidSignature, or an error of the verifier
+func Verify(ctx context.Context, verifier Verifier, m *Message, signature []*felt.Felt, opts ...VerifyOption) error {
+	options := verifyOptions{now: time.Now()}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.domain != "" && m.Domain != options.domain {
+		return fmt.Errorf("%w: %q", ErrDomainMismatch, m.Domain)
+	}
+	if options.nonce != "" && m.Nonce != options.nonce {
+		return ErrNonceMismatch
+	}
+	if options.now.Before(m.IssuedAt) {
+		return fmt.Errorf("%w: issued at %s", ErrNotYetValid, m.IssuedAt.UTC().Format(time.RFC3339))
+	}
+	if !m.ExpirationTime.IsZero() && !options.now.Before(m.ExpirationTime) {
+		return fmt.Errorf("%w: at %s", ErrExpired, m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	msgHash, err := m.Hash()
+	if err != nil {
+		return err
+	}
+	valid, err := verifier.VerifySignature(ctx, m.Address, msgHash, signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// encodeString encodes a string of the message as a felt: its short string,
+// or its Starknet keccak when longer than 31 bytes.
+//
+// Parameters:
+// - s: the string
+// Returns:
+// - *felt.Felt: the felt
+func encodeString(s string) *felt.Felt {
+	if len(s) > 31 {
+		return utils.GetSelectorFromNameFelt(s)
+	}
+	return new(felt.Felt).SetBytes([]byte(s))
+}
@@ -0,0 +1,90 @@
+package siws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/curve"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// keyVerifier verifies signatures against a public key, in place of an
+// account on chain.
+type keyVerifier struct {
+	pub *felt.Felt
+}
+
+// VerifySignature verifies a signature against the public key.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address of the account, ignored
+// - msgHash: the signed hash
+// - signature: the signature
+// Returns:
+// - bool: true if the signature is valid
+// - error: always nil
+func (v keyVerifier) VerifySignature(ctx context.Context, address, msgHash *felt.Felt, signature []*felt.Felt) (bool, error) {
+	pubY := curve.Curve.GetYCoordinate(utils.FeltToBigInt(v.pub))
+	return len(signature) == 2 && curve.Curve.Verify(utils.FeltToBigInt(msgHash), utils.FeltToBigInt(signature[0]),
+		utils.FeltToBigInt(signature[1]), utils.FeltToBigInt(v.pub), pubY), nil
+}
+
+// TestMessage tests that a message survives its text form, and that a signed
+// message verifies only for its domain, its nonce, its validity window and an
+// untampered content, with a key or with the account on chain.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestMessage(t *testing.T) {
+	ks, pub, _ := account.GetRandomKeys()
+	address := utils.TestHexToFelt(t, "0x5167e7")
+	provider := mocks.NewMockRpcProvider(gomock.NewController(t))
+	signer, err := account.NewAccount(provider, address, pub.String(), ks, 2, account.WithChainID("SN_SEPOLIA"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	msg, err := NewMessage("example.com", address, "SN_SEPOLIA", "https://example.com/a/rather/long/login/path", time.Hour)
+	require.NoError(t, err)
+	msg.Statement = "Sign in to Example"
+	require.Len(t, msg.Nonce, 16)
+	parsed, err := ParseMessage(msg.String())
+	require.NoError(t, err)
+	require.Equal(t, msg, parsed)
+	msg.Statement = ""
+	parsed, err = ParseMessage(msg.String())
+	require.NoError(t, err)
+	require.Equal(t, msg, parsed)
+	_, err = ParseMessage("hello")
+	require.True(t, errors.Is(err, ErrInvalidMessage))
+
+	signature, err := Sign(ctx, signer, msg)
+	require.NoError(t, err)
+	verifier := keyVerifier{pub: pub}
+	require.NoError(t, Verify(ctx, verifier, msg, signature, WithDomain("example.com"), WithNonce(msg.Nonce)))
+	require.True(t, errors.Is(Verify(ctx, verifier, msg, signature, WithDomain("evil.com")), ErrDomainMismatch))
+	require.True(t, errors.Is(Verify(ctx, verifier, msg, signature, WithNonce("0000")), ErrNonceMismatch))
+	require.True(t, errors.Is(Verify(ctx, verifier, msg, signature, WithTime(msg.ExpirationTime)), ErrExpired))
+	require.True(t, errors.Is(Verify(ctx, verifier, msg, signature, WithTime(msg.IssuedAt.Add(-time.Minute))), ErrNotYetValid))
+	provider.EXPECT().Call(ctx, gomock.Any(), rpc.WithBlockTag("pending")).Return([]*felt.Felt{new(felt.Felt).SetBytes([]byte("VALID"))}, nil)
+	require.NoError(t, Verify(ctx, NewVerifier(provider), msg, signature))
+	tampered := *msg
+	tampered.URI = "https://evil.com"
+	require.True(t, errors.Is(Verify(ctx, verifier, &tampered, signature), ErrInvalidSignature))
+
+	other := *msg
+	other.Address = utils.TestHexToFelt(t, "0xb0b")
+	_, err = Sign(ctx, signer, &other)
+	require.True(t, errors.Is(err, ErrInvalidMessage))
+}
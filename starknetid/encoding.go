@@ -0,0 +1,142 @@
+package starknetid
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// basicAlphabet is the alphabet of the Starknet ID domain encoding. Each
+// character is a digit in base len(basicAlphabet) + 1, the extra digit being
+// an escape.
+const basicAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789-"
+
+// DomainSuffix is the top-level domain of Starknet ID.
+const DomainSuffix = ".stark"
+
+var (
+	ErrInvalidDomain       = errors.New("starknetid: invalid domain")
+	ErrUnsupportedEncoding = errors.New("starknetid: unsupported domain encoding")
+
+	basicBase   = big.NewInt(int64(len(basicAlphabet) + 1))
+	escapeDigit = big.NewInt(int64(len(basicAlphabet)))
+)
+
+// EncodeLabel encodes a single domain label, e.g. "foo" for "foo.stark".
+//
+// The label is read as a little-endian number in base 38 whose digits are
+// the indexes of the characters in the alphabet. A trailing "a" would be a
+// leading zero and is encoded by the escape digit instead. Only the basic
+// alphabet (lowercase letters, digits and hyphen) is supported.
+//
+// Parameters:
+// - label: the label to encode
+// Returns:
+// - *felt.Felt: the encoded label
+// - error: an error if the label is empty or contains an unsupported character
+func EncodeLabel(label string) (*felt.Felt, error) {
+	if label == "" {
+		return nil, fmt.Errorf("%w: empty label", ErrInvalidDomain)
+	}
+	encoded := new(big.Int)
+	multiplier := big.NewInt(1)
+	for i, c := range label {
+		index := strings.IndexRune(basicAlphabet, c)
+		if index < 0 {
+			return nil, fmt.Errorf("%w: unsupported character %q in %q", ErrInvalidDomain, c, label)
+		}
+		digit := big.NewInt(int64(index))
+		if i == len(label)-1 && index == 0 {
+			digit = escapeDigit
+		}
+		encoded.Add(encoded, new(big.Int).Mul(multiplier, digit))
+		multiplier.Mul(multiplier, basicBase)
+	}
+	return new(felt.Felt).SetBigInt(encoded), nil
+}
+
+// DecodeLabel decodes a label encoded with EncodeLabel.
+//
+// Parameters:
+// - encoded: the encoded label
+// Returns:
+// - string: the label
+// - error: an error if the label uses the extended alphabet
+func DecodeLabel(encoded *felt.Felt) (string, error) {
+	var sb strings.Builder
+	v := encoded.BigInt(new(big.Int))
+	digit := new(big.Int)
+	for v.Sign() != 0 {
+		v.QuoRem(v, basicBase, digit)
+		if digit.Cmp(escapeDigit) == 0 {
+			if v.Sign() != 0 {
+				return "", fmt.Errorf("%w: %s", ErrUnsupportedEncoding, encoded)
+			}
+			sb.WriteByte(basicAlphabet[0])
+			break
+		}
+		sb.WriteByte(basicAlphabet[digit.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// EncodeDomain encodes a domain such as "sub.foo.stark" to the labels
+// expected by the naming contract, in the order they are written.
+//
+// Parameters:
+// - domain: the domain, with or without the .stark suffix
+// Returns:
+// - []*felt.Felt: the encoded labels
+// - error: an error if a label can not be encoded
+func EncodeDomain(domain string) ([]*felt.Felt, error) {
+	domain = normalizeDomain(domain)
+	if domain == "" {
+		return nil, fmt.Errorf("%w: empty domain", ErrInvalidDomain)
+	}
+	labels := strings.Split(domain, ".")
+	encoded := make([]*felt.Felt, len(labels))
+	for i, label := range labels {
+		f, err := EncodeLabel(label)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = f
+	}
+	return encoded, nil
+}
+
+// DecodeDomain decodes the labels returned by the naming contract to a
+// domain with the .stark suffix.
+//
+// Parameters:
+// - labels: the encoded labels
+// Returns:
+// - string: the domain, empty if labels is empty
+// - error: an error if a label can not be decoded
+func DecodeDomain(labels []*felt.Felt) (string, error) {
+	if len(labels) == 0 {
+		return "", nil
+	}
+	decoded := make([]string, len(labels))
+	for i, label := range labels {
+		s, err := DecodeLabel(label)
+		if err != nil {
+			return "", err
+		}
+		decoded[i] = s
+	}
+	return strings.Join(decoded, ".") + DomainSuffix, nil
+}
+
+// normalizeDomain lowercases a domain and removes its .stark suffix.
+//
+// Parameters:
+// - domain: the domain
+// Returns:
+// - string: the normalized domain
+func normalizeDomain(domain string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), DomainSuffix)
+}
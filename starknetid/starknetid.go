@@ -0,0 +1,244 @@
+// Package starknetid resolves Starknet ID domains ("foo.stark") to addresses
+// and addresses to their main domain through the Starknet ID naming contract.
+package starknetid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// DefaultCacheTTL is how long a resolution is cached when no TTL is configured.
+const DefaultCacheTTL = 5 * time.Minute
+
+var (
+	ErrDomainNotFound  = errors.New("starknetid: domain not found")
+	ErrAddressNotFound = errors.New("starknetid: no domain for address")
+	ErrUnknownChain    = errors.New("starknetid: no naming contract known for chain")
+)
+
+// NamingContracts are the addresses of the naming contract per chain ID.
+var NamingContracts = map[string]string{
+	"SN_MAIN":    "0x6ac597f8116f886fa1c97a23fa4e08299975ecaf6b598873ca6792b9bbfb678",
+	"SN_GOERLI":  "0x3bab268e932d2cecd1946f100ae67ce3dff9fd234119ea2f6da57d16d29fce",
+	"SN_SEPOLIA": "0x0154bc2e1af9260b9e66af0e9c46fc757ff893b3ff6a85718a810baf1474",
+}
+
+// Client resolves domains with the naming contract and caches the results.
+type Client struct {
+	provider rpc.RpcProvider
+	contract *felt.Felt
+	ttl      time.Duration
+
+	mu        sync.Mutex
+	addresses map[string]cacheEntry[*felt.Felt]
+	domains   map[felt.Felt]cacheEntry[string]
+	now       func() time.Time
+}
+
+type cacheEntry[T any] struct {
+	value     T
+	err       error
+	expiresAt time.Time
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithNamingContract sets the address of the naming contract instead of the
+// one known for the chain of the provider.
+//
+// Parameters:
+// - contract: the address of the naming contract
+// Returns:
+// - Option: the option
+func WithNamingContract(contract *felt.Felt) Option {
+	return func(c *Client) {
+		c.contract = contract
+	}
+}
+
+// WithCacheTTL sets how long resolutions, including misses, are cached. A
+// zero TTL disables the cache.
+//
+// Parameters:
+// - ttl: the cache duration
+// Returns:
+// - Option: the option
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.ttl = ttl
+	}
+}
+
+// NewClient creates a Client. Unless WithNamingContract is given, the naming
+// contract is looked up in NamingContracts from the chain ID of the provider.
+//
+// Parameters:
+// - ctx: the context used to read the chain ID
+// - provider: the provider used to call the naming contract
+// - opts: the options of the client
+// Returns:
+// - *Client: the client
+// - error: an error if the naming contract of the chain is unknown
+func NewClient(ctx context.Context, provider rpc.RpcProvider, opts ...Option) (*Client, error) {
+	c := &Client{
+		provider:  provider,
+		ttl:       DefaultCacheTTL,
+		addresses: map[string]cacheEntry[*felt.Felt]{},
+		domains:   map[felt.Felt]cacheEntry[string]{},
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.contract == nil {
+		chainID, err := provider.ChainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		address, ok := NamingContracts[chainID]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownChain, chainID)
+		}
+		contract, err := utils.HexToFelt(address)
+		if err != nil {
+			return nil, err
+		}
+		c.contract = contract
+	}
+	return c, nil
+}
+
+// Resolve returns the address a domain points to.
+//
+// Parameters:
+// - ctx: the context
+// - domain: the domain, such as "foo.stark"
+// Returns:
+// - *felt.Felt: the address
+// - error: ErrDomainNotFound if the domain does not point to an address
+func (c *Client) Resolve(ctx context.Context, domain string) (*felt.Felt, error) {
+	labels, err := EncodeDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	key := normalizeDomain(domain)
+	if entry, ok := lookup(c, c.addresses, key); ok {
+		return entry.value, entry.err
+	}
+
+	// domain_to_address(domain: Span<felt252>, hint: Span<felt252>)
+	calldata := append([]*felt.Felt{new(felt.Felt).SetUint64(uint64(len(labels)))}, labels...)
+	calldata = append(calldata, new(felt.Felt))
+	result, err := c.call(ctx, "domain_to_address", calldata)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("starknetid: domain_to_address returned no value")
+	}
+	address := result[0]
+	var resolveErr error
+	if address.IsZero() {
+		address, resolveErr = nil, fmt.Errorf("%w: %s", ErrDomainNotFound, domain)
+	}
+	store(c, c.addresses, key, address, resolveErr)
+	return address, resolveErr
+}
+
+// ReverseResolve returns the main domain of an address.
+//
+// Parameters:
+// - ctx: the context
+// - address: the address
+// Returns:
+// - string: the domain, such as "foo.stark"
+// - error: ErrAddressNotFound if the address has no main domain
+func (c *Client) ReverseResolve(ctx context.Context, address *felt.Felt) (string, error) {
+	if entry, ok := lookup(c, c.domains, *address); ok {
+		return entry.value, entry.err
+	}
+
+	// address_to_domain(address: ContractAddress, hint: Span<felt252>)
+	result, err := c.call(ctx, "address_to_domain", []*felt.Felt{address, new(felt.Felt)})
+	if err != nil {
+		return "", err
+	}
+	if len(result) == 0 || uint64(len(result)-1) != result[0].Uint64() {
+		return "", fmt.Errorf("starknetid: address_to_domain returned an invalid domain %v", result)
+	}
+	domain, err := DecodeDomain(result[1:])
+	if err != nil {
+		return "", err
+	}
+	var resolveErr error
+	if domain == "" {
+		resolveErr = fmt.Errorf("%w: %s", ErrAddressNotFound, address)
+	}
+	store(c, c.domains, *address, domain, resolveErr)
+	return domain, resolveErr
+}
+
+// call calls an entrypoint of the naming contract on the latest block.
+//
+// Parameters:
+// - ctx: the context
+// - entrypoint: the entrypoint name
+// - calldata: the calldata
+// Returns:
+// - []*felt.Felt: the result of the call
+// - error: an error if the call fails
+func (c *Client) call(ctx context.Context, entrypoint string, calldata []*felt.Felt) ([]*felt.Felt, error) {
+	return c.provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    c.contract,
+		EntryPointSelector: utils.GetSelectorFromNameCached(entrypoint),
+		Calldata:           calldata,
+	}, rpc.WithBlockTag("latest"))
+}
+
+// lookup returns a live cache entry.
+//
+// Parameters:
+// - c: the client holding the cache
+// - cache: the cache to look into
+// - key: the key
+// Returns:
+// - cacheEntry[V]: the entry
+// - bool: true if a live entry was found
+func lookup[K comparable, V any](c *Client, cache map[K]cacheEntry[V], key K) (cacheEntry[V], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := cache[key]
+	if !ok || c.now().After(entry.expiresAt) {
+		delete(cache, key)
+		return cacheEntry[V]{}, false
+	}
+	return entry, true
+}
+
+// store caches a resolution, unless the cache is disabled.
+//
+// Parameters:
+// - c: the client holding the cache
+// - cache: the cache to store into
+// - key: the key
+// - value: the resolved value
+// - err: the resolution error, such as a not found error
+// Returns:
+//
+//	none
+func store[K comparable, V any](c *Client, cache map[K]cacheEntry[V], key K, value V, err error) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cache[key] = cacheEntry[V]{value: value, err: err, expiresAt: c.now().Add(c.ttl)}
+}
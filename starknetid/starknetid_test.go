@@ -0,0 +1,85 @@
+package starknetid
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/golang/mock/gomock"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/mocks"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+// TestEncodeDomain tests the encoding of domains against known values.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestEncodeDomain(t *testing.T) {
+	type testSetType struct {
+		Domain   string
+		Expected []uint64
+	}
+	testSet := []testSetType{
+		{Domain: "ben.stark", Expected: []uint64{18925}},
+		{Domain: "iris.stark", Expected: []uint64{999902}},
+		{Domain: "sub.ben.stark", Expected: []uint64{18 + 20*38 + 1*38*38, 18925}},
+		{Domain: "ba.stark", Expected: []uint64{1 + 38*37}},
+	}
+	for _, test := range testSet {
+		encoded, err := EncodeDomain(test.Domain)
+		require.NoError(t, err)
+		require.Equal(t, utils.Map(test.Expected, utils.Uint64ToFelt), encoded)
+
+		decoded, err := DecodeDomain(encoded)
+		require.NoError(t, err)
+		require.Equal(t, test.Domain, decoded)
+	}
+
+	_, err := EncodeDomain("Not_valid.stark")
+	require.True(t, errors.Is(err, ErrInvalidDomain))
+}
+
+// TestClient_Resolve tests the resolution calls and the caching of results.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestClient_Resolve(t *testing.T) {
+	ctx := context.Background()
+	contract := utils.TestHexToFelt(t, "0x5a11")
+	address := utils.TestHexToFelt(t, "0xbe11")
+
+	ctrl := gomock.NewController(t)
+	provider := mocks.NewMockRpcProvider(ctrl)
+	provider.EXPECT().Call(ctx, rpc.FunctionCall{
+		ContractAddress:    contract,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("domain_to_address"),
+		Calldata:           []*felt.Felt{utils.Uint64ToFelt(1), utils.Uint64ToFelt(18925), utils.Uint64ToFelt(0)},
+	}, rpc.WithBlockTag("latest")).Return([]*felt.Felt{address}, nil).Times(1)
+	provider.EXPECT().Call(ctx, rpc.FunctionCall{
+		ContractAddress:    contract,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("address_to_domain"),
+		Calldata:           []*felt.Felt{address, utils.Uint64ToFelt(0)},
+	}, rpc.WithBlockTag("latest")).Return([]*felt.Felt{utils.Uint64ToFelt(1), utils.Uint64ToFelt(18925)}, nil).Times(1)
+
+	client, err := NewClient(ctx, provider, WithNamingContract(contract))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		resolved, err := client.Resolve(ctx, "Ben.stark")
+		require.NoError(t, err)
+		require.Equal(t, address, resolved)
+
+		domain, err := client.ReverseResolve(ctx, address)
+		require.NoError(t, err)
+		require.Equal(t, "ben.stark", domain)
+	}
+}
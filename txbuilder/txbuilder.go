@@ -0,0 +1,575 @@
+// Package txbuilder builds V3 transactions step by step, without a provider,
+// for air-gapped signing: an online machine builds the transaction with the
+// nonce and the resource bounds it reads from the node, the offline machine
+// holding the key signs its hash, and the online machine attaches the
+// signature and broadcasts the JSON of the transaction.
+//
+//	b := txbuilder.NewInvoke("SN_MAIN", accountAddress).
+//		SetCalls(calls, 2).
+//		SetNonce(nonce).
+//		SetResourceBounds(bounds)
+//	txHash, err := b.Hash()
+//	// sign txHash offline, then
+//	tx, err := b.SetSignature(r, s).Transaction()
+//
+// The setters record the first error of the chain, returned by Hash,
+// Transaction and MarshalJSON.
+package txbuilder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/hash"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var (
+	ErrMissingField = errors.New("txbuilder: missing field")
+	ErrNotSigned    = errors.New("txbuilder: transaction not signed")
+	ErrWrongKind    = errors.New("txbuilder: field not part of the transaction")
+)
+
+// Kind is the type of transaction a Builder builds.
+type Kind int
+
+const (
+	KindInvoke Kind = iota
+	KindDeclare
+	KindDeployAccount
+)
+
+// String returns the name of the kind.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the name
+func (k Kind) String() string {
+	switch k {
+	case KindInvoke:
+		return "invoke"
+	case KindDeclare:
+		return "declare"
+	case KindDeployAccount:
+		return "deploy account"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Builder builds a V3 transaction.
+type Builder struct {
+	kind    Kind
+	chainID *felt.Felt
+	// sender is the account sending the transaction, or the account deployed
+	sender *felt.Felt
+
+	calldata []*felt.Felt
+
+	class             *rpc.ContractClass
+	classHash         *felt.Felt
+	compiledClassHash *felt.Felt
+
+	salt                *felt.Felt
+	constructorCalldata []*felt.Felt
+
+	nonce                 *felt.Felt
+	resourceBounds        rpc.ResourceBoundsMapping
+	tip                   uint64
+	paymasterData         []*felt.Felt
+	accountDeploymentData []*felt.Felt
+	nonceDAMode           rpc.DataAvailabilityMode
+	feeDAMode             rpc.DataAvailabilityMode
+	signature             []*felt.Felt
+
+	err error
+}
+
+// NewInvoke starts an invoke transaction.
+//
+// Parameters:
+// - chainID: the chain ID, e.g. "SN_MAIN"
+// - sender: the address of the account sending the transaction
+// Returns:
+// - *Builder: the builder
+func NewInvoke(chainID string, sender *felt.Felt) *Builder {
+	return newBuilder(KindInvoke, chainID, sender)
+}
+
+// NewDeclare starts a declare transaction.
+//
+// Parameters:
+// - chainID: the chain ID, e.g. "SN_MAIN"
+// - sender: the address of the account sending the transaction
+// - class: the Sierra class declared
+// - compiledClassHash: the hash of the CASM class compiled from it
+// Returns:
+// - *Builder: the builder
+func NewDeclare(chainID string, sender *felt.Felt, class *rpc.ContractClass, compiledClassHash *felt.Felt) *Builder {
+	b := newBuilder(KindDeclare, chainID, sender)
+	b.class = class
+	b.compiledClassHash = compiledClassHash
+	if class == nil {
+		b.err = fmt.Errorf("%w: class", ErrMissingField)
+		return b
+	}
+	b.classHash, b.err = hash.ClassHash(*class)
+	return b
+}
+
+// NewDeployAccount starts a deploy account transaction. The nonce of the
+// transaction is zero.
+//
+// Parameters:
+// - chainID: the chain ID, e.g. "SN_MAIN"
+// - classHash: the class of the account
+// - salt: the salt of the address
+// - constructorCalldata: the calldata of the constructor of the account
+// Returns:
+// - *Builder: the builder
+func NewDeployAccount(chainID string, classHash, salt *felt.Felt, constructorCalldata []*felt.Felt) *Builder {
+	b := newBuilder(KindDeployAccount, chainID, nil)
+	b.classHash = classHash
+	b.salt = salt
+	b.constructorCalldata = nonNil(constructorCalldata)
+	b.nonce = new(felt.Felt)
+	b.sender, b.err = b.calculator().PrecomputeAddress(&felt.Zero, salt, classHash, b.constructorCalldata)
+	return b
+}
+
+// newBuilder creates a builder with the default fields of V3 transactions.
+//
+// Parameters:
+// - kind: the kind of transaction
+// - chainID: the chain ID
+// - sender: the address of the account
+// Returns:
+// - *Builder: the builder
+func newBuilder(kind Kind, chainID string, sender *felt.Felt) *Builder {
+	return &Builder{
+		kind:                  kind,
+		chainID:               new(felt.Felt).SetBytes([]byte(chainID)),
+		sender:                sender,
+		paymasterData:         []*felt.Felt{},
+		accountDeploymentData: []*felt.Felt{},
+		nonceDAMode:           rpc.DAModeL1,
+		feeDAMode:             rpc.DAModeL1,
+	}
+}
+
+// Kind returns the kind of transaction built.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - Kind: the kind
+func (b *Builder) Kind() Kind {
+	return b.kind
+}
+
+// Address returns the address of the account sending the transaction, the
+// precomputed address of the account deployed for deploy account transactions.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the address
+func (b *Builder) Address() *felt.Felt {
+	return b.sender
+}
+
+// SetCalls sets the calls of an invoke transaction, formatted for the
+// __execute__ entrypoint of the account.
+//
+// Parameters:
+// - calls: the calls
+// - cairoVersion: the Cairo version of the account, 0 or 2
+// Returns:
+// - *Builder: the builder
+func (b *Builder) SetCalls(calls []rpc.FunctionCall, cairoVersion int) *Builder {
+	switch cairoVersion {
+	case 0:
+		return b.SetCalldata(account.FmtCallDataCairo0(calls))
+	case 2:
+		return b.SetCalldata(account.FmtCallDataCairo2(calls))
+	}
+	return b.fail(fmt.Errorf("txbuilder: Cairo version %d not supported", cairoVersion))
+}
+
+// SetCalldata sets the calldata of an invoke transaction, already formatted
+// for the __execute__ entrypoint of the account.
+//
+// Parameters:
+// - calldata: the calldata
+// Returns:
+// - *Builder: the builder
+func (b *Builder) SetCalldata(calldata []*felt.Felt) *Builder {
+	if b.kind != KindInvoke {
+		return b.fail(fmt.Errorf("%w: calldata of a %s transaction", ErrWrongKind, b.kind))
+	}
+	b.calldata = calldata
+	return b
+}
+
+// SetNonce sets the nonce of the account.
+//
+// Parameters:
+// - nonce: the nonce
+// Returns:
+// - *Builder: the builder
+func (b *Builder) SetNonce(nonce *felt.Felt) *Builder {
+	b.nonce = nonce
+	return b
+}
+
+// SetResourceBounds sets the max amounts and max prices per unit of the
+// resources the transaction may use.
+//
+// Parameters:
+// - bounds: the resource bounds
+// Returns:
+// - *Builder: the builder
+func (b *Builder) SetResourceBounds(bounds rpc.ResourceBoundsMapping) *Builder {
+	b.resourceBounds = bounds
+	return b
+}
+
+// SetTip sets the tip paid to the sequencer.
+//
+// Parameters:
+// - tip: the tip
+// Returns:
+// - *Builder: the builder
+func (b *Builder) SetTip(tip uint64) *Builder {
+	b.tip = tip
+	return b
+}
+
+// SetPaymasterData sets the data of the paymaster paying for the transaction.
+//
+// Parameters:
+// - data: the paymaster data
+// Returns:
+// - *Builder: the builder
+func (b *Builder) SetPaymasterData(data []*felt.Felt) *Builder {
+	b.paymasterData = nonNil(data)
+	return b
+}
+
+// SetAccountDeploymentData sets the data deploying the account sending an
+// invoke or a declare transaction.
+//
+// Parameters:
+// - data: the account deployment data
+// Returns:
+// - *Builder: the builder
+func (b *Builder) SetAccountDeploymentData(data []*felt.Felt) *Builder {
+	if b.kind == KindDeployAccount {
+		return b.fail(fmt.Errorf("%w: account deployment data of a %s transaction", ErrWrongKind, b.kind))
+	}
+	b.accountDeploymentData = nonNil(data)
+	return b
+}
+
+// SetDataAvailabilityModes sets the storage domains of the nonce and of the
+// balance paying the fee, L1 by default.
+//
+// Parameters:
+// - nonceMode: the domain of the nonce
+// - feeMode: the domain of the balance
+// Returns:
+// - *Builder: the builder
+func (b *Builder) SetDataAvailabilityModes(nonceMode, feeMode rpc.DataAvailabilityMode) *Builder {
+	b.nonceDAMode = nonceMode
+	b.feeDAMode = feeMode
+	return b
+}
+
+// SetSignature sets the signature of the hash of the transaction, e.g. the r
+// and s of a signature made offline.
+//
+// Parameters:
+// - signature: the signature
+// Returns:
+// - *Builder: the builder
+func (b *Builder) SetSignature(signature ...*felt.Felt) *Builder {
+	b.signature = signature
+	return b
+}
+
+// Sign signs the hash of the transaction with a keystore, for workflows
+// where the key is at hand.
+//
+// Parameters:
+// - ctx: the context
+// - ks: the keystore
+// - publicKey: the public key the keystore signs with
+// Returns:
+// - *Builder: the builder
+func (b *Builder) Sign(ctx context.Context, ks account.Keystore, publicKey string) *Builder {
+	txHash, err := b.Hash()
+	if err != nil {
+		return b.fail(err)
+	}
+	r, s, err := ks.Sign(ctx, publicKey, utils.FeltToBigInt(txHash))
+	if err != nil {
+		return b.fail(err)
+	}
+	return b.SetSignature(utils.BigIntToFelt(r), utils.BigIntToFelt(s))
+}
+
+// Hash calculates the hash of the transaction, the message signed by the
+// account.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the hash
+// - error: the first error of the builder, or ErrMissingField
+func (b *Builder) Hash() (*felt.Felt, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+	calculator := b.calculator()
+	switch b.kind {
+	case KindInvoke:
+		return calculator.TransactionHashInvoke(b.invoke())
+	case KindDeclare:
+		return calculator.TransactionHashDeclare(b.declare())
+	default:
+		return calculator.TransactionHashDeployAccount(b.deployAccount(), b.sender)
+	}
+}
+
+// Transaction returns the signed transaction, ready to be broadcast with the
+// AddInvokeTransaction, AddDeclareTransaction or AddDeployAccountTransaction
+// method of a provider.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - rpc.BroadcastTxn: a rpc.BroadcastInvokev3Txn, rpc.BroadcastDeclareTxnV3 or rpc.BroadcastDeployAccountTxnV3
+// - error: the first error of the builder, ErrMissingField or ErrNotSigned
+func (b *Builder) Transaction() (rpc.BroadcastTxn, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+	if len(b.signature) == 0 {
+		return nil, ErrNotSigned
+	}
+	switch b.kind {
+	case KindInvoke:
+		return rpc.BroadcastInvokev3Txn{InvokeTxnV3: b.invoke()}, nil
+	case KindDeclare:
+		tx := b.declare()
+		return rpc.BroadcastDeclareTxnV3{
+			Type:                  tx.Type,
+			SenderAddress:         tx.SenderAddress,
+			CompiledClassHash:     tx.CompiledClassHash,
+			Version:               rpc.NumAsHex(tx.Version),
+			Signature:             tx.Signature,
+			Nonce:                 tx.Nonce,
+			ContractClass:         b.class,
+			ResourceBounds:        tx.ResourceBounds,
+			Tip:                   tx.Tip,
+			PayMasterData:         tx.PayMasterData,
+			AccountDeploymentData: tx.AccountDeploymentData,
+			NonceDataMode:         tx.NonceDataMode,
+			FeeMode:               tx.FeeMode,
+		}, nil
+	default:
+		return rpc.BroadcastDeployAccountTxnV3{DeployAccountTxnV3: b.deployAccount()}, nil
+	}
+}
+
+// MarshalJSON encodes the signed transaction, as sent to the node.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the JSON of the transaction
+// - error: an error of Transaction
+func (b *Builder) MarshalJSON() ([]byte, error) {
+	tx, err := b.Transaction()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(tx)
+}
+
+// fail records the first error of the builder.
+//
+// Parameters:
+// - err: the error
+// Returns:
+// - *Builder: the builder
+func (b *Builder) fail(err error) *Builder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// check returns the first error of the builder, or the first field missing
+// from the transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - error: the error
+func (b *Builder) check() error {
+	if b.err != nil {
+		return b.err
+	}
+	missing := ""
+	switch {
+	case b.sender == nil:
+		missing = "sender address"
+	case b.kind == KindInvoke && len(b.calldata) == 0:
+		missing = "calls"
+	case b.kind == KindDeclare && b.compiledClassHash == nil:
+		missing = "compiled class hash"
+	case b.kind == KindDeployAccount && (b.classHash == nil || b.salt == nil):
+		missing = "class hash and salt"
+	case b.nonce == nil:
+		missing = "nonce"
+	case b.resourceBounds == (rpc.ResourceBoundsMapping{}):
+		missing = "resource bounds"
+	}
+	if missing != "" {
+		return fmt.Errorf("%w: %s of the %s transaction", ErrMissingField, missing, b.kind)
+	}
+	return nil
+}
+
+// calculator returns an account calculating the hashes of the chain, offline.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *account.Account: the account
+func (b *Builder) calculator() *account.Account {
+	return &account.Account{ChainId: b.chainID, AccountAddress: b.sender}
+}
+
+// invoke returns the invoke transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - rpc.InvokeTxnV3: the transaction
+func (b *Builder) invoke() rpc.InvokeTxnV3 {
+	return rpc.InvokeTxnV3{
+		Type:                  rpc.TransactionType_Invoke,
+		SenderAddress:         b.sender,
+		Calldata:              b.calldata,
+		Version:               rpc.TransactionV3,
+		Signature:             nonNil(b.signature),
+		Nonce:                 b.nonce,
+		ResourceBounds:        b.resourceBounds,
+		Tip:                   b.tipU64(),
+		PayMasterData:         b.paymasterData,
+		AccountDeploymentData: b.accountDeploymentData,
+		NonceDataMode:         b.nonceDAMode,
+		FeeMode:               b.feeDAMode,
+	}
+}
+
+// declare returns the declare transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - rpc.DeclareTxnV3: the transaction
+func (b *Builder) declare() rpc.DeclareTxnV3 {
+	return rpc.DeclareTxnV3{
+		Type:                  rpc.TransactionType_Declare,
+		SenderAddress:         b.sender,
+		CompiledClassHash:     b.compiledClassHash,
+		Version:               rpc.TransactionV3,
+		Signature:             nonNil(b.signature),
+		Nonce:                 b.nonce,
+		ClassHash:             b.classHash,
+		ResourceBounds:        b.resourceBounds,
+		Tip:                   b.tipU64(),
+		PayMasterData:         b.paymasterData,
+		AccountDeploymentData: b.accountDeploymentData,
+		NonceDataMode:         b.nonceDAMode,
+		FeeMode:               b.feeDAMode,
+	}
+}
+
+// deployAccount returns the deploy account transaction.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - rpc.DeployAccountTxnV3: the transaction
+func (b *Builder) deployAccount() rpc.DeployAccountTxnV3 {
+	return rpc.DeployAccountTxnV3{
+		Type:                rpc.TransactionType_DeployAccount,
+		Version:             rpc.TransactionV3,
+		Signature:           nonNil(b.signature),
+		Nonce:               b.nonce,
+		ContractAddressSalt: b.salt,
+		ConstructorCalldata: b.constructorCalldata,
+		ClassHash:           b.classHash,
+		ResourceBounds:      b.resourceBounds,
+		Tip:                 b.tipU64(),
+		PayMasterData:       b.paymasterData,
+		NonceDataMode:       b.nonceDAMode,
+		FeeMode:             b.feeDAMode,
+	}
+}
+
+// tipU64 returns the tip as a hexadecimal U64.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - rpc.U64: the tip
+func (b *Builder) tipU64() rpc.U64 {
+	return rpc.U64(fmt.Sprintf("0x%x", b.tip))
+}
+
+// nonNil returns an empty slice for nil, as the node expects lists.
+//
+// Parameters:
+// - felts: the felts
+// Returns:
+// - []*felt.Felt: felts, or an empty slice
+func nonNil(felts []*felt.Felt) []*felt.Felt {
+	if felts == nil {
+		return []*felt.Felt{}
+	}
+	return felts
+}
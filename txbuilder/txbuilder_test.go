@@ -0,0 +1,168 @@
+package txbuilder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+	"github.com/xiang-xx/starknet.go/account"
+	"github.com/xiang-xx/starknet.go/curve"
+	"github.com/xiang-xx/starknet.go/rpc"
+	"github.com/xiang-xx/starknet.go/utils"
+)
+
+var bounds = rpc.ResourceBoundsMapping{
+	L1Gas: rpc.ResourceBounds{MaxAmount: "0x100", MaxPricePerUnit: "0x10"},
+	L2Gas: rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"},
+}
+
+// TestInvoke tests that an invoke transaction built step by step has the hash
+// of the account, can be signed offline or with a keystore, and serializes
+// with its signature.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestInvoke(t *testing.T) {
+	sender := utils.Uint64ToFelt(0xacc)
+	calls := []rpc.FunctionCall{{
+		ContractAddress:    utils.Uint64ToFelt(0x10),
+		EntryPointSelector: utils.SelectorTransfer,
+		Calldata:           []*felt.Felt{utils.Uint64ToFelt(0x20), utils.Uint64ToFelt(1000), utils.Uint64ToFelt(0)},
+	}}
+	b := NewInvoke("SN_SEPOLIA", sender).SetCalls(calls, 2).SetResourceBounds(bounds)
+
+	_, err := b.Hash()
+	require.True(t, errors.Is(err, ErrMissingField))
+
+	b.SetNonce(utils.Uint64ToFelt(3)).SetTip(5)
+	txHash, err := b.Hash()
+	require.NoError(t, err)
+	expected, err := (&account.Account{ChainId: new(felt.Felt).SetBytes([]byte("SN_SEPOLIA"))}).TransactionHashInvoke(rpc.InvokeTxnV3{
+		Type:                  rpc.TransactionType_Invoke,
+		SenderAddress:         sender,
+		Calldata:              account.FmtCallDataCairo2(calls),
+		Version:               rpc.TransactionV3,
+		Nonce:                 utils.Uint64ToFelt(3),
+		ResourceBounds:        bounds,
+		Tip:                   "0x5",
+		PayMasterData:         []*felt.Felt{},
+		AccountDeploymentData: []*felt.Felt{},
+		NonceDataMode:         rpc.DAModeL1,
+		FeeMode:               rpc.DAModeL1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected, txHash)
+
+	_, err = b.Transaction()
+	require.True(t, errors.Is(err, ErrNotSigned))
+
+	// offline signature
+	ks, pub, _ := account.GetRandomKeys()
+	r, s, err := ks.Sign(context.Background(), pub.String(), utils.FeltToBigInt(txHash))
+	require.NoError(t, err)
+	data, err := json.Marshal(b.SetSignature(utils.BigIntToFelt(r), utils.BigIntToFelt(s)))
+	require.NoError(t, err)
+	var decoded rpc.InvokeTxnV3
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, rpc.TransactionType_Invoke, decoded.Type)
+	require.Equal(t, rpc.TransactionV3, decoded.Version)
+	require.Equal(t, rpc.U64("0x5"), decoded.Tip)
+	require.Equal(t, []*felt.Felt{utils.BigIntToFelt(r), utils.BigIntToFelt(s)}, decoded.Signature)
+
+	// keystore signature
+	tx, err := b.SetSignature().Sign(context.Background(), ks, pub.String()).Transaction()
+	require.NoError(t, err)
+	signature := tx.(rpc.BroadcastInvokev3Txn).Signature
+	valid, err := curve.Curve.VerifyBatch(
+		[]*big.Int{utils.FeltToBigInt(txHash)},
+		[]*big.Int{utils.FeltToBigInt(signature[0])},
+		[]*big.Int{utils.FeltToBigInt(signature[1])},
+		[]*big.Int{utils.FeltToBigInt(pub)},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []bool{true}, valid)
+
+	_, err = NewInvoke("SN_SEPOLIA", sender).SetCalls(calls, 1).Hash()
+	require.Error(t, err)
+}
+
+// TestDeployAccount tests that a deploy account transaction is sent by the
+// precomputed address of the account and has the hash of the account.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDeployAccount(t *testing.T) {
+	classHash := utils.Uint64ToFelt(0xc1a55)
+	salt := utils.Uint64ToFelt(7)
+	calldata := []*felt.Felt{utils.Uint64ToFelt(0x9e7)}
+	b := NewDeployAccount("SN_MAIN", classHash, salt, calldata).SetResourceBounds(bounds)
+	require.Equal(t, KindDeployAccount, b.Kind())
+
+	calculator := &account.Account{ChainId: new(felt.Felt).SetBytes([]byte("SN_MAIN"))}
+	address, err := calculator.PrecomputeAddress(&felt.Zero, salt, classHash, calldata)
+	require.NoError(t, err)
+	require.Equal(t, address, b.Address())
+
+	txHash, err := b.Hash()
+	require.NoError(t, err)
+	expected, err := calculator.TransactionHashDeployAccount(rpc.DeployAccountTxnV3{
+		Type:                rpc.TransactionType_DeployAccount,
+		Version:             rpc.TransactionV3,
+		Nonce:               &felt.Zero,
+		ContractAddressSalt: salt,
+		ConstructorCalldata: calldata,
+		ClassHash:           classHash,
+		ResourceBounds:      bounds,
+		Tip:                 "0x0",
+		PayMasterData:       []*felt.Felt{},
+		NonceDataMode:       rpc.DAModeL1,
+		FeeMode:             rpc.DAModeL1,
+	}, address)
+	require.NoError(t, err)
+	require.Equal(t, expected, txHash)
+
+	_, err = b.SetAccountDeploymentData([]*felt.Felt{}).Hash()
+	require.True(t, errors.Is(err, ErrWrongKind))
+}
+
+// TestDeclare tests that a declare transaction declares the hash of its class
+// and broadcasts the class.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestDeclare(t *testing.T) {
+	content, err := os.ReadFile("../hash/tests/hello_starknet_compiled.sierra.json")
+	require.NoError(t, err)
+	var class rpc.ContractClass
+	require.NoError(t, json.Unmarshal(content, &class))
+	compiledClassHash := utils.TestHexToFelt(t, "0x785fa5f2bacf0bfe3bc413be5820a61e1ea63f2ec27ef00331ee9f46ad07603")
+
+	b := NewDeclare("SN_MAIN", utils.Uint64ToFelt(0xacc), &class, compiledClassHash).SetNonce(utils.Uint64ToFelt(1)).SetResourceBounds(bounds)
+	_, err = b.Hash()
+	require.NoError(t, err)
+	tx, err := b.SetSignature(utils.Uint64ToFelt(1), utils.Uint64ToFelt(2)).Transaction()
+	require.NoError(t, err)
+	declare := tx.(rpc.BroadcastDeclareTxnV3)
+	require.Equal(t, &class, declare.ContractClass)
+	require.Equal(t, compiledClassHash, declare.CompiledClassHash)
+	require.Equal(t, "0x4ec2ecf58014bc2ffd7c84843c3525e5ecb0a2cac33c47e9c347f39fc0c0944", b.declare().ClassHash.String())
+
+	_, err = b.SetCalldata([]*felt.Felt{}).Hash()
+	require.True(t, errors.Is(err, ErrWrongKind))
+	_, err = NewDeclare("SN_MAIN", utils.Uint64ToFelt(0xacc), nil, compiledClassHash).Hash()
+	require.True(t, errors.Is(err, ErrMissingField))
+}
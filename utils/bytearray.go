@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// byteArrayWordLen is the number of bytes stored in each full word of a Cairo ByteArray
+const byteArrayWordLen = 31
+
+var ErrInvalidByteArray = errors.New("invalid ByteArray serialization")
+
+// StringToByteArrayFelts serializes a string as a Cairo 1 ByteArray.
+//
+// The string is split into 31-byte words. The serialization is the number of
+// full words, the full words, the pending word holding the remaining bytes,
+// and the length of the pending word.
+//
+// Parameters:
+// - s: the string to serialize
+// Returns:
+// - []*felt.Felt: the serialized ByteArray
+func StringToByteArrayFelts(s string) []*felt.Felt {
+	b := []byte(s)
+	full := len(b) / byteArrayWordLen
+	result := make([]*felt.Felt, 0, full+3)
+	result = append(result, new(felt.Felt).SetUint64(uint64(full)))
+	for i := 0; i < full; i++ {
+		result = append(result, new(felt.Felt).SetBytes(b[i*byteArrayWordLen:(i+1)*byteArrayWordLen]))
+	}
+	pending := b[full*byteArrayWordLen:]
+	return append(result, new(felt.Felt).SetBytes(pending), new(felt.Felt).SetUint64(uint64(len(pending))))
+}
+
+// ByteArrayFeltsToString deserializes a Cairo 1 ByteArray, such as the name
+// returned by a Cairo 1 token contract, to a string.
+//
+// Parameters:
+// - arr: the serialized ByteArray, without any trailing felt
+// Returns:
+// - string: the deserialized string
+// - error: an error if arr is not a valid ByteArray serialization
+func ByteArrayFeltsToString(arr []*felt.Felt) (string, error) {
+	if len(arr) < 3 {
+		return "", fmt.Errorf("%w: expected at least 3 felts, got %d", ErrInvalidByteArray, len(arr))
+	}
+	for i, f := range arr {
+		if f == nil {
+			return "", fmt.Errorf("%w: nil felt at position %d", ErrInvalidByteArray, i)
+		}
+	}
+	full := arr[0].Uint64()
+	if !arr[0].Equal(new(felt.Felt).SetUint64(full)) || full != uint64(len(arr)-3) {
+		return "", fmt.Errorf("%w: %d felts can not hold %s full words", ErrInvalidByteArray, len(arr), arr[0])
+	}
+	pendingLen := arr[len(arr)-1].Uint64()
+	if !arr[len(arr)-1].Equal(new(felt.Felt).SetUint64(pendingLen)) || pendingLen >= byteArrayWordLen {
+		return "", fmt.Errorf("%w: pending word length %s", ErrInvalidByteArray, arr[len(arr)-1])
+	}
+
+	b := make([]byte, 0, int(full)*byteArrayWordLen+int(pendingLen))
+	for _, word := range arr[1 : len(arr)-2] {
+		w, err := wordBytes(word, byteArrayWordLen)
+		if err != nil {
+			return "", err
+		}
+		b = append(b, w...)
+	}
+	w, err := wordBytes(arr[len(arr)-2], int(pendingLen))
+	if err != nil {
+		return "", err
+	}
+	return string(append(b, w...)), nil
+}
+
+// wordBytes returns the size trailing bytes of the big-endian representation
+// of a ByteArray word.
+//
+// Parameters:
+// - word: the word
+// - size: the number of bytes held by the word
+// Returns:
+// - []byte: the bytes of the word
+// - error: an error if the word holds more than size bytes
+func wordBytes(word *felt.Felt, size int) ([]byte, error) {
+	b := word.Bytes()
+	for _, c := range b[:len(b)-size] {
+		if c != 0 {
+			return nil, fmt.Errorf("%w: word %s is longer than %d bytes", ErrInvalidByteArray, word, size)
+		}
+	}
+	return b[len(b)-size:], nil
+}
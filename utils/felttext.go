@@ -0,0 +1,212 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// shortStringLen is the maximum length of a Cairo short string
+const shortStringLen = 31
+
+var (
+	ErrInvalidFelt        = errors.New("invalid felt")
+	ErrInvalidShortString = errors.New("invalid short string")
+)
+
+const hexDigits = "0123456789abcdef"
+
+// Felt is a felt.Felt with text conversions: it marshals to its hexadecimal
+// form, and unmarshals from any form FeltFromString accepts, so that it can
+// be used in configuration files, as a JSON map key, or with flag.TextVar.
+// Convert between the two types with (*utils.Felt)(f) and (*felt.Felt)(f).
+type Felt felt.Felt
+
+// FeltFromString parses a felt from a hexadecimal number with a 0x prefix, a
+// decimal number, or otherwise a Cairo short string of at most 31 ASCII
+// characters, e.g. "0x534e5f4d41494e", "1000" or "SN_MAIN". Whitespace around
+// numbers is ignored, and underscores may separate their digits. Numbers must
+// be lower than the field prime, they are not reduced.
+//
+// Parameters:
+// - s: the string
+// Returns:
+// - *felt.Felt: the felt
+// - error: an error wrapping ErrInvalidFelt or ErrInvalidShortString describing the problem
+func FeltFromString(s string) (*felt.Felt, error) {
+	input := strings.TrimSpace(s)
+	if input == "" {
+		return nil, fmt.Errorf("%w: empty string", ErrInvalidFelt)
+	}
+	if hasHexPrefix(input) {
+		v, err := parseDigits(input[2:], 16)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidFelt, s, err)
+		}
+		return setCanonical(s, v.Bytes())
+	}
+	if isDecimal(input) {
+		v, err := parseDigits(input, 10)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidFelt, s, err)
+		}
+		return setCanonical(s, v.Bytes())
+	}
+	return ShortStringToFelt(s)
+}
+
+// ShortStringToFelt encodes a Cairo short string, at most 31 ASCII
+// characters, as a felt.
+//
+// Parameters:
+// - s: the short string
+// Returns:
+// - *felt.Felt: the felt
+// - error: an error wrapping ErrInvalidShortString if s is too long or not ASCII
+func ShortStringToFelt(s string) (*felt.Felt, error) {
+	if len(s) > shortStringLen {
+		return nil, fmt.Errorf("%w: %q: %d characters, at most %d allowed", ErrInvalidShortString, s, len(s), shortStringLen)
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return nil, fmt.Errorf("%w: %q: non-ASCII character at position %d", ErrInvalidShortString, s, i)
+		}
+	}
+	return new(felt.Felt).SetBytes([]byte(s)), nil
+}
+
+// ToShortString decodes the felt as a Cairo short string, e.g. a chain ID.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the short string, empty for zero
+// - error: an error wrapping ErrInvalidShortString if the felt is not printable ASCII
+func (f Felt) ToShortString() (string, error) {
+	b := (*felt.Felt)(&f).Bytes()
+	i := 0
+	for i < len(b) && b[i] == 0 {
+		i++
+	}
+	for j := i; j < len(b); j++ {
+		if b[j] < 0x20 || b[j] > 0x7e {
+			return "", fmt.Errorf("%w: %s: non-printable byte 0x%02x", ErrInvalidShortString, f, b[j])
+		}
+	}
+	return string(b[i:]), nil
+}
+
+// AppendHex appends the hexadecimal form of the felt, with a 0x prefix and
+// without leading zeros, to dst, without allocating if dst has room for it.
+//
+// Parameters:
+// - dst: the buffer appended to
+// Returns:
+// - []byte: the extended buffer
+func (f Felt) AppendHex(dst []byte) []byte {
+	b := (*felt.Felt)(&f).Bytes()
+	dst = append(dst, '0', 'x')
+	leading := true
+	for _, c := range b {
+		if leading && c == 0 {
+			continue
+		}
+		if leading && c < 0x10 {
+			dst = append(dst, hexDigits[c])
+		} else {
+			dst = append(dst, hexDigits[c>>4], hexDigits[c&0xf])
+		}
+		leading = false
+	}
+	if leading {
+		dst = append(dst, '0')
+	}
+	return dst
+}
+
+// String returns the hexadecimal form of the felt.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the hexadecimal form, with a 0x prefix
+func (f Felt) String() string {
+	var buf [66]byte
+	return string(f.AppendHex(buf[:0]))
+}
+
+// MarshalText encodes the felt in its hexadecimal form.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []byte: the hexadecimal form, with a 0x prefix
+// - error: always nil
+func (f Felt) MarshalText() ([]byte, error) {
+	return f.AppendHex(make([]byte, 0, 66)), nil
+}
+
+// UnmarshalText decodes a felt with FeltFromString. The felt is left
+// unchanged on error.
+//
+// Parameters:
+// - text: the text
+// Returns:
+// - error: an error if the text is not a felt
+func (f *Felt) UnmarshalText(text []byte) error {
+	v, err := FeltFromString(string(text))
+	if err != nil {
+		return err
+	}
+	*f = Felt(*v)
+	return nil
+}
+
+// setCanonical creates a felt from big-endian bytes, rejecting values of at
+// least the field prime.
+//
+// Parameters:
+// - s: the string the bytes were parsed from, for errors
+// - b: the bytes
+// Returns:
+// - *felt.Felt: the felt
+// - error: an error wrapping ErrInvalidFelt if the value does not fit
+func setCanonical(s string, b []byte) (*felt.Felt, error) {
+	if len(b) > 32 {
+		return nil, fmt.Errorf("%w: %q: larger than the field prime", ErrInvalidFelt, s)
+	}
+	var buf [32]byte
+	copy(buf[32-len(b):], b)
+	var element felt.Felt
+	if err := element.Impl().SetBytesCanonical(buf[:]); err != nil {
+		return nil, fmt.Errorf("%w: %q: larger than the field prime", ErrInvalidFelt, s)
+	}
+	return &element, nil
+}
+
+// isDecimal reports whether s only has decimal digits and underscores,
+// starting with a digit.
+//
+// Parameters:
+// - s: the string
+// Returns:
+// - bool: true if s is a decimal number
+func isDecimal(s string) bool {
+	if s[0] < '0' || s[0] > '9' {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if (s[i] < '0' || s[i] > '9') && s[i] != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,289 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var (
+	ErrInvalidAmount  = errors.New("invalid amount")
+	ErrInvalidAddress = errors.New("invalid address")
+
+	// maxAddress is the bound of contract addresses, 2^251 - 256
+	maxAddress = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 251), big.NewInt(256))
+)
+
+// DefaultUnits are the units known to ParseAmount, by their number of
+// decimals: wei and fri are the base units of ETH and STRK.
+var DefaultUnits = map[string]int{
+	"wei":  0,
+	"fri":  0,
+	"gwei": 9,
+	"gfri": 9,
+	"eth":  18,
+	"strk": 18,
+}
+
+// AmountParser parses user-provided amounts, e.g. "1.5 strk", "1_000 gwei",
+// "0x2386f26fc10000" or "250000", into base units.
+type AmountParser struct {
+	// Decimals is the number of decimals of amounts without unit, 0 if they
+	// are in base units
+	Decimals int
+	// Units are the accepted unit suffixes, case insensitive, by their
+	// number of decimals
+	Units map[string]int
+}
+
+// ParseAmount parses an amount with the default units, amounts without unit
+// being in base units.
+//
+// Parameters:
+// - s: the amount
+// Returns:
+// - *Uint256: the amount in base units
+// - error: an error wrapping ErrInvalidAmount describing the problem
+func ParseAmount(s string) (*Uint256, error) {
+	return AmountParser{Units: DefaultUnits}.Parse(s)
+}
+
+// ParseUnits parses a decimal amount of a unit with decimals into base units,
+// e.g. ParseUnits("1.5", 18) for 1.5 STRK, as a u256 whose Low and High are
+// the felts of its calldata. Underscores may separate digits, and hexadecimal
+// amounts are in base units.
+//
+// Parameters:
+// - s: the amount, without unit
+// - decimals: the number of decimals of the unit, e.g. 18 for ETH and STRK, 6 for USDC
+// Returns:
+// - *Uint256: the amount in base units
+// - error: an error wrapping ErrInvalidAmount describing the problem
+func ParseUnits(s string, decimals int) (*Uint256, error) {
+	if decimals < 0 {
+		return nil, fmt.Errorf("%w: %q: negative decimals %d", ErrInvalidAmount, s, decimals)
+	}
+	return AmountParser{Decimals: decimals}.Parse(s)
+}
+
+// Parse parses an amount. Whitespace around the amount and between the
+// number and its unit is ignored, and underscores may separate digits.
+// Hexadecimal amounts are in base units and take no unit.
+//
+// Parameters:
+// - s: the amount
+// Returns:
+// - *Uint256: the amount in base units
+// - error: an error wrapping ErrInvalidAmount describing the problem
+func (p AmountParser) Parse(s string) (*Uint256, error) {
+	input := strings.TrimSpace(s)
+	if input == "" {
+		return nil, fmt.Errorf("%w: empty amount", ErrInvalidAmount)
+	}
+
+	var v *big.Int
+	var err error
+	if hasHexPrefix(input) {
+		if len(strings.Fields(input)) > 1 {
+			return nil, fmt.Errorf("%w: %q: hexadecimal amounts are in base units and take no unit", ErrInvalidAmount, s)
+		}
+		v, err = parseDigits(input[2:], 16)
+	} else {
+		number, unit := input, ""
+		if i := strings.LastIndexAny(input, "0123456789_."); i >= 0 && i < len(input)-1 {
+			number, unit = strings.TrimSpace(input[:i+1]), strings.ToLower(strings.TrimSpace(input[i+1:]))
+		}
+		decimals := p.Decimals
+		if unit != "" {
+			d, ok := p.Units[unit]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q: unknown unit %q, expected one of %s", ErrInvalidAmount, s, unit, unitNames(p.Units))
+			}
+			decimals = d
+		}
+		v, err = parseDecimal(number, decimals)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidAmount, s, err)
+	}
+	if v.Cmp(maxUint256) > 0 {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidAmount, s, ErrUint256Overflow)
+	}
+	return NewUint256(v)
+}
+
+// ParseAddress parses a user-provided contract address, hexadecimal with a
+// 0x prefix or decimal. Whitespace around the address is ignored, and
+// underscores may separate digits.
+//
+// Parameters:
+// - s: the address
+// Returns:
+// - *felt.Felt: the address
+// - error: an error wrapping ErrInvalidAddress describing the problem
+func ParseAddress(s string) (*felt.Felt, error) {
+	input := strings.TrimSpace(s)
+	if input == "" {
+		return nil, fmt.Errorf("%w: empty address", ErrInvalidAddress)
+	}
+	var v *big.Int
+	var err error
+	if hasHexPrefix(input) {
+		if len(strings.ReplaceAll(input[2:], "_", "")) > 64 {
+			return nil, fmt.Errorf("%w: %q: more than 64 hexadecimal digits", ErrInvalidAddress, s)
+		}
+		v, err = parseDigits(input[2:], 16)
+	} else {
+		v, err = parseDigits(input, 10)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidAddress, s, err)
+	}
+	if v.Cmp(maxAddress) >= 0 {
+		return nil, fmt.Errorf("%w: %q: addresses must be lower than 2^251 - 256", ErrInvalidAddress, s)
+	}
+	return BigIntToFelt(v), nil
+}
+
+// FormatUnits formats an amount in base units as a decimal number of a unit
+// with decimals, the inverse of ParseUnits, e.g. FormatUnits(1500000000000000000,
+// 18) is "1.5". Trailing zeros of the fraction are trimmed. A u256 is
+// formatted from its BigInt.
+//
+// Parameters:
+// - amount: the amount in base units
+// - decimals: the number of decimals of the unit, e.g. 18 for ETH and STRK
+// Returns:
+// - string: the formatted amount
+func FormatUnits(amount *big.Int, decimals int) string {
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+	digits := new(big.Int).Abs(amount).String()
+	if decimals <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	integer, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if fraction == "" {
+		return sign + integer
+	}
+	return sign + integer + "." + fraction
+}
+
+// parseDecimal parses a decimal number with an optional fraction, scaled by
+// 10^decimals.
+//
+// Parameters:
+// - s: the number
+// - decimals: the number of decimals
+// Returns:
+// - *big.Int: the scaled number
+// - error: an error describing the problem
+func parseDecimal(s string, decimals int) (*big.Int, error) {
+	integer, fraction, hasFraction := strings.Cut(s, ".")
+	if strings.Contains(fraction, ".") {
+		return nil, errors.New("more than one decimal point")
+	}
+	if integer == "" && (!hasFraction || fraction == "") {
+		return nil, errors.New("no digits")
+	}
+	if hasFraction && fraction == "" {
+		return nil, errors.New("no digits after the decimal point")
+	}
+	if integer == "" {
+		integer = "0"
+	}
+	v, err := parseDigits(integer, 10)
+	if err != nil {
+		return nil, err
+	}
+	v.Mul(v, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	if !hasFraction {
+		return v, nil
+	}
+	if _, err := parseDigits(fraction, 10); err != nil {
+		return nil, fmt.Errorf("in the fraction: %v", err)
+	}
+	fraction = strings.TrimRight(strings.ReplaceAll(fraction, "_", ""), "0")
+	if len(fraction) > decimals {
+		if decimals == 0 {
+			return nil, errors.New("fractional amount in base units, add a unit")
+		}
+		return nil, fmt.Errorf("%d decimals, at most %d allowed", len(fraction), decimals)
+	}
+	if fraction != "" {
+		f, _ := new(big.Int).SetString(fraction, 10)
+		f.Mul(f, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-len(fraction))), nil))
+		v.Add(v, f)
+	}
+	return v, nil
+}
+
+// parseDigits parses digits in a base, underscores being allowed between
+// digits.
+//
+// Parameters:
+// - s: the digits
+// - base: 10 or 16
+// Returns:
+// - *big.Int: the number
+// - error: an error describing the first invalid character
+func parseDigits(s string, base int) (*big.Int, error) {
+	if s == "" {
+		return nil, errors.New("no digits")
+	}
+	var digits strings.Builder
+	for i, c := range s {
+		switch {
+		case c == '_':
+			if i == 0 || i == len(s)-1 || s[i-1] == '_' {
+				return nil, fmt.Errorf("misplaced underscore at position %d, underscores may only separate digits", i)
+			}
+			continue
+		case c == '-' && i == 0:
+			return nil, errors.New("negative values are not allowed")
+		case c >= '0' && c <= '9', base == 16 && (c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'):
+		default:
+			return nil, fmt.Errorf("invalid character %q at position %d", c, i)
+		}
+		digits.WriteRune(c)
+	}
+	v, ok := new(big.Int).SetString(digits.String(), base)
+	if !ok {
+		return nil, errors.New("invalid number")
+	}
+	return v, nil
+}
+
+// hasHexPrefix reports whether s starts with 0x or 0X.
+//
+// Parameters:
+// - s: the string
+// Returns:
+// - bool: true if s starts with 0x or 0X
+func hasHexPrefix(s string) bool {
+	return len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X')
+}
+
+// unitNames returns the names of units, sorted.
+//
+// Parameters:
+// - units: the units
+// Returns:
+// - string: the comma-separated names
+func unitNames(units map[string]int) string {
+	names := make([]string, 0, len(units))
+	for name := range units {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
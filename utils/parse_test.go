@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+)
+
+// TestParseAmount tests the parsing of amounts with units, underscores,
+// fractions and hexadecimal values, and the messages of rejected amounts.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestParseAmount(t *testing.T) {
+	for input, expected := range map[string]string{
+		"250000":           "250000",
+		"  1_000_000 ":     "1000000",
+		"1.5 strk":         "1500000000000000000",
+		"1.5STRK":          "1500000000000000000",
+		".25 eth":          "250000000000000000",
+		"2 gwei":           "2000000000",
+		"1.500 gfri":       "1500000000",
+		"0x2386f26fc10000": "10000000000000000",
+		"0X10":             "16",
+		"7 wei":            "7",
+	} {
+		amount, err := ParseAmount(input)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, amount.BigInt().String(), input)
+	}
+
+	usdc := AmountParser{Decimals: 6}
+	amount, err := usdc.Parse("12.34")
+	require.NoError(t, err)
+	require.Equal(t, "12340000", amount.BigInt().String())
+
+	for input, message := range map[string]string{
+		"":                              "empty amount",
+		"1.5":                           "fractional amount in base units, add a unit",
+		"1.0000000000000000001 eth":     "19 decimals, at most 18 allowed",
+		"1 btc":                         `unknown unit "btc", expected one of eth, fri, gfri, gwei, strk, wei`,
+		"-1 strk":                       "negative values are not allowed",
+		"1__000":                        "misplaced underscore at position 2",
+		"_1":                            "misplaced underscore at position 0",
+		"1e18":                          "invalid character 'e' at position 1",
+		"1.2.3 eth":                     "more than one decimal point",
+		"1. eth":                        "no digits after the decimal point",
+		"0x10 strk":                     "hexadecimal amounts are in base units and take no unit",
+		"0xfg":                          "invalid character 'g' at position 1",
+		"0x1" + strings.Repeat("0", 64): "value overflows u256",
+	} {
+		_, err := ParseAmount(input)
+		require.Error(t, err, input)
+		require.True(t, errors.Is(err, ErrInvalidAmount), input)
+		require.Contains(t, err.Error(), message, input)
+	}
+	_, err = usdc.Parse("1 strk")
+	require.Contains(t, err.Error(), `unknown unit "strk", expected one of `)
+}
+
+// TestUnits tests the formatting of amounts with decimals, and that
+// formatted amounts parse back to the same amounts, up to the u256 range.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestUnits(t *testing.T) {
+	for _, test := range []struct {
+		amount   string
+		decimals int
+		expected string
+	}{
+		{"1500000000000000000", 18, "1.5"},
+		{"1000000000000000000", 18, "1"},
+		{"1", 18, "0.000000000000000001"},
+		{"0", 18, "0"},
+		{"-25", 1, "-2.5"},
+		{"12340000", 6, "12.34"},
+		{"250000", 0, "250000"},
+		{maxUint256.String(), 18, "115792089237316195423570985008687907853269984665640564039457.584007913129639935"},
+	} {
+		amount, _ := new(big.Int).SetString(test.amount, 10)
+		formatted := FormatUnits(amount, test.decimals)
+		require.Equal(t, test.expected, formatted, test.amount)
+		if amount.Sign() >= 0 {
+			parsed, err := ParseUnits(formatted, test.decimals)
+			require.NoError(t, err, formatted)
+			require.Equal(t, amount, parsed.BigInt())
+		}
+	}
+
+	amount, err := ParseUnits("1_000.25", 6)
+	require.NoError(t, err)
+	require.Equal(t, "1000250000", amount.BigInt().String())
+	require.Equal(t, uint64(1000250000), amount.Low().Uint64())
+	require.True(t, amount.High().IsZero())
+	for _, input := range []string{"1.5 strk", "1.0000001", "-1", ""} {
+		_, err := ParseUnits(input, 6)
+		require.True(t, errors.Is(err, ErrInvalidAmount), input)
+	}
+	_, err = ParseUnits("1", -1)
+	require.True(t, errors.Is(err, ErrInvalidAmount))
+}
+
+// TestParseAddress tests the parsing of addresses and the messages of
+// rejected addresses.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestParseAddress(t *testing.T) {
+	for input, expected := range map[string]string{
+		" 0x049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7\n": "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7",
+		"0xdead_beef": "0xdeadbeef",
+		"4660":        "0x1234",
+	} {
+		address, err := ParseAddress(input)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, address.String(), input)
+	}
+	for input, message := range map[string]string{
+		"":                             "empty address",
+		"0x" + strings.Repeat("1", 65): "more than 64 hexadecimal digits",
+		"0x0800000000000000000000000000000000000000000000000000000000000000": "lower than 2^251 - 256",
+		"0xabcz": "invalid character 'z' at position 3",
+		"0x":     "no digits",
+	} {
+		_, err := ParseAddress(input)
+		require.Error(t, err, input)
+		require.True(t, errors.Is(err, ErrInvalidAddress), input)
+		require.Contains(t, err.Error(), message, input)
+	}
+}
+
+// TestFeltFromString tests the detection of hexadecimal numbers, decimal
+// numbers and short strings, the text round trip of Felt, and the rejected
+// strings and felts.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestFeltFromString(t *testing.T) {
+	for input, expected := range map[string]string{
+		"0x534e5f4d41494e": "0x534e5f4d41494e",
+		" 0X1_0 ":          "0x10",
+		"1000":             "0x3e8",
+		"SN_MAIN":          "0x534e5f4d41494e",
+		"1st":              "0x317374",
+		"":                 "",
+		"0x800000000000011000000000000000000000000000000000000000000000001": "",
+	} {
+		f, err := FeltFromString(input)
+		if expected == "" {
+			require.True(t, errors.Is(err, ErrInvalidFelt), input)
+			continue
+		}
+		require.NoError(t, err, input)
+		require.Equal(t, expected, f.String(), input)
+	}
+	_, err := FeltFromString(strings.Repeat("a", 32))
+	require.True(t, errors.Is(err, ErrInvalidShortString))
+	_, err = FeltFromString("é")
+	require.True(t, errors.Is(err, ErrInvalidShortString))
+
+	chainID, err := FeltFromString("SN_SEPOLIA")
+	require.NoError(t, err)
+	s, err := (*Felt)(chainID).ToShortString()
+	require.NoError(t, err)
+	require.Equal(t, "SN_SEPOLIA", s)
+	_, err = (*Felt)(new(felt.Felt).SetUint64(1)).ToShortString()
+	require.True(t, errors.Is(err, ErrInvalidShortString))
+
+	for _, v := range []uint64{0, 1, 0xf, 0x10, 0xabc, 1 << 63} {
+		f := (*Felt)(new(felt.Felt).SetUint64(v))
+		require.Equal(t, (*felt.Felt)(f).String(), f.String())
+	}
+	buf := make([]byte, 0, 66)
+	f := (*Felt)(chainID)
+	require.Equal(t, 0.0, testing.AllocsPerRun(10, func() { buf = f.AppendHex(buf[:0]) }))
+
+	data, err := json.Marshal(map[Felt]*Felt{*f: f})
+	require.NoError(t, err)
+	require.Equal(t, `{"0x534e5f5345504f4c4941":"0x534e5f5345504f4c4941"}`, string(data))
+	var decoded map[Felt]*Felt
+	require.NoError(t, json.Unmarshal([]byte(`{"SN_SEPOLIA":"0x534e5f5345504f4c4941"}`), &decoded))
+	require.Equal(t, map[Felt]*Felt{*f: f}, decoded)
+
+	unchanged := *f
+	require.Error(t, unchanged.UnmarshalText([]byte("0x800000000000011000000000000000000000000000000000000000000000001")))
+	require.Equal(t, *f, unchanged)
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"sync"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// Selectors of common entrypoints and events. They are shared, so they must
+// not be modified.
+var (
+	// SelectorExecute is the selector of the __execute__ entrypoint of accounts
+	SelectorExecute = GetSelectorFromNameFelt("__execute__")
+	// SelectorTransfer is the selector of the transfer entrypoint of ERC20 tokens
+	SelectorTransfer = GetSelectorFromNameFelt("transfer")
+	// SelectorApprove is the selector of the approve entrypoint of ERC20 tokens
+	SelectorApprove = GetSelectorFromNameFelt("approve")
+	// SelectorBalanceOf is the selector of the balanceOf entrypoint of ERC20 tokens
+	SelectorBalanceOf = GetSelectorFromNameFelt("balanceOf")
+	// SelectorTransferEvent is the key of the Transfer event of ERC20 tokens
+	SelectorTransferEvent = GetSelectorFromNameFelt("Transfer")
+)
+
+// selectorCache maps names to their selectors, as felt.Felt values.
+var selectorCache sync.Map
+
+// GetSelectorFromNameCached returns the selector of a name as
+// GetSelectorFromNameFelt does, hashing each name once and serving it from a
+// cache afterwards, for hot paths like building calls or matching events. It
+// is safe for concurrent use.
+//
+// Parameters:
+// - funcName: the name of the function or the event
+// Returns:
+// - *felt.Felt: the selector, a copy the caller may modify
+func GetSelectorFromNameCached(funcName string) *felt.Felt {
+	if selector, ok := selectorCache.Load(funcName); ok {
+		s := selector.(felt.Felt)
+		return &s
+	}
+	selector := GetSelectorFromNameFelt(funcName)
+	selectorCache.Store(funcName, *selector)
+	return selector
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/test-go/testify/require"
+)
+
+// TestGetSelectorFromNameCached tests that cached selectors are the selectors
+// of their names, concurrently, and that the copies returned do not alias the
+// cache.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestGetSelectorFromNameCached(t *testing.T) {
+	require.Equal(t, "0x15d40a3d6ca2ac30f4031e42be28da9b056fef9bb7357ac5e85627ee876e5ad", SelectorExecute.String())
+	require.Equal(t, "0x83afd3f4caedc6eebf44246fe54e38c95e3179a5ec9ea81740eca5b482d12e", SelectorTransfer.String())
+	require.Equal(t, "0x219209e083275171774dab1df80982e9df2096516f06319c5c6d71ae0a8480c", SelectorApprove.String())
+	require.Equal(t, "0x2e4263afad30923c891518314c3c95dbe830a16874e8abc5777a9a20b54c76e", SelectorBalanceOf.String())
+	require.Equal(t, "0x99cd8bde557814842a3121e8ddfd433a539b8c9f14bf31ebf108d12e6196e9", SelectorTransferEvent.String())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, name := range []string{"transfer", "approve", "get_nonce"} {
+				require.Equal(t, GetSelectorFromNameFelt(name), GetSelectorFromNameCached(name))
+			}
+		}()
+	}
+	wg.Wait()
+
+	selector := GetSelectorFromNameCached("transfer")
+	selector.SetUint64(0)
+	require.Equal(t, SelectorTransfer, GetSelectorFromNameCached("transfer"))
+}
@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var ErrSignedOutOfRange = errors.New("value out of range for signed integer")
+
+// FieldPrime is the prime of the Starknet field, P = 2^251 + 17 * 2^192 + 1.
+// It must not be modified.
+var FieldPrime, _ = new(big.Int).SetString("800000000000011000000000000000000000000000000000000000000000001", 16)
+
+// checkSignedBits validates the bit size of a Cairo signed integer type.
+//
+// Parameters:
+// - bits: the bit size
+// Returns:
+// - error: an error if bits is not 8, 16, 32, 64 or 128
+func checkSignedBits(bits int) error {
+	switch bits {
+	case 8, 16, 32, 64, 128:
+		return nil
+	}
+	return fmt.Errorf("unsupported signed integer size i%d", bits)
+}
+
+// signedBounds returns the smallest and largest values of a signed integer type.
+//
+// Parameters:
+// - bits: the bit size
+// Returns:
+// - *big.Int: the smallest value, -2^(bits-1)
+// - *big.Int: the largest value, 2^(bits-1) - 1
+func signedBounds(bits int) (*big.Int, *big.Int) {
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	return new(big.Int).Neg(limit), new(big.Int).Sub(limit, big.NewInt(1))
+}
+
+// SignedToFelt encodes a signed integer of a Cairo iN type (i8 ... i128) as a felt.
+//
+// Cairo represents a negative value x as the field element P - |x|, where P
+// is the Starknet prime, which is the serialization expected in calldata,
+// events and storage.
+//
+// Parameters:
+// - v: the value
+// - bits: the bit size of the Cairo type (8, 16, 32, 64 or 128)
+// Returns:
+// - *felt.Felt: the encoded value
+// - error: an error if v does not fit in the type
+func SignedToFelt(v *big.Int, bits int) (*felt.Felt, error) {
+	if err := checkSignedBits(bits); err != nil {
+		return nil, err
+	}
+	lo, hi := signedBounds(bits)
+	if v.Cmp(lo) < 0 || v.Cmp(hi) > 0 {
+		return nil, fmt.Errorf("%w: %s is not a valid i%d", ErrSignedOutOfRange, v, bits)
+	}
+	return new(felt.Felt).SetBigInt(new(big.Int).Mod(v, FieldPrime)), nil
+}
+
+// FeltToSigned decodes a felt holding a Cairo iN value (i8 ... i128).
+//
+// Field elements greater than (P - 1) / 2 are negative values.
+//
+// Parameters:
+// - f: the encoded value
+// - bits: the bit size of the Cairo type (8, 16, 32, 64 or 128)
+// Returns:
+// - *big.Int: the decoded value
+// - error: an error if f is not a valid value of the type
+func FeltToSigned(f *felt.Felt, bits int) (*big.Int, error) {
+	if err := checkSignedBits(bits); err != nil {
+		return nil, err
+	}
+	v := f.BigInt(new(big.Int))
+	if v.Cmp(new(big.Int).Rsh(FieldPrime, 1)) > 0 {
+		v.Sub(v, FieldPrime)
+	}
+	lo, hi := signedBounds(bits)
+	if v.Cmp(lo) < 0 || v.Cmp(hi) > 0 {
+		return nil, fmt.Errorf("%w: %s is not a valid i%d", ErrSignedOutOfRange, f, bits)
+	}
+	return v, nil
+}
+
+// Int64ToFelt encodes an int64 as a Cairo i64 felt.
+//
+// Parameters:
+// - v: the value
+// Returns:
+// - *felt.Felt: the encoded value
+func Int64ToFelt(v int64) *felt.Felt {
+	f, _ := SignedToFelt(big.NewInt(v), 64)
+	return f
+}
+
+// TwosComplement returns the bits-wide two's-complement representation of a
+// signed value, as used when a Cairo iN is reinterpreted as a uN. It is the
+// inverse of FromTwosComplement.
+//
+// Parameters:
+// - v: the signed value
+// - bits: the bit size (8, 16, 32, 64 or 128)
+// Returns:
+// - *big.Int: the unsigned representation, in [0, 2^bits)
+// - error: an error if v does not fit in the type
+func TwosComplement(v *big.Int, bits int) (*big.Int, error) {
+	if err := checkSignedBits(bits); err != nil {
+		return nil, err
+	}
+	lo, hi := signedBounds(bits)
+	if v.Cmp(lo) < 0 || v.Cmp(hi) > 0 {
+		return nil, fmt.Errorf("%w: %s is not a valid i%d", ErrSignedOutOfRange, v, bits)
+	}
+	return new(big.Int).Mod(v, new(big.Int).Lsh(big.NewInt(1), uint(bits))), nil
+}
+
+// FromTwosComplement returns the signed value of a bits-wide two's-complement representation.
+//
+// Parameters:
+// - u: the unsigned representation, in [0, 2^bits)
+// - bits: the bit size (8, 16, 32, 64 or 128)
+// Returns:
+// - *big.Int: the signed value
+// - error: an error if u does not fit in bits
+func FromTwosComplement(u *big.Int, bits int) (*big.Int, error) {
+	if err := checkSignedBits(bits); err != nil {
+		return nil, err
+	}
+	if u.Sign() < 0 || u.BitLen() > bits {
+		return nil, fmt.Errorf("%w: %s does not fit in %d bits", ErrSignedOutOfRange, u, bits)
+	}
+	v := new(big.Int).Set(u)
+	if u.Bit(bits-1) == 1 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+	}
+	return v, nil
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+// storageAddressBound is the bound of the storage addresses, 2^251 - 256
+var storageAddressBound = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 251), big.NewInt(256))
+
+// StorageVarAddress returns the address of a storage variable, as the Cairo
+// compilers compute it: the sn_keccak of the variable name, then hashed with
+// Pedersen with each key of a map, in order, modulo 2^251 - 256. A u256 key is
+// given as its low and high felts, a struct key as its serialized members,
+// e.g. StorageVarAddress("ERC20_balances", owner) for the balance of an
+// OpenZeppelin Cairo 0 or Cairo 1 token. The value is read with StorageAt.
+//
+// Parameters:
+// - name: the name of the storage variable, e.g. "ERC20_balances"
+// - keys: the keys of the map, none for a plain variable
+// Returns:
+// - *felt.Felt: the storage address of the value
+func StorageVarAddress(name string, keys ...*felt.Felt) *felt.Felt {
+	address := GetSelectorFromNameFelt(name)
+	for _, key := range keys {
+		address = crypto.Pedersen(address, key)
+	}
+	return normalizeStorageAddress(address)
+}
+
+// StorageAddressOffset returns the address of a slot after the base address
+// of a value stored over several slots, as the high limb of a u256 at offset
+// 1 or the members of a struct at the offsets of their serialization.
+//
+// Parameters:
+// - base: the address of the first slot, as returned by StorageVarAddress
+// - offset: the offset of the slot
+// Returns:
+// - *felt.Felt: the storage address of the slot
+func StorageAddressOffset(base *felt.Felt, offset uint64) *felt.Felt {
+	return new(felt.Felt).Add(base, new(felt.Felt).SetUint64(offset))
+}
+
+// normalizeStorageAddress reduces an address modulo the storage address bound.
+//
+// Parameters:
+// - address: the address
+// Returns:
+// - *felt.Felt: the reduced address
+func normalizeStorageAddress(address *felt.Felt) *felt.Felt {
+	v := address.BigInt(new(big.Int))
+	if v.Cmp(storageAddressBound) < 0 {
+		return address
+	}
+	return new(felt.Felt).SetBigInt(v.Mod(v, storageAddressBound))
+}
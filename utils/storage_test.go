@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/test-go/testify/require"
+)
+
+// TestStorageVarAddress tests the storage addresses of plain variables and of
+// map entries, their offsets, and their reduction below 2^251 - 256.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestStorageVarAddress(t *testing.T) {
+	require.Equal(t, "0x206f38f7e4f15e87567361213c28f235cccdaa1d7fd34c9db1dfe9489c6a091", StorageVarAddress("balance").String())
+
+	owner := new(felt.Felt).SetUint64(0x1234)
+	expected := crypto.Pedersen(GetSelectorFromNameFelt("ERC20_balances"), owner)
+	require.Equal(t, expected, StorageVarAddress("ERC20_balances", owner))
+
+	low, high := new(felt.Felt).SetUint64(1), new(felt.Felt)
+	expected = crypto.Pedersen(crypto.Pedersen(GetSelectorFromNameFelt("ERC721_owners"), low), high)
+	require.Equal(t, expected, StorageVarAddress("ERC721_owners", low, high))
+
+	require.Equal(t, new(felt.Felt).Add(expected, new(felt.Felt).SetUint64(1)), StorageAddressOffset(expected, 1))
+
+	bound := new(felt.Felt).SetBigInt(storageAddressBound)
+	require.Equal(t, new(felt.Felt).SetUint64(5), normalizeStorageAddress(new(felt.Felt).Add(bound, new(felt.Felt).SetUint64(5))))
+	below := new(felt.Felt).SetBigInt(new(big.Int).Sub(storageAddressBound, big.NewInt(1)))
+	require.Equal(t, below, normalizeStorageAddress(below))
+}
@@ -0,0 +1,269 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var (
+	ErrUint256Overflow  = errors.New("value overflows u256")
+	ErrUint256Underflow = errors.New("value underflows u256")
+	ErrDivisionByZero   = errors.New("division by zero")
+
+	maxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+)
+
+// Uint256 is the Go counterpart of the Cairo u256, an unsigned 256-bit
+// integer serialized as its low and high 128-bit limbs.
+//
+// A Uint256 is immutable: the arithmetic methods return a new value and fail
+// instead of wrapping around, as Cairo does.
+type Uint256 struct {
+	value big.Int
+}
+
+// NewUint256 creates a Uint256 from a big.Int.
+//
+// Parameters:
+// - v: the value
+// Returns:
+// - *Uint256: the Uint256
+// - error: an error if v is negative or does not fit in 256 bits
+func NewUint256(v *big.Int) (*Uint256, error) {
+	if v.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUint256Underflow, v)
+	}
+	if v.Cmp(maxUint256) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUint256Overflow, v)
+	}
+	u := &Uint256{}
+	u.value.Set(v)
+	return u, nil
+}
+
+// Uint64ToUint256 creates a Uint256 from a uint64.
+//
+// Parameters:
+// - v: the value
+// Returns:
+// - *Uint256: the Uint256
+func Uint64ToUint256(v uint64) *Uint256 {
+	u := &Uint256{}
+	u.value.SetUint64(v)
+	return u
+}
+
+// HexToUint256 creates a Uint256 from a hexadecimal string.
+//
+// Parameters:
+// - hex: the hexadecimal string, with or without 0x prefix
+// Returns:
+// - *Uint256: the Uint256
+// - error: an error if hex is not a valid u256
+func HexToUint256(hex string) (*Uint256, error) {
+	v, ok := new(big.Int).SetString(trimHexPrefix(hex), 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid hexadecimal u256 %q", hex)
+	}
+	return NewUint256(v)
+}
+
+// FeltsToUint256 creates a Uint256 from its low and high limbs.
+//
+// Parameters:
+// - low: the 128 least significant bits
+// - high: the 128 most significant bits
+// Returns:
+// - *Uint256: the Uint256
+// - error: an error if a limb does not fit in 128 bits
+func FeltsToUint256(low, high *felt.Felt) (*Uint256, error) {
+	l := low.BigInt(new(big.Int))
+	h := high.BigInt(new(big.Int))
+	if l.Cmp(maxUint128) > 0 || h.Cmp(maxUint128) > 0 {
+		return nil, fmt.Errorf("%w: limbs (%s, %s) do not fit in 128 bits", ErrUint256Overflow, low, high)
+	}
+	u := &Uint256{}
+	u.value.Or(l, h.Lsh(h, 128))
+	return u, nil
+}
+
+// BigInt returns the value as a new big.Int.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *big.Int: the value
+func (u *Uint256) BigInt() *big.Int {
+	return new(big.Int).Set(&u.value)
+}
+
+// Low returns the 128 least significant bits.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the low limb
+func (u *Uint256) Low() *felt.Felt {
+	return new(felt.Felt).SetBigInt(new(big.Int).And(&u.value, maxUint128))
+}
+
+// High returns the 128 most significant bits.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - *felt.Felt: the high limb
+func (u *Uint256) High() *felt.Felt {
+	return new(felt.Felt).SetBigInt(new(big.Int).Rsh(&u.value, 128))
+}
+
+// Felts returns the low and high limbs, in the order of the Cairo serialization.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - [2]*felt.Felt: the low and high limbs
+func (u *Uint256) Felts() [2]*felt.Felt {
+	return [2]*felt.Felt{u.Low(), u.High()}
+}
+
+// String returns the value in decimal.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - string: the decimal representation
+func (u *Uint256) String() string {
+	return u.value.String()
+}
+
+// Cmp compares two Uint256.
+//
+// Parameters:
+// - other: the Uint256 to compare to
+// Returns:
+// - int: -1 if u < other, 0 if u == other, +1 if u > other
+func (u *Uint256) Cmp(other *Uint256) int {
+	return u.value.Cmp(&other.value)
+}
+
+// IsZero returns whether the value is zero.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - bool: true if the value is zero
+func (u *Uint256) IsZero() bool {
+	return u.value.Sign() == 0
+}
+
+// Add returns u + other.
+//
+// Parameters:
+// - other: the Uint256 to add
+// Returns:
+// - *Uint256: the sum
+// - error: ErrUint256Overflow if the sum does not fit in 256 bits
+func (u *Uint256) Add(other *Uint256) (*Uint256, error) {
+	return NewUint256(new(big.Int).Add(&u.value, &other.value))
+}
+
+// Sub returns u - other.
+//
+// Parameters:
+// - other: the Uint256 to subtract
+// Returns:
+// - *Uint256: the difference
+// - error: ErrUint256Underflow if other is greater than u
+func (u *Uint256) Sub(other *Uint256) (*Uint256, error) {
+	return NewUint256(new(big.Int).Sub(&u.value, &other.value))
+}
+
+// Mul returns u * other.
+//
+// Parameters:
+// - other: the Uint256 to multiply by
+// Returns:
+// - *Uint256: the product
+// - error: ErrUint256Overflow if the product does not fit in 256 bits
+func (u *Uint256) Mul(other *Uint256) (*Uint256, error) {
+	return NewUint256(new(big.Int).Mul(&u.value, &other.value))
+}
+
+// Div returns the quotient and the remainder of u / other.
+//
+// Parameters:
+// - other: the divisor
+// Returns:
+// - *Uint256: the quotient
+// - *Uint256: the remainder
+// - error: ErrDivisionByZero if other is zero
+func (u *Uint256) Div(other *Uint256) (*Uint256, *Uint256, error) {
+	if other.IsZero() {
+		return nil, nil, ErrDivisionByZero
+	}
+	q, r := &Uint256{}, &Uint256{}
+	q.value.QuoRem(&u.value, &other.value, &r.value)
+	return q, r, nil
+}
+
+// MarshalCairo serializes the value as its low and high limbs.
+//
+// Parameters:
+//
+//	none
+//
+// Returns:
+// - []*felt.Felt: the low and high limbs
+// - error: always nil
+func (u *Uint256) MarshalCairo() ([]*felt.Felt, error) {
+	return []*felt.Felt{u.Low(), u.High()}, nil
+}
+
+// UnmarshalCairo deserializes the value from its low and high limbs.
+//
+// Parameters:
+// - data: the felts to deserialize, starting with the low limb
+// Returns:
+// - int: the number of felts consumed
+// - error: an error if data is too short or a limb does not fit in 128 bits
+func (u *Uint256) UnmarshalCairo(data []*felt.Felt) (int, error) {
+	if len(data) < 2 {
+		return 0, fmt.Errorf("u256 needs 2 felts, got %d", len(data))
+	}
+	v, err := FeltsToUint256(data[0], data[1])
+	if err != nil {
+		return 0, err
+	}
+	u.value.Set(&v.value)
+	return 2, nil
+}
+
+// trimHexPrefix removes the 0x prefix of a hexadecimal string.
+//
+// Parameters:
+// - hex: the hexadecimal string
+// Returns:
+// - string: the string without prefix
+func trimHexPrefix(hex string) string {
+	if len(hex) >= 2 && hex[0] == '0' && (hex[1] == 'x' || hex[1] == 'X') {
+		return hex[2:]
+	}
+	return hex
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/test-go/testify/require"
+)
+
+// TestUint256 tests the conversions and the checked arithmetic of Uint256.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestUint256(t *testing.T) {
+	max, err := HexToUint256("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	require.NoError(t, err)
+	require.Equal(t, "0xffffffffffffffffffffffffffffffff", max.Low().String())
+	require.Equal(t, "0xffffffffffffffffffffffffffffffff", max.High().String())
+
+	limbs := max.Felts()
+	back, err := FeltsToUint256(limbs[0], limbs[1])
+	require.NoError(t, err)
+	require.Equal(t, 0, max.Cmp(back))
+
+	one := Uint64ToUint256(1)
+	_, err = max.Add(one)
+	require.True(t, errors.Is(err, ErrUint256Overflow))
+	_, err = one.Sub(Uint64ToUint256(2))
+	require.True(t, errors.Is(err, ErrUint256Underflow))
+	_, err = NewUint256(new(big.Int).Lsh(big.NewInt(1), 256))
+	require.True(t, errors.Is(err, ErrUint256Overflow))
+
+	sum, err := Uint64ToUint256(1 << 63).Add(Uint64ToUint256(1 << 63))
+	require.NoError(t, err)
+	require.Equal(t, "18446744073709551616", sum.String())
+	q, r, err := sum.Div(Uint64ToUint256(3))
+	require.NoError(t, err)
+	require.Equal(t, "6148914691236517205", q.String())
+	require.Equal(t, "1", r.String())
+	_, _, err = sum.Div(Uint64ToUint256(0))
+	require.Equal(t, ErrDivisionByZero, err)
+}
+
+// TestSignedToFelt tests the Cairo encoding of signed integers.
+//
+// Parameters:
+// - t: the testing.T instance for running the test
+// Returns:
+//
+//	none
+func TestSignedToFelt(t *testing.T) {
+	minusOne, err := SignedToFelt(big.NewInt(-1), 8)
+	require.NoError(t, err)
+	require.Equal(t, "0x800000000000011000000000000000000000000000000000000000000000000", minusOne.String())
+
+	v, err := FeltToSigned(minusOne, 8)
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), v.Int64())
+
+	_, err = SignedToFelt(big.NewInt(128), 8)
+	require.True(t, errors.Is(err, ErrSignedOutOfRange))
+	_, err = FeltToSigned(Uint64ToFelt(128), 8)
+	require.True(t, errors.Is(err, ErrSignedOutOfRange))
+
+	u, err := TwosComplement(big.NewInt(-2), 8)
+	require.NoError(t, err)
+	require.Equal(t, int64(254), u.Int64())
+	s, err := FromTwosComplement(u, 8)
+	require.NoError(t, err)
+	require.Equal(t, int64(-2), s.Int64())
+}